@@ -0,0 +1,116 @@
+package common
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/libp2p/go-libp2p/core/peer"
+
+	"github.com/onflow/flow-go/admin"
+	"github.com/onflow/flow-go/admin/commands"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/module"
+	"github.com/onflow/flow-go/network/p2p"
+)
+
+var _ commands.AdminCommand = (*DisconnectPeerCommand)(nil)
+
+type disconnectPeerRequestData struct {
+	nodeID   flow.Identifier
+	peerID   peer.ID
+	duration time.Duration
+}
+
+// DisconnectPeerCommand is an admin command which force-disconnects a peer for a given duration.
+// The peer is identified either by its Flow node ID ("flow_id") or by its libp2p peer ID ("peer_id"), and is
+// disallow-listed for the given "duration" (e.g. "10m"). While disallow-listed, the peer is considered ejected,
+// i.e., existing connections are closed and new connections are rejected. Once the duration elapsed, connections
+// to the peer can be re-established.
+type DisconnectPeerCommand struct {
+	idProvider module.IdentityProvider
+	lister     p2p.TimedDisallowLister
+}
+
+func NewDisconnectPeerCommand(idProvider module.IdentityProvider, lister p2p.TimedDisallowLister) *DisconnectPeerCommand {
+	return &DisconnectPeerCommand{
+		idProvider: idProvider,
+		lister:     lister,
+	}
+}
+
+func (d *DisconnectPeerCommand) Handler(_ context.Context, req *admin.CommandRequest) (interface{}, error) {
+	if d.lister == nil {
+		return nil, fmt.Errorf("timed disallow lister is not available on this node")
+	}
+	data := req.ValidatorData.(*disconnectPeerRequestData)
+
+	nodeID := data.nodeID
+	if data.peerID != "" {
+		identity, ok := d.idProvider.ByPeerID(data.peerID)
+		if !ok {
+			return nil, fmt.Errorf("no identity found for peer ID: %s", data.peerID)
+		}
+		nodeID = identity.NodeID
+	}
+
+	err := d.lister.DisallowListNodeFor(nodeID, data.duration)
+	if err != nil {
+		return nil, fmt.Errorf("failed to disallow-list node %v: %w", nodeID, err)
+	}
+
+	return map[string]interface{}{
+		"node_id":            nodeID.String(),
+		"disconnected_until": time.Now().Add(data.duration).UTC().String(),
+	}, nil
+}
+
+// Validator validates the request.
+// Returns admin.InvalidAdminReqError for invalid/malformed requests.
+func (d *DisconnectPeerCommand) Validator(req *admin.CommandRequest) error {
+	input, ok := req.Data.(map[string]interface{})
+	if !ok {
+		return admin.NewInvalidAdminReqFormatError("expected map[string]any")
+	}
+
+	data := &disconnectPeerRequestData{}
+
+	rawDuration, ok := input["duration"]
+	if !ok {
+		return admin.NewInvalidAdminReqErrorf("the \"duration\" field is required")
+	}
+	durationStr, ok := rawDuration.(string)
+	if !ok {
+		return admin.NewInvalidAdminReqParameterError("duration", "must be a duration string, e.g. \"10m\"", rawDuration)
+	}
+	duration, err := time.ParseDuration(durationStr)
+	if err != nil || duration <= 0 {
+		return admin.NewInvalidAdminReqParameterError("duration", "must be a positive duration string, e.g. \"10m\"", rawDuration)
+	}
+	data.duration = duration
+
+	if flowID, ok := input["flow_id"]; ok {
+		if flowID, ok := flowID.(string); ok {
+			if len(flowID) == 2*flow.IdentifierLen {
+				if b, err := hex.DecodeString(flowID); err == nil {
+					data.nodeID = flow.HashToID(b)
+					req.ValidatorData = data
+					return nil
+				}
+			}
+		}
+		return admin.NewInvalidAdminReqParameterError("flow_id", "must be 64-char hex string", flowID)
+	}
+
+	pid, ok, err := parsePeerIdField(input)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return admin.NewInvalidAdminReqErrorf("either \"flow_id\" or \"peer_id\" field is required")
+	}
+	data.peerID = pid
+	req.ValidatorData = data
+	return nil
+}
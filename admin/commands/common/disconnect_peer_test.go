@@ -0,0 +1,70 @@
+package common
+
+import (
+	"context"
+	"crypto/rand"
+	"testing"
+	"time"
+
+	libp2pcrypto "github.com/libp2p/go-libp2p/core/crypto"
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/admin"
+	"github.com/onflow/flow-go/model/flow"
+	mockmodule "github.com/onflow/flow-go/module/mock"
+	mockp2p "github.com/onflow/flow-go/network/p2p/mock"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+// TestDisconnectPeer_Validator checks that malformed disconnect-peer requests are rejected.
+func TestDisconnectPeer_Validator(t *testing.T) {
+	command := NewDisconnectPeerCommand(mockmodule.NewIdentityProvider(t), mockp2p.NewTimedDisallowLister(t))
+
+	invalid := []interface{}{
+		"not a map",
+		map[string]interface{}{"flow_id": unittest.IdentifierFixture().String()},
+		map[string]interface{}{"flow_id": unittest.IdentifierFixture().String(), "duration": "ten minutes"},
+		map[string]interface{}{"flow_id": unittest.IdentifierFixture().String(), "duration": "-1m"},
+		map[string]interface{}{"flow_id": "abc", "duration": "10m"},
+		map[string]interface{}{"peer_id": "not a peer id", "duration": "10m"},
+		map[string]interface{}{"duration": "10m"},
+	}
+	for _, data := range invalid {
+		err := command.Validator(&admin.CommandRequest{Data: data})
+		require.True(t, admin.IsInvalidAdminParameterError(err), "expected invalid request for %v, got: %v", data, err)
+	}
+}
+
+// TestDisconnectPeer_Handler checks that the peer identified by either its flow id or its peer id is
+// disallow-listed for the requested duration.
+func TestDisconnectPeer_Handler(t *testing.T) {
+	nodeID := unittest.IdentifierFixture()
+	pid := peerIDFixture(t)
+
+	idProvider := mockmodule.NewIdentityProvider(t)
+	idProvider.On("ByPeerID", pid).Return(&flow.Identity{NodeID: nodeID}, true).Once()
+	lister := mockp2p.NewTimedDisallowLister(t)
+	lister.On("DisallowListNodeFor", nodeID, 10*time.Minute).Return(nil).Twice()
+
+	command := NewDisconnectPeerCommand(idProvider, lister)
+
+	for _, data := range []map[string]interface{}{
+		{"flow_id": nodeID.String(), "duration": "10m"},
+		{"peer_id": pid.String(), "duration": "10m"},
+	} {
+		req := &admin.CommandRequest{Data: data}
+		require.NoError(t, command.Validator(req))
+		res, err := command.Handler(context.Background(), req)
+		require.NoError(t, err)
+		require.Equal(t, nodeID.String(), res.(map[string]interface{})["node_id"])
+	}
+}
+
+func peerIDFixture(t *testing.T) peer.ID {
+	key, _, err := libp2pcrypto.GenerateEd25519Key(rand.Reader)
+	require.NoError(t, err)
+	pid, err := peer.IDFromPrivateKey(key)
+	require.NoError(t, err)
+	return pid
+}
@@ -0,0 +1,112 @@
+package common
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/libp2p/go-libp2p/core/peer"
+
+	"github.com/onflow/flow-go/admin"
+	"github.com/onflow/flow-go/admin/commands"
+	"github.com/onflow/flow-go/module"
+	"github.com/onflow/flow-go/network/channels"
+	"github.com/onflow/flow-go/network/p2p"
+)
+
+var _ commands.AdminCommand = (*GetMeshPeersCommand)(nil)
+
+// GetMeshPeersCommand is an admin command which returns the current GossipSub mesh peers of the node per topic,
+// together with their Flow identities and roles.
+// By default, the mesh peers of all subscribed topics are returned. The optional "topic" field restricts the
+// result to a single topic.
+type GetMeshPeersCommand struct {
+	node       p2p.LibP2PNode
+	idProvider module.IdentityProvider
+}
+
+func NewGetMeshPeersCommand(node p2p.LibP2PNode, idProvider module.IdentityProvider) *GetMeshPeersCommand {
+	return &GetMeshPeersCommand{
+		node:       node,
+		idProvider: idProvider,
+	}
+}
+
+func (g *GetMeshPeersCommand) Handler(_ context.Context, req *admin.CommandRequest) (interface{}, error) {
+	if g.node == nil {
+		return nil, fmt.Errorf("libp2p node is not available on this node")
+	}
+
+	var topics []channels.Topic
+	if topic, ok := req.ValidatorData.(channels.Topic); ok {
+		topics = []channels.Topic{topic}
+	} else {
+		topics = g.node.GetSubscribedTopics()
+	}
+
+	res := make(map[string]interface{}, len(topics))
+	for _, topic := range topics {
+		meshPeers := g.node.GetLocalMeshPeers(topic)
+		peers := make([]interface{}, 0, len(meshPeers))
+		for _, pid := range meshPeers {
+			peers = append(peers, peerIdentityInfo(g.idProvider, pid))
+		}
+		res[topic.String()] = peers
+	}
+	return res, nil
+}
+
+// Validator validates the request.
+// Returns admin.InvalidAdminReqError for invalid/malformed requests.
+func (g *GetMeshPeersCommand) Validator(req *admin.CommandRequest) error {
+	if req.Data == nil {
+		return nil
+	}
+	input, ok := req.Data.(map[string]interface{})
+	if !ok {
+		return admin.NewInvalidAdminReqFormatError("expected map[string]any")
+	}
+	topic, ok := input["topic"]
+	if !ok {
+		return nil
+	}
+	topicStr, ok := topic.(string)
+	if !ok || len(topicStr) == 0 {
+		return admin.NewInvalidAdminReqParameterError("topic", "must be a non-empty string", topic)
+	}
+	req.ValidatorData = channels.Topic(topicStr)
+	return nil
+}
+
+// peerIdentityInfo returns the peer ID of the given peer, together with its Flow node ID and role if the peer
+// is known to the identity provider.
+func peerIdentityInfo(idProvider module.IdentityProvider, pid peer.ID) map[string]interface{} {
+	info := map[string]interface{}{
+		"peer_id": pid.String(),
+	}
+	id, ok := idProvider.ByPeerID(pid)
+	if !ok {
+		info["known"] = false
+		return info
+	}
+	info["known"] = true
+	info["node_id"] = id.NodeID.String()
+	info["role"] = id.Role.String()
+	info["ejected"] = id.Ejected
+	return info
+}
+
+// parsePeerIdField parses the optional "peer_id" field of the given admin request input. The second return value
+// is false if the field is not present.
+// Returns admin.InvalidAdminReqError if the field is present but is not a valid peer ID.
+func parsePeerIdField(input map[string]interface{}) (peer.ID, bool, error) {
+	raw, ok := input["peer_id"]
+	if !ok {
+		return "", false, nil
+	}
+	if str, ok := raw.(string); ok {
+		if pid, err := peer.Decode(str); err == nil {
+			return pid, true, nil
+		}
+	}
+	return "", false, admin.NewInvalidAdminReqParameterError("peer_id", "must be valid peer id string", raw)
+}
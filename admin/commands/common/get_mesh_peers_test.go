@@ -0,0 +1,103 @@
+package common
+
+import (
+	"context"
+	"testing"
+
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/admin"
+	"github.com/onflow/flow-go/model/flow"
+	mockmodule "github.com/onflow/flow-go/module/mock"
+	"github.com/onflow/flow-go/network/channels"
+	mockp2p "github.com/onflow/flow-go/network/p2p/mock"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+// TestGetMeshPeers_Validator checks that malformed get-mesh-peers requests are rejected, and that the optional
+// topic is passed on to the handler.
+func TestGetMeshPeers_Validator(t *testing.T) {
+	command := NewGetMeshPeersCommand(mockp2p.NewLibP2PNode(t), mockmodule.NewIdentityProvider(t))
+
+	invalid := []interface{}{
+		"not a map",
+		map[string]interface{}{"topic": ""},
+		map[string]interface{}{"topic": 1},
+	}
+	for _, data := range invalid {
+		err := command.Validator(&admin.CommandRequest{Data: data})
+		require.True(t, admin.IsInvalidAdminParameterError(err), "expected invalid request for %v, got: %v", data, err)
+	}
+
+	req := &admin.CommandRequest{}
+	require.NoError(t, command.Validator(req))
+	require.Nil(t, req.ValidatorData)
+
+	req = &admin.CommandRequest{Data: map[string]interface{}{}}
+	require.NoError(t, command.Validator(req))
+	require.Nil(t, req.ValidatorData)
+
+	req = &admin.CommandRequest{Data: map[string]interface{}{"topic": "consensus-committee"}}
+	require.NoError(t, command.Validator(req))
+	require.Equal(t, channels.Topic("consensus-committee"), req.ValidatorData)
+}
+
+// TestGetMeshPeers_Handler checks that the mesh peers of all subscribed topics, or only of the requested topic, are
+// returned together with their identities.
+func TestGetMeshPeers_Handler(t *testing.T) {
+	known := peerIDFixture(t)
+	unknown := peerIDFixture(t)
+	identity := &flow.Identity{NodeID: unittest.IdentifierFixture(), Role: flow.RoleConsensus}
+	topicA := channels.Topic("topic-a")
+	topicB := channels.Topic("topic-b")
+
+	idProvider := mockmodule.NewIdentityProvider(t)
+	idProvider.On("ByPeerID", known).Return(identity, true)
+	idProvider.On("ByPeerID", unknown).Return(nil, false)
+	node := mockp2p.NewLibP2PNode(t)
+	node.On("GetSubscribedTopics").Return([]channels.Topic{topicA, topicB}).Once()
+	node.On("GetLocalMeshPeers", topicA).Return([]peer.ID{known, unknown})
+	node.On("GetLocalMeshPeers", topicB).Return([]peer.ID{})
+
+	command := NewGetMeshPeersCommand(node, idProvider)
+
+	t.Run("all topics", func(t *testing.T) {
+		req := &admin.CommandRequest{}
+		require.NoError(t, command.Validator(req))
+		res, err := command.Handler(context.Background(), req)
+		require.NoError(t, err)
+
+		meshPeers := res.(map[string]interface{})
+		require.Len(t, meshPeers, 2)
+		require.Empty(t, meshPeers[topicB.String()])
+		peers := meshPeers[topicA.String()].([]interface{})
+		require.Len(t, peers, 2)
+		require.Equal(t, map[string]interface{}{
+			"peer_id": known.String(),
+			"known":   true,
+			"node_id": identity.NodeID.String(),
+			"role":    flow.RoleConsensus.String(),
+			"ejected": false,
+		}, peers[0])
+		require.Equal(t, map[string]interface{}{
+			"peer_id": unknown.String(),
+			"known":   false,
+		}, peers[1])
+	})
+
+	t.Run("single topic", func(t *testing.T) {
+		req := &admin.CommandRequest{Data: map[string]interface{}{"topic": topicB.String()}}
+		require.NoError(t, command.Validator(req))
+		res, err := command.Handler(context.Background(), req)
+		require.NoError(t, err)
+		require.Equal(t, map[string]interface{}{topicB.String(): []interface{}{}}, res)
+	})
+}
+
+// TestGetMeshPeers_NoNode checks that the command fails if the node has no libp2p node.
+func TestGetMeshPeers_NoNode(t *testing.T) {
+	command := NewGetMeshPeersCommand(nil, mockmodule.NewIdentityProvider(t))
+	_, err := command.Handler(context.Background(), &admin.CommandRequest{})
+	require.Error(t, err)
+}
@@ -0,0 +1,49 @@
+package common
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/onflow/flow-go/admin"
+	"github.com/onflow/flow-go/admin/commands"
+	"github.com/onflow/flow-go/network/alsp"
+)
+
+var _ commands.AdminCommand = (*GetMisbehaviorPenaltiesCommand)(nil)
+
+// GetMisbehaviorPenaltiesCommand is an admin command which lists the current penalties of the nodes reported
+// for misbehavior by the engines, as well as whether they are currently disallow-listed.
+type GetMisbehaviorPenaltiesCommand struct {
+	manager *alsp.MisbehaviorReportManager
+}
+
+func NewGetMisbehaviorPenaltiesCommand(manager *alsp.MisbehaviorReportManager) *GetMisbehaviorPenaltiesCommand {
+	return &GetMisbehaviorPenaltiesCommand{
+		manager: manager,
+	}
+}
+
+func (g *GetMisbehaviorPenaltiesCommand) Handler(_ context.Context, _ *admin.CommandRequest) (interface{}, error) {
+	if g.manager == nil {
+		return nil, fmt.Errorf("misbehavior report manager is not available on this node")
+	}
+
+	records := g.manager.Penalties()
+	res := make([]interface{}, 0, len(records))
+	for _, record := range records {
+		res = append(res, map[string]interface{}{
+			"node_id":         record.OriginId.String(),
+			"penalty":         record.Penalty,
+			"decay":           record.Decay,
+			"cutoff_counter":  record.CutoffCounter,
+			"disallow_listed": record.DisallowListed,
+		})
+	}
+	return res, nil
+}
+
+// Validator validates the request.
+// The command takes no input, hence any request is valid.
+func (g *GetMisbehaviorPenaltiesCommand) Validator(_ *admin.CommandRequest) error {
+	return nil
+}
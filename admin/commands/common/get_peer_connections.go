@@ -0,0 +1,102 @@
+package common
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/libp2p/go-libp2p/core/peer"
+
+	"github.com/onflow/flow-go/admin"
+	"github.com/onflow/flow-go/admin/commands"
+	"github.com/onflow/flow-go/module"
+	"github.com/onflow/flow-go/network/p2p"
+	"github.com/onflow/flow-go/network/p2p/unicast/protocols"
+)
+
+var _ commands.AdminCommand = (*GetPeerConnectionsCommand)(nil)
+
+// GetPeerConnectionsCommand is an admin command which returns the currently open connections of the node per peer,
+// including the direction of each connection (inbound or outbound), and its number of open streams, broken down
+// by protocol. The number of Flow unicast streams is reported separately.
+// The optional "peer_id" field restricts the result to a single peer.
+type GetPeerConnectionsCommand struct {
+	node       p2p.LibP2PNode
+	idProvider module.IdentityProvider
+}
+
+func NewGetPeerConnectionsCommand(node p2p.LibP2PNode, idProvider module.IdentityProvider) *GetPeerConnectionsCommand {
+	return &GetPeerConnectionsCommand{
+		node:       node,
+		idProvider: idProvider,
+	}
+}
+
+func (g *GetPeerConnectionsCommand) Handler(_ context.Context, req *admin.CommandRequest) (interface{}, error) {
+	if g.node == nil {
+		return nil, fmt.Errorf("libp2p node is not available on this node")
+	}
+
+	network := g.node.Host().Network()
+	var peers []peer.ID
+	if pid, ok := req.ValidatorData.(peer.ID); ok {
+		peers = []peer.ID{pid}
+	} else {
+		peers = network.Peers()
+		sort.Slice(peers, func(i, j int) bool {
+			return peers[i] < peers[j]
+		})
+	}
+
+	res := make([]interface{}, 0, len(peers))
+	for _, pid := range peers {
+		info := peerIdentityInfo(g.idProvider, pid)
+		conns := network.ConnsToPeer(pid)
+		connections := make([]interface{}, 0, len(conns))
+		unicastStreams := 0
+		for _, conn := range conns {
+			streams := conn.GetStreams()
+			streamsByProtocol := make(map[string]int)
+			for _, s := range streams {
+				proto := string(s.Protocol())
+				streamsByProtocol[proto]++
+				if strings.HasPrefix(proto, protocols.FlowLibP2POneToOneProtocolIDPrefix) {
+					unicastStreams++
+				}
+			}
+			stat := conn.Stat()
+			connections = append(connections, map[string]interface{}{
+				"direction":           stat.Direction.String(),
+				"opened":              stat.Opened.UTC().String(),
+				"remote_address":      conn.RemoteMultiaddr().String(),
+				"streams":             len(streams),
+				"streams_by_protocol": streamsByProtocol,
+			})
+		}
+		info["connections"] = connections
+		info["unicast_streams"] = unicastStreams
+		res = append(res, info)
+	}
+	return res, nil
+}
+
+// Validator validates the request.
+// Returns admin.InvalidAdminReqError for invalid/malformed requests.
+func (g *GetPeerConnectionsCommand) Validator(req *admin.CommandRequest) error {
+	if req.Data == nil {
+		return nil
+	}
+	input, ok := req.Data.(map[string]interface{})
+	if !ok {
+		return admin.NewInvalidAdminReqFormatError("expected map[string]any")
+	}
+	pid, ok, err := parsePeerIdField(input)
+	if err != nil {
+		return err
+	}
+	if ok {
+		req.ValidatorData = pid
+	}
+	return nil
+}
@@ -0,0 +1,136 @@
+package common
+
+import (
+	"context"
+	"testing"
+
+	"github.com/libp2p/go-libp2p/core/host"
+	"github.com/libp2p/go-libp2p/core/network"
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/libp2p/go-libp2p/core/protocol"
+	mocknet "github.com/libp2p/go-libp2p/p2p/net/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/admin"
+	"github.com/onflow/flow-go/model/flow"
+	mockmodule "github.com/onflow/flow-go/module/mock"
+	mockp2p "github.com/onflow/flow-go/network/p2p/mock"
+	"github.com/onflow/flow-go/network/p2p/unicast/protocols"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+// TestGetPeerConnections_Validator checks that malformed get-peer-connections requests are rejected, and that the
+// optional peer id is passed on to the handler.
+func TestGetPeerConnections_Validator(t *testing.T) {
+	command := NewGetPeerConnectionsCommand(mockp2p.NewLibP2PNode(t), mockmodule.NewIdentityProvider(t))
+
+	invalid := []interface{}{
+		"not a map",
+		map[string]interface{}{"peer_id": "not a peer id"},
+		map[string]interface{}{"peer_id": 1},
+	}
+	for _, data := range invalid {
+		err := command.Validator(&admin.CommandRequest{Data: data})
+		require.True(t, admin.IsInvalidAdminParameterError(err), "expected invalid request for %v, got: %v", data, err)
+	}
+
+	req := &admin.CommandRequest{}
+	require.NoError(t, command.Validator(req))
+	require.Nil(t, req.ValidatorData)
+
+	pid := peerIDFixture(t)
+	req = &admin.CommandRequest{Data: map[string]interface{}{"peer_id": pid.String()}}
+	require.NoError(t, command.Validator(req))
+	require.Equal(t, pid, req.ValidatorData)
+}
+
+// TestGetPeerConnections_Handler checks that the open connections of the connected peers, or only of the requested
+// peer, are returned together with their open streams, and that Flow unicast streams are counted separately.
+func TestGetPeerConnections_Handler(t *testing.T) {
+	mn, err := mocknet.FullMeshLinked(3)
+	require.NoError(t, err)
+	defer mn.Close()
+	hosts := mn.Hosts()
+	local, remote, idle := hosts[0], hosts[1], hosts[2]
+	// only the local host dials, so that there is a single outbound connection to each peer
+	for _, h := range []host.Host{remote, idle} {
+		require.NoError(t, local.Connect(context.Background(), peer.AddrInfo{ID: h.ID()}))
+	}
+
+	unicastProtocol := protocols.FlowProtocolID(unittest.IdentifierFixture())
+	otherProtocol := protocols.PingProtocolId(unittest.IdentifierFixture())
+	remote.SetStreamHandler(unicastProtocol, func(network.Stream) {})
+	remote.SetStreamHandler(otherProtocol, func(network.Stream) {})
+	for _, pid := range []protocol.ID{unicastProtocol, unicastProtocol, otherProtocol} {
+		s, err := local.NewStream(context.Background(), remote.ID(), pid)
+		require.NoError(t, err)
+		defer s.Close()
+	}
+
+	identity := &flow.Identity{NodeID: unittest.IdentifierFixture(), Role: flow.RoleExecution}
+	idProvider := mockmodule.NewIdentityProvider(t)
+	idProvider.On("ByPeerID", remote.ID()).Return(identity, true)
+	idProvider.On("ByPeerID", idle.ID()).Return(nil, false)
+	node := mockp2p.NewLibP2PNode(t)
+	node.On("Host").Return(local)
+
+	command := NewGetPeerConnectionsCommand(node, idProvider)
+
+	checkRemote := func(info map[string]interface{}) {
+		require.Equal(t, true, info["known"])
+		require.Equal(t, identity.NodeID.String(), info["node_id"])
+		require.Equal(t, 2, info["unicast_streams"])
+		connections := info["connections"].([]interface{})
+		require.Len(t, connections, 1)
+		conn := connections[0].(map[string]interface{})
+		require.Equal(t, network.DirOutbound.String(), conn["direction"])
+		require.Equal(t, 3, conn["streams"])
+		require.Equal(t, map[string]int{
+			string(unicastProtocol): 2,
+			string(otherProtocol):   1,
+		}, conn["streams_by_protocol"])
+	}
+
+	t.Run("all peers", func(t *testing.T) {
+		req := &admin.CommandRequest{}
+		require.NoError(t, command.Validator(req))
+		res, err := command.Handler(context.Background(), req)
+		require.NoError(t, err)
+
+		peers := res.([]interface{})
+		require.Len(t, peers, 2)
+		for _, p := range peers {
+			info := p.(map[string]interface{})
+			switch info["peer_id"] {
+			case remote.ID().String():
+				checkRemote(info)
+			case idle.ID().String():
+				require.Equal(t, false, info["known"])
+				require.Equal(t, 0, info["unicast_streams"])
+				connections := info["connections"].([]interface{})
+				require.Len(t, connections, 1)
+				require.Equal(t, 0, connections[0].(map[string]interface{})["streams"])
+			default:
+				t.Fatalf("unexpected peer %v", info["peer_id"])
+			}
+		}
+	})
+
+	t.Run("single peer", func(t *testing.T) {
+		req := &admin.CommandRequest{Data: map[string]interface{}{"peer_id": remote.ID().String()}}
+		require.NoError(t, command.Validator(req))
+		res, err := command.Handler(context.Background(), req)
+		require.NoError(t, err)
+
+		peers := res.([]interface{})
+		require.Len(t, peers, 1)
+		checkRemote(peers[0].(map[string]interface{}))
+	})
+}
+
+// TestGetPeerConnections_NoNode checks that the command fails if the node has no libp2p node.
+func TestGetPeerConnections_NoNode(t *testing.T) {
+	command := NewGetPeerConnectionsCommand(nil, mockmodule.NewIdentityProvider(t))
+	_, err := command.Handler(context.Background(), &admin.CommandRequest{})
+	require.Error(t, err)
+}
@@ -0,0 +1,109 @@
+package common
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/libp2p/go-libp2p/core/peer"
+
+	"github.com/onflow/flow-go/admin"
+	"github.com/onflow/flow-go/admin/commands"
+	"github.com/onflow/flow-go/module"
+	"github.com/onflow/flow-go/network/p2p"
+)
+
+var _ commands.AdminCommand = (*GetPeerScoresCommand)(nil)
+
+// GetPeerScoresCommand is an admin command which returns the full GossipSub peer score breakdown of the
+// currently connected peers, i.e., the overall score, the per topic scores, the app specific score, the IP
+// colocation factor and the behaviour penalty.
+// The scores are the latest snapshot reported to the peer score tracer, hence the command requires the
+// peer score tracer to be enabled. The optional "peer_id" field restricts the result to a single peer.
+type GetPeerScoresCommand struct {
+	node       p2p.LibP2PNode
+	idProvider module.IdentityProvider
+}
+
+func NewGetPeerScoresCommand(node p2p.LibP2PNode, idProvider module.IdentityProvider) *GetPeerScoresCommand {
+	return &GetPeerScoresCommand{
+		node:       node,
+		idProvider: idProvider,
+	}
+}
+
+func (g *GetPeerScoresCommand) Handler(_ context.Context, req *admin.CommandRequest) (interface{}, error) {
+	if g.node == nil {
+		return nil, fmt.Errorf("libp2p node is not available on this node")
+	}
+	exposer, ok := g.node.PeerScoreExposer()
+	if !ok {
+		return nil, fmt.Errorf("peer score tracer is not enabled on this node")
+	}
+
+	var peers []peer.ID
+	if pid, ok := req.ValidatorData.(peer.ID); ok {
+		peers = []peer.ID{pid}
+	} else {
+		peers = g.node.Host().Network().Peers()
+		sort.Slice(peers, func(i, j int) bool {
+			return peers[i] < peers[j]
+		})
+	}
+
+	res := make([]interface{}, 0, len(peers))
+	for _, pid := range peers {
+		info := peerIdentityInfo(g.idProvider, pid)
+		score, ok := exposer.GetScore(pid)
+		if !ok {
+			// no score snapshot yet for this peer, e.g., the peer has just connected.
+			info["score"] = nil
+			res = append(res, info)
+			continue
+		}
+		info["score"] = score
+		if appScore, ok := exposer.GetAppScore(pid); ok {
+			info["app_specific_score"] = appScore
+		}
+		if ipColocationFactor, ok := exposer.GetIPColocationFactor(pid); ok {
+			info["ip_colocation_factor"] = ipColocationFactor
+		}
+		if behaviourPenalty, ok := exposer.GetBehaviourPenalty(pid); ok {
+			info["behaviour_penalty"] = behaviourPenalty
+		}
+		if topicScores, ok := exposer.GetTopicScores(pid); ok {
+			topics := make(map[string]interface{}, len(topicScores))
+			for topic, snapshot := range topicScores {
+				topics[topic] = map[string]interface{}{
+					"time_in_mesh":               snapshot.TimeInMesh.String(),
+					"first_message_deliveries":   snapshot.FirstMessageDeliveries,
+					"mesh_message_deliveries":    snapshot.MeshMessageDeliveries,
+					"invalid_message_deliveries": snapshot.InvalidMessageDeliveries,
+				}
+			}
+			info["topic_scores"] = topics
+		}
+		res = append(res, info)
+	}
+	return res, nil
+}
+
+// Validator validates the request.
+// Returns admin.InvalidAdminReqError for invalid/malformed requests.
+func (g *GetPeerScoresCommand) Validator(req *admin.CommandRequest) error {
+	if req.Data == nil {
+		return nil
+	}
+	input, ok := req.Data.(map[string]interface{})
+	if !ok {
+		return admin.NewInvalidAdminReqFormatError("expected map[string]any")
+	}
+	pid, ok, err := parsePeerIdField(input)
+	if err != nil {
+		return err
+	}
+	if ok {
+		req.ValidatorData = pid
+	}
+	return nil
+}
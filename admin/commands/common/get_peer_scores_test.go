@@ -0,0 +1,134 @@
+package common
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	mocknet "github.com/libp2p/go-libp2p/p2p/net/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/admin"
+	mockmodule "github.com/onflow/flow-go/module/mock"
+	"github.com/onflow/flow-go/network/p2p"
+	mockp2p "github.com/onflow/flow-go/network/p2p/mock"
+)
+
+// TestGetPeerScores_Validator checks that malformed get-peer-scores requests are rejected, and that the optional
+// peer id is passed on to the handler.
+func TestGetPeerScores_Validator(t *testing.T) {
+	command := NewGetPeerScoresCommand(mockp2p.NewLibP2PNode(t), mockmodule.NewIdentityProvider(t))
+
+	invalid := []interface{}{
+		"not a map",
+		map[string]interface{}{"peer_id": "not a peer id"},
+		map[string]interface{}{"peer_id": 1},
+	}
+	for _, data := range invalid {
+		err := command.Validator(&admin.CommandRequest{Data: data})
+		require.True(t, admin.IsInvalidAdminParameterError(err), "expected invalid request for %v, got: %v", data, err)
+	}
+
+	req := &admin.CommandRequest{}
+	require.NoError(t, command.Validator(req))
+	require.Nil(t, req.ValidatorData)
+
+	pid := peerIDFixture(t)
+	req = &admin.CommandRequest{Data: map[string]interface{}{"peer_id": pid.String()}}
+	require.NoError(t, command.Validator(req))
+	require.Equal(t, pid, req.ValidatorData)
+}
+
+// TestGetPeerScores_Handler checks that the score breakdown of the connected peers, or only of the requested peer,
+// is returned, and that peers without a score snapshot are reported without a score.
+func TestGetPeerScores_Handler(t *testing.T) {
+	mn, err := mocknet.FullMeshConnected(3)
+	require.NoError(t, err)
+	defer mn.Close()
+	hosts := mn.Hosts()
+	local, scored, unscored := hosts[0], hosts[1].ID(), hosts[2].ID()
+
+	idProvider := mockmodule.NewIdentityProvider(t)
+	idProvider.On("ByPeerID", scored).Return(nil, false)
+	idProvider.On("ByPeerID", unscored).Return(nil, false)
+
+	exposer := mockp2p.NewPeerScoreExposer(t)
+	exposer.On("GetScore", scored).Return(1.5, true)
+	exposer.On("GetScore", unscored).Return(float64(0), false)
+	exposer.On("GetAppScore", scored).Return(2.5, true)
+	exposer.On("GetIPColocationFactor", scored).Return(float64(0), false)
+	exposer.On("GetBehaviourPenalty", scored).Return(-1.0, true)
+	exposer.On("GetTopicScores", scored).Return(map[string]p2p.TopicScoreSnapshot{
+		"topic": {
+			TimeInMesh:             time.Minute,
+			FirstMessageDeliveries: 3,
+		},
+	}, true)
+
+	node := mockp2p.NewLibP2PNode(t)
+	node.On("PeerScoreExposer").Return(exposer, true)
+	node.On("Host").Return(local)
+
+	command := NewGetPeerScoresCommand(node, idProvider)
+
+	expectedScored := map[string]interface{}{
+		"peer_id":            scored.String(),
+		"known":              false,
+		"score":              1.5,
+		"app_specific_score": 2.5,
+		"behaviour_penalty":  -1.0,
+		"topic_scores": map[string]interface{}{
+			"topic": map[string]interface{}{
+				"time_in_mesh":               time.Minute.String(),
+				"first_message_deliveries":   float64(3),
+				"mesh_message_deliveries":    float64(0),
+				"invalid_message_deliveries": float64(0),
+			},
+		},
+	}
+
+	t.Run("all peers", func(t *testing.T) {
+		req := &admin.CommandRequest{}
+		require.NoError(t, command.Validator(req))
+		res, err := command.Handler(context.Background(), req)
+		require.NoError(t, err)
+
+		scores := res.([]interface{})
+		require.Len(t, scores, 2)
+		for _, score := range scores {
+			info := score.(map[string]interface{})
+			switch info["peer_id"] {
+			case scored.String():
+				require.Equal(t, expectedScored, info)
+			case unscored.String():
+				require.Contains(t, info, "score")
+				require.Nil(t, info["score"])
+				require.NotContains(t, info, "app_specific_score")
+			default:
+				t.Fatalf("unexpected peer %v", info["peer_id"])
+			}
+		}
+	})
+
+	t.Run("single peer", func(t *testing.T) {
+		req := &admin.CommandRequest{Data: map[string]interface{}{"peer_id": scored.String()}}
+		require.NoError(t, command.Validator(req))
+		res, err := command.Handler(context.Background(), req)
+		require.NoError(t, err)
+		require.Equal(t, []interface{}{expectedScored}, res)
+	})
+}
+
+// TestGetPeerScores_NoTracer checks that the command fails if the peer score tracer is not enabled.
+func TestGetPeerScores_NoTracer(t *testing.T) {
+	node := mockp2p.NewLibP2PNode(t)
+	node.On("PeerScoreExposer").Return(nil, false)
+
+	command := NewGetPeerScoresCommand(node, mockmodule.NewIdentityProvider(t))
+	_, err := command.Handler(context.Background(), &admin.CommandRequest{})
+	require.Error(t, err)
+
+	command = NewGetPeerScoresCommand(nil, mockmodule.NewIdentityProvider(t))
+	_, err = command.Handler(context.Background(), &admin.CommandRequest{})
+	require.Error(t, err)
+}
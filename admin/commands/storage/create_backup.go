@@ -0,0 +1,74 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+
+	"github.com/dgraph-io/badger/v2"
+	"github.com/rs/zerolog/log"
+
+	"github.com/onflow/flow-go/admin"
+	"github.com/onflow/flow-go/admin/commands"
+	"github.com/onflow/flow-go/storage/badger/backup"
+)
+
+var _ commands.AdminCommand = (*CreateBackupCommand)(nil)
+
+// CreateBackupCommand is an admin command which creates a consistent backup of the protocol
+// database while the node is running. The backup is written into the directory given by the
+// "dir" field, which must be an absolute path that does not exist yet. On execution nodes, the
+// latest ledger checkpoint is included in the backup, together with the WAL segments following it
+// if needed to restore the executed state. The response is the manifest of the backup, which
+// records the finalized, sealed and executed blocks it corresponds to.
+type CreateBackupCommand struct {
+	db            *badger.DB
+	checkpointDir string
+}
+
+// NewCreateBackupCommand creates a CreateBackupCommand. If checkpointDir is not empty,
+// the latest checkpoint in checkpointDir, and the WAL following it, are included in the backups.
+func NewCreateBackupCommand(db *badger.DB, checkpointDir string) *CreateBackupCommand {
+	return &CreateBackupCommand{
+		db:            db,
+		checkpointDir: checkpointDir,
+	}
+}
+
+func (c *CreateBackupCommand) Handler(_ context.Context, req *admin.CommandRequest) (interface{}, error) {
+	dir := req.ValidatorData.(string)
+
+	log.Info().Str("dir", dir).Msg("admintool: creating database backup")
+	manifest, err := backup.Create(c.db, dir, c.checkpointDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create backup: %w", err)
+	}
+	log.Info().
+		Str("dir", dir).
+		Uint64("finalized_height", manifest.FinalizedHeight).
+		Uint64("sealed_height", manifest.SealedHeight).
+		Msg("admintool: database backup created")
+
+	return commands.ConvertToMap(manifest)
+}
+
+// Validator validates the request.
+// Returns admin.InvalidAdminReqError for invalid/malformed requests.
+func (c *CreateBackupCommand) Validator(req *admin.CommandRequest) error {
+	input, ok := req.Data.(map[string]interface{})
+	if !ok {
+		return admin.NewInvalidAdminReqFormatError("expected map[string]any")
+	}
+
+	rawDir, ok := input["dir"]
+	if !ok {
+		return admin.NewInvalidAdminReqErrorf("the \"dir\" field is required")
+	}
+	dir, ok := rawDir.(string)
+	if !ok || !filepath.IsAbs(dir) {
+		return admin.NewInvalidAdminReqParameterError("dir", "must be an absolute path", rawDir)
+	}
+
+	req.ValidatorData = filepath.Clean(dir)
+	return nil
+}
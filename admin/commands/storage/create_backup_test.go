@@ -0,0 +1,70 @@
+package storage
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/dgraph-io/badger/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/admin"
+	"github.com/onflow/flow-go/storage/badger/backup"
+	"github.com/onflow/flow-go/storage/badger/operation"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+func TestCreateBackupValidator(t *testing.T) {
+	t.Parallel()
+
+	command := NewCreateBackupCommand(nil, "")
+
+	for _, data := range []interface{}{
+		"dir",
+		map[string]interface{}{},
+		map[string]interface{}{"dir": 1},
+		map[string]interface{}{"dir": "relative/path"},
+	} {
+		req := &admin.CommandRequest{Data: data}
+		assert.Error(t, command.Validator(req), data)
+	}
+
+	req := &admin.CommandRequest{Data: map[string]interface{}{"dir": "/backups/node/"}}
+	require.NoError(t, command.Validator(req))
+	assert.Equal(t, "/backups/node", req.ValidatorData)
+}
+
+func TestCreateBackup(t *testing.T) {
+	t.Parallel()
+
+	unittest.RunWithBadgerDB(t, func(db *badger.DB) {
+		blockID := unittest.IdentifierFixture()
+		require.NoError(t, db.Update(func(tx *badger.Txn) error {
+			err := operation.IndexBlockHeight(0, blockID)(tx)
+			if err != nil {
+				return err
+			}
+			err = operation.InsertFinalizedHeight(0)(tx)
+			if err != nil {
+				return err
+			}
+			return operation.InsertSealedHeight(0)(tx)
+		}))
+
+		dir := filepath.Join(unittest.TempDir(t), "backup")
+		defer os.RemoveAll(filepath.Dir(dir))
+
+		command := NewCreateBackupCommand(db, "")
+		req := &admin.CommandRequest{Data: map[string]interface{}{"dir": dir}}
+		require.NoError(t, command.Validator(req))
+		result, err := command.Handler(context.Background(), req)
+		require.NoError(t, err)
+
+		manifest, err := backup.Validate(dir)
+		require.NoError(t, err)
+		assert.Equal(t, blockID, manifest.FinalizedBlockID)
+		assert.Equal(t, blockID.String(), result.(map[string]interface{})["finalized_block_id"])
+	})
+}
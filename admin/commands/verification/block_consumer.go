@@ -0,0 +1,12 @@
+package verification
+
+// BlockConsumer is the block consumer of the verification node, which hands the finalized blocks to the
+// assigner engine in the order of their height, and keeps track of the last processed height.
+type BlockConsumer interface {
+	// LastProcessedIndex returns the height of the last block processed by the block consumer.
+	LastProcessedIndex() uint64
+
+	// FastForward moves the processed height of the block consumer forward to the given height, so
+	// that the blocks up to and including it are skipped.
+	FastForward(height uint64) error
+}
@@ -0,0 +1,85 @@
+package verification
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/onflow/flow-go/admin"
+	"github.com/onflow/flow-go/admin/commands"
+	"github.com/onflow/flow-go/state/protocol"
+)
+
+var _ commands.AdminCommand = (*FastForwardBlockConsumerCommand)(nil)
+
+// FastForwardBlockConsumerCommand moves the processed height of the block consumer of the verification
+// node forward, so that a node catching up skips verifying the blocks that are already sealed.
+type FastForwardBlockConsumerCommand struct {
+	state         protocol.State
+	blockConsumer BlockConsumer
+}
+
+// NewFastForwardBlockConsumerCommand creates a new FastForwardBlockConsumerCommand object
+func NewFastForwardBlockConsumerCommand(state protocol.State, blockConsumer BlockConsumer) *FastForwardBlockConsumerCommand {
+	return &FastForwardBlockConsumerCommand{
+		state:         state,
+		blockConsumer: blockConsumer,
+	}
+}
+
+// Handler fast forwards the block consumer to the validated height.
+// Returns "ok" if successful.
+func (f *FastForwardBlockConsumerCommand) Handler(_ context.Context, req *admin.CommandRequest) (interface{}, error) {
+	height := req.ValidatorData.(uint64)
+	previous := f.blockConsumer.LastProcessedIndex()
+
+	err := f.blockConsumer.FastForward(height)
+	if err != nil {
+		return nil, fmt.Errorf("could not fast forward block consumer to height %d: %w", height, err)
+	}
+
+	log.Info().Msgf("admintool: block consumer fast forwarded from height %d to height %d", previous, height)
+
+	return "ok", nil
+}
+
+// Validator checks the inputs for FastForwardBlockConsumer command.
+// It expects the following fields in the Data field of the req object:
+//   - height in a numeric format
+//
+// Additionally, height must be beyond the last processed height of the block consumer, and not beyond
+// the latest sealed height. If a float value is provided, only the integer part is used.
+// The following sentinel errors are expected during normal operations:
+// * `admin.InvalidAdminReqError` if the height is missing, in a wrong format, or out of range
+func (f *FastForwardBlockConsumerCommand) Validator(req *admin.CommandRequest) error {
+	input, ok := req.Data.(map[string]interface{})
+	if !ok {
+		return admin.NewInvalidAdminReqFormatError("expected map[string]any")
+	}
+	result, ok := input["height"]
+	if !ok {
+		return admin.NewInvalidAdminReqErrorf("missing required field: 'height'")
+	}
+	value, ok := result.(float64)
+	if !ok || value <= 0 {
+		return admin.NewInvalidAdminReqParameterError("height", "must be number >0", result)
+	}
+	height := uint64(value)
+
+	processed := f.blockConsumer.LastProcessedIndex()
+	if height <= processed {
+		return admin.NewInvalidAdminReqParameterError("height", fmt.Sprintf("must be beyond the processed height %d", processed), result)
+	}
+
+	sealed, err := f.state.Sealed().Head()
+	if err != nil {
+		return fmt.Errorf("could not get sealed head: %w", err)
+	}
+	if height > sealed.Height {
+		return admin.NewInvalidAdminReqParameterError("height", fmt.Sprintf("must not be beyond the sealed height %d", sealed.Height), result)
+	}
+
+	req.ValidatorData = height
+	return nil
+}
@@ -0,0 +1,139 @@
+package verification
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/admin"
+	protocolmock "github.com/onflow/flow-go/state/protocol/mock"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+// blockConsumer is a test block consumer, which keeps the processed height in memory.
+type blockConsumer struct {
+	processed uint64
+}
+
+func (b *blockConsumer) LastProcessedIndex() uint64 {
+	return b.processed
+}
+
+func (b *blockConsumer) FastForward(height uint64) error {
+	b.processed = height
+	return nil
+}
+
+// mockState returns a protocol state with the latest sealed and finalized blocks at the given heights.
+func mockState(t *testing.T, sealedHeight uint64, finalizedHeight uint64) *protocolmock.State {
+	state := protocolmock.NewState(t)
+
+	sealed := protocolmock.NewSnapshot(t)
+	sealed.On("Head").Return(unittest.BlockHeaderFixture(unittest.WithHeaderHeight(sealedHeight)), nil).Maybe()
+	state.On("Sealed").Return(sealed).Maybe()
+
+	final := protocolmock.NewSnapshot(t)
+	final.On("Head").Return(unittest.BlockHeaderFixture(unittest.WithHeaderHeight(finalizedHeight)), nil).Maybe()
+	state.On("Final").Return(final).Maybe()
+
+	return state
+}
+
+func TestFastForwardCommandParsing(t *testing.T) {
+	consumer := &blockConsumer{processed: 10}
+	cmd := NewFastForwardBlockConsumerCommand(mockState(t, 100, 120), consumer)
+
+	t.Run("happy path", func(t *testing.T) {
+		req := &admin.CommandRequest{
+			Data: map[string]interface{}{
+				"height": float64(50), // raw json parses to float64
+			},
+		}
+
+		err := cmd.Validator(req)
+		require.NoError(t, err)
+		require.Equal(t, uint64(50), req.ValidatorData)
+	})
+
+	t.Run("sealed height", func(t *testing.T) {
+		req := &admin.CommandRequest{
+			Data: map[string]interface{}{
+				"height": float64(100),
+			},
+		}
+
+		err := cmd.Validator(req)
+		require.NoError(t, err)
+		require.Equal(t, uint64(100), req.ValidatorData)
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		req := &admin.CommandRequest{
+			Data: map[string]interface{}{},
+		}
+
+		err := cmd.Validator(req)
+		require.True(t, admin.IsInvalidAdminParameterError(err))
+	})
+
+	t.Run("wrong height type", func(t *testing.T) {
+		req := &admin.CommandRequest{
+			Data: map[string]interface{}{
+				"height": "abc",
+			},
+		}
+
+		err := cmd.Validator(req)
+		require.True(t, admin.IsInvalidAdminParameterError(err))
+	})
+
+	t.Run("not beyond processed height", func(t *testing.T) {
+		req := &admin.CommandRequest{
+			Data: map[string]interface{}{
+				"height": float64(10),
+			},
+		}
+
+		err := cmd.Validator(req)
+		require.True(t, admin.IsInvalidAdminParameterError(err))
+	})
+
+	t.Run("beyond sealed height", func(t *testing.T) {
+		req := &admin.CommandRequest{
+			Data: map[string]interface{}{
+				"height": float64(101),
+			},
+		}
+
+		err := cmd.Validator(req)
+		require.True(t, admin.IsInvalidAdminParameterError(err))
+	})
+}
+
+func TestFastForwardCommandSetsHeight(t *testing.T) {
+	consumer := &blockConsumer{processed: 10}
+	cmd := NewFastForwardBlockConsumerCommand(mockState(t, 100, 120), consumer)
+
+	req := &admin.CommandRequest{
+		ValidatorData: uint64(50),
+	}
+
+	_, err := cmd.Handler(context.TODO(), req)
+	require.NoError(t, err)
+	require.Equal(t, uint64(50), consumer.processed)
+}
+
+func TestGetCatchUpProgress(t *testing.T) {
+	consumer := &blockConsumer{processed: 10}
+	cmd := NewGetCatchUpProgressCommand(mockState(t, 100, 120), consumer)
+
+	result, err := cmd.Handler(context.TODO(), &admin.CommandRequest{})
+	require.NoError(t, err)
+	require.Equal(t, map[string]interface{}{
+		"processed_height":     uint64(10),
+		"sealed_height":        uint64(100),
+		"finalized_height":     uint64(120),
+		"blocks_behind_sealed": uint64(90),
+	}, result)
+}
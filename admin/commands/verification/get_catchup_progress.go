@@ -0,0 +1,59 @@
+package verification
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/onflow/flow-go/admin"
+	"github.com/onflow/flow-go/admin/commands"
+	"github.com/onflow/flow-go/state/protocol"
+)
+
+var _ commands.AdminCommand = (*GetCatchUpProgressCommand)(nil)
+
+// GetCatchUpProgressCommand reports how far the block consumer of the verification node is behind
+// the sealed and finalized heads of the protocol state.
+type GetCatchUpProgressCommand struct {
+	state         protocol.State
+	blockConsumer BlockConsumer
+}
+
+// NewGetCatchUpProgressCommand creates a new GetCatchUpProgressCommand object
+func NewGetCatchUpProgressCommand(state protocol.State, blockConsumer BlockConsumer) *GetCatchUpProgressCommand {
+	return &GetCatchUpProgressCommand{
+		state:         state,
+		blockConsumer: blockConsumer,
+	}
+}
+
+// Handler returns the last processed height of the block consumer, along with the sealed and finalized heights.
+// No errors are expected during normal operation.
+func (g *GetCatchUpProgressCommand) Handler(_ context.Context, _ *admin.CommandRequest) (interface{}, error) {
+	processed := g.blockConsumer.LastProcessedIndex()
+
+	sealed, err := g.state.Sealed().Head()
+	if err != nil {
+		return nil, fmt.Errorf("could not get sealed head: %w", err)
+	}
+	finalized, err := g.state.Final().Head()
+	if err != nil {
+		return nil, fmt.Errorf("could not get finalized head: %w", err)
+	}
+
+	behindSealed := uint64(0)
+	if sealed.Height > processed {
+		behindSealed = sealed.Height - processed
+	}
+
+	return map[string]interface{}{
+		"processed_height":     processed,
+		"sealed_height":        sealed.Height,
+		"finalized_height":     finalized.Height,
+		"blocks_behind_sealed": behindSealed,
+	}, nil
+}
+
+// Validator validates the request, this command takes no input.
+func (g *GetCatchUpProgressCommand) Validator(_ *admin.CommandRequest) error {
+	return nil
+}
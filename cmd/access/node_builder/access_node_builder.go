@@ -71,6 +71,7 @@ import (
 	"github.com/onflow/flow-go/network/p2p/distributor"
 	"github.com/onflow/flow-go/network/p2p/middleware"
 	"github.com/onflow/flow-go/network/p2p/p2pbuilder"
+	"github.com/onflow/flow-go/network/p2p/peerrecord"
 	"github.com/onflow/flow-go/network/p2p/subscription"
 	"github.com/onflow/flow-go/network/p2p/tracer"
 	"github.com/onflow/flow-go/network/p2p/translator"
@@ -131,8 +132,12 @@ type AccessNodeConfig struct {
 type PublicNetworkConfig struct {
 	// NetworkKey crypto.PublicKey // TODO: do we need a different key for the public network?
 	BindAddress string
-	Network     network.Network
-	Metrics     module.NetworkMetrics
+	// PeerRecordAddress is the address of the node on the public network published in its signed peer record, in
+	// host:port format. The node does not publish a peer record if it is not set.
+	PeerRecordAddress string
+	PeerRecordTTL     time.Duration
+	Network           network.Network
+	Metrics           module.NetworkMetrics
 }
 
 // DefaultAccessNodeConfig defines all the default values for the AccessNodeConfig
@@ -170,8 +175,10 @@ func DefaultAccessNodeConfig() *AccessNodeConfig {
 		apiRatelimits:                nil,
 		apiBurstlimits:               nil,
 		PublicNetworkConfig: PublicNetworkConfig{
-			BindAddress: cmd.NotSet,
-			Metrics:     metrics.NewNoopCollector(),
+			BindAddress:       cmd.NotSet,
+			PeerRecordAddress: "",
+			PeerRecordTTL:     peerrecord.DefaultRecordTTL,
+			Metrics:           metrics.NewNoopCollector(),
 		},
 		executionDataSyncEnabled: false,
 		executionDataDir:         filepath.Join(homedir, ".flow", "execution_data"),
@@ -372,6 +379,7 @@ func (builder *FlowAccessNodeBuilder) buildSyncEngine() *FlowAccessNodeBuilder {
 		sync, err := synceng.New(
 			node.Logger,
 			node.Metrics.Engine,
+			node.Metrics.SyncPeers,
 			node.Network,
 			node.Me,
 			node.Storage.Blocks,
@@ -611,6 +619,8 @@ func (builder *FlowAccessNodeBuilder) extraFlags() {
 		flags.StringToIntVar(&builder.apiBurstlimits, "api-burst-limits", defaultConfig.apiBurstlimits, "burst limits for Access API methods e.g. Ping=100,GetTransaction=100 etc.")
 		flags.BoolVar(&builder.supportsObserver, "supports-observer", defaultConfig.supportsObserver, "true if this staked access node supports observer or follower connections")
 		flags.StringVar(&builder.PublicNetworkConfig.BindAddress, "public-network-address", defaultConfig.PublicNetworkConfig.BindAddress, "staked access node's public network bind address")
+		flags.StringVar(&builder.PublicNetworkConfig.PeerRecordAddress, "public-network-peer-record-address", defaultConfig.PublicNetworkConfig.PeerRecordAddress, "staked access node's public network address published to the observers in its signed peer record e.g. access-001.mainnet.flow.org:3570 (if empty no peer record is published)")
+		flags.DurationVar(&builder.PublicNetworkConfig.PeerRecordTTL, "public-network-peer-record-ttl", defaultConfig.PublicNetworkConfig.PeerRecordTTL, "validity of the signed peer records of the staked access node, which are renewed halfway through their validity")
 
 		// ExecutionDataRequester config
 		flags.BoolVar(&builder.executionDataSyncEnabled, "execution-data-sync-enabled", defaultConfig.executionDataSyncEnabled, "whether to enable the execution data sync protocol")
@@ -625,6 +635,14 @@ func (builder *FlowAccessNodeBuilder) extraFlags() {
 		if builder.supportsObserver && (builder.PublicNetworkConfig.BindAddress == cmd.NotSet || builder.PublicNetworkConfig.BindAddress == "") {
 			return errors.New("public-network-address must be set if supports-observer is true")
 		}
+		if builder.PublicNetworkConfig.PeerRecordAddress != "" {
+			if !builder.supportsObserver {
+				return errors.New("supports-observer must be true if public-network-peer-record-address is set")
+			}
+			if builder.PublicNetworkConfig.PeerRecordTTL <= 0 || builder.PublicNetworkConfig.PeerRecordTTL > peerrecord.MaxRecordTTL {
+				return fmt.Errorf("public-network-peer-record-ttl must be positive and at most %v", peerrecord.MaxRecordTTL)
+			}
+		}
 		if builder.executionDataSyncEnabled {
 			if builder.executionDataConfig.FetchTimeout <= 0 {
 				return errors.New("execution-data-fetch-timeout must be greater than 0")
@@ -1062,6 +1080,20 @@ func (builder *FlowAccessNodeBuilder) enqueuePublicNetworkInit() {
 		Component("public peer manager", func(node *cmd.NodeConfig) (module.ReadyDoneAware, error) {
 			return libp2pNode.PeerManagerComponent(), nil
 		})
+
+	if builder.PublicNetworkConfig.PeerRecordAddress != "" {
+		builder.Component("public peer record exchange", func(node *cmd.NodeConfig) (module.ReadyDoneAware, error) {
+			// the node publishes its record signed with its networking key, which is also its key on the public network
+			return peerrecord.NewExchange(
+				node.Logger.With().Bool("public", true).Logger(),
+				libp2pNode,
+				builder.SporkID,
+				peerrecord.NewStore(node.IdentityProvider),
+				peerrecord.DefaultExchangeInterval,
+				peerrecord.WithLocalRecord(builder.NodeID, builder.PublicNetworkConfig.PeerRecordAddress, builder.NodeConfig.NetworkKey, builder.PublicNetworkConfig.PeerRecordTTL),
+			)
+		})
+	}
 }
 
 // initLibP2PFactory creates the LibP2P factory function for the given node ID and network key.
@@ -0,0 +1,197 @@
+package run
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	"github.com/onflow/flow-go/model/bootstrap"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/model/flow/assignment"
+	"github.com/onflow/flow-go/model/flow/factory"
+	"github.com/onflow/flow-go/model/flow/filter"
+	"github.com/onflow/flow-go/module/signature"
+	"github.com/onflow/flow-go/state/protocol"
+)
+
+// RecoveryEpochConfig contains the configurable parameters of the recovery epoch.
+type RecoveryEpochConfig struct {
+	FirstView             uint64 // first view of the recovery epoch, 0 to derive it from the snapshot
+	ViewBuffer            uint64 // views between the snapshot's head and the derived first view, on top of the safety threshold
+	NumViewsInEpoch       uint64
+	NumViewsInStaking     uint64
+	NumViewsInDKGPhase    uint64
+	NumCollectionClusters int // 0 to keep the number of clusters of the current epoch
+}
+
+// GenerateRecoverEpochData generates the EpochRecover service event for the recovery epoch
+// following the current epoch of the given snapshot. The cluster root QCs are signed by the
+// given internal nodes, which must hold a super-majority of weight in each cluster.
+// No errors are expected for valid inputs.
+func GenerateRecoverEpochData(
+	snapshot protocol.Snapshot,
+	internalNodes []bootstrap.NodeInfo,
+	randomSource []byte,
+	config RecoveryEpochConfig,
+) (*flow.EpochRecover, error) {
+
+	head, err := snapshot.Head()
+	if err != nil {
+		return nil, fmt.Errorf("could not get snapshot head: %w", err)
+	}
+	safetyThreshold, err := snapshot.Params().EpochCommitSafetyThreshold()
+	if err != nil {
+		return nil, fmt.Errorf("could not get epoch commit safety threshold: %w", err)
+	}
+	currentEpoch := snapshot.Epochs().Current()
+	currentCounter, err := currentEpoch.Counter()
+	if err != nil {
+		return nil, fmt.Errorf("could not get current epoch counter: %w", err)
+	}
+	currentFinalView, err := currentEpoch.FinalView()
+	if err != nil {
+		return nil, fmt.Errorf("could not get current epoch final view: %w", err)
+	}
+	participants, err := currentEpoch.InitialIdentities()
+	if err != nil {
+		return nil, fmt.Errorf("could not get current epoch participants: %w", err)
+	}
+	currentClustering, err := currentEpoch.Clustering()
+	if err != nil {
+		return nil, fmt.Errorf("could not get current epoch clustering: %w", err)
+	}
+
+	// STEP 1: determine the view ranges of the recovery epoch
+	firstView := config.FirstView
+	if firstView == 0 {
+		firstView = head.View + safetyThreshold + config.ViewBuffer
+	}
+	if firstView <= currentFinalView {
+		firstView = currentFinalView + 1
+	}
+	if firstView < head.View+safetyThreshold {
+		return nil, fmt.Errorf("recovery epoch first view %d is within the epoch commit safety threshold of the snapshot's head (view %d + %d)",
+			firstView, head.View, safetyThreshold)
+	}
+	dkgFinalView := config.NumViewsInStaking + config.NumViewsInDKGPhase*3 // 3 DKG phases
+	if config.NumViewsInEpoch <= dkgFinalView {
+		return nil, fmt.Errorf("recovery epoch length %d must exceed the staking and DKG phases (%d)", config.NumViewsInEpoch, dkgFinalView)
+	}
+
+	// STEP 2: assign collectors to new clusters
+	numClusters := config.NumCollectionClusters
+	if numClusters == 0 {
+		numClusters = len(currentClustering)
+	}
+	assignments, clusters, err := constructRecoveryClusterAssignment(participants, internalNodes, numClusters, randomSource)
+	if err != nil {
+		return nil, fmt.Errorf("could not construct cluster assignment: %w", err)
+	}
+
+	// STEP 3: generate root blocks and root QCs for the new clusters
+	counter := currentCounter + 1
+	clusterBlocks := GenerateRootClusterBlocks(counter, clusters)
+	clusterQCs := make([]flow.ClusterQCVoteData, 0, len(clusters))
+	for i, cluster := range clusters {
+		signers := filterRecoveryClusterSigners(cluster, internalNodes)
+		qc, err := GenerateClusterRootQC(signers, cluster, clusterBlocks[i])
+		if err != nil {
+			return nil, fmt.Errorf("could not generate root QC for cluster %d: %w", i, err)
+		}
+		signerIDs, err := signature.DecodeSignerIndicesToIdentifiers(cluster.NodeIDs(), qc.SignerIndices)
+		if err != nil {
+			return nil, fmt.Errorf("could not decode signers of root QC for cluster %d: %w", i, err)
+		}
+		clusterQCs = append(clusterQCs, flow.ClusterQCVoteDataFromQC(&flow.QuorumCertificateWithSignerIDs{
+			View:      qc.View,
+			BlockID:   qc.BlockID,
+			SignerIDs: signerIDs,
+			SigData:   qc.SigData,
+		}))
+	}
+
+	// STEP 4: re-use the DKG of the current epoch
+	dkg, err := currentEpoch.DKG()
+	if err != nil {
+		return nil, fmt.Errorf("could not get current epoch DKG: %w", err)
+	}
+	dkgParticipantKeys, err := protocol.GetDKGParticipantKeys(dkg, participants.Filter(filter.IsValidDKGParticipant))
+	if err != nil {
+		return nil, fmt.Errorf("could not get current epoch DKG participant keys: %w", err)
+	}
+
+	epochRecover := &flow.EpochRecover{
+		EpochSetup: flow.EpochSetup{
+			Counter:            counter,
+			FirstView:          firstView,
+			DKGPhase1FinalView: firstView + config.NumViewsInStaking + config.NumViewsInDKGPhase - 1,
+			DKGPhase2FinalView: firstView + config.NumViewsInStaking + config.NumViewsInDKGPhase*2 - 1,
+			DKGPhase3FinalView: firstView + config.NumViewsInStaking + config.NumViewsInDKGPhase*3 - 1,
+			FinalView:          firstView + config.NumViewsInEpoch - 1,
+			Participants:       participants,
+			Assignments:        assignments,
+			RandomSource:       randomSource,
+		},
+		EpochCommit: flow.EpochCommit{
+			Counter:            counter,
+			ClusterQCs:         clusterQCs,
+			DKGGroupKey:        dkg.GroupKey(),
+			DKGParticipantKeys: dkgParticipantKeys,
+		},
+	}
+	return epochRecover, nil
+}
+
+// constructRecoveryClusterAssignment assigns the collectors among the participants to the given
+// number of clusters. Same as during bootstrapping, internal and partner collectors are shuffled
+// and distributed round-robin across the clusters, so that each cluster has a super-majority of
+// internal nodes which can sign the cluster root QC. The shuffle is seeded by the random source.
+func constructRecoveryClusterAssignment(participants flow.IdentityList, internalNodes []bootstrap.NodeInfo, numClusters int, randomSource []byte) (flow.AssignmentList, flow.ClusterList, error) {
+	if numClusters < 1 {
+		return nil, nil, fmt.Errorf("need at least one collection cluster")
+	}
+
+	internalIDs := make(map[flow.Identifier]struct{}, len(internalNodes))
+	for _, node := range internalNodes {
+		internalIDs[node.NodeID] = struct{}{}
+	}
+	isInternal := func(identity *flow.Identity) bool {
+		_, ok := internalIDs[identity.NodeID]
+		return ok
+	}
+
+	seed := int64(binary.BigEndian.Uint64(randomSource))
+	collectors := participants.Filter(filter.And(filter.HasRole(flow.RoleCollection), filter.HasWeight(true)))
+	internals := collectors.Filter(isInternal).DeterministicShuffle(seed)
+	partners := collectors.Filter(filter.Not(isInternal)).DeterministicShuffle(seed)
+	if len(internals) < numClusters {
+		return nil, nil, fmt.Errorf("not enough internal collectors (%d) for %d clusters", len(internals), numClusters)
+	}
+
+	identifierLists := make([]flow.IdentifierList, numClusters)
+	// first, round-robin internal nodes into each cluster
+	for i, node := range internals {
+		identifierLists[i%numClusters] = append(identifierLists[i%numClusters], node.NodeID)
+	}
+	// next, round-robin partner nodes into each cluster
+	for i, node := range partners {
+		identifierLists[i%numClusters] = append(identifierLists[i%numClusters], node.NodeID)
+	}
+
+	assignments := assignment.FromIdentifierLists(identifierLists)
+	clusters, err := factory.NewClusterList(assignments, collectors)
+	if err != nil {
+		return nil, nil, fmt.Errorf("could not create cluster list: %w", err)
+	}
+	return assignments, clusters, nil
+}
+
+// filterRecoveryClusterSigners returns the internal nodes which are members of the given cluster.
+func filterRecoveryClusterSigners(cluster flow.IdentityList, internalNodes []bootstrap.NodeInfo) []bootstrap.NodeInfo {
+	var filtered []bootstrap.NodeInfo
+	for _, node := range internalNodes {
+		if _, isInCluster := cluster.ByNodeID(node.NodeID); isInCluster {
+			filtered = append(filtered, node)
+		}
+	}
+	return filtered
+}
@@ -0,0 +1,132 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	synceng "github.com/onflow/flow-go/engine/common/synchronization"
+	"github.com/rs/zerolog"
+
+	"github.com/onflow/flow-go/model/bootstrap"
+	"github.com/onflow/flow-go/state/protocol/inmem"
+	utilsio "github.com/onflow/flow-go/utils/io"
+)
+
+// CheckpointSyncConsumer returns the consumer for checkpoints obtained by checkpoint-based
+// fast sync of the chain synchronization engine. As the protocol state and the consensus
+// follower can't jump forward in place, the consumer persists the verified checkpoint to
+// the bootstrap directory and terminates the node. Upon restart, the node re-bootstraps its
+// protocol state from the checkpoint, and replaces its database (see applyCheckpoint).
+//
+// Checkpoint sync must only be enabled for node roles which can afford to drop their local state
+// below the checkpoint: observer nodes, which only follow the chain, and verification nodes, which
+// only verify chunks of blocks finalized after the checkpoint. Access nodes must not use it, as
+// they would lose the indexed collections, transactions and events and never index the skipped
+// blocks. Execution nodes would end up with an execution state inconsistent with the checkpoint,
+// and consensus nodes participate in the protocol and must not skip blocks.
+func CheckpointSyncConsumer(node *NodeConfig) synceng.CheckpointConsumer {
+	return func(snapshot *inmem.Snapshot) error {
+		path := filepath.Join(node.BootstrapDir, bootstrap.PathCheckpointProtocolStateSnapshot)
+
+		// write to a temporary file first, so we never restart from a partially written checkpoint
+		tmpPath := path + ".tmp"
+		err := utilsio.WriteJSON(tmpPath, snapshot.Encodable())
+		if err != nil {
+			return fmt.Errorf("could not write checkpoint (path=%s): %w", tmpPath, err)
+		}
+		err = os.Rename(tmpPath, path)
+		if err != nil {
+			return fmt.Errorf("could not move checkpoint into place (path=%s): %w", path, err)
+		}
+
+		head, _ := snapshot.Head()
+		node.Logger.Fatal().
+			Uint64("checkpoint_height", head.Height).
+			Str("path", path).
+			Msg("persisted checkpoint, terminating to re-bootstrap protocol state from checkpoint upon restart")
+		return nil
+	}
+}
+
+// applyCheckpoint applies a checkpoint persisted by checkpoint-based fast sync, if there is one.
+// The protocol state is bootstrapped from the checkpoint into a temporary directory first, using the
+// given bootstrap function. Only if this succeeds, the existing database is moved aside (not deleted)
+// and replaced by the temporary directory, and the checkpoint file is renamed, so that it is only
+// applied once. If the database can't be replaced, the existing database is restored and an error
+// is returned. A checkpoint which can't be read or bootstrapped from is renamed as rejected, and the
+// node continues with its existing database, requesting a checkpoint from other peers.
+// It returns the applied checkpoint, or nil if there is no checkpoint to apply.
+func applyCheckpoint(
+	log zerolog.Logger,
+	bootstrapDir string,
+	datadir string,
+	bootstrapState func(checkpoint *inmem.Snapshot, dir string) error,
+) (*inmem.Snapshot, error) {
+	path := filepath.Join(bootstrapDir, bootstrap.PathCheckpointProtocolStateSnapshot)
+	if !utilsio.FileExists(path) {
+		return nil, nil
+	}
+
+	reject := func(err error) (*inmem.Snapshot, error) {
+		log.Error().Err(err).Str("path", path).Msg("rejecting checkpoint, continuing with existing protocol state")
+		err = os.Rename(path, path+".rejected")
+		if err != nil {
+			return nil, fmt.Errorf("could not mark checkpoint as rejected (path=%s): %w", path, err)
+		}
+		return nil, nil
+	}
+
+	snapshot, err := loadSnapshot(path)
+	if err != nil {
+		return reject(fmt.Errorf("could not read checkpoint: %w", err))
+	}
+
+	// remove leftovers of an interrupted attempt to apply a checkpoint
+	tmpDir := datadir + ".checkpoint"
+	err = os.RemoveAll(tmpDir)
+	if err != nil {
+		return nil, fmt.Errorf("could not remove temporary datadir (path=%s): %w", tmpDir, err)
+	}
+	err = bootstrapState(snapshot, tmpDir)
+	if err != nil {
+		removeErr := os.RemoveAll(tmpDir)
+		if removeErr != nil {
+			return nil, fmt.Errorf("could not remove temporary datadir (path=%s): %w", tmpDir, removeErr)
+		}
+		return reject(fmt.Errorf("could not bootstrap protocol state from checkpoint: %w", err))
+	}
+
+	backup := ""
+	if utilsio.Exists(datadir) {
+		backup = fmt.Sprintf("%s.pre-checkpoint-%d", datadir, time.Now().Unix())
+		err = os.Rename(datadir, backup)
+		if err != nil {
+			return nil, fmt.Errorf("could not move datadir aside (path=%s): %w", datadir, err)
+		}
+	}
+	// restore moves the existing database back into place, in case the checkpoint can't be applied
+	restore := func(cause error) error {
+		err := os.RemoveAll(datadir)
+		if err == nil && backup != "" {
+			err = os.Rename(backup, datadir)
+		}
+		if err != nil {
+			return fmt.Errorf("could not restore datadir (path=%s, backup=%s): %v, after: %w", datadir, backup, err, cause)
+		}
+		return cause
+	}
+
+	err = os.Rename(tmpDir, datadir)
+	if err != nil {
+		return nil, restore(fmt.Errorf("could not move bootstrapped datadir into place (path=%s): %w", datadir, err))
+	}
+
+	err = os.Rename(path, path+".applied")
+	if err != nil {
+		return nil, restore(fmt.Errorf("could not mark checkpoint as applied (path=%s): %w", path, err))
+	}
+
+	return snapshot, nil
+}
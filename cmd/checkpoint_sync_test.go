@@ -0,0 +1,95 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/model/bootstrap"
+	"github.com/onflow/flow-go/state/protocol/inmem"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+// TestApplyCheckpoint evaluates that a persisted checkpoint replaces the existing database only once the protocol
+// state was bootstrapped from it, and that the existing database is kept otherwise.
+func TestApplyCheckpoint(t *testing.T) {
+	// setup creates a bootstrap directory with a persisted checkpoint, and a datadir with an existing database.
+	setup := func(t *testing.T) (string, string, string) {
+		dir := unittest.TempDir(t)
+		t.Cleanup(func() { require.NoError(t, os.RemoveAll(dir)) })
+		path := filepath.Join(dir, bootstrap.PathCheckpointProtocolStateSnapshot)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0700))
+		require.NoError(t, os.WriteFile(path, []byte("{}"), 0600))
+		datadir := filepath.Join(dir, "data")
+		require.NoError(t, os.MkdirAll(datadir, 0700))
+		require.NoError(t, os.WriteFile(filepath.Join(datadir, "existing"), nil, 0600))
+		return dir, path, datadir
+	}
+
+	t.Run("no checkpoint", func(t *testing.T) {
+		dir := unittest.TempDir(t)
+		defer os.RemoveAll(dir)
+		checkpoint, err := applyCheckpoint(unittest.Logger(), dir, filepath.Join(dir, "data"), func(*inmem.Snapshot, string) error {
+			require.Fail(t, "no checkpoint should be bootstrapped")
+			return nil
+		})
+		require.NoError(t, err)
+		assert.Nil(t, checkpoint)
+	})
+
+	t.Run("bootstrapped", func(t *testing.T) {
+		dir, path, datadir := setup(t)
+		checkpoint, err := applyCheckpoint(unittest.Logger(), dir, datadir, func(_ *inmem.Snapshot, tmpDir string) error {
+			require.NoError(t, os.MkdirAll(tmpDir, 0700))
+			return os.WriteFile(filepath.Join(tmpDir, "bootstrapped"), nil, 0600)
+		})
+		require.NoError(t, err)
+		assert.NotNil(t, checkpoint)
+
+		// the datadir is replaced by the bootstrapped one, and the existing one is moved aside
+		assert.FileExists(t, filepath.Join(datadir, "bootstrapped"))
+		assert.NoFileExists(t, filepath.Join(datadir, "existing"))
+		backups, err := filepath.Glob(datadir + ".pre-checkpoint-*")
+		require.NoError(t, err)
+		require.Len(t, backups, 1)
+		assert.FileExists(t, filepath.Join(backups[0], "existing"))
+		assert.NoDirExists(t, datadir+".checkpoint")
+
+		// the checkpoint is applied only once
+		assert.NoFileExists(t, path)
+		assert.FileExists(t, path+".applied")
+	})
+
+	t.Run("bootstrapping fails", func(t *testing.T) {
+		dir, path, datadir := setup(t)
+		checkpoint, err := applyCheckpoint(unittest.Logger(), dir, datadir, func(_ *inmem.Snapshot, tmpDir string) error {
+			require.NoError(t, os.MkdirAll(tmpDir, 0700))
+			return fmt.Errorf("invalid checkpoint")
+		})
+		require.NoError(t, err)
+		assert.Nil(t, checkpoint)
+
+		// the existing datadir is kept, and the checkpoint is rejected
+		assert.FileExists(t, filepath.Join(datadir, "existing"))
+		assert.NoDirExists(t, datadir+".checkpoint")
+		assert.NoFileExists(t, path)
+		assert.FileExists(t, path+".rejected")
+	})
+
+	t.Run("unreadable checkpoint", func(t *testing.T) {
+		dir, path, datadir := setup(t)
+		require.NoError(t, os.WriteFile(path, []byte("not a checkpoint"), 0600))
+		checkpoint, err := applyCheckpoint(unittest.Logger(), dir, datadir, func(*inmem.Snapshot, string) error {
+			require.Fail(t, "unreadable checkpoint should not be bootstrapped")
+			return nil
+		})
+		require.NoError(t, err)
+		assert.Nil(t, checkpoint)
+		assert.FileExists(t, filepath.Join(datadir, "existing"))
+		assert.FileExists(t, path+".rejected")
+	})
+}
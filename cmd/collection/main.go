@@ -4,7 +4,10 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/onflow/flow/protobuf/go/flow/execution"
 	"github.com/spf13/pflag"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
 
 	client "github.com/onflow/flow-go-sdk/access/grpc"
 	"github.com/onflow/flow-go/cmd/util/cmd/common"
@@ -13,6 +16,7 @@ import (
 	modulecompliance "github.com/onflow/flow-go/module/compliance"
 	"github.com/onflow/flow-go/module/mempool/herocache"
 	"github.com/onflow/flow-go/module/mempool/queue"
+	"github.com/onflow/flow-go/module/mempool/stdmap"
 	"github.com/onflow/flow-go/utils/grpcutils"
 
 	sdkcrypto "github.com/onflow/flow-go-sdk/crypto"
@@ -44,6 +48,7 @@ import (
 	confinalizer "github.com/onflow/flow-go/module/finalizer/consensus"
 	"github.com/onflow/flow-go/module/mempool"
 	epochpool "github.com/onflow/flow-go/module/mempool/epochs"
+	"github.com/onflow/flow-go/module/mempool/persistent"
 	"github.com/onflow/flow-go/module/metrics"
 	"github.com/onflow/flow-go/network/channels"
 	"github.com/onflow/flow-go/state/protocol"
@@ -66,6 +71,12 @@ func main() {
 		builderPayerRateLimitDryRun       bool
 		builderPayerRateLimit             float64
 		builderUnlimitedPayers            []string
+		builderTransactionSelection       string
+		preExecutionCheckExecutionAPIAddr string
+		txPriorityAgingRate               float64
+		txPersistence                     bool
+		txPersistenceLimit                uint
+		txPersistenceFlushInterval        time.Duration
 		hotstuffMinTimeout                time.Duration
 		hotstuffTimeoutAdjustmentFactor   float64
 		hotstuffHappyPathMaxRoundFailures uint64
@@ -80,6 +91,7 @@ func main() {
 		clusterComplianceConfig modulecompliance.Config
 
 		pools                   *epochpool.TransactionPools // epoch-scoped transaction pools
+		txFlusher               *persistent.Flusher         // flushes the persisted transaction pools, if enabled
 		followerBuffer          *buffer.PendingBlocks       // pending block cache for follower
 		finalizationDistributor *pubsub.FinalizationDistributor
 		finalizedHeader         *consync.FinalizedHeaderCache
@@ -123,6 +135,14 @@ func main() {
 			"expiry buffer for inbound transactions")
 		flags.UintVar(&ingestConf.PropagationRedundancy, "ingest-tx-propagation-redundancy", 10,
 			"how many additional cluster members we propagate transactions to")
+		flags.StringVar(&preExecutionCheckExecutionAPIAddr, "ingest-pre-execution-check-execution-api-addr", "",
+			"address of the execution API of the execution node inbound transactions are checked against before execution (revoked proposal key, proposal key sequence number, payer balance), empty disables the pre-execution checks")
+		flags.Uint64Var(&ingestConf.MinPayerBalance, "ingest-min-payer-balance", ingest.DefaultMinPayerBalance,
+			"minimum balance of the payer of inbound transactions at their reference block, when pre-execution checks are enabled; transactions whose payer is below it are rejected (0 disables the payer balance check)")
+		flags.Uint64Var(&ingestConf.MaxSequenceNumberGap, "ingest-max-sequence-number-gap", ingest.DefaultMaxSequenceNumberGap,
+			"maximum difference between the proposal key sequence number of inbound transactions and the sequence number of the key at their reference block, when pre-execution checks are enabled")
+		flags.UintVar(&ingestConf.PreExecutionCheckWorkers, "ingest-pre-execution-check-workers", ingestConf.PreExecutionCheckWorkers,
+			"number of workers reading the account state of inbound transactions from the execution API in the background, when pre-execution checks are enabled")
 		flags.UintVar(&builderExpiryBuffer, "builder-expiry-buffer", builder.DefaultExpiryBuffer,
 			"expiry buffer for transactions in proposed collections")
 		flags.BoolVar(&builderPayerRateLimitDryRun, "builder-rate-limit-dry-run", false,
@@ -137,6 +157,16 @@ func main() {
 			"maximum byte size of the proposed collection")
 		flags.Uint64Var(&maxCollectionTotalGas, "builder-max-collection-total-gas", flow.DefaultMaxCollectionTotalGas,
 			"maximum total amount of maxgas of transactions in proposed collections")
+		flags.StringVar(&builderTransactionSelection, "builder-transaction-selection", string(builder.FIFOSelection),
+			"policy selecting the transactions of proposed collections: fifo or priority (by compute limit and age)")
+		flags.Float64Var(&txPriorityAgingRate, "tx-priority-aging-rate", stdmap.DefaultPriorityAgingRate,
+			"priority gained per second by transactions waiting in the transaction pool, when selecting transactions by priority")
+		flags.BoolVar(&txPersistence, "tx-persistence", false,
+			"whether to persist the transaction pool in the database, so that it is replayed after a restart")
+		flags.UintVar(&txPersistenceLimit, "tx-persistence-limit", 0,
+			"maximum number of transactions persisted in the database per epoch, 0 defaults to twice the tx-limit")
+		flags.DurationVar(&txPersistenceFlushInterval, "tx-persistence-flush-interval", persistent.DefaultFlushInterval,
+			"interval between two writes of the transaction pool changes to the database, when the transaction pool is persisted")
 		flags.DurationVar(&hotstuffMinTimeout, "hotstuff-min-timeout", 2500*time.Millisecond,
 			"the lower timeout bound for the hotstuff pacemaker, this is also used as initial timeout")
 		flags.Float64Var(&hotstuffTimeoutAdjustmentFactor, "hotstuff-timeout-adjustment-factor", timeout.DefaultConfig.TimeoutAdjustmentFactor,
@@ -164,6 +194,14 @@ func main() {
 			}
 			startupTime = t
 		}
+		switch builder.TransactionSelection(builderTransactionSelection) {
+		case builder.FIFOSelection, builder.PrioritySelection:
+		default:
+			return fmt.Errorf("invalid builder-transaction-selection value %q, expected %q or %q", builderTransactionSelection, builder.PrioritySelection, builder.FIFOSelection)
+		}
+		if txPriorityAgingRate < 0 {
+			return fmt.Errorf("invalid tx-priority-aging-rate value %v, must be non-negative", txPriorityAgingRate)
+		}
 		return nil
 	})
 
@@ -185,6 +223,9 @@ func main() {
 		}).
 		Module("transactions mempool", func(node *cmd.NodeConfig) error {
 			create := func(epoch uint64) mempool.Transactions {
+				if builder.TransactionSelection(builderTransactionSelection) == builder.PrioritySelection {
+					return stdmap.NewPrioritizedTransactions(txLimit, stdmap.ComputeLimitPriority, txPriorityAgingRate)
+				}
 				var heroCacheMetricsCollector module.HeroCacheMetrics = metrics.NewNoopCollector()
 				if node.BaseConfig.HeroCacheMetricsEnable {
 					heroCacheMetricsCollector = metrics.CollectionNodeTransactionsCacheMetrics(node.MetricsRegisterer, epoch)
@@ -195,6 +236,19 @@ func main() {
 					heroCacheMetricsCollector)
 			}
 
+			if txPersistence {
+				limit := txPersistenceLimit
+				if limit == 0 {
+					limit = 2 * txLimit
+				}
+				var err error
+				txFlusher = persistent.NewFlusher(node.Logger, txPersistenceFlushInterval)
+				create, err = persistentTransactionPools(node, create, limit, txFlusher)
+				if err != nil {
+					return fmt.Errorf("could not create persistent transaction pools: %w", err)
+				}
+			}
+
 			pools = epochpool.NewTransactionPools(create)
 			err := node.Metrics.Mempool.Register(metrics.ResourceTransaction, pools.CombinedSize)
 			return err
@@ -229,6 +283,12 @@ func main() {
 
 			return nil
 		}).
+		Component("transaction persistence flusher", func(node *cmd.NodeConfig) (module.ReadyDoneAware, error) {
+			if txFlusher == nil {
+				return &module.NoopReadyDoneAware{}, nil
+			}
+			return txFlusher, nil
+		}).
 		Component("machine account config validator", func(node *cmd.NodeConfig) (module.ReadyDoneAware, error) {
 			//@TODO use fallback logic for flowClient similar to DKG/QC contract clients
 			flowClient, err := common.FlowClient(flowClientConfigs[0])
@@ -336,6 +396,7 @@ func main() {
 			sync, err := consync.New(
 				node.Logger,
 				node.Metrics.Engine,
+				node.Metrics.SyncPeers,
 				node.Network,
 				node.Me,
 				node.Storage.Blocks,
@@ -351,6 +412,20 @@ func main() {
 			return sync, nil
 		}).
 		Component("ingestion engine", func(node *cmd.NodeConfig) (module.ReadyDoneAware, error) {
+			var opts []ingest.Option
+			if preExecutionCheckExecutionAPIAddr != "" {
+				conn, err := grpc.Dial(
+					preExecutionCheckExecutionAPIAddr,
+					grpc.WithDefaultCallOptions(grpc.MaxCallRecvMsgSize(int(rpcConf.MaxMsgSize))),
+					grpc.WithTransportCredentials(insecure.NewCredentials()),
+				)
+				if err != nil {
+					return nil, fmt.Errorf("could not connect to execution API for pre-execution checks: %w", err)
+				}
+				reader := ingest.NewExecutionNodeAccountStateReader(execution.NewExecutionAPIClient(conn))
+				opts = append(opts, ingest.WithPreExecutionChecks(reader))
+			}
+
 			ing, err = ingest.New(
 				node.Logger,
 				node.Network,
@@ -362,6 +437,7 @@ func main() {
 				node.RootChainID.Chain(),
 				pools,
 				ingestConf,
+				opts...,
 			)
 			return ing, err
 		}).
@@ -446,6 +522,7 @@ func main() {
 				builder.WithRateLimitDryRun(builderPayerRateLimitDryRun),
 				builder.WithMaxPayerTransactionRate(builderPayerRateLimit),
 				builder.WithUnlimitedPayers(unlimitedPayers...),
+				builder.WithTransactionSelection(builder.TransactionSelection(builderTransactionSelection)),
 			)
 			if err != nil {
 				return nil, err
@@ -622,3 +699,50 @@ func createQCContractClients(node *cmd.NodeConfig, machineAccountInfo *bootstrap
 	}
 	return qcClients, nil
 }
+
+// persistentTransactionPools wraps the transaction pools created by create, so that their transactions are persisted
+// in the database and replayed into the pool of an epoch when it is first created after a restart. The persisted
+// transactions of the epochs which ended before the current epoch are removed. The pools are registered with the
+// flusher, which writes their changes to the database.
+func persistentTransactionPools(node *cmd.NodeConfig, create func(uint64) mempool.Transactions, limit uint, flusher *persistent.Flusher) (func(uint64) mempool.Transactions, error) {
+	counter, err := node.State.Final().Epochs().Current().Counter()
+	if err != nil {
+		return nil, fmt.Errorf("could not get current epoch counter: %w", err)
+	}
+	if counter > 0 {
+		err = persistent.RemoveExpiredEpochs(node.DB, counter-1)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return func(epoch uint64) mempool.Transactions {
+		log := node.Logger.With().Uint64("epoch", epoch).Logger()
+
+		var pool mempool.Transactions
+		var persisted *persistent.Transactions
+		switch wrapped := create(epoch).(type) {
+		case mempool.PrioritizedTransactions:
+			prioritized := persistent.NewPrioritizedTransactions(node.Logger, node.DB, epoch, wrapped, limit)
+			pool, persisted = prioritized, prioritized.Transactions
+		default:
+			persisted = persistent.NewTransactions(node.Logger, node.DB, epoch, wrapped, limit)
+			pool = persisted
+		}
+
+		final, err := node.State.Final().Head()
+		if err != nil {
+			log.Fatal().Err(err).Msg("could not get finalized header to replay persisted transactions")
+		}
+		replayed, dropped, err := persisted.Replay(node.Storage.Headers, final.Height, flow.DefaultTransactionExpiry)
+		if err != nil {
+			log.Fatal().Err(err).Msg("could not replay persisted transactions")
+		}
+		log.Info().
+			Uint("replayed", replayed).
+			Uint("dropped", dropped).
+			Msg("replayed persisted transactions")
+		flusher.Register(persisted)
+		return pool
+	}, nil
+}
@@ -33,6 +33,7 @@ import (
 	"github.com/onflow/flow-go/engine/consensus/approvals/tracker"
 	"github.com/onflow/flow-go/engine/consensus/compliance"
 	dkgeng "github.com/onflow/flow-go/engine/consensus/dkg"
+	"github.com/onflow/flow-go/engine/consensus/faults"
 	"github.com/onflow/flow-go/engine/consensus/ingestion"
 	"github.com/onflow/flow-go/engine/consensus/matching"
 	"github.com/onflow/flow-go/engine/consensus/message_hub"
@@ -500,6 +501,21 @@ func main() {
 
 			return ing, err
 		}).
+		Component("chunk faults engine", func(node *cmd.NodeConfig) (module.ReadyDoneAware, error) {
+			faultsEng, err := faults.New(
+				node.Logger,
+				node.Metrics.Engine,
+				node.Network,
+				node.State,
+				node.Storage.Results,
+				bstorage.NewChunkFaultReports(node.DB),
+			)
+			if err != nil {
+				return nil, fmt.Errorf("could not create chunk faults engine: %w", err)
+			}
+			node.ProtocolEvents.AddConsumer(faultsEng)
+			return faultsEng, nil
+		}).
 		Component("hotstuff committee", func(node *cmd.NodeConfig) (module.ReadyDoneAware, error) {
 			committee, err = committees.NewConsensusCommittee(node.State, node.Me.NodeID())
 			node.ProtocolEvents.AddConsumer(committee)
@@ -757,6 +773,7 @@ func main() {
 			sync, err := synceng.New(
 				node.Logger,
 				node.Metrics.Engine,
+				node.Metrics.SyncPeers,
 				node.Network,
 				node.Me,
 				node.Storage.Blocks,
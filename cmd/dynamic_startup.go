@@ -161,6 +161,12 @@ func DynamicStartPreInit(nodeConfig *NodeConfig) error {
 		return nil
 	}
 
+	// skip dynamic startup if a root snapshot is already set, e.g. from a checkpoint
+	if nodeConfig.RootSnapshot != nil {
+		log.Info().Msg("protocol state is not bootstrapped, will bootstrap using configured root snapshot, skipping dynamic startup")
+		return nil
+	}
+
 	// skip dynamic startup if a root snapshot file is specified - this takes priority
 	rootSnapshotPath := filepath.Join(nodeConfig.BootstrapDir, bootstrap.PathRootProtocolStateSnapshot)
 	if utilsio.FileExists(rootSnapshotPath) {
@@ -153,6 +153,9 @@ func (builder *ExecutionNodeBuilder) LoadComponentsAndModules() {
 		toTriggerCheckpoint: atomic.NewBool(false),
 	}
 
+	// database backups of execution nodes include the ledger checkpoint
+	builder.FlowNodeBuilder.BackupCheckpointDir = builder.exeConf.triedir
+
 	builder.FlowNodeBuilder.
 		AdminCommand("read-execution-data", func(config *NodeConfig) commands.AdminCommand {
 			return stateSyncCommands.NewReadExecutionDataCommand(exeNode.executionDataStore)
@@ -985,6 +988,7 @@ func (exeNode *ExecutionNode) LoadSynchronizationEngine(
 	exeNode.syncEngine, err = synchronization.New(
 		node.Logger,
 		node.Metrics.Engine,
+		node.Metrics.SyncPeers,
 		node.Network,
 		node.Me,
 		node.Storage.Blocks,
@@ -26,12 +26,15 @@ import (
 	"github.com/onflow/flow-go/module/profiler"
 	"github.com/onflow/flow-go/module/updatable_configs"
 	"github.com/onflow/flow-go/network"
+	"github.com/onflow/flow-go/network/alsp"
 	"github.com/onflow/flow-go/network/codec/cbor"
 	"github.com/onflow/flow-go/network/p2p"
 	"github.com/onflow/flow-go/network/p2p/connection"
 	"github.com/onflow/flow-go/network/p2p/dns"
 	"github.com/onflow/flow-go/network/p2p/middleware"
 	"github.com/onflow/flow-go/network/p2p/unicast"
+	netqueue "github.com/onflow/flow-go/network/queue"
+	"github.com/onflow/flow-go/network/topology"
 	"github.com/onflow/flow-go/state/protocol"
 	"github.com/onflow/flow-go/state/protocol/events"
 	bstorage "github.com/onflow/flow-go/storage/badger"
@@ -172,6 +175,8 @@ type BaseConfig struct {
 	db                          *badger.DB
 	HeroCacheMetricsEnable      bool
 	SyncCoreConfig              chainsync.Config
+	CheckpointSyncThreshold     uint64
+	PayloadPruningRetention     uint64
 	CodecFactory                func() network.Codec
 	LibP2PNode                  p2p.LibP2PNode
 	// ComplianceConfig configures either the compliance engine (consensus nodes)
@@ -205,6 +210,10 @@ type NetworkConfig struct {
 	UnicastMessageTimeout time.Duration
 	// UnicastCreateStreamRetryDelay initial delay used in the exponential backoff for create stream retries
 	UnicastCreateStreamRetryDelay time.Duration
+	// UnicastMaxIdleStreamsPerPeer is the maximum number of idle outbound unicast streams kept open per peer for reuse.
+	UnicastMaxIdleStreamsPerPeer int
+	// UnicastStreamIdleTimeout is the duration after which an idle outbound unicast stream is closed.
+	UnicastStreamIdleTimeout time.Duration
 	// DNSCacheTTL time to live for DNS cache
 	DNSCacheTTL time.Duration
 	// LibP2PResourceManagerConfig configuration for p2pbuilder.ResourceManagerConfig
@@ -213,8 +222,24 @@ type NetworkConfig struct {
 	ConnectionManagerConfig *connection.ManagerConfig
 	// size of the queue for notifications about new peers in the disallow list.
 	DisallowListNotificationCacheSize uint32
-	// size of the queue for notifications about gossipsub RPC inspections.
-	GossipSubRPCInspectorNotificationCacheSize uint32
+	// AlspDisablePenalty disables penalizing and disallow-listing nodes reported for misbehavior by the engines,
+	// the reports are only logged and tracked in metrics.
+	AlspDisablePenalty bool
+	// InboundQueueMaxMessagesPerSender is the maximum number of messages of a single sender in the inbound message queue.
+	InboundQueueMaxMessagesPerSender int
+	// InboundQueueMaxSize is the maximum number of messages in the inbound message queue.
+	InboundQueueMaxSize int
+	// InboundQueueRoleWeights are the weights of the roles of the senders sharing the inbound message queue, keyed by role name.
+	InboundQueueRoleWeights map[string]string
+	// InboundQueueChannelWeights are the weights of the messages in the inbound message queue, keyed by channel name.
+	InboundQueueChannelWeights map[string]string
+	// MessageTracingEnabled enables stamping the outgoing messages with a trace ID and their origin time, and recording the
+	// propagation latency of the incoming traced messages.
+	MessageTracingEnabled bool
+	// Topology is the name of the topology selecting the peers the node maintains connections to.
+	Topology string
+	// TopologyRoleFanouts are the maximum numbers of nodes of each role in the fanout of the role-aware topology, keyed by role name.
+	TopologyRoleFanouts map[string]string
 }
 
 // NodeConfig contains all the derived parameters such the NodeID, private keys etc. and initialized instances of
@@ -254,6 +279,12 @@ type NodeConfig struct {
 	IdentityProvider             module.IdentityProvider
 	IDTranslator                 p2p.IDTranslator
 	SyncEngineIdentifierProvider module.IdentifierProvider
+	// NodeDisallowLister disallow-lists misbehaving nodes, it wraps the IdentityProvider.
+	NodeDisallowLister alsp.NodeDisallowLister
+	// TimedDisallowLister disallow-lists nodes for a limited duration on operator request, it wraps the IdentityProvider.
+	TimedDisallowLister p2p.TimedDisallowLister
+	// MisbehaviorReportManager handles the misbehavior reports of the engines.
+	MisbehaviorReportManager *alsp.MisbehaviorReportManager
 
 	// root state information
 	RootSnapshot protocol.Snapshot
@@ -272,6 +303,10 @@ type NodeConfig struct {
 	UnicastRateLimiterDistributor p2p.UnicastRateLimiterDistributor
 	// NodeDisallowListDistributor notifies consumers of updates to disallow listing of nodes.
 	NodeDisallowListDistributor p2p.DisallowListNotificationDistributor
+
+	// BackupCheckpointDir is the directory of the ledger checkpoints which are included in the
+	// backups created by the create-backup admin command. Only execution nodes set it.
+	BackupCheckpointDir string
 }
 
 func DefaultBaseConfig() *BaseConfig {
@@ -287,21 +322,29 @@ func DefaultBaseConfig() *BaseConfig {
 			UnicastCreateStreamRetryDelay:   unicast.DefaultRetryDelay,
 			PeerUpdateInterval:              connection.DefaultPeerUpdateInterval,
 			UnicastMessageTimeout:           middleware.DefaultUnicastTimeout,
+			UnicastMaxIdleStreamsPerPeer:    middleware.DefaultMaxIdleStreamsPerPeer,
+			UnicastStreamIdleTimeout:        middleware.DefaultStreamIdleTimeout,
 			NetworkReceivedMessageCacheSize: p2p.DefaultReceiveCacheSize,
 			// By default we let networking layer trim connections to all nodes that
-			// are no longer part of protocol state.
-			NetworkConnectionPruning:                   connection.ConnectionPruningEnabled,
-			GossipSubConfig:                            p2pbuilder.DefaultGossipSubConfig(),
-			UnicastMessageRateLimit:                    0,
-			UnicastBandwidthRateLimit:                  0,
-			UnicastBandwidthBurstLimit:                 middleware.LargeMsgMaxUnicastMsgSize,
-			UnicastRateLimitLockoutDuration:            10,
-			UnicastRateLimitDryRun:                     true,
-			DNSCacheTTL:                                dns.DefaultTimeToLive,
-			LibP2PResourceManagerConfig:                p2pbuilder.DefaultResourceManagerConfig(),
-			ConnectionManagerConfig:                    connection.DefaultConnManagerConfig(),
-			GossipSubRPCInspectorNotificationCacheSize: distributor.DefaultGossipSubInspectorNotificationQueueCacheSize,
-			DisallowListNotificationCacheSize:          distributor.DefaultDisallowListNotificationQueueCacheSize,
+			// are no longer part of protocol state, or outside the fanout of the topology.
+			// This is safe with all topologies, as their fanouts are symmetric.
+			NetworkConnectionPruning:          connection.ConnectionPruningEnabled,
+			GossipSubConfig:                   p2pbuilder.DefaultGossipSubConfig(),
+			UnicastMessageRateLimit:           0,
+			UnicastBandwidthRateLimit:         0,
+			UnicastBandwidthBurstLimit:        middleware.LargeMsgMaxUnicastMsgSize,
+			UnicastRateLimitLockoutDuration:   10,
+			UnicastRateLimitDryRun:            true,
+			DNSCacheTTL:                       dns.DefaultTimeToLive,
+			LibP2PResourceManagerConfig:       p2pbuilder.DefaultResourceManagerConfig(),
+			ConnectionManagerConfig:           connection.DefaultConnManagerConfig(),
+			DisallowListNotificationCacheSize: distributor.DefaultDisallowListNotificationQueueCacheSize,
+			InboundQueueMaxMessagesPerSender:  netqueue.DefaultMaxMessagesPerSender,
+			InboundQueueMaxSize:               netqueue.DefaultMaxQueueSize,
+			InboundQueueRoleWeights:           map[string]string{},
+			InboundQueueChannelWeights:        map[string]string{},
+			Topology:                          topology.FullyConnectedTopologyName,
+			TopologyRoleFanouts:               map[string]string{},
 		},
 		nodeIDHex:        NotSet,
 		AdminAddr:        NotSet,
@@ -66,6 +66,7 @@ import (
 	"github.com/onflow/flow-go/network/p2p/keyutils"
 	"github.com/onflow/flow-go/network/p2p/middleware"
 	"github.com/onflow/flow-go/network/p2p/p2pbuilder"
+	"github.com/onflow/flow-go/network/p2p/peerrecord"
 	"github.com/onflow/flow-go/network/p2p/subscription"
 	"github.com/onflow/flow-go/network/p2p/tracer"
 	"github.com/onflow/flow-go/network/p2p/translator"
@@ -119,6 +120,9 @@ type ObserverServiceConfig struct {
 	upstreamNodeAddresses     []string
 	upstreamNodePublicKeys    []string
 	upstreamIdentities        flow.IdentityList // the identity list of upstream peers the node uses to forward API requests to
+	peerRecordDiscovery       bool              // whether the node discovers the staked access nodes from their signed peer records
+	peerRecordInterval        time.Duration
+	peerRecordUpstreams       int
 }
 
 // DefaultObserverServiceConfig defines all the default values for the ObserverServiceConfig
@@ -158,6 +162,9 @@ func DefaultObserverServiceConfig() *ObserverServiceConfig {
 		apiTimeout:             3 * time.Second,
 		upstreamNodeAddresses:  []string{},
 		upstreamNodePublicKeys: []string{},
+		peerRecordDiscovery:    false,
+		peerRecordInterval:     peerrecord.DefaultExchangeInterval,
+		peerRecordUpstreams:    peerrecord.DefaultUpstreams,
 	}
 }
 
@@ -169,6 +176,7 @@ type ObserverServiceBuilder struct {
 
 	// components
 	LibP2PNode              p2p.LibP2PNode
+	PeerRecords             *peerrecord.Store
 	FollowerState           stateprotocol.FollowerState
 	SyncCore                *chainsync.Core
 	RpcEng                  *rpc.Engine
@@ -191,6 +199,8 @@ type ObserverServiceBuilder struct {
 
 	// Public network
 	peerID peer.ID
+	// verifiedPeerFilter allows the bootstrap peers and the verified staked access nodes, if peer record discovery is enabled
+	verifiedPeerFilter func(peer.ID) bool
 }
 
 // deriveBootstrapPeerIdentities derives the Flow Identity of the bootstrap peers from the parameters.
@@ -405,6 +415,7 @@ func (builder *ObserverServiceBuilder) buildSyncEngine() *ObserverServiceBuilder
 		sync, err := synceng.New(
 			node.Logger,
 			node.Metrics.Engine,
+			node.Metrics.SyncPeers,
 			node.Network,
 			node.Me,
 			node.Storage.Blocks,
@@ -412,6 +423,11 @@ func (builder *ObserverServiceBuilder) buildSyncEngine() *ObserverServiceBuilder
 			builder.SyncCore,
 			builder.FinalizedHeader,
 			builder.SyncEngineParticipantsProviderFactory(),
+			synceng.WithCheckpointSync(
+				node.CheckpointSyncThreshold,
+				synceng.NewCheckpointVerifier(node.State, builder.Validator),
+				cmd.CheckpointSyncConsumer(node),
+			),
 		)
 		if err != nil {
 			return nil, fmt.Errorf("could not create synchronization engine: %w", err)
@@ -590,6 +606,9 @@ func (builder *ObserverServiceBuilder) extraFlags() {
 		flags.StringSliceVar(&builder.upstreamNodeAddresses, "upstream-node-addresses", defaultConfig.upstreamNodeAddresses, "the gRPC network addresses of the upstream access node. e.g. access-001.mainnet.flow.org:9000,access-002.mainnet.flow.org:9000")
 		flags.StringSliceVar(&builder.upstreamNodePublicKeys, "upstream-node-public-keys", defaultConfig.upstreamNodePublicKeys, "the networking public key of the upstream access node (in the same order as the upstream node addresses) e.g. \"d57a5e9c5.....\",\"44ded42d....\"")
 		flags.BoolVar(&builder.rpcMetricsEnabled, "rpc-metrics-enabled", defaultConfig.rpcMetricsEnabled, "whether to enable the rpc metrics")
+		flags.BoolVar(&builder.peerRecordDiscovery, "peer-record-discovery", defaultConfig.peerRecordDiscovery, "whether to discover the staked access nodes from their signed peer records, and restrict the DHT routing table to the bootstrap and verified staked access nodes")
+		flags.DurationVar(&builder.peerRecordInterval, "peer-record-exchange-interval", defaultConfig.peerRecordInterval, "interval between two exchanges of peer records with the peers")
+		flags.IntVar(&builder.peerRecordUpstreams, "peer-record-upstreams", defaultConfig.peerRecordUpstreams, "number of verified staked access nodes to keep connections with")
 
 		// ExecutionDataRequester config
 		flags.BoolVar(&builder.executionDataSyncEnabled, "execution-data-sync-enabled", defaultConfig.executionDataSyncEnabled, "whether to enable the execution data sync protocol")
@@ -800,6 +819,10 @@ func (builder *ObserverServiceBuilder) Initialize() error {
 
 	builder.enqueueConnectWithStakedAN()
 
+	if builder.peerRecordDiscovery {
+		builder.enqueuePeerRecordExchange()
+	}
+
 	builder.enqueueRPCServer()
 
 	if builder.BaseConfig.MetricsEnabled {
@@ -821,6 +844,9 @@ func (builder *ObserverServiceBuilder) validateParams() error {
 	if builder.ObserverServiceConfig.observerNetworkingKeyPath == cmd.NotSet {
 		return errors.New("networking key not provided")
 	}
+	if builder.BaseConfig.PayloadPruningRetention > 0 {
+		return errors.New("payload pruning is not supported on observer nodes")
+	}
 	if len(builder.bootstrapIdentities) > 0 {
 		return nil
 	}
@@ -840,6 +866,7 @@ func (builder *ObserverServiceBuilder) validateParams() error {
 // The factory function is later passed into the initMiddleware function to eventually instantiate the p2p.LibP2PNode instance
 // The LibP2P host is created with the following options:
 // * DHT as client and seeded with the given bootstrap peers
+// * DHT routing table restricted to the bootstrap peers and the verified staked access nodes, if peer record discovery is enabled
 // * The specified bind address as the listen address
 // * The passed in private key as the libp2p key
 // * No connection gater
@@ -860,6 +887,21 @@ func (builder *ObserverServiceBuilder) initLibP2PFactory(networkKey crypto.Priva
 			pis = append(pis, pi)
 		}
 
+		dhtOptions := []dht.Option{
+			p2pdht.AsClient(),
+			dht.BootstrapPeers(pis...),
+		}
+		if builder.peerRecordDiscovery {
+			// the routing table only admits the bootstrap peers and the staked access nodes with a verified peer record
+			bootstrapPeers := make([]peer.ID, 0, len(pis))
+			for _, pi := range pis {
+				bootstrapPeers = append(bootstrapPeers, pi.ID)
+			}
+			builder.PeerRecords = peerrecord.NewStore(builder.IdentityProvider)
+			builder.verifiedPeerFilter = peerrecord.VerifiedPeerFilter(builder.PeerRecords, bootstrapPeers...)
+			dhtOptions = append(dhtOptions, p2pdht.RoutingTableFilter(builder.verifiedPeerFilter))
+		}
+
 		meshTracer := tracer.NewGossipSubMeshTracer(
 			builder.Logger,
 			builder.Metrics.Network,
@@ -882,8 +924,7 @@ func (builder *ObserverServiceBuilder) initLibP2PFactory(networkKey crypto.Priva
 				return p2pdht.NewDHT(ctx, h, protocols.FlowPublicDHTProtocolID(builder.SporkID),
 					builder.Logger,
 					builder.Metrics.Network,
-					p2pdht.AsClient(),
-					dht.BootstrapPeers(pis...),
+					dhtOptions...,
 				)
 			}).
 			SetStreamCreationRetryInterval(builder.UnicastCreateStreamRetryDelay).
@@ -996,6 +1037,21 @@ func (builder *ObserverServiceBuilder) enqueueConnectWithStakedAN() {
 	})
 }
 
+// enqueuePeerRecordExchange enqueues the exchange of the signed peer records of the staked access nodes, which keeps
+// the observer connected to verified staked access nodes, and its DHT routing table free of unverified peers.
+func (builder *ObserverServiceBuilder) enqueuePeerRecordExchange() {
+	builder.Component("peer record exchange", func(node *cmd.NodeConfig) (module.ReadyDoneAware, error) {
+		return peerrecord.NewExchange(
+			node.Logger,
+			builder.LibP2PNode,
+			builder.SporkID,
+			builder.PeerRecords,
+			builder.peerRecordInterval,
+			peerrecord.WithUpstreams(builder.peerRecordUpstreams, builder.verifiedPeerFilter),
+		)
+	})
+}
+
 func (builder *ObserverServiceBuilder) enqueueRPCServer() {
 	builder.Component("RPC engine", func(node *cmd.NodeConfig) (module.ReadyDoneAware, error) {
 		engineBuilder, err := rpc.NewBuilder(
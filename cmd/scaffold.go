@@ -45,10 +45,12 @@ import (
 	"github.com/onflow/flow-go/module/mempool/queue"
 	"github.com/onflow/flow-go/module/metrics"
 	"github.com/onflow/flow-go/module/profiler"
+	"github.com/onflow/flow-go/module/pruner"
 	"github.com/onflow/flow-go/module/trace"
 	"github.com/onflow/flow-go/module/updatable_configs"
 	"github.com/onflow/flow-go/module/util"
 	"github.com/onflow/flow-go/network"
+	"github.com/onflow/flow-go/network/alsp"
 	netcache "github.com/onflow/flow-go/network/cache"
 	"github.com/onflow/flow-go/network/p2p"
 	"github.com/onflow/flow-go/network/p2p/cache"
@@ -59,8 +61,10 @@ import (
 	"github.com/onflow/flow-go/network/p2p/p2pbuilder"
 	"github.com/onflow/flow-go/network/p2p/ping"
 	"github.com/onflow/flow-go/network/p2p/subscription"
+	"github.com/onflow/flow-go/network/p2p/tracer"
 	"github.com/onflow/flow-go/network/p2p/unicast/protocols"
 	"github.com/onflow/flow-go/network/p2p/unicast/ratelimit"
+	netqueue "github.com/onflow/flow-go/network/queue"
 	"github.com/onflow/flow-go/network/slashing"
 	"github.com/onflow/flow-go/network/topology"
 	"github.com/onflow/flow-go/state/protocol"
@@ -90,6 +94,7 @@ type Metrics struct {
 	Mempool        module.MempoolMetrics
 	CleanCollector module.CleanerMetrics
 	Bitswap        module.BitswapMetrics
+	SyncPeers      module.ChainSyncPeerMetrics
 }
 
 type Storage = storage.All
@@ -179,13 +184,24 @@ func (fnb *FlowNodeBuilder) BaseFlags() {
 	fnb.flags.DurationVar(&fnb.BaseConfig.ConnectionManagerConfig.SilencePeriod, "libp2p-connmgr-silence", defaultConfig.ConnectionManagerConfig.SilencePeriod, "silence period for libp2p connection manager")
 
 	fnb.flags.DurationVar(&fnb.BaseConfig.DNSCacheTTL, "dns-cache-ttl", defaultConfig.DNSCacheTTL, "time-to-live for dns cache")
-	fnb.flags.StringSliceVar(&fnb.BaseConfig.PreferredUnicastProtocols, "preferred-unicast-protocols", nil, "preferred unicast protocols in ascending order of preference")
+	fnb.flags.StringSliceVar(&fnb.BaseConfig.PreferredUnicastProtocols, "preferred-unicast-protocols", nil, "preferred unicast protocols in ascending order of preference, i.e., any of gzip-compression, snappy-compression and zstd-compression")
 	fnb.flags.Uint32Var(&fnb.BaseConfig.NetworkReceivedMessageCacheSize, "networking-receive-cache-size", p2p.DefaultReceiveCacheSize,
 		"incoming message cache size at networking layer")
 	fnb.flags.BoolVar(&fnb.BaseConfig.NetworkConnectionPruning, "networking-connection-pruning", defaultConfig.NetworkConnectionPruning, "enabling connection trimming")
 	fnb.flags.BoolVar(&fnb.BaseConfig.GossipSubConfig.PeerScoring, "peer-scoring-enabled", defaultConfig.GossipSubConfig.PeerScoring, "enabling peer scoring on pubsub network")
 	fnb.flags.DurationVar(&fnb.BaseConfig.GossipSubConfig.LocalMeshLogInterval, "gossipsub-local-mesh-logging-interval", defaultConfig.GossipSubConfig.LocalMeshLogInterval, "logging interval for local mesh in gossipsub")
 	fnb.flags.DurationVar(&fnb.BaseConfig.GossipSubConfig.ScoreTracerInterval, "gossipsub-score-tracer-interval", defaultConfig.GossipSubConfig.ScoreTracerInterval, "logging interval for peer score tracer in gossipsub, set to 0 to disable")
+
+	// gossipsub rpc control message validation limits
+	fnb.flags.Uint64Var(&fnb.BaseConfig.GossipSubConfig.RpcInspector.ValidationConfig.GraftDiscardThreshold, "gossipsub-rpc-graft-discard-threshold", defaultConfig.GossipSubConfig.RpcInspector.ValidationConfig.GraftDiscardThreshold, "maximum number of graft messages allowed in a single gossipsub rpc, rpcs exceeding it are discarded")
+	fnb.flags.Uint64Var(&fnb.BaseConfig.GossipSubConfig.RpcInspector.ValidationConfig.PruneDiscardThreshold, "gossipsub-rpc-prune-discard-threshold", defaultConfig.GossipSubConfig.RpcInspector.ValidationConfig.PruneDiscardThreshold, "maximum number of prune messages allowed in a single gossipsub rpc, rpcs exceeding it are discarded")
+	fnb.flags.Uint64Var(&fnb.BaseConfig.GossipSubConfig.RpcInspector.ValidationConfig.IHaveDiscardThreshold, "gossipsub-rpc-ihave-discard-threshold", defaultConfig.GossipSubConfig.RpcInspector.ValidationConfig.IHaveDiscardThreshold, "maximum number of ihave messages allowed in a single gossipsub rpc, rpcs exceeding it are discarded")
+	fnb.flags.Uint64Var(&fnb.BaseConfig.GossipSubConfig.RpcInspector.ValidationConfig.IHaveSafetyThreshold, "gossipsub-rpc-ihave-safety-threshold", defaultConfig.GossipSubConfig.RpcInspector.ValidationConfig.IHaveSafetyThreshold, "number of ihave messages in a single gossipsub rpc above which only a random sample of them is validated")
+	fnb.flags.Uint64Var(&fnb.BaseConfig.GossipSubConfig.RpcInspector.ValidationConfig.IHaveSampleSize, "gossipsub-rpc-ihave-sample-size", defaultConfig.GossipSubConfig.RpcInspector.ValidationConfig.IHaveSampleSize, "number of ihave messages sampled for validation once the ihave safety threshold is exceeded")
+	fnb.flags.Uint64Var(&fnb.BaseConfig.GossipSubConfig.RpcInspector.ValidationConfig.IWantDiscardThreshold, "gossipsub-rpc-iwant-discard-threshold", defaultConfig.GossipSubConfig.RpcInspector.ValidationConfig.IWantDiscardThreshold, "maximum number of iwant messages allowed in a single gossipsub rpc, rpcs exceeding it are discarded")
+	fnb.flags.Uint64Var(&fnb.BaseConfig.GossipSubConfig.RpcInspector.ValidationConfig.IWantMaxMessageIDs, "gossipsub-rpc-iwant-max-message-ids", defaultConfig.GossipSubConfig.RpcInspector.ValidationConfig.IWantMaxMessageIDs, "maximum number of message ids requested across all iwant messages of a single gossipsub rpc")
+	fnb.flags.Uint64Var(&fnb.BaseConfig.GossipSubConfig.RpcInspector.ValidationConfig.UnsubscribedTopicTolerance, "gossipsub-rpc-unsubscribed-topic-tolerance", defaultConfig.GossipSubConfig.RpcInspector.ValidationConfig.UnsubscribedTopicTolerance, "number of graft and ihave messages for unsubscribed topics dropped from the rpcs of a peer within the tolerance window before the peer is penalized")
+	fnb.flags.DurationVar(&fnb.BaseConfig.GossipSubConfig.RpcInspector.ValidationConfig.UnsubscribedTopicToleranceWindow, "gossipsub-rpc-unsubscribed-topic-tolerance-window", defaultConfig.GossipSubConfig.RpcInspector.ValidationConfig.UnsubscribedTopicToleranceWindow, "window over which the graft and ihave messages for unsubscribed topics of a peer are counted against the tolerance")
 	fnb.flags.UintVar(&fnb.BaseConfig.guaranteesCacheSize, "guarantees-cache-size", bstorage.DefaultCacheSize, "collection guarantees cache size")
 	fnb.flags.UintVar(&fnb.BaseConfig.receiptsCacheSize, "receipts-cache-size", bstorage.DefaultCacheSize, "receipts cache size")
 
@@ -205,6 +221,8 @@ func (fnb *FlowNodeBuilder) BaseFlags() {
 	fnb.flags.UintVar(&fnb.BaseConfig.SyncCoreConfig.MaxAttempts, "sync-max-attempts", defaultConfig.SyncCoreConfig.MaxAttempts, "the maximum number of attempts we make for each requested block/height before discarding")
 	fnb.flags.UintVar(&fnb.BaseConfig.SyncCoreConfig.MaxSize, "sync-max-size", defaultConfig.SyncCoreConfig.MaxSize, "the maximum number of blocks we request in the same block request message")
 	fnb.flags.UintVar(&fnb.BaseConfig.SyncCoreConfig.MaxRequests, "sync-max-requests", defaultConfig.SyncCoreConfig.MaxRequests, "the maximum number of requests we send during each scanning period")
+	fnb.flags.Uint64Var(&fnb.BaseConfig.CheckpointSyncThreshold, "checkpoint-sync-threshold", defaultConfig.CheckpointSyncThreshold, "number of blocks the node must be behind its peers to fast sync from a verified checkpoint, instead of downloading every block (0 disables, only supported by observer and verification nodes)")
+	fnb.flags.Uint64Var(&fnb.BaseConfig.PayloadPruningRetention, "payload-pruning-retention", defaultConfig.PayloadPruningRetention, fmt.Sprintf("number of latest finalized blocks whose payloads, collections, guarantees and receipts are retained, older payloads are pruned while headers, seals and results are kept, as are payloads not yet processed by the node (0 disables pruning, otherwise at least %d; supported on consensus, collection, execution, access and verification nodes)", pruner.MinRetention))
 
 	fnb.flags.Uint64Var(&fnb.BaseConfig.ComplianceConfig.SkipNewProposalsThreshold, "compliance-skip-proposals-threshold", defaultConfig.ComplianceConfig.SkipNewProposalsThreshold, "threshold at which new proposals are discarded rather than cached, if their height is this much above local finalized height")
 
@@ -216,11 +234,29 @@ func (fnb *FlowNodeBuilder) BaseFlags() {
 	fnb.flags.BoolVar(&fnb.BaseConfig.UnicastRateLimitDryRun, "unicast-rate-limit-dry-run", defaultConfig.NetworkConfig.UnicastRateLimitDryRun, "disable peer disconnects and connections gating when rate limiting peers")
 
 	// networking event notifications
-	fnb.flags.Uint32Var(&fnb.BaseConfig.GossipSubRPCInspectorNotificationCacheSize, "gossipsub-rpc-inspector-notification-cache-size", defaultConfig.GossipSubRPCInspectorNotificationCacheSize, "cache size for notification events from gossipsub rpc inspector")
+	fnb.flags.Uint32Var(&fnb.BaseConfig.GossipSubConfig.RpcInspector.NotificationCacheSize, "gossipsub-rpc-inspector-notification-cache-size", defaultConfig.GossipSubConfig.RpcInspector.NotificationCacheSize, "cache size for notification events from gossipsub rpc inspector")
 	fnb.flags.Uint32Var(&fnb.BaseConfig.DisallowListNotificationCacheSize, "disallow-list-notification-cache-size", defaultConfig.DisallowListNotificationCacheSize, "cache size for notification events from disallow list")
 
+	// application layer spam prevention (alsp) protocol
+	fnb.flags.BoolVar(&fnb.BaseConfig.AlspDisablePenalty, "alsp-disable-penalty", defaultConfig.AlspDisablePenalty, "disable the penalty mechanism of the alsp protocol, misbehavior reports are only logged and tracked in metrics")
+
+	// inbound message queue
+	fnb.flags.IntVar(&fnb.BaseConfig.InboundQueueMaxMessagesPerSender, "inbound-queue-max-messages-per-sender", defaultConfig.InboundQueueMaxMessagesPerSender, "maximum number of messages of a single sender in the inbound message queue, beyond which the lowest priority messages of the sender are dropped")
+	fnb.flags.IntVar(&fnb.BaseConfig.InboundQueueMaxSize, "inbound-queue-max-size", defaultConfig.InboundQueueMaxSize, "maximum number of messages in the inbound message queue, beyond which the lowest priority messages of the sender with the most queued messages are dropped")
+	fnb.flags.StringToStringVar(&fnb.BaseConfig.InboundQueueRoleWeights, "inbound-queue-role-weights", defaultConfig.InboundQueueRoleWeights, "weights of the roles of the senders sharing the inbound message queue, e.g., consensus=2,execution=1 (roles not listed have weight 1)")
+	fnb.flags.StringToStringVar(&fnb.BaseConfig.InboundQueueChannelWeights, "inbound-queue-channel-weights", defaultConfig.InboundQueueChannelWeights, "weights of the messages in the inbound message queue per channel, e.g., consensus-committee=2,sync-committee=0.5 (channels not listed have weight 1)")
+
+	// message tracing
+	fnb.flags.BoolVar(&fnb.BaseConfig.MessageTracingEnabled, "network-message-tracing", defaultConfig.MessageTracingEnabled, "stamp the outgoing messages with a trace id and their origin time, and record the propagation latency of the incoming traced messages")
+
+	// topology
+	fnb.flags.StringVar(&fnb.BaseConfig.Topology, "topology", defaultConfig.Topology, fmt.Sprintf("topology selecting the peers the node maintains connections to, one of: %s, %s", topology.FullyConnectedTopologyName, topology.RoleAwareTopologyName))
+	fnb.flags.StringToStringVar(&fnb.BaseConfig.TopologyRoleFanouts, "topology-role-fanouts", defaultConfig.TopologyRoleFanouts, fmt.Sprintf("maximum number of nodes of each role in the fanout of the role-aware topology, e.g., consensus=4,execution=2 (roles not listed have fanout %d)", topology.DefaultRoleFanout))
+
 	// unicast manager options
 	fnb.flags.DurationVar(&fnb.BaseConfig.UnicastCreateStreamRetryDelay, "unicast-manager-create-stream-retry-delay", defaultConfig.NetworkConfig.UnicastCreateStreamRetryDelay, "Initial delay between failing to establish a connection with another node and retrying. This delay increases exponentially (exponential backoff) with the number of subsequent failures to establish a connection.")
+	fnb.flags.IntVar(&fnb.BaseConfig.UnicastMaxIdleStreamsPerPeer, "unicast-max-idle-streams-per-peer", defaultConfig.NetworkConfig.UnicastMaxIdleStreamsPerPeer, "maximum number of idle outbound unicast streams kept open per peer for reuse, 0 creates a new stream for each message")
+	fnb.flags.DurationVar(&fnb.BaseConfig.UnicastStreamIdleTimeout, "unicast-stream-idle-timeout", defaultConfig.NetworkConfig.UnicastStreamIdleTimeout, "duration after which an idle outbound unicast stream is closed")
 }
 
 func (fnb *FlowNodeBuilder) EnqueuePingService() {
@@ -397,8 +433,23 @@ func (fnb *FlowNodeBuilder) EnqueueNetworkInit() {
 		return libp2pNode, nil
 	})
 
+	fnb.Component("misbehavior report manager", func(node *NodeConfig) (module.ReadyDoneAware, error) {
+		manager, err := alsp.NewMisbehaviorReportManager(&alsp.MisbehaviorReportManagerConfig{
+			Logger:            node.Logger,
+			AlspMetrics:       node.Metrics.Network,
+			DisablePenalty:    node.AlspDisablePenalty,
+			DisallowLister:    node.NodeDisallowLister,
+			HeartBeatInterval: alsp.DefaultHeartBeatInterval,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("could not create misbehavior report manager: %w", err)
+		}
+		node.MisbehaviorReportManager = manager
+		return manager, nil
+	})
+
 	fnb.Component(NetworkComponent, func(node *NodeConfig) (module.ReadyDoneAware, error) {
-		cf := conduit.NewDefaultConduitFactory()
+		cf := conduit.NewDefaultConduitFactory(conduit.WithMisbehaviorManager(node.MisbehaviorReportManager))
 		fnb.Logger.Info().Hex("node_id", logging.ID(fnb.NodeID)).Msg("default conduit factory initiated")
 		return fnb.InitFlowNetworkWithConduitFactory(node, cf, unicastRateLimiters, peerManagerFilters)
 	})
@@ -427,8 +478,13 @@ func (fnb *FlowNodeBuilder) InitFlowNetworkWithConduitFactory(node *NodeConfig,
 
 	mwOpts = append(mwOpts,
 		middleware.WithPreferredUnicastProtocols(protocols.ToProtocolNames(fnb.PreferredUnicastProtocols)),
+		middleware.WithUnicastStreamPool(fnb.UnicastMaxIdleStreamsPerPeer, fnb.UnicastStreamIdleTimeout),
 	)
 
+	if fnb.MessageTracingEnabled {
+		mwOpts = append(mwOpts, middleware.WithMessageTracer(tracer.NewMessageTracer(fnb.Logger, fnb.Tracer, fnb.Metrics.Network)))
+	}
+
 	// peerManagerFilters are used by the peerManager via the middleware to filter peers from the topology.
 	if len(peerManagerFilters) > 0 {
 		mwOpts = append(mwOpts, middleware.WithPeerManagerFilters(peerManagerFilters))
@@ -465,17 +521,27 @@ func (fnb *FlowNodeBuilder) InitFlowNetworkWithConduitFactory(node *NodeConfig,
 	}
 
 	// creates network instance
+	queueConfig, err := fnb.inboundQueueConfig()
+	if err != nil {
+		return nil, fmt.Errorf("could not create inbound queue config: %w", err)
+	}
+
+	top, err := fnb.networkTopology(node)
+	if err != nil {
+		return nil, fmt.Errorf("could not create network topology: %w", err)
+	}
+
 	net, err := p2p.NewNetwork(&p2p.NetworkParameters{
 		Logger:              fnb.Logger,
 		Codec:               fnb.CodecFactory(),
 		Me:                  fnb.Me,
 		MiddlewareFactory:   func() (network.Middleware, error) { return fnb.Middleware, nil },
-		Topology:            topology.NewFullyConnectedTopology(),
+		Topology:            top,
 		SubscriptionManager: subscriptionManager,
 		Metrics:             fnb.Metrics.Network,
 		IdentityProvider:    fnb.IdentityProvider,
 		ReceiveCache:        receiveCache,
-		Options:             []p2p.NetworkOptFunction{p2p.WithConduitFactory(cf)},
+		Options:             []p2p.NetworkOptFunction{p2p.WithConduitFactory(cf), p2p.WithInboundQueueConfig(queueConfig)},
 	})
 	if err != nil {
 		return nil, fmt.Errorf("could not initialize network: %w", err)
@@ -494,6 +560,46 @@ func (fnb *FlowNodeBuilder) InitFlowNetworkWithConduitFactory(node *NodeConfig,
 	return net, nil
 }
 
+// networkTopology returns the topology of the network selected by the node flags. The role-aware topology is updated
+// at epoch transitions through the protocol events.
+func (fnb *FlowNodeBuilder) networkTopology(node *NodeConfig) (network.Topology, error) {
+	switch fnb.Topology {
+	case topology.FullyConnectedTopologyName:
+		return topology.NewFullyConnectedTopology(), nil
+	case topology.RoleAwareTopologyName:
+		fanouts, err := topology.ParseRoleFanouts(fnb.TopologyRoleFanouts)
+		if err != nil {
+			return nil, fmt.Errorf("invalid topology role fanouts: %w", err)
+		}
+		top, err := topology.NewRoleAwareTopology(fnb.Logger, node.State, node.Me.NodeID(), fanouts, fnb.LibP2PNode.RequestPeerUpdate)
+		if err != nil {
+			return nil, fmt.Errorf("could not create role-aware topology: %w", err)
+		}
+		fnb.ProtocolEvents.AddConsumer(top)
+		return top, nil
+	default:
+		return nil, fmt.Errorf("unknown topology: %s", fnb.Topology)
+	}
+}
+
+// inboundQueueConfig returns the configuration of the inbound message queue of the network from the node flags.
+func (fnb *FlowNodeBuilder) inboundQueueConfig() (netqueue.FairQueueConfig, error) {
+	roleWeights, err := netqueue.ParseRoleWeights(fnb.InboundQueueRoleWeights)
+	if err != nil {
+		return netqueue.FairQueueConfig{}, fmt.Errorf("invalid inbound queue role weights: %w", err)
+	}
+	channelWeights, err := netqueue.ParseChannelWeights(fnb.InboundQueueChannelWeights)
+	if err != nil {
+		return netqueue.FairQueueConfig{}, fmt.Errorf("invalid inbound queue channel weights: %w", err)
+	}
+	return netqueue.FairQueueConfig{
+		MaxMessagesPerSender: fnb.InboundQueueMaxMessagesPerSender,
+		MaxSize:              fnb.InboundQueueMaxSize,
+		RoleWeights:          roleWeights,
+		ChannelWeights:       channelWeights,
+	}, nil
+}
+
 func (fnb *FlowNodeBuilder) EnqueueMetricsServerInit() {
 	fnb.Component("metrics server", func(node *NodeConfig) (module.ReadyDoneAware, error) {
 		server := metrics.NewServer(fnb.Logger, fnb.BaseConfig.metricsPort)
@@ -564,6 +670,55 @@ func (fnb *FlowNodeBuilder) EnqueueTracer() {
 	})
 }
 
+// EnqueuePayloadPruner enqueues the component pruning the payloads of finalized blocks
+// older than the configured retention.
+func (fnb *FlowNodeBuilder) EnqueuePayloadPruner() {
+	fnb.Component("payload pruner", func(node *NodeConfig) (module.ReadyDoneAware, error) {
+		processed, err := payloadPrunerProcessedHeight(node)
+		if err != nil {
+			return nil, err
+		}
+		payloadPruner, err := pruner.New(node.Logger, node.State, node.Storage.PayloadPruner, node.PayloadPruningRetention, processed)
+		if err != nil {
+			return nil, fmt.Errorf("could not create payload pruner: %w", err)
+		}
+		node.ProtocolEvents.AddConsumer(payloadPruner)
+		return payloadPruner, nil
+	})
+}
+
+// payloadPrunerProcessedHeight returns the height below which payloads are no longer needed by
+// the role-specific processing of the node, or nil if the node only needs the payloads retained
+// by the pruner anyway. Pruning is rejected for roles whose processing is not accounted for.
+func payloadPrunerProcessedHeight(node *NodeConfig) (pruner.ProcessedHeight, error) {
+	switch node.NodeRole {
+	case flow.RoleConsensus.String(), flow.RoleCollection.String():
+		return nil, nil
+	case flow.RoleExecution.String():
+		// blocks above the highest executed block still have to be executed
+		return func() (uint64, error) {
+			var blockID flow.Identifier
+			err := node.DB.View(operation.RetrieveExecutedBlock(&blockID))
+			if err != nil {
+				return 0, fmt.Errorf("could not retrieve highest executed block: %w", err)
+			}
+			header, err := node.Storage.Headers.ByBlockID(blockID)
+			if err != nil {
+				return 0, fmt.Errorf("could not retrieve header of highest executed block %v: %w", blockID, err)
+			}
+			return header.Height, nil
+		}, nil
+	case flow.RoleAccess.String():
+		// the collections of blocks above the last full block have not all been received yet
+		return node.Storage.Blocks.GetLastFullBlockHeight, nil
+	case flow.RoleVerification.String():
+		// blocks above the processed height have not been assigned chunks yet
+		return bstorage.NewConsumerProgress(node.DB, module.ConsumeProgressVerificationBlockHeight).ProcessedIndex, nil
+	default:
+		return nil, fmt.Errorf("payload pruning is not supported for role %s", node.NodeRole)
+	}
+}
+
 func (fnb *FlowNodeBuilder) ParseAndPrintFlags() error {
 	// parse configuration parameters
 	pflag.Parse()
@@ -684,6 +839,7 @@ func (fnb *FlowNodeBuilder) initMetrics() error {
 		Mempool:        metrics.NewNoopCollector(),
 		CleanCollector: metrics.NewNoopCollector(),
 		Bitswap:        metrics.NewNoopCollector(),
+		SyncPeers:      metrics.NewNoopCollector(),
 	}
 	if fnb.BaseConfig.MetricsEnabled {
 		fnb.MetricsRegisterer = prometheus.DefaultRegisterer
@@ -700,6 +856,7 @@ func (fnb *FlowNodeBuilder) initMetrics() error {
 			CleanCollector: metrics.NewCleanerCollector(),
 			Mempool:        mempools,
 			Bitswap:        metrics.NewBitswapCollector(),
+			SyncPeers:      metrics.NewChainSyncPeerCollector(),
 		}
 
 		// registers mempools as a Component so that its Ready method is invoked upon startup
@@ -834,10 +991,41 @@ func (fnb *FlowNodeBuilder) initDB() error {
 		return nil
 	}
 
+	// a checkpoint obtained by checkpoint-based fast sync replaces the existing database
+	checkpoint, err := applyCheckpoint(fnb.Logger, fnb.BaseConfig.BootstrapDir, fnb.BaseConfig.datadir, fnb.bootstrapCheckpoint)
+	if err != nil {
+		return fmt.Errorf("could not apply checkpoint: %w", err)
+	}
+	if checkpoint != nil {
+		head, _ := checkpoint.Head()
+		fnb.Logger.Info().
+			Uint64("checkpoint_height", head.Height).
+			Msg("applied checkpoint, protocol state was re-bootstrapped from checkpoint")
+	}
+
+	publicDB, err := fnb.openPublicDB(fnb.BaseConfig.datadir)
+	if err != nil {
+		return err
+	}
+	fnb.DB = publicDB
+
+	fnb.ShutdownFunc(func() error {
+		if err := fnb.DB.Close(); err != nil {
+			return fmt.Errorf("error closing protocol database: %w", err)
+		}
+		return nil
+	})
+
+	return nil
+}
+
+// openPublicDB opens the public database in the given directory, creating the directory if needed.
+// No errors are expected during normal operation.
+func (fnb *FlowNodeBuilder) openPublicDB(dir string) (*badger.DB, error) {
 	// Pre-create DB path (Badger creates only one-level dirs)
-	err := os.MkdirAll(fnb.BaseConfig.datadir, 0700)
+	err := os.MkdirAll(dir, 0700)
 	if err != nil {
-		return fmt.Errorf("could not create datadir (path: %s): %w", fnb.BaseConfig.datadir, err)
+		return nil, fmt.Errorf("could not create datadir (path: %s): %w", dir, err)
 	}
 
 	log := sutil.NewLogger(fnb.Logger)
@@ -847,7 +1035,7 @@ func (fnb *FlowNodeBuilder) initDB() error {
 	// tables in-memory as well; this slows down compaction and increases memory
 	// usage, but it improves overall performance and disk i/o
 	opts := badger.
-		DefaultOptions(fnb.BaseConfig.datadir).
+		DefaultOptions(dir).
 		WithKeepL0InMemory(true).
 		WithLogger(log).
 
@@ -862,17 +1050,66 @@ func (fnb *FlowNodeBuilder) initDB() error {
 
 	publicDB, err := bstorage.InitPublic(opts)
 	if err != nil {
-		return fmt.Errorf("could not open public db: %w", err)
+		return nil, fmt.Errorf("could not open public db: %w", err)
 	}
-	fnb.DB = publicDB
+	return publicDB, nil
+}
 
-	fnb.ShutdownFunc(func() error {
-		if err := fnb.DB.Close(); err != nil {
-			return fmt.Errorf("error closing protocol database: %w", err)
+// bootstrapCheckpoint bootstraps the protocol state from the given checkpoint into a new public
+// database in the given directory, which is closed afterwards.
+// An error is returned if the checkpoint is not a valid root snapshot for this node.
+func (fnb *FlowNodeBuilder) bootstrapCheckpoint(checkpoint *inmem.Snapshot, dir string) error {
+	err := badgerState.IsValidRootSnapshotQCs(checkpoint)
+	if err != nil {
+		return fmt.Errorf("invalid checkpoint QCs: %w", err)
+	}
+	if fnb.extraRootSnapshotCheck != nil {
+		err = fnb.extraRootSnapshotCheck(checkpoint)
+		if err != nil {
+			return fmt.Errorf("failed to perform extra checks on checkpoint: %w", err)
 		}
-		return nil
-	})
+	}
+
+	db, err := fnb.openPublicDB(dir)
+	if err != nil {
+		return err
+	}
 
+	// node metrics are not initialized yet, and not needed for bootstrapping
+	collector := metrics.NewNoopCollector()
+	headers := bstorage.NewHeaders(collector, db)
+	guarantees := bstorage.NewGuarantees(collector, db, fnb.BaseConfig.guaranteesCacheSize)
+	seals := bstorage.NewSeals(collector, db)
+	results := bstorage.NewExecutionResults(collector, db)
+	receipts := bstorage.NewExecutionReceipts(collector, db, results, fnb.BaseConfig.receiptsCacheSize)
+	index := bstorage.NewIndex(collector, db)
+	payloads := bstorage.NewPayloads(db, index, guarantees, seals, receipts, results)
+
+	var options []badgerState.BootstrapConfigOptions
+	if fnb.SkipNwAddressBasedValidations {
+		options = append(options, badgerState.SkipNetworkAddressValidation)
+	}
+	_, err = badgerState.Bootstrap(
+		collector,
+		db,
+		headers,
+		seals,
+		results,
+		bstorage.NewBlocks(db, headers, payloads),
+		bstorage.NewQuorumCertificates(collector, db, bstorage.DefaultCacheSize),
+		bstorage.NewEpochSetups(collector, db),
+		bstorage.NewEpochCommits(collector, db),
+		bstorage.NewEpochStatuses(collector, db),
+		checkpoint,
+		options...,
+	)
+	closeErr := db.Close()
+	if err != nil {
+		return fmt.Errorf("could not bootstrap protocol state: %w", err)
+	}
+	if closeErr != nil {
+		return fmt.Errorf("could not close bootstrapped db: %w", closeErr)
+	}
 	return nil
 }
 
@@ -959,6 +1196,7 @@ func (fnb *FlowNodeBuilder) initStorage() error {
 	epochCommits := bstorage.NewEpochCommits(fnb.Metrics.Cache, fnb.DB)
 	statuses := bstorage.NewEpochStatuses(fnb.Metrics.Cache, fnb.DB)
 	commits := bstorage.NewCommits(fnb.Metrics.Cache, fnb.DB)
+	payloadPruner := bstorage.NewPayloadPruner(fnb.DB, index, guarantees, receipts, transactions)
 
 	fnb.Storage = Storage{
 		Headers:            headers,
@@ -976,6 +1214,7 @@ func (fnb *FlowNodeBuilder) initStorage() error {
 		EpochCommits:       epochCommits,
 		Statuses:           statuses,
 		Commits:            commits,
+		PayloadPruner:      payloadPruner,
 	}
 
 	return nil
@@ -1008,6 +1247,8 @@ func (fnb *FlowNodeBuilder) InitIDProviders() {
 			return fmt.Errorf("could not initialize NodeBlockListWrapper: %w", err)
 		}
 		node.IdentityProvider = disallowListWrapper
+		node.NodeDisallowLister = disallowListWrapper
+		node.TimedDisallowLister = disallowListWrapper
 
 		// register the disallow list wrapper for dynamic configuration via admin command
 		err = node.ConfigManager.RegisterIdentifierListConfig("network-id-provider-blocklist",
@@ -1698,6 +1939,10 @@ func (fnb *FlowNodeBuilder) Initialize() error {
 
 	fnb.EnqueueTracer()
 
+	if fnb.PayloadPruningRetention > 0 {
+		fnb.EnqueuePayloadPruner()
+	}
+
 	return nil
 }
 
@@ -1720,6 +1965,18 @@ func (fnb *FlowNodeBuilder) RegisterDefaultAdminCommands() {
 		return storageCommands.NewReadSealsCommand(config.State, config.Storage.Seals, config.Storage.Index)
 	}).AdminCommand("get-latest-identity", func(config *NodeConfig) commands.AdminCommand {
 		return common.NewGetIdentityCommand(config.IdentityProvider)
+	}).AdminCommand("get-misbehavior-penalties", func(config *NodeConfig) commands.AdminCommand {
+		return common.NewGetMisbehaviorPenaltiesCommand(config.MisbehaviorReportManager)
+	}).AdminCommand("get-mesh-peers", func(config *NodeConfig) commands.AdminCommand {
+		return common.NewGetMeshPeersCommand(config.LibP2PNode, config.IdentityProvider)
+	}).AdminCommand("get-peer-scores", func(config *NodeConfig) commands.AdminCommand {
+		return common.NewGetPeerScoresCommand(config.LibP2PNode, config.IdentityProvider)
+	}).AdminCommand("get-peer-connections", func(config *NodeConfig) commands.AdminCommand {
+		return common.NewGetPeerConnectionsCommand(config.LibP2PNode, config.IdentityProvider)
+	}).AdminCommand("disconnect-peer", func(config *NodeConfig) commands.AdminCommand {
+		return common.NewDisconnectPeerCommand(config.IdentityProvider, config.TimedDisallowLister)
+	}).AdminCommand("create-backup", func(config *NodeConfig) commands.AdminCommand {
+		return storageCommands.NewCreateBackupCommand(config.DB, config.BackupCheckpointDir)
 	})
 }
 
@@ -1847,9 +2104,14 @@ func (fnb *FlowNodeBuilder) extraFlagsValidation() error {
 // loadRootProtocolSnapshot loads the root protocol snapshot from disk
 func loadRootProtocolSnapshot(dir string) (*inmem.Snapshot, error) {
 	path := filepath.Join(dir, bootstrap.PathRootProtocolStateSnapshot)
+	return loadSnapshot(path)
+}
+
+// loadSnapshot loads a JSON-encoded protocol state snapshot from disk
+func loadSnapshot(path string) (*inmem.Snapshot, error) {
 	data, err := io.ReadFile(path)
 	if err != nil {
-		return nil, fmt.Errorf("could not read root snapshot (path=%s): %w", path, err)
+		return nil, fmt.Errorf("could not read snapshot (path=%s): %w", path, err)
 	}
 
 	var snapshot inmem.EncodableSnapshot
@@ -0,0 +1,65 @@
+package backup_database
+
+import (
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+
+	"github.com/onflow/flow-go/cmd/util/cmd/common"
+	"github.com/onflow/flow-go/storage/badger/backup"
+)
+
+var (
+	flagDatadir string
+	flagTrieDir string
+	flagOutput  string
+)
+
+// Cmd creates a backup of the protocol database of a stopped node. The backup of a running
+// node can be created with the "create-backup" admin command.
+var Cmd = &cobra.Command{
+	Use:   "backup-database",
+	Short: "Creates a consistent backup of the protocol state database, and of the latest checkpoint and the WAL following it for execution nodes",
+	Run:   run,
+}
+
+func init() {
+	Cmd.Flags().StringVar(&flagDatadir, "datadir", "",
+		"directory that stores the protocol state")
+	_ = Cmd.MarkFlagRequired("datadir")
+
+	Cmd.Flags().StringVar(&flagTrieDir, "triedir", "",
+		"directory that stores the execution state checkpoints, only set for execution nodes")
+
+	Cmd.Flags().StringVar(&flagOutput, "output-dir", "",
+		"directory to write the backup to, must not exist yet")
+	_ = Cmd.MarkFlagRequired("output-dir")
+}
+
+func run(*cobra.Command, []string) {
+	db := common.InitStorage(flagDatadir)
+	defer db.Close()
+
+	log.Info().Str("output_dir", flagOutput).Msg("creating backup")
+
+	manifest, err := backup.Create(db, flagOutput, flagTrieDir)
+	if err != nil {
+		log.Fatal().Err(err).Msg("could not create backup")
+	}
+
+	log.Info().
+		Uint64("finalized_height", manifest.FinalizedHeight).
+		Hex("finalized_block_id", manifest.FinalizedBlockID[:]).
+		Uint64("sealed_height", manifest.SealedHeight).
+		Hex("sealed_block_id", manifest.SealedBlockID[:]).
+		Msg("backup created")
+
+	if manifest.Checkpoint != nil {
+		log.Info().
+			Uint64("executed_height", manifest.ExecutedHeight).
+			Hex("executed_block_id", manifest.ExecutedBlockID[:]).
+			Str("checkpoint", manifest.Checkpoint.Name).
+			Uint64("checkpoint_height", manifest.Checkpoint.Height).
+			Int("wal_segments", len(manifest.Checkpoint.WAL)).
+			Msg("execution state backed up")
+	}
+}
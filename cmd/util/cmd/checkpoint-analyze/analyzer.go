@@ -0,0 +1,265 @@
+package checkpoint_analyze
+
+import (
+	"container/heap"
+	"encoding/hex"
+	"fmt"
+	"sort"
+
+	checkpoint_collect_stats "github.com/onflow/flow-go/cmd/util/cmd/checkpoint-collect-stats"
+	"github.com/onflow/flow-go/ledger"
+	"github.com/onflow/flow-go/ledger/complete/mtrie/node"
+	"github.com/onflow/flow-go/ledger/complete/mtrie/trie"
+	"github.com/onflow/flow-go/model/flow"
+)
+
+// Report holds the analysis of a single trie (ledger state) stored in a checkpoint.
+type Report struct {
+	RootHash       string              `json:"root_hash"`
+	RegisterCount  uint64              `json:"register_count"`
+	PayloadSize    uint64              `json:"payload_size"`
+	ValueSize      uint64              `json:"value_size"`
+	AccountCount   uint64              `json:"account_count"`
+	StatsByTypes   []RegisterTypeStats `json:"stats_by_types"`
+	TopAccounts    []AccountStats      `json:"top_accounts"`
+	TopRegisters   []RegisterStats     `json:"top_registers"`
+	DepthHistogram []DepthBucket       `json:"depth_histogram"`
+
+	// accounts holds the stats of every account, keyed by the hex encoded owner.
+	// It is only written to CSV, as it is too large to be useful in the JSON report.
+	accounts map[string]*AccountStats
+}
+
+// RegisterTypeStats holds the aggregated stats of all registers of the same type
+// (see checkpoint_collect_stats.GetRegisterType).
+type RegisterTypeStats struct {
+	Type          string `json:"type"`
+	RegisterCount uint64 `json:"register_count"`
+	PayloadSize   uint64 `json:"payload_size"`
+	ValueSize     uint64 `json:"value_size"`
+}
+
+// AccountStats holds the aggregated stats of all registers owned by the same account.
+// Registers with an empty owner (global registers) are accounted under the empty owner.
+type AccountStats struct {
+	Owner         string `json:"owner"`
+	RegisterCount uint64 `json:"register_count"`
+	PayloadSize   uint64 `json:"payload_size"`
+	ValueSize     uint64 `json:"value_size"`
+}
+
+// RegisterStats holds the stats of a single register.
+type RegisterStats struct {
+	RegisterID  string `json:"register_id"`
+	Type        string `json:"type"`
+	PayloadSize uint64 `json:"payload_size"`
+	ValueSize   uint64 `json:"value_size"`
+	Depth       int    `json:"depth"`
+}
+
+// DepthBucket holds the number of leaves found at a given depth of the trie.
+type DepthBucket struct {
+	Depth     int    `json:"depth"`
+	LeafCount uint64 `json:"leaf_count"`
+}
+
+// Analyzer aggregates stats over the payloads of a trie.
+// Not concurrency safe.
+type Analyzer struct {
+	topN         int
+	report       *Report
+	types        map[string]*RegisterTypeStats
+	depths       [ledger.NodeMaxHeight + 1]uint64
+	topRegisters registerHeap
+}
+
+// NewAnalyzer creates an analyzer which keeps track of the topN largest accounts and registers.
+func NewAnalyzer(topN int) *Analyzer {
+	return &Analyzer{
+		topN: topN,
+		report: &Report{
+			accounts: make(map[string]*AccountStats),
+		},
+		types: make(map[string]*RegisterTypeStats),
+	}
+}
+
+// AnalyzeTrie walks all leaves of the given trie and returns the resulting report.
+func AnalyzeTrie(t *trie.MTrie, topN int) (*Report, error) {
+	analyzer := NewAnalyzer(topN)
+	err := analyzer.addSubtrie(t.RootNode())
+	if err != nil {
+		return nil, fmt.Errorf("could not analyze trie %v: %w", t.RootHash(), err)
+	}
+	return analyzer.Report(t.RootHash()), nil
+}
+
+func (a *Analyzer) addSubtrie(n *node.Node) error {
+	if n == nil {
+		return nil
+	}
+	if n.IsLeaf() {
+		payload := n.Payload()
+		if payload == nil || payload.IsEmpty() {
+			return nil
+		}
+		return a.AddPayload(payload, ledger.NodeMaxHeight-n.Height())
+	}
+	err := a.addSubtrie(n.LeftChild())
+	if err != nil {
+		return err
+	}
+	return a.addSubtrie(n.RightChild())
+}
+
+// AddPayload accounts the given payload, which is stored in a leaf at the given depth of the trie.
+func (a *Analyzer) AddPayload(p *ledger.Payload, depth int) error {
+	if depth < 0 || depth > ledger.NodeMaxHeight {
+		return fmt.Errorf("invalid leaf depth %d", depth)
+	}
+
+	key, err := p.Key()
+	if err != nil {
+		return fmt.Errorf("could not decode payload key: %w", err)
+	}
+	if len(key.KeyParts) < 2 {
+		return fmt.Errorf("unexpected number of key parts: %d", len(key.KeyParts))
+	}
+
+	payloadSize := uint64(p.Size())
+	valueSize := uint64(p.Value().Size())
+	registerType := checkpoint_collect_stats.GetRegisterType(key)
+	owner := key.KeyParts[0].Value
+
+	a.report.RegisterCount++
+	a.report.PayloadSize += payloadSize
+	a.report.ValueSize += valueSize
+
+	typeStats, ok := a.types[registerType]
+	if !ok {
+		typeStats = &RegisterTypeStats{Type: registerType}
+		a.types[registerType] = typeStats
+	}
+	typeStats.RegisterCount++
+	typeStats.PayloadSize += payloadSize
+	typeStats.ValueSize += valueSize
+
+	ownerHex := hex.EncodeToString(owner)
+	account, ok := a.report.accounts[ownerHex]
+	if !ok {
+		account = &AccountStats{Owner: ownerHex}
+		a.report.accounts[ownerHex] = account
+	}
+	account.RegisterCount++
+	account.PayloadSize += payloadSize
+	account.ValueSize += valueSize
+
+	a.depths[depth]++
+
+	if a.topN > 0 {
+		register := RegisterStats{
+			RegisterID:  flow.NewRegisterID(string(owner), string(key.KeyParts[1].Value)).String(),
+			Type:        registerType,
+			PayloadSize: payloadSize,
+			ValueSize:   valueSize,
+			Depth:       depth,
+		}
+		if a.topRegisters.Len() < a.topN {
+			heap.Push(&a.topRegisters, register)
+		} else if a.topRegisters[0].PayloadSize < payloadSize {
+			a.topRegisters[0] = register
+			heap.Fix(&a.topRegisters, 0)
+		}
+	}
+
+	return nil
+}
+
+// Report finalizes the aggregated stats into a report for the trie with the given root hash.
+func (a *Analyzer) Report(rootHash ledger.RootHash) *Report {
+	report := a.report
+	report.RootHash = rootHash.String()
+	report.AccountCount = uint64(len(report.accounts))
+
+	report.StatsByTypes = make([]RegisterTypeStats, 0, len(a.types))
+	for _, typeStats := range a.types {
+		report.StatsByTypes = append(report.StatsByTypes, *typeStats)
+	}
+	sort.Slice(report.StatsByTypes, func(i, j int) bool {
+		return report.StatsByTypes[i].PayloadSize > report.StatsByTypes[j].PayloadSize
+	})
+
+	report.TopAccounts = topAccounts(report.accounts, a.topN, func(account *AccountStats) int64 {
+		return int64(account.PayloadSize)
+	})
+
+	report.TopRegisters = make([]RegisterStats, len(a.topRegisters))
+	copy(report.TopRegisters, a.topRegisters)
+	sort.Slice(report.TopRegisters, func(i, j int) bool {
+		return report.TopRegisters[i].PayloadSize > report.TopRegisters[j].PayloadSize
+	})
+
+	report.DepthHistogram = make([]DepthBucket, 0)
+	for depth, count := range a.depths {
+		if count > 0 {
+			report.DepthHistogram = append(report.DepthHistogram, DepthBucket{Depth: depth, LeafCount: count})
+		}
+	}
+
+	return report
+}
+
+// Accounts returns the stats of all accounts, sorted by owner.
+func (r *Report) Accounts() []AccountStats {
+	accounts := make([]AccountStats, 0, len(r.accounts))
+	for _, account := range r.accounts {
+		accounts = append(accounts, *account)
+	}
+	sort.Slice(accounts, func(i, j int) bool {
+		return accounts[i].Owner < accounts[j].Owner
+	})
+	return accounts
+}
+
+// topAccounts returns the n accounts with the largest metric, in decreasing order.
+func topAccounts(accounts map[string]*AccountStats, n int, metric func(*AccountStats) int64) []AccountStats {
+	all := make([]*AccountStats, 0, len(accounts))
+	for _, account := range accounts {
+		all = append(all, account)
+	}
+	sort.Slice(all, func(i, j int) bool {
+		mi, mj := metric(all[i]), metric(all[j])
+		if mi != mj {
+			return mi > mj
+		}
+		return all[i].Owner < all[j].Owner
+	})
+	if len(all) > n {
+		all = all[:n]
+	}
+
+	top := make([]AccountStats, len(all))
+	for i, account := range all {
+		top[i] = *account
+	}
+	return top
+}
+
+// registerHeap is a min-heap of registers ordered by payload size, used to keep track of the largest registers.
+type registerHeap []RegisterStats
+
+func (h registerHeap) Len() int           { return len(h) }
+func (h registerHeap) Less(i, j int) bool { return h[i].PayloadSize < h[j].PayloadSize }
+func (h registerHeap) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+
+func (h *registerHeap) Push(x interface{}) {
+	*h = append(*h, x.(RegisterStats))
+}
+
+func (h *registerHeap) Pop() interface{} {
+	old := *h
+	n := len(old)
+	x := old[n-1]
+	*h = old[:n-1]
+	return x
+}
@@ -0,0 +1,150 @@
+package checkpoint_analyze
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/ledger"
+	"github.com/onflow/flow-go/ledger/common/pathfinder"
+	"github.com/onflow/flow-go/ledger/complete"
+	"github.com/onflow/flow-go/ledger/complete/mtrie/trie"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+func payload(owner string, key string, value string) ledger.Payload {
+	k := ledger.NewKey([]ledger.KeyPart{
+		ledger.NewKeyPart(0, []byte(owner)),
+		ledger.NewKeyPart(2, []byte(key)),
+	})
+	return *ledger.NewPayload(k, ledger.Value(value))
+}
+
+func trieWithPayloads(t *testing.T, parent *trie.MTrie, payloads ...ledger.Payload) *trie.MTrie {
+	paths := make([]ledger.Path, len(payloads))
+	for i, p := range payloads {
+		key, err := p.Key()
+		require.NoError(t, err)
+		paths[i], err = pathfinder.KeyToPath(key, complete.DefaultPathFinderVersion)
+		require.NoError(t, err)
+	}
+	updated, _, err := trie.NewTrieWithUpdatedRegisters(parent, paths, payloads, true)
+	require.NoError(t, err)
+	return updated
+}
+
+func TestAnalyzeTrie(t *testing.T) {
+	alice := payload("alice", "storage", "aaaaaaaaaa")
+	aliceCode := payload("alice", "code.Hello", "access(all) contract Hello {}")
+	bob := payload("bob", "public_key_0", "bb")
+
+	tr := trieWithPayloads(t, trie.NewEmptyMTrie(), alice, aliceCode, bob)
+
+	report, err := AnalyzeTrie(tr, 1)
+	require.NoError(t, err)
+
+	assert.Equal(t, tr.RootHash().String(), report.RootHash)
+	assert.Equal(t, uint64(3), report.RegisterCount)
+	assert.Equal(t, uint64(2), report.AccountCount)
+	assert.Equal(t, uint64(alice.Size()+aliceCode.Size()+bob.Size()), report.PayloadSize)
+	assert.Equal(t, uint64(10+len("access(all) contract Hello {}")+2), report.ValueSize)
+
+	// one register of each type
+	require.Len(t, report.StatsByTypes, 3)
+	types := make(map[string]RegisterTypeStats)
+	for _, stats := range report.StatsByTypes {
+		types[stats.Type] = stats
+	}
+	assert.Equal(t, uint64(1), types["account's cadence storage domain map"].RegisterCount)
+	assert.Equal(t, uint64(1), types["contract content"].RegisterCount)
+	assert.Equal(t, uint64(bob.Size()), types["public key"].PayloadSize)
+
+	// top-1 account and register
+	require.Len(t, report.TopAccounts, 1)
+	assert.Equal(t, "616c696365", report.TopAccounts[0].Owner)
+	assert.Equal(t, uint64(2), report.TopAccounts[0].RegisterCount)
+	require.Len(t, report.TopRegisters, 1)
+	assert.Equal(t, "contract content", report.TopRegisters[0].Type)
+
+	// all leaves are accounted for in the depth histogram
+	leaves := uint64(0)
+	for _, bucket := range report.DepthHistogram {
+		assert.Greater(t, bucket.Depth, 0)
+		leaves += bucket.LeafCount
+	}
+	assert.Equal(t, uint64(3), leaves)
+
+	// all accounts are reported, sorted by owner
+	accounts := report.Accounts()
+	require.Len(t, accounts, 2)
+	assert.Equal(t, "616c696365", accounts[0].Owner)
+	assert.Equal(t, "626f62", accounts[1].Owner)
+}
+
+func TestDiff(t *testing.T) {
+	alice := payload("alice", "storage", "aaaaaaaaaa")
+	bob := payload("bob", "storage", "bb")
+	from := trieWithPayloads(t, trie.NewEmptyMTrie(), alice, bob)
+
+	// alice grows, bob is removed and carol is created
+	aliceGrown := payload("alice", "storage", strings.Repeat("a", 110))
+	bobRemoved := payload("bob", "storage", "")
+	carol := payload("carol", "storage", "c")
+	to := trieWithPayloads(t, from, aliceGrown, bobRemoved, carol)
+
+	fromReport, err := AnalyzeTrie(from, 10)
+	require.NoError(t, err)
+	toReport, err := AnalyzeTrie(to, 10)
+	require.NoError(t, err)
+
+	growth := Diff(fromReport, toReport, 10)
+	assert.Equal(t, int64(0), growth.RegisterCountDelta)
+	assert.Equal(t, int64(0), growth.AccountCountDelta)
+	assert.Equal(t, int64(100+1-2), growth.ValueSizeDelta)
+	assert.Equal(t, uint64(1), growth.NewAccounts)
+	assert.Equal(t, uint64(1), growth.RemovedAccounts)
+
+	require.Len(t, growth.GrowthByTypes, 1)
+	assert.Equal(t, int64(100+1-2), growth.GrowthByTypes[0].ValueSizeDelta)
+
+	require.Len(t, growth.TopGrowingAccounts, 2)
+	assert.Equal(t, "616c696365", growth.TopGrowingAccounts[0].Owner)
+	assert.Equal(t, int64(100), growth.TopGrowingAccounts[0].ValueSizeDelta)
+}
+
+func TestWriteReport(t *testing.T) {
+	tr := trieWithPayloads(t, trie.NewEmptyMTrie(),
+		payload("alice", "storage", "aaaaaaaaaa"),
+		payload("bob", "public_key_0", "bb"),
+	)
+	report, err := AnalyzeTrie(tr, 10)
+	require.NoError(t, err)
+
+	unittest.RunWithTempDir(t, func(dir string) {
+		require.NoError(t, WriteReport(dir, FormatJSON, report))
+		require.NoError(t, WriteReport(dir, FormatCSV, report))
+		require.Error(t, WriteReport(dir, "xml", report))
+
+		accounts, err := os.ReadFile(filepath.Join(dir, "ledger.accounts.csv"))
+		require.NoError(t, err)
+		assert.Equal(t, "owner,register_count,payload_size,value_size\n"+
+			"616c696365,1,"+u64(report.TopAccounts[0].PayloadSize)+",10\n"+
+			"626f62,1,"+u64(report.TopAccounts[1].PayloadSize)+",2\n", string(accounts))
+
+		_, err = os.Stat(filepath.Join(dir, "ledger.analysis.json"))
+		require.NoError(t, err)
+	})
+}
+
+func TestValidateFlags(t *testing.T) {
+	require.NoError(t, validateFlags(0, []string{FormatJSON, FormatCSV}))
+	require.NoError(t, validateFlags(100, []string{FormatCSV}))
+
+	// a negative top-n would panic when truncating the top accounts
+	require.Error(t, validateFlags(-1, []string{FormatJSON}))
+	require.Error(t, validateFlags(10, []string{"xml"}))
+}
@@ -0,0 +1,124 @@
+package checkpoint_analyze
+
+import (
+	"fmt"
+
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+
+	"github.com/onflow/flow-go/ledger/complete/wal"
+)
+
+var (
+	flagCheckpoint        string
+	flagCompareCheckpoint string
+	flagOutputDir         string
+	flagTopN              int
+	flagFormats           []string
+)
+
+var Cmd = &cobra.Command{
+	Use:   "checkpoint-analyze",
+	Short: "analyzes the ledger state stored in a checkpoint, and its growth since an older checkpoint",
+	Long: `analyzes the latest trie stored in a checkpoint: register counts and sizes per account and per
+register type, the largest accounts and registers, and the depth histogram of the trie leaves.
+If --compare-checkpoint is given, the growth between the latest tries of both checkpoints is reported as well.`,
+	Run: run,
+}
+
+func init() {
+	Cmd.Flags().StringVar(&flagCheckpoint, "checkpoint", "",
+		"checkpoint file to analyze")
+	_ = Cmd.MarkFlagRequired("checkpoint")
+
+	Cmd.Flags().StringVar(&flagCompareCheckpoint, "compare-checkpoint", "",
+		"older checkpoint file to compute the growth from (optional)")
+
+	Cmd.Flags().StringVar(&flagOutputDir, "output-dir", "",
+		"Directory to write the reports to")
+	_ = Cmd.MarkFlagRequired("output-dir")
+
+	Cmd.Flags().IntVar(&flagTopN, "top-n", 100,
+		"number of largest (or fastest growing) accounts and registers to report")
+
+	Cmd.Flags().StringSliceVar(&flagFormats, "format", []string{FormatJSON, FormatCSV},
+		"output formats, comma separated (json, csv)")
+}
+
+func run(*cobra.Command, []string) {
+	err := validateFlags(flagTopN, flagFormats)
+	if err != nil {
+		log.Fatal().Err(err).Msg("invalid flags")
+	}
+
+	report := analyzeCheckpoint(flagCheckpoint)
+
+	for _, format := range flagFormats {
+		err := WriteReport(flagOutputDir, format, report)
+		if err != nil {
+			log.Fatal().Err(err).Str("format", format).Msg("could not write ledger analysis")
+		}
+	}
+
+	log.Info().
+		Str("root_hash", report.RootHash).
+		Uint64("register_count", report.RegisterCount).
+		Uint64("payload_size", report.PayloadSize).
+		Uint64("account_count", report.AccountCount).
+		Msg("ledger analysis written")
+
+	if flagCompareCheckpoint == "" {
+		return
+	}
+
+	previous := analyzeCheckpoint(flagCompareCheckpoint)
+	growth := Diff(previous, report, flagTopN)
+
+	for _, format := range flagFormats {
+		err := WriteGrowthReport(flagOutputDir, format, growth)
+		if err != nil {
+			log.Fatal().Err(err).Str("format", format).Msg("could not write ledger growth")
+		}
+	}
+
+	log.Info().
+		Str("from_root_hash", growth.FromRootHash).
+		Str("to_root_hash", growth.ToRootHash).
+		Int64("register_count_delta", growth.RegisterCountDelta).
+		Int64("payload_size_delta", growth.PayloadSizeDelta).
+		Msg("ledger growth written")
+}
+
+// validateFlags checks the flags, which are not validated by cobra itself.
+func validateFlags(topN int, formats []string) error {
+	if topN < 0 {
+		return fmt.Errorf("--top-n must not be negative, got %d", topN)
+	}
+	for _, format := range formats {
+		if format != FormatJSON && format != FormatCSV {
+			return fmt.Errorf("unsupported --format %q (supported: %s, %s)", format, FormatJSON, FormatCSV)
+		}
+	}
+	return nil
+}
+
+// analyzeCheckpoint loads the given checkpoint file and analyzes its latest trie.
+func analyzeCheckpoint(checkpoint string) *Report {
+	log.Info().Msgf("loading checkpoint %v", checkpoint)
+	tries, err := wal.LoadCheckpoint(checkpoint, &log.Logger)
+	if err != nil {
+		log.Fatal().Err(err).Msg("error while loading checkpoint")
+	}
+	if len(tries) == 0 {
+		log.Fatal().Msgf("checkpoint %v contains no tries", checkpoint)
+	}
+	log.Info().Msgf("checkpoint loaded, total tries: %v", len(tries))
+
+	// the latest state is the last trie of the checkpoint
+	latest := tries[len(tries)-1]
+	report, err := AnalyzeTrie(latest, flagTopN)
+	if err != nil {
+		log.Fatal().Err(err).Msg("could not analyze trie")
+	}
+	return report
+}
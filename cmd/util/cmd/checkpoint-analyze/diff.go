@@ -0,0 +1,115 @@
+package checkpoint_analyze
+
+import (
+	"sort"
+)
+
+// GrowthReport holds the growth of the ledger state between two analyzed tries.
+// All deltas are computed as (to - from), so they are negative if the state shrank.
+type GrowthReport struct {
+	FromRootHash       string              `json:"from_root_hash"`
+	ToRootHash         string              `json:"to_root_hash"`
+	RegisterCountDelta int64               `json:"register_count_delta"`
+	PayloadSizeDelta   int64               `json:"payload_size_delta"`
+	ValueSizeDelta     int64               `json:"value_size_delta"`
+	AccountCountDelta  int64               `json:"account_count_delta"`
+	NewAccounts        uint64              `json:"new_accounts"`
+	RemovedAccounts    uint64              `json:"removed_accounts"`
+	GrowthByTypes      []RegisterTypeDelta `json:"growth_by_types"`
+	TopGrowingAccounts []AccountDelta      `json:"top_growing_accounts"`
+}
+
+// RegisterTypeDelta holds the growth of all registers of the same type.
+type RegisterTypeDelta struct {
+	Type               string `json:"type"`
+	RegisterCountDelta int64  `json:"register_count_delta"`
+	PayloadSizeDelta   int64  `json:"payload_size_delta"`
+	ValueSizeDelta     int64  `json:"value_size_delta"`
+}
+
+// AccountDelta holds the growth of a single account.
+type AccountDelta struct {
+	Owner              string `json:"owner"`
+	RegisterCountDelta int64  `json:"register_count_delta"`
+	PayloadSizeDelta   int64  `json:"payload_size_delta"`
+	ValueSizeDelta     int64  `json:"value_size_delta"`
+}
+
+// Diff computes the growth between the two given reports, keeping track of the
+// topN accounts which grew the most (by payload size).
+func Diff(from *Report, to *Report, topN int) *GrowthReport {
+	growth := &GrowthReport{
+		FromRootHash:       from.RootHash,
+		ToRootHash:         to.RootHash,
+		RegisterCountDelta: int64(to.RegisterCount) - int64(from.RegisterCount),
+		PayloadSizeDelta:   int64(to.PayloadSize) - int64(from.PayloadSize),
+		ValueSizeDelta:     int64(to.ValueSize) - int64(from.ValueSize),
+		AccountCountDelta:  int64(to.AccountCount) - int64(from.AccountCount),
+	}
+
+	types := make(map[string]*RegisterTypeDelta)
+	typeDelta := func(registerType string) *RegisterTypeDelta {
+		delta, ok := types[registerType]
+		if !ok {
+			delta = &RegisterTypeDelta{Type: registerType}
+			types[registerType] = delta
+		}
+		return delta
+	}
+	for _, stats := range to.StatsByTypes {
+		delta := typeDelta(stats.Type)
+		delta.RegisterCountDelta += int64(stats.RegisterCount)
+		delta.PayloadSizeDelta += int64(stats.PayloadSize)
+		delta.ValueSizeDelta += int64(stats.ValueSize)
+	}
+	for _, stats := range from.StatsByTypes {
+		delta := typeDelta(stats.Type)
+		delta.RegisterCountDelta -= int64(stats.RegisterCount)
+		delta.PayloadSizeDelta -= int64(stats.PayloadSize)
+		delta.ValueSizeDelta -= int64(stats.ValueSize)
+	}
+	growth.GrowthByTypes = make([]RegisterTypeDelta, 0, len(types))
+	for _, delta := range types {
+		growth.GrowthByTypes = append(growth.GrowthByTypes, *delta)
+	}
+	sort.Slice(growth.GrowthByTypes, func(i, j int) bool {
+		return growth.GrowthByTypes[i].PayloadSizeDelta > growth.GrowthByTypes[j].PayloadSizeDelta
+	})
+
+	accounts := make([]AccountDelta, 0, len(to.accounts))
+	for owner, toAccount := range to.accounts {
+		delta := AccountDelta{
+			Owner:              owner,
+			RegisterCountDelta: int64(toAccount.RegisterCount),
+			PayloadSizeDelta:   int64(toAccount.PayloadSize),
+			ValueSizeDelta:     int64(toAccount.ValueSize),
+		}
+		fromAccount, ok := from.accounts[owner]
+		if ok {
+			delta.RegisterCountDelta -= int64(fromAccount.RegisterCount)
+			delta.PayloadSizeDelta -= int64(fromAccount.PayloadSize)
+			delta.ValueSizeDelta -= int64(fromAccount.ValueSize)
+		} else {
+			growth.NewAccounts++
+		}
+		accounts = append(accounts, delta)
+	}
+	for owner := range from.accounts {
+		if _, ok := to.accounts[owner]; !ok {
+			growth.RemovedAccounts++
+		}
+	}
+
+	sort.Slice(accounts, func(i, j int) bool {
+		if accounts[i].PayloadSizeDelta != accounts[j].PayloadSizeDelta {
+			return accounts[i].PayloadSizeDelta > accounts[j].PayloadSizeDelta
+		}
+		return accounts[i].Owner < accounts[j].Owner
+	})
+	if len(accounts) > topN {
+		accounts = accounts[:topN]
+	}
+	growth.TopGrowingAccounts = accounts
+
+	return growth
+}
@@ -0,0 +1,184 @@
+package checkpoint_analyze
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+)
+
+const (
+	FormatJSON = "json"
+	FormatCSV  = "csv"
+)
+
+// writeJSON encodes the given value as JSON into the given file of the output directory.
+func writeJSON(dir string, fileName string, v interface{}) error {
+	path := filepath.Join(dir, fileName)
+	fi, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("could not create %s: %w", path, err)
+	}
+	defer fi.Close()
+
+	writer := bufio.NewWriter(fi)
+
+	encoder := json.NewEncoder(writer)
+	encoder.SetIndent("", "  ")
+	err = encoder.Encode(v)
+	if err != nil {
+		return fmt.Errorf("could not json encode %s: %w", path, err)
+	}
+
+	return writer.Flush()
+}
+
+// writeCSV writes the given header and rows into the given file of the output directory.
+func writeCSV(dir string, fileName string, header []string, rows [][]string) error {
+	path := filepath.Join(dir, fileName)
+	fi, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("could not create %s: %w", path, err)
+	}
+	defer fi.Close()
+
+	writer := csv.NewWriter(fi)
+	err = writer.Write(header)
+	if err != nil {
+		return fmt.Errorf("could not write header of %s: %w", path, err)
+	}
+	// WriteAll flushes the underlying buffer once all rows are written
+	err = writer.WriteAll(rows)
+	if err != nil {
+		return fmt.Errorf("could not write rows of %s: %w", path, err)
+	}
+
+	return nil
+}
+
+// WriteReport writes the given report into the output directory, in the given format.
+// The CSV format writes one file per table, including the stats of every account.
+func WriteReport(dir string, format string, report *Report) error {
+	switch format {
+	case FormatJSON:
+		return writeJSON(dir, "ledger.analysis.json", report)
+	case FormatCSV:
+		return writeReportCSV(dir, report)
+	default:
+		return fmt.Errorf("unsupported output format: %s", format)
+	}
+}
+
+// WriteGrowthReport writes the given growth report into the output directory, in the given format.
+func WriteGrowthReport(dir string, format string, growth *GrowthReport) error {
+	switch format {
+	case FormatJSON:
+		return writeJSON(dir, "ledger.growth.json", growth)
+	case FormatCSV:
+		return writeGrowthReportCSV(dir, growth)
+	default:
+		return fmt.Errorf("unsupported output format: %s", format)
+	}
+}
+
+func writeReportCSV(dir string, report *Report) error {
+	err := writeCSV(dir, "ledger.summary.csv",
+		[]string{"root_hash", "register_count", "payload_size", "value_size", "account_count"},
+		[][]string{{
+			report.RootHash,
+			u64(report.RegisterCount),
+			u64(report.PayloadSize),
+			u64(report.ValueSize),
+			u64(report.AccountCount),
+		}})
+	if err != nil {
+		return err
+	}
+
+	rows := make([][]string, 0, len(report.StatsByTypes))
+	for _, stats := range report.StatsByTypes {
+		rows = append(rows, []string{stats.Type, u64(stats.RegisterCount), u64(stats.PayloadSize), u64(stats.ValueSize)})
+	}
+	err = writeCSV(dir, "ledger.types.csv", []string{"type", "register_count", "payload_size", "value_size"}, rows)
+	if err != nil {
+		return err
+	}
+
+	accounts := report.Accounts()
+	rows = make([][]string, 0, len(accounts))
+	for _, account := range accounts {
+		rows = append(rows, []string{account.Owner, u64(account.RegisterCount), u64(account.PayloadSize), u64(account.ValueSize)})
+	}
+	err = writeCSV(dir, "ledger.accounts.csv", []string{"owner", "register_count", "payload_size", "value_size"}, rows)
+	if err != nil {
+		return err
+	}
+
+	rows = make([][]string, 0, len(report.TopAccounts))
+	for _, account := range report.TopAccounts {
+		rows = append(rows, []string{account.Owner, u64(account.RegisterCount), u64(account.PayloadSize), u64(account.ValueSize)})
+	}
+	err = writeCSV(dir, "ledger.top_accounts.csv", []string{"owner", "register_count", "payload_size", "value_size"}, rows)
+	if err != nil {
+		return err
+	}
+
+	rows = make([][]string, 0, len(report.TopRegisters))
+	for _, register := range report.TopRegisters {
+		rows = append(rows, []string{register.RegisterID, register.Type, u64(register.PayloadSize), u64(register.ValueSize), strconv.Itoa(register.Depth)})
+	}
+	err = writeCSV(dir, "ledger.top_registers.csv", []string{"register_id", "type", "payload_size", "value_size", "depth"}, rows)
+	if err != nil {
+		return err
+	}
+
+	rows = make([][]string, 0, len(report.DepthHistogram))
+	for _, bucket := range report.DepthHistogram {
+		rows = append(rows, []string{strconv.Itoa(bucket.Depth), u64(bucket.LeafCount)})
+	}
+	return writeCSV(dir, "ledger.depths.csv", []string{"depth", "leaf_count"}, rows)
+}
+
+func writeGrowthReportCSV(dir string, growth *GrowthReport) error {
+	err := writeCSV(dir, "ledger.growth.summary.csv",
+		[]string{"from_root_hash", "to_root_hash", "register_count_delta", "payload_size_delta", "value_size_delta", "account_count_delta", "new_accounts", "removed_accounts"},
+		[][]string{{
+			growth.FromRootHash,
+			growth.ToRootHash,
+			i64(growth.RegisterCountDelta),
+			i64(growth.PayloadSizeDelta),
+			i64(growth.ValueSizeDelta),
+			i64(growth.AccountCountDelta),
+			u64(growth.NewAccounts),
+			u64(growth.RemovedAccounts),
+		}})
+	if err != nil {
+		return err
+	}
+
+	rows := make([][]string, 0, len(growth.GrowthByTypes))
+	for _, delta := range growth.GrowthByTypes {
+		rows = append(rows, []string{delta.Type, i64(delta.RegisterCountDelta), i64(delta.PayloadSizeDelta), i64(delta.ValueSizeDelta)})
+	}
+	err = writeCSV(dir, "ledger.growth.types.csv", []string{"type", "register_count_delta", "payload_size_delta", "value_size_delta"}, rows)
+	if err != nil {
+		return err
+	}
+
+	rows = make([][]string, 0, len(growth.TopGrowingAccounts))
+	for _, delta := range growth.TopGrowingAccounts {
+		rows = append(rows, []string{delta.Owner, i64(delta.RegisterCountDelta), i64(delta.PayloadSizeDelta), i64(delta.ValueSizeDelta)})
+	}
+	return writeCSV(dir, "ledger.growth.top_accounts.csv", []string{"owner", "register_count_delta", "payload_size_delta", "value_size_delta"}, rows)
+}
+
+func u64(v uint64) string {
+	return strconv.FormatUint(v, 10)
+}
+
+func i64(v int64) string {
+	return strconv.FormatInt(v, 10)
+}
@@ -123,7 +123,7 @@ func run(*cobra.Command, []string) {
 		valueSize = value.Size()
 		totalPayloadSize += uint64(size)
 		totalPayloadValueSize += uint64(valueSize)
-		valueSizesByType[getType(key)] = append(valueSizesByType[getType(key)], float64(valueSize))
+		valueSizesByType[GetRegisterType(key)] = append(valueSizesByType[GetRegisterType(key)], float64(valueSize))
 	})
 
 	statsByTypes := make([]RegisterStatsByTypes, 0)
@@ -210,7 +210,8 @@ func run(*cobra.Command, []string) {
 	}
 }
 
-func getType(key ledger.Key) string {
+// GetRegisterType returns a human-readable classification of the register identified by the given key.
+func GetRegisterType(key ledger.Key) string {
 	k := key.KeyParts[1].Value
 	kstr := string(k)
 
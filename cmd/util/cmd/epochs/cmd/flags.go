@@ -13,4 +13,14 @@ var (
 	flagFlowTokenAddress     string
 	flagFlowFeesAddress      string
 	flagIDTableAddress       string
+
+	// recovery epoch flags
+	flagSnapshotPath             string
+	flagInternalNodePrivInfoDir  string
+	flagCollectionClusters       int
+	flagRecoveryFirstView        uint64
+	flagRecoveryViewBuffer       uint64
+	flagNumViewsInEpoch          uint64
+	flagNumViewsInStakingAuction uint64
+	flagNumViewsInDKGPhase       uint64
 )
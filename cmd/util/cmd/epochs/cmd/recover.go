@@ -0,0 +1,165 @@
+package cmd
+
+import (
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/onflow/flow-go/cmd/bootstrap/run"
+	"github.com/onflow/flow-go/model/bootstrap"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/state/protocol/inmem"
+	flowIO "github.com/onflow/flow-go/utils/io"
+)
+
+// recoverCmd represents a command to generate the data for a recovery epoch, which is
+// used to exit epoch emergency fallback mode (EECC).
+//
+// When the epoch setup fails (for example, because the DKG failed or the smart contract
+// did not emit the EpochCommit event in time), the network enters EECC and extends the
+// current epoch until a recovery epoch is defined by the governance committee. The recovery
+// epoch is derived from the current protocol state:
+//   - participants are the participants of the current epoch
+//   - collectors are re-assigned to new clusters, for which we generate cluster root QCs
+//     using the private keys of our internal collector nodes
+//   - a new random source is generated
+//   - the DKG of the current epoch is re-used, so no new DKG is required
+//
+// The resulting EpochRecover service event is written to STDOUT as JSON.
+var recoverCmd = &cobra.Command{
+	Use:   "recover-epoch-data",
+	Short: "Generates recovery epoch data to exit epoch emergency fallback mode",
+	Long: "Generates an EpochRecover service event from the current protocol state snapshot and writes it as JSON to STDOUT. " +
+		"For use by the governance committee when the network is in epoch emergency fallback mode.",
+	Run: recoverRun,
+}
+
+func init() {
+	rootCmd.AddCommand(recoverCmd)
+	addRecoverCmdFlags()
+}
+
+func addRecoverCmdFlags() {
+	recoverCmd.Flags().StringVar(&flagSnapshotPath, "snapshot", "", "path to a protocol state snapshot of the current state (defaults to the root snapshot in --boot-dir)")
+	recoverCmd.Flags().StringVar(&flagInternalNodePrivInfoDir, "internal-priv-dir", "", "path to directory containing the private node-info files of internal nodes")
+	recoverCmd.Flags().IntVar(&flagCollectionClusters, "collection-clusters", 0, "number of collection clusters in the recovery epoch (defaults to the number of clusters in the current epoch)")
+	recoverCmd.Flags().Uint64Var(&flagRecoveryFirstView, "first-view", 0, "first view of the recovery epoch (defaults to a view sufficiently far beyond the snapshot's head)")
+	recoverCmd.Flags().Uint64Var(&flagRecoveryViewBuffer, "view-buffer", 2000, "when --first-view is not set, number of views on top of the epoch commit safety threshold "+
+		"between the snapshot's head and the first view of the recovery epoch, allowing for the time to submit the recovery transaction")
+	recoverCmd.Flags().Uint64Var(&flagNumViewsInEpoch, "epoch-length", 0, "length of the recovery epoch measured in views")
+	recoverCmd.Flags().Uint64Var(&flagNumViewsInStakingAuction, "epoch-staking-phase-length", 100, "length of the recovery epoch's staking phase measured in views")
+	recoverCmd.Flags().Uint64Var(&flagNumViewsInDKGPhase, "epoch-dkg-phase-length", 1000, "length of each DKG phase of the recovery epoch measured in views")
+
+	_ = recoverCmd.MarkFlagRequired("internal-priv-dir")
+	_ = recoverCmd.MarkFlagRequired("epoch-length")
+}
+
+// recoverRun generates the EpochRecover service event from the current protocol state snapshot and writes it to STDOUT.
+func recoverRun(cmd *cobra.Command, args []string) {
+
+	stdout := cmd.OutOrStdout()
+
+	path := flagSnapshotPath
+	if path == "" {
+		if flagBootDir == "" {
+			log.Fatal().Msg("must provide a source for the protocol state snapshot (specify either --snapshot or --boot-dir)")
+		}
+		path = filepath.Join(flagBootDir, bootstrap.PathRootProtocolStateSnapshot)
+	}
+	snapshot, err := getSnapshotFromLocalBootstrapDir(path)
+	if err != nil {
+		log.Fatal().Err(err).Str("path", path).Msg("failed to retrieve protocol state snapshot")
+	}
+
+	internalNodes, err := readInternalNodeInfos(flagInternalNodePrivInfoDir, snapshot)
+	if err != nil {
+		log.Fatal().Err(err).Msg("failed to read internal node infos")
+	}
+	log.Info().Msgf("read %d internal node infos", len(internalNodes))
+
+	randomSource := make([]byte, flow.EpochSetupRandomSourceLength)
+	_, err = rand.Read(randomSource)
+	if err != nil {
+		log.Fatal().Err(err).Msg("could not generate random source")
+	}
+
+	config := run.RecoveryEpochConfig{
+		FirstView:             flagRecoveryFirstView,
+		ViewBuffer:            flagRecoveryViewBuffer,
+		NumViewsInEpoch:       flagNumViewsInEpoch,
+		NumViewsInStaking:     flagNumViewsInStakingAuction,
+		NumViewsInDKGPhase:    flagNumViewsInDKGPhase,
+		NumCollectionClusters: flagCollectionClusters,
+	}
+	epochRecover, err := run.GenerateRecoverEpochData(snapshot, internalNodes, randomSource, config)
+	if err != nil {
+		log.Fatal().Err(err).Msg("could not generate recovery epoch data")
+	}
+
+	encoded, err := json.MarshalIndent(epochRecover.ServiceEvent(), "", "  ")
+	if err != nil {
+		log.Fatal().Err(err).Msg("could not encode recovery epoch data")
+	}
+	_, err = stdout.Write(encoded)
+	if err != nil {
+		log.Fatal().Err(err).Msg("could not write recovery epoch data")
+	}
+}
+
+// readInternalNodeInfos reads the private node-info files of our internal nodes from the
+// given directory. The weight of each node is taken from its identity in the snapshot.
+// Nodes which are not participants of the current epoch are skipped.
+func readInternalNodeInfos(dir string, snapshot *inmem.Snapshot) ([]bootstrap.NodeInfo, error) {
+	participants, err := snapshot.Epochs().Current().InitialIdentities()
+	if err != nil {
+		return nil, fmt.Errorf("could not get current epoch participants: %w", err)
+	}
+
+	var files []string
+	err = filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		// skip files that do not include node-infos
+		if !info.IsDir() && strings.Contains(path, bootstrap.PathPrivNodeInfoPrefix) {
+			files = append(files, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not list internal node infos (dir=%s): %w", dir, err)
+	}
+
+	var nodes []bootstrap.NodeInfo
+	for _, file := range files {
+		bz, err := flowIO.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("could not read internal node info (path=%s): %w", file, err)
+		}
+		var info bootstrap.NodeInfoPriv
+		err = json.Unmarshal(bz, &info)
+		if err != nil {
+			return nil, fmt.Errorf("could not decode internal node info (path=%s): %w", file, err)
+		}
+
+		identity, ok := participants.ByNodeID(info.NodeID)
+		if !ok {
+			log.Warn().Hex("node_id", info.NodeID[:]).Msg("skipping internal node which is not a participant of the current epoch")
+			continue
+		}
+		nodes = append(nodes, bootstrap.NewPrivateNodeInfo(
+			info.NodeID,
+			info.Role,
+			info.Address,
+			identity.Weight,
+			info.NetworkPrivKey.PrivateKey,
+			info.StakingPrivKey.PrivateKey,
+		))
+	}
+	return nodes, nil
+}
@@ -0,0 +1,91 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/model/bootstrap"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/model/flow/filter"
+	"github.com/onflow/flow-go/utils/io"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+// TestRecoverEpoch tests that the command generates a valid recovery epoch
+// from the root snapshot and the internal node infos.
+func TestRecoverEpoch(t *testing.T) {
+
+	unittest.RunWithTempDir(t, func(bootDir string) {
+
+		// all nodes are internal nodes
+		internalNodes := unittest.PrivateNodeInfosFixture(10, unittest.WithAllRoles())
+		rootSnapshot := unittest.RootSnapshotFixture(bootstrap.ToIdentityList(internalNodes))
+		err := writeRootSnapshot(bootDir, rootSnapshot)
+		require.NoError(t, err)
+		for _, node := range internalNodes {
+			private, err := node.Private()
+			require.NoError(t, err)
+			err = io.WriteJSON(filepath.Join(bootDir, fmt.Sprintf(bootstrap.PathNodeInfoPriv, node.NodeID)), private)
+			require.NoError(t, err)
+		}
+
+		// set flag values
+		flagBootDir = bootDir
+		flagSnapshotPath = ""
+		flagInternalNodePrivInfoDir = bootDir
+		flagCollectionClusters = 1
+		flagRecoveryFirstView = 0
+		flagRecoveryViewBuffer = 100
+		flagNumViewsInEpoch = 10_000
+		flagNumViewsInStakingAuction = 100
+		flagNumViewsInDKGPhase = 1000
+
+		// run command with overwritten stdout
+		stdout := bytes.NewBuffer(nil)
+		recoverCmd.SetOut(stdout)
+		recoverRun(recoverCmd, nil)
+
+		// read output from stdout
+		var event flow.ServiceEvent
+		err = json.NewDecoder(stdout).Decode(&event)
+		require.NoError(t, err)
+		require.Equal(t, flow.ServiceEventRecover, event.Type)
+		epochRecover, ok := event.Event.(*flow.EpochRecover)
+		require.True(t, ok)
+
+		current := rootSnapshot.Epochs().Current()
+		counter, err := current.Counter()
+		require.NoError(t, err)
+		finalView, err := current.FinalView()
+		require.NoError(t, err)
+		participants, err := current.InitialIdentities()
+		require.NoError(t, err)
+		dkg, err := current.DKG()
+		require.NoError(t, err)
+
+		setup, commit := epochRecover.EpochSetup, epochRecover.EpochCommit
+		assert.Equal(t, counter+1, setup.Counter)
+		assert.Equal(t, counter+1, commit.Counter)
+		assert.Greater(t, setup.FirstView, finalView)
+		assert.Equal(t, setup.FirstView+flagNumViewsInEpoch-1, setup.FinalView)
+		assert.Equal(t, participants, setup.Participants)
+		assert.Len(t, setup.RandomSource, flow.EpochSetupRandomSourceLength)
+
+		// all collectors are assigned to the single cluster, which has a root QC
+		collectors := participants.Filter(filter.HasRole(flow.RoleCollection))
+		require.Len(t, setup.Assignments, 1)
+		assert.ElementsMatch(t, collectors.NodeIDs(), setup.Assignments[0])
+		require.Len(t, commit.ClusterQCs, 1)
+		assert.ElementsMatch(t, collectors.NodeIDs(), commit.ClusterQCs[0].VoterIDs)
+
+		// the DKG of the current epoch is re-used
+		assert.Equal(t, dkg.GroupKey(), commit.DKGGroupKey)
+		assert.Len(t, commit.DKGParticipantKeys, len(participants.Filter(filter.IsValidDKGParticipant)))
+	})
+}
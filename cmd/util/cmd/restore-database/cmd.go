@@ -0,0 +1,80 @@
+package restore_database
+
+import (
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+
+	"github.com/onflow/flow-go/storage/badger/backup"
+)
+
+var (
+	flagBackupDir    string
+	flagDatadir      string
+	flagTrieDir      string
+	flagValidateOnly bool
+)
+
+// Cmd validates a backup created by the "backup-database" command or the "create-backup"
+// admin command, and restores it into an empty data directory.
+var Cmd = &cobra.Command{
+	Use:   "restore-database",
+	Short: "Validates a protocol state database backup and restores it",
+	Run:   run,
+}
+
+func init() {
+	Cmd.Flags().StringVar(&flagBackupDir, "backup-dir", "",
+		"directory that stores the backup")
+	_ = Cmd.MarkFlagRequired("backup-dir")
+
+	Cmd.Flags().StringVar(&flagDatadir, "datadir", "",
+		"directory to restore the protocol state into, must not exist or be empty")
+
+	Cmd.Flags().StringVar(&flagTrieDir, "triedir", "",
+		"directory to restore the execution state checkpoint and WAL into, required if the backup includes a checkpoint, must not exist or be empty")
+
+	Cmd.Flags().BoolVar(&flagValidateOnly, "validate-only", false,
+		"only validate the backup without restoring it")
+}
+
+func run(*cobra.Command, []string) {
+	if flagValidateOnly {
+		manifest, err := backup.Validate(flagBackupDir)
+		if err != nil {
+			log.Fatal().Err(err).Msg("backup is invalid")
+		}
+		log.Info().
+			Uint64("finalized_height", manifest.FinalizedHeight).
+			Uint64("sealed_height", manifest.SealedHeight).
+			Msg("backup is valid")
+		return
+	}
+
+	if flagDatadir == "" {
+		log.Fatal().Msg("--datadir is required to restore a backup")
+	}
+
+	log.Info().Str("backup_dir", flagBackupDir).Msg("restoring backup")
+
+	manifest, err := backup.Restore(flagBackupDir, flagDatadir, flagTrieDir)
+	if err != nil {
+		log.Fatal().Err(err).Msg("could not restore backup")
+	}
+
+	log.Info().
+		Uint64("finalized_height", manifest.FinalizedHeight).
+		Hex("finalized_block_id", manifest.FinalizedBlockID[:]).
+		Uint64("sealed_height", manifest.SealedHeight).
+		Hex("sealed_block_id", manifest.SealedBlockID[:]).
+		Msg("backup restored")
+
+	if manifest.Checkpoint != nil {
+		log.Info().
+			Uint64("executed_height", manifest.ExecutedHeight).
+			Hex("executed_block_id", manifest.ExecutedBlockID[:]).
+			Str("checkpoint", manifest.Checkpoint.Name).
+			Uint64("checkpoint_height", manifest.Checkpoint.Height).
+			Int("wal_segments", len(manifest.Checkpoint.WAL)).
+			Msg("execution state restored")
+	}
+}
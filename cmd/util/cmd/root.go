@@ -9,6 +9,8 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 
+	backup_database "github.com/onflow/flow-go/cmd/util/cmd/backup-database"
+	checkpoint_analyze "github.com/onflow/flow-go/cmd/util/cmd/checkpoint-analyze"
 	checkpoint_collect_stats "github.com/onflow/flow-go/cmd/util/cmd/checkpoint-collect-stats"
 	checkpoint_list_tries "github.com/onflow/flow-go/cmd/util/cmd/checkpoint-list-tries"
 	epochs "github.com/onflow/flow-go/cmd/util/cmd/epochs/cmd"
@@ -22,9 +24,12 @@ import (
 	read_hotstuff "github.com/onflow/flow-go/cmd/util/cmd/read-hotstuff/cmd"
 	read_protocol_state "github.com/onflow/flow-go/cmd/util/cmd/read-protocol-state/cmd"
 	index_er "github.com/onflow/flow-go/cmd/util/cmd/reindex/cmd"
+	restore_database "github.com/onflow/flow-go/cmd/util/cmd/restore-database"
 	rollback_executed_height "github.com/onflow/flow-go/cmd/util/cmd/rollback-executed-height/cmd"
 	"github.com/onflow/flow-go/cmd/util/cmd/snapshot"
+	topology_analyze "github.com/onflow/flow-go/cmd/util/cmd/topology-analyze"
 	truncate_database "github.com/onflow/flow-go/cmd/util/cmd/truncate-database"
+	verify_snapshot "github.com/onflow/flow-go/cmd/util/cmd/verify-snapshot"
 )
 
 var (
@@ -64,7 +69,10 @@ func addCommands() {
 	rootCmd.AddCommand(export.Cmd)
 	rootCmd.AddCommand(checkpoint_list_tries.Cmd)
 	rootCmd.AddCommand(checkpoint_collect_stats.Cmd)
+	rootCmd.AddCommand(checkpoint_analyze.Cmd)
 	rootCmd.AddCommand(truncate_database.Cmd)
+	rootCmd.AddCommand(backup_database.Cmd)
+	rootCmd.AddCommand(restore_database.Cmd)
 	rootCmd.AddCommand(read_badger.RootCmd)
 	rootCmd.AddCommand(read_protocol_state.RootCmd)
 	rootCmd.AddCommand(ledger_json_exporter.Cmd)
@@ -74,6 +82,8 @@ func addCommands() {
 	rootCmd.AddCommand(rollback_executed_height.Cmd)
 	rootCmd.AddCommand(read_execution_state.Cmd)
 	rootCmd.AddCommand(snapshot.Cmd)
+	rootCmd.AddCommand(verify_snapshot.Cmd)
+	rootCmd.AddCommand(topology_analyze.Cmd)
 	rootCmd.AddCommand(export_json_transactions.Cmd)
 	rootCmd.AddCommand(read_hotstuff.RootCmd)
 }
@@ -0,0 +1,82 @@
+package topology_analyze
+
+import (
+	"os"
+
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+
+	"github.com/onflow/flow-go/engine/common/rpc/convert"
+	"github.com/onflow/flow-go/network/p2p"
+	"github.com/onflow/flow-go/network/topology"
+	"github.com/onflow/flow-go/utils/io"
+)
+
+var (
+	flagSnapshot    string
+	flagRoleFanouts map[string]string
+)
+
+// Cmd computes the role-aware topology of the nodes of a protocol state snapshot for the current epoch, i.e., the
+// fanout of each node derived from the source of randomness of the epoch, and prints a connectivity report of the
+// resulting graph of the connections between the nodes.
+// The command exits with a non-zero status if the graph is not connected, or the subgraph of a role is not connected.
+var Cmd = &cobra.Command{
+	Use:   "topology-analyze",
+	Short: "Analyzes the connectivity of the role-aware topology of the current epoch of a protocol state snapshot",
+	Run:   run,
+}
+
+func init() {
+	Cmd.Flags().StringVar(&flagSnapshot, "snapshot", "",
+		"path to the JSON encoded protocol state snapshot")
+	_ = Cmd.MarkFlagRequired("snapshot")
+
+	Cmd.Flags().StringToStringVar(&flagRoleFanouts, "topology-role-fanouts", map[string]string{},
+		"maximum number of nodes of each role in the fanout of the role-aware topology, as configured on the nodes")
+}
+
+func run(*cobra.Command, []string) {
+	log := log.With().Str("snapshot", flagSnapshot).Logger()
+
+	fanouts, err := topology.ParseRoleFanouts(flagRoleFanouts)
+	if err != nil {
+		log.Fatal().Err(err).Msg("invalid role fanouts")
+	}
+
+	bytes, err := io.ReadFile(flagSnapshot)
+	if err != nil {
+		log.Fatal().Err(err).Msg("could not read snapshot")
+	}
+
+	snapshot, err := convert.BytesToInmemSnapshot(bytes)
+	if err != nil {
+		log.Fatal().Err(err).Msg("could not decode snapshot")
+	}
+
+	ids, err := snapshot.Identities(p2p.NotEjectedFilter)
+	if err != nil {
+		log.Fatal().Err(err).Msg("could not get identities")
+	}
+
+	randomSource, err := snapshot.Epochs().Current().RandomSource()
+	if err != nil {
+		log.Fatal().Err(err).Msg("could not get random source of current epoch")
+	}
+
+	all, err := topology.RoleAwareFanouts(ids, fanouts, randomSource)
+	if err != nil {
+		log.Fatal().Err(err).Msg("could not compute fanouts")
+	}
+
+	report := topology.AnalyzeConnectivity(ids, all)
+	err = report.Write(os.Stdout)
+	if err != nil {
+		log.Fatal().Err(err).Msg("could not write report")
+	}
+
+	if !report.Passed() {
+		log.Fatal().Msg("topology is not connected")
+	}
+	log.Info().Msg("topology is connected")
+}
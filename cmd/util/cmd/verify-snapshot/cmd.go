@@ -0,0 +1,69 @@
+package verify_snapshot
+
+import (
+	"os"
+
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+
+	"github.com/onflow/flow-go/engine/common/rpc/convert"
+	"github.com/onflow/flow-go/utils/io"
+)
+
+var (
+	flagSnapshot                     string
+	flagSkipNetworkAddressValidation bool
+	flagCheckEntityExpiry            bool
+)
+
+// Cmd verifies a protocol state snapshot (as served by the GetLatestProtocolStateSnapshot
+// endpoint of access nodes), before it is used to dynamically bootstrap a node.
+// It checks the internal consistency of the snapshot and verifies the QC signatures against
+// the keys of the committee included in the snapshot, then prints a human-readable report.
+// The command exits with a non-zero status if any check fails.
+var Cmd = &cobra.Command{
+	Use:   "verify-snapshot",
+	Short: "Verifies the consistency and QC signatures of a protocol state snapshot",
+	Run:   run,
+}
+
+func init() {
+	Cmd.Flags().StringVar(&flagSnapshot, "snapshot", "",
+		"path to the JSON encoded protocol state snapshot")
+	_ = Cmd.MarkFlagRequired("snapshot")
+
+	Cmd.Flags().BoolVar(&flagSkipNetworkAddressValidation, "skip-network-address-validation", false,
+		"allow nodes of the same epoch to share a network address")
+
+	Cmd.Flags().BoolVar(&flagCheckEntityExpiry, "check-entity-expiry", false,
+		"check the sealing segment covers an entire entity expiry window (required by access and consensus nodes)")
+}
+
+func run(*cobra.Command, []string) {
+	log := log.With().Str("snapshot", flagSnapshot).Logger()
+
+	bytes, err := io.ReadFile(flagSnapshot)
+	if err != nil {
+		log.Fatal().Err(err).Msg("could not read snapshot")
+	}
+
+	snapshot, err := convert.BytesToInmemSnapshot(bytes)
+	if err != nil {
+		log.Fatal().Err(err).Msg("could not decode snapshot")
+	}
+
+	report, err := Verify(snapshot, !flagSkipNetworkAddressValidation, flagCheckEntityExpiry)
+	if err != nil {
+		log.Fatal().Err(err).Msg("could not summarize snapshot")
+	}
+
+	err = report.Write(os.Stdout)
+	if err != nil {
+		log.Fatal().Err(err).Msg("could not write report")
+	}
+
+	if !report.Passed() {
+		log.Fatal().Msg("snapshot verification failed")
+	}
+	log.Info().Msg("snapshot verification passed")
+}
@@ -0,0 +1,143 @@
+package verify_snapshot
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/model/flow/filter"
+	"github.com/onflow/flow-go/state/protocol"
+	"github.com/onflow/flow-go/state/protocol/badger"
+)
+
+// Check is the outcome of a single verification step of a snapshot.
+// Err is nil if the check passed.
+type Check struct {
+	Name string
+	Err  error
+}
+
+// Report summarizes a snapshot and holds the outcome of each verification step.
+type Report struct {
+	BlockID       flow.Identifier
+	Height        uint64
+	View          uint64
+	SealedHeight  uint64
+	SporkID       flow.Identifier
+	EpochCounter  uint64
+	EpochPhase    flow.EpochPhase
+	Identities    int
+	SegmentBlocks int
+	Checks        []Check
+}
+
+// Verify checks the internal consistency of the given snapshot and verifies the signatures
+// of the QCs it contains (the QC over the head and the root QCs of all collection clusters
+// of the current epoch) against the keys of the respective committees.
+// Failing checks are recorded in the returned report. An error is only returned if the
+// snapshot cannot be summarized at all.
+func Verify(snapshot protocol.Snapshot, verifyNetworkAddress bool, checkEntityExpiry bool) (*Report, error) {
+	head, err := snapshot.Head()
+	if err != nil {
+		return nil, fmt.Errorf("could not get head: %w", err)
+	}
+	segment, err := snapshot.SealingSegment()
+	if err != nil {
+		return nil, fmt.Errorf("could not get sealing segment: %w", err)
+	}
+	sporkID, err := snapshot.Params().SporkID()
+	if err != nil {
+		return nil, fmt.Errorf("could not get spork ID: %w", err)
+	}
+	counter, err := snapshot.Epochs().Current().Counter()
+	if err != nil {
+		return nil, fmt.Errorf("could not get current epoch counter: %w", err)
+	}
+	phase, err := snapshot.Phase()
+	if err != nil {
+		return nil, fmt.Errorf("could not get epoch phase: %w", err)
+	}
+	identities, err := snapshot.Identities(filter.Any)
+	if err != nil {
+		return nil, fmt.Errorf("could not get identities: %w", err)
+	}
+
+	report := &Report{
+		BlockID:       head.ID(),
+		Height:        head.Height,
+		View:          head.View,
+		SealedHeight:  segment.Sealed().Header.Height,
+		SporkID:       sporkID,
+		EpochCounter:  counter,
+		EpochPhase:    phase,
+		Identities:    len(identities),
+		SegmentBlocks: len(segment.AllBlocks()),
+	}
+
+	report.Checks = append(report.Checks, Check{
+		Name: "sealing segment, sealed result, QC over head and identity table are consistent",
+		Err:  badger.IsValidRootSnapshot(snapshot, true),
+	})
+	report.Checks = append(report.Checks, Check{
+		Name: "epoch setup and commit events are coherent",
+		Err:  badger.IsValidRootSnapshotEpochs(snapshot, verifyNetworkAddress),
+	})
+	report.Checks = append(report.Checks, Check{
+		Name: "QC signatures are valid for the consensus committee and collection clusters",
+		Err:  badger.IsValidRootSnapshotQCs(snapshot),
+	})
+	if checkEntityExpiry {
+		report.Checks = append(report.Checks, Check{
+			Name: "sealing segment covers an entity expiry window",
+			Err:  badger.ValidRootSnapshotContainsEntityExpiryRange(snapshot),
+		})
+	}
+
+	return report, nil
+}
+
+// Passed returns true if and only if all checks of the report passed.
+func (r *Report) Passed() bool {
+	for _, check := range r.Checks {
+		if check.Err != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// Write writes the report in a human-readable format to the given writer.
+func (r *Report) Write(w io.Writer) error {
+	_, err := fmt.Fprintf(w,
+		"snapshot summary:\n"+
+			"  block ID:        %v\n"+
+			"  height:          %d\n"+
+			"  view:            %d\n"+
+			"  sealed height:   %d\n"+
+			"  segment blocks:  %d\n"+
+			"  spork ID:        %v\n"+
+			"  epoch counter:   %d\n"+
+			"  epoch phase:     %s\n"+
+			"  identities:      %d\n"+
+			"checks:\n",
+		r.BlockID, r.Height, r.View, r.SealedHeight, r.SegmentBlocks, r.SporkID, r.EpochCounter, r.EpochPhase, r.Identities)
+	if err != nil {
+		return err
+	}
+
+	passed := 0
+	for _, check := range r.Checks {
+		if check.Err == nil {
+			passed++
+			_, err = fmt.Fprintf(w, "  [PASS] %s\n", check.Name)
+		} else {
+			_, err = fmt.Fprintf(w, "  [FAIL] %s: %v\n", check.Name, check.Err)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err = fmt.Fprintf(w, "%d/%d checks passed\n", passed, len(r.Checks))
+	return err
+}
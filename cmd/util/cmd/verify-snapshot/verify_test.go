@@ -0,0 +1,114 @@
+package verify_snapshot
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/state/protocol/inmem"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+// indices of the checks in the report of Verify
+const (
+	checkConsistency = iota
+	checkEpochs
+	checkQCs
+	checkEntityExpiry
+)
+
+// TestVerify tests that the report summarizes the snapshot and records the outcome of each check.
+func TestVerify(t *testing.T) {
+	participants := unittest.IdentityListFixture(5, unittest.WithAllRoles())
+
+	t.Run("consistent snapshot", func(t *testing.T) {
+		snapshot := unittest.RootSnapshotFixture(participants)
+		head, err := snapshot.Head()
+		require.NoError(t, err)
+
+		report, err := Verify(snapshot, true, true)
+		require.NoError(t, err)
+
+		assert.Equal(t, head.ID(), report.BlockID)
+		assert.Equal(t, head.Height, report.Height)
+		assert.Equal(t, head.View, report.View)
+		assert.Equal(t, head.Height, report.SealedHeight)
+		assert.Equal(t, snapshot.Encodable().Epochs.Current.Counter, report.EpochCounter)
+		assert.Equal(t, flow.EpochPhaseStaking, report.EpochPhase)
+		assert.Equal(t, len(participants), report.Identities)
+		assert.Equal(t, 1, report.SegmentBlocks)
+
+		require.Len(t, report.Checks, 4)
+		assert.NoError(t, report.Checks[checkConsistency].Err)
+		assert.NoError(t, report.Checks[checkEpochs].Err)
+		assert.NoError(t, report.Checks[checkEntityExpiry].Err)
+	})
+
+	t.Run("entity expiry check is optional", func(t *testing.T) {
+		report, err := Verify(unittest.RootSnapshotFixture(participants), true, false)
+		require.NoError(t, err)
+		require.Len(t, report.Checks, 3)
+	})
+
+	t.Run("inconsistent epochs", func(t *testing.T) {
+		encodable := unittest.RootSnapshotFixture(participants).Encodable()
+		next := encodable.Epochs.Current
+		next.Counter++
+		next.FirstView = encodable.Epochs.Current.FinalView + 1
+		next.FinalView = next.FirstView + 1000
+		encodable.Epochs.Next = &next
+		// a next epoch is included, but the phase is still staking
+
+		report, err := Verify(inmem.SnapshotFromEncodable(encodable), true, false)
+		require.NoError(t, err)
+		assert.NoError(t, report.Checks[checkConsistency].Err)
+		assert.ErrorContains(t, report.Checks[checkEpochs].Err, "unexpected next epoch in epoch phase")
+		assert.False(t, report.Passed())
+	})
+
+	t.Run("QC does not certify head", func(t *testing.T) {
+		encodable := unittest.RootSnapshotFixture(participants).Encodable()
+		encodable.QuorumCertificate = unittest.QuorumCertificateFixture()
+
+		report, err := Verify(inmem.SnapshotFromEncodable(encodable), true, false)
+		require.NoError(t, err)
+		assert.Error(t, report.Checks[checkConsistency].Err)
+		assert.False(t, report.Passed())
+	})
+}
+
+// TestReport tests the outcome and the human-readable output of a report.
+func TestReport(t *testing.T) {
+	report := &Report{
+		BlockID:      unittest.IdentifierFixture(),
+		Height:       100,
+		View:         120,
+		SealedHeight: 90,
+		EpochCounter: 3,
+		EpochPhase:   flow.EpochPhaseSetup,
+		Identities:   10,
+		Checks: []Check{
+			{Name: "first"},
+			{Name: "second"},
+		},
+	}
+	assert.True(t, report.Passed())
+
+	var out bytes.Buffer
+	require.NoError(t, report.Write(&out))
+	assert.Contains(t, out.String(), "  height:          100\n")
+	assert.Contains(t, out.String(), "  epoch phase:     EpochPhaseSetup\n")
+	assert.Contains(t, out.String(), "  [PASS] first\n")
+	assert.Contains(t, out.String(), "2/2 checks passed\n")
+
+	report.Checks[1].Err = assert.AnError
+	assert.False(t, report.Passed())
+
+	out.Reset()
+	require.NoError(t, report.Write(&out))
+	assert.Contains(t, out.String(), "  [FAIL] second: "+assert.AnError.Error()+"\n")
+	assert.Contains(t, out.String(), "1/2 checks passed\n")
+}
@@ -6,6 +6,8 @@ import (
 
 	"github.com/spf13/pflag"
 
+	"github.com/onflow/flow-go/admin/commands"
+	verificationCommands "github.com/onflow/flow-go/admin/commands/verification"
 	flowconsensus "github.com/onflow/flow-go/consensus"
 	"github.com/onflow/flow-go/consensus/hotstuff"
 	"github.com/onflow/flow-go/consensus/hotstuff/committees"
@@ -52,7 +54,8 @@ type VerificationConfig struct {
 	blockWorkers uint64 // number of blocks processed in parallel.
 	chunkWorkers uint64 // number of chunks processed in parallel.
 
-	stopAtHeight uint64 // height to stop the node on
+	stopAtHeight    uint64 // height to stop the node on
+	sealedSkipDepth uint64 // depth below the sealed head beyond which results of sealed blocks are not verified.
 }
 
 type VerificationNodeBuilder struct {
@@ -80,6 +83,7 @@ func (v *VerificationNodeBuilder) LoadFlags() {
 			flags.Uint64Var(&v.verConf.blockWorkers, "block-workers", blockconsumer.DefaultBlockWorkers, "maximum number of blocks being processed in parallel")
 			flags.Uint64Var(&v.verConf.chunkWorkers, "chunk-workers", chunkconsumer.DefaultChunkWorkers, "maximum number of execution nodes a chunk data pack request is dispatched to")
 			flags.Uint64Var(&v.verConf.stopAtHeight, "stop-at-height", 0, "height to stop the node at (0 to disable)")
+			flags.Uint64Var(&v.verConf.sealedSkipDepth, "sealed-skip-depth", 0, "skip verifying results of blocks sealed more than this many blocks below the latest sealed block (0 to disable)")
 		})
 }
 
@@ -104,13 +108,20 @@ func (v *VerificationNodeBuilder) LoadComponentsAndModules() {
 		finalizationDistributor *pubsub.FinalizationDistributor
 		finalizedHeader         *commonsync.FinalizedHeaderCache
 
-		committee    *committees.Consensus
-		followerCore *hotstuff.FollowerLoop     // follower hotstuff logic
-		followerEng  *follower.Engine           // the follower engine
-		collector    module.VerificationMetrics // used to collect metrics of all engines
+		committee         *committees.Consensus
+		followerValidator hotstuff.Validator         // used in follower engine and for checkpoint sync
+		followerCore      *hotstuff.FollowerLoop     // follower hotstuff logic
+		followerEng       *follower.Engine           // the follower engine
+		collector         module.VerificationMetrics // used to collect metrics of all engines
 	)
 
 	v.FlowNodeBuilder.
+		AdminCommand("get-catchup-progress", func(config *NodeConfig) commands.AdminCommand {
+			return verificationCommands.NewGetCatchUpProgressCommand(config.State, blockConsumer)
+		}).
+		AdminCommand("fast-forward-block-consumer", func(config *NodeConfig) commands.AdminCommand {
+			return verificationCommands.NewFastForwardBlockConsumerCommand(config.State, blockConsumer)
+		}).
 		PreInit(DynamicStartPreInit).
 		Module("mutable follower state", func(node *NodeConfig) error {
 			var err error
@@ -200,6 +211,7 @@ func (v *VerificationNodeBuilder) LoadComponentsAndModules() {
 			vmCtx := fvm.NewContext(fvmOptions...)
 			chunkVerifier := chunks.NewChunkVerifier(vm, vmCtx, node.Logger)
 			approvalStorage := badger.NewResultApprovals(node.Metrics.Cache, node.DB)
+			faultReportStorage := badger.NewChunkFaultReports(node.DB)
 			verifierEng, err = verifier.New(
 				node.Logger,
 				collector,
@@ -208,7 +220,8 @@ func (v *VerificationNodeBuilder) LoadComponentsAndModules() {
 				node.State,
 				node.Me,
 				chunkVerifier,
-				approvalStorage)
+				approvalStorage,
+				faultReportStorage)
 			return verifierEng, err
 		}).
 		Component("chunk consumer, requester, and fetcher engines", func(node *NodeConfig) (module.ReadyDoneAware, error) {
@@ -281,7 +294,8 @@ func (v *VerificationNodeBuilder) LoadComponentsAndModules() {
 				chunkAssigner,
 				chunkQueue,
 				chunkConsumer,
-				v.verConf.stopAtHeight)
+				v.verConf.stopAtHeight,
+				v.verConf.sealedSkipDepth)
 
 			return assignerEngine, nil
 		}).
@@ -368,7 +382,7 @@ func (v *VerificationNodeBuilder) LoadComponentsAndModules() {
 			packer := hotsignature.NewConsensusSigDataPacker(committee)
 			// initialize the verifier for the protocol consensus
 			verifier := verification.NewCombinedVerifier(committee, packer)
-			validator := validator.New(committee, verifier)
+			followerValidator = validator.New(committee, verifier)
 
 			var err error
 			followerEng, err = follower.New(
@@ -383,7 +397,7 @@ func (v *VerificationNodeBuilder) LoadComponentsAndModules() {
 				followerState,
 				pendingBlocks,
 				followerCore,
-				validator,
+				followerValidator,
 				syncCore,
 				node.Tracer,
 				follower.WithComplianceOptions(compliance.WithSkipNewProposalsThreshold(node.ComplianceConfig.SkipNewProposalsThreshold)),
@@ -407,6 +421,7 @@ func (v *VerificationNodeBuilder) LoadComponentsAndModules() {
 			sync, err := commonsync.New(
 				node.Logger,
 				node.Metrics.Engine,
+				node.Metrics.SyncPeers,
 				node.Network,
 				node.Me,
 				node.Storage.Blocks,
@@ -414,6 +429,11 @@ func (v *VerificationNodeBuilder) LoadComponentsAndModules() {
 				syncCore,
 				finalizedHeader,
 				node.SyncEngineIdentifierProvider,
+				commonsync.WithCheckpointSync(
+					node.CheckpointSyncThreshold,
+					commonsync.NewCheckpointVerifier(node.State, followerValidator),
+					CheckpointSyncConsumer(node),
+				),
 			)
 			if err != nil {
 				return nil, fmt.Errorf("could not create synchronization engine: %w", err)
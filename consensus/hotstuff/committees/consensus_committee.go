@@ -31,6 +31,7 @@ type staticEpochInfo struct {
 	weightThresholdForQC uint64 // computed based on initial committee weights
 	weightThresholdForTO uint64 // computed based on initial committee weights
 	dkg                  hotstuff.DKG
+	fallback             bool // whether this is an artificial fallback epoch, injected during EECC
 }
 
 // newStaticEpochInfo returns the static epoch information from the epoch.
@@ -103,10 +104,24 @@ func newEmergencyFallbackEpoch(lastCommittedEpoch *staticEpochInfo) (*staticEpoc
 		weightThresholdForQC: lastCommittedEpoch.weightThresholdForQC,
 		weightThresholdForTO: lastCommittedEpoch.weightThresholdForTO,
 		dkg:                  lastCommittedEpoch.dkg,
+		fallback:             true,
 	}
 	return epochInfo, nil
 }
 
+// truncatedFallbackEpoch returns a copy of the fallback epoch, which ends in the view
+// immediately before the given recovery epoch starts. The truncated fallback epoch
+// covers the views between the last committed epoch and the recovery epoch.
+// Returns nil if the recovery epoch immediately follows the last committed epoch.
+func truncatedFallbackEpoch(fallbackEpoch *staticEpochInfo, recoveryEpoch *staticEpochInfo) *staticEpochInfo {
+	if recoveryEpoch.firstView <= fallbackEpoch.firstView {
+		return nil
+	}
+	truncated := *fallbackEpoch
+	truncated.finalView = recoveryEpoch.firstView - 1
+	return &truncated
+}
+
 // Consensus represents the main committee for consensus nodes. The consensus
 // committee might be active for multiple successive epochs.
 type Consensus struct {
@@ -114,6 +129,8 @@ type Consensus struct {
 	me                     flow.Identifier             // the node ID of this node
 	mu                     sync.RWMutex                // protects access to epochs
 	epochs                 map[uint64]*staticEpochInfo // cache of initial committee & leader selection per epoch
+	fallbackExtension      *staticEpochInfo            // truncated fallback epoch, covering views between an epoch and the subsequent recovery epoch
+	fallbackExtensionOf    uint64                      // counter of the epoch extended by fallbackExtension
 	committedEpochsCh      chan *flow.Header           // protocol events for newly committed epochs (the first block of the epoch is passed over the channel)
 	epochEmergencyFallback chan struct{}               // protocol event for epoch emergency fallback
 	isEpochFallbackHandled *atomic.Bool                // ensure we only inject fallback epoch once
@@ -156,15 +173,6 @@ func NewConsensusCommittee(state protocol.State, me flow.Identifier) (*Consensus
 		epochs = append(epochs, final.Epochs().Previous())
 	}
 
-	// we prepare the next epoch, if it is committed
-	phase, err := final.Phase()
-	if err != nil {
-		return nil, fmt.Errorf("could not check epoch phase: %w", err)
-	}
-	if phase == flow.EpochPhaseCommitted {
-		epochs = append(epochs, final.Epochs().Next())
-	}
-
 	for _, epoch := range epochs {
 		_, err = com.prepareEpoch(epoch)
 		if err != nil {
@@ -172,7 +180,18 @@ func NewConsensusCommittee(state protocol.State, me flow.Identifier) (*Consensus
 		}
 	}
 
+	// if the current epoch is a recovery epoch, the views between the previous epoch
+	// and the current epoch were covered by the fallback epoch - re-construct it
+	if exists {
+		err = com.prepareRecoveredFallbackExtension(final)
+		if err != nil {
+			return nil, fmt.Errorf("could not prepare fallback extension of previous epoch: %w", err)
+		}
+	}
+
 	// if epoch emergency fallback was triggered, inject the fallback epoch
+	// IMPORTANT: this must happen before preparing the committed next epoch, which
+	// in this case is a recovery epoch replacing the fallback epoch
 	triggered, err := state.Params().EpochFallbackTriggered()
 	if err != nil {
 		return nil, fmt.Errorf("could not check epoch fallback: %w", err)
@@ -184,9 +203,54 @@ func NewConsensusCommittee(state protocol.State, me flow.Identifier) (*Consensus
 		}
 	}
 
+	// we prepare the next epoch, if it is committed
+	phase, err := final.Phase()
+	if err != nil {
+		return nil, fmt.Errorf("could not check epoch phase: %w", err)
+	}
+	if phase == flow.EpochPhaseCommitted {
+		_, err = com.prepareEpoch(final.Epochs().Next())
+		if err != nil {
+			return nil, fmt.Errorf("could not prepare next epoch: %w", err)
+		}
+	}
+
 	return com, nil
 }
 
+// prepareRecoveredFallbackExtension re-constructs the fallback epoch covering the
+// views between the previous epoch and the current epoch, if the current epoch is
+// a recovery epoch. For regular epochs, this is a no-op.
+// No errors are expected during normal operation.
+func (c *Consensus) prepareRecoveredFallbackExtension(final protocol.Snapshot) error {
+	previousCounter, err := final.Epochs().Previous().Counter()
+	if err != nil {
+		return fmt.Errorf("could not get previous epoch counter: %w", err)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	previousEpoch, ok := c.epochs[previousCounter]
+	if !ok {
+		return fmt.Errorf("could not find previous epoch (counter=%d) info", previousCounter)
+	}
+	currentEpoch, ok := c.epochs[previousCounter+1]
+	if !ok {
+		return fmt.Errorf("could not find current epoch (counter=%d) info", previousCounter+1)
+	}
+	if currentEpoch.firstView == previousEpoch.finalView+1 {
+		return nil
+	}
+
+	fallbackEpoch, err := newEmergencyFallbackEpoch(previousEpoch)
+	if err != nil {
+		return fmt.Errorf("could not construct fallback epoch: %w", err)
+	}
+	c.fallbackExtension = truncatedFallbackEpoch(fallbackEpoch, currentEpoch)
+	c.fallbackExtensionOf = previousCounter
+	return nil
+}
+
 // Identities returns the identities of all authorized consensus participants at the given block.
 // The order of the identities is the canonical order.
 func (c *Consensus) IdentitiesByBlock(blockID flow.Identifier) (flow.IdentityList, error) {
@@ -420,7 +484,12 @@ func (c *Consensus) staticEpochInfoByView(view uint64) (*staticEpochInfo, error)
 			return epoch, nil
 		}
 	}
+	// views between an epoch and the subsequent recovery epoch are covered by the fallback epoch
+	extension := c.fallbackExtension
 	c.mu.RUnlock()
+	if extension != nil && extension.firstView <= view && view <= extension.finalView {
+		return extension, nil
+	}
 
 	return nil, model.ErrViewForUnknownEpoch
 }
@@ -428,6 +497,9 @@ func (c *Consensus) staticEpochInfoByView(view uint64) (*staticEpochInfo, error)
 // prepareEpoch pre-computes and stores the static epoch information for the
 // given epoch, including leader selection. Calling prepareEpoch multiple times
 // for the same epoch returns cached static epoch information.
+// If a fallback epoch was injected for the given epoch counter, the input is a
+// recovery epoch and replaces the fallback epoch. The fallback epoch is truncated
+// to cover the views between the prior epoch and the recovery epoch.
 // Input must be a committed epoch.
 // No errors are expected during normal operation.
 func (c *Consensus) prepareEpoch(epoch protocol.Epoch) (*staticEpochInfo, error) {
@@ -441,9 +513,10 @@ func (c *Consensus) prepareEpoch(epoch protocol.Epoch) (*staticEpochInfo, error)
 	c.mu.RLock()
 	epochInfo, exists := c.epochs[counter]
 	c.mu.RUnlock()
-	if exists {
+	if exists && !epochInfo.fallback {
 		return epochInfo, nil
 	}
+	fallbackEpoch := epochInfo
 
 	epochInfo, err = newStaticEpochInfo(epoch)
 	if err != nil {
@@ -451,10 +524,16 @@ func (c *Consensus) prepareEpoch(epoch protocol.Epoch) (*staticEpochInfo, error)
 	}
 
 	// sanity check: ensure new epoch has contiguous views with the prior epoch
+	// (for a recovery epoch, the prior epoch is extended by the fallback epoch)
 	c.mu.RLock()
 	prevEpochInfo, exists := c.epochs[counter-1]
 	c.mu.RUnlock()
-	if exists {
+	if fallbackEpoch != nil {
+		if epochInfo.firstView < fallbackEpoch.firstView {
+			return nil, fmt.Errorf("recovery epoch %d starts at view %d, before the fallback epoch it replaces (first view %d)",
+				counter, epochInfo.firstView, fallbackEpoch.firstView)
+		}
+	} else if exists {
 		if epochInfo.firstView != prevEpochInfo.finalView+1 {
 			return nil, fmt.Errorf("non-contiguous view ranges between consecutive epochs (epoch_%d=[%d,%d], epoch_%d=[%d,%d])",
 				counter-1, prevEpochInfo.firstView, prevEpochInfo.finalView,
@@ -466,6 +545,12 @@ func (c *Consensus) prepareEpoch(epoch protocol.Epoch) (*staticEpochInfo, error)
 	c.mu.Lock()
 	defer c.mu.Unlock()
 	c.epochs[counter] = epochInfo
+	if fallbackEpoch != nil {
+		c.fallbackExtension = truncatedFallbackEpoch(fallbackEpoch, epochInfo)
+		c.fallbackExtensionOf = counter - 1
+		// epoch fallback may be triggered again during the recovery epoch
+		c.isEpochFallbackHandled.Store(false)
+	}
 	// now prune any old epochs, if we have exceeded our maximum of 3
 	// if we have fewer than 3 epochs, this is a no-op
 	c.pruneEpochInfo()
@@ -489,4 +574,7 @@ func (c *Consensus) pruneEpochInfo() {
 			delete(c.epochs, counter)
 		}
 	}
+	if c.fallbackExtension != nil && c.fallbackExtensionOf+3 <= max {
+		c.fallbackExtension = nil
+	}
 }
@@ -14,6 +14,7 @@ import (
 
 	"github.com/onflow/flow-go/consensus/hotstuff/model"
 	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/model/flow/filter"
 	"github.com/onflow/flow-go/model/flow/mapfunc"
 	"github.com/onflow/flow-go/module/irrecoverable"
 	"github.com/onflow/flow-go/state/protocol"
@@ -255,6 +256,85 @@ func (suite *ConsensusSuite) TestProtocolEvents_EpochFallback() {
 	suite.AssertStoredEpochCounterRange(suite.currentEpochCounter, suite.currentEpochCounter+1)
 }
 
+// TestConstruction_RecoveryEpochCommitted tests construction when EECC has been triggered
+// and a recovery epoch has been committed. The recovery epoch should replace the fallback
+// epoch, which should only cover the views between the current and the recovery epoch.
+func (suite *ConsensusSuite) TestConstruction_RecoveryEpochCommitted() {
+	curEpoch := newMockEpoch(suite.currentEpochCounter, unittest.IdentityListFixture(10), 101, 200, unittest.SeedFixture(32), true)
+	recoveryIdentities := unittest.IdentityListFixture(10)
+	recoveryEpoch := newMockEpoch(suite.currentEpochCounter+1, recoveryIdentities, 301, 400, unittest.SeedFixture(32), true)
+	suite.epochs.Add(curEpoch)
+	suite.epochs.Add(recoveryEpoch)
+	suite.epochFallbackTriggered = true
+	suite.phase = flow.EpochPhaseCommitted
+
+	suite.CreateAndStartCommittee()
+	suite.Assert().Len(suite.committee.epochs, 2)
+	suite.AssertStoredEpochCounterRange(suite.currentEpochCounter, suite.currentEpochCounter+1)
+	suite.AssertRecoveryEpoch(recoveryIdentities, 201, 301, 400)
+}
+
+// TestProtocolEvents_RecoveryEpoch tests that a recovery epoch committed after epoch
+// fallback was triggered is handled correctly. The recovery epoch should replace the
+// fallback epoch, which should only cover the views between the current and the
+// recovery epoch.
+func (suite *ConsensusSuite) TestProtocolEvents_RecoveryEpoch() {
+	curEpoch := newMockEpoch(suite.currentEpochCounter, unittest.IdentityListFixture(10), 101, 200, unittest.SeedFixture(32), true)
+	suite.epochs.Add(curEpoch)
+
+	suite.CreateAndStartCommittee()
+
+	suite.committee.EpochEmergencyFallbackTriggered()
+	// wait for the protocol event to be processed (async)
+	require.Eventually(suite.T(), func() bool {
+		_, err := suite.committee.IdentitiesByEpoch(unittest.Uint64InRange(201, 300))
+		return err == nil
+	}, 30*time.Second, 50*time.Millisecond)
+
+	recoveryIdentities := unittest.IdentityListFixture(10)
+	recoveryEpoch := newMockEpoch(suite.currentEpochCounter+1, recoveryIdentities, 301, 400, unittest.SeedFixture(32), true)
+	firstBlockOfCommittedPhase := unittest.BlockHeaderFixture()
+	suite.state.On("AtBlockID", firstBlockOfCommittedPhase.ID()).Return(suite.snapshot)
+	suite.epochs.Add(recoveryEpoch)
+	suite.committee.EpochCommittedPhaseStarted(suite.currentEpochCounter, firstBlockOfCommittedPhase)
+	// wait for the protocol event to be processed (async)
+	require.Eventually(suite.T(), func() bool {
+		identities, err := suite.committee.IdentitiesByEpoch(301)
+		return err == nil && identities[0].NodeID == recoveryIdentities[0].NodeID
+	}, 30*time.Second, 50*time.Millisecond)
+
+	suite.Assert().Len(suite.committee.epochs, 2)
+	suite.AssertStoredEpochCounterRange(suite.currentEpochCounter, suite.currentEpochCounter+1)
+	suite.AssertRecoveryEpoch(recoveryIdentities, 201, 301, 400)
+}
+
+// AssertRecoveryEpoch asserts that the views [fallbackFirstView, recoveryFirstView-1]
+// are covered by the fallback epoch, and the views [recoveryFirstView, recoveryFinalView]
+// are covered by the recovery epoch with the given identities.
+func (suite *ConsensusSuite) AssertRecoveryEpoch(recoveryIdentities flow.IdentityList, fallbackFirstView, recoveryFirstView, recoveryFinalView uint64) {
+	t := suite.T()
+
+	// the fallback epoch should cover the views before the recovery epoch
+	fallbackIdentities, err := suite.committee.IdentitiesByEpoch(unittest.Uint64InRange(fallbackFirstView, recoveryFirstView-1))
+	require.NoError(t, err)
+	assert.NotEqual(t, recoveryIdentities[0].NodeID, fallbackIdentities[0].NodeID)
+	_, err = suite.committee.LeaderForView(recoveryFirstView - 1)
+	require.NoError(t, err)
+
+	// the recovery epoch should cover its own views
+	identities, err := suite.committee.IdentitiesByEpoch(unittest.Uint64InRange(recoveryFirstView, recoveryFinalView))
+	require.NoError(t, err)
+	assert.Equal(t, recoveryIdentities.Filter(filter.IsVotingConsensusCommitteeMember), identities)
+	leader, err := suite.committee.LeaderForView(recoveryFirstView)
+	require.NoError(t, err)
+	_, ok := recoveryIdentities.ByNodeID(leader)
+	assert.True(t, ok)
+
+	// the fallback epoch should no longer cover views after the recovery epoch
+	_, err = suite.committee.IdentitiesByEpoch(recoveryFinalView + 1)
+	assert.ErrorIs(t, err, model.ErrViewForUnknownEpoch)
+}
+
 // TestIdentitiesByBlock tests retrieving committee members by block.
 // * should use up-to-block committee information
 // * should exclude non-committee members
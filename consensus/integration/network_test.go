@@ -198,6 +198,30 @@ func (c *Conduit) Multicast(event interface{}, num uint, targetIDs ...flow.Ident
 	return c.net.multicast(event, c.channel, num, targetIDs...)
 }
 
+// Request sends the request, but does not await the response: the stub network delivers the response, if any, to the
+// engine like any other message. It blocks until the context is done, or network.DefaultRequestTimeout elapsed.
+func (c *Conduit) Request(ctx context.Context, request network.RequestMessage, targetID flow.Identifier) (network.ResponseMessage, error) {
+	err := c.Unicast(request, targetID)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, network.DefaultRequestTimeout)
+		defer cancel()
+	}
+	<-ctx.Done()
+	return nil, fmt.Errorf("stub conduit does not await responses: %w", ctx.Err())
+}
+
+func (c *Conduit) Respond(response network.ResponseMessage, targetID flow.Identifier) error {
+	return c.Unicast(response, targetID)
+}
+
+func (c *Conduit) ReportMisbehavior(_ network.MisbehaviorReport) {
+	// no-op for stub network
+}
+
 func (c *Conduit) Close() error {
 	if c.ctx.Err() != nil {
 		return fmt.Errorf("conduit closed")
@@ -619,6 +619,7 @@ func createNode(
 	sync, err := synceng.New(
 		log,
 		metricsCollector,
+		metricsCollector,
 		net,
 		me,
 		blocksDB,
@@ -2,6 +2,7 @@ package backend
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
@@ -229,6 +230,9 @@ func (b *Backend) Ping(ctx context.Context) error {
 func (b *Backend) GetCollectionByID(_ context.Context, colID flow.Identifier) (*flow.LightCollection, error) {
 	// retrieve the collection from the collection storage
 	col, err := b.collections.LightByID(colID)
+	if errors.Is(err, storage.ErrPruned) {
+		return nil, rpc.ConvertStorageError(err)
+	}
 	if err != nil {
 		// Collections are retrieved asynchronously as we finalize blocks, so
 		// it is possible for a client to request a finalized block from us
@@ -605,6 +605,55 @@ func (suite *Suite) TestGetCollection() {
 	suite.assertAllExpectations()
 }
 
+// TestGetPrunedTransactionAndCollection tests that transactions and collections of pruned
+// payloads are reported as out of range, without looking them up on historical access nodes.
+func (suite *Suite) TestGetPrunedTransactionAndCollection() {
+	suite.state.On("Sealed").Return(suite.snapshot, nil).Maybe()
+
+	txID := unittest.IdentifierFixture()
+	collID := unittest.IdentifierFixture()
+
+	suite.transactions.
+		On("ByID", txID).
+		Return(nil, storage.ErrPruned)
+	suite.collections.
+		On("LightByID", collID).
+		Return(nil, storage.ErrPruned).
+		Once()
+
+	backend := New(
+		suite.state,
+		nil,
+		nil,
+		nil,
+		nil,
+		suite.collections,
+		suite.transactions,
+		nil,
+		nil,
+		suite.chainID,
+		metrics.NewNoopCollector(),
+		nil,
+		false,
+		DefaultMaxHeightRange,
+		nil,
+		nil,
+		suite.log,
+		DefaultSnapshotHistoryLimit,
+	)
+
+	_, err := backend.GetTransaction(context.Background(), txID)
+	suite.Require().Equal(codes.OutOfRange, status.Code(err))
+
+	_, err = backend.GetTransactionResult(context.Background(), txID)
+	suite.Require().Equal(codes.OutOfRange, status.Code(err))
+
+	_, err = backend.GetCollectionByID(context.Background(), collID)
+	suite.Require().Equal(codes.OutOfRange, status.Code(err))
+
+	suite.assertAllExpectations()
+}
+
 // TestGetTransactionResultByIndex tests that the request is forwarded to EN
 func (suite *Suite) TestGetTransactionResultByIndex() {
 	suite.state.On("Sealed").Return(suite.snapshot, nil).Maybe()
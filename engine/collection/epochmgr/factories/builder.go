@@ -55,6 +55,7 @@ func (f *BuilderFactory) Create(
 	build, err := builder.NewBuilder(
 		f.db,
 		f.trace,
+		f.metrics,
 		f.mainChainHeaders,
 		clusterHeaders,
 		clusterPayloads,
@@ -0,0 +1,207 @@
+package ingest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/onflow/flow/protobuf/go/flow/execution"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/onflow/flow-go/engine/common/rpc/convert"
+	"github.com/onflow/flow-go/model/flow"
+)
+
+var (
+	// ErrAccountNotFound indicates that the account does not exist at the given block.
+	ErrAccountNotFound = errors.New("account not found")
+	// ErrAccountKeyNotFound indicates that the account does not have a key with the given index at the given block.
+	ErrAccountKeyNotFound = errors.New("account key not found")
+	// ErrStateUnavailable indicates that the state can't be read from the source, e.g., because the block is not
+	// executed yet, or the source does not provide this part of the state.
+	ErrStateUnavailable = errors.New("execution state unavailable")
+)
+
+// AccountStateReader reads the state of the accounts after the execution of a block, from an execution state source.
+type AccountStateReader interface {
+	// Balance returns the balance of the account after the execution of the block.
+	// Expected errors during normal operation:
+	//   - ErrAccountNotFound if the account does not exist.
+	//   - ErrStateUnavailable if the balance can't be read from the source.
+	Balance(ctx context.Context, blockID flow.Identifier, address flow.Address) (uint64, error)
+
+	// AccountKey returns the public key of the account with the given index after the execution of the block.
+	// Expected errors during normal operation:
+	//   - ErrAccountNotFound if the account does not exist.
+	//   - ErrAccountKeyNotFound if the account does not have a key with the given index.
+	//   - ErrStateUnavailable if the key can't be read from the source.
+	AccountKey(ctx context.Context, blockID flow.Identifier, address flow.Address, keyIndex uint64) (*flow.AccountPublicKey, error)
+}
+
+// ExecutionNodeAccountStateReader reads the state of the accounts from the execution API of an execution node.
+//
+// The account keys are read from the registers of the account, which is cheaper than reading the whole account, and
+// tells unknown accounts apart from blocks which are not executed yet. The balances are read from the account, as they
+// can't be decoded from the registers.
+type ExecutionNodeAccountStateReader struct {
+	client    execution.ExecutionAPIClient
+	registers *ExecutionNodeRegisterReader
+	keys      *RegisterAccountStateReader
+}
+
+var _ AccountStateReader = (*ExecutionNodeAccountStateReader)(nil)
+
+// NewExecutionNodeAccountStateReader creates a reader of the state of the accounts from the execution API client.
+func NewExecutionNodeAccountStateReader(client execution.ExecutionAPIClient) *ExecutionNodeAccountStateReader {
+	registers := NewExecutionNodeRegisterReader(client)
+	return &ExecutionNodeAccountStateReader{
+		client:    client,
+		registers: registers,
+		keys:      NewRegisterAccountStateReader(registers),
+	}
+}
+
+// Balance returns the balance of the account after the execution of the block.
+// Expected errors during normal operation:
+//   - ErrAccountNotFound if the account does not exist.
+//   - ErrStateUnavailable if the block is not executed by the execution node.
+func (r *ExecutionNodeAccountStateReader) Balance(ctx context.Context, blockID flow.Identifier, address flow.Address) (uint64, error) {
+	account, err := r.account(ctx, blockID, address)
+	if err != nil {
+		return 0, err
+	}
+	return account.Balance, nil
+}
+
+// AccountKey returns the public key of the account with the given index after the execution of the block.
+// Expected errors during normal operation:
+//   - ErrAccountNotFound if the account does not exist.
+//   - ErrAccountKeyNotFound if the account does not have a key with the given index.
+//   - ErrStateUnavailable if the block is not executed by the execution node.
+func (r *ExecutionNodeAccountStateReader) AccountKey(ctx context.Context, blockID flow.Identifier, address flow.Address, keyIndex uint64) (*flow.AccountPublicKey, error) {
+	return r.keys.AccountKey(ctx, blockID, address, keyIndex)
+}
+
+// account retrieves the account after the execution of the block from the execution node.
+// The execution API answers NotFound both for accounts which don't exist and for blocks which are not executed yet,
+// hence the status register of the account is read to tell them apart.
+// Expected errors during normal operation:
+//   - ErrAccountNotFound if the account does not exist.
+//   - ErrStateUnavailable if the block is not executed by the execution node.
+func (r *ExecutionNodeAccountStateReader) account(ctx context.Context, blockID flow.Identifier, address flow.Address) (*flow.Account, error) {
+	res, err := r.client.GetAccountAtBlockID(ctx, &execution.GetAccountAtBlockIDRequest{
+		BlockId: blockID[:],
+		Address: address.Bytes(),
+	})
+	if status.Code(err) == codes.NotFound {
+		accountStatus, err := r.registers.RegisterAtBlockID(ctx, blockID, flow.AccountStatusRegisterID(address))
+		if err != nil {
+			return nil, fmt.Errorf("could not read status of account %s not found at block %x: %w", address, blockID, err)
+		}
+		if len(accountStatus) == 0 {
+			return nil, ErrAccountNotFound
+		}
+		return nil, fmt.Errorf("existing account %s not found at block %x: %w", address, blockID, ErrStateUnavailable)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not get account %s at block %x from execution node: %w", address, blockID, err)
+	}
+	account, err := convert.MessageToAccount(res.GetAccount())
+	if err != nil {
+		return nil, fmt.Errorf("could not convert account message: %w", err)
+	}
+	return account, nil
+}
+
+// RegisterReader reads the registers of the execution state.
+type RegisterReader interface {
+	// RegisterAtBlockID returns the value of the register after the execution of the block. An empty value means
+	// the register is not set.
+	// Expected errors during normal operation:
+	//   - ErrStateUnavailable if the block is not executed yet.
+	RegisterAtBlockID(ctx context.Context, blockID flow.Identifier, id flow.RegisterID) (flow.RegisterValue, error)
+}
+
+// ExecutionNodeRegisterReader reads the registers of the execution state from the execution API of an execution node.
+type ExecutionNodeRegisterReader struct {
+	client execution.ExecutionAPIClient
+}
+
+var _ RegisterReader = (*ExecutionNodeRegisterReader)(nil)
+
+// NewExecutionNodeRegisterReader creates a reader of the registers from the execution API client.
+func NewExecutionNodeRegisterReader(client execution.ExecutionAPIClient) *ExecutionNodeRegisterReader {
+	return &ExecutionNodeRegisterReader{
+		client: client,
+	}
+}
+
+// RegisterAtBlockID returns the value of the register after the execution of the block. An empty value means
+// the register is not set.
+// Expected errors during normal operation:
+//   - ErrStateUnavailable if the block is not executed by the execution node.
+func (r *ExecutionNodeRegisterReader) RegisterAtBlockID(ctx context.Context, blockID flow.Identifier, id flow.RegisterID) (flow.RegisterValue, error) {
+	res, err := r.client.GetRegisterAtBlockID(ctx, &execution.GetRegisterAtBlockIDRequest{
+		BlockId:       blockID[:],
+		RegisterOwner: []byte(id.Owner),
+		RegisterKey:   []byte(id.Key),
+	})
+	// the execution API answers Internal when the state commitment of the block is not known yet
+	if status.Code(err) == codes.Internal {
+		return nil, fmt.Errorf("could not get register %s at block %x: %v: %w", id, blockID, err, ErrStateUnavailable)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not get register %s at block %x from execution node: %w", id, blockID, err)
+	}
+	return res.GetValue(), nil
+}
+
+// RegisterAccountStateReader reads the state of the accounts from a local register store.
+//
+// The balance of an account is held in a Cadence vault, which can't be decoded without the Cadence runtime, hence
+// the reader only provides the account keys, and reports the balances as unavailable.
+type RegisterAccountStateReader struct {
+	registers RegisterReader
+}
+
+var _ AccountStateReader = (*RegisterAccountStateReader)(nil)
+
+// NewRegisterAccountStateReader creates a reader of the state of the accounts from the register store.
+func NewRegisterAccountStateReader(registers RegisterReader) *RegisterAccountStateReader {
+	return &RegisterAccountStateReader{
+		registers: registers,
+	}
+}
+
+// Balance always returns ErrStateUnavailable, as the balances can't be decoded from the registers.
+func (r *RegisterAccountStateReader) Balance(context.Context, flow.Identifier, flow.Address) (uint64, error) {
+	return 0, fmt.Errorf("balances can't be read from registers: %w", ErrStateUnavailable)
+}
+
+// AccountKey returns the public key of the account with the given index after the execution of the block.
+// Expected errors during normal operation:
+//   - ErrAccountNotFound if the account does not exist.
+//   - ErrAccountKeyNotFound if the account does not have a key with the given index.
+//   - ErrStateUnavailable if the block is not executed yet.
+func (r *RegisterAccountStateReader) AccountKey(ctx context.Context, blockID flow.Identifier, address flow.Address, keyIndex uint64) (*flow.AccountPublicKey, error) {
+	value, err := r.registers.RegisterAtBlockID(ctx, blockID, flow.PublicKeyRegisterID(address, keyIndex))
+	if err != nil {
+		return nil, fmt.Errorf("could not read public key register: %w", err)
+	}
+	if len(value) == 0 {
+		status, err := r.registers.RegisterAtBlockID(ctx, blockID, flow.AccountStatusRegisterID(address))
+		if err != nil {
+			return nil, fmt.Errorf("could not read account status register: %w", err)
+		}
+		if len(status) == 0 {
+			return nil, ErrAccountNotFound
+		}
+		return nil, ErrAccountKeyNotFound
+	}
+	key, err := flow.DecodeAccountPublicKey(value, keyIndex)
+	if err != nil {
+		return nil, fmt.Errorf("could not decode public key %d of account %s: %w", keyIndex, address, err)
+	}
+	return &key, nil
+}
@@ -1,9 +1,20 @@
 package ingest
 
 import (
+	"time"
+
 	"github.com/onflow/flow-go/model/flow"
 )
 
+const (
+	// DefaultMinPayerBalance is the default minimum balance of the payer of a transaction, which is the inclusion fee
+	// of a transaction (1e-6 FLOW).
+	DefaultMinPayerBalance uint64 = 100
+	// DefaultMaxSequenceNumberGap is the default maximum difference between the sequence number of the proposal key of
+	// a transaction and the sequence number of the key at the reference block of the transaction.
+	DefaultMaxSequenceNumberGap uint64 = 1000
+)
+
 // Config defines configuration for the transaction ingest engine.
 type Config struct {
 	// how much buffer time there is between a transaction being ingested by a
@@ -22,16 +33,35 @@ type Config struct {
 	MaxCollectionByteSize uint64
 	// maximum number of un-processed transaction messages to hold in the queue.
 	MaxMessageQueueSize uint
+	// the minimum balance of the payer of a transaction at its reference block, checked
+	// by the pre-execution checks. Transactions whose payer has a lower balance are
+	// rejected. 0 disables the payer balance check.
+	MinPayerBalance uint64
+	// the maximum difference between the sequence number of the proposal key of a
+	// transaction and the sequence number of the key at the reference block, checked
+	// by the pre-execution checks.
+	MaxSequenceNumberGap uint64
+	// the number of account balances and keys cached by the pre-execution checks.
+	PreExecutionCheckCacheSize uint
+	// the timeout of reading the account state for the pre-execution checks.
+	PreExecutionCheckTimeout time.Duration
+	// the number of workers reading the account state for the pre-execution checks.
+	PreExecutionCheckWorkers uint
 }
 
 func DefaultConfig() Config {
 	return Config{
-		ExpiryBuffer:           flow.DefaultTransactionExpiryBuffer,
-		MaxGasLimit:            flow.DefaultMaxTransactionGasLimit,
-		MaxTransactionByteSize: flow.DefaultMaxTransactionByteSize,
-		MaxCollectionByteSize:  flow.DefaultMaxCollectionByteSize,
-		CheckScriptsParse:      true,
-		PropagationRedundancy:  2,
-		MaxMessageQueueSize:    10_000,
+		ExpiryBuffer:               flow.DefaultTransactionExpiryBuffer,
+		MaxGasLimit:                flow.DefaultMaxTransactionGasLimit,
+		MaxTransactionByteSize:     flow.DefaultMaxTransactionByteSize,
+		MaxCollectionByteSize:      flow.DefaultMaxCollectionByteSize,
+		CheckScriptsParse:          true,
+		PropagationRedundancy:      2,
+		MaxMessageQueueSize:        10_000,
+		MinPayerBalance:            DefaultMinPayerBalance,
+		MaxSequenceNumberGap:       DefaultMaxSequenceNumberGap,
+		PreExecutionCheckCacheSize: 10_000,
+		PreExecutionCheckTimeout:   time.Second,
+		PreExecutionCheckWorkers:   4,
 	}
 }
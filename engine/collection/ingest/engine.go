@@ -39,10 +39,23 @@ type Engine struct {
 	messageHandler       *engine.MessageHandler
 	pools                *epochs.TransactionPools
 	transactionValidator *access.TransactionValidator
+	accountStateReader   AccountStateReader
+	preExecutionChecker  *preExecutionChecker
 
 	config Config
 }
 
+// Option configures the ingest engine.
+type Option func(*Engine)
+
+// WithPreExecutionChecks enables the pre-execution checks of the transactions, reading the payer balances and the
+// proposal key sequence numbers from the account state reader in the background.
+func WithPreExecutionChecks(reader AccountStateReader) Option {
+	return func(e *Engine) {
+		e.accountStateReader = reader
+	}
+}
+
 // New creates a new collection ingest engine.
 func New(
 	log zerolog.Logger,
@@ -55,6 +68,7 @@ func New(
 	chain flow.Chain,
 	pools *epochs.TransactionPools,
 	config Config,
+	opts ...Option,
 ) (*Engine, error) {
 
 	logger := log.With().Str("engine", "ingest").Logger()
@@ -112,10 +126,24 @@ func New(
 		config:               config,
 		transactionValidator: transactionValidator,
 	}
+	for _, apply := range opts {
+		apply(e)
+	}
+	if e.accountStateReader != nil {
+		e.preExecutionChecker, err = newPreExecutionChecker(logger, e.accountStateReader, config)
+		if err != nil {
+			return nil, fmt.Errorf("could not create pre-execution checker: %w", err)
+		}
+	}
 
-	e.ComponentManager = component.NewComponentManagerBuilder().
-		AddWorker(e.processQueuedTransactions).
-		Build()
+	builder := component.NewComponentManagerBuilder().
+		AddWorker(e.processQueuedTransactions)
+	if e.preExecutionChecker != nil {
+		for i := uint(0); i < config.PreExecutionCheckWorkers; i++ {
+			builder.AddWorker(e.preExecutionChecker.lookupAccountStates)
+		}
+	}
+	e.ComponentManager = builder.Build()
 
 	conduit, err := net.Register(channels.PushTransactions, e)
 	if err != nil {
@@ -359,6 +387,14 @@ func (e *Engine) ingestTransaction(
 		return engine.NewInvalidInputErrorf("invalid transaction (%x): %w", txID, err)
 	}
 
+	// check that the transaction won't clearly fail at execution
+	if e.preExecutionChecker != nil {
+		err = e.preExecutionChecker.Check(tx)
+		if err != nil {
+			return engine.NewInvalidInputErrorf("transaction (%x) fails pre-execution checks: %w", txID, err)
+		}
+	}
+
 	// if our cluster is responsible for the transaction, add it to our local mempool
 	if localClusterFingerprint == txClusterFingerprint {
 		_ = pool.Add(tx)
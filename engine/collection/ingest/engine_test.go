@@ -13,6 +13,7 @@ import (
 
 	"github.com/onflow/flow-go/access"
 	"github.com/onflow/flow-go/engine"
+	mockingest "github.com/onflow/flow-go/engine/collection/ingest/mock"
 	"github.com/onflow/flow-go/model/flow"
 	"github.com/onflow/flow-go/model/flow/factory"
 	"github.com/onflow/flow-go/model/flow/filter"
@@ -270,7 +271,50 @@ func (suite *Suite) TestInvalidTransaction() {
 
 }
 
+// TestPreExecutionChecks checks that the transactions failing the pre-execution checks are rejected once the state of
+// their accounts was read in the background, when the checks are enabled.
+func (suite *Suite) TestPreExecutionChecks() {
+	log := zerolog.New(io.Discard)
+	metrics := metrics.NewNoopCollector()
+	net := new(mocknetwork.Network)
+	net.On("Register", mock.Anything, mock.Anything).Return(suite.conduit, nil).Once()
+	reader := mockingest.NewAccountStateReader(suite.T())
+
+	var err error
+	suite.engine, err = New(log, net, suite.state, metrics, metrics, metrics, suite.me, flow.Testnet.Chain(), suite.pools, suite.conf,
+		WithPreExecutionChecks(reader))
+	suite.Require().NoError(err)
+
+	parentCtx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	suite.engine.Start(irrecoverable.NewMockSignalerContext(suite.T(), parentCtx))
+	unittest.AssertClosesBefore(suite.T(), suite.engine.Ready(), time.Second)
+
+	tx := unittest.TransactionBodyFixture()
+	tx.ReferenceBlockID = suite.root.ID()
+	reader.On("AccountKey", mock.Anything, tx.ReferenceBlockID, tx.ProposalKey.Address, tx.ProposalKey.KeyIndex).
+		Return(&flow.AccountPublicKey{SeqNumber: tx.ProposalKey.SequenceNumber + 1}, nil).Once()
+	reader.On("Balance", mock.Anything, tx.ReferenceBlockID, tx.Payer).Return(suite.conf.MinPayerBalance, nil).Once()
+
+	// the first transaction is ingested without waiting for the state of its accounts
+	suite.conduit.On("Multicast", &tx, mock.Anything, mock.Anything, mock.Anything).Return(nil).Once()
+	err = suite.engine.ProcessTransaction(&tx)
+	suite.Require().NoError(err)
+
+	// once the state was read, the next transactions of the accounts are checked
+	replay := tx
+	replay.Script = append(replay.Script, ' ')
+	suite.Require().Eventually(func() bool {
+		err = suite.engine.ProcessTransaction(&replay)
+		return err != nil
+	}, time.Second, 10*time.Millisecond)
+	suite.Assert().True(engine.IsInvalidInputError(err))
+	suite.Assert().True(errors.As(err, &InvalidSequenceNumberError{}))
+	suite.Assert().False(suite.pools.ForEpoch(1).Has(replay.ID()))
+}
+
 // should return an error if the engine is shutdown and not processing transactions
+
 func (suite *Suite) TestComponentShutdown() {
 	tx := unittest.TransactionBodyFixture()
 	tx.ReferenceBlockID = suite.root.ID()
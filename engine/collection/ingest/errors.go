@@ -0,0 +1,45 @@
+package ingest
+
+import (
+	"fmt"
+
+	"github.com/onflow/flow-go/model/flow"
+)
+
+// InvalidProposalKeyError indicates that the proposal key of a transaction is revoked.
+type InvalidProposalKeyError struct {
+	Address  flow.Address
+	KeyIndex uint64
+}
+
+func (e InvalidProposalKeyError) Error() string {
+	return fmt.Sprintf("proposal key (address: %s, index: %d) is revoked", e.Address, e.KeyIndex)
+}
+
+// InvalidSequenceNumberError indicates that the sequence number of the proposal key of a transaction was already used,
+// or is too far ahead of the current sequence number of the key to be plausible.
+type InvalidSequenceNumberError struct {
+	Address  flow.Address
+	KeyIndex uint64
+	Current  uint64
+	Actual   uint64
+}
+
+func (e InvalidSequenceNumberError) Error() string {
+	if e.Actual < e.Current {
+		return fmt.Sprintf("proposal key (address: %s, index: %d) sequence number (%d) was already used, current sequence number is %d", e.Address, e.KeyIndex, e.Actual, e.Current)
+	}
+	return fmt.Sprintf("proposal key (address: %s, index: %d) sequence number (%d) is too far ahead of the current sequence number (%d)", e.Address, e.KeyIndex, e.Actual, e.Current)
+}
+
+// InsufficientPayerBalanceError indicates that the balance of the payer of a transaction at its reference block does
+// not cover the minimum transaction fees.
+type InsufficientPayerBalanceError struct {
+	Address flow.Address
+	Balance uint64
+	Minimum uint64
+}
+
+func (e InsufficientPayerBalanceError) Error() string {
+	return fmt.Sprintf("payer (address: %s) balance (%d) is lower than the minimum balance (%d) at the reference block", e.Address, e.Balance, e.Minimum)
+}
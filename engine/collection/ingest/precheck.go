@@ -0,0 +1,266 @@
+package ingest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	lru "github.com/hashicorp/golang-lru"
+	"github.com/rs/zerolog"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/module/component"
+	"github.com/onflow/flow-go/module/irrecoverable"
+	"github.com/onflow/flow-go/utils/logging"
+)
+
+// balanceKey is the key of the cached balance of an account at a reference block.
+type balanceKey struct {
+	blockID flow.Identifier
+	address flow.Address
+}
+
+// balanceResult is the cached result of a balance lookup.
+type balanceResult struct {
+	balance uint64
+	err     error
+}
+
+// accountKeyKey is the key of the cached account key of an account at a reference block.
+type accountKeyKey struct {
+	blockID  flow.Identifier
+	address  flow.Address
+	keyIndex uint64
+}
+
+// accountKeyResult is the cached result of an account key lookup.
+type accountKeyResult struct {
+	key *flow.AccountPublicKey
+	err error
+}
+
+// preExecutionLookupQueueSize is the maximum number of account state lookups waiting for a lookup worker. The lookups
+// scheduled once the queue is full are dropped, and scheduled again by the next transactions of the accounts.
+const preExecutionLookupQueueSize = 1000
+
+// preExecutionChecker rejects the transactions which will clearly fail at execution, because their proposal key is
+// revoked, or its sequence number was already used or is implausibly far ahead, or because their payer can't afford
+// the transaction fees.
+//
+// The state of the accounts is read at the reference block of the transaction, and cached per reference block. As
+// the transaction is executed against a later state, only the results which hold for every later state are
+// definitive: a revoked key can't be restored, and a sequence number lower than the one of the key at the reference
+// block is already used. The payer balance is not definitive, as the payer may be funded after the reference block,
+// but a payer whose balance at the reference block does not cover the minimum fees is rejected: the reference block
+// is recent, and a payer funded since can submit the transaction again with a later reference block. Conversely, the
+// payer or the proposer may be created and the proposal key may be added after the reference block, so that an
+// unknown account or an unknown key at the reference block are inconclusive: they are logged, and the transaction is
+// let through.
+//
+// Reading the state never delays the ingestion of the transactions: the transactions are checked against the cached
+// state only, and the state missing from the cache is read in the background by the lookup workers, for the next
+// transactions of the same accounts and reference block. Hence the checks are skipped for the first transactions of
+// an account at a reference block, as well as when the state can't be read, e.g., because the reference block is not
+// executed yet, so that the availability of the source never prevents transactions from being ingested.
+type preExecutionChecker struct {
+	log         zerolog.Logger
+	reader      AccountStateReader
+	config      Config
+	balances    *lru.Cache
+	accountKeys *lru.Cache
+	lookups     chan interface{} // balanceKey or accountKeyKey of the state to read
+	pendingMu   sync.Mutex
+	pending     map[interface{}]struct{} // lookups scheduled and not completed yet
+}
+
+// newPreExecutionChecker creates a checker reading the state of the accounts from the reader.
+func newPreExecutionChecker(log zerolog.Logger, reader AccountStateReader, config Config) (*preExecutionChecker, error) {
+	if config.PreExecutionCheckWorkers == 0 {
+		return nil, fmt.Errorf("pre-execution checks require at least one lookup worker")
+	}
+	balances, err := lru.New(int(config.PreExecutionCheckCacheSize))
+	if err != nil {
+		return nil, fmt.Errorf("could not create balance cache: %w", err)
+	}
+	accountKeys, err := lru.New(int(config.PreExecutionCheckCacheSize))
+	if err != nil {
+		return nil, fmt.Errorf("could not create account key cache: %w", err)
+	}
+	return &preExecutionChecker{
+		log:         log.With().Str("component", "pre_execution_checker").Logger(),
+		reader:      reader,
+		config:      config,
+		balances:    balances,
+		accountKeys: accountKeys,
+		lookups:     make(chan interface{}, preExecutionLookupQueueSize),
+		pending:     make(map[interface{}]struct{}),
+	}, nil
+}
+
+// Check checks that the proposal key of the transaction is not revoked and that its sequence number is plausible, and
+// that the payer of the transaction can afford the transaction fees, against the cached state of the accounts. The
+// state missing from the cache is scheduled for lookup, and the corresponding checks are skipped.
+// Expected errors during normal operation:
+//   - InvalidProposalKeyError if the proposal key is revoked.
+//   - InvalidSequenceNumberError if the proposal key sequence number is already used or implausibly far ahead.
+//   - InsufficientPayerBalanceError if the payer balance is lower than the minimum payer balance.
+func (c *preExecutionChecker) Check(tx *flow.TransactionBody) error {
+	err := c.checkProposalKey(tx)
+	if err != nil {
+		return err
+	}
+	return c.checkPayerBalance(tx)
+}
+
+// checkProposalKey checks that the proposal key is not revoked, and that its sequence number is plausible. An unknown
+// proposer or proposal key is inconclusive.
+func (c *preExecutionChecker) checkProposalKey(tx *flow.TransactionBody) error {
+	proposer := tx.ProposalKey.Address
+	keyIndex := tx.ProposalKey.KeyIndex
+	cacheKey := accountKeyKey{blockID: tx.ReferenceBlockID, address: proposer, keyIndex: keyIndex}
+	cached, ok := c.accountKeys.Get(cacheKey)
+	if !ok {
+		c.schedule(tx, cacheKey)
+		return nil
+	}
+	result := cached.(accountKeyResult)
+	if errors.Is(result.err, ErrAccountNotFound) {
+		c.inconclusive(tx, "proposer does not exist at reference block")
+		return nil
+	}
+	if errors.Is(result.err, ErrAccountKeyNotFound) {
+		c.inconclusive(tx, "proposal key does not exist at reference block")
+		return nil
+	}
+
+	key := result.key
+	if key.Revoked {
+		return InvalidProposalKeyError{Address: proposer, KeyIndex: keyIndex}
+	}
+	seqNumber := tx.ProposalKey.SequenceNumber
+	if seqNumber < key.SeqNumber || seqNumber-key.SeqNumber > c.config.MaxSequenceNumberGap {
+		return InvalidSequenceNumberError{
+			Address:  proposer,
+			KeyIndex: keyIndex,
+			Current:  key.SeqNumber,
+			Actual:   seqNumber,
+		}
+	}
+	return nil
+}
+
+// checkPayerBalance checks that the balance of the payer covers the minimum transaction fees. As the payer may be
+// created after the reference block, an unknown payer is inconclusive.
+func (c *preExecutionChecker) checkPayerBalance(tx *flow.TransactionBody) error {
+	if c.config.MinPayerBalance == 0 {
+		return nil
+	}
+	cacheKey := balanceKey{blockID: tx.ReferenceBlockID, address: tx.Payer}
+	cached, ok := c.balances.Get(cacheKey)
+	if !ok {
+		c.schedule(tx, cacheKey)
+		return nil
+	}
+	result := cached.(balanceResult)
+	if errors.Is(result.err, ErrAccountNotFound) {
+		c.inconclusive(tx, "payer does not exist at reference block")
+		return nil
+	}
+	if result.balance < c.config.MinPayerBalance {
+		return InsufficientPayerBalanceError{
+			Address: tx.Payer,
+			Balance: result.balance,
+			Minimum: c.config.MinPayerBalance,
+		}
+	}
+	return nil
+}
+
+// inconclusive logs a pre-execution check failing at the reference block of the transaction, which may succeed at
+// execution.
+func (c *preExecutionChecker) inconclusive(tx *flow.TransactionBody, reason string) {
+	c.log.Debug().
+		Hex("tx_id", logging.Entity(tx)).
+		Hex("ref_block_id", tx.ReferenceBlockID[:]).
+		Str("reason", reason).
+		Msg("inconclusive pre-execution check, passing transaction through")
+}
+
+// schedule schedules the lookup of the state missing from the cache, unless it is already scheduled, or too many
+// lookups are pending.
+func (c *preExecutionChecker) schedule(tx *flow.TransactionBody, key interface{}) {
+	c.pendingMu.Lock()
+	defer c.pendingMu.Unlock()
+
+	lg := c.log.Debug().
+		Hex("tx_id", logging.Entity(tx)).
+		Hex("ref_block_id", tx.ReferenceBlockID[:])
+	if _, ok := c.pending[key]; ok {
+		lg.Msg("skipping pre-execution check, account state lookup pending")
+		return
+	}
+	select {
+	case c.lookups <- key:
+		c.pending[key] = struct{}{}
+		lg.Msg("skipping pre-execution check, account state lookup scheduled")
+	default:
+		lg.Msg("skipping pre-execution check, too many pending account state lookups")
+	}
+}
+
+// lookupAccountStates is a worker reading the state of the accounts scheduled for lookup.
+func (c *preExecutionChecker) lookupAccountStates(ctx irrecoverable.SignalerContext, ready component.ReadyFunc) {
+	ready()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case key := <-c.lookups:
+			c.lookup(ctx, key)
+		}
+	}
+}
+
+// lookup reads the state of the account from the reader, and caches the result, unless the state is unavailable,
+// which may change once the reference block is executed.
+func (c *preExecutionChecker) lookup(ctx context.Context, key interface{}) {
+	defer func() {
+		c.pendingMu.Lock()
+		delete(c.pending, key)
+		c.pendingMu.Unlock()
+	}()
+
+	ctx, cancel := context.WithTimeout(ctx, c.config.PreExecutionCheckTimeout)
+	defer cancel()
+
+	switch key := key.(type) {
+	case accountKeyKey:
+		accountKey, err := c.reader.AccountKey(ctx, key.blockID, key.address, key.keyIndex)
+		if err == nil || errors.Is(err, ErrAccountNotFound) || errors.Is(err, ErrAccountKeyNotFound) {
+			c.accountKeys.Add(key, accountKeyResult{key: accountKey, err: err})
+			return
+		}
+		c.unavailable(key.blockID, key.address, err)
+	case balanceKey:
+		balance, err := c.reader.Balance(ctx, key.blockID, key.address)
+		if err == nil || errors.Is(err, ErrAccountNotFound) {
+			c.balances.Add(key, balanceResult{balance: balance, err: err})
+			return
+		}
+		c.unavailable(key.blockID, key.address, err)
+	}
+}
+
+// unavailable logs that the state of an account could not be read.
+func (c *preExecutionChecker) unavailable(blockID flow.Identifier, address flow.Address, err error) {
+	lg := c.log.Debug()
+	if !errors.Is(err, ErrStateUnavailable) {
+		lg = c.log.Warn()
+	}
+	lg.Err(err).
+		Hex("ref_block_id", blockID[:]).
+		Str("address", address.String()).
+		Msg("could not read account state for pre-execution checks")
+}
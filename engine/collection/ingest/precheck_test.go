@@ -0,0 +1,222 @@
+package ingest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/onflow/flow/protobuf/go/flow/execution"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	accessmock "github.com/onflow/flow-go/engine/access/mock"
+	mockingest "github.com/onflow/flow-go/engine/collection/ingest/mock"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+// TestPreExecutionChecker evaluates that the checker rejects the transactions which will clearly fail at execution once
+// the state of their accounts was read, and lets the other transactions through, including when the state of the
+// accounts is not read yet or unavailable, or when the checks are inconclusive at the reference block.
+func TestPreExecutionChecker(t *testing.T) {
+	tx := unittest.TransactionBodyFixture()
+	tx.ProposalKey.SequenceNumber = 10
+	key := &flow.AccountPublicKey{Index: int(tx.ProposalKey.KeyIndex), SeqNumber: 10}
+
+	newChecker := func(t *testing.T, reader *mockingest.AccountStateReader) *preExecutionChecker {
+		config := DefaultConfig()
+		config.MinPayerBalance = 1000
+		config.MaxSequenceNumberGap = 5
+		checker, err := newPreExecutionChecker(zerolog.Nop(), reader, config)
+		require.NoError(t, err)
+		return checker
+	}
+	// lookup runs the scheduled account state lookups.
+	lookup := func(checker *preExecutionChecker) {
+		for len(checker.lookups) > 0 {
+			checker.lookup(context.Background(), <-checker.lookups)
+		}
+	}
+	// check checks the transaction once the state of its accounts was read, as the first check of the transaction
+	// only schedules the lookups.
+	check := func(t *testing.T, checker *preExecutionChecker, tx *flow.TransactionBody) error {
+		require.NoError(t, checker.Check(tx))
+		lookup(checker)
+		return checker.Check(tx)
+	}
+
+	t.Run("valid transaction", func(t *testing.T) {
+		reader := mockingest.NewAccountStateReader(t)
+		reader.On("AccountKey", mock.Anything, tx.ReferenceBlockID, tx.ProposalKey.Address, tx.ProposalKey.KeyIndex).Return(key, nil).Once()
+		reader.On("Balance", mock.Anything, tx.ReferenceBlockID, tx.Payer).Return(uint64(1000), nil).Once()
+		checker := newChecker(t, reader)
+
+		// the lookups are scheduled once, until they complete
+		require.NoError(t, checker.Check(&tx))
+		require.NoError(t, checker.Check(&tx))
+		assert.Len(t, checker.lookups, 2)
+		lookup(checker)
+		// the account state is cached per reference block
+		require.NoError(t, checker.Check(&tx))
+		require.NoError(t, checker.Check(&tx))
+		assert.Empty(t, checker.lookups)
+	})
+
+	t.Run("insufficient payer balance", func(t *testing.T) {
+		reader := mockingest.NewAccountStateReader(t)
+		reader.On("AccountKey", mock.Anything, tx.ReferenceBlockID, tx.ProposalKey.Address, tx.ProposalKey.KeyIndex).Return(key, nil).Once()
+		reader.On("Balance", mock.Anything, tx.ReferenceBlockID, tx.Payer).Return(uint64(999), nil).Once()
+		checker := newChecker(t, reader)
+
+		var balanceErr InsufficientPayerBalanceError
+		err := check(t, checker, &tx)
+		require.True(t, errors.As(err, &balanceErr))
+		assert.Equal(t, tx.Payer, balanceErr.Address)
+		assert.Equal(t, uint64(999), balanceErr.Balance)
+		assert.Equal(t, uint64(1000), balanceErr.Minimum)
+		assert.Empty(t, checker.lookups)
+	})
+
+	t.Run("sequence number already used", func(t *testing.T) {
+		reader := mockingest.NewAccountStateReader(t)
+		reader.On("Balance", mock.Anything, tx.ReferenceBlockID, tx.Payer).Return(uint64(1000), nil).Once()
+		used := *key
+		used.SeqNumber = 11
+		reader.On("AccountKey", mock.Anything, tx.ReferenceBlockID, tx.ProposalKey.Address, tx.ProposalKey.KeyIndex).Return(&used, nil).Once()
+
+		var seqErr InvalidSequenceNumberError
+		err := check(t, newChecker(t, reader), &tx)
+		require.True(t, errors.As(err, &seqErr))
+		assert.Equal(t, uint64(11), seqErr.Current)
+		assert.Equal(t, uint64(10), seqErr.Actual)
+	})
+
+	t.Run("sequence number too far ahead", func(t *testing.T) {
+		reader := mockingest.NewAccountStateReader(t)
+		reader.On("Balance", mock.Anything, tx.ReferenceBlockID, tx.Payer).Return(uint64(1000), nil).Once()
+		behind := *key
+		behind.SeqNumber = 4
+		reader.On("AccountKey", mock.Anything, tx.ReferenceBlockID, tx.ProposalKey.Address, tx.ProposalKey.KeyIndex).Return(&behind, nil).Once()
+
+		err := check(t, newChecker(t, reader), &tx)
+		assert.True(t, errors.As(err, &InvalidSequenceNumberError{}))
+	})
+
+	t.Run("revoked proposal key", func(t *testing.T) {
+		reader := mockingest.NewAccountStateReader(t)
+		reader.On("Balance", mock.Anything, tx.ReferenceBlockID, tx.Payer).Return(uint64(1000), nil).Once()
+		revoked := *key
+		revoked.Revoked = true
+		reader.On("AccountKey", mock.Anything, tx.ReferenceBlockID, tx.ProposalKey.Address, tx.ProposalKey.KeyIndex).Return(&revoked, nil).Once()
+
+		err := check(t, newChecker(t, reader), &tx)
+		assert.True(t, errors.As(err, &InvalidProposalKeyError{}))
+	})
+
+	t.Run("unknown proposal key", func(t *testing.T) {
+		reader := mockingest.NewAccountStateReader(t)
+		reader.On("Balance", mock.Anything, tx.ReferenceBlockID, tx.Payer).Return(uint64(1000), nil).Once()
+		reader.On("AccountKey", mock.Anything, tx.ReferenceBlockID, tx.ProposalKey.Address, tx.ProposalKey.KeyIndex).Return(nil, ErrAccountKeyNotFound).Once()
+
+		// the proposal key may be added after the reference block
+		require.NoError(t, check(t, newChecker(t, reader), &tx))
+	})
+
+	t.Run("unknown accounts", func(t *testing.T) {
+		reader := mockingest.NewAccountStateReader(t)
+		reader.On("AccountKey", mock.Anything, tx.ReferenceBlockID, tx.ProposalKey.Address, tx.ProposalKey.KeyIndex).Return(nil, ErrAccountNotFound).Once()
+		reader.On("Balance", mock.Anything, tx.ReferenceBlockID, tx.Payer).Return(uint64(0), ErrAccountNotFound).Once()
+		checker := newChecker(t, reader)
+
+		// the proposer and the payer may be created after the reference block
+		require.NoError(t, check(t, checker, &tx))
+		// unknown accounts are cached too
+		require.NoError(t, checker.Check(&tx))
+		assert.Empty(t, checker.lookups)
+	})
+
+	t.Run("unavailable state", func(t *testing.T) {
+		reader := mockingest.NewAccountStateReader(t)
+		reader.On("AccountKey", mock.Anything, tx.ReferenceBlockID, tx.ProposalKey.Address, tx.ProposalKey.KeyIndex).Return(nil, ErrStateUnavailable).Twice()
+		reader.On("Balance", mock.Anything, tx.ReferenceBlockID, tx.Payer).Return(uint64(0), fmt.Errorf("connection refused")).Twice()
+		checker := newChecker(t, reader)
+
+		// the checks are skipped, and the unavailable state is not cached, so that it is looked up again
+		require.NoError(t, check(t, checker, &tx))
+		assert.Len(t, checker.lookups, 2)
+		lookup(checker)
+	})
+}
+
+// TestRegisterAccountStateReader evaluates that the register reader decodes the account keys from the registers, and
+// tells unknown accounts and keys apart.
+func TestRegisterAccountStateReader(t *testing.T) {
+	blockID := unittest.IdentifierFixture()
+	address := unittest.RandomAddressFixture()
+	privateKey, err := unittest.AccountKeyDefaultFixture()
+	require.NoError(t, err)
+	publicKey := privateKey.PublicKey(1000)
+	publicKey.SeqNumber = 42
+	encoded, err := flow.EncodeAccountPublicKey(publicKey)
+	require.NoError(t, err)
+
+	registers := mockingest.NewRegisterReader(t)
+	registers.On("RegisterAtBlockID", mock.Anything, blockID, flow.PublicKeyRegisterID(address, 0)).Return(encoded, nil)
+	registers.On("RegisterAtBlockID", mock.Anything, blockID, flow.PublicKeyRegisterID(address, 1)).Return(nil, nil)
+	registers.On("RegisterAtBlockID", mock.Anything, blockID, flow.AccountStatusRegisterID(address)).Return([]byte{0}, nil)
+	unknown := unittest.RandomAddressFixture()
+	registers.On("RegisterAtBlockID", mock.Anything, blockID, flow.PublicKeyRegisterID(unknown, 0)).Return(nil, nil)
+	registers.On("RegisterAtBlockID", mock.Anything, blockID, flow.AccountStatusRegisterID(unknown)).Return(nil, nil)
+	reader := NewRegisterAccountStateReader(registers)
+
+	key, err := reader.AccountKey(context.Background(), blockID, address, 0)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(42), key.SeqNumber)
+	assert.True(t, key.PublicKey.Equals(publicKey.PublicKey))
+
+	_, err = reader.AccountKey(context.Background(), blockID, address, 1)
+	assert.ErrorIs(t, err, ErrAccountKeyNotFound)
+	_, err = reader.AccountKey(context.Background(), blockID, unknown, 0)
+	assert.ErrorIs(t, err, ErrAccountNotFound)
+	_, err = reader.Balance(context.Background(), blockID, address)
+	assert.ErrorIs(t, err, ErrStateUnavailable)
+}
+
+// TestExecutionNodeAccountStateReader evaluates that the execution node reader tells unknown accounts apart from
+// blocks which are not executed yet, when the execution API does not find the account.
+func TestExecutionNodeAccountStateReader(t *testing.T) {
+	blockID := unittest.IdentifierFixture()
+	address := unittest.RandomAddressFixture()
+	statusRequest := &execution.GetRegisterAtBlockIDRequest{
+		BlockId:       blockID[:],
+		RegisterOwner: []byte(flow.AccountStatusRegisterID(address).Owner),
+		RegisterKey:   []byte(flow.AccountStatusRegisterID(address).Key),
+	}
+	newClient := func(t *testing.T) *accessmock.ExecutionAPIClient {
+		client := accessmock.NewExecutionAPIClient(t)
+		client.On("GetAccountAtBlockID", mock.Anything, mock.Anything).Return(nil, status.Error(codes.NotFound, "account not found")).Once()
+		return client
+	}
+
+	t.Run("unknown account", func(t *testing.T) {
+		client := newClient(t)
+		client.On("GetRegisterAtBlockID", mock.Anything, statusRequest).Return(&execution.GetRegisterAtBlockIDResponse{}, nil).Once()
+
+		_, err := NewExecutionNodeAccountStateReader(client).Balance(context.Background(), blockID, address)
+		assert.ErrorIs(t, err, ErrAccountNotFound)
+	})
+
+	t.Run("block not executed", func(t *testing.T) {
+		client := newClient(t)
+		client.On("GetRegisterAtBlockID", mock.Anything, statusRequest).Return(nil, status.Error(codes.Internal, "state commitment not found")).Once()
+
+		_, err := NewExecutionNodeAccountStateReader(client).Balance(context.Background(), blockID, address)
+		assert.ErrorIs(t, err, ErrStateUnavailable)
+		assert.NotErrorIs(t, err, ErrAccountNotFound)
+	})
+}
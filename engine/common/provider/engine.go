@@ -17,6 +17,7 @@ import (
 	"github.com/onflow/flow-go/module/irrecoverable"
 	"github.com/onflow/flow-go/module/metrics"
 	"github.com/onflow/flow-go/network"
+	"github.com/onflow/flow-go/network/alsp"
 	"github.com/onflow/flow-go/network/channels"
 	"github.com/onflow/flow-go/state/protocol"
 	"github.com/onflow/flow-go/storage"
@@ -257,7 +258,7 @@ func (e *Engine) onEntityRequest(request *internal.EntityRequest) error {
 		EntityIDs: entityIDs,
 		Blobs:     blobs,
 	}
-	err = e.con.Unicast(res, request.OriginId)
+	err = e.con.Respond(res, request.OriginId)
 	if err != nil {
 		return engine.NewNetworkTransmissionErrorf("could not send entity response: %w", err)
 	}
@@ -343,7 +344,11 @@ func (e *Engine) processEntityRequestWorker(ctx irrecoverable.SignalerContext, r
 		lg.Trace().Msg("worker picked up entity request for processing")
 		err := e.onEntityRequest(request)
 		if err != nil {
-			if engine.IsInvalidInputError(err) || engine.IsNetworkTransmissionError(err) {
+			if engine.IsInvalidInputError(err) {
+				lg.Error().Err(err).Bool(logging.KeySuspicious, true).Msg("worker could not process invalid entity request")
+				// report the requester to the networking layer, so that repeated invalid requests are penalized
+				e.con.ReportMisbehavior(alsp.MustNewMisbehaviorReport(request.OriginId, alsp.InvalidMessage))
+			} else if engine.IsNetworkTransmissionError(err) {
 				lg.Error().Err(err).Msg("worker could not process entity request")
 			} else {
 				// this is an unexpected error, we crash the node.
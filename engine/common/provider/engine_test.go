@@ -19,6 +19,8 @@ import (
 	"github.com/onflow/flow-go/module/mempool/queue"
 	"github.com/onflow/flow-go/module/metrics"
 	mockmodule "github.com/onflow/flow-go/module/mock"
+	"github.com/onflow/flow-go/network"
+	"github.com/onflow/flow-go/network/alsp"
 	"github.com/onflow/flow-go/network/channels"
 	"github.com/onflow/flow-go/network/mocknetwork"
 	protocol "github.com/onflow/flow-go/state/protocol/mock"
@@ -71,7 +73,7 @@ func TestOnEntityRequestFull(t *testing.T) {
 	net := mocknetwork.NewNetwork(t)
 	con := mocknetwork.NewConduit(t)
 	net.On("Register", mock.Anything, mock.Anything).Return(con, nil)
-	con.On("Unicast", mock.Anything, mock.Anything).Run(
+	con.On("Respond", mock.Anything, mock.Anything).Run(
 		func(args mock.Arguments) {
 			defer cancel()
 
@@ -165,7 +167,7 @@ func TestOnEntityRequestPartial(t *testing.T) {
 	net := mocknetwork.NewNetwork(t)
 	con := mocknetwork.NewConduit(t)
 	net.On("Register", mock.Anything, mock.Anything).Return(con, nil)
-	con.On("Unicast", mock.Anything, mock.Anything).Run(
+	con.On("Respond", mock.Anything, mock.Anything).Run(
 		func(args mock.Arguments) {
 			defer cancel()
 
@@ -253,7 +255,7 @@ func TestOnEntityRequestDuplicates(t *testing.T) {
 	net := mocknetwork.NewNetwork(t)
 	con := mocknetwork.NewConduit(t)
 	net.On("Register", mock.Anything, mock.Anything).Return(con, nil)
-	con.On("Unicast", mock.Anything, mock.Anything).Run(
+	con.On("Respond", mock.Anything, mock.Anything).Run(
 		func(args mock.Arguments) {
 			defer cancel()
 
@@ -338,7 +340,7 @@ func TestOnEntityRequestEmpty(t *testing.T) {
 	net := mocknetwork.NewNetwork(t)
 	con := mocknetwork.NewConduit(t)
 	net.On("Register", mock.Anything, mock.Anything).Return(con, nil)
-	con.On("Unicast", mock.Anything, mock.Anything).Run(
+	con.On("Respond", mock.Anything, mock.Anything).Run(
 		func(args mock.Arguments) {
 			defer cancel()
 
@@ -423,6 +425,10 @@ func TestOnEntityRequestInvalidOrigin(t *testing.T) {
 	net := mocknetwork.NewNetwork(t)
 	con := mocknetwork.NewConduit(t)
 	net.On("Register", mock.Anything, mock.Anything).Return(con, nil)
+	// the requester is not authorized, hence it should be reported as misbehaving
+	con.On("ReportMisbehavior", mock.MatchedBy(func(report network.MisbehaviorReport) bool {
+		return report.OriginId() == originID && report.Reason() == alsp.InvalidMessage
+	})).Once()
 	me := mockmodule.NewLocal(t)
 	me.On("NodeID").Return(unittest.IdentifierFixture())
 	requestQueue := queue.NewHeroStore(10, unittest.Logger(), metrics.NewNoopCollector())
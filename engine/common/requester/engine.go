@@ -1,6 +1,8 @@
 package requester
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"math"
 	"math/rand"
@@ -17,6 +19,7 @@ import (
 	"github.com/onflow/flow-go/module"
 	"github.com/onflow/flow-go/module/metrics"
 	"github.com/onflow/flow-go/network"
+	"github.com/onflow/flow-go/network/alsp"
 	"github.com/onflow/flow-go/network/channels"
 	"github.com/onflow/flow-go/state/protocol"
 	"github.com/onflow/flow-go/utils/logging"
@@ -49,7 +52,6 @@ type Engine struct {
 
 	// changing the following state variables must be guarded by unit.Lock()
 	items                 map[flow.Identifier]*Item
-	requests              map[uint64]*messages.EntityRequest
 	forcedDispatchOngoing *atomic.Bool // to ensure only trigger dispatching logic once at any time
 	rng                   *rand.Rand
 }
@@ -114,8 +116,7 @@ func New(log zerolog.Logger, metrics module.EngineMetrics, net network.Network,
 		selector:              selector,
 		create:                create,
 		handle:                nil,
-		items:                 make(map[flow.Identifier]*Item),          // holds all pending items
-		requests:              make(map[uint64]*messages.EntityRequest), // holds all sent requests
+		items:                 make(map[flow.Identifier]*Item), // holds all pending items
 		forcedDispatchOngoing: atomic.NewBool(false),
 		rng:                   rand.New(rand.NewSource(time.Now().UnixNano())),
 	}
@@ -190,7 +191,9 @@ func (e *Engine) ProcessLocal(message interface{}) error {
 // a blocking manner. It returns the potential processing error when done.
 func (e *Engine) Process(channel channels.Channel, originID flow.Identifier, message interface{}) error {
 	return e.unit.Do(func() error {
-		return e.process(originID, message)
+		err := e.process(originID, message)
+		e.reportInvalidInput(originID, err)
+		return err
 	})
 }
 
@@ -396,14 +399,12 @@ func (e *Engine) dispatchRequest() (bool, error) {
 		return false, nil
 	}
 
-	// create a batch request, send it and store it for reference
+	// create a batch request and send it; the response is awaited concurrently
 	req := &messages.EntityRequest{
 		Nonce:     e.rng.Uint64(),
 		EntityIDs: entityIDs,
 	}
 
-	requestStart := time.Now()
-
 	if e.log.Debug().Enabled() {
 		e.log.Debug().
 			Hex("provider", logging.ID(providerID)).
@@ -413,41 +414,53 @@ func (e *Engine) dispatchRequest() (bool, error) {
 			Msg("sending entity request")
 	}
 
-	err = e.con.Unicast(req, providerID)
-	if err != nil {
-		return true, fmt.Errorf("could not send request for entities %v: %w", logging.IDs(entityIDs), err)
-	}
-	e.requests[req.Nonce] = req
-
-	if e.log.Debug().Enabled() {
-		e.log.Debug().
-			Hex("provider", logging.ID(providerID)).
-			Uint64("nonce", req.Nonce).
-			Strs("entities", logging.IDs(entityIDs)).
-			TimeDiff("duration", time.Now(), requestStart).
-			Msg("entity request sent")
-	}
+	e.unit.Launch(func() {
+		e.request(providerID, req)
+	})
 
-	// NOTE: we forget about requests after the expiry of the shortest retry time
-	// from the entities in the list; this means that we purge requests aggressively.
-	// However, most requests should be responded to on the first attempt and clearing
-	// these up only removes the ability to instantly retry upon partial responses, so
-	// it won't affect much.
-	go func() {
-		<-time.After(e.cfg.RetryInitial)
+	e.metrics.MessageSent(e.channel.String(), metrics.MessageEntityRequest)
 
-		e.unit.Lock()
-		defer e.unit.Unlock()
-		delete(e.requests, req.Nonce)
-	}()
+	return true, nil
+}
 
-	e.metrics.MessageSent(e.channel.String(), metrics.MessageEntityRequest)
-	e.log.Debug().
+// request sends the entity request to the provider and processes its response.
+// NOTE: we wait for the response until the expiry of the shortest retry time from the entities in the list; a
+// response arriving later is processed as an unsolicited response, which only removes the ability to instantly retry
+// the entities missing from the response. Most requests should be responded to on the first attempt, so it won't
+// affect much.
+func (e *Engine) request(providerID flow.Identifier, req *messages.EntityRequest) {
+	lg := e.log.With().
+		Hex("provider", logging.ID(providerID)).
 		Uint64("nonce", req.Nonce).
 		Strs("entity_ids", flow.IdentifierList(req.EntityIDs).Strings()).
-		Msg("entity request sent")
+		Logger()
 
-	return true, nil
+	ctx, cancel := context.WithTimeout(e.unit.Ctx(), e.cfg.RetryInitial)
+	defer cancel()
+
+	requestStart := time.Now()
+	res, err := network.RequestAs[*messages.EntityResponse](ctx, e.con, req, providerID)
+	if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, context.Canceled) {
+		lg.Debug().Err(err).Msg("no entity response received in time")
+		return
+	}
+	if err != nil {
+		lg.Error().Err(err).Msg("could not request entities")
+		return
+	}
+
+	lg.Debug().
+		TimeDiff("duration", time.Now(), requestStart).
+		Msg("entity response received for request")
+
+	e.metrics.MessageReceived(e.channel.String(), metrics.MessageEntityResponse)
+	defer e.metrics.MessageHandled(e.channel.String(), metrics.MessageEntityResponse)
+
+	err = e.onEntityResponse(providerID, res, req)
+	if err != nil {
+		e.reportInvalidInput(providerID, err)
+		engine.LogError(e.log, err)
+	}
 }
 
 // process processes events for the propagation engine on the consensus node.
@@ -458,13 +471,16 @@ func (e *Engine) process(originID flow.Identifier, message interface{}) error {
 
 	switch msg := message.(type) {
 	case *messages.EntityResponse:
-		return e.onEntityResponse(originID, msg)
+		// the response was not awaited by its request (e.g., it arrived after the request timed out)
+		return e.onEntityResponse(originID, msg, nil)
 	default:
 		return engine.NewInvalidInputErrorf("invalid message type (%T)", message)
 	}
 }
 
-func (e *Engine) onEntityResponse(originID flow.Identifier, res *messages.EntityResponse) error {
+// onEntityResponse processes the response of a provider to the given entity request, or to an unknown request if req
+// is nil (in which case the entities missing from the response can't be re-queued).
+func (e *Engine) onEntityResponse(originID flow.Identifier, res *messages.EntityResponse, req *messages.EntityRequest) error {
 	lg := e.log.With().Str("origin_id", originID.String()).Uint64("nonce", res.Nonce).Logger()
 
 	lg.Debug().Strs("entity_ids", flow.IdentifierList(res.EntityIDs).Strings()).Msg("entity response received")
@@ -498,9 +514,7 @@ func (e *Engine) onEntityResponse(originID flow.Identifier, res *messages.Entity
 	// build a list of needed entities; if not available, process anyway,
 	// but in that case we can't re-queue missing items
 	needed := make(map[flow.Identifier]struct{})
-	req, exists := e.requests[res.Nonce]
-	if exists {
-		delete(e.requests, req.Nonce)
+	if req != nil {
 		for _, entityID := range req.EntityIDs {
 			needed[entityID] = struct{}{}
 		}
@@ -529,7 +543,7 @@ func (e *Engine) onEntityResponse(originID flow.Identifier, res *messages.Entity
 		entity := e.create()
 		err := msgpack.Unmarshal(blob, &entity)
 		if err != nil {
-			return fmt.Errorf("could not decode entity: %w", err)
+			return engine.NewInvalidInputErrorf("could not decode entity: %w", err)
 		}
 
 		if item.checkIntegrity {
@@ -574,3 +588,13 @@ func (e *Engine) onEntityResponse(originID flow.Identifier, res *messages.Entity
 
 	return nil
 }
+
+// reportInvalidInput reports the provider to the networking layer if the given error indicates that the provider sent
+// an invalid message, so that repeatedly misbehaving providers are eventually disallow-listed. Any other error is
+// ignored, as it does not imply a misbehavior of the provider.
+func (e *Engine) reportInvalidInput(originID flow.Identifier, err error) {
+	if !engine.IsInvalidInputError(err) {
+		return
+	}
+	e.con.ReportMisbehavior(alsp.MustNewMisbehaviorReport(originID, alsp.InvalidMessage))
+}
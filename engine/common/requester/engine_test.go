@@ -1,6 +1,7 @@
 package requester
 
 import (
+	"context"
 	"math/rand"
 	"testing"
 	"time"
@@ -19,6 +20,8 @@ import (
 	"github.com/onflow/flow-go/model/flow/filter"
 	"github.com/onflow/flow-go/model/messages"
 	"github.com/onflow/flow-go/module/metrics"
+	"github.com/onflow/flow-go/network"
+	"github.com/onflow/flow-go/network/alsp"
 	"github.com/onflow/flow-go/network/mocknetwork"
 	protocol "github.com/onflow/flow-go/state/protocol/mock"
 	"github.com/onflow/flow-go/utils/unittest"
@@ -114,18 +117,15 @@ func TestDispatchRequestVarious(t *testing.T) {
 	items[triedRecently.EntityID] = triedRecently
 	items[triedTwice.EntityID] = triedTwice
 
-	var nonce uint64
-
 	con := &mocknetwork.Conduit{}
-	con.On("Unicast", mock.Anything, mock.Anything).Run(
+	con.On("Request", mock.Anything, mock.Anything, mock.Anything).Run(
 		func(args mock.Arguments) {
-			request := args.Get(0).(*messages.EntityRequest)
-			originID := args.Get(1).(flow.Identifier)
-			nonce = request.Nonce
+			request := args.Get(1).(*messages.EntityRequest)
+			originID := args.Get(2).(flow.Identifier)
 			assert.Equal(t, originID, targetID)
 			assert.ElementsMatch(t, request.EntityIDs, []flow.Identifier{justAdded.EntityID, triedAnciently.EntityID})
 		},
-	).Return(nil)
+	).Return(nil, context.DeadlineExceeded)
 
 	request := Engine{
 		unit:     engine.NewUnit(),
@@ -134,7 +134,6 @@ func TestDispatchRequestVarious(t *testing.T) {
 		state:    state,
 		con:      con,
 		items:    items,
-		requests: make(map[uint64]*messages.EntityRequest),
 		selector: filter.HasNodeID(targetID),
 		rng:      rand.New(rand.NewSource(0)),
 	}
@@ -142,18 +141,101 @@ func TestDispatchRequestVarious(t *testing.T) {
 	require.NoError(t, err)
 	require.True(t, dispatched)
 
+	// the request is sent asynchronously, and the unit is done once it timed out
+	unittest.AssertClosesBefore(t, request.unit.Done(), time.Second)
 	con.AssertExpectations(t)
 
-	request.unit.Lock()
-	assert.Contains(t, request.requests, nonce)
-	request.unit.Unlock()
+	// the requested items are scheduled for retry, and the item tried twice is dropped
+	assert.Equal(t, uint(1), justAdded.NumAttempts)
+	assert.Equal(t, uint(2), triedAnciently.NumAttempts)
+	assert.Equal(t, uint(1), triedRecently.NumAttempts)
+	assert.NotContains(t, request.items, triedTwice.EntityID)
+}
+
+// TestDispatchRequestResponse evaluates that the response to a dispatched request is processed, and that the entities
+// missing from the response are scheduled for an immediate retry.
+func TestDispatchRequestResponse(t *testing.T) {
+
+	identities := unittest.IdentityListFixture(4)
+	targetID := identities[0].NodeID
+
+	final := &protocol.Snapshot{}
+	final.On("Identities", mock.Anything).Return(
+		func(selector flow.IdentityFilter) flow.IdentityList {
+			return identities.Filter(selector)
+		},
+		nil,
+	)
+
+	state := &protocol.State{}
+	state.On("Final").Return(final)
+
+	cfg := Config{
+		BatchInterval:  24 * time.Hour,
+		BatchThreshold: 999,
+		RetryInitial:   24 * time.Hour,
+		RetryFunction:  RetryLinear(1),
+		RetryAttempts:  2,
+		RetryMaximum:   24 * time.Hour,
+	}
+
+	available := unittest.CollectionFixture(1)
+	unavailable := unittest.CollectionFixture(2)
+	items := make(map[flow.Identifier]*Item)
+	for _, entityID := range []flow.Identifier{available.ID(), unavailable.ID()} {
+		items[entityID] = &Item{
+			EntityID:      entityID,
+			RetryAfter:    cfg.RetryInitial,
+			ExtraSelector: filter.Any,
+		}
+	}
+	bavailable, _ := msgpack.Marshal(available)
+
+	con := &mocknetwork.Conduit{}
+	con.On("Request", mock.Anything, mock.Anything, targetID).Return(
+		func(_ context.Context, request network.RequestMessage, _ flow.Identifier) network.ResponseMessage {
+			return &messages.EntityResponse{
+				Nonce:     request.RequestID(),
+				EntityIDs: []flow.Identifier{available.ID()},
+				Blobs:     [][]byte{bavailable},
+			}
+		},
+		nil,
+	)
+
+	handled := make(chan flow.Entity, 1)
+	request := Engine{
+		unit:     engine.NewUnit(),
+		metrics:  metrics.NewNoopCollector(),
+		cfg:      cfg,
+		state:    state,
+		con:      con,
+		items:    items,
+		selector: filter.HasNodeID(targetID),
+		create:   func() flow.Entity { return &flow.Collection{} },
+		handle: func(_ flow.Identifier, entity flow.Entity) {
+			handled <- entity
+		},
+		rng: rand.New(rand.NewSource(0)),
+	}
+	dispatched, err := request.dispatchRequest()
+	require.NoError(t, err)
+	require.True(t, dispatched)
+
+	unittest.AssertClosesBefore(t, request.unit.Done(), time.Second)
+	con.AssertExpectations(t)
 
-	// TODO: racy/slow test
-	time.Sleep(2 * cfg.RetryInitial)
+	select {
+	case entity := <-handled:
+		assert.Equal(t, available.ID(), entity.ID())
+	case <-time.After(time.Second):
+		t.Fatal("entity was not handled")
+	}
 
-	request.unit.Lock()
-	assert.NotContains(t, request.requests, nonce)
-	request.unit.Unlock()
+	// the available entity is received, and the unavailable one is retried right away
+	assert.NotContains(t, request.items, available.ID())
+	require.Contains(t, request.items, unavailable.ID())
+	assert.Equal(t, time.Time{}, request.items[unavailable.ID()].LastRequested)
 }
 
 func TestDispatchRequestBatchSize(t *testing.T) {
@@ -197,12 +279,12 @@ func TestDispatchRequestBatchSize(t *testing.T) {
 	}
 
 	con := &mocknetwork.Conduit{}
-	con.On("Unicast", mock.Anything, mock.Anything).Run(
+	con.On("Request", mock.Anything, mock.Anything, mock.Anything).Run(
 		func(args mock.Arguments) {
-			request := args.Get(0).(*messages.EntityRequest)
+			request := args.Get(1).(*messages.EntityRequest)
 			assert.Len(t, request.EntityIDs, int(batchLimit))
 		},
-	).Return(nil)
+	).Return(nil, context.DeadlineExceeded)
 
 	request := Engine{
 		unit:     engine.NewUnit(),
@@ -211,7 +293,6 @@ func TestDispatchRequestBatchSize(t *testing.T) {
 		state:    state,
 		con:      con,
 		items:    items,
-		requests: make(map[uint64]*messages.EntityRequest),
 		selector: filter.Any,
 		rng:      rand.New(rand.NewSource(0)),
 	}
@@ -219,6 +300,7 @@ func TestDispatchRequestBatchSize(t *testing.T) {
 	require.NoError(t, err)
 	require.True(t, dispatched)
 
+	unittest.AssertClosesBefore(t, request.unit.Done(), time.Second)
 	con.AssertExpectations(t)
 }
 
@@ -285,7 +367,6 @@ func TestOnEntityResponseValid(t *testing.T) {
 		metrics:  metrics.NewNoopCollector(),
 		state:    state,
 		items:    make(map[flow.Identifier]*Item),
-		requests: make(map[uint64]*messages.EntityRequest),
 		selector: filter.HasNodeID(targetID),
 		create:   func() flow.Entity { return &flow.Collection{} },
 		handle: func(flow.Identifier, flow.Entity) {
@@ -300,14 +381,9 @@ func TestOnEntityResponseValid(t *testing.T) {
 	request.items[iwanted2.EntityID] = iwanted2
 	request.items[iunavailable.EntityID] = iunavailable
 
-	request.requests[req.Nonce] = req
-
-	err := request.onEntityResponse(targetID, res)
+	err := request.onEntityResponse(targetID, res, req)
 	assert.NoError(t, err)
 
-	// check that the request was removed
-	assert.NotContains(t, request.requests, nonce)
-
 	// check that the provided items were removed
 	assert.NotContains(t, request.items, wanted1.ID())
 	assert.NotContains(t, request.items, wanted2.ID())
@@ -373,7 +449,6 @@ func TestOnEntityIntegrityCheck(t *testing.T) {
 		metrics:  metrics.NewNoopCollector(),
 		state:    state,
 		items:    make(map[flow.Identifier]*Item),
-		requests: make(map[uint64]*messages.EntityRequest),
 		selector: filter.HasNodeID(targetID),
 		create:   func() flow.Entity { return &flow.Collection{} },
 		handle:   func(flow.Identifier, flow.Entity) { close(called) },
@@ -382,22 +457,16 @@ func TestOnEntityIntegrityCheck(t *testing.T) {
 
 	request.items[iwanted.EntityID] = iwanted
 
-	request.requests[req.Nonce] = req
-
-	err := request.onEntityResponse(targetID, res)
+	err := request.onEntityResponse(targetID, res, req)
 	assert.NoError(t, err)
 
-	// check that the request was removed
-	assert.NotContains(t, request.requests, nonce)
-
 	// check that the provided item wasn't removed
 	assert.Contains(t, request.items, wanted.ID())
 
 	iwanted.checkIntegrity = false
 	request.items[iwanted.EntityID] = iwanted
-	request.requests[req.Nonce] = req
 
-	err = request.onEntityResponse(targetID, res)
+	err = request.onEntityResponse(targetID, res, req)
 	assert.NoError(t, err)
 
 	// make sure we process item without checking integrity
@@ -477,17 +546,86 @@ func TestOriginValidation(t *testing.T) {
 	})
 
 	e.items[iwanted.EntityID] = iwanted
-	e.requests[req.Nonce] = req
 
-	err = e.onEntityResponse(wrongID, res)
+	err = e.onEntityResponse(wrongID, res, req)
 	assert.Error(t, err)
 	assert.IsType(t, engine.InvalidInputError{}, err)
 
 	e.cfg.ValidateStaking = false
 
-	err = e.onEntityResponse(wrongID, res)
+	err = e.onEntityResponse(wrongID, res, req)
 	assert.NoError(t, err)
 
 	// handler are called async, but this should be extremely quick
 	unittest.AssertClosesBefore(t, called, time.Second)
 }
+
+// TestMisbehaviorReporting tests that providers sending invalid responses are reported to the networking layer,
+// while valid responses do not lead to a report.
+func TestMisbehaviorReporting(t *testing.T) {
+	identities := unittest.IdentityListFixture(16)
+	targetID := identities[0].NodeID
+	wrongID := identities[1].NodeID
+
+	final := &protocol.Snapshot{}
+	final.On("Identities", mock.Anything).Return(
+		func(selector flow.IdentityFilter) flow.IdentityList {
+			return identities.Filter(selector)
+		},
+		nil,
+	)
+
+	state := &protocol.State{}
+	state.On("Final").Return(final)
+
+	me := &module.Local{}
+	me.On("NodeID").Return(identities[3].NodeID)
+
+	con := mocknetwork.NewConduit(t)
+	net := &mocknetwork.Network{}
+	net.On("Register", mock.Anything, mock.Anything).Return(con, nil)
+
+	e, err := New(
+		zerolog.Nop(),
+		metrics.NewNoopCollector(),
+		net,
+		me,
+		state,
+		"",
+		filter.HasNodeID(targetID),
+		func() flow.Entity { return &flow.Collection{} },
+	)
+	require.NoError(t, err)
+	e.WithHandle(func(flow.Identifier, flow.Entity) {})
+
+	reported := func(originID flow.Identifier) interface{} {
+		return mock.MatchedBy(func(report network.MisbehaviorReport) bool {
+			return report.OriginId() == originID && report.Reason() == alsp.InvalidMessage
+		})
+	}
+
+	// a response from a node which is not a valid provider is reported
+	wanted := unittest.CollectionFixture(1)
+	bwanted, _ := msgpack.Marshal(wanted)
+	res := &messages.EntityResponse{
+		Nonce:     rand.Uint64(),
+		EntityIDs: []flow.Identifier{wanted.ID()},
+		Blobs:     [][]byte{bwanted},
+	}
+	con.On("ReportMisbehavior", reported(wrongID)).Once()
+	err = e.Process("", wrongID, res)
+	require.True(t, engine.IsInvalidInputError(err))
+
+	// a malformed response from a valid provider is reported
+	malformed := &messages.EntityResponse{
+		Nonce:     rand.Uint64(),
+		EntityIDs: []flow.Identifier{wanted.ID()},
+	}
+	con.On("ReportMisbehavior", reported(targetID)).Once()
+	err = e.Process("", targetID, malformed)
+	require.True(t, engine.IsInvalidInputError(err))
+
+	// a valid response from a valid provider is not reported
+	err = e.Process("", targetID, res)
+	require.NoError(t, err)
+}
@@ -48,17 +48,21 @@ func ConvertError(err error, msg string, defaultCode codes.Code) error {
 }
 
 // ConvertStorageError converts a generic error into a grpc status error, converting storage errors
-// into codes.NotFound
+// into codes.NotFound, and errors about pruned data into codes.OutOfRange
 func ConvertStorageError(err error) error {
 	if err == nil {
 		return nil
 	}
 
 	// Already converted
-	if status.Code(err) == codes.NotFound {
+	if code := status.Code(err); code == codes.NotFound || code == codes.OutOfRange {
 		return err
 	}
 
+	if errors.Is(err, storage.ErrPruned) {
+		return status.Errorf(codes.OutOfRange, "data has been pruned: %v", err)
+	}
+
 	if errors.Is(err, storage.ErrNotFound) {
 		return status.Errorf(codes.NotFound, "not found: %v", err)
 	}
@@ -70,6 +70,32 @@ func TestConvertError(t *testing.T) {
 	})
 }
 
+func TestConvertStorageError(t *testing.T) {
+	t.Run("no error", func(t *testing.T) {
+		err := ConvertStorageError(nil)
+		assert.NoError(t, err)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		err := ConvertStorageError(fmt.Errorf("could not get block: %w", storage.ErrNotFound))
+		assert.Equal(t, codes.NotFound, status.Code(err))
+	})
+
+	t.Run("pruned", func(t *testing.T) {
+		err := ConvertStorageError(fmt.Errorf("could not get block: %w", storage.ErrPruned))
+		assert.Equal(t, codes.OutOfRange, status.Code(err))
+		assert.ErrorContains(t, err, "data has been pruned")
+
+		// already converted errors are kept
+		assert.Equal(t, err, ConvertStorageError(err))
+	})
+
+	t.Run("unexpected error", func(t *testing.T) {
+		err := ConvertStorageError(fmt.Errorf("unexpected error"))
+		assert.Equal(t, codes.Internal, status.Code(err))
+	})
+}
+
 func TestConvertMultiError(t *testing.T) {
 	defaultCode := codes.Internal
 	t.Run("single error", func(t *testing.T) {
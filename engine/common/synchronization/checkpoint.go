@@ -0,0 +1,491 @@
+package synchronization
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/onflow/flow-go/consensus/hotstuff"
+	"github.com/onflow/flow-go/consensus/hotstuff/model"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/model/flow/filter"
+	"github.com/onflow/flow-go/model/flow/mapfunc"
+	"github.com/onflow/flow-go/model/flow/order"
+	"github.com/onflow/flow-go/state/protocol"
+	"github.com/onflow/flow-go/state/protocol/inmem"
+)
+
+// CheckpointConsumer consumes a protocol state snapshot (checkpoint), which was
+// received from a peer and successfully verified by the CheckpointVerifier.
+// Implementations are expected to persist the snapshot and restart the node, so it
+// re-bootstraps its protocol state from the checkpoint. If the checkpoint can't be
+// persisted, an error is returned, and the synchronization engine continues with
+// regular sync and requests a checkpoint from other peers.
+type CheckpointConsumer func(snapshot *inmem.Snapshot) error
+
+// ErrUnverifiableCheckpoint is returned when a checkpoint can not be verified
+// against our local knowledge of the epoch committees, for example because it
+// refers to an epoch we don't know yet. This does not imply the checkpoint is
+// forged; the node has to fall back to regular block-by-block synchronization.
+var ErrUnverifiableCheckpoint = errors.New("checkpoint can not be verified with local committee knowledge")
+
+// InvalidCheckpointError indicates that a checkpoint served by a peer is
+// invalid: it is either inconsistent or not backed by valid QCs of the
+// consensus committee. Peers serving invalid checkpoints are faulty.
+type InvalidCheckpointError struct {
+	err error
+}
+
+func NewInvalidCheckpointErrorf(msg string, args ...interface{}) error {
+	return InvalidCheckpointError{
+		err: fmt.Errorf(msg, args...),
+	}
+}
+
+func (e InvalidCheckpointError) Error() string {
+	return fmt.Sprintf("invalid checkpoint: %s", e.err.Error())
+}
+
+func (e InvalidCheckpointError) Unwrap() error {
+	return e.err
+}
+
+// IsInvalidCheckpointError returns whether the given error is an InvalidCheckpointError.
+func IsInvalidCheckpointError(err error) bool {
+	var e InvalidCheckpointError
+	return errors.As(err, &e)
+}
+
+// CheckpointVerifier verifies protocol state snapshots served by peers during
+// checkpoint-based fast sync. A checkpoint is accepted only if:
+//   - the sealing segment is well-formed, i.e. the blocks form a chain by parent ID and
+//     height, every block's payload matches its payload hash, and the segment is valid,
+//   - the latest seal and result of the snapshot are the ones of the sealing segment,
+//   - the parameters and epochs of the snapshot match our local protocol state, and the
+//     epoch phase and identities are consistent with the epochs,
+//   - the head of the snapshot is certified by a QC of the consensus committee, and
+//   - the head is finalized, which is proven by a certified direct child of the head with
+//     a view exactly one higher (2-chain finalization rule of HotStuff).
+//
+// All QCs are validated against the consensus committee as known by our local protocol
+// state. Hence, a node can only fast sync to a checkpoint within the epochs it knows.
+type CheckpointVerifier struct {
+	state     protocol.State
+	validator hotstuff.Validator
+}
+
+// NewCheckpointVerifier creates a new checkpoint verifier.
+func NewCheckpointVerifier(state protocol.State, validator hotstuff.Validator) *CheckpointVerifier {
+	return &CheckpointVerifier{
+		state:     state,
+		validator: validator,
+	}
+}
+
+// Verify verifies the given snapshot, together with the proof of finality of
+// its head, consisting of a direct child of the head and the QC certifying it.
+// Expected errors during normal operations:
+//   - InvalidCheckpointError if the checkpoint is invalid or forged
+//   - ErrUnverifiableCheckpoint if the checkpoint refers to epochs unknown to the local protocol state
+func (v *CheckpointVerifier) Verify(snapshot *inmem.Snapshot, proof *flow.Header, proofQC *flow.QuorumCertificate) error {
+	enc := snapshot.Encodable()
+
+	segment := enc.SealingSegment
+	if segment == nil || len(segment.Blocks) == 0 {
+		return NewInvalidCheckpointErrorf("missing sealing segment")
+	}
+	blocks := segment.AllBlocks()
+	for _, block := range blocks {
+		if block == nil || block.Header == nil || block.Payload == nil {
+			return NewInvalidCheckpointErrorf("sealing segment contains incomplete block")
+		}
+	}
+	err := segment.Validate()
+	if err != nil {
+		return NewInvalidCheckpointErrorf("invalid sealing segment: %w", err)
+	}
+
+	// the blocks of the segment must form a chain, so that the QC for the head
+	// transitively certifies all of them
+	for i, block := range blocks {
+		if block.Header.PayloadHash != block.Payload.Hash() {
+			return NewInvalidCheckpointErrorf("payload of block %x does not match its payload hash", block.ID())
+		}
+		if i == 0 {
+			continue
+		}
+		parent := blocks[i-1].Header
+		if block.Header.ParentID != parent.ID() || block.Header.Height != parent.Height+1 {
+			return NewInvalidCheckpointErrorf("block %x at height %d does not extend block %x at height %d",
+				block.ID(), block.Header.Height, parent.ID(), parent.Height)
+		}
+	}
+
+	head := segment.Highest().Header
+	headID := head.ID()
+	if enc.Head == nil || enc.Head.ID() != headID {
+		return NewInvalidCheckpointErrorf("snapshot head does not match highest block of sealing segment")
+	}
+
+	err = v.verifyParams(snapshot)
+	if err != nil {
+		return fmt.Errorf("could not verify checkpoint parameters: %w", err)
+	}
+	err = verifySeal(enc)
+	if err != nil {
+		return fmt.Errorf("could not verify checkpoint seal: %w", err)
+	}
+	err = v.verifyEpochs(snapshot, head)
+	if err != nil {
+		return fmt.Errorf("could not verify checkpoint epochs: %w", err)
+	}
+	err = verifyIdentities(snapshot)
+	if err != nil {
+		return fmt.Errorf("could not verify checkpoint identities: %w", err)
+	}
+
+	// the head must be certified ...
+	qc := enc.QuorumCertificate
+	if qc == nil || qc.BlockID != headID || qc.View != head.View {
+		return NewInvalidCheckpointErrorf("snapshot QC does not certify head %x", headID)
+	}
+	// ... and finalized by a certified direct child with consecutive view
+	if proof == nil || proofQC == nil {
+		return NewInvalidCheckpointErrorf("missing finality proof")
+	}
+	if proof.ParentID != headID || proof.View != head.View+1 {
+		return NewInvalidCheckpointErrorf("finality proof %x (view=%d) is not a direct child of head %x (view=%d)",
+			proof.ID(), proof.View, headID, head.View)
+	}
+	if proofQC.BlockID != proof.ID() || proofQC.View != proof.View {
+		return NewInvalidCheckpointErrorf("finality proof QC does not certify finality proof block %x", proof.ID())
+	}
+
+	for _, certificate := range []*flow.QuorumCertificate{qc, proof.QuorumCertificate(), proofQC} {
+		err = v.validator.ValidateQC(certificate)
+		if model.IsInvalidQCError(err) {
+			return NewInvalidCheckpointErrorf("invalid QC for block %x: %w", certificate.BlockID, err)
+		}
+		if errors.Is(err, model.ErrViewForUnknownEpoch) {
+			return fmt.Errorf("QC for block %x at view %d refers to unknown epoch: %w", certificate.BlockID, certificate.View, ErrUnverifiableCheckpoint)
+		}
+		if err != nil {
+			return fmt.Errorf("could not validate QC for block %x: %w", certificate.BlockID, err)
+		}
+	}
+
+	return nil
+}
+
+// verifyParams checks that the checkpoint has the same protocol parameters as our local state,
+// i.e. that it belongs to the same spork and chain.
+// Expected errors during normal operations:
+//   - InvalidCheckpointError if the parameters don't match
+func (v *CheckpointVerifier) verifyParams(snapshot *inmem.Snapshot) error {
+	local, err := inmem.FromParams(v.state.Params())
+	if err != nil {
+		return fmt.Errorf("could not get local parameters: %w", err)
+	}
+	params := snapshot.Params()
+
+	localSporkID, _ := local.SporkID()
+	sporkID, _ := params.SporkID()
+	if sporkID != localSporkID {
+		return NewInvalidCheckpointErrorf("spork ID %x does not match local spork ID %x", sporkID, localSporkID)
+	}
+	localChainID, _ := local.ChainID()
+	chainID, _ := params.ChainID()
+	if chainID != localChainID {
+		return NewInvalidCheckpointErrorf("chain ID %s does not match local chain ID %s", chainID, localChainID)
+	}
+	localRootHeight, _ := local.SporkRootBlockHeight()
+	rootHeight, _ := params.SporkRootBlockHeight()
+	if rootHeight != localRootHeight {
+		return NewInvalidCheckpointErrorf("spork root block height %d does not match local spork root block height %d", rootHeight, localRootHeight)
+	}
+	localVersion, _ := local.ProtocolVersion()
+	version, _ := params.ProtocolVersion()
+	if version != localVersion {
+		return NewInvalidCheckpointErrorf("protocol version %d does not match local protocol version %d", version, localVersion)
+	}
+	localThreshold, _ := local.EpochCommitSafetyThreshold()
+	threshold, _ := params.EpochCommitSafetyThreshold()
+	if threshold != localThreshold {
+		return NewInvalidCheckpointErrorf("epoch commit safety threshold %d does not match local threshold %d", threshold, localThreshold)
+	}
+
+	return nil
+}
+
+// verifySeal checks that the latest seal of the checkpoint is the seal for the lowest block of
+// the sealing segment, and that the latest result is the result committed to by this seal.
+// The sealing segment must be validated.
+// Expected errors during normal operations:
+//   - InvalidCheckpointError if the seal or result don't match the sealing segment
+func verifySeal(enc inmem.EncodableSnapshot) error {
+	segment := enc.SealingSegment
+	seal, err := segment.FinalizedSeal()
+	if err != nil {
+		return NewInvalidCheckpointErrorf("could not get seal for lowest block of sealing segment: %w", err)
+	}
+	if seal.BlockID != segment.Sealed().ID() {
+		return NewInvalidCheckpointErrorf("seal %x does not seal lowest block %x of sealing segment", seal.ID(), segment.Sealed().ID())
+	}
+	if enc.LatestSeal == nil || enc.LatestSeal.ID() != seal.ID() {
+		return NewInvalidCheckpointErrorf("latest seal does not match seal %x of sealing segment", seal.ID())
+	}
+	if enc.LatestResult == nil || enc.LatestResult.ID() != seal.ResultID {
+		return NewInvalidCheckpointErrorf("latest result does not match result %x of latest seal", seal.ResultID)
+	}
+	return nil
+}
+
+// verifyEpochs checks that every epoch of the checkpoint matches the epoch with the same counter
+// in our local protocol state, that the checkpoint includes every neighbouring epoch known to us,
+// and that the epoch phase of the checkpoint is consistent with its epochs.
+// As the checkpoint is ahead of our local state, it must know at least as much about each epoch
+// as we do. Epochs (or epoch commits) we don't know yet can not be verified, as they determine
+// the committees of future epochs.
+// Expected errors during normal operations:
+//   - InvalidCheckpointError if the epochs don't match our local knowledge or the phase
+//   - ErrUnverifiableCheckpoint if an epoch of the checkpoint is unknown to us
+func (v *CheckpointVerifier) verifyEpochs(snapshot *inmem.Snapshot, head *flow.Header) error {
+	epochs := snapshot.Epochs()
+	current := epochs.Current()
+	counter, err := current.Counter()
+	if err != nil {
+		return NewInvalidCheckpointErrorf("could not get current epoch counter: %w", err)
+	}
+	err = v.verifyEpoch(current, counter)
+	if err != nil {
+		return err
+	}
+
+	firstView, err := current.FirstView()
+	if err != nil {
+		return NewInvalidCheckpointErrorf("could not get first view of epoch %d: %w", counter, err)
+	}
+	finalView, err := current.FinalView()
+	if err != nil {
+		return NewInvalidCheckpointErrorf("could not get final view of epoch %d: %w", counter, err)
+	}
+	if head.View < firstView || head.View > finalView {
+		return NewInvalidCheckpointErrorf("head view %d is outside of current epoch %d [%d, %d]",
+			head.View, counter, firstView, finalView)
+	}
+	firstHeight, known, err := epochHeight(current.FirstHeight)
+	if err != nil {
+		return NewInvalidCheckpointErrorf("could not get first height of epoch %d: %w", counter, err)
+	}
+	if known && firstHeight > head.Height {
+		return NewInvalidCheckpointErrorf("current epoch %d starts at height %d above head height %d", counter, firstHeight, head.Height)
+	}
+
+	if counter > 0 {
+		_, err = v.verifyNeighbourEpoch(epochs.Previous(), counter-1, protocol.ErrNoPreviousEpoch)
+		if err != nil {
+			return err
+		}
+	}
+	hasNext, err := v.verifyNeighbourEpoch(epochs.Next(), counter+1, protocol.ErrNextEpochNotSetup)
+	if err != nil {
+		return err
+	}
+
+	expectedPhase := flow.EpochPhaseStaking
+	if hasNext {
+		expectedPhase = flow.EpochPhaseSetup
+		_, err = epochs.Next().DKG()
+		if err == nil {
+			expectedPhase = flow.EpochPhaseCommitted
+		} else if !errors.Is(err, protocol.ErrNextEpochNotCommitted) {
+			return NewInvalidCheckpointErrorf("could not get DKG of epoch %d: %w", counter+1, err)
+		}
+	}
+	phase, err := snapshot.Phase()
+	if err != nil {
+		return NewInvalidCheckpointErrorf("could not get epoch phase: %w", err)
+	}
+	if phase != expectedPhase {
+		return NewInvalidCheckpointErrorf("epoch phase %s is inconsistent with epochs, expected %s", phase, expectedPhase)
+	}
+
+	return nil
+}
+
+// verifyNeighbourEpoch verifies the previous or next epoch of the checkpoint, which is expected to
+// have the given counter and is absent if querying it returns the given sentinel. The epoch may
+// only be absent if it is also unknown to our local state. Returns whether the epoch is present.
+// Expected errors during normal operations:
+//   - InvalidCheckpointError if the epoch does not match our local knowledge
+//   - ErrUnverifiableCheckpoint if the epoch is unknown to us
+func (v *CheckpointVerifier) verifyNeighbourEpoch(epoch protocol.Epoch, counter uint64, absent error) (bool, error) {
+	actualCounter, err := epoch.Counter()
+	if errors.Is(err, absent) {
+		_, err = v.localEpoch(counter)
+		if errors.Is(err, ErrUnverifiableCheckpoint) {
+			return false, nil
+		}
+		if err != nil {
+			return false, err
+		}
+		return false, NewInvalidCheckpointErrorf("checkpoint is missing epoch %d known to local state", counter)
+	}
+	if err != nil {
+		return false, NewInvalidCheckpointErrorf("could not get counter of epoch %d: %w", counter, err)
+	}
+	if actualCounter != counter {
+		return false, NewInvalidCheckpointErrorf("epoch has counter %d, expected %d", actualCounter, counter)
+	}
+	return true, v.verifyEpoch(epoch, counter)
+}
+
+// verifyEpoch checks that the epoch of the checkpoint with the given counter matches the epoch
+// with the same counter in our local protocol state. The setup of both epochs must be identical,
+// and so must be their commits, as well as their first and final heights, as far as they are
+// known to our local state.
+// Expected errors during normal operations:
+//   - InvalidCheckpointError if the epoch does not match our local knowledge
+//   - ErrUnverifiableCheckpoint if the epoch, or its commit, is unknown to us
+func (v *CheckpointVerifier) verifyEpoch(epoch protocol.Epoch, counter uint64) error {
+	local, err := v.localEpoch(counter)
+	if err != nil {
+		return err
+	}
+
+	setup, err := protocol.ToEpochSetup(epoch)
+	if err != nil {
+		return NewInvalidCheckpointErrorf("could not get setup of epoch %d: %w", counter, err)
+	}
+	localSetup, err := protocol.ToEpochSetup(local)
+	if err != nil {
+		return fmt.Errorf("could not get local setup of epoch %d: %w", counter, err)
+	}
+	if setup.ID() != localSetup.ID() {
+		return NewInvalidCheckpointErrorf("setup of epoch %d does not match local state", counter)
+	}
+
+	commit, err := protocol.ToEpochCommit(epoch)
+	committed := err == nil
+	if err != nil && !errors.Is(err, protocol.ErrNextEpochNotCommitted) {
+		return NewInvalidCheckpointErrorf("could not get commit of epoch %d: %w", counter, err)
+	}
+	localCommit, err := protocol.ToEpochCommit(local)
+	localCommitted := err == nil
+	if err != nil && !errors.Is(err, protocol.ErrNextEpochNotCommitted) {
+		return fmt.Errorf("could not get local commit of epoch %d: %w", counter, err)
+	}
+	switch {
+	case committed && !localCommitted:
+		return fmt.Errorf("commit of epoch %d is not known locally: %w", counter, ErrUnverifiableCheckpoint)
+	case !committed && localCommitted:
+		return NewInvalidCheckpointErrorf("epoch %d is committed in local state, but not in checkpoint", counter)
+	case committed && commit.ID() != localCommit.ID():
+		return NewInvalidCheckpointErrorf("commit of epoch %d does not match local state", counter)
+	}
+
+	for _, heights := range []struct {
+		name       string
+		checkpoint func() (uint64, error)
+		local      func() (uint64, error)
+	}{
+		{name: "first", checkpoint: epoch.FirstHeight, local: local.FirstHeight},
+		{name: "final", checkpoint: epoch.FinalHeight, local: local.FinalHeight},
+	} {
+		localHeight, localKnown, err := epochHeight(heights.local)
+		if err != nil {
+			return fmt.Errorf("could not get local %s height of epoch %d: %w", heights.name, counter, err)
+		}
+		if !localKnown {
+			continue
+		}
+		height, known, err := epochHeight(heights.checkpoint)
+		if err != nil {
+			return NewInvalidCheckpointErrorf("could not get %s height of epoch %d: %w", heights.name, counter, err)
+		}
+		if known && height != localHeight {
+			return NewInvalidCheckpointErrorf("%s height %d of epoch %d does not match local height %d",
+				heights.name, height, counter, localHeight)
+		}
+	}
+
+	return nil
+}
+
+// epochHeight returns the first or final height of an epoch using the given getter, and whether
+// the height is known, i.e. the respective epoch transition has been finalized.
+// No errors are expected during normal operation.
+func epochHeight(get func() (uint64, error)) (uint64, bool, error) {
+	height, err := get()
+	if errors.Is(err, protocol.ErrEpochTransitionNotFinalized) {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, err
+	}
+	return height, true, nil
+}
+
+// verifyIdentities checks that the identity table of the checkpoint is the one derived from its
+// epochs, the same way our protocol state derives it: the participants of the current epoch, plus
+// the participants of the previous epoch during the staking phase, or of the next epoch during the
+// setup and committed phases, with zero weight, in canonical order.
+// The epochs and phase of the checkpoint must be verified.
+// Expected errors during normal operations:
+//   - InvalidCheckpointError if the identities don't match the epochs
+func verifyIdentities(snapshot *inmem.Snapshot) error {
+	epochs := snapshot.Epochs()
+	participants, err := epochs.Current().InitialIdentities()
+	if err != nil {
+		return NewInvalidCheckpointErrorf("could not get participants of current epoch: %w", err)
+	}
+	identities := participants.Sort(order.Canonical)
+
+	phase, err := snapshot.Phase()
+	if err != nil {
+		return NewInvalidCheckpointErrorf("could not get epoch phase: %w", err)
+	}
+	other := epochs.Next()
+	if phase == flow.EpochPhaseStaking {
+		other = epochs.Previous()
+	}
+	otherParticipants, err := other.InitialIdentities()
+	if err != nil && !errors.Is(err, protocol.ErrNoPreviousEpoch) {
+		return NewInvalidCheckpointErrorf("could not get participants of neighbouring epoch: %w", err)
+	}
+	var otherIdentities flow.IdentityList
+	for _, identity := range otherParticipants {
+		if !identities.Exists(identity) {
+			otherIdentities = append(otherIdentities, identity)
+		}
+	}
+	identities = append(identities, otherIdentities.Map(mapfunc.WithWeight(0))...).Sort(order.Canonical)
+
+	actual, err := snapshot.Identities(filter.Any)
+	if err != nil {
+		return NewInvalidCheckpointErrorf("could not get identities: %w", err)
+	}
+	if actual.Checksum() != identities.Checksum() {
+		return NewInvalidCheckpointErrorf("identities do not match participants of epochs")
+	}
+	return nil
+}
+
+// localEpoch returns the epoch with the given counter, if it is known to our local protocol state.
+// Expected errors during normal operations:
+//   - ErrUnverifiableCheckpoint if the epoch is unknown
+func (v *CheckpointVerifier) localEpoch(counter uint64) (protocol.Epoch, error) {
+	epochs := v.state.Final().Epochs()
+	for _, epoch := range []protocol.Epoch{epochs.Previous(), epochs.Current(), epochs.Next()} {
+		localCounter, err := epoch.Counter()
+		if errors.Is(err, protocol.ErrNoPreviousEpoch) || errors.Is(err, protocol.ErrNextEpochNotSetup) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("could not get local epoch counter: %w", err)
+		}
+		if localCounter == counter {
+			return epoch, nil
+		}
+	}
+	return nil, fmt.Errorf("epoch %d is not known locally: %w", counter, ErrUnverifiableCheckpoint)
+}
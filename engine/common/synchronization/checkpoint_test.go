@@ -0,0 +1,366 @@
+package synchronization
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/onflow/flow-go/consensus/hotstuff/mocks"
+	"github.com/onflow/flow-go/consensus/hotstuff/model"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/model/flow/mapfunc"
+	"github.com/onflow/flow-go/model/flow/order"
+	protocolint "github.com/onflow/flow-go/state/protocol"
+	"github.com/onflow/flow-go/state/protocol/inmem"
+	"github.com/onflow/flow-go/state/protocol/invalid"
+	protocol "github.com/onflow/flow-go/state/protocol/mock"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+func TestCheckpointVerifier(t *testing.T) {
+	suite.Run(t, new(CheckpointVerifierSuite))
+}
+
+type CheckpointVerifierSuite struct {
+	suite.Suite
+
+	checkpoint *inmem.Snapshot
+	proof      *flow.Header
+	proofQC    *flow.QuorumCertificate
+
+	state     *protocol.State
+	validator *mocks.Validator
+	verifier  *CheckpointVerifier
+}
+
+func (cs *CheckpointVerifierSuite) SetupTest() {
+	cs.checkpoint, cs.proof, cs.proofQC, cs.state = checkpointFixture(cs.T(), 1000)
+	cs.validator = mocks.NewValidator(cs.T())
+	cs.verifier = NewCheckpointVerifier(cs.state, cs.validator)
+}
+
+// checkpointFixture returns a checkpoint with head at the given height, a proof of finality
+// of the head, and a local protocol state, which knows the current epoch of the checkpoint.
+func checkpointFixture(t *testing.T, height uint64) (*inmem.Snapshot, *flow.Header, *flow.QuorumCertificate, *protocol.State) {
+	participants := unittest.IdentityListFixture(5, unittest.WithAllRoles())
+	checkpoint := unittest.RootSnapshotFixture(participants, func(block *flow.Block) {
+		block.Header.Height = height
+	})
+	head, err := checkpoint.Head()
+	require.NoError(t, err)
+
+	proof := unittest.BlockHeaderWithParentFixture(head)
+	proof.View = head.View + 1
+	proofQC := unittest.CertifyBlock(proof)
+
+	state := localStateFixture(t, checkpoint.Params(),
+		invalid.NewEpoch(protocolint.ErrNoPreviousEpoch),
+		checkpoint.Epochs().Current(),
+		invalid.NewEpoch(protocolint.ErrNextEpochNotSetup),
+	)
+
+	return checkpoint, proof, proofQC, state
+}
+
+// localStateFixture returns a local protocol state with the given parameters, whose finalized
+// snapshot knows the given previous, current and next epochs.
+func localStateFixture(t *testing.T, globalParams protocolint.GlobalParams, previous, current, next protocolint.Epoch) *protocol.State {
+	sporkID, err := globalParams.SporkID()
+	require.NoError(t, err)
+	chainID, err := globalParams.ChainID()
+	require.NoError(t, err)
+	sporkRootHeight, err := globalParams.SporkRootBlockHeight()
+	require.NoError(t, err)
+	version, err := globalParams.ProtocolVersion()
+	require.NoError(t, err)
+	threshold, err := globalParams.EpochCommitSafetyThreshold()
+	require.NoError(t, err)
+	params := new(protocol.Params)
+	params.On("SporkID").Return(sporkID, nil).Maybe()
+	params.On("ChainID").Return(chainID, nil).Maybe()
+	params.On("SporkRootBlockHeight").Return(sporkRootHeight, nil).Maybe()
+	params.On("ProtocolVersion").Return(version, nil).Maybe()
+	params.On("EpochCommitSafetyThreshold").Return(threshold, nil).Maybe()
+
+	epochs := new(protocol.EpochQuery)
+	epochs.On("Previous").Return(previous).Maybe()
+	epochs.On("Current").Return(current).Maybe()
+	epochs.On("Next").Return(next).Maybe()
+	final := new(protocol.Snapshot)
+	final.On("Epochs").Return(epochs).Maybe()
+
+	state := new(protocol.State)
+	state.On("Params").Return(params).Maybe()
+	state.On("Final").Return(final).Maybe()
+
+	return state
+}
+
+// nextEpochFixture returns the setup and commit of an epoch directly following the current epoch of the given checkpoint.
+func nextEpochFixture(t *testing.T, checkpoint *inmem.Snapshot) (*flow.EpochSetup, *flow.EpochCommit) {
+	current := checkpoint.Epochs().Current()
+	counter, err := current.Counter()
+	require.NoError(t, err)
+	finalView, err := current.FinalView()
+	require.NoError(t, err)
+
+	participants := unittest.IdentityListFixture(5, unittest.WithAllRoles()).Sort(order.Canonical)
+	setup := unittest.EpochSetupFixture(
+		unittest.WithParticipants(participants),
+		unittest.SetupWithCounter(counter+1),
+		unittest.WithFirstView(finalView+1),
+		unittest.WithFinalView(finalView+1000),
+	)
+	commit := unittest.EpochCommitFixture(
+		unittest.CommitWithCounter(counter+1),
+		unittest.WithClusterQCsFromAssignments(setup.Assignments),
+		unittest.WithDKGFromParticipants(participants),
+	)
+	return setup, commit
+}
+
+// withNextEpoch returns the encoded checkpoint in the setup or committed phase of the given next epoch,
+// with the identities derived accordingly.
+func withNextEpoch(t *testing.T, checkpoint *inmem.Snapshot, next protocolint.Epoch, phase flow.EpochPhase) inmem.EncodableSnapshot {
+	encodable, err := inmem.FromEpoch(next)
+	require.NoError(t, err)
+	nextEpoch := encodable.Encodable()
+	nextParticipants, err := next.InitialIdentities()
+	require.NoError(t, err)
+
+	enc := checkpoint.Encodable()
+	enc.Epochs.Next = &nextEpoch
+	enc.Phase = phase
+	enc.Identities = append(enc.Identities.Copy(), nextParticipants.Map(mapfunc.WithWeight(0))...).Sort(order.Canonical)
+	return enc
+}
+
+// TestValidCheckpoint tests that a consistent checkpoint, whose head is certified and
+// finalized by a certified child, is accepted.
+func (cs *CheckpointVerifierSuite) TestValidCheckpoint() {
+	cs.validator.On("ValidateQC", mock.Anything).Return(nil).Times(3)
+
+	err := cs.verifier.Verify(cs.checkpoint, cs.proof, cs.proofQC)
+	require.NoError(cs.T(), err)
+}
+
+// TestValidCheckpointWithNextEpoch tests that a checkpoint in the setup or committed phase is
+// accepted, if its next epoch matches the next epoch known to the local state.
+func (cs *CheckpointVerifierSuite) TestValidCheckpointWithNextEpoch() {
+	setup, commit := nextEpochFixture(cs.T(), cs.checkpoint)
+	for phase, next := range map[flow.EpochPhase]protocolint.Epoch{
+		flow.EpochPhaseSetup:     inmem.NewSetupEpoch(setup),
+		flow.EpochPhaseCommitted: inmem.NewCommittedEpoch(setup, commit),
+	} {
+		cs.Run(phase.String(), func() {
+			state := localStateFixture(cs.T(), cs.checkpoint.Params(),
+				invalid.NewEpoch(protocolint.ErrNoPreviousEpoch), cs.checkpoint.Epochs().Current(), next)
+			verifier := NewCheckpointVerifier(state, cs.validator)
+			cs.validator.On("ValidateQC", mock.Anything).Return(nil).Times(3)
+
+			enc := withNextEpoch(cs.T(), cs.checkpoint, next, phase)
+			err := verifier.Verify(inmem.SnapshotFromEncodable(enc), cs.proof, cs.proofQC)
+			require.NoError(cs.T(), err)
+		})
+	}
+}
+
+// TestInvalidQC tests that a checkpoint with an invalid QC is rejected as invalid.
+func (cs *CheckpointVerifierSuite) TestInvalidQC() {
+	cs.validator.On("ValidateQC", mock.Anything).Return(model.InvalidQCError{Err: fmt.Errorf("forged")}).Once()
+
+	err := cs.verifier.Verify(cs.checkpoint, cs.proof, cs.proofQC)
+	require.True(cs.T(), IsInvalidCheckpointError(err), err)
+}
+
+// TestUnknownEpochQC tests that a checkpoint, whose QCs can't be validated with the
+// local committee knowledge, is unverifiable but not considered invalid.
+func (cs *CheckpointVerifierSuite) TestUnknownEpochQC() {
+	cs.validator.On("ValidateQC", mock.Anything).Return(model.ErrViewForUnknownEpoch).Once()
+
+	err := cs.verifier.Verify(cs.checkpoint, cs.proof, cs.proofQC)
+	require.ErrorIs(cs.T(), err, ErrUnverifiableCheckpoint)
+	require.False(cs.T(), IsInvalidCheckpointError(err))
+}
+
+// TestInvalidFinalityProof tests that checkpoints without a valid proof of finality of their head are rejected.
+func (cs *CheckpointVerifierSuite) TestInvalidFinalityProof() {
+	cs.Run("missing proof", func() {
+		err := cs.verifier.Verify(cs.checkpoint, nil, nil)
+		require.True(cs.T(), IsInvalidCheckpointError(err), err)
+	})
+	cs.Run("proof view is not consecutive", func() {
+		cs.proof.View++
+		cs.proofQC = unittest.CertifyBlock(cs.proof)
+		err := cs.verifier.Verify(cs.checkpoint, cs.proof, cs.proofQC)
+		require.True(cs.T(), IsInvalidCheckpointError(err), err)
+	})
+	cs.Run("proof QC certifies other block", func() {
+		err := cs.verifier.Verify(cs.checkpoint, cs.proof, unittest.QuorumCertificateFixture())
+		require.True(cs.T(), IsInvalidCheckpointError(err), err)
+	})
+}
+
+// TestForgedSnapshot tests that snapshots, which are inconsistent in themselves or with
+// the local state, are rejected as invalid.
+func (cs *CheckpointVerifierSuite) TestForgedSnapshot() {
+	forged := func(tamper func(enc *inmem.EncodableSnapshot)) *inmem.Snapshot {
+		enc := cs.checkpoint.Encodable()
+		tamper(&enc)
+		return inmem.SnapshotFromEncodable(enc)
+	}
+	current := func(enc *inmem.EncodableSnapshot) *inmem.EncodableEpoch {
+		epoch := enc.Epochs.Current
+		return &epoch
+	}
+
+	for name, snapshot := range map[string]*inmem.Snapshot{
+		"head QC certifies other block": forged(func(enc *inmem.EncodableSnapshot) {
+			enc.QuorumCertificate = unittest.QuorumCertificateFixture()
+		}),
+		"head does not match sealing segment": forged(func(enc *inmem.EncodableSnapshot) {
+			enc.Head = unittest.BlockHeaderFixture()
+		}),
+		"different spork": forged(func(enc *inmem.EncodableSnapshot) {
+			enc.Params.SporkID = unittest.IdentifierFixture()
+		}),
+		"different chain": forged(func(enc *inmem.EncodableSnapshot) {
+			enc.Params.ChainID = flow.ChainID("forged")
+		}),
+		"different spork root block height": forged(func(enc *inmem.EncodableSnapshot) {
+			enc.Params.SporkRootBlockHeight++
+		}),
+		"different protocol version": forged(func(enc *inmem.EncodableSnapshot) {
+			enc.Params.ProtocolVersion++
+		}),
+		"different epoch commit safety threshold": forged(func(enc *inmem.EncodableSnapshot) {
+			enc.Params.EpochCommitSafetyThreshold++
+		}),
+		"latest seal does not match sealing segment": forged(func(enc *inmem.EncodableSnapshot) {
+			enc.LatestSeal = unittest.Seal.Fixture()
+		}),
+		"latest result does not match latest seal": forged(func(enc *inmem.EncodableSnapshot) {
+			enc.LatestResult = unittest.ExecutionResultFixture()
+		}),
+		"different epoch committee": forged(func(enc *inmem.EncodableSnapshot) {
+			enc.Epochs.Current.InitialIdentities = unittest.IdentityListFixture(5, unittest.WithAllRoles())
+		}),
+		"different epoch random source": forged(func(enc *inmem.EncodableSnapshot) {
+			enc.Epochs.Current.RandomSource = unittest.SeedFixture(flow.EpochSetupRandomSourceLength)
+		}),
+		"current epoch not committed": forged(func(enc *inmem.EncodableSnapshot) {
+			enc.Epochs.Current.DKG = nil
+		}),
+		"different epoch first height": forged(func(enc *inmem.EncodableSnapshot) {
+			firstHeight := *enc.Epochs.Current.FirstHeight + 1
+			enc.Epochs.Current.FirstHeight = &firstHeight
+		}),
+		"previous epoch with wrong counter": forged(func(enc *inmem.EncodableSnapshot) {
+			enc.Epochs.Previous = current(enc)
+		}),
+		"next epoch with wrong counter": forged(func(enc *inmem.EncodableSnapshot) {
+			enc.Epochs.Next = current(enc)
+		}),
+		"phase inconsistent with epochs": forged(func(enc *inmem.EncodableSnapshot) {
+			enc.Phase = flow.EpochPhaseSetup
+		}),
+		"identities do not match epoch participants": forged(func(enc *inmem.EncodableSnapshot) {
+			enc.Identities = unittest.IdentityListFixture(5, unittest.WithAllRoles()).Sort(order.Canonical)
+		}),
+		"identity with different weight": forged(func(enc *inmem.EncodableSnapshot) {
+			enc.Identities = enc.Identities.Map(func(identity flow.Identity) flow.Identity {
+				identity.Weight++
+				return identity
+			})
+		}),
+	} {
+		cs.Run(name, func() {
+			err := cs.verifier.Verify(snapshot, cs.proof, cs.proofQC)
+			require.True(cs.T(), IsInvalidCheckpointError(err), err)
+		})
+	}
+}
+
+// TestForgedNextEpoch tests that snapshots, whose next epoch does not match the next epoch
+// known to the local state, are rejected as invalid.
+func (cs *CheckpointVerifierSuite) TestForgedNextEpoch() {
+	setup, commit := nextEpochFixture(cs.T(), cs.checkpoint)
+	verifierWithNext := func(next protocolint.Epoch) *CheckpointVerifier {
+		state := localStateFixture(cs.T(), cs.checkpoint.Params(),
+			invalid.NewEpoch(protocolint.ErrNoPreviousEpoch), cs.checkpoint.Epochs().Current(), next)
+		return NewCheckpointVerifier(state, cs.validator)
+	}
+
+	cs.Run("missing next epoch known locally", func() {
+		verifier := verifierWithNext(inmem.NewSetupEpoch(setup))
+		err := verifier.Verify(cs.checkpoint, cs.proof, cs.proofQC)
+		require.True(cs.T(), IsInvalidCheckpointError(err), err)
+	})
+	cs.Run("different next epoch", func() {
+		verifier := verifierWithNext(inmem.NewSetupEpoch(setup))
+		otherSetup, _ := nextEpochFixture(cs.T(), cs.checkpoint)
+		enc := withNextEpoch(cs.T(), cs.checkpoint, inmem.NewSetupEpoch(otherSetup), flow.EpochPhaseSetup)
+		err := verifier.Verify(inmem.SnapshotFromEncodable(enc), cs.proof, cs.proofQC)
+		require.True(cs.T(), IsInvalidCheckpointError(err), err)
+	})
+	cs.Run("next epoch committed locally, but not in checkpoint", func() {
+		verifier := verifierWithNext(inmem.NewCommittedEpoch(setup, commit))
+		enc := withNextEpoch(cs.T(), cs.checkpoint, inmem.NewSetupEpoch(setup), flow.EpochPhaseSetup)
+		err := verifier.Verify(inmem.SnapshotFromEncodable(enc), cs.proof, cs.proofQC)
+		require.True(cs.T(), IsInvalidCheckpointError(err), err)
+	})
+	cs.Run("phase inconsistent with next epoch", func() {
+		verifier := verifierWithNext(inmem.NewSetupEpoch(setup))
+		enc := withNextEpoch(cs.T(), cs.checkpoint, inmem.NewSetupEpoch(setup), flow.EpochPhaseCommitted)
+		err := verifier.Verify(inmem.SnapshotFromEncodable(enc), cs.proof, cs.proofQC)
+		require.True(cs.T(), IsInvalidCheckpointError(err), err)
+	})
+	cs.Run("identities without participants of next epoch", func() {
+		verifier := verifierWithNext(inmem.NewSetupEpoch(setup))
+		enc := withNextEpoch(cs.T(), cs.checkpoint, inmem.NewSetupEpoch(setup), flow.EpochPhaseSetup)
+		enc.Identities = cs.checkpoint.Encodable().Identities
+		err := verifier.Verify(inmem.SnapshotFromEncodable(enc), cs.proof, cs.proofQC)
+		require.True(cs.T(), IsInvalidCheckpointError(err), err)
+	})
+}
+
+// TestUnknownEpoch tests that a checkpoint referring to epochs, or epoch commits, unknown to the
+// local state is unverifiable, but not considered invalid.
+func (cs *CheckpointVerifierSuite) TestUnknownEpoch() {
+	setup, commit := nextEpochFixture(cs.T(), cs.checkpoint)
+
+	cs.Run("unknown current epoch", func() {
+		enc := cs.checkpoint.Encodable()
+		enc.Epochs.Current.Counter += 2
+		err := cs.verifier.Verify(inmem.SnapshotFromEncodable(enc), cs.proof, cs.proofQC)
+		require.ErrorIs(cs.T(), err, ErrUnverifiableCheckpoint)
+		require.False(cs.T(), IsInvalidCheckpointError(err))
+	})
+	cs.Run("unknown previous epoch", func() {
+		enc := cs.checkpoint.Encodable()
+		previous := enc.Epochs.Current
+		previous.Counter--
+		enc.Epochs.Previous = &previous
+		err := cs.verifier.Verify(inmem.SnapshotFromEncodable(enc), cs.proof, cs.proofQC)
+		require.ErrorIs(cs.T(), err, ErrUnverifiableCheckpoint)
+		require.False(cs.T(), IsInvalidCheckpointError(err))
+	})
+	cs.Run("unknown next epoch", func() {
+		enc := withNextEpoch(cs.T(), cs.checkpoint, inmem.NewSetupEpoch(setup), flow.EpochPhaseSetup)
+		err := cs.verifier.Verify(inmem.SnapshotFromEncodable(enc), cs.proof, cs.proofQC)
+		require.ErrorIs(cs.T(), err, ErrUnverifiableCheckpoint)
+		require.False(cs.T(), IsInvalidCheckpointError(err))
+	})
+	cs.Run("unknown commit of next epoch", func() {
+		state := localStateFixture(cs.T(), cs.checkpoint.Params(),
+			invalid.NewEpoch(protocolint.ErrNoPreviousEpoch), cs.checkpoint.Epochs().Current(), inmem.NewSetupEpoch(setup))
+		verifier := NewCheckpointVerifier(state, cs.validator)
+		enc := withNextEpoch(cs.T(), cs.checkpoint, inmem.NewCommittedEpoch(setup, commit), flow.EpochPhaseCommitted)
+		err := verifier.Verify(inmem.SnapshotFromEncodable(enc), cs.proof, cs.proofQC)
+		require.ErrorIs(cs.T(), err, ErrUnverifiableCheckpoint)
+		require.False(cs.T(), IsInvalidCheckpointError(err))
+	})
+}
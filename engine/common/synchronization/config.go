@@ -9,6 +9,13 @@ import (
 type Config struct {
 	PollInterval time.Duration
 	ScanInterval time.Duration
+
+	// CheckpointThreshold is the number of blocks a node must be behind the highest
+	// height reported by its peers before it requests a checkpoint (a protocol state
+	// snapshot) to skip ahead, instead of downloading every block. 0 disables it.
+	CheckpointThreshold uint64
+	CheckpointVerifier  *CheckpointVerifier
+	CheckpointConsumer  CheckpointConsumer
 }
 
 func DefaultConfig() *Config {
@@ -36,3 +43,14 @@ func WithScanInterval(interval time.Duration) OptionFunc {
 		cfg.ScanInterval = interval
 	}
 }
+
+// WithCheckpointSync enables checkpoint-based fast sync. If the node is more than
+// threshold blocks behind its peers, it requests a checkpoint from them, which
+// is handed to the consumer once it is successfully verified by the verifier.
+func WithCheckpointSync(threshold uint64, verifier *CheckpointVerifier, consumer CheckpointConsumer) OptionFunc {
+	return func(cfg *Config) {
+		cfg.CheckpointThreshold = threshold
+		cfg.CheckpointVerifier = verifier
+		cfg.CheckpointConsumer = consumer
+	}
+}
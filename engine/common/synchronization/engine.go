@@ -3,8 +3,11 @@
 package synchronization
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
 	"math/rand"
+	"sync"
 	"time"
 
 	"github.com/hashicorp/go-multierror"
@@ -21,8 +24,11 @@ import (
 	"github.com/onflow/flow-go/module/lifecycle"
 	"github.com/onflow/flow-go/module/metrics"
 	"github.com/onflow/flow-go/network"
+	"github.com/onflow/flow-go/network/alsp"
 	"github.com/onflow/flow-go/network/channels"
+	"github.com/onflow/flow-go/state/protocol/inmem"
 	"github.com/onflow/flow-go/storage"
+	"github.com/onflow/flow-go/utils/logging"
 )
 
 // defaultSyncResponseQueueCapacity maximum capacity of sync responses queue
@@ -31,6 +37,13 @@ const defaultSyncResponseQueueCapacity = 500
 // defaultBlockResponseQueueCapacity maximum capacity of block responses queue
 const defaultBlockResponseQueueCapacity = 500
 
+// defaultSnapshotResponseQueueCapacity maximum capacity of snapshot responses queue
+const defaultSnapshotResponseQueueCapacity = 10
+
+// checkpointRequestTimeout is the duration after which we stop accepting
+// responses to a snapshot request.
+const checkpointRequestTimeout = time.Minute
+
 // Engine is the synchronization engine, responsible for synchronizing chain state.
 type Engine struct {
 	unit    *engine.Unit
@@ -47,18 +60,31 @@ type Engine struct {
 	core                 module.SyncCore
 	participantsProvider module.IdentifierProvider
 	finalizedHeader      *FinalizedHeaderCache
+	peers                *peerTracker // tracks the quality of peers we request blocks from
 
 	requestHandler *RequestHandler // component responsible for handling requests
 
-	pendingSyncResponses   engine.MessageStore    // message store for *message.SyncResponse
-	pendingBlockResponses  engine.MessageStore    // message store for *message.BlockResponse
-	responseMessageHandler *engine.MessageHandler // message handler responsible for response processing
+	pendingSyncResponses     engine.MessageStore    // message store for *message.SyncResponse
+	pendingBlockResponses    engine.MessageStore    // message store for *message.BlockResponse
+	pendingSnapshotResponses engine.MessageStore    // message store for *message.SnapshotResponse
+	responseMessageHandler   *engine.MessageHandler // message handler responsible for response processing
+
+	// checkpoint-based fast sync, disabled if checkpointThreshold is 0
+	checkpointThreshold uint64
+	checkpointVerifier  *CheckpointVerifier
+	checkpointConsumer  CheckpointConsumer
+	checkpointLock      sync.Mutex
+	checkpointRequests  map[uint64]time.Time         // nonces of outstanding snapshot requests
+	checkpointFaulty    map[flow.Identifier]struct{} // peers which served invalid or unusable checkpoints
+	checkpointDone      bool                         // true once a checkpoint was accepted by the consumer
+	highestHeight       uint64                       // highest finalized height reported by peers
 }
 
 // New creates a new main chain synchronization engine.
 func New(
 	log zerolog.Logger,
 	metrics module.EngineMetrics,
+	peerMetrics module.ChainSyncPeerMetrics,
 	net network.Network,
 	me module.Local,
 	blocks storage.Blocks,
@@ -77,6 +103,9 @@ func New(
 	if comp == nil {
 		panic("must initialize synchronization engine with comp engine")
 	}
+	if opt.CheckpointThreshold > 0 && (opt.CheckpointVerifier == nil || opt.CheckpointConsumer == nil) {
+		return nil, fmt.Errorf("checkpoint sync requires a checkpoint verifier and consumer")
+	}
 
 	// initialize the propagation engine with its dependencies
 	e := &Engine{
@@ -92,6 +121,12 @@ func New(
 		scanInterval:         opt.ScanInterval,
 		finalizedHeader:      finalizedHeader,
 		participantsProvider: participantsProvider,
+		peers:                newPeerTracker(peerMetrics),
+		checkpointThreshold:  opt.CheckpointThreshold,
+		checkpointVerifier:   opt.CheckpointVerifier,
+		checkpointConsumer:   opt.CheckpointConsumer,
+		checkpointRequests:   make(map[uint64]time.Time),
+		checkpointFaulty:     make(map[flow.Identifier]struct{}),
 	}
 
 	err := e.setupResponseMessageHandler()
@@ -131,6 +166,15 @@ func (e *Engine) setupResponseMessageHandler() error {
 		FifoQueue: blockResponseQueue,
 	}
 
+	snapshotResponseQueue, err := fifoqueue.NewFifoQueue(defaultSnapshotResponseQueueCapacity)
+	if err != nil {
+		return fmt.Errorf("failed to create queue for snapshot responses: %w", err)
+	}
+
+	e.pendingSnapshotResponses = &engine.FifoMessageStore{
+		FifoQueue: snapshotResponseQueue,
+	}
+
 	// define message queueing behaviour
 	e.responseMessageHandler = engine.NewMessageHandler(
 		e.log,
@@ -155,6 +199,16 @@ func (e *Engine) setupResponseMessageHandler() error {
 			},
 			Store: e.pendingBlockResponses,
 		},
+		engine.Pattern{
+			Match: func(msg *engine.Message) bool {
+				_, ok := msg.Payload.(*messages.SnapshotResponse)
+				if ok {
+					e.metrics.MessageReceived(metrics.EngineSynchronization, metrics.MessageSnapshotResponse)
+				}
+				return ok
+			},
+			Store: e.pendingSnapshotResponses,
+		},
 	)
 
 	return nil
@@ -230,9 +284,9 @@ func (e *Engine) Process(channel channels.Channel, originID flow.Identifier, eve
 //   - All other errors are potential symptoms of internal state corruption or bugs (fatal).
 func (e *Engine) process(originID flow.Identifier, event interface{}) error {
 	switch event.(type) {
-	case *messages.RangeRequest, *messages.BatchRequest, *messages.SyncRequest:
+	case *messages.RangeRequest, *messages.BatchRequest, *messages.SyncRequest, *messages.SnapshotRequest:
 		return e.requestHandler.process(originID, event)
-	case *messages.SyncResponse, *messages.BlockResponse:
+	case *messages.SyncResponse, *messages.BlockResponse, *messages.SnapshotResponse:
 		return e.responseMessageHandler.Process(originID, event)
 	default:
 		return fmt.Errorf("received input with type %T from %x: %w", event, originID[:], engine.IncompatibleInputTypeError)
@@ -275,6 +329,13 @@ func (e *Engine) processAvailableResponses() {
 			continue
 		}
 
+		msg, ok = e.pendingSnapshotResponses.Get()
+		if ok {
+			e.onSnapshotResponse(msg.OriginID, msg.Payload.(*messages.SnapshotResponse))
+			e.metrics.MessageHandled(metrics.EngineSynchronization, metrics.MessageSnapshotResponse)
+			continue
+		}
+
 		// when there is no more messages in the queue, back to the loop to wait
 		// for the next incoming message to arrive.
 		return
@@ -286,10 +347,30 @@ func (e *Engine) onSyncResponse(originID flow.Identifier, res *messages.SyncResp
 	e.log.Debug().Str("origin_id", originID.String()).Msg("received sync response")
 	final := e.finalizedHeader.Get()
 	e.core.HandleHeight(final, res.Height)
+	e.peers.OnHeight(originID, res.Height)
+
+	if e.checkpointThreshold > 0 {
+		e.checkpointLock.Lock()
+		if res.Height > e.highestHeight {
+			e.highestHeight = res.Height
+		}
+		e.checkpointLock.Unlock()
+	}
 }
 
 // onBlockResponse processes a response containing a specifically requested block.
 func (e *Engine) onBlockResponse(originID flow.Identifier, res *messages.BlockResponse) {
+	failure, failed := e.peers.OnBlockResponse(originID, res)
+	if failed {
+		e.log.Warn().
+			Str("origin_id", originID.String()).
+			Uint64("range_nonce", res.Nonce).
+			Str("failure", string(failure)).
+			Bool(logging.KeySuspicious, failure == failureInvalid).
+			Msg("dropping failed block response")
+		return
+	}
+
 	// process the blocks one by one
 	if len(res.Blocks) == 0 {
 		e.log.Debug().Msg("received empty block response")
@@ -317,6 +398,107 @@ func (e *Engine) onBlockResponse(originID flow.Identifier, res *messages.BlockRe
 	})
 }
 
+// onSnapshotResponse processes a response containing a checkpoint, which we
+// requested as we are far behind. Once a checkpoint is verified, it is handed
+// to the checkpoint consumer and no further checkpoints are requested, unless
+// the consumer fails to persist it, in which case checkpoints are requested from
+// the other peers.
+func (e *Engine) onSnapshotResponse(originID flow.Identifier, res *messages.SnapshotResponse) {
+	logger := e.log.With().Str("origin_id", originID.String()).Uint64("nonce", res.Nonce).Logger()
+	logger.Debug().Msg("received snapshot response")
+
+	if e.checkpointThreshold == 0 {
+		logger.Warn().Bool(logging.KeySuspicious, true).Msg("received snapshot response while checkpoint sync is disabled")
+		return
+	}
+
+	snapshot, ok := e.verifyCheckpoint(originID, res, logger)
+	if !ok {
+		return
+	}
+
+	head, _ := snapshot.Head()
+	logger.Info().
+		Hex("block_id", logging.ID(head.ID())).
+		Uint64("height", head.Height).
+		Msg("verified checkpoint, handing over to checkpoint consumer")
+	err := e.checkpointConsumer(snapshot)
+	if err != nil {
+		logger.Error().Err(err).Msg("checkpoint consumer failed, continuing with regular sync and requesting checkpoint from other peers")
+		e.checkpointLock.Lock()
+		e.checkpointDone = false
+		e.checkpointFaulty[originID] = struct{}{}
+		e.checkpointLock.Unlock()
+	}
+}
+
+// verifyCheckpoint verifies the checkpoint of the given snapshot response, and
+// returns it if it was requested, is sufficiently far ahead and valid. Peers
+// serving invalid checkpoints, or checkpoints which can't be verified due to an
+// unexpected error, are excluded from further snapshot requests.
+func (e *Engine) verifyCheckpoint(originID flow.Identifier, res *messages.SnapshotResponse, logger zerolog.Logger) (*inmem.Snapshot, bool) {
+	e.checkpointLock.Lock()
+	defer e.checkpointLock.Unlock()
+
+	if e.checkpointDone {
+		return nil, false
+	}
+	requested, ok := e.checkpointRequests[res.Nonce]
+	if !ok || time.Since(requested) > checkpointRequestTimeout {
+		logger.Warn().Bool(logging.KeySuspicious, true).Msg("received unexpected snapshot response")
+		return nil, false
+	}
+	if _, faulty := e.checkpointFaulty[originID]; faulty {
+		logger.Debug().Msg("ignoring snapshot response from faulty peer")
+		return nil, false
+	}
+
+	var encodable inmem.EncodableSnapshot
+	err := json.Unmarshal(res.Snapshot, &encodable)
+	if err != nil {
+		e.checkpointFaulty[originID] = struct{}{}
+		e.reportInvalidCheckpoint(originID)
+		logger.Warn().Err(err).Bool(logging.KeySuspicious, true).Msg("could not decode checkpoint")
+		return nil, false
+	}
+	snapshot := inmem.SnapshotFromEncodable(encodable)
+
+	// only accept checkpoints which allow us to skip ahead beyond the threshold
+	final := e.finalizedHeader.Get()
+	if encodable.Head == nil || encodable.Head.Height <= final.Height+e.checkpointThreshold {
+		logger.Debug().Uint64("local_height", final.Height).Msg("ignoring checkpoint which is not sufficiently ahead")
+		return nil, false
+	}
+
+	err = e.checkpointVerifier.Verify(snapshot, res.FinalityProof, res.FinalityProofQC)
+	if IsInvalidCheckpointError(err) {
+		e.checkpointFaulty[originID] = struct{}{}
+		e.reportInvalidCheckpoint(originID)
+		logger.Warn().Err(err).Bool(logging.KeySuspicious, true).Msg("received invalid checkpoint")
+		return nil, false
+	}
+	if errors.Is(err, ErrUnverifiableCheckpoint) {
+		logger.Info().Err(err).Msg("could not verify checkpoint, continuing with regular sync")
+		return nil, false
+	}
+	if err != nil {
+		// the checkpoint is served by a peer, hence we don't crash on it, but try the checkpoints of other peers
+		e.checkpointFaulty[originID] = struct{}{}
+		logger.Error().Err(err).Msg("unexpected error verifying checkpoint, requesting checkpoint from other peers")
+		return nil, false
+	}
+
+	e.checkpointDone = true
+	return snapshot, true
+}
+
+// reportInvalidCheckpoint reports the peer which served an invalid checkpoint to the networking layer.
+// While the peer is excluded from further snapshot requests by the engine itself, the report penalizes
+// it at the networking layer, such that repeated misbehavior across engines leads to disallow-listing.
+func (e *Engine) reportInvalidCheckpoint(originID flow.Identifier) {
+	e.con.ReportMisbehavior(alsp.MustNewMisbehaviorReport(originID, alsp.InvalidMessage))
+}
+
 // checkLoop will regularly scan for items that need requesting.
 func (e *Engine) checkLoop() {
 	pollChan := make(<-chan time.Time)
@@ -345,6 +527,7 @@ CheckLoop:
 			head := e.finalizedHeader.Get()
 			participants := e.participantsProvider.Identifiers()
 			ranges, batches := e.core.ScanPending(head)
+			e.peers.ExpireRequests()
 			e.sendRequests(participants, ranges, batches)
 		}
 	}
@@ -373,20 +556,73 @@ func (e *Engine) pollHeight() {
 		return
 	}
 	e.metrics.MessageSent(metrics.EngineSynchronization, metrics.MessageSyncRequest)
+
+	if e.checkpointThreshold > 0 {
+		e.requestCheckpoint(head, participants)
+	}
+}
+
+// requestCheckpoint sends a snapshot request to random nodes, which did not serve
+// invalid checkpoints before, if our peers reported a finalized height beyond
+// the checkpoint threshold.
+func (e *Engine) requestCheckpoint(head *flow.Header, participants flow.IdentifierList) {
+	e.checkpointLock.Lock()
+	defer e.checkpointLock.Unlock()
+
+	if e.checkpointDone || e.highestHeight <= head.Height+e.checkpointThreshold {
+		return
+	}
+
+	// forget about requests, whose responses we wouldn't accept anymore
+	for nonce, requested := range e.checkpointRequests {
+		if time.Since(requested) > checkpointRequestTimeout {
+			delete(e.checkpointRequests, nonce)
+		}
+	}
+
+	eligible := participants.Filter(func(nodeID flow.Identifier) bool {
+		_, faulty := e.checkpointFaulty[nodeID]
+		return !faulty
+	})
+
+	req := &messages.SnapshotRequest{
+		Nonce:  rand.Uint64(),
+		Height: head.Height,
+	}
+	e.log.Info().
+		Uint64("height", req.Height).
+		Uint64("highest_height", e.highestHeight).
+		Uint64("nonce", req.Nonce).
+		Msg("far behind, sending snapshot request")
+	err := e.con.Multicast(req, synccore.DefaultPollNodes, eligible...)
+	if err != nil {
+		e.log.Warn().Err(err).Msg("sending snapshot request failed")
+		return
+	}
+	e.checkpointRequests[req.Nonce] = time.Now()
+	e.metrics.MessageSent(metrics.EngineSynchronization, metrics.MessageSnapshotRequest)
 }
 
 // sendRequests sends a request for each range and batch using consensus participants from last finalized snapshot.
+// Each request is sent to the peers selected by the peer tracker, which prefers responsive peers.
 func (e *Engine) sendRequests(participants flow.IdentifierList, ranges []chainsync.Range, batches []chainsync.Batch) {
 	var errs *multierror.Error
 
 	for _, ran := range ranges {
+		ran := ran
 		req := &messages.RangeRequest{
 			Nonce:      rand.Uint64(),
 			FromHeight: ran.From,
 			ToHeight:   ran.To,
 		}
-		err := e.con.Multicast(req, synccore.DefaultBlockRequestNodes, participants...)
+		// track the request before sending it, so we don't miss fast responses
+		targets := e.peers.SelectPeers(participants, synccore.DefaultBlockRequestNodes)
+		e.peers.TrackRequest(req.Nonce, targets, ran.To, func(header *flow.Header) bool {
+			return header.Height >= ran.From && header.Height <= ran.To
+		})
+		err := e.con.Multicast(req, synccore.DefaultBlockRequestNodes, targets...)
 		if err != nil {
+			e.peers.UntrackRequest(req.Nonce)
 			errs = multierror.Append(errs, fmt.Errorf("could not submit range request: %w", err))
 			continue
 		}
@@ -404,8 +640,18 @@ func (e *Engine) sendRequests(participants flow.IdentifierList, ranges []chainsy
 			Nonce:    rand.Uint64(),
 			BlockIDs: batch.BlockIDs,
 		}
-		err := e.con.Multicast(req, synccore.DefaultBlockRequestNodes, participants...)
+		requested := make(map[flow.Identifier]struct{}, len(batch.BlockIDs))
+		for _, blockID := range batch.BlockIDs {
+			requested[blockID] = struct{}{}
+		}
+		targets := e.peers.SelectPeers(participants, synccore.DefaultBlockRequestNodes)
+		e.peers.TrackRequest(req.Nonce, targets, 0, func(header *flow.Header) bool {
+			_, ok := requested[header.ID()]
+			return ok
+		})
+		err := e.con.Multicast(req, synccore.DefaultBlockRequestNodes, targets...)
 		if err != nil {
+			e.peers.UntrackRequest(req.Nonce)
 			errs = multierror.Append(errs, fmt.Errorf("could not submit batch request: %w", err))
 			continue
 		}
@@ -1,6 +1,8 @@
 package synchronization
 
 import (
+	"encoding/json"
+	"fmt"
 	"io"
 	"math"
 	"math/rand"
@@ -13,6 +15,8 @@ import (
 	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
 
+	"github.com/onflow/flow-go/consensus/hotstuff/mocks"
+	"github.com/onflow/flow-go/consensus/hotstuff/model"
 	"github.com/onflow/flow-go/consensus/hotstuff/notifications/pubsub"
 	"github.com/onflow/flow-go/engine"
 	mockconsensus "github.com/onflow/flow-go/engine/consensus/mock"
@@ -24,11 +28,13 @@ import (
 	"github.com/onflow/flow-go/module/metrics"
 	module "github.com/onflow/flow-go/module/mock"
 	netint "github.com/onflow/flow-go/network"
+	"github.com/onflow/flow-go/network/alsp"
 	"github.com/onflow/flow-go/network/channels"
 	"github.com/onflow/flow-go/network/mocknetwork"
 	"github.com/onflow/flow-go/network/p2p/cache"
 	protocolint "github.com/onflow/flow-go/state/protocol"
 	protocolEvents "github.com/onflow/flow-go/state/protocol/events"
+	"github.com/onflow/flow-go/state/protocol/inmem"
 	protocol "github.com/onflow/flow-go/state/protocol/mock"
 	storerr "github.com/onflow/flow-go/storage"
 	storage "github.com/onflow/flow-go/storage/mock"
@@ -173,7 +179,7 @@ func (ss *SyncSuite) SetupTest() {
 
 	idCache, err := cache.NewProtocolStateIDCache(log, ss.state, protocolEvents.NewDistributor())
 	require.NoError(ss.T(), err, "could not create protocol state identity cache")
-	e, err := New(log, metrics, ss.net, ss.me, ss.blocks, ss.comp, ss.core, finalizedHeader,
+	e, err := New(log, metrics, metrics, ss.net, ss.me, ss.blocks, ss.comp, ss.core, finalizedHeader,
 		id.NewIdentityFilterIdentifierProvider(
 			filter.And(
 				filter.HasRole(flow.RoleConsensus),
@@ -484,6 +490,128 @@ func (ss *SyncSuite) TestPollHeight() {
 	ss.con.AssertExpectations(ss.T())
 }
 
+// TestRequestCheckpoint tests that we only request checkpoints if our peers are beyond the
+// checkpoint threshold, and never from peers which served invalid checkpoints before.
+func (ss *SyncSuite) TestRequestCheckpoint() {
+	ss.e.checkpointThreshold = 100
+	others := ss.participants[1:].NodeIDs()
+
+	// if our peers are within the threshold, we should not request a checkpoint
+	ss.e.highestHeight = ss.head.Height + ss.e.checkpointThreshold
+	ss.e.requestCheckpoint(ss.head, others)
+	ss.con.AssertNotCalled(ss.T(), "Multicast", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+
+	// if our peers are beyond the threshold, we should request a checkpoint from non-faulty peers
+	ss.e.highestHeight = ss.head.Height + ss.e.checkpointThreshold + 1
+	ss.e.checkpointFaulty[others[0]] = struct{}{}
+	ss.con.On("Multicast", mock.AnythingOfType("*messages.SnapshotRequest"), synccore.DefaultPollNodes, others[1]).Return(nil).Run(
+		func(args mock.Arguments) {
+			req := args.Get(0).(*messages.SnapshotRequest)
+			require.Equal(ss.T(), ss.head.Height, req.Height, "request should contain finalized height")
+		},
+	).Once()
+	ss.e.requestCheckpoint(ss.head, others)
+	ss.con.AssertExpectations(ss.T())
+	require.Len(ss.T(), ss.e.checkpointRequests, 1)
+
+	// once a checkpoint was accepted, we should not request any more checkpoints
+	ss.e.checkpointDone = true
+	ss.e.requestCheckpoint(ss.head, others)
+	ss.con.AssertNumberOfCalls(ss.T(), "Multicast", 1)
+}
+
+// TestOnSnapshotResponse tests that only requested and valid checkpoints are handed to the
+// checkpoint consumer, and that peers serving invalid checkpoints, checkpoints which can't be
+// verified or persisted are excluded from further snapshot requests.
+func (ss *SyncSuite) TestOnSnapshotResponse() {
+	checkpoint, proof, proofQC, state := checkpointFixture(ss.T(), ss.head.Height+1000)
+	validator := mocks.NewValidator(ss.T())
+	var consumed *inmem.Snapshot
+	ss.e.checkpointThreshold = 100
+	ss.e.checkpointVerifier = NewCheckpointVerifier(state, validator)
+	var consumerErr error
+	ss.e.checkpointConsumer = func(snapshot *inmem.Snapshot) error {
+		consumed = snapshot
+		return consumerErr
+	}
+
+	data, err := json.Marshal(checkpoint.Encodable())
+	require.NoError(ss.T(), err)
+	res := &messages.SnapshotResponse{
+		Nonce:           rand.Uint64(),
+		Snapshot:        data,
+		FinalityProof:   proof,
+		FinalityProofQC: proofQC,
+	}
+
+	// unsolicited responses should be ignored
+	ss.e.onSnapshotResponse(ss.participants[1].NodeID, res)
+	require.Nil(ss.T(), consumed)
+
+	// invalid checkpoints should be rejected, and their origin considered faulty and reported as misbehaving
+	ss.e.checkpointRequests[res.Nonce] = time.Now()
+	ss.con.On("ReportMisbehavior", mock.MatchedBy(func(report netint.MisbehaviorReport) bool {
+		return report.OriginId() == ss.participants[1].NodeID && report.Reason() == alsp.InvalidMessage
+	})).Once()
+	validator.On("ValidateQC", mock.Anything).Return(model.InvalidQCError{Err: fmt.Errorf("forged")}).Once()
+	ss.e.onSnapshotResponse(ss.participants[1].NodeID, res)
+	require.Nil(ss.T(), consumed)
+	require.Contains(ss.T(), ss.e.checkpointFaulty, ss.participants[1].NodeID)
+	ss.con.AssertNumberOfCalls(ss.T(), "ReportMisbehavior", 1)
+
+	// unexpected errors verifying a checkpoint should not crash the node, but exclude the peer
+	unverifiable := unittest.IdentifierFixture()
+	validator.On("ValidateQC", mock.Anything).Return(fmt.Errorf("unexpected")).Once()
+	ss.e.onSnapshotResponse(unverifiable, res)
+	require.Nil(ss.T(), consumed)
+	require.Contains(ss.T(), ss.e.checkpointFaulty, unverifiable)
+	ss.con.AssertNumberOfCalls(ss.T(), "ReportMisbehavior", 1)
+
+	// if the consumer fails to persist a valid checkpoint, checkpoints should be requested from other peers
+	consumerErr = fmt.Errorf("could not persist checkpoint")
+	validator.On("ValidateQC", mock.Anything).Return(nil).Times(3)
+	unpersisted := unittest.IdentifierFixture()
+	ss.e.onSnapshotResponse(unpersisted, res)
+	require.NotNil(ss.T(), consumed)
+	require.False(ss.T(), ss.e.checkpointDone)
+	require.Contains(ss.T(), ss.e.checkpointFaulty, unpersisted)
+
+	// valid checkpoints should be handed to the consumer
+	consumed = nil
+	consumerErr = nil
+	validator.On("ValidateQC", mock.Anything).Return(nil).Times(3)
+	ss.e.onSnapshotResponse(ss.participants[2].NodeID, res)
+	require.NotNil(ss.T(), consumed)
+	expected, err := checkpoint.Head()
+	require.NoError(ss.T(), err)
+	actual, err := consumed.Head()
+	require.NoError(ss.T(), err)
+	require.Equal(ss.T(), expected.ID(), actual.ID())
+	require.True(ss.T(), ss.e.checkpointDone)
+}
+
+// TestOnSnapshotRequestWithinTolerance tests that we don't serve checkpoints to nodes
+// which are within the sync tolerance or ahead of us.
+func (ss *SyncSuite) TestOnSnapshotRequestWithinTolerance() {
+	originID := unittest.IdentifierFixture()
+	req := &messages.SnapshotRequest{
+		Nonce:  rand.Uint64(),
+		Height: ss.head.Height - 1,
+	}
+
+	ss.core.On("WithinTolerance", ss.head, req.Height).Return(true).Once()
+	err := ss.e.requestHandler.onSnapshotRequest(originID, req)
+	require.NoError(ss.T(), err)
+
+	req.Height = ss.head.Height + 1
+	ss.core.On("WithinTolerance", ss.head, req.Height).Return(false).Once()
+	err = ss.e.requestHandler.onSnapshotRequest(originID, req)
+	require.NoError(ss.T(), err)
+
+	ss.con.AssertNotCalled(ss.T(), "Unicast", mock.Anything, mock.Anything)
+	ss.core.AssertExpectations(ss.T())
+}
+
 func (ss *SyncSuite) TestSendRequests() {
 
 	ranges := unittest.RangeListFixture(1)
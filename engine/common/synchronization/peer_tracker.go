@@ -0,0 +1,318 @@
+package synchronization
+
+import (
+	"math/rand"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/model/messages"
+	"github.com/onflow/flow-go/module"
+)
+
+const (
+	// peerResponseTimeout is the duration after which a range or batch request,
+	// which a peer did not respond to, counts as failed for this peer.
+	peerResponseTimeout = 10 * time.Second
+
+	// peerExclusionThreshold is the number of consecutive failed requests after
+	// which a peer is temporarily excluded from block requests. Peers serving
+	// invalid responses are excluded immediately.
+	peerExclusionThreshold = 3
+
+	// minPeerExclusion is the duration a peer is excluded for the first time.
+	// It doubles with every further exclusion of the same peer, up to maxPeerExclusion.
+	minPeerExclusion = 30 * time.Second
+	maxPeerExclusion = 10 * time.Minute
+
+	// peerLatencyWeight is the weight of a new sample in the moving average of a peer's response latency.
+	peerLatencyWeight = 0.25
+)
+
+// requestFailure is the reason why a peer did not answer a block request successfully.
+type requestFailure string
+
+const (
+	failureTimeout requestFailure = "timeout" // the peer did not respond in time
+	failureEmpty   requestFailure = "empty"   // the peer responded without blocks
+	failureInvalid requestFailure = "invalid" // the peer responded with blocks we didn't request or which are malformed
+)
+
+// peerStats is the sync quality of a single peer.
+type peerStats struct {
+	height        uint64        // highest finalized height reported by the peer, 0 if it reported none yet
+	latency       time.Duration // moving average of the response latency, 0 if there is no sample yet
+	failures      uint          // number of consecutive failed requests
+	exclusions    uint          // number of times the peer was excluded, determines the exclusion duration
+	excludedUntil time.Time
+}
+
+// pendingBlockRequest is a range or batch request awaiting responses.
+type pendingBlockRequest struct {
+	sent      time.Time
+	toHeight  uint64                         // highest requested height, 0 if unknown as for batch requests
+	peers     map[flow.Identifier]struct{}   // peers which have not responded yet
+	requested func(header *flow.Header) bool // whether the block with the given header was requested
+}
+
+// peerTracker tracks the quality of the peers we request blocks from, based on
+// their responses to our range and batch requests: the response latency, and
+// requests which time out or are answered with empty or invalid responses.
+// It selects the peers to send requests to, preferring responsive peers and
+// temporarily excluding peers which repeatedly fail to respond or serve invalid
+// responses. A range request which times out or is answered without blocks only
+// counts as failed for peers whose reported finalized height covers the range, as
+// peers which are behind us can't serve it. Blocks which pass the checks here, but
+// turn out to be invalid upon validation by the compliance layer, are not accounted for.
+//
+// peerTracker is safe for concurrent use by multiple goroutines.
+type peerTracker struct {
+	metrics            module.ChainSyncPeerMetrics
+	responseTimeout    time.Duration
+	exclusionThreshold uint
+	minExclusion       time.Duration
+	maxExclusion       time.Duration
+
+	mu       sync.Mutex
+	peers    map[flow.Identifier]*peerStats
+	requests map[uint64]*pendingBlockRequest // outstanding requests by nonce
+}
+
+func newPeerTracker(metrics module.ChainSyncPeerMetrics) *peerTracker {
+	return &peerTracker{
+		metrics:            metrics,
+		responseTimeout:    peerResponseTimeout,
+		exclusionThreshold: peerExclusionThreshold,
+		minExclusion:       minPeerExclusion,
+		maxExclusion:       maxPeerExclusion,
+		peers:              make(map[flow.Identifier]*peerStats),
+		requests:           make(map[uint64]*pendingBlockRequest),
+	}
+}
+
+// SelectPeers selects up to count of the given participants to send a block request to.
+// Currently excluded peers are skipped, unless all participants are excluded, so that
+// synchronization never stalls. Peers with fewer consecutive failures and a lower response
+// latency are preferred, and peers without samples are treated as the most responsive ones,
+// so that new peers get a chance to prove themselves. The last slot is filled with a random
+// peer among the remaining ones, so that we keep sampling peers other than the best ones.
+func (t *peerTracker) SelectPeers(participants flow.IdentifierList, count uint) flow.IdentifierList {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := time.Now()
+	eligible := make(flow.IdentifierList, 0, len(participants))
+	lookup := make(map[flow.Identifier]struct{}, len(participants))
+	for _, nodeID := range participants {
+		lookup[nodeID] = struct{}{}
+		stats, ok := t.peers[nodeID]
+		if ok && now.Before(stats.excludedUntil) {
+			continue
+		}
+		eligible = append(eligible, nodeID)
+	}
+	t.metrics.ExcludedSyncPeers(len(participants) - len(eligible))
+
+	// forget about peers which are no longer participants
+	for nodeID := range t.peers {
+		if _, ok := lookup[nodeID]; !ok {
+			delete(t.peers, nodeID)
+		}
+	}
+
+	if len(eligible) == 0 {
+		eligible = participants.Copy()
+	}
+	if count == 0 {
+		return nil
+	}
+
+	// shuffle first, so that peers with equal stats are selected randomly
+	rand.Shuffle(len(eligible), func(i, j int) {
+		eligible[i], eligible[j] = eligible[j], eligible[i]
+	})
+	if uint(len(eligible)) <= count {
+		return eligible
+	}
+	sort.SliceStable(eligible, func(i, j int) bool {
+		return t.stats(eligible[i]).ranksBefore(t.stats(eligible[j]))
+	})
+
+	best := int(count) - 1
+	random := eligible[best+rand.Intn(len(eligible)-best)]
+	return append(eligible[:best:best], random)
+}
+
+// TrackRequest records a range or batch request with the given nonce, which is sent to the given peers.
+// The highest requested height is given for range requests, and 0 for batch requests, whose heights are
+// unknown. The requested function returns whether the block with the given header is part of the request.
+// Requests must be tracked before they are sent, so that no response arrives before.
+func (t *peerTracker) TrackRequest(nonce uint64, peers flow.IdentifierList, toHeight uint64, requested func(header *flow.Header) bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	pending := &pendingBlockRequest{
+		sent:      time.Now(),
+		toHeight:  toHeight,
+		peers:     make(map[flow.Identifier]struct{}, len(peers)),
+		requested: requested,
+	}
+	for _, nodeID := range peers {
+		pending.peers[nodeID] = struct{}{}
+	}
+	t.requests[nonce] = pending
+}
+
+// OnHeight records the finalized height reported by the given peer in a sync response.
+func (t *peerTracker) OnHeight(nodeID flow.Identifier, height uint64) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	stats := t.getOrCreate(nodeID)
+	if height > stats.height {
+		stats.height = height
+	}
+}
+
+// UntrackRequest forgets about the request with the given nonce, for example because it could not be sent.
+func (t *peerTracker) UntrackRequest(nonce uint64) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	delete(t.requests, nonce)
+}
+
+// OnBlockResponse accounts the given block response to the quality of the origin peer.
+// It returns whether the response failed, in which case its blocks should be dropped,
+// together with the reason. Responses we didn't request from the origin, for example
+// late responses to requests which already timed out, are not accounted for and never fail,
+// and neither are empty responses to range requests beyond the origin's reported height.
+func (t *peerTracker) OnBlockResponse(originID flow.Identifier, res *messages.BlockResponse) (requestFailure, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	pending, ok := t.requests[res.Nonce]
+	if !ok {
+		return "", false
+	}
+	if _, ok := pending.peers[originID]; !ok {
+		return "", false
+	}
+	delete(pending.peers, originID)
+	if len(pending.peers) == 0 {
+		delete(t.requests, res.Nonce)
+	}
+
+	now := time.Now()
+	if len(res.Blocks) == 0 {
+		if !t.covers(originID, pending) {
+			return "", false
+		}
+		t.failed(originID, failureEmpty, now)
+		return failureEmpty, true
+	}
+	for i := range res.Blocks {
+		block := &res.Blocks[i]
+		if !pending.requested(&block.Header) || block.ToInternal().Payload.Hash() != block.Header.PayloadHash {
+			t.failed(originID, failureInvalid, now)
+			return failureInvalid, true
+		}
+	}
+
+	t.succeeded(originID, now.Sub(pending.sent))
+	return "", false
+}
+
+// ExpireRequests accounts requests, which were not answered within the response
+// timeout, as failed for the peers which did not respond, unless the request is a
+// range request beyond the peer's reported height.
+func (t *peerTracker) ExpireRequests() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := time.Now()
+	for nonce, pending := range t.requests {
+		if now.Sub(pending.sent) < t.responseTimeout {
+			continue
+		}
+		for nodeID := range pending.peers {
+			if !t.covers(nodeID, pending) {
+				continue
+			}
+			t.failed(nodeID, failureTimeout, now)
+		}
+		delete(t.requests, nonce)
+	}
+}
+
+// succeeded records a successful response of the given peer. Must be called with the lock held.
+func (t *peerTracker) succeeded(nodeID flow.Identifier, latency time.Duration) {
+	t.metrics.BlockResponseReceived(latency)
+
+	stats := t.getOrCreate(nodeID)
+	stats.failures = 0
+	if stats.latency == 0 {
+		stats.latency = latency
+		return
+	}
+	stats.latency += time.Duration(peerLatencyWeight * float64(latency-stats.latency))
+}
+
+// failed records a failed request of the given peer, and excludes the peer if it served an invalid
+// response or reached the threshold of consecutive failures. Must be called with the lock held.
+func (t *peerTracker) failed(nodeID flow.Identifier, reason requestFailure, now time.Time) {
+	t.metrics.BlockRequestFailed(string(reason))
+
+	stats := t.getOrCreate(nodeID)
+	stats.failures++
+	if reason != failureInvalid && stats.failures < t.exclusionThreshold {
+		return
+	}
+
+	exclusion := t.minExclusion
+	for i := uint(0); i < stats.exclusions && exclusion < t.maxExclusion; i++ {
+		exclusion *= 2
+	}
+	if exclusion > t.maxExclusion {
+		exclusion = t.maxExclusion
+	}
+	stats.excludedUntil = now.Add(exclusion)
+	stats.exclusions++
+	stats.failures = 0
+	t.metrics.SyncPeerExcluded()
+}
+
+// covers returns whether the given peer is expected to serve the given request, which is the case for
+// batch requests, and for range requests up to the peer's reported height. Must be called with the lock held.
+func (t *peerTracker) covers(nodeID flow.Identifier, pending *pendingBlockRequest) bool {
+	return pending.toHeight == 0 || t.stats(nodeID).height >= pending.toHeight
+}
+
+// stats returns the stats of the given peer, or empty stats if we have no samples for it.
+// Must be called with the lock held.
+func (t *peerTracker) stats(nodeID flow.Identifier) peerStats {
+	stats, ok := t.peers[nodeID]
+	if !ok {
+		return peerStats{}
+	}
+	return *stats
+}
+
+// getOrCreate returns the stats of the given peer, creating them if necessary. Must be called with the lock held.
+func (t *peerTracker) getOrCreate(nodeID flow.Identifier) *peerStats {
+	stats, ok := t.peers[nodeID]
+	if !ok {
+		stats = &peerStats{}
+		t.peers[nodeID] = stats
+	}
+	return stats
+}
+
+// ranksBefore returns whether a peer with these stats is preferred over a peer with the other stats.
+func (s peerStats) ranksBefore(other peerStats) bool {
+	if s.failures != other.failures {
+		return s.failures < other.failures
+	}
+	return s.latency < other.latency
+}
@@ -0,0 +1,204 @@
+package synchronization
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/model/messages"
+	"github.com/onflow/flow-go/module/metrics"
+	modulemock "github.com/onflow/flow-go/module/mock"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+// requestedRange returns a function accepting blocks within the given height range.
+func requestedRange(from, to uint64) func(header *flow.Header) bool {
+	return func(header *flow.Header) bool {
+		return header.Height >= from && header.Height <= to
+	}
+}
+
+// blockResponse returns a response with the given nonce, containing the given blocks.
+func blockResponse(nonce uint64, blocks ...*flow.Block) *messages.BlockResponse {
+	res := &messages.BlockResponse{Nonce: nonce}
+	for _, block := range blocks {
+		res.Blocks = append(res.Blocks, messages.UntrustedBlockFromInternal(block))
+	}
+	return res
+}
+
+// TestPeerTracker_SelectPeers tests that the most responsive peers are selected,
+// while the last slot is filled with a random other peer.
+func TestPeerTracker_SelectPeers(t *testing.T) {
+	tracker := newPeerTracker(metrics.NewNoopCollector())
+	participants := unittest.IdentifierListFixture(10)
+
+	// all peers have samples, the first two are the most responsive ones
+	for i, nodeID := range participants {
+		tracker.peers[nodeID] = &peerStats{latency: time.Duration(i+1) * time.Second}
+	}
+
+	randomlySelected := make(map[flow.Identifier]struct{})
+	for i := 0; i < 100; i++ {
+		selected := tracker.SelectPeers(participants, 3)
+		require.Len(t, selected, 3)
+		require.Equal(t, participants[:2], selected[:2])
+		require.NotContains(t, participants[:2], selected[2])
+		randomlySelected[selected[2]] = struct{}{}
+	}
+	require.Greater(t, len(randomlySelected), 1)
+
+	// peers without samples are preferred, so they get a chance to prove themselves
+	newPeer := unittest.IdentifierFixture()
+	selected := tracker.SelectPeers(append(participants, newPeer), 3)
+	require.Equal(t, newPeer, selected[0])
+
+	// stats of peers which are no longer participants are forgotten
+	tracker.SelectPeers(participants[:5], 3)
+	require.Len(t, tracker.peers, 5)
+}
+
+// TestPeerTracker_Exclusion tests that peers are excluded after serving an invalid response,
+// or after repeatedly failing to respond, and that all participants are selected if all of them are excluded.
+func TestPeerTracker_Exclusion(t *testing.T) {
+	tracker := newPeerTracker(metrics.NewNoopCollector())
+	tracker.responseTimeout = 0
+	participants := unittest.IdentifierListFixture(3)
+	invalid, silent, good := participants[0], participants[1], participants[2]
+	block := unittest.BlockFixture()
+	block.Header.Height = 10
+
+	// the invalid peer is excluded right away
+	tracker.TrackRequest(1, flow.IdentifierList{invalid}, 30, requestedRange(20, 30))
+	failure, failed := tracker.OnBlockResponse(invalid, blockResponse(1, &block))
+	require.True(t, failed)
+	require.Equal(t, failureInvalid, failure)
+	require.ElementsMatch(t, flow.IdentifierList{silent, good}, tracker.SelectPeers(participants, 3))
+
+	// the silent peer is excluded after reaching the threshold of consecutive failures
+	tracker.OnHeight(silent, 10)
+	for i := uint64(0); i < peerExclusionThreshold; i++ {
+		require.ElementsMatch(t, flow.IdentifierList{silent, good}, tracker.SelectPeers(participants, 3))
+		tracker.TrackRequest(i+2, flow.IdentifierList{silent}, 10, requestedRange(10, 10))
+		tracker.ExpireRequests()
+	}
+	require.Equal(t, flow.IdentifierList{good}, tracker.SelectPeers(participants, 3))
+
+	// if all participants are excluded, we fall back to selecting among all of them
+	tracker.failed(good, failureInvalid, time.Now())
+	require.ElementsMatch(t, participants, tracker.SelectPeers(participants, 3))
+}
+
+// TestPeerTracker_ExclusionDuration tests that the exclusion duration doubles with
+// every exclusion of the same peer, up to the maximum.
+func TestPeerTracker_ExclusionDuration(t *testing.T) {
+	tracker := newPeerTracker(metrics.NewNoopCollector())
+	nodeID := unittest.IdentifierFixture()
+
+	expected := minPeerExclusion
+	for i := 0; i < 10; i++ {
+		now := time.Now()
+		tracker.failed(nodeID, failureInvalid, now)
+		require.Equal(t, now.Add(expected), tracker.peers[nodeID].excludedUntil)
+		expected *= 2
+		if expected > maxPeerExclusion {
+			expected = maxPeerExclusion
+		}
+	}
+}
+
+// TestPeerTracker_OnBlockResponse tests that responses are accounted to the responding
+// peer, and that only solicited responses can fail.
+func TestPeerTracker_OnBlockResponse(t *testing.T) {
+	peerMetrics := modulemock.NewChainSyncPeerMetrics(t)
+	tracker := newPeerTracker(peerMetrics)
+	originID := unittest.IdentifierFixture()
+	block := unittest.BlockFixture()
+	block.Header.Height = 10
+
+	t.Run("unsolicited response", func(t *testing.T) {
+		_, failed := tracker.OnBlockResponse(originID, blockResponse(1))
+		require.False(t, failed)
+
+		tracker.TrackRequest(1, unittest.IdentifierListFixture(1), 10, requestedRange(10, 10))
+		_, failed = tracker.OnBlockResponse(originID, blockResponse(1, &block))
+		require.False(t, failed)
+		require.NotContains(t, tracker.peers, originID)
+	})
+
+	t.Run("empty response", func(t *testing.T) {
+		peerMetrics.On("BlockRequestFailed", string(failureEmpty)).Once()
+		tracker.OnHeight(originID, 10)
+		tracker.TrackRequest(2, flow.IdentifierList{originID}, 10, requestedRange(10, 10))
+		failure, failed := tracker.OnBlockResponse(originID, blockResponse(2))
+		require.True(t, failed)
+		require.Equal(t, failureEmpty, failure)
+		require.Equal(t, uint(1), tracker.peers[originID].failures)
+	})
+
+	t.Run("block with mismatching payload", func(t *testing.T) {
+		peerMetrics.On("BlockRequestFailed", string(failureInvalid)).Once()
+		peerMetrics.On("SyncPeerExcluded").Once()
+		forged := unittest.BlockFixture()
+		forged.Header.Height = 10
+		forged.Header.PayloadHash = unittest.IdentifierFixture()
+		tracker.TrackRequest(3, flow.IdentifierList{originID}, 10, requestedRange(10, 10))
+		failure, failed := tracker.OnBlockResponse(originID, blockResponse(3, &forged))
+		require.True(t, failed)
+		require.Equal(t, failureInvalid, failure)
+		require.True(t, tracker.peers[originID].excludedUntil.After(time.Now()))
+	})
+
+	t.Run("valid response", func(t *testing.T) {
+		peerMetrics.On("BlockResponseReceived", mock.Anything).Once()
+		tracker.peers[originID].failures = 2
+		tracker.TrackRequest(4, flow.IdentifierList{originID}, 10, requestedRange(10, 10))
+		_, failed := tracker.OnBlockResponse(originID, blockResponse(4, &block))
+		require.False(t, failed)
+		require.Zero(t, tracker.peers[originID].failures)
+		require.NotContains(t, tracker.requests, uint64(4))
+
+		// a second response to the same request is not accounted for
+		_, failed = tracker.OnBlockResponse(originID, blockResponse(4))
+		require.False(t, failed)
+	})
+}
+
+// TestPeerTracker_RangeBeyondHeight tests that range requests, which time out or are answered without blocks,
+// only count as failed for peers whose reported height covers the range, while batch requests always count.
+func TestPeerTracker_RangeBeyondHeight(t *testing.T) {
+	tracker := newPeerTracker(metrics.NewNoopCollector())
+	tracker.responseTimeout = 0
+	participants := unittest.IdentifierListFixture(3)
+	behind, unknown, ahead := participants[0], participants[1], participants[2]
+	tracker.OnHeight(behind, 15)
+	tracker.OnHeight(ahead, 25)
+	// a lower reported height doesn't lower the known height of the peer
+	tracker.OnHeight(ahead, 5)
+
+	// peers behind the range, or without a reported height, don't fail on timeouts and empty responses
+	tracker.TrackRequest(1, participants, 20, requestedRange(10, 20))
+	_, failed := tracker.OnBlockResponse(behind, blockResponse(1))
+	require.False(t, failed)
+	failure, failed := tracker.OnBlockResponse(ahead, blockResponse(1))
+	require.True(t, failed)
+	require.Equal(t, failureEmpty, failure)
+	tracker.ExpireRequests()
+	require.Zero(t, tracker.stats(behind).failures)
+	require.Zero(t, tracker.stats(unknown).failures)
+	require.Equal(t, uint(1), tracker.stats(ahead).failures)
+
+	// once the peer is beyond the range, timeouts count
+	tracker.OnHeight(behind, 20)
+	tracker.TrackRequest(2, flow.IdentifierList{behind}, 20, requestedRange(10, 20))
+	tracker.ExpireRequests()
+	require.Equal(t, uint(1), tracker.stats(behind).failures)
+
+	// the heights of batch requests are unknown, so timeouts always count
+	tracker.TrackRequest(3, flow.IdentifierList{unknown}, 0, requestedRange(10, 20))
+	tracker.ExpireRequests()
+	require.Equal(t, uint(1), tracker.stats(unknown).failures)
+}
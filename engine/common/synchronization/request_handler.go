@@ -1,8 +1,10 @@
 package synchronization
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
+	"sync"
 
 	"github.com/rs/zerolog"
 
@@ -14,6 +16,7 @@ import (
 	"github.com/onflow/flow-go/module/lifecycle"
 	"github.com/onflow/flow-go/module/metrics"
 	"github.com/onflow/flow-go/network/channels"
+	"github.com/onflow/flow-go/state/protocol/inmem"
 	"github.com/onflow/flow-go/storage"
 	"github.com/onflow/flow-go/utils/logging"
 )
@@ -27,6 +30,9 @@ const defaultRangeRequestQueueCapacity = 500
 // defaultSyncRequestQueueCapacity maximum capacity of batch requests queue
 const defaultBatchRequestQueueCapacity = 500
 
+// defaultSnapshotRequestQueueCapacity maximum capacity of snapshot requests queue
+const defaultSnapshotRequestQueueCapacity = 100
+
 // defaultEngineRequestsWorkers number of workers to dispatch events for requests
 const defaultEngineRequestsWorkers = 8
 
@@ -43,10 +49,18 @@ type RequestHandler struct {
 	finalizedHeader *FinalizedHeaderCache
 	responseSender  ResponseSender
 
-	pendingSyncRequests   engine.MessageStore    // message store for *message.SyncRequest
-	pendingBatchRequests  engine.MessageStore    // message store for *message.BatchRequest
-	pendingRangeRequests  engine.MessageStore    // message store for *message.RangeRequest
-	requestMessageHandler *engine.MessageHandler // message handler responsible for request processing
+	pendingSyncRequests     engine.MessageStore    // message store for *message.SyncRequest
+	pendingBatchRequests    engine.MessageStore    // message store for *message.BatchRequest
+	pendingRangeRequests    engine.MessageStore    // message store for *message.RangeRequest
+	pendingSnapshotRequests engine.MessageStore    // message store for *message.SnapshotRequest
+	requestMessageHandler   *engine.MessageHandler // message handler responsible for request processing
+
+	// checkpoint caches the snapshot served to nodes far behind, as building it
+	// requires reading the entire sealing segment from the database. It is rebuilt
+	// at most once per finalized block.
+	checkpointLock sync.Mutex
+	checkpoint     *messages.SnapshotResponse
+	checkpointID   flow.Identifier // ID of the finalized block the cached checkpoint refers to
 
 	queueMissingHeights bool // true if missing heights should be added to download queue
 }
@@ -107,6 +121,7 @@ func (r *RequestHandler) setupRequestMessageHandler() {
 	r.pendingSyncRequests = NewRequestHeap(defaultSyncRequestQueueCapacity)
 	r.pendingRangeRequests = NewRequestHeap(defaultRangeRequestQueueCapacity)
 	r.pendingBatchRequests = NewRequestHeap(defaultBatchRequestQueueCapacity)
+	r.pendingSnapshotRequests = NewRequestHeap(defaultSnapshotRequestQueueCapacity)
 
 	// define message queueing behaviour
 	r.requestMessageHandler = engine.NewMessageHandler(
@@ -142,6 +157,16 @@ func (r *RequestHandler) setupRequestMessageHandler() {
 			},
 			Store: r.pendingBatchRequests,
 		},
+		engine.Pattern{
+			Match: func(msg *engine.Message) bool {
+				_, ok := msg.Payload.(*messages.SnapshotRequest)
+				if ok {
+					r.metrics.MessageReceived(metrics.EngineSynchronization, metrics.MessageSnapshotRequest)
+				}
+				return ok
+			},
+			Store: r.pendingSnapshotRequests,
+		},
 	)
 }
 
@@ -221,6 +246,10 @@ func (r *RequestHandler) onRangeRequest(originID flow.Identifier, req *messages.
 	blocks := make([]messages.UntrustedBlock, 0, req.ToHeight-req.FromHeight+1)
 	for height := req.FromHeight; height <= req.ToHeight; height++ {
 		block, err := r.blocks.ByHeight(height)
+		if errors.Is(err, storage.ErrPruned) {
+			logger.Debug().Uint64("height", height).Msg("skipping pruned height")
+			continue
+		}
 		if errors.Is(err, storage.ErrNotFound) {
 			logger.Error().Uint64("height", height).Msg("skipping unknown heights")
 			break
@@ -293,6 +322,10 @@ func (r *RequestHandler) onBatchRequest(originID flow.Identifier, req *messages.
 	blocks := make([]messages.UntrustedBlock, 0, len(blockIDs))
 	for blockID := range blockIDs {
 		block, err := r.blocks.ByID(blockID)
+		if errors.Is(err, storage.ErrPruned) {
+			logger.Debug().Hex("block_id", blockID[:]).Msg("skipping pruned block")
+			continue
+		}
 		if errors.Is(err, storage.ErrNotFound) {
 			logger.Debug().Hex("block_id", blockID[:]).Msg("skipping unknown block")
 			continue
@@ -324,6 +357,119 @@ func (r *RequestHandler) onBatchRequest(originID flow.Identifier, req *messages.
 	return nil
 }
 
+// onSnapshotRequest processes a request for a protocol state snapshot of a node
+// which is far behind. We only respond if we are ahead of the requester beyond
+// the sync tolerance and if we can prove finality of our latest finalized block.
+func (r *RequestHandler) onSnapshotRequest(originID flow.Identifier, req *messages.SnapshotRequest) error {
+	final := r.finalizedHeader.Get()
+
+	logger := r.log.With().Str("origin_id", originID.String()).Logger()
+	logger.Debug().
+		Uint64("origin_height", req.Height).
+		Uint64("local_height", final.Height).
+		Msg("received new snapshot request")
+
+	// don't bother sending a response if we're within tolerance or if we're
+	// behind the requester
+	if r.core.WithinTolerance(final, req.Height) || req.Height > final.Height {
+		return nil
+	}
+
+	checkpoint, err := r.checkpointAt(final)
+	if err != nil {
+		return fmt.Errorf("could not build checkpoint at finalized block %x: %w", final.ID(), err)
+	}
+	if checkpoint == nil {
+		logger.Debug().Uint64("height", final.Height).Msg("skipping snapshot response without finality proof")
+		return nil
+	}
+
+	// send the response
+	res := &messages.SnapshotResponse{
+		Nonce:           req.Nonce,
+		Snapshot:        checkpoint.Snapshot,
+		FinalityProof:   checkpoint.FinalityProof,
+		FinalityProofQC: checkpoint.FinalityProofQC,
+	}
+	err = r.responseSender.SendResponse(res, originID)
+	if err != nil {
+		logger.Warn().Err(err).Msg("sending snapshot response failed")
+		return nil
+	}
+	r.metrics.MessageSent(metrics.EngineSynchronization, metrics.MessageSnapshotResponse)
+
+	return nil
+}
+
+// checkpointAt returns the checkpoint for the given finalized block, building and
+// caching it if necessary. The returned response carries no nonce. It returns nil
+// if the finality of the block can not be proven yet, because we don't know a
+// certified direct child of it.
+// No errors are expected during normal operation.
+func (r *RequestHandler) checkpointAt(final *flow.Header) (*messages.SnapshotResponse, error) {
+	r.checkpointLock.Lock()
+	defer r.checkpointLock.Unlock()
+
+	finalID := final.ID()
+	if r.checkpoint != nil && r.checkpointID == finalID {
+		return r.checkpoint, nil
+	}
+
+	state := r.finalizedHeader.state
+	snapshot := state.AtBlockID(finalID)
+
+	// find a direct child of the finalized block, which is certified and whose view
+	// is exactly one higher, to prove finality by the 2-chain rule
+	descendants, err := snapshot.Descendants()
+	if err != nil {
+		return nil, fmt.Errorf("could not get descendants: %w", err)
+	}
+	var (
+		proof   *flow.Header
+		proofQC *flow.QuorumCertificate
+	)
+	for _, descendantID := range descendants {
+		header, err := state.AtBlockID(descendantID).Head()
+		if err != nil {
+			return nil, fmt.Errorf("could not get descendant %x: %w", descendantID, err)
+		}
+		if header.ParentID != finalID || header.View != final.View+1 {
+			continue
+		}
+		qc, err := state.AtBlockID(descendantID).QuorumCertificate()
+		if errors.Is(err, storage.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("could not get QC for descendant %x: %w", descendantID, err)
+		}
+		proof = header
+		proofQC = qc
+		break
+	}
+	if proof == nil {
+		return nil, nil
+	}
+
+	serializable, err := inmem.FromSnapshot(snapshot)
+	if err != nil {
+		return nil, fmt.Errorf("could not convert snapshot: %w", err)
+	}
+	data, err := json.Marshal(serializable.Encodable())
+	if err != nil {
+		return nil, fmt.Errorf("could not encode snapshot: %w", err)
+	}
+
+	r.checkpoint = &messages.SnapshotResponse{
+		Snapshot:        data,
+		FinalityProof:   proof,
+		FinalityProofQC: proofQC,
+	}
+	r.checkpointID = finalID
+
+	return r.checkpoint, nil
+}
+
 // processAvailableRequests is processor of pending events which drives events from networking layer to business logic.
 func (r *RequestHandler) processAvailableRequests() error {
 	for {
@@ -360,6 +506,15 @@ func (r *RequestHandler) processAvailableRequests() error {
 			continue
 		}
 
+		msg, ok = r.pendingSnapshotRequests.Get()
+		if ok {
+			err := r.onSnapshotRequest(msg.OriginID, msg.Payload.(*messages.SnapshotRequest))
+			if err != nil {
+				return fmt.Errorf("processing snapshot request failed: %w", err)
+			}
+			continue
+		}
+
 		// when there is no more messages in the queue, back to the loop to wait
 		// for the next incoming message to arrive.
 		return nil
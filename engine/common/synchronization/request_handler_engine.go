@@ -33,6 +33,11 @@ func (r *ResponseSenderImpl) SendResponse(res interface{}, target flow.Identifie
 		if err != nil {
 			return fmt.Errorf("could not unicast sync response to target %x: %w", target, err)
 		}
+	case *messages.SnapshotResponse:
+		err := r.con.Unicast(res, target)
+		if err != nil {
+			return fmt.Errorf("could not unicast snapshot response to target %x: %w", target, err)
+		}
 	default:
 		return fmt.Errorf("unable to unicast unexpected response %+v", res)
 	}
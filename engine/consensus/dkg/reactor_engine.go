@@ -290,6 +290,11 @@ func (e *ReactorEngine) handleEpochCommittedPhaseStarted(currentEpochCounter uin
 	}
 
 	myBeaconPrivKey, err := e.dkgState.RetrieveMyBeaconPrivateKey(nextEpochCounter)
+	if errors.Is(err, storage.ErrNotFound) {
+		// A recovery epoch committed during epoch fallback mode re-uses the DKG of the
+		// current epoch, in which case no DKG was run and we re-use our current key.
+		myBeaconPrivKey, err = e.reuseCurrentBeaconKey(firstBlock, currentEpochCounter, nextDKG)
+	}
 	if errors.Is(err, storage.ErrNotFound) {
 		log.Warn().Msg("checking beacon key consistency: no key found")
 		err := e.dkgState.SetDKGEndState(nextEpochCounter, flow.DKGEndStateNoKey)
@@ -335,6 +340,37 @@ func (e *ReactorEngine) handleEpochCommittedPhaseStarted(currentEpochCounter uin
 	log.Info().Msgf("successfully ended DKG, my beacon pub key for epoch %d is %s", nextEpochCounter, localPubKey)
 }
 
+// reuseCurrentBeaconKey handles the case where the next epoch re-uses the DKG of the
+// current epoch, which is the case for recovery epochs committed during epoch fallback
+// mode. If the group keys of both DKGs match, we copy our beacon private key for the
+// current epoch to the next epoch and return it. The caller must still check the key's
+// consistency with the next epoch's DKG.
+// Error returns:
+//   - storage.ErrNotFound if the next epoch does not re-use the current DKG,
+//     or if we have no beacon private key for the current epoch
+func (e *ReactorEngine) reuseCurrentBeaconKey(firstBlock *flow.Header, currentEpochCounter uint64, nextDKG protocol.DKG) (crypto.PrivateKey, error) {
+	currentDKG, err := e.State.AtBlockID(firstBlock.ID()).Epochs().Current().DKG()
+	if err != nil {
+		return nil, fmt.Errorf("could not retrieve current DKG info: %w", err)
+	}
+	if !currentDKG.GroupKey().Equals(nextDKG.GroupKey()) {
+		return nil, storage.ErrNotFound
+	}
+
+	key, err := e.dkgState.RetrieveMyBeaconPrivateKey(currentEpochCounter)
+	if err != nil {
+		return nil, fmt.Errorf("could not retrieve beacon private key for current epoch: %w", err)
+	}
+	err = e.dkgState.InsertMyBeaconPrivateKey(currentEpochCounter+1, key)
+	if err != nil {
+		return nil, fmt.Errorf("could not store re-used beacon private key for next epoch: %w", err)
+	}
+	e.log.Info().
+		Uint64("cur_epoch", currentEpochCounter).
+		Msg("next epoch re-uses the current DKG, re-using current beacon private key")
+	return key, nil
+}
+
 // TODO document error returns
 func (e *ReactorEngine) getDKGInfo(firstBlockID flow.Identifier) (*dkgInfo, error) {
 	currEpoch := e.State.AtBlockID(firstBlockID).Epochs().Current()
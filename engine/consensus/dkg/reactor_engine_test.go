@@ -265,6 +265,9 @@ type ReactorEngineSuite_CommittedPhase struct {
 	epochCounter         uint64            // current epoch counter
 	myLocalBeaconKey     crypto.PrivateKey // my locally computed beacon key
 	myGlobalBeaconPubKey crypto.PublicKey  // my public key, as dictated by global DKG
+	myCurrentBeaconKey   crypto.PrivateKey // my beacon key for the current epoch
+	currentGroupKey      crypto.PublicKey  // group key of the current epoch's DKG
+	nextGroupKey         crypto.PublicKey  // group key of the next epoch's DKG
 	dkgEndState          flow.DKGEndState  // backend for DGKState.
 	firstBlock           *flow.Header      // first block of EpochCommitted phase
 	warnsLogged          int               // count # of warn-level logs
@@ -298,6 +301,10 @@ func (suite *ReactorEngineSuite_CommittedPhase) SetupTest() {
 	// by default we seed the test suite with consistent keys
 	suite.myLocalBeaconKey = unittest.RandomBeaconPriv().PrivateKey
 	suite.myGlobalBeaconPubKey = suite.myLocalBeaconKey.PublicKey()
+	// by default the next epoch runs a new DKG
+	suite.myCurrentBeaconKey = unittest.RandomBeaconPriv().PrivateKey
+	suite.currentGroupKey = unittest.RandomBeaconPriv().PublicKey()
+	suite.nextGroupKey = unittest.RandomBeaconPriv().PublicKey()
 
 	suite.dkgState = new(storage.DKGState)
 	suite.dkgState.On("RetrieveMyBeaconPrivateKey", suite.NextEpochCounter()).Return(
@@ -309,6 +316,16 @@ func (suite *ReactorEngineSuite_CommittedPhase) SetupTest() {
 			return nil
 		},
 	)
+	suite.dkgState.On("RetrieveMyBeaconPrivateKey", suite.epochCounter).Return(
+		func(_ uint64) crypto.PrivateKey { return suite.myCurrentBeaconKey },
+		func(_ uint64) error { return nil },
+	)
+	suite.dkgState.On("InsertMyBeaconPrivateKey", suite.NextEpochCounter(), mock.Anything).
+		Run(func(args mock.Arguments) {
+			assert.Nil(suite.T(), suite.myLocalBeaconKey) // must be unset
+			suite.myLocalBeaconKey = args[1].(crypto.PrivateKey)
+		}).
+		Return(nil)
 	suite.dkgState.On("SetDKGEndState", suite.NextEpochCounter(), mock.Anything).
 		Run(func(args mock.Arguments) {
 			assert.Equal(suite.T(), flow.DKGEndStateUnknown, suite.dkgEndState) // must be unset
@@ -326,10 +343,15 @@ func (suite *ReactorEngineSuite_CommittedPhase) SetupTest() {
 		},
 	)
 
+	currentDKG := new(protocol.DKG)
+	currentDKG.On("GroupKey").Return(func() crypto.PublicKey { return suite.currentGroupKey })
+
 	currentEpoch := new(protocol.Epoch)
 	currentEpoch.On("Counter").Return(suite.epochCounter, nil)
+	currentEpoch.On("DKG").Return(currentDKG, nil)
 
 	nextDKG := new(protocol.DKG)
+	nextDKG.On("GroupKey").Return(func() crypto.PublicKey { return suite.nextGroupKey })
 	nextDKG.On("KeyShare", id).Return(
 		func(_ flow.Identifier) crypto.PublicKey { return suite.myGlobalBeaconPubKey },
 		func(_ flow.Identifier) error { return nil },
@@ -414,6 +436,24 @@ func (suite *ReactorEngineSuite_CommittedPhase) TestMissingKey() {
 	suite.Assert().Equal(flow.DKGEndStateNoKey, suite.dkgEndState)
 }
 
+// TestRecoveryEpochReusesDKG tests the path where the next epoch is a recovery epoch,
+// which re-uses the DKG of the current epoch, hence we have not run a DKG locally.
+// We should:
+// * store our current beacon key for the next epoch
+// * set the DKG end state to Success
+func (suite *ReactorEngineSuite_CommittedPhase) TestRecoveryEpochReusesDKG() {
+
+	// no locally computed key, the next epoch re-uses the current DKG
+	suite.myLocalBeaconKey = nil
+	suite.myGlobalBeaconPubKey = suite.myCurrentBeaconKey.PublicKey()
+	suite.nextGroupKey = suite.currentGroupKey
+
+	suite.engine.EpochCommittedPhaseStarted(suite.epochCounter, suite.firstBlock)
+	suite.Require().Equal(0, suite.warnsLogged)
+	suite.Assert().Equal(suite.myCurrentBeaconKey, suite.myLocalBeaconKey)
+	suite.Assert().Equal(flow.DKGEndStateSuccess, suite.dkgEndState)
+}
+
 // TestLocalDKGFailure tests the path where we are checking the global DKG
 // results and observe that we have already set the DKG end state as a failure.
 // We should:
@@ -0,0 +1,352 @@
+package faults
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/rs/zerolog"
+
+	"github.com/onflow/flow-go/crypto/hash"
+	"github.com/onflow/flow-go/engine"
+	"github.com/onflow/flow-go/engine/common/fifoqueue"
+	"github.com/onflow/flow-go/engine/verification/utils"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/module"
+	"github.com/onflow/flow-go/module/component"
+	"github.com/onflow/flow-go/module/irrecoverable"
+	"github.com/onflow/flow-go/module/metrics"
+	"github.com/onflow/flow-go/network"
+	"github.com/onflow/flow-go/network/channels"
+	statepkg "github.com/onflow/flow-go/state"
+	"github.com/onflow/flow-go/state/protocol"
+	"github.com/onflow/flow-go/state/protocol/events"
+	"github.com/onflow/flow-go/storage"
+	"github.com/onflow/flow-go/utils/logging"
+)
+
+const (
+	// defaultReportQueueCapacity maximum capacity of pending chunk fault reports queue, everything above will be dropped
+	defaultReportQueueCapacity = 1000
+	// maxReportsPerReporterAndEpoch is the maximum number of chunk fault reports stored for a reporter on the blocks of
+	// an epoch, across all execution results. As each report embeds a chunk data pack, this bounds the storage a
+	// verification node can consume per epoch.
+	maxReportsPerReporterAndEpoch = 1000
+	// maxReportsPerReporterAndResult is the maximum number of chunk fault reports stored for a reporter and an
+	// execution result, which bounds the reports stored for an execution result by the number of verification nodes,
+	// while a single reporter can't take up the storage of an execution result.
+	maxReportsPerReporterAndResult = 10
+)
+
+// Engine receives the chunk fault reports of verification nodes, and stores the valid ones, so that the faulty
+// execution results can be investigated during the sealing phase.
+//
+// A chunk fault report is valid if it is sent by its reporter, and the reporter is a verification node with
+// positive weight at the executed block, which signed the report, and if its execution result is a result of the
+// executed block. The storage quota of the reporters is a sliding window over the epochs: the reports are counted
+// per epoch of their executed block, and the valid reports on the blocks of the epochs before the previous epoch are
+// dropped, as well as the valid reports exceeding the storage quota of their reporter in the epoch. When the window
+// advances at an epoch transition, and on startup, the stored reports on the blocks of the epochs before the previous
+// epoch are removed.
+type Engine struct {
+	component.Component
+	events.Noop    // satisfy protocol events consumer interface
+	log            zerolog.Logger
+	state          protocol.State
+	results        storage.ExecutionResults
+	reports        storage.ChunkFaultReports
+	hasher         hash.Hasher
+	pendingReports engine.MessageStore
+	messageHandler *engine.MessageHandler
+	pruneNotifier  engine.Notifier
+}
+
+// New creates a new chunk fault report engine.
+func New(
+	log zerolog.Logger,
+	engineMetrics module.EngineMetrics,
+	net network.Network,
+	state protocol.State,
+	results storage.ExecutionResults,
+	reports storage.ChunkFaultReports,
+) (*Engine, error) {
+
+	logger := log.With().Str("engine", "chunk_faults").Logger()
+
+	reportsQueue, err := fifoqueue.NewFifoQueue(defaultReportQueueCapacity)
+	if err != nil {
+		return nil, fmt.Errorf("could not create chunk fault reports queue: %w", err)
+	}
+	pendingReports := &engine.FifoMessageStore{
+		FifoQueue: reportsQueue,
+	}
+
+	handler := engine.NewMessageHandler(
+		logger,
+		engine.NewNotifier(),
+		engine.Pattern{
+			Match: func(msg *engine.Message) bool {
+				_, ok := msg.Payload.(*flow.ChunkFaultReport)
+				if ok {
+					engineMetrics.MessageReceived(metrics.EngineChunkFaults, metrics.MessageChunkFaultReport)
+				}
+				return ok
+			},
+			Store: pendingReports,
+		},
+	)
+
+	e := &Engine{
+		log:            logger,
+		state:          state,
+		results:        results,
+		reports:        reports,
+		hasher:         utils.NewChunkFaultReportHasher(),
+		pendingReports: pendingReports,
+		messageHandler: handler,
+		pruneNotifier:  engine.NewNotifier(),
+	}
+
+	e.Component = component.NewComponentManagerBuilder().
+		AddWorker(func(ctx irrecoverable.SignalerContext, ready component.ReadyFunc) {
+			ready()
+			// remove the reports of the epochs which passed while the node was down
+			err := e.prune()
+			if err != nil {
+				ctx.Throw(err)
+			}
+			err = e.loop(ctx)
+			if err != nil {
+				ctx.Throw(err)
+			}
+		}).
+		Build()
+
+	_, err = net.Register(channels.ReceiveChunkFaultReports, e)
+	if err != nil {
+		return nil, fmt.Errorf("could not register engine: %w", err)
+	}
+	return e, nil
+}
+
+// Process processes the given event from the node with the given origin ID in
+// a blocking manner. It returns error only in unexpected scenario.
+func (e *Engine) Process(channel channels.Channel, originID flow.Identifier, event interface{}) error {
+	err := e.messageHandler.Process(originID, event)
+	if err != nil {
+		if engine.IsIncompatibleInputTypeError(err) {
+			e.log.Warn().Msgf("%v delivered unsupported message %T through %v", originID, event, channel)
+			return nil
+		}
+		return fmt.Errorf("unexpected error while processing engine message: %w", err)
+	}
+	return nil
+}
+
+// EpochTransition handles the epoch transition protocol event, by notifying the worker to remove the reports which
+// moved out of the window.
+func (e *Engine) EpochTransition(uint64, *flow.Header) {
+	e.pruneNotifier.Notify()
+}
+
+// OnChunkFaultReport validates and stores the chunk fault report received from the given origin.
+// Expected errors during normal operation:
+//   - engine.InvalidInputError if the report is not sent by a verification node with positive weight
+//     at the executed block, is not signed by its reporter, or its execution result is not a result of
+//     the executed block.
+//   - engine.UnverifiableInputError if the executed block or the execution result is unknown.
+func (e *Engine) OnChunkFaultReport(originID flow.Identifier, report *flow.ChunkFaultReport) error {
+	err := e.validate(originID, report)
+	if err != nil {
+		return err
+	}
+
+	epoch, err := e.state.AtBlockID(report.Body.BlockID).Epochs().Current().Counter()
+	if err != nil {
+		return fmt.Errorf("could not get epoch of block %x: %w", report.Body.BlockID, err)
+	}
+	current, err := e.state.Final().Epochs().Current().Counter()
+	if err != nil {
+		return fmt.Errorf("could not get current epoch: %w", err)
+	}
+	if epoch+1 < current {
+		e.log.Warn().
+			Hex("reporter_id", logging.ID(report.Body.ReporterID)).
+			Hex("block_id", logging.ID(report.Body.BlockID)).
+			Uint64("epoch", epoch).
+			Msg("dropping chunk fault report on a block before the previous epoch")
+		return nil
+	}
+
+	exceeded, err := e.exceedsQuota(report, epoch)
+	if err != nil {
+		return fmt.Errorf("could not check storage quota of reporter: %w", err)
+	}
+	if exceeded {
+		e.log.Warn().
+			Hex("reporter_id", logging.ID(report.Body.ReporterID)).
+			Hex("result_id", logging.ID(report.Body.ExecutionResultID)).
+			Uint64("chunk_index", report.Body.ChunkIndex).
+			Msg("dropping chunk fault report exceeding the storage quota of its reporter")
+		return nil
+	}
+
+	err = e.reports.Store(report, epoch)
+	if err != nil {
+		return fmt.Errorf("could not store chunk fault report: %w", err)
+	}
+
+	e.log.Warn().
+		Hex("reporter_id", logging.ID(report.Body.ReporterID)).
+		Hex("block_id", logging.ID(report.Body.BlockID)).
+		Hex("result_id", logging.ID(report.Body.ExecutionResultID)).
+		Uint64("chunk_index", report.Body.ChunkIndex).
+		Str("fault_type", string(report.Body.FaultType)).
+		Str("fault_details", report.Body.FaultDetails).
+		Msg("chunk fault reported by verification node")
+	return nil
+}
+
+// exceedsQuota checks whether storing the report exceeds the number of reports stored for its reporter in the given
+// epoch of its executed block, overall or for its execution result. As the reports are processed by a single worker,
+// the quota can't be exceeded by concurrent reports.
+// No errors are expected during normal operation.
+func (e *Engine) exceedsQuota(report *flow.ChunkFaultReport, epoch uint64) (bool, error) {
+	reporterID := report.Body.ReporterID
+	count, err := e.reports.CountByReporterIDAndEpoch(reporterID, epoch)
+	if err != nil {
+		return false, fmt.Errorf("could not count reports of reporter %x in epoch %d: %w", reporterID, epoch, err)
+	}
+	if count >= maxReportsPerReporterAndEpoch {
+		return true, nil
+	}
+	resultID := report.Body.ExecutionResultID
+	count, err = e.reports.CountByReporterIDAndResultID(reporterID, epoch, resultID)
+	if err != nil {
+		return false, fmt.Errorf("could not count reports of reporter %x for result %x: %w", reporterID, resultID, err)
+	}
+	return count >= maxReportsPerReporterAndResult, nil
+}
+
+// validate checks that the report is sent by its reporter, which is a verification node with positive weight at the
+// executed block, that the reporter signed the report, and that the execution result of the report is a result of
+// the executed block.
+func (e *Engine) validate(originID flow.Identifier, report *flow.ChunkFaultReport) error {
+	if report.Body.ReporterID != originID {
+		return engine.NewInvalidInputErrorf("chunk fault report of %x sent by other node %x", report.Body.ReporterID, originID)
+	}
+
+	reporter, err := e.state.AtBlockID(report.Body.BlockID).Identity(originID)
+	if err != nil {
+		if protocol.IsIdentityNotFound(err) {
+			return engine.NewInvalidInputErrorf("unknown reporter %x at block %x", originID, report.Body.BlockID)
+		}
+		if errors.Is(err, storage.ErrNotFound) || errors.Is(err, statepkg.ErrUnknownSnapshotReference) {
+			return engine.NewUnverifiableInputError("unknown block %x of chunk fault report", report.Body.BlockID)
+		}
+		return fmt.Errorf("could not get identity of reporter %x at block %x: %w", originID, report.Body.BlockID, err)
+	}
+	if reporter.Role != flow.RoleVerification {
+		return engine.NewInvalidInputErrorf("reporter %x has invalid role %s", originID, reporter.Role)
+	}
+	if reporter.Weight == 0 || reporter.Ejected {
+		return engine.NewInvalidInputErrorf("reporter %x has no weight at block %x", originID, report.Body.BlockID)
+	}
+
+	bodyID := report.Body.ID()
+	valid, err := reporter.StakingPubKey.Verify(report.ReporterSignature, bodyID[:], e.hasher)
+	if err != nil {
+		return fmt.Errorf("could not verify signature of chunk fault report: %w", err)
+	}
+	if !valid {
+		return engine.NewInvalidInputErrorf("invalid signature of chunk fault report from %x", originID)
+	}
+
+	result, err := e.results.ByID(report.Body.ExecutionResultID)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return engine.NewUnverifiableInputError("unknown execution result %x of chunk fault report", report.Body.ExecutionResultID)
+		}
+		return fmt.Errorf("could not get execution result %x: %w", report.Body.ExecutionResultID, err)
+	}
+	if result.BlockID != report.Body.BlockID {
+		return engine.NewInvalidInputErrorf("execution result %x of chunk fault report is a result of block %x, not of block %x",
+			report.Body.ExecutionResultID, result.BlockID, report.Body.BlockID)
+	}
+	return nil
+}
+
+// processAvailableMessages processes the queued chunk fault reports.
+func (e *Engine) processAvailableMessages(ctx context.Context) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		default: // fall through to business logic
+		}
+
+		msg, ok := e.pendingReports.Get()
+		if !ok {
+			// when there is no more messages in the queue, back to the loop to wait
+			// for the next incoming message to arrive.
+			return nil
+		}
+
+		originID := msg.OriginID
+		err := e.OnChunkFaultReport(originID, msg.Payload.(*flow.ChunkFaultReport))
+		if err != nil {
+			if engine.IsInvalidInputError(err) {
+				e.log.Error().Str("origin", originID.String()).Err(err).Msg("received invalid chunk fault report")
+				continue
+			}
+			if engine.IsUnverifiableInputError(err) {
+				e.log.Warn().Str("origin", originID.String()).Err(err).Msg("received unverifiable chunk fault report")
+				continue
+			}
+			return fmt.Errorf("processing chunk fault report unexpected err: %w", err)
+		}
+	}
+}
+
+// prune removes the stored reports on the blocks of the epochs before the previous epoch of the finalized state.
+// As the reports are stored by the same worker, a report on a block of those epochs can't be stored concurrently.
+// No errors are expected during normal operation.
+func (e *Engine) prune() error {
+	current, err := e.state.Final().Epochs().Current().Counter()
+	if err != nil {
+		return fmt.Errorf("could not get current epoch: %w", err)
+	}
+	if current < 2 {
+		return nil
+	}
+	removed, err := e.reports.RemoveBeforeEpoch(current - 1)
+	if err != nil {
+		return fmt.Errorf("could not remove chunk fault reports before epoch %d: %w", current-1, err)
+	}
+	if removed > 0 {
+		e.log.Info().
+			Uint64("epoch", current-1).
+			Uint("removed", removed).
+			Msg("removed chunk fault reports on blocks before the previous epoch")
+	}
+	return nil
+}
+
+func (e *Engine) loop(ctx context.Context) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-e.pruneNotifier.Channel():
+			err := e.prune()
+			if err != nil {
+				return fmt.Errorf("internal error pruning chunk fault reports: %w", err)
+			}
+		case <-e.messageHandler.GetNotifier():
+			err := e.processAvailableMessages(ctx)
+			if err != nil {
+				return fmt.Errorf("internal error processing queued message: %w", err)
+			}
+		}
+	}
+}
@@ -0,0 +1,256 @@
+package faults
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/onflow/flow-go/crypto"
+	"github.com/onflow/flow-go/engine"
+	"github.com/onflow/flow-go/engine/verification/utils"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/module/irrecoverable"
+	"github.com/onflow/flow-go/module/metrics"
+	"github.com/onflow/flow-go/network/channels"
+	"github.com/onflow/flow-go/network/mocknetwork"
+	"github.com/onflow/flow-go/state/protocol"
+	mockprotocol "github.com/onflow/flow-go/state/protocol/mock"
+	"github.com/onflow/flow-go/storage"
+	mockstorage "github.com/onflow/flow-go/storage/mock"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+func TestChunkFaultsEngine(t *testing.T) {
+	suite.Run(t, new(EngineSuite))
+}
+
+type EngineSuite struct {
+	suite.Suite
+
+	reporter   *flow.Identity
+	reporterSK crypto.PrivateKey
+	blockID    flow.Identifier
+	epoch      uint64 // epoch of the executed block
+	current    uint64 // current epoch of the finalized state
+	snapshot   *mockprotocol.Snapshot
+	results    *mockstorage.ExecutionResults
+	reports    *mockstorage.ChunkFaultReports
+
+	engine *Engine
+}
+
+func (s *EngineSuite) SetupTest() {
+	s.reporterSK = unittest.StakingPrivKeyFixture()
+	s.reporter = unittest.IdentityFixture(unittest.WithRole(flow.RoleVerification))
+	s.reporter.StakingPubKey = s.reporterSK.PublicKey()
+	s.blockID = unittest.IdentifierFixture()
+	s.epoch = 5
+	s.current = s.epoch + 1
+
+	s.snapshot = mockprotocol.NewSnapshot(s.T())
+	s.snapshot.On("Epochs").Return(s.epochQuery(&s.epoch)).Maybe()
+	final := mockprotocol.NewSnapshot(s.T())
+	final.On("Epochs").Return(s.epochQuery(&s.current)).Maybe()
+	state := mockprotocol.NewState(s.T())
+	state.On("AtBlockID", s.blockID).Return(s.snapshot).Maybe()
+	state.On("Final").Return(final).Maybe()
+	s.results = mockstorage.NewExecutionResults(s.T())
+	s.reports = mockstorage.NewChunkFaultReports(s.T())
+
+	net := mocknetwork.NewNetwork(s.T())
+	net.On("Register", channels.ReceiveChunkFaultReports, mock.Anything).Return(mocknetwork.NewConduit(s.T()), nil).Once()
+
+	var err error
+	s.engine, err = New(unittest.Logger(), metrics.NewNoopCollector(), net, state, s.results, s.reports)
+	require.NoError(s.T(), err)
+}
+
+// epochQuery returns an epoch query whose current epoch has the given counter.
+func (s *EngineSuite) epochQuery(counter *uint64) *mockprotocol.EpochQuery {
+	epoch := mockprotocol.NewEpoch(s.T())
+	epoch.On("Counter").Return(func() uint64 { return *counter }, nil).Maybe()
+	query := mockprotocol.NewEpochQuery(s.T())
+	query.On("Current").Return(epoch).Maybe()
+	return query
+}
+
+// reportFixture returns a chunk fault report of the reporter on a result of the block, signed with the given key.
+func (s *EngineSuite) reportFixture(sk crypto.PrivateKey) *flow.ChunkFaultReport {
+	result := unittest.ExecutionResultFixture(unittest.WithExecutionResultBlockID(s.blockID))
+	s.results.On("ByID", result.ID()).Return(result, nil).Maybe()
+	body := flow.ChunkFaultReportBody{
+		BlockID:           s.blockID,
+		ExecutionResultID: result.ID(),
+		ChunkIndex:        1,
+		ReporterID:        s.reporter.NodeID,
+		FaultType:         flow.ChunkFaultNonMatchingFinalState,
+		FaultDetails:      "final state commitment doesn't match",
+		ChunkDataPack:     *unittest.ChunkDataPackFixture(unittest.IdentifierFixture()),
+		ComputedEndState:  unittest.StateCommitmentFixture(),
+	}
+	return &flow.ChunkFaultReport{Body: body, ReporterSignature: s.sign(sk, body)}
+}
+
+// sign signs the chunk fault report body with the given key.
+func (s *EngineSuite) sign(sk crypto.PrivateKey, body flow.ChunkFaultReportBody) crypto.Signature {
+	bodyID := body.ID()
+	sig, err := sk.Sign(bodyID[:], utils.NewChunkFaultReportHasher())
+	require.NoError(s.T(), err)
+	return sig
+}
+
+// TestValidReport evaluates that a valid chunk fault report is stored.
+func (s *EngineSuite) TestValidReport() {
+	report := s.reportFixture(s.reporterSK)
+	s.snapshot.On("Identity", s.reporter.NodeID).Return(s.reporter, nil).Once()
+	s.reports.On("CountByReporterIDAndEpoch", s.reporter.NodeID, s.epoch).Return(uint(maxReportsPerReporterAndEpoch-1), nil).Once()
+	s.reports.On("CountByReporterIDAndResultID", s.reporter.NodeID, s.epoch, report.Body.ExecutionResultID).Return(uint(maxReportsPerReporterAndResult-1), nil).Once()
+	s.reports.On("Store", report, s.epoch).Return(nil).Once()
+
+	err := s.engine.OnChunkFaultReport(s.reporter.NodeID, report)
+	require.NoError(s.T(), err)
+}
+
+// TestReportQuota evaluates that the valid chunk fault reports exceeding the storage quota of their reporter in the
+// epoch of their executed block, overall or for their execution result, are dropped, as well as the valid reports on
+// the blocks of the epochs before the previous epoch.
+func (s *EngineSuite) TestReportQuota() {
+	s.Run("reporter quota", func() {
+		report := s.reportFixture(s.reporterSK)
+		s.snapshot.On("Identity", s.reporter.NodeID).Return(s.reporter, nil).Once()
+		s.reports.On("CountByReporterIDAndEpoch", s.reporter.NodeID, s.epoch).Return(uint(maxReportsPerReporterAndEpoch), nil).Once()
+
+		err := s.engine.OnChunkFaultReport(s.reporter.NodeID, report)
+		require.NoError(s.T(), err)
+	})
+
+	s.Run("reporter quota for result", func() {
+		report := s.reportFixture(s.reporterSK)
+		s.snapshot.On("Identity", s.reporter.NodeID).Return(s.reporter, nil).Once()
+		s.reports.On("CountByReporterIDAndEpoch", s.reporter.NodeID, s.epoch).Return(uint(0), nil).Once()
+		s.reports.On("CountByReporterIDAndResultID", s.reporter.NodeID, s.epoch, report.Body.ExecutionResultID).Return(uint(maxReportsPerReporterAndResult), nil).Once()
+
+		err := s.engine.OnChunkFaultReport(s.reporter.NodeID, report)
+		require.NoError(s.T(), err)
+	})
+
+	s.Run("block before previous epoch", func() {
+		s.current = s.epoch + 2
+		defer func() { s.current = s.epoch + 1 }()
+		report := s.reportFixture(s.reporterSK)
+		s.snapshot.On("Identity", s.reporter.NodeID).Return(s.reporter, nil).Once()
+
+		err := s.engine.OnChunkFaultReport(s.reporter.NodeID, report)
+		require.NoError(s.T(), err)
+	})
+
+	s.reports.AssertNotCalled(s.T(), "Store", mock.Anything)
+}
+
+// TestInvalidReports evaluates that the chunk fault reports which are not sent by their reporter, not sent by a
+// verification node with positive weight, or not signed by their reporter are rejected, and not stored.
+func (s *EngineSuite) TestInvalidReports() {
+	s.Run("sent by other node", func() {
+		err := s.engine.OnChunkFaultReport(unittest.IdentifierFixture(), s.reportFixture(s.reporterSK))
+		assert.True(s.T(), engine.IsInvalidInputError(err))
+	})
+
+	s.Run("unknown reporter", func() {
+		s.snapshot.On("Identity", s.reporter.NodeID).Return(nil, protocol.IdentityNotFoundError{NodeID: s.reporter.NodeID}).Once()
+		err := s.engine.OnChunkFaultReport(s.reporter.NodeID, s.reportFixture(s.reporterSK))
+		assert.True(s.T(), engine.IsInvalidInputError(err))
+	})
+
+	s.Run("invalid role", func() {
+		execution := *s.reporter
+		execution.Role = flow.RoleExecution
+		s.snapshot.On("Identity", s.reporter.NodeID).Return(&execution, nil).Once()
+		err := s.engine.OnChunkFaultReport(s.reporter.NodeID, s.reportFixture(s.reporterSK))
+		assert.True(s.T(), engine.IsInvalidInputError(err))
+	})
+
+	s.Run("zero weight", func() {
+		unweighted := *s.reporter
+		unweighted.Weight = 0
+		s.snapshot.On("Identity", s.reporter.NodeID).Return(&unweighted, nil).Once()
+		err := s.engine.OnChunkFaultReport(s.reporter.NodeID, s.reportFixture(s.reporterSK))
+		assert.True(s.T(), engine.IsInvalidInputError(err))
+	})
+
+	s.Run("invalid signature", func() {
+		s.snapshot.On("Identity", s.reporter.NodeID).Return(s.reporter, nil).Once()
+		err := s.engine.OnChunkFaultReport(s.reporter.NodeID, s.reportFixture(unittest.StakingPrivKeyFixture()))
+		assert.True(s.T(), engine.IsInvalidInputError(err))
+	})
+
+	s.Run("unknown block", func() {
+		s.snapshot.On("Identity", s.reporter.NodeID).Return(nil, storage.ErrNotFound).Once()
+		err := s.engine.OnChunkFaultReport(s.reporter.NodeID, s.reportFixture(s.reporterSK))
+		assert.True(s.T(), engine.IsUnverifiableInputError(err))
+	})
+
+	s.Run("result of other block", func() {
+		result := unittest.ExecutionResultFixture()
+		s.results.On("ByID", result.ID()).Return(result, nil).Once()
+		s.snapshot.On("Identity", s.reporter.NodeID).Return(s.reporter, nil).Once()
+		report := s.reportFixture(s.reporterSK)
+		report.Body.ExecutionResultID = result.ID()
+		report.ReporterSignature = s.sign(s.reporterSK, report.Body)
+		err := s.engine.OnChunkFaultReport(s.reporter.NodeID, report)
+		assert.True(s.T(), engine.IsInvalidInputError(err))
+	})
+
+	s.Run("unknown result", func() {
+		resultID := unittest.IdentifierFixture()
+		s.results.On("ByID", resultID).Return(nil, storage.ErrNotFound).Once()
+		s.snapshot.On("Identity", s.reporter.NodeID).Return(s.reporter, nil).Once()
+		report := s.reportFixture(s.reporterSK)
+		report.Body.ExecutionResultID = resultID
+		report.ReporterSignature = s.sign(s.reporterSK, report.Body)
+		err := s.engine.OnChunkFaultReport(s.reporter.NodeID, report)
+		assert.True(s.T(), engine.IsUnverifiableInputError(err))
+	})
+
+	s.reports.AssertNotCalled(s.T(), "Store", mock.Anything)
+}
+
+// TestPrune evaluates that the stored reports on the blocks of the epochs before the previous epoch are removed on
+// startup and at each epoch transition.
+func (s *EngineSuite) TestPrune() {
+	removed := make(chan uint64, 2)
+	s.reports.On("RemoveBeforeEpoch", mock.Anything).Run(func(args mock.Arguments) {
+		removed <- args.Get(0).(uint64)
+	}).Return(uint(0), nil).Twice()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	s.engine.Start(irrecoverable.NewMockSignalerContext(s.T(), ctx))
+	unittest.RequireCloseBefore(s.T(), s.engine.Ready(), time.Second, "engine not ready")
+
+	// on startup, the window starts at the previous epoch
+	unittest.RequireReturnsBefore(s.T(), func() {
+		assert.Equal(s.T(), s.current-1, <-removed)
+	}, time.Second, "reports not pruned on startup")
+
+	// at the epoch transition, the window advances
+	s.current++
+	s.engine.EpochTransition(s.current, unittest.BlockHeaderFixture())
+	unittest.RequireReturnsBefore(s.T(), func() {
+		assert.Equal(s.T(), s.current-1, <-removed)
+	}, time.Second, "reports not pruned at epoch transition")
+
+	cancel()
+	unittest.RequireCloseBefore(s.T(), s.engine.Done(), time.Second, "engine not done")
+}
+
+// TestPruneFirstEpochs evaluates that no reports are removed while there is no epoch before the previous epoch.
+func (s *EngineSuite) TestPruneFirstEpochs() {
+	s.current = 1
+	require.NoError(s.T(), s.engine.prune())
+	s.reports.AssertNotCalled(s.T(), "RemoveBeforeEpoch", mock.Anything)
+}
@@ -1063,6 +1063,21 @@ func (e *Engine) GetRegisterAtBlockID(ctx context.Context, owner, key []byte, bl
 	return data, nil
 }
 
+func (e *Engine) GetRegisterProofs(ctx context.Context, commitment flow.StateCommitment, registerIDs []flow.RegisterID) ([]byte, error) {
+	// proving is not interruptible, so don't start if the caller already gave up
+	err := ctx.Err()
+	if err != nil {
+		return nil, fmt.Errorf("request aborted before proving registers: %w", err)
+	}
+
+	proof, err := e.execState.GetRegisterProofs(commitment, registerIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get proofs for %d registers at state commitment (%x): %w", len(registerIDs), commitment[:], err)
+	}
+
+	return proof, nil
+}
+
 func (e *Engine) GetAccount(ctx context.Context, addr flow.Address, blockID flow.Identifier) (*flow.Account, error) {
 	stateCommit, err := e.execState.StateCommitmentByBlockID(ctx, blockID)
 	if err != nil {
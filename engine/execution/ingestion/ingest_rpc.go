@@ -17,4 +17,8 @@ type IngestRPC interface {
 
 	// GetRegisterAtBlockID returns the value of a register at the given Block id (if available)
 	GetRegisterAtBlockID(ctx context.Context, owner, key []byte, blockID flow.Identifier) ([]byte, error)
+
+	// GetRegisterProofs returns an encoded batch proof of the given registers at the given state commitment,
+	// which clients can verify using the registerproof package
+	GetRegisterProofs(ctx context.Context, commitment flow.StateCommitment, registerIDs []flow.RegisterID) ([]byte, error)
 }
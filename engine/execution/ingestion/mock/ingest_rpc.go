@@ -93,6 +93,32 @@ func (_m *IngestRPC) GetRegisterAtBlockID(ctx context.Context, owner []byte, key
 	return r0, r1
 }
 
+// GetRegisterProofs provides a mock function with given fields: ctx, commitment, registerIDs
+func (_m *IngestRPC) GetRegisterProofs(ctx context.Context, commitment flow.StateCommitment, registerIDs []flow.RegisterID) ([]byte, error) {
+	ret := _m.Called(ctx, commitment, registerIDs)
+
+	var r0 []byte
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, flow.StateCommitment, []flow.RegisterID) ([]byte, error)); ok {
+		return rf(ctx, commitment, registerIDs)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, flow.StateCommitment, []flow.RegisterID) []byte); ok {
+		r0 = rf(ctx, commitment, registerIDs)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]byte)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, flow.StateCommitment, []flow.RegisterID) error); ok {
+		r1 = rf(ctx, commitment, registerIDs)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 type mockConstructorTestingTNewIngestRPC interface {
 	mock.TestingT
 	Cleanup(func())
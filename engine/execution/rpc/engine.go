@@ -21,12 +21,19 @@ import (
 	"github.com/onflow/flow-go/engine/common/rpc"
 	"github.com/onflow/flow-go/engine/common/rpc/convert"
 	"github.com/onflow/flow-go/engine/execution/ingestion"
+	"github.com/onflow/flow-go/engine/execution/rpc/proofs"
+	"github.com/onflow/flow-go/engine/execution/state"
 	fvmerrors "github.com/onflow/flow-go/fvm/errors"
 	"github.com/onflow/flow-go/model/flow"
 	"github.com/onflow/flow-go/state/protocol"
 	"github.com/onflow/flow-go/storage"
 )
 
+// maxRegisterProofsPerRequest is the maximum number of registers a client can request proofs
+// for in a single GetRegisterProofs call. Proof size and proving time grow linearly with
+// the number of registers, so larger batches have to be split by the client.
+const maxRegisterProofsPerRequest = 1000
+
 // Config defines the configurable options for the gRPC server.
 type Config struct {
 	ListenAddr        string
@@ -109,6 +116,7 @@ func New(
 	}
 
 	execution.RegisterExecutionAPIServer(eng.server, eng.handler)
+	proofs.RegisterRegisterProofAPIServer(eng.server, eng.handler)
 
 	return eng
 }
@@ -157,9 +165,12 @@ type handler struct {
 	transactionResults   storage.TransactionResults
 	log                  zerolog.Logger
 	commits              storage.Commits
+
+	proofs.UnimplementedRegisterProofAPIServer
 }
 
 var _ execution.ExecutionAPIServer = &handler{}
+var _ proofs.RegisterProofAPIServer = &handler{}
 
 // Ping responds to requests when the server is up.
 func (h *handler) Ping(_ context.Context, _ *execution.PingRequest) (*execution.PingResponse, error) {
@@ -214,6 +225,47 @@ func (h *handler) GetRegisterAtBlockID(
 	return res, nil
 }
 
+// GetRegisterProofs returns an encoded batch proof of the requested registers at the given
+// state commitment, which clients can verify using the registerproof package.
+func (h *handler) GetRegisterProofs(
+	ctx context.Context,
+	req *proofs.GetRegisterProofsRequest,
+) (*proofs.GetRegisterProofsResponse, error) {
+
+	commitment, err := convert.MessageToStateCommitment(req.GetStateCommitment())
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid state commitment: %v", err)
+	}
+
+	reqRegisterIDs := req.GetRegisterIds()
+	if len(reqRegisterIDs) == 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "no register IDs provided")
+	}
+	if len(reqRegisterIDs) > maxRegisterProofsPerRequest {
+		return nil, status.Errorf(codes.InvalidArgument, "too many register IDs requested: %d > %d", len(reqRegisterIDs), maxRegisterProofsPerRequest)
+	}
+
+	registerIDs := make([]flow.RegisterID, 0, len(reqRegisterIDs))
+	for _, id := range reqRegisterIDs {
+		registerIDs = append(registerIDs, flow.NewRegisterID(string(id.GetOwner()), string(id.GetKey())))
+	}
+
+	proof, err := h.engine.GetRegisterProofs(ctx, commitment, registerIDs)
+	if err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, status.FromContextError(ctxErr).Err()
+		}
+		if errors.Is(err, state.ErrStateNotFound) {
+			return nil, status.Errorf(codes.NotFound, "state commitment %x not available: %v", commitment[:], err)
+		}
+		return nil, status.Errorf(codes.Internal, "failed to prove %d registers at state commitment %x: %v", len(registerIDs), commitment[:], err)
+	}
+
+	return &proofs.GetRegisterProofsResponse{
+		Proofs: proof,
+	}, nil
+}
+
 func (h *handler) GetEventsForBlockIDs(_ context.Context,
 	req *execution.GetEventsForBlockIDsRequest) (*execution.GetEventsForBlockIDsResponse, error) {
 
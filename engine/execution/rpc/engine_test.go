@@ -19,6 +19,8 @@ import (
 
 	"github.com/onflow/flow-go/engine/common/rpc/convert"
 	ingestion "github.com/onflow/flow-go/engine/execution/ingestion/mock"
+	"github.com/onflow/flow-go/engine/execution/rpc/proofs"
+	"github.com/onflow/flow-go/engine/execution/state"
 	"github.com/onflow/flow-go/model/flow"
 	realstorage "github.com/onflow/flow-go/storage"
 	storage "github.com/onflow/flow-go/storage/mock"
@@ -343,6 +345,73 @@ func (suite *Suite) TestGetRegisterAtBlockID() {
 	})
 }
 
+// TestGetRegisterProofs tests the GetRegisterProofs API call
+func (suite *Suite) TestGetRegisterProofs() {
+
+	commitment := unittest.StateCommitmentFixture()
+	owner := unittest.RandomAddressFixture()
+	registerID := flow.NewRegisterID(string(owner.Bytes()), "key")
+
+	mockEngine := new(ingestion.IngestRPC)
+
+	// create the handler
+	handler := &handler{
+		engine: mockEngine,
+		chain:  flow.Mainnet,
+	}
+
+	createReq := func(commitment []byte, count int) *proofs.GetRegisterProofsRequest {
+		ids := make([]*proofs.RegisterID, count)
+		for i := range ids {
+			ids[i] = &proofs.RegisterID{Owner: []byte(registerID.Owner), Key: []byte(registerID.Key)}
+		}
+		return &proofs.GetRegisterProofsRequest{
+			StateCommitment: commitment,
+			RegisterIds:     ids,
+		}
+	}
+
+	suite.Run("happy path with valid request", func() {
+		mockEngine.On("GetRegisterProofs", mock.Anything, commitment, []flow.RegisterID{registerID}).Return([]byte{1, 2, 3}, nil).Once()
+
+		resp, err := handler.GetRegisterProofs(context.Background(), createReq(commitment[:], 1))
+		suite.Require().NoError(err)
+		suite.Require().Equal([]byte{1, 2, 3}, resp.GetProofs())
+		mockEngine.AssertExpectations(suite.T())
+	})
+
+	suite.Run("invalid state commitment", func() {
+		_, err := handler.GetRegisterProofs(context.Background(), createReq([]byte{1}, 1))
+		suite.Require().Equal(codes.InvalidArgument, status.Code(err))
+	})
+
+	suite.Run("no register IDs", func() {
+		_, err := handler.GetRegisterProofs(context.Background(), createReq(commitment[:], 0))
+		suite.Require().Equal(codes.InvalidArgument, status.Code(err))
+	})
+
+	suite.Run("too many register IDs", func() {
+		_, err := handler.GetRegisterProofs(context.Background(), createReq(commitment[:], maxRegisterProofsPerRequest+1))
+		suite.Require().Equal(codes.InvalidArgument, status.Code(err))
+	})
+
+	suite.Run("unknown state commitment", func() {
+		mockEngine.On("GetRegisterProofs", mock.Anything, commitment, []flow.RegisterID{registerID}).Return(nil, state.ErrStateNotFound).Once()
+
+		_, err := handler.GetRegisterProofs(context.Background(), createReq(commitment[:], 1))
+		suite.Require().Equal(codes.NotFound, status.Code(err))
+	})
+
+	suite.Run("canceled request", func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		mockEngine.On("GetRegisterProofs", mock.Anything, commitment, []flow.RegisterID{registerID}).Return(nil, ctx.Err()).Once()
+
+		_, err := handler.GetRegisterProofs(ctx, createReq(commitment[:], 1))
+		suite.Require().Equal(codes.Canceled, status.Code(err))
+	})
+}
+
 // TestGetTransactionResult tests the GetTransactionResult and GetTransactionResultByIndex API calls
 func (suite *Suite) TestGetTransactionResult() {
 
@@ -0,0 +1,302 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.28.1
+// 	protoc        v3.17.1
+// source: proofs.proto
+
+package proofs
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type RegisterID struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Owner []byte `protobuf:"bytes,1,opt,name=owner,proto3" json:"owner,omitempty"`
+	Key   []byte `protobuf:"bytes,2,opt,name=key,proto3" json:"key,omitempty"`
+}
+
+func (x *RegisterID) Reset() {
+	*x = RegisterID{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proofs_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RegisterID) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RegisterID) ProtoMessage() {}
+
+func (x *RegisterID) ProtoReflect() protoreflect.Message {
+	mi := &file_proofs_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RegisterID.ProtoReflect.Descriptor instead.
+func (*RegisterID) Descriptor() ([]byte, []int) {
+	return file_proofs_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *RegisterID) GetOwner() []byte {
+	if x != nil {
+		return x.Owner
+	}
+	return nil
+}
+
+func (x *RegisterID) GetKey() []byte {
+	if x != nil {
+		return x.Key
+	}
+	return nil
+}
+
+type GetRegisterProofsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	StateCommitment []byte        `protobuf:"bytes,1,opt,name=state_commitment,json=stateCommitment,proto3" json:"state_commitment,omitempty"`
+	RegisterIds     []*RegisterID `protobuf:"bytes,2,rep,name=register_ids,json=registerIds,proto3" json:"register_ids,omitempty"`
+}
+
+func (x *GetRegisterProofsRequest) Reset() {
+	*x = GetRegisterProofsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proofs_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetRegisterProofsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetRegisterProofsRequest) ProtoMessage() {}
+
+func (x *GetRegisterProofsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proofs_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetRegisterProofsRequest.ProtoReflect.Descriptor instead.
+func (*GetRegisterProofsRequest) Descriptor() ([]byte, []int) {
+	return file_proofs_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *GetRegisterProofsRequest) GetStateCommitment() []byte {
+	if x != nil {
+		return x.StateCommitment
+	}
+	return nil
+}
+
+func (x *GetRegisterProofsRequest) GetRegisterIds() []*RegisterID {
+	if x != nil {
+		return x.RegisterIds
+	}
+	return nil
+}
+
+type GetRegisterProofsResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Proofs []byte `protobuf:"bytes,1,opt,name=proofs,proto3" json:"proofs,omitempty"`
+}
+
+func (x *GetRegisterProofsResponse) Reset() {
+	*x = GetRegisterProofsResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proofs_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetRegisterProofsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetRegisterProofsResponse) ProtoMessage() {}
+
+func (x *GetRegisterProofsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proofs_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetRegisterProofsResponse.ProtoReflect.Descriptor instead.
+func (*GetRegisterProofsResponse) Descriptor() ([]byte, []int) {
+	return file_proofs_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *GetRegisterProofsResponse) GetProofs() []byte {
+	if x != nil {
+		return x.Proofs
+	}
+	return nil
+}
+
+var File_proofs_proto protoreflect.FileDescriptor
+
+var file_proofs_proto_rawDesc = []byte{
+	0x0a, 0x0c, 0x70, 0x72, 0x6f, 0x6f, 0x66, 0x73, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x06,
+	0x70, 0x72, 0x6f, 0x6f, 0x66, 0x73, 0x22, 0x34, 0x0a, 0x0a, 0x52, 0x65, 0x67, 0x69, 0x73, 0x74,
+	0x65, 0x72, 0x49, 0x44, 0x12, 0x14, 0x0a, 0x05, 0x6f, 0x77, 0x6e, 0x65, 0x72, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x0c, 0x52, 0x05, 0x6f, 0x77, 0x6e, 0x65, 0x72, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65,
+	0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x22, 0x7c, 0x0a, 0x18,
+	0x47, 0x65, 0x74, 0x52, 0x65, 0x67, 0x69, 0x73, 0x74, 0x65, 0x72, 0x50, 0x72, 0x6f, 0x6f, 0x66,
+	0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x29, 0x0a, 0x10, 0x73, 0x74, 0x61, 0x74,
+	0x65, 0x5f, 0x63, 0x6f, 0x6d, 0x6d, 0x69, 0x74, 0x6d, 0x65, 0x6e, 0x74, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x0c, 0x52, 0x0f, 0x73, 0x74, 0x61, 0x74, 0x65, 0x43, 0x6f, 0x6d, 0x6d, 0x69, 0x74, 0x6d,
+	0x65, 0x6e, 0x74, 0x12, 0x35, 0x0a, 0x0c, 0x72, 0x65, 0x67, 0x69, 0x73, 0x74, 0x65, 0x72, 0x5f,
+	0x69, 0x64, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x70, 0x72, 0x6f, 0x6f,
+	0x66, 0x73, 0x2e, 0x52, 0x65, 0x67, 0x69, 0x73, 0x74, 0x65, 0x72, 0x49, 0x44, 0x52, 0x0b, 0x72,
+	0x65, 0x67, 0x69, 0x73, 0x74, 0x65, 0x72, 0x49, 0x64, 0x73, 0x22, 0x33, 0x0a, 0x19, 0x47, 0x65,
+	0x74, 0x52, 0x65, 0x67, 0x69, 0x73, 0x74, 0x65, 0x72, 0x50, 0x72, 0x6f, 0x6f, 0x66, 0x73, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x70, 0x72, 0x6f, 0x6f, 0x66,
+	0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x06, 0x70, 0x72, 0x6f, 0x6f, 0x66, 0x73, 0x32,
+	0x6c, 0x0a, 0x10, 0x52, 0x65, 0x67, 0x69, 0x73, 0x74, 0x65, 0x72, 0x50, 0x72, 0x6f, 0x6f, 0x66,
+	0x41, 0x50, 0x49, 0x12, 0x58, 0x0a, 0x11, 0x47, 0x65, 0x74, 0x52, 0x65, 0x67, 0x69, 0x73, 0x74,
+	0x65, 0x72, 0x50, 0x72, 0x6f, 0x6f, 0x66, 0x73, 0x12, 0x20, 0x2e, 0x70, 0x72, 0x6f, 0x6f, 0x66,
+	0x73, 0x2e, 0x47, 0x65, 0x74, 0x52, 0x65, 0x67, 0x69, 0x73, 0x74, 0x65, 0x72, 0x50, 0x72, 0x6f,
+	0x6f, 0x66, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x21, 0x2e, 0x70, 0x72, 0x6f,
+	0x6f, 0x66, 0x73, 0x2e, 0x47, 0x65, 0x74, 0x52, 0x65, 0x67, 0x69, 0x73, 0x74, 0x65, 0x72, 0x50,
+	0x72, 0x6f, 0x6f, 0x66, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x42, 0x37, 0x5a,
+	0x35, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x6f, 0x6e, 0x66, 0x6c,
+	0x6f, 0x77, 0x2f, 0x66, 0x6c, 0x6f, 0x77, 0x2d, 0x67, 0x6f, 0x2f, 0x65, 0x6e, 0x67, 0x69, 0x6e,
+	0x65, 0x2f, 0x65, 0x78, 0x65, 0x63, 0x75, 0x74, 0x69, 0x6f, 0x6e, 0x2f, 0x72, 0x70, 0x63, 0x2f,
+	0x70, 0x72, 0x6f, 0x6f, 0x66, 0x73, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_proofs_proto_rawDescOnce sync.Once
+	file_proofs_proto_rawDescData = file_proofs_proto_rawDesc
+)
+
+func file_proofs_proto_rawDescGZIP() []byte {
+	file_proofs_proto_rawDescOnce.Do(func() {
+		file_proofs_proto_rawDescData = protoimpl.X.CompressGZIP(file_proofs_proto_rawDescData)
+	})
+	return file_proofs_proto_rawDescData
+}
+
+var file_proofs_proto_msgTypes = make([]protoimpl.MessageInfo, 3)
+var file_proofs_proto_goTypes = []interface{}{
+	(*RegisterID)(nil),                // 0: proofs.RegisterID
+	(*GetRegisterProofsRequest)(nil),  // 1: proofs.GetRegisterProofsRequest
+	(*GetRegisterProofsResponse)(nil), // 2: proofs.GetRegisterProofsResponse
+}
+var file_proofs_proto_depIdxs = []int32{
+	0, // 0: proofs.GetRegisterProofsRequest.register_ids:type_name -> proofs.RegisterID
+	1, // 1: proofs.RegisterProofAPI.GetRegisterProofs:input_type -> proofs.GetRegisterProofsRequest
+	2, // 2: proofs.RegisterProofAPI.GetRegisterProofs:output_type -> proofs.GetRegisterProofsResponse
+	2, // [2:3] is the sub-list for method output_type
+	1, // [1:2] is the sub-list for method input_type
+	1, // [1:1] is the sub-list for extension type_name
+	1, // [1:1] is the sub-list for extension extendee
+	0, // [0:1] is the sub-list for field type_name
+}
+
+func init() { file_proofs_proto_init() }
+func file_proofs_proto_init() {
+	if File_proofs_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_proofs_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RegisterID); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proofs_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetRegisterProofsRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proofs_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetRegisterProofsResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_proofs_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   3,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_proofs_proto_goTypes,
+		DependencyIndexes: file_proofs_proto_depIdxs,
+		MessageInfos:      file_proofs_proto_msgTypes,
+	}.Build()
+	File_proofs_proto = out.File
+	file_proofs_proto_rawDesc = nil
+	file_proofs_proto_goTypes = nil
+	file_proofs_proto_depIdxs = nil
+}
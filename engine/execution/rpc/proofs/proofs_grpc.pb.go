@@ -0,0 +1,105 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+
+package proofs
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+// RegisterProofAPIClient is the client API for RegisterProofAPI service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type RegisterProofAPIClient interface {
+	// GetRegisterProofs returns an encoded batch proof of the requested registers at the
+	// given state commitment.
+	GetRegisterProofs(ctx context.Context, in *GetRegisterProofsRequest, opts ...grpc.CallOption) (*GetRegisterProofsResponse, error)
+}
+
+type registerProofAPIClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewRegisterProofAPIClient(cc grpc.ClientConnInterface) RegisterProofAPIClient {
+	return &registerProofAPIClient{cc}
+}
+
+func (c *registerProofAPIClient) GetRegisterProofs(ctx context.Context, in *GetRegisterProofsRequest, opts ...grpc.CallOption) (*GetRegisterProofsResponse, error) {
+	out := new(GetRegisterProofsResponse)
+	err := c.cc.Invoke(ctx, "/proofs.RegisterProofAPI/GetRegisterProofs", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// RegisterProofAPIServer is the server API for RegisterProofAPI service.
+// All implementations must embed UnimplementedRegisterProofAPIServer
+// for forward compatibility
+type RegisterProofAPIServer interface {
+	// GetRegisterProofs returns an encoded batch proof of the requested registers at the
+	// given state commitment.
+	GetRegisterProofs(context.Context, *GetRegisterProofsRequest) (*GetRegisterProofsResponse, error)
+	mustEmbedUnimplementedRegisterProofAPIServer()
+}
+
+// UnimplementedRegisterProofAPIServer must be embedded to have forward compatible implementations.
+type UnimplementedRegisterProofAPIServer struct {
+}
+
+func (UnimplementedRegisterProofAPIServer) GetRegisterProofs(context.Context, *GetRegisterProofsRequest) (*GetRegisterProofsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetRegisterProofs not implemented")
+}
+func (UnimplementedRegisterProofAPIServer) mustEmbedUnimplementedRegisterProofAPIServer() {}
+
+// UnsafeRegisterProofAPIServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to RegisterProofAPIServer will
+// result in compilation errors.
+type UnsafeRegisterProofAPIServer interface {
+	mustEmbedUnimplementedRegisterProofAPIServer()
+}
+
+func RegisterRegisterProofAPIServer(s grpc.ServiceRegistrar, srv RegisterProofAPIServer) {
+	s.RegisterService(&RegisterProofAPI_ServiceDesc, srv)
+}
+
+func _RegisterProofAPI_GetRegisterProofs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetRegisterProofsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RegisterProofAPIServer).GetRegisterProofs(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proofs.RegisterProofAPI/GetRegisterProofs",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RegisterProofAPIServer).GetRegisterProofs(ctx, req.(*GetRegisterProofsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// RegisterProofAPI_ServiceDesc is the grpc.ServiceDesc for RegisterProofAPI service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var RegisterProofAPI_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "proofs.RegisterProofAPI",
+	HandlerType: (*RegisterProofAPIServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetRegisterProofs",
+			Handler:    _RegisterProofAPI_GetRegisterProofs_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "proofs.proto",
+}
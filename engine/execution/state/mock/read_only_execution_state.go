@@ -127,6 +127,32 @@ func (_m *ReadOnlyExecutionState) GetHighestExecutedBlockID(_a0 context.Context)
 	return r0, r1, r2
 }
 
+// GetRegisterProofs provides a mock function with given fields: _a0, _a1
+func (_m *ReadOnlyExecutionState) GetRegisterProofs(_a0 flow.StateCommitment, _a1 []flow.RegisterID) ([]byte, error) {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 []byte
+	var r1 error
+	if rf, ok := ret.Get(0).(func(flow.StateCommitment, []flow.RegisterID) ([]byte, error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(flow.StateCommitment, []flow.RegisterID) []byte); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]byte)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(flow.StateCommitment, []flow.RegisterID) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // HasState provides a mock function with given fields: _a0
 func (_m *ReadOnlyExecutionState) HasState(_a0 flow.StateCommitment) bool {
 	ret := _m.Called(_a0)
@@ -0,0 +1,71 @@
+// Package registerproof allows clients to verify register values served by an execution node
+// against a state commitment they trust (typically the final state of a sealed execution result),
+// without having to trust the node serving them.
+package registerproof
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/onflow/flow-go/engine/execution/state"
+	"github.com/onflow/flow-go/ledger"
+	"github.com/onflow/flow-go/ledger/partial"
+	"github.com/onflow/flow-go/model/flow"
+)
+
+// ErrInvalidProof is returned when a batch proof cannot be decoded, does not hash up to the
+// expected state commitment or does not cover all of the requested registers.
+var ErrInvalidProof = errors.New("invalid register proof")
+
+// Verify checks the encoded batch proof (as returned by ReadOnlyExecutionState.GetRegisterProofs)
+// against the given state commitment and returns the proven values of the given registers, in
+// the same order as registerIDs. Registers which are not set at the given state are proven by
+// non-inclusion and have an empty value.
+// Expected errors during normal operations:
+//   - ErrInvalidProof if the proof is malformed, does not match the state commitment or
+//     does not cover all given registers
+func Verify(
+	commitment flow.StateCommitment,
+	registerIDs []flow.RegisterID,
+	proof []byte,
+) (
+	[]flow.RegisterValue,
+	error,
+) {
+	if len(registerIDs) == 0 {
+		return nil, nil
+	}
+
+	// building the partial ledger recomputes the root hash from the proofs
+	// and fails if it does not match the expected state commitment
+	ptrie, err := partial.NewLedger(proof, ledger.State(commitment), partial.DefaultPathFinderVersion)
+	if err != nil {
+		return nil, fmt.Errorf("%w: could not reconstruct partial trie at %x: %v", ErrInvalidProof, commitment[:], err)
+	}
+
+	keys := make([]ledger.Key, len(registerIDs))
+	for i, id := range registerIDs {
+		keys[i] = state.RegisterIDToKey(id)
+	}
+
+	query, err := ledger.NewQuery(ledger.State(commitment), keys)
+	if err != nil {
+		return nil, fmt.Errorf("cannot create ledger query: %w", err)
+	}
+
+	values, err := ptrie.Get(query)
+	if err != nil {
+		var missingErr *ledger.ErrMissingKeys
+		if errors.As(err, &missingErr) {
+			return nil, fmt.Errorf("%w: proof does not cover %d of the requested registers", ErrInvalidProof, len(missingErr.Keys))
+		}
+		return nil, fmt.Errorf("could not read proven registers: %w", err)
+	}
+
+	registerValues := make([]flow.RegisterValue, len(values))
+	for i, value := range values {
+		registerValues[i] = flow.RegisterValue(value)
+	}
+
+	return registerValues, nil
+}
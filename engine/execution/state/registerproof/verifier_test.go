@@ -0,0 +1,91 @@
+package registerproof_test
+
+import (
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/engine/execution/state"
+	"github.com/onflow/flow-go/engine/execution/state/registerproof"
+	"github.com/onflow/flow-go/ledger"
+	"github.com/onflow/flow-go/ledger/complete"
+	"github.com/onflow/flow-go/ledger/complete/wal/fixtures"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/module/metrics"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+func TestVerify(t *testing.T) {
+	ls, err := complete.NewLedger(&fixtures.NoopWAL{}, 100, &metrics.NoopCollector{}, zerolog.Nop(), complete.DefaultPathFinderVersion)
+	require.NoError(t, err)
+	compactor := fixtures.NewNoopCompactor(ls)
+	<-compactor.Ready()
+	defer func() {
+		<-ls.Done()
+		<-compactor.Done()
+	}()
+
+	fruit := flow.NewRegisterID("fruit", "")
+	vegetable := flow.NewRegisterID("vegetable", "")
+	unset := flow.NewRegisterID("mineral", "")
+
+	update, err := ledger.NewUpdate(
+		ls.InitialState(),
+		[]ledger.Key{state.RegisterIDToKey(fruit), state.RegisterIDToKey(vegetable)},
+		[]ledger.Value{ledger.Value("apple"), ledger.Value("carrot")},
+	)
+	require.NoError(t, err)
+	newState, _, err := ls.Set(update)
+	require.NoError(t, err)
+	commitment := flow.StateCommitment(newState)
+
+	prove := func(ids ...flow.RegisterID) []byte {
+		keys := make([]ledger.Key, len(ids))
+		for i, id := range ids {
+			keys[i] = state.RegisterIDToKey(id)
+		}
+		query, err := ledger.NewQuery(newState, keys)
+		require.NoError(t, err)
+		proof, err := ls.Prove(query)
+		require.NoError(t, err)
+		return proof
+	}
+
+	t.Run("valid proof", func(t *testing.T) {
+		ids := []flow.RegisterID{vegetable, unset, fruit}
+		values, err := registerproof.Verify(commitment, ids, prove(ids...))
+		require.NoError(t, err)
+		require.Len(t, values, 3)
+		assert.Equal(t, flow.RegisterValue("carrot"), values[0])
+		assert.Empty(t, values[1])
+		assert.Equal(t, flow.RegisterValue("apple"), values[2])
+	})
+
+	t.Run("wrong state commitment", func(t *testing.T) {
+		ids := []flow.RegisterID{fruit}
+		_, err := registerproof.Verify(unittest.StateCommitmentFixture(), ids, prove(ids...))
+		assert.ErrorIs(t, err, registerproof.ErrInvalidProof)
+	})
+
+	t.Run("proof not covering all registers", func(t *testing.T) {
+		_, err := registerproof.Verify(commitment, []flow.RegisterID{fruit, vegetable}, prove(fruit))
+		assert.ErrorIs(t, err, registerproof.ErrInvalidProof)
+	})
+
+	t.Run("tampered proof", func(t *testing.T) {
+		ids := []flow.RegisterID{fruit}
+		batchProof, err := ledger.DecodeTrieBatchProof(prove(ids...))
+		require.NoError(t, err)
+		batchProof.Proofs[0].Payload = ledger.NewPayload(state.RegisterIDToKey(fruit), ledger.Value("banana"))
+
+		_, err = registerproof.Verify(commitment, ids, ledger.EncodeTrieBatchProof(batchProof))
+		assert.ErrorIs(t, err, registerproof.ErrInvalidProof)
+	})
+
+	t.Run("malformed proof", func(t *testing.T) {
+		_, err := registerproof.Verify(commitment, []flow.RegisterID{fruit}, []byte{0xde, 0xad})
+		assert.ErrorIs(t, err, registerproof.ErrInvalidProof)
+	})
+}
@@ -31,6 +31,13 @@ type ReadOnlyExecutionState interface {
 	// HasState returns true if the state with the given state commitment exists in memory
 	HasState(flow.StateCommitment) bool
 
+	// GetRegisterProofs returns an encoded batch proof (see ledger.EncodeTrieBatchProof) for
+	// the given registers at the given state commitment. The proof covers registers that
+	// are not set as well, so clients can verify both inclusion and non-inclusion.
+	// Expected errors during normal operations:
+	//   - ErrStateNotFound if the state commitment is not (or no longer) held in memory
+	GetRegisterProofs(flow.StateCommitment, []flow.RegisterID) ([]byte, error)
+
 	// ChunkDataPackByChunkID retrieve a chunk data pack given the chunk ID.
 	ChunkDataPackByChunkID(flow.Identifier) (*flow.ChunkDataPack, error)
 
@@ -65,6 +72,10 @@ const (
 	KeyPartKey = uint16(2)
 )
 
+// ErrStateNotFound is returned when the requested state commitment is not held by the ledger,
+// either because it was never executed or because it was already purged from memory.
+var ErrStateNotFound = errors.New("state commitment not found")
+
 type state struct {
 	tracer             module.Tracer
 	ls                 ledger.Ledger
@@ -254,6 +265,35 @@ func (s *state) HasState(commitment flow.StateCommitment) bool {
 	return s.ls.HasState(ledger.State(commitment))
 }
 
+func (s *state) GetRegisterProofs(
+	commitment flow.StateCommitment,
+	registerIDs []flow.RegisterID,
+) (
+	[]byte,
+	error,
+) {
+	if !s.ls.HasState(ledger.State(commitment)) {
+		return nil, fmt.Errorf("could not prove registers at %x: %w", commitment[:], ErrStateNotFound)
+	}
+
+	keys := make([]ledger.Key, len(registerIDs))
+	for i, id := range registerIDs {
+		keys[i] = RegisterIDToKey(id)
+	}
+
+	query, err := ledger.NewQuery(ledger.State(commitment), keys)
+	if err != nil {
+		return nil, fmt.Errorf("cannot create ledger query: %w", err)
+	}
+
+	proof, err := s.ls.Prove(query)
+	if err != nil {
+		return nil, fmt.Errorf("cannot prove registers at %x: %w", commitment[:], err)
+	}
+
+	return proof, nil
+}
+
 func (s *state) StateCommitmentByBlockID(ctx context.Context, blockID flow.Identifier) (flow.StateCommitment, error) {
 	return s.commits.ByBlockID(blockID)
 }
@@ -12,6 +12,7 @@ import (
 
 	ledger2 "github.com/onflow/flow-go/ledger"
 	"github.com/onflow/flow-go/ledger/common/pathfinder"
+	proof2 "github.com/onflow/flow-go/ledger/common/proof"
 
 	"github.com/onflow/flow-go/engine/execution/state"
 	"github.com/onflow/flow-go/engine/execution/state/delta"
@@ -232,4 +233,36 @@ func TestExecutionStateWithTrieStorage(t *testing.T) {
 		require.Equal(t, sc2, sc2Same)
 	}))
 
+	t.Run("prove registers at committed state", prepareTest(func(t *testing.T, es state.ExecutionState, l *ledger.Ledger) {
+		// TODO: use real block ID
+		sc1, err := es.StateCommitmentByBlockID(context.Background(), flow.Identifier{})
+		assert.NoError(t, err)
+
+		view1 := delta.NewDeltaView(es.NewStorageSnapshot(sc1))
+		err = view1.Set(registerID1, flow.RegisterValue("apple"))
+		assert.NoError(t, err)
+
+		sc2, _, err := state.CommitDelta(l, view1.Delta(), sc1)
+		assert.NoError(t, err)
+
+		proof, err := es.GetRegisterProofs(sc2, []flow.RegisterID{registerID1, registerID2})
+		require.NoError(t, err)
+
+		batchProof, err := ledger2.DecodeTrieBatchProof(proof)
+		require.NoError(t, err)
+		require.Equal(t, 2, batchProof.Size())
+		assert.True(t, proof2.VerifyTrieBatchProof(batchProof, ledger2.State(sc2)))
+	}))
+
+	t.Run("prove registers at unknown state", prepareTest(func(t *testing.T, es state.ExecutionState, l *ledger.Ledger) {
+		// TODO: use real block ID
+		sc1, err := es.StateCommitmentByBlockID(context.Background(), flow.Identifier{})
+		assert.NoError(t, err)
+
+		_, err = es.GetRegisterProofs(sc1, []flow.RegisterID{registerID1})
+		assert.NoError(t, err)
+
+		_, err = es.GetRegisterProofs(unittest.StateCommitmentFixture(), []flow.RegisterID{registerID1})
+		assert.ErrorIs(t, err, state.ErrStateNotFound)
+	}))
 }
@@ -87,6 +87,7 @@ func registerConduits(net network.Network, state protocol.State, eng network.Eng
 		channels.PushBlocks,
 		channels.PushReceipts,
 		channels.PushApprovals,
+		channels.PushChunkFaultReports,
 		channels.RequestCollections,
 		channels.RequestChunks,
 	}
@@ -704,6 +704,7 @@ func ExecutionNode(t *testing.T, hub *stub.Hub, identity *flow.Identity, identit
 	syncEngine, err := synchronization.New(
 		node.Log,
 		node.Metrics,
+		node.Metrics,
 		node.Net,
 		node.Me,
 		node.Blocks,
@@ -941,6 +942,7 @@ func VerificationNode(t testing.TB,
 		chunkVerifier := chunks.NewChunkVerifier(vm, vmCtx, node.Log)
 
 		approvalStorage := storage.NewResultApprovals(node.Metrics, node.PublicDB)
+		faultReportStorage := storage.NewChunkFaultReports(node.PublicDB)
 
 		node.VerifierEngine, err = verifier.New(node.Log,
 			collector,
@@ -949,7 +951,8 @@ func VerificationNode(t testing.TB,
 			node.State,
 			node.Me,
 			chunkVerifier,
-			approvalStorage)
+			approvalStorage,
+			faultReportStorage)
 		require.Nil(t, err)
 	}
 
@@ -1010,6 +1013,7 @@ func VerificationNode(t testing.TB,
 			assigner,
 			node.ChunksQueue,
 			node.ChunkConsumer,
+			0,
 			0)
 	}
 
@@ -25,6 +25,7 @@ type BlockConsumer struct {
 	defaultIndex uint64
 	unit         *engine.Unit
 	metrics      module.VerificationMetrics
+	state        protocol.State
 }
 
 // defaultProcessedIndex returns the last sealed block height from the protocol state.
@@ -70,6 +71,7 @@ func NewBlockConsumer(log zerolog.Logger,
 		defaultIndex: defaultIndex,
 		unit:         engine.NewUnit(),
 		metrics:      metrics,
+		state:        state,
 	}
 	worker.withBlockConsumer(blockConsumer)
 
@@ -88,6 +90,33 @@ func (c *BlockConsumer) Size() uint {
 	return c.consumer.Size()
 }
 
+// LastProcessedIndex returns the height of the last block processed by the block consumer.
+func (c *BlockConsumer) LastProcessedIndex() uint64 {
+	return c.consumer.LastProcessedIndex()
+}
+
+// FastForward moves the processed height of the block consumer forward to the given height, so that the
+// assigner engine skips the blocks up to and including it. It allows an operator to let a verification
+// node that is catching up skip the blocks that are already sealed.
+// No errors are expected during normal operation, if the height is not beyond the last processed height,
+// or is beyond the latest sealed height, an error is returned and the processed height is unchanged.
+func (c *BlockConsumer) FastForward(height uint64) error {
+	sealed, err := c.state.Sealed().Head()
+	if err != nil {
+		return fmt.Errorf("could not get sealed head: %w", err)
+	}
+	if height > sealed.Height {
+		return fmt.Errorf("can not fast forward to height %v beyond the sealed height %v", height, sealed.Height)
+	}
+
+	err = c.consumer.FastForward(height)
+	if err != nil {
+		return fmt.Errorf("could not fast forward block consumer: %w", err)
+	}
+	c.metrics.OnBlockConsumerJobDone(c.consumer.LastProcessedIndex())
+	return nil
+}
+
 // OnFinalizedBlock implements FinalizationConsumer, and is invoked by the follower engine whenever
 // a new block is finalized.
 // In this implementation for block consumer, invoking OnFinalizedBlock is enough to only notify the consumer
@@ -22,6 +22,12 @@ import (
 // For each receipt, it reads its result and find the chunks the assigned
 // to me to verify, and then save it to the chunks job queue for the
 // fetcher engine to process.
+//
+// While catching up on old blocks, the results of the blocks sealed deeper than the catch-up
+// depth below the latest sealed block are skipped altogether, as verifying them no longer
+// contributes to sealing. Out of the other results of a finalized block, the chunks of the
+// results of unsealed blocks are pushed to the chunks queue ahead of the chunks of the results
+// of sealed blocks, so that the fetcher engine requests their chunk data packs first.
 type Engine struct {
 	unit                  *engine.Unit
 	log                   zerolog.Logger
@@ -35,6 +41,7 @@ type Engine struct {
 	blockConsumerNotifier module.ProcessingNotifier // to report a block has been processed.
 	stopAtHeight          uint64
 	stopAtBlockID         atomic.Value
+	sealedSkipDepth       uint64 // results of blocks sealed more than this depth below the sealed head are skipped, 0 disables skipping.
 }
 
 func New(
@@ -47,6 +54,7 @@ func New(
 	chunksQueue storage.ChunksQueue,
 	newChunkListener module.NewJobListener,
 	stopAtHeight uint64,
+	sealedSkipDepth uint64,
 ) *Engine {
 	e := &Engine{
 		unit:             engine.NewUnit(),
@@ -59,6 +67,7 @@ func New(
 		chunksQueue:      chunksQueue,
 		newChunkListener: newChunkListener,
 		stopAtHeight:     stopAtHeight,
+		sealedSkipDepth:  sealedSkipDepth,
 	}
 	e.stopAtBlockID.Store(flow.ZeroID)
 	return e
@@ -192,9 +201,14 @@ func (e *Engine) processFinalizedBlock(ctx context.Context, block *flow.Block) {
 		Int("result_num", len(block.Payload.Results)).Logger()
 	lg.Debug().Msg("new finalized block arrived")
 
+	results, err := e.catchUpResults(block.Payload.Results)
+	if err != nil {
+		lg.Fatal().Err(err).Msg("could not determine results to assign chunks for")
+	}
+
 	// determine chunk assigment on each result and pushes the assigned chunks to the chunks queue.
 	receiptsGroupedByResultID := block.Payload.Receipts.GroupByResultID() // for logging purposes
-	for _, result := range block.Payload.Results {
+	for _, result := range results {
 		resultID := result.ID()
 
 		// log receipts committing to result
@@ -241,6 +255,53 @@ func (e *Engine) processFinalizedBlock(ctx context.Context, block *flow.Block) {
 		Msg("finished processing finalized block")
 }
 
+// catchUpResults returns the results to determine chunk assignment for, out of the results included in a finalized block,
+// skipping the results of the blocks sealed more than the sealed skip depth below the latest sealed block. The results of
+// the unsealed blocks are prioritized: they are returned first, followed by the remaining results of the sealed blocks,
+// each in the order of the block.
+// No errors are expected during normal operation.
+func (e *Engine) catchUpResults(results []*flow.ExecutionResult) ([]*flow.ExecutionResult, error) {
+	if len(results) == 0 {
+		return results, nil
+	}
+
+	sealed, err := e.state.Sealed().Head()
+	if err != nil {
+		return nil, fmt.Errorf("could not get sealed head: %w", err)
+	}
+	e.metrics.OnSealedHeightObservedAtAssigner(sealed.Height)
+
+	unsealedResults := make([]*flow.ExecutionResult, 0, len(results))
+	sealedResults := make([]*flow.ExecutionResult, 0)
+	for _, result := range results {
+		executed, err := e.state.AtBlockID(result.BlockID).Head()
+		if err != nil {
+			return nil, fmt.Errorf("could not get executed block %x of result: %w", result.BlockID, err)
+		}
+
+		if executed.Height > sealed.Height {
+			unsealedResults = append(unsealedResults, result)
+			continue
+		}
+
+		sealedBeyondDepth := e.sealedSkipDepth > 0 && executed.Height+e.sealedSkipDepth < sealed.Height
+		if sealedBeyondDepth {
+			e.metrics.OnSealedResultSkippedAtAssigner()
+			e.log.Debug().
+				Hex("result_id", logging.Entity(result)).
+				Hex("executed_block_id", logging.ID(result.BlockID)).
+				Uint64("executed_block_height", executed.Height).
+				Uint64("sealed_height", sealed.Height).
+				Msg("skipping chunk assignment for result of block sealed beyond catch-up depth")
+			continue
+		}
+
+		sealedResults = append(sealedResults, result)
+	}
+
+	return append(unsealedResults, sealedResults...), nil
+}
+
 // chunkAssignments returns the list of chunks in the chunk list assigned to this verification node.
 func (e *Engine) chunkAssignments(ctx context.Context, result *flow.ExecutionResult, incorporatingBlock flow.Identifier) (flow.ChunkList, error) {
 	span, _ := e.tracer.StartSpanFromContext(ctx, trace.VERMatchMyChunkAssignments)
@@ -26,6 +26,7 @@ type AssignerEngineTestSuite struct {
 	me               *module.Local
 	state            *protocol.State
 	snapshot         *protocol.Snapshot
+	sealedSnapshot   *protocol.Snapshot
 	metrics          *module.VerificationMetrics
 	tracer           *trace.NoopTracer
 	assigner         *module.ChunkAssigner
@@ -35,6 +36,9 @@ type AssignerEngineTestSuite struct {
 
 	// identities
 	verIdentity *flow.Identity // verification node
+
+	sealedHeight    uint64 // height of the latest sealed block
+	sealedSkipDepth uint64 // catch-up depth of the assigner engine
 }
 
 // mockChunkAssigner mocks the chunk assigner of this test suite to assign the chunks based on the input assignment.
@@ -47,10 +51,20 @@ func (s *AssignerEngineTestSuite) mockChunkAssigner(result *flow.IncorporatedRes
 }
 
 // mockStateAtBlockID is a test helper that mocks the protocol state of test suite at the given block id. This is the
-// underlying protocol state of the verification node of the test suite.
+// underlying protocol state of the verification node of the test suite, in which the block is not sealed yet.
 func (s *AssignerEngineTestSuite) mockStateAtBlockID(blockID flow.Identifier) {
 	s.state.On("AtBlockID", blockID).Return(s.snapshot)
 	s.snapshot.On("Identity", s.verIdentity.NodeID).Return(s.verIdentity, nil)
+	s.snapshot.On("Head").Return(unittest.BlockHeaderFixture(unittest.WithHeaderHeight(s.sealedHeight+1)), nil)
+}
+
+// mockStateAtBlockHeight is a test helper that mocks the protocol state of test suite at the given block id, with
+// the block being at the given height.
+func (s *AssignerEngineTestSuite) mockStateAtBlockHeight(blockID flow.Identifier, height uint64) {
+	snapshot := &protocol.Snapshot{}
+	s.state.On("AtBlockID", blockID).Return(snapshot)
+	snapshot.On("Identity", s.verIdentity.NodeID).Return(s.verIdentity, nil).Maybe()
+	snapshot.On("Head").Return(unittest.BlockHeaderFixture(unittest.WithHeaderHeight(height)), nil)
 }
 
 // myID is a test helper that returns identifier of verification identity.
@@ -64,12 +78,19 @@ func WithIdentity(identity *flow.Identity) func(*AssignerEngineTestSuite) {
 	}
 }
 
+func WithSealedSkipDepth(depth uint64) func(*AssignerEngineTestSuite) {
+	return func(testSuite *AssignerEngineTestSuite) {
+		testSuite.sealedSkipDepth = depth
+	}
+}
+
 // SetupTest initiates the test setups prior to each test.
 func SetupTest(options ...func(suite *AssignerEngineTestSuite)) *AssignerEngineTestSuite {
 	s := &AssignerEngineTestSuite{
 		me:               &module.Local{},
 		state:            &protocol.State{},
 		snapshot:         &protocol.Snapshot{},
+		sealedSnapshot:   &protocol.Snapshot{},
 		metrics:          &module.VerificationMetrics{},
 		tracer:           trace.NewNoopTracer(),
 		assigner:         &module.ChunkAssigner{},
@@ -77,6 +98,7 @@ func SetupTest(options ...func(suite *AssignerEngineTestSuite)) *AssignerEngineT
 		newChunkListener: &module.NewJobListener{},
 		verIdentity:      unittest.IdentityFixture(unittest.WithRole(flow.RoleVerification)),
 		notifier:         &module.ProcessingNotifier{},
+		sealedHeight:     100,
 	}
 
 	for _, apply := range options {
@@ -115,10 +137,17 @@ func NewAssignerEngine(s *AssignerEngineTestSuite) *assigner.Engine {
 		s.state,
 		s.assigner,
 		s.chunksQueue,
-		s.newChunkListener, 0)
+		s.newChunkListener,
+		0,
+		s.sealedSkipDepth)
 
 	e.WithBlockConsumerNotifier(s.notifier)
 
+	// mocks the latest sealed block of the protocol state
+	s.state.On("Sealed").Return(s.sealedSnapshot).Maybe()
+	s.sealedSnapshot.On("Head").Return(unittest.BlockHeaderFixture(unittest.WithHeaderHeight(s.sealedHeight)), nil).Maybe()
+	s.metrics.On("OnSealedHeightObservedAtAssigner", s.sealedHeight).Return().Maybe()
+
 	// mocks identity of the verification node
 	s.me.On("NodeID").Return(s.verIdentity.NodeID)
 
@@ -146,6 +175,12 @@ func TestAssignerEngine(t *testing.T) {
 	t.Run("chunk queue unhappy path duplicate", func(t *testing.T) {
 		chunkQueueUnhappyPathDuplicate(t)
 	})
+	t.Run("new block sealed beyond depth", func(t *testing.T) {
+		newBlockSealedBeyondDepth(t)
+	})
+	t.Run("new block sealed result within catch-up depth", func(t *testing.T) {
+		newBlockSealedResultWithinDepth(t)
+	})
 }
 
 // newBlockHappyPath evaluates that passing a new finalized block to assigner engine that contains
@@ -388,6 +423,104 @@ func chunkQueueUnhappyPathDuplicate(t *testing.T) {
 	s.newChunkListener.AssertNotCalled(t, "Check")
 }
 
+// newBlockSealedBeyondDepth evaluates that passing a new finalized block to assigner engine that contains
+// a result for a block sealed beyond the catch-up depth, and a result for an unsealed block, results in the
+// assigner engine skipping the chunk assignment of the sealed result, while assigning the chunks of the unsealed one.
+func newBlockSealedBeyondDepth(t *testing.T) {
+	s := SetupTest(WithSealedSkipDepth(10))
+	e := NewAssignerEngine(s)
+
+	// creates a container block, with a receipt for an unsealed block, and a receipt
+	// for a block sealed beyond the catch-up depth, each with one chunk assigned to
+	// verification node.
+	containerBlock, assignment := createContainerBlock(
+		vertestutils.WithChunks(
+			vertestutils.WithAssignee(s.myID())))
+	sealedBlock, _ := createContainerBlock(
+		vertestutils.WithChunks(
+			vertestutils.WithAssignee(s.myID())))
+	containerBlock.Payload.Receipts = append(containerBlock.Payload.Receipts, sealedBlock.Payload.Receipts...)
+	containerBlock.Payload.Results = append(containerBlock.Payload.Results, sealedBlock.Payload.Results...)
+
+	result := containerBlock.Payload.Results[0]
+	s.mockStateAtBlockID(result.BlockID)
+	sealedResult := containerBlock.Payload.Results[1]
+	s.mockStateAtBlockHeight(sealedResult.BlockID, s.sealedHeight-11)
+	chunksNum := s.mockChunkAssigner(flow.NewIncorporatedResult(containerBlock.ID(), result), assignment)
+	require.Equal(t, chunksNum, 1) // one chunk should be assigned
+
+	// only the chunk of the unsealed result should be stored in the chunks queue.
+	chunksQueueWG := mockChunksQueueForAssignment(t, s.verIdentity.NodeID, s.chunksQueue, result.ID(), assignment, true, nil)
+	s.newChunkListener.On("Check").Return().Times(chunksNum)
+	s.notifier.On("Notify", containerBlock.ID()).Return().Once()
+	s.metrics.On("OnAssignedChunkProcessedAtAssigner").Return().Once()
+	s.metrics.On("OnSealedResultSkippedAtAssigner").Return().Once()
+
+	// sends containerBlock containing receipts to assigner engine
+	s.metrics.On("OnFinalizedBlockArrivedAtAssigner", containerBlock.Header.Height).Return().Once()
+	s.metrics.On("OnExecutionResultReceivedAtAssignerEngine").Return().Once()
+	e.ProcessFinalizedBlock(containerBlock)
+
+	unittest.RequireReturnsBefore(t, chunksQueueWG.Wait, 10*time.Millisecond, "could not receive chunk locators")
+
+	mock.AssertExpectationsForObjects(t,
+		s.metrics,
+		s.assigner,
+		s.newChunkListener,
+		s.notifier)
+}
+
+// newBlockSealedResultWithinDepth evaluates that passing a new finalized block to assigner engine that contains
+// a result for a block sealed within the catch-up depth, followed by a result for an unsealed block, results in the
+// assigner engine passing the chunks of both results to the chunks queue, prioritizing the chunks of the unsealed one.
+func newBlockSealedResultWithinDepth(t *testing.T) {
+	s := SetupTest(WithSealedSkipDepth(10))
+	e := NewAssignerEngine(s)
+
+	// creates a container block, with a receipt for a block sealed within the catch-up depth,
+	// followed by a receipt for an unsealed block, each with one chunk assigned to verification node.
+	containerBlock, sealedAssignment := createContainerBlock(
+		vertestutils.WithChunks(
+			vertestutils.WithAssignee(s.myID())))
+	unsealedBlock, unsealedAssignment := createContainerBlock(
+		vertestutils.WithChunks(
+			vertestutils.WithAssignee(s.myID())))
+	containerBlock.Payload.Receipts = append(containerBlock.Payload.Receipts, unsealedBlock.Payload.Receipts...)
+	containerBlock.Payload.Results = append(containerBlock.Payload.Results, unsealedBlock.Payload.Results...)
+
+	sealedResult := containerBlock.Payload.Results[0]
+	s.mockStateAtBlockHeight(sealedResult.BlockID, s.sealedHeight-10)
+	unsealedResult := containerBlock.Payload.Results[1]
+	s.mockStateAtBlockID(unsealedResult.BlockID)
+	s.mockChunkAssigner(flow.NewIncorporatedResult(containerBlock.ID(), sealedResult), sealedAssignment)
+	s.mockChunkAssigner(flow.NewIncorporatedResult(containerBlock.ID(), unsealedResult), unsealedAssignment)
+
+	// records the order of the results of the chunk locators stored in the chunks queue.
+	storedResults := make([]flow.Identifier, 0)
+	s.chunksQueue.On("StoreChunkLocator", mock.Anything).Run(func(args mock.Arguments) {
+		locator, ok := args[0].(*chunks.Locator)
+		require.True(t, ok)
+		storedResults = append(storedResults, locator.ResultID)
+	}).Return(true, nil).Twice()
+	s.newChunkListener.On("Check").Return().Twice()
+	s.notifier.On("Notify", containerBlock.ID()).Return().Once()
+	s.metrics.On("OnAssignedChunkProcessedAtAssigner").Return().Twice()
+
+	// sends containerBlock containing receipts to assigner engine
+	s.metrics.On("OnFinalizedBlockArrivedAtAssigner", containerBlock.Header.Height).Return().Once()
+	s.metrics.On("OnExecutionResultReceivedAtAssignerEngine").Return().Twice()
+	e.ProcessFinalizedBlock(containerBlock)
+
+	require.Equal(t, []flow.Identifier{unsealedResult.ID(), sealedResult.ID()}, storedResults)
+
+	mock.AssertExpectationsForObjects(t,
+		s.metrics,
+		s.assigner,
+		s.chunksQueue,
+		s.newChunkListener,
+		s.notifier)
+}
+
 // mockChunksQueueForAssignment mocks chunks queue against invoking its store functionality for the
 // input assignment.
 // The mocked version of chunks queue evaluates that whatever chunk locator is tried to be stored belongs to the
@@ -476,6 +476,50 @@ func TestSkipChunkOfSealedBlock(t *testing.T) {
 	s.pendingChunks.AssertNotCalled(t, "Add")
 }
 
+// TestSealedChunksDoNotHoldBackUnsealedChunks evaluates that the chunks of sealed blocks queued ahead of the chunks of
+// an unsealed block, as the assigner queues them while catching up, are completed by the fetcher engine without
+// fetching their chunk data packs, so that the chunk data pack of the unsealed block is requested right after.
+func TestSealedChunksDoNotHoldBackUnsealedChunks(t *testing.T) {
+	s := setupTest()
+	e := newFetcherEngine(s)
+
+	// the chunk of the unsealed block is queued after the chunks of a result of the block right below it, which is sealed.
+	block, result, statuses, locators, _ := completeChunkStatusListFixture(t, 2, 1)
+	sealedHeader := unittest.BlockHeaderFixture(unittest.WithHeaderHeight(block.Header.Height - 1))
+	sealedResult := unittest.ExecutionResultFixture(unittest.WithExecutionResultBlockID(sealedHeader.ID()))
+	sealedStatuses := unittest.ChunkStatusListFixture(t, sealedHeader.Height, sealedResult, 2)
+	sealedLocators := unittest.ChunkStatusListToChunkLocatorFixture(sealedStatuses)
+	queue := append(sealedLocators.ToList(), locators.ToList()...)
+	s.metrics.On("OnAssignedChunkReceivedAtFetcher").Return().Times(len(queue))
+
+	// the sealed height is the height of the sealed block.
+	mockBlockSealingStatus(s.state, s.headers, block.Header, false)
+	s.headers.On("ByBlockID", sealedHeader.ID()).Return(sealedHeader, nil)
+	mockResultsByIDs(s.results, []*flow.ExecutionResult{sealedResult, result})
+
+	// only the chunk of the unsealed block is added to the pending chunks and requested.
+	_, _, agrees, disagrees := mockReceiptsBlockID(t, block.ID(), s.receipts, result, 1, 0)
+	mockStateAtBlockIDForIdentities(s.state, block.ID(), agrees.Union(disagrees))
+	mockPendingChunksAdd(t, s.pendingChunks, statuses, true)
+	s.metrics.On("OnChunkDataPackRequestSentByFetcher").Return().Once()
+	s.requester.On("Request", mock.Anything).Run(func(args mock.Arguments) {
+		request, ok := args[0].(*verification.ChunkDataPackRequest)
+		require.True(t, ok)
+		require.Equal(t, result.ID(), request.Locator.ResultID)
+		require.Equal(t, statuses[0].ChunkIndex, request.Locator.Index)
+	}).Return().Once()
+
+	// the chunks of the sealed block are completed right away, while the chunk of the unsealed block is completed
+	// once its chunk data pack arrives.
+	mockChunkConsumerNotifier(t, s.chunkConsumerNotifier, flow.GetIDs(sealedLocators.ToList()))
+
+	for _, locator := range queue {
+		e.ProcessAssignedChunk(locator)
+	}
+
+	mock.AssertExpectationsForObjects(t, s.results, s.requester, s.pendingChunks, s.chunkConsumerNotifier, s.metrics)
+}
+
 // TestSkipChunkOfSealedBlock evaluates that if fetcher engine receives a chunk belonging to a sealed block,
 // it drops it without processing it any further and notifies consumer
 // that it is done with processing that chunk.
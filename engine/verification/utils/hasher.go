@@ -11,3 +11,10 @@ func NewResultApprovalHasher() hash.Hasher {
 	h := signature.NewBLSHasher(signature.ResultApprovalTag)
 	return h
 }
+
+// NewChunkFaultReportHasher generates and returns a hasher for signing
+// and verification of chunk fault reports
+func NewChunkFaultReportHasher() hash.Hasher {
+	h := signature.NewBLSHasher(signature.ChunkFaultReportTag)
+	return h
+}
@@ -26,23 +26,26 @@ import (
 	"github.com/onflow/flow-go/utils/logging"
 )
 
-// Engine (verifier engine) verifies chunks, generates result approvals or raises challenges.
+// Engine (verifier engine) verifies chunks, generates result approvals or reports chunk faults.
 // as input it accepts verifiable chunks (chunk + all data needed) and perform verification by
 // constructing a partial trie, executing transactions and check the final state commitment and
 // other chunk meta data (e.g. tx count)
 type Engine struct {
-	unit           *engine.Unit               // used to control startup/shutdown
-	log            zerolog.Logger             // used to log relevant actions
-	metrics        module.VerificationMetrics // used to capture the performance metrics
-	tracer         module.Tracer              // used for tracing
-	pushConduit    network.Conduit            // used to push result approvals
-	pullConduit    network.Conduit            // used to respond to requests for result approvals
-	me             module.Local               // used to access local node information
-	state          protocol.State             // used to access the protocol state
-	approvalHasher hash.Hasher                // used as hasher to sign the result approvals
-	chVerif        module.ChunkVerifier       // used to verify chunks
-	spockHasher    hash.Hasher                // used for generating spocks
-	approvals      storage.ResultApprovals    // used to store result approvals
+	unit              *engine.Unit               // used to control startup/shutdown
+	log               zerolog.Logger             // used to log relevant actions
+	metrics           module.VerificationMetrics // used to capture the performance metrics
+	tracer            module.Tracer              // used for tracing
+	pushConduit       network.Conduit            // used to push result approvals
+	pullConduit       network.Conduit            // used to respond to requests for result approvals
+	faultConduit      network.Conduit            // used to push chunk fault reports
+	me                module.Local               // used to access local node information
+	state             protocol.State             // used to access the protocol state
+	approvalHasher    hash.Hasher                // used as hasher to sign the result approvals
+	faultReportHasher hash.Hasher                // used as hasher to sign the chunk fault reports
+	chVerif           module.ChunkVerifier       // used to verify chunks
+	spockHasher       hash.Hasher                // used for generating spocks
+	approvals         storage.ResultApprovals    // used to store result approvals
+	faultReports      storage.ChunkFaultReports  // used to store chunk fault reports
 }
 
 // New creates and returns a new instance of a verifier engine.
@@ -55,19 +58,22 @@ func New(
 	me module.Local,
 	chVerif module.ChunkVerifier,
 	approvals storage.ResultApprovals,
+	faultReports storage.ChunkFaultReports,
 ) (*Engine, error) {
 
 	e := &Engine{
-		unit:           engine.NewUnit(),
-		log:            log.With().Str("engine", "verifier").Logger(),
-		metrics:        metrics,
-		tracer:         tracer,
-		state:          state,
-		me:             me,
-		chVerif:        chVerif,
-		approvalHasher: utils.NewResultApprovalHasher(),
-		spockHasher:    signature.NewBLSHasher(signature.SPOCKTag),
-		approvals:      approvals,
+		unit:              engine.NewUnit(),
+		log:               log.With().Str("engine", "verifier").Logger(),
+		metrics:           metrics,
+		tracer:            tracer,
+		state:             state,
+		me:                me,
+		chVerif:           chVerif,
+		approvalHasher:    utils.NewResultApprovalHasher(),
+		faultReportHasher: utils.NewChunkFaultReportHasher(),
+		spockHasher:       signature.NewBLSHasher(signature.SPOCKTag),
+		approvals:         approvals,
+		faultReports:      faultReports,
 	}
 
 	var err error
@@ -81,6 +87,11 @@ func New(
 		return nil, fmt.Errorf("could not register engine on approval pull channel: %w", err)
 	}
 
+	e.faultConduit, err = net.Register(channels.PushChunkFaultReports, e)
+	if err != nil {
+		return nil, fmt.Errorf("could not register engine on chunk fault report push channel: %w", err)
+	}
+
 	return e, nil
 }
 
@@ -156,7 +167,9 @@ func (e *Engine) process(originID flow.Identifier, event interface{}) error {
 
 // verify handles the core verification process. It accepts a verifiable chunk
 // and all dependent resources, verifies the chunk, and emits a
-// result approval if applicable.
+// result approval if applicable. If a fault is found with the chunk, and the
+// fault is reproduced by verifying the chunk again, a chunk fault report is
+// emitted instead.
 //
 // If any part of verification fails, an error is returned, indicating to the
 // initiating engine that the verification must be re-tried.
@@ -198,26 +211,29 @@ func (e *Engine) verify(ctx context.Context, originID flow.Identifier,
 
 	// if any fault found with the chunk
 	if chFault != nil {
-		switch chFault.(type) {
-		case *chmodels.CFMissingRegisterTouch:
-			e.log.Warn().Msg(chFault.String())
-			// still create approvals for this case
-		case *chmodels.CFNonMatchingFinalState:
-			// TODO raise challenge
-			e.log.Warn().Msg(chFault.String())
-			return nil
-		case *chmodels.CFInvalidVerifiableChunk:
-			// TODO raise challenge
-			e.log.Error().Msg(chFault.String())
-			return nil
-		case *chmodels.CFInvalidEventsCollection:
-			// TODO raise challenge
-			e.log.Error().Msg(chFault.String())
-			return nil
-		default:
+		faultType, ok := chmodels.FaultType(chFault)
+		if !ok {
 			return engine.NewInvalidInputErrorf("unknown type of chunk fault is received (type: %T) : %v",
 				chFault, chFault.String())
 		}
+		log.Warn().Str("fault_type", string(faultType)).Msg(chFault.String())
+
+		// the fault may be caused by a transient failure of this node rather than by a faulty
+		// execution, so the chunk is verified again before the fault is reported.
+		err = e.reverify(ctx, vc, faultType)
+		if err != nil {
+			return err
+		}
+
+		err = e.reportFault(ctx, vc, chFault, faultType)
+		if err != nil {
+			return fmt.Errorf("could not report chunk fault: %w", err)
+		}
+
+		// still create approvals for missing register touches
+		if faultType != flow.ChunkFaultMissingRegisterTouch {
+			return nil
+		}
 	}
 
 	// Generate result approval
@@ -271,6 +287,117 @@ func (e *Engine) verify(ctx context.Context, originID flow.Identifier,
 	return nil
 }
 
+// reverify verifies the chunk again, and returns an error if the fault of the given type found by the first
+// verification is not reproduced.
+func (e *Engine) reverify(ctx context.Context, vc *verification.VerifiableChunkData, faultType flow.ChunkFaultType) error {
+	span, _ := e.tracer.StartSpanFromContext(ctx, trace.VERVerChunkVerify)
+	_, chFault, err := e.chVerif.Verify(vc)
+	span.End()
+	if err != nil {
+		return fmt.Errorf("cannot re-verify chunk: %w", err)
+	}
+	if chFault == nil {
+		return fmt.Errorf("chunk fault (%s) is not reproduced by re-verification", faultType)
+	}
+	reverified, _ := chmodels.FaultType(chFault)
+	if reverified != faultType {
+		return fmt.Errorf("chunk fault (%s) is not reproduced by re-verification, found (%s) instead", faultType, reverified)
+	}
+	return nil
+}
+
+// reportFault generates a signed chunk fault report for the given chunk fault, stores it, and sends it to each of the
+// consensus nodes. As the report embeds the chunk data pack, it is sent by unicast, which allows for messages larger
+// than the pubsub message size limit.
+func (e *Engine) reportFault(ctx context.Context, vc *verification.VerifiableChunkData, chFault chmodels.ChunkFault, faultType flow.ChunkFaultType) error {
+	span, _ := e.tracer.StartSpanFromContext(ctx, trace.VERVerGenerateChunkFaultReport)
+	report, err := GenerateChunkFaultReport(e.me, e.faultReportHasher, vc, chFault, faultType)
+	span.End()
+	if err != nil {
+		return fmt.Errorf("couldn't generate a chunk fault report: %w", err)
+	}
+
+	epoch, err := e.state.AtBlockID(report.Body.BlockID).Epochs().Current().Counter()
+	if err != nil {
+		return fmt.Errorf("could not get epoch of block %x: %w", report.Body.BlockID, err)
+	}
+	err = e.faultReports.Store(report, epoch)
+	if err != nil {
+		return fmt.Errorf("could not store chunk fault report: %w", err)
+	}
+
+	consensusNodes, err := e.state.Final().
+		Identities(filter.HasRole(flow.RoleConsensus))
+	if err != nil {
+		return fmt.Errorf("could not load consensus node IDs: %w", err)
+	}
+
+	sent := 0
+	for _, nodeID := range consensusNodes.NodeIDs() {
+		err = e.faultConduit.Unicast(report, nodeID)
+		if err != nil {
+			// the report is stored, and the other consensus nodes receive it
+			e.log.Error().Err(err).
+				Hex("report_id", logging.Entity(report)).
+				Hex("target_id", logging.ID(nodeID)).
+				Msg("could not send chunk fault report to consensus node")
+			continue
+		}
+		sent++
+	}
+	if sent == 0 {
+		return fmt.Errorf("could not send chunk fault report to any of the %d consensus nodes", len(consensusNodes))
+	}
+	e.log.Info().
+		Hex("result_id", logging.ID(report.Body.ExecutionResultID)).
+		Uint64("chunk_index", report.Body.ChunkIndex).
+		Hex("report_id", logging.Entity(report)).
+		Str("fault_type", string(faultType)).
+		Int("consensus_nodes", sent).
+		Msg("chunk fault report submitted")
+	e.metrics.OnChunkFaultReportDispatchedInNetworkByVerifier()
+
+	return nil
+}
+
+// GenerateChunkFaultReport generates a chunk fault report for the given fault of the verifiable chunk.
+func GenerateChunkFaultReport(
+	me module.Local,
+	faultReportHasher hash.Hasher,
+	vc *verification.VerifiableChunkData,
+	chFault chmodels.ChunkFault,
+	faultType flow.ChunkFaultType,
+) (*flow.ChunkFaultReport, error) {
+
+	body := flow.ChunkFaultReportBody{
+		BlockID:           vc.Header.ID(),
+		ExecutionResultID: vc.Result.ID(),
+		ChunkIndex:        vc.Chunk.Index,
+		ReporterID:        me.NodeID(),
+		FaultType:         faultType,
+		FaultDetails:      chFault.String(),
+	}
+	if vc.ChunkDataPack != nil {
+		body.ChunkDataPack = *vc.ChunkDataPack
+	}
+	// the end state is only computed by the verification when the final state does not match
+	if nonMatching, ok := chFault.(*chmodels.CFNonMatchingFinalState); ok {
+		body.ComputedEndState = nonMatching.Expected()
+	}
+
+	// generates a signature over the report body
+	bodyID := body.ID()
+	sig, err := me.Sign(bodyID[:], faultReportHasher)
+	if err != nil {
+		return nil, fmt.Errorf("could not sign chunk fault report body: %w", err)
+	}
+
+	return &flow.ChunkFaultReport{
+		Body:              body,
+		ReporterSignature: sig,
+	}, nil
+}
+
 // GenerateResultApproval generates result approval for specific chunk of an execution receipt.
 func GenerateResultApproval(
 	me module.Local,
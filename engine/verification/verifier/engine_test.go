@@ -31,18 +31,20 @@ import (
 
 type VerifierEngineTestSuite struct {
 	suite.Suite
-	net       *mocknetwork.Network
-	tracer    realModule.Tracer
-	state     *protocol.State
-	ss        *protocol.Snapshot
-	me        *mocklocal.MockLocal
-	sk        crypto.PrivateKey
-	hasher    hash.Hasher
-	chain     flow.Chain
-	pushCon   *mocknetwork.Conduit // mocks con for submitting result approvals
-	pullCon   *mocknetwork.Conduit
-	metrics   *mockmodule.VerificationMetrics // mocks performance monitoring metrics
-	approvals *mockstorage.ResultApprovals
+	net          *mocknetwork.Network
+	tracer       realModule.Tracer
+	state        *protocol.State
+	ss           *protocol.Snapshot
+	me           *mocklocal.MockLocal
+	sk           crypto.PrivateKey
+	hasher       hash.Hasher
+	chain        flow.Chain
+	pushCon      *mocknetwork.Conduit // mocks con for submitting result approvals
+	pullCon      *mocknetwork.Conduit
+	faultCon     *mocknetwork.Conduit            // mocks con for submitting chunk fault reports
+	metrics      *mockmodule.VerificationMetrics // mocks performance monitoring metrics
+	approvals    *mockstorage.ResultApprovals
+	faultReports *mockstorage.ChunkFaultReports
 }
 
 func TestVerifierEngine(t *testing.T) {
@@ -57,9 +59,11 @@ func (suite *VerifierEngineTestSuite) SetupTest() {
 	suite.ss = &protocol.Snapshot{}
 	suite.pushCon = &mocknetwork.Conduit{}
 	suite.pullCon = &mocknetwork.Conduit{}
+	suite.faultCon = &mocknetwork.Conduit{}
 	suite.metrics = &mockmodule.VerificationMetrics{}
 	suite.chain = flow.Testnet.Chain()
 	suite.approvals = &mockstorage.ResultApprovals{}
+	suite.faultReports = &mockstorage.ChunkFaultReports{}
 
 	suite.approvals.On("Store", mock.Anything).Return(nil)
 	suite.approvals.On("Index", mock.Anything, mock.Anything, mock.Anything).Return(nil)
@@ -72,7 +76,18 @@ func (suite *VerifierEngineTestSuite) SetupTest() {
 		Return(suite.pullCon, nil).
 		Once()
 
+	suite.net.On("Register", channels.PushChunkFaultReports, testifymock.Anything).
+		Return(suite.faultCon, nil).
+		Once()
+
 	suite.state.On("Final").Return(suite.ss)
+	// the chunk fault reports are stored by epoch of their executed block
+	epoch := &protocol.Epoch{}
+	epoch.On("Counter").Return(uint64(1), nil)
+	epochs := &protocol.EpochQuery{}
+	epochs.On("Current").Return(epoch)
+	suite.ss.On("Epochs").Return(epochs)
+	suite.state.On("AtBlockID", testifymock.Anything).Return(suite.ss)
 
 	// Mocks the signature oracle of the engine
 	//
@@ -105,7 +120,8 @@ func (suite *VerifierEngineTestSuite) TestNewEngine() *verifier.Engine {
 		suite.state,
 		suite.me,
 		ChunkVerifierMock{},
-		suite.approvals)
+		suite.approvals,
+		suite.faultReports)
 	require.Nil(suite.T(), err)
 
 	suite.net.AssertExpectations(suite.T())
@@ -192,6 +208,8 @@ func (suite *VerifierEngineTestSuite) TestVerifyUnhappyPaths() {
 
 	// emission of result approval
 	suite.metrics.On("OnResultApprovalDispatchedInNetworkByVerifier").Return()
+	// emission of chunk fault reports
+	suite.metrics.On("OnChunkFaultReportDispatchedInNetworkByVerifier").Return()
 
 	var tests = []struct {
 		vc          *verification.VerifiableChunkData
@@ -202,11 +220,73 @@ func (suite *VerifierEngineTestSuite) TestVerifyUnhappyPaths() {
 		{unittest.VerifiableChunkDataFixture(uint64(3)), nil},
 	}
 	for _, test := range tests {
+		suite.faultReports.On("Store", testifymock.Anything, uint64(1)).Return(nil).Once()
+		suite.faultCon.
+			On("Publish", testifymock.Anything, testifymock.Anything).
+			Return(nil).
+			Once()
 		err := eng.ProcessLocal(test.vc)
 		suite.Assert().NoError(err)
 	}
+	suite.faultReports.AssertExpectations(suite.T())
+	suite.faultCon.AssertExpectations(suite.T())
+}
+
+// TestVerifyFaultReport tests that a chunk fault found by the verifier is reproduced and reported to all consensus
+// nodes through a signed chunk fault report, which is persisted and carries the chunk data pack and the end state
+// computed by the verifier, while no result approval is emitted for the chunk.
+func (suite *VerifierEngineTestSuite) TestVerifyFaultReport() {
+	eng := suite.TestNewEngine()
+	myID := unittest.IdentifierFixture()
+	consensusNodes := unittest.IdentityListFixture(2, unittest.WithRole(flow.RoleConsensus))
+	// chunk index 3 is verified with a non-matching final state
+	vChunk := unittest.VerifiableChunkDataFixture(uint64(3))
+
+	suite.me.MockNodeID(myID)
+	suite.ss.On("Identities", testifymock.Anything).Return(consensusNodes, nil)
+	suite.metrics.On("OnVerifiableChunkReceivedAtVerifierEngine").Return()
+	suite.metrics.On("OnChunkFaultReportDispatchedInNetworkByVerifier").Return().Once()
+
+	hasher := utils.NewChunkFaultReportHasher()
+	checkReport := func(report *flow.ChunkFaultReport) {
+		suite.Assert().Equal(vChunk.Result.ID(), report.Body.ExecutionResultID)
+		suite.Assert().Equal(vChunk.Header.ID(), report.Body.BlockID)
+		suite.Assert().Equal(vChunk.Chunk.Index, report.Body.ChunkIndex)
+		suite.Assert().Equal(myID, report.Body.ReporterID)
+		suite.Assert().Equal(flow.ChunkFaultNonMatchingFinalState, report.Body.FaultType)
+		suite.Assert().Equal(*vChunk.ChunkDataPack, report.Body.ChunkDataPack)
+		suite.Assert().Equal(nonMatchingEndState, report.Body.ComputedEndState)
+		bodyID := report.Body.ID()
+		suite.Assert().True(suite.sk.PublicKey().Verify(report.ReporterSignature, bodyID[:], hasher))
+	}
+
+	suite.faultReports.
+		On("Store", testifymock.Anything, uint64(1)).
+		Return(nil).
+		Run(func(args testifymock.Arguments) {
+			checkReport(args[0].(*flow.ChunkFaultReport))
+		}).
+		Once()
+	for _, consensusNode := range consensusNodes {
+		suite.faultCon.
+			On("Unicast", testifymock.Anything, consensusNode.NodeID).
+			Return(nil).
+			Run(func(args testifymock.Arguments) {
+				checkReport(args[0].(*flow.ChunkFaultReport))
+			}).
+			Once()
+	}
+
+	err := eng.ProcessLocal(vChunk)
+	suite.Assert().NoError(err)
+	suite.faultReports.AssertExpectations(suite.T())
+	suite.faultCon.AssertExpectations(suite.T())
+	suite.pushCon.AssertNotCalled(suite.T(), "Publish", testifymock.Anything, testifymock.Anything)
 }
 
+// nonMatchingEndState is the end state computed by the ChunkVerifierMock for chunks with a non-matching final state.
+var nonMatchingEndState = unittest.StateCommitmentFixture()
+
 type ChunkVerifierMock struct {
 }
 
@@ -235,7 +315,7 @@ func (v ChunkVerifierMock) Verify(vc *verification.VerifiableChunkData) ([]byte,
 
 	case 3:
 		return nil, chmodel.NewCFNonMatchingFinalState(
-			unittest.StateCommitmentFixture(),
+			nonMatchingEndState,
 			unittest.StateCommitmentFixture(),
 			vc.Chunk.Index,
 			vc.Result.ID()), nil
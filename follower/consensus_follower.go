@@ -33,16 +33,17 @@ type ConsensusFollower interface {
 
 // Config contains the configurable fields for a `ConsensusFollower`.
 type Config struct {
-	networkPrivKey   crypto.PrivateKey   // the network private key of this node
-	bootstrapNodes   []BootstrapNodeInfo // the bootstrap nodes to use
-	bindAddr         string              // address to bind on
-	db               *badger.DB          // the badger DB storage to use for the protocol state
-	dataDir          string              // directory to store the protocol state (if the badger storage is not provided)
-	bootstrapDir     string              // path to the bootstrap directory
-	logLevel         string              // log level
-	exposeMetrics    bool                // whether to expose metrics
-	syncConfig       *chainsync.Config   // sync core configuration
-	complianceConfig *compliance.Config  // follower engine configuration
+	networkPrivKey      crypto.PrivateKey   // the network private key of this node
+	bootstrapNodes      []BootstrapNodeInfo // the bootstrap nodes to use
+	bindAddr            string              // address to bind on
+	db                  *badger.DB          // the badger DB storage to use for the protocol state
+	dataDir             string              // directory to store the protocol state (if the badger storage is not provided)
+	bootstrapDir        string              // path to the bootstrap directory
+	logLevel            string              // log level
+	exposeMetrics       bool                // whether to expose metrics
+	syncConfig          *chainsync.Config   // sync core configuration
+	complianceConfig    *compliance.Config  // follower engine configuration
+	peerRecordDiscovery bool                // whether to discover the staked access nodes from their signed peer records
 }
 
 type Option func(c *Config)
@@ -96,6 +97,14 @@ func WithComplianceConfig(config *compliance.Config) Option {
 	}
 }
 
+// WithPeerRecordDiscovery enables the discovery of the staked access nodes from their signed peer records, which
+// restricts the DHT routing table to the bootstrap peers and the verified staked access nodes.
+func WithPeerRecordDiscovery(enabled bool) Option {
+	return func(c *Config) {
+		c.peerRecordDiscovery = enabled
+	}
+}
+
 // BootstrapNodeInfo contains the details about the upstream bootstrap peer the consensus follower uses
 type BootstrapNodeInfo struct {
 	Host             string // ip or hostname
@@ -122,6 +131,7 @@ func getFollowerServiceOptions(config *Config) []FollowerOption {
 		WithBootStrapPeers(ids...),
 		WithBaseOptions(getBaseOptions(config)),
 		WithNetworkKey(config.networkPrivKey),
+		WithPeerRecordDiscoveryEnabled(config.peerRecordDiscovery),
 	}
 }
 
@@ -51,6 +51,7 @@ import (
 	"github.com/onflow/flow-go/network/p2p/keyutils"
 	"github.com/onflow/flow-go/network/p2p/middleware"
 	"github.com/onflow/flow-go/network/p2p/p2pbuilder"
+	"github.com/onflow/flow-go/network/p2p/peerrecord"
 	"github.com/onflow/flow-go/network/p2p/subscription"
 	"github.com/onflow/flow-go/network/p2p/tracer"
 	"github.com/onflow/flow-go/network/p2p/translator"
@@ -89,6 +90,7 @@ type FollowerServiceConfig struct {
 	bootstrapNodePublicKeys []string
 	bootstrapIdentities     flow.IdentityList // the identity list of bootstrap peers the node uses to discover other nodes
 	NetworkKey              crypto.PrivateKey // the networking key passed in by the caller when being used as a library
+	peerRecordDiscovery     bool              // whether the node discovers the staked access nodes from their signed peer records
 	baseOptions             []cmd.Option
 }
 
@@ -109,6 +111,7 @@ type FollowerServiceBuilder struct {
 
 	// components
 	LibP2PNode              p2p.LibP2PNode
+	PeerRecords             *peerrecord.Store
 	FollowerState           protocol.FollowerState
 	SyncCore                *synchronization.Core
 	FinalizationDistributor *pubsub.FinalizationDistributor
@@ -128,6 +131,8 @@ type FollowerServiceBuilder struct {
 	SyncEng     *synceng.Engine
 
 	peerID peer.ID
+	// verifiedPeerFilter allows the bootstrap peers and the verified staked access nodes, if peer record discovery is enabled
+	verifiedPeerFilter func(peer.ID) bool
 }
 
 // deriveBootstrapPeerIdentities derives the Flow Identity of the bootstrap peers from the parameters.
@@ -282,6 +287,7 @@ func (builder *FollowerServiceBuilder) buildSyncEngine() *FollowerServiceBuilder
 		sync, err := synceng.New(
 			node.Logger,
 			node.Metrics.Engine,
+			node.Metrics.SyncPeers,
 			node.Network,
 			node.Me,
 			node.Storage.Blocks,
@@ -329,6 +335,13 @@ func WithNetworkKey(key crypto.PrivateKey) FollowerOption {
 	}
 }
 
+// WithPeerRecordDiscoveryEnabled enables the discovery of the staked access nodes from their signed peer records.
+func WithPeerRecordDiscoveryEnabled(enabled bool) FollowerOption {
+	return func(config *FollowerServiceConfig) {
+		config.peerRecordDiscovery = enabled
+	}
+}
+
 func WithBaseOptions(baseOptions []cmd.Option) FollowerOption {
 	return func(config *FollowerServiceConfig) {
 		config.baseOptions = baseOptions
@@ -535,6 +548,10 @@ func (builder *FollowerServiceBuilder) Initialize() error {
 
 	builder.enqueueConnectWithStakedAN()
 
+	if builder.peerRecordDiscovery {
+		builder.enqueuePeerRecordExchange()
+	}
+
 	if builder.BaseConfig.MetricsEnabled {
 		builder.EnqueueMetricsServerInit()
 		if err := builder.RegisterBadgerMetrics(); err != nil {
@@ -570,6 +587,7 @@ func (builder *FollowerServiceBuilder) validateParams() error {
 // The factory function is later passed into the initMiddleware function to eventually instantiate the p2p.LibP2PNode instance
 // The LibP2P host is created with the following options:
 //   - DHT as client and seeded with the given bootstrap peers
+//   - DHT routing table restricted to the bootstrap peers and the verified staked access nodes, if peer record discovery is enabled
 //   - The specified bind address as the listen address
 //   - The passed in private key as the libp2p key
 //   - No connection gater
@@ -590,6 +608,21 @@ func (builder *FollowerServiceBuilder) initLibP2PFactory(networkKey crypto.Priva
 			pis = append(pis, pi)
 		}
 
+		dhtOptions := []dht.Option{
+			p2pdht.AsClient(),
+			dht.BootstrapPeers(pis...),
+		}
+		if builder.peerRecordDiscovery {
+			// the routing table only admits the bootstrap peers and the staked access nodes with a verified peer record
+			bootstrapPeers := make([]peer.ID, 0, len(pis))
+			for _, pi := range pis {
+				bootstrapPeers = append(bootstrapPeers, pi.ID)
+			}
+			builder.PeerRecords = peerrecord.NewStore(builder.IdentityProvider)
+			builder.verifiedPeerFilter = peerrecord.VerifiedPeerFilter(builder.PeerRecords, bootstrapPeers...)
+			dhtOptions = append(dhtOptions, p2pdht.RoutingTableFilter(builder.verifiedPeerFilter))
+		}
+
 		meshTracer := tracer.NewGossipSubMeshTracer(
 			builder.Logger,
 			builder.Metrics.Network,
@@ -612,8 +645,7 @@ func (builder *FollowerServiceBuilder) initLibP2PFactory(networkKey crypto.Priva
 				return p2pdht.NewDHT(ctx, h, protocols.FlowPublicDHTProtocolID(builder.SporkID),
 					builder.Logger,
 					builder.Metrics.Network,
-					p2pdht.AsClient(),
-					dht.BootstrapPeers(pis...),
+					dhtOptions...,
 				)
 			}).
 			SetStreamCreationRetryInterval(builder.UnicastCreateStreamRetryDelay).
@@ -723,6 +755,21 @@ func (builder *FollowerServiceBuilder) enqueueConnectWithStakedAN() {
 	})
 }
 
+// enqueuePeerRecordExchange enqueues the exchange of the signed peer records of the staked access nodes, which keeps
+// the node connected to verified staked access nodes, and its DHT routing table free of unverified peers.
+func (builder *FollowerServiceBuilder) enqueuePeerRecordExchange() {
+	builder.Component("peer record exchange", func(node *cmd.NodeConfig) (module.ReadyDoneAware, error) {
+		return peerrecord.NewExchange(
+			node.Logger,
+			builder.LibP2PNode,
+			builder.SporkID,
+			builder.PeerRecords,
+			peerrecord.DefaultExchangeInterval,
+			peerrecord.WithUpstreams(peerrecord.DefaultUpstreams, builder.verifiedPeerFilter),
+		)
+	})
+}
+
 // initMiddleware creates the network.Middleware implementation with the libp2p factory function, metrics, peer update
 // interval, and validators. The network.Middleware is then passed into the initNetwork function.
 func (builder *FollowerServiceBuilder) initMiddleware(nodeID flow.Identifier,
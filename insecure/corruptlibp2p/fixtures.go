@@ -39,6 +39,62 @@ func WithIHave(msgCount int, msgSize int) GossipSubCtrlOption {
 	}
 }
 
+// WithIHaveTopic adds iHave control messages of the given size and number for the given topic to the control message.
+func WithIHaveTopic(msgCount int, msgSize int, topicId string) GossipSubCtrlOption {
+	return func(msg *pubsubpb.ControlMessage) {
+		iHaves := make([]*pubsubpb.ControlIHave, msgCount)
+		for i := 0; i < msgCount; i++ {
+			topicId := topicId
+			iHaves[i] = &pubsubpb.ControlIHave{
+				TopicID:    &topicId,
+				MessageIDs: gossipSubMessageIdsFixture(msgSize),
+			}
+		}
+		msg.Ihave = iHaves
+	}
+}
+
+// WithIWant adds iWant control messages of the given size and number to the control message.
+func WithIWant(msgCount int, msgSize int) GossipSubCtrlOption {
+	return func(msg *pubsubpb.ControlMessage) {
+		iWants := make([]*pubsubpb.ControlIWant, msgCount)
+		for i := 0; i < msgCount; i++ {
+			iWants[i] = &pubsubpb.ControlIWant{
+				MessageIDs: gossipSubMessageIdsFixture(msgSize),
+			}
+		}
+		msg.Iwant = iWants
+	}
+}
+
+// WithGraft adds the given number of graft control messages for the given topic to the control message.
+func WithGraft(msgCount int, topicId string) GossipSubCtrlOption {
+	return func(msg *pubsubpb.ControlMessage) {
+		grafts := make([]*pubsubpb.ControlGraft, msgCount)
+		for i := 0; i < msgCount; i++ {
+			topicId := topicId
+			grafts[i] = &pubsubpb.ControlGraft{
+				TopicID: &topicId,
+			}
+		}
+		msg.Graft = grafts
+	}
+}
+
+// WithPrune adds the given number of prune control messages for the given topic to the control message.
+func WithPrune(msgCount int, topicId string) GossipSubCtrlOption {
+	return func(msg *pubsubpb.ControlMessage) {
+		prunes := make([]*pubsubpb.ControlPrune, msgCount)
+		for i := 0; i < msgCount; i++ {
+			topicId := topicId
+			prunes[i] = &pubsubpb.ControlPrune{
+				TopicID: &topicId,
+			}
+		}
+		msg.Prune = prunes
+	}
+}
+
 // gossipSubMessageIdFixture returns a random gossipSub message ID.
 func gossipSubMessageIdFixture() string {
 	// TODO: messageID length should be a parameter.
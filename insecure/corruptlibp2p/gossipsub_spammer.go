@@ -35,7 +35,14 @@ func NewGossipSubRouterSpammer(t *testing.T, sporkId flow.Identifier, role flow.
 // SpamIHave spams the victim with junk iHave messages.
 // ctlMessages is the list of spam messages to send to the victim node.
 func (s *GossipSubRouterSpammer) SpamIHave(t *testing.T, victim p2p.LibP2PNode, ctlMessages []pb.ControlMessage) {
+	s.SpamControlMessage(t, victim, ctlMessages)
+}
+
+// SpamControlMessage spams the victim with junk control messages.
+// ctlMessages is the list of spam messages to send to the victim node.
+func (s *GossipSubRouterSpammer) SpamControlMessage(t *testing.T, victim p2p.LibP2PNode, ctlMessages []pb.ControlMessage) {
 	for _, ctlMessage := range ctlMessages {
+		ctlMessage := ctlMessage
 		require.True(t, s.router.Get().SendControl(victim.Host().ID(), &ctlMessage))
 	}
 }
@@ -54,6 +61,16 @@ func (s *GossipSubRouterSpammer) GenerateIHaveCtlMessages(t *testing.T, msgCount
 	return iHaveCtlMsgs
 }
 
+// GenerateCtlMessages generates the given number of control messages, each built from the given options.
+// The messages are generated before they are sent so the test can prepare to expect receiving them.
+func (s *GossipSubRouterSpammer) GenerateCtlMessages(msgCount int, opts ...GossipSubCtrlOption) []pb.ControlMessage {
+	ctlMsgs := make([]pb.ControlMessage, msgCount)
+	for i := 0; i < msgCount; i++ {
+		ctlMsgs[i] = *GossipSubCtrlFixture(opts...)
+	}
+	return ctlMsgs
+}
+
 // Start starts the spammer and waits until it is fully initialized before returning.
 func (s *GossipSubRouterSpammer) Start(t *testing.T) {
 	require.Eventuallyf(t, func() bool {
@@ -113,5 +130,3 @@ func (a *atomicRouter) Get() *corrupt.GossipSubRouter {
 	defer a.mu.Unlock()
 	return a.router
 }
-
-// TODO: SpamIWant, SpamGraft, SpamPrune.
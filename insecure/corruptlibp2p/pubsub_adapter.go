@@ -104,6 +104,11 @@ func (c *CorruptGossipSubAdapter) ListPeers(topic string) []peer.ID {
 	return c.gossipSub.ListPeers(topic)
 }
 
+// GetLocalMeshPeers always returns an empty list, as the corrupt gossipsub adapter does not support pubsub tracers.
+func (c *CorruptGossipSubAdapter) GetLocalMeshPeers(_ string) []peer.ID {
+	return []peer.ID{}
+}
+
 func NewCorruptGossipSubAdapter(ctx context.Context, logger zerolog.Logger, h host.Host, cfg p2p.PubSubAdapterConfig) (p2p.PubSubAdapter, *corrupt.GossipSubRouter, error) {
 	gossipSubConfig, ok := cfg.(*CorruptPubSubAdapterConfig)
 	if !ok {
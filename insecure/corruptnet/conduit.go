@@ -64,6 +64,50 @@ func (c *Conduit) Multicast(event interface{}, num uint, targetIDs ...flow.Ident
 	return nil
 }
 
+// Request sends the request as a unicast event to the controller of this conduit (i.e., its factory) to handle.
+// Since the controller dispatches the events asynchronously (if at all), the corrupted conduit does not await the
+// response: it blocks until the context is done (or network.DefaultRequestTimeout elapsed if the context has no
+// deadline) and returns an error wrapping the context error. The response, if any, is delivered to the engine like
+// any other message.
+func (c *Conduit) Request(ctx context.Context, request network.RequestMessage, targetID flow.Identifier) (network.ResponseMessage, error) {
+	if c.ctx.Err() != nil {
+		return nil, fmt.Errorf("conduit for channel %s closed", c.channel)
+	}
+
+	err := c.egressController.HandleOutgoingEvent(request, c.channel, insecure.Protocol_UNICAST, 0, targetID)
+	if err != nil {
+		return nil, fmt.Errorf("factory could not handle the request event: %w", err)
+	}
+
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, network.DefaultRequestTimeout)
+		defer cancel()
+	}
+	<-ctx.Done()
+
+	return nil, fmt.Errorf("corrupted conduit does not await responses: %w", ctx.Err())
+}
+
+// Respond sends the response as a unicast event to the controller of this conduit (i.e., its factory) to handle.
+func (c *Conduit) Respond(response network.ResponseMessage, targetID flow.Identifier) error {
+	if c.ctx.Err() != nil {
+		return fmt.Errorf("conduit for channel %s closed", c.channel)
+	}
+
+	err := c.egressController.HandleOutgoingEvent(response, c.channel, insecure.Protocol_UNICAST, 0, targetID)
+	if err != nil {
+		return fmt.Errorf("factory could not handle the response event: %w", err)
+	}
+
+	return nil
+}
+
+// ReportMisbehavior reports the misbehavior of a node on sending a message to the current node that appears valid
+// based on the networking layer but is considered invalid by the current node based on the Flow protocol.
+// The corrupted conduit does not penalize any node, hence the report is dropped.
+func (c *Conduit) ReportMisbehavior(_ network.MisbehaviorReport) {}
+
 // Close informs the conduit controller that the engine is not going to use this conduit anymore.
 func (c *Conduit) Close() error {
 	if c.ctx.Err() != nil {
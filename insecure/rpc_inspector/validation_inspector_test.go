@@ -0,0 +1,195 @@
+package rpc_inspector
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/insecure/corruptlibp2p"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/module/irrecoverable"
+	"github.com/onflow/flow-go/module/metrics"
+	"github.com/onflow/flow-go/network/channels"
+	"github.com/onflow/flow-go/network/p2p"
+	"github.com/onflow/flow-go/network/p2p/inspector/validation"
+	mockp2p "github.com/onflow/flow-go/network/p2p/mock"
+	gossipsubbuilder "github.com/onflow/flow-go/network/p2p/p2pbuilder/gossipsub"
+	p2ptest "github.com/onflow/flow-go/network/p2p/test"
+	validator "github.com/onflow/flow-go/network/validator/pubsub"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+// TestValidationInspector_DiscardThreshold ensures that when RPCs are received with more control messages than the
+// discard threshold, the victim rejects them and reports the spammer to the consumers of the inspector suite.
+func TestValidationInspector_DiscardThreshold(t *testing.T) {
+	t.Parallel()
+	role := flow.RoleConsensus
+	sporkID := unittest.IdentifierFixture()
+	spammer := corruptlibp2p.NewGossipSubRouterSpammer(t, sporkID, role)
+
+	cfg := validation.DefaultControlMsgValidationInspectorConfig()
+	cfg.GraftDiscardThreshold = 10
+	cfg.PruneDiscardThreshold = 10
+	// number of RPCs sent per control message type.
+	const spamCount = 5
+	// number of control messages per RPC, above the discard threshold.
+	const ctlMsgCount = 20
+
+	topic := channels.TopicFromChannel(channels.PushBlocks, sporkID).String()
+	allReported := sync.WaitGroup{}
+	allReported.Add(2 * spamCount)
+	consumer := mockp2p.NewGossipSubInvalidControlMessageNotificationConsumer(t)
+	consumer.On("OnInvalidControlMessageNotification", mock.Anything).Run(func(args mock.Arguments) {
+		notification := args.Get(0).(*p2p.InvalidControlMessageNotification)
+		require.Equal(t, spammer.SpammerNode.Host().ID(), notification.PeerID)
+		require.Equal(t, uint64(ctlMsgCount), notification.Count)
+		require.True(t, validation.IsErrDiscardThreshold(notification.Err))
+		require.Contains(t, []p2p.ControlMessageType{p2p.CtrlMsgGraft, p2p.CtrlMsgPrune}, notification.MsgType)
+		allReported.Done()
+	}).Return()
+
+	victimNode := victimNodeFixture(t, sporkID, role, cfg, consumer)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	signalerCtx := irrecoverable.NewMockSignalerContext(t, ctx)
+	nodes := []p2p.LibP2PNode{victimNode, spammer.SpammerNode}
+	p2ptest.StartNodes(t, signalerCtx, nodes, 5*time.Second)
+	defer p2ptest.StopNodes(t, nodes, cancel, 5*time.Second)
+	spammer.Start(t)
+
+	// the spammer circumvents the normal pubsub subscription mechanism, hence a prior connection is needed.
+	p2ptest.EnsureConnected(t, ctx, nodes)
+
+	spammer.SpamControlMessage(t, victimNode, spammer.GenerateCtlMessages(spamCount, corruptlibp2p.WithGraft(ctlMsgCount, topic)))
+	spammer.SpamControlMessage(t, victimNode, spammer.GenerateCtlMessages(spamCount, corruptlibp2p.WithPrune(ctlMsgCount, topic)))
+
+	unittest.RequireReturnsBefore(t, allReported.Wait, 2*time.Second, "victim did not report all spam control messages")
+}
+
+// TestValidationInspector_InvalidTopic ensures that when RPCs are received with control messages for topics that
+// are not valid Flow topics, the victim rejects them and reports the spammer to the consumers of the inspector suite.
+func TestValidationInspector_InvalidTopic(t *testing.T) {
+	t.Parallel()
+	role := flow.RoleConsensus
+	sporkID := unittest.IdentifierFixture()
+	spammer := corruptlibp2p.NewGossipSubRouterSpammer(t, sporkID, role)
+
+	// number of RPCs sent per control message type.
+	const spamCount = 5
+	unknownTopic := channels.Topic("unknown-topic/" + sporkID.String()).String()
+	wrongSporkTopic := channels.TopicFromChannel(channels.PushBlocks, unittest.IdentifierFixture()).String()
+
+	allReported := sync.WaitGroup{}
+	allReported.Add(2 * spamCount)
+	consumer := mockp2p.NewGossipSubInvalidControlMessageNotificationConsumer(t)
+	consumer.On("OnInvalidControlMessageNotification", mock.Anything).Run(func(args mock.Arguments) {
+		notification := args.Get(0).(*p2p.InvalidControlMessageNotification)
+		require.Equal(t, spammer.SpammerNode.Host().ID(), notification.PeerID)
+		require.True(t, validation.IsErrInvalidTopic(notification.Err))
+		allReported.Done()
+	}).Return()
+
+	cfg := validation.DefaultControlMsgValidationInspectorConfig()
+	cfg.UnsubscribedTopicTolerance = 0
+	victimNode := victimNodeFixture(t, sporkID, role, cfg, consumer)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	signalerCtx := irrecoverable.NewMockSignalerContext(t, ctx)
+	nodes := []p2p.LibP2PNode{victimNode, spammer.SpammerNode}
+	p2ptest.StartNodes(t, signalerCtx, nodes, 5*time.Second)
+	defer p2ptest.StopNodes(t, nodes, cancel, 5*time.Second)
+	spammer.Start(t)
+
+	p2ptest.EnsureConnected(t, ctx, nodes)
+
+	spammer.SpamControlMessage(t, victimNode, spammer.GenerateCtlMessages(spamCount, corruptlibp2p.WithGraft(1, unknownTopic)))
+	spammer.SpamControlMessage(t, victimNode, spammer.GenerateCtlMessages(spamCount, corruptlibp2p.WithPrune(1, wrongSporkTopic)))
+
+	unittest.RequireReturnsBefore(t, allReported.Wait, 2*time.Second, "victim did not report all spam control messages")
+}
+
+// TestValidationInspector_UnsubscribedTopic ensures that the victim validates GRAFT and IHAVE messages against the topics
+// it is subscribed to, as tracked by the libp2p node: messages for subscribed topics are accepted, while messages for
+// valid Flow topics the victim is not subscribed to are dropped and, with no tolerance, the spammer is reported. The subscriptions are
+// looked up from the event loop of the GossipSub router, which must not block on the router itself.
+func TestValidationInspector_UnsubscribedTopic(t *testing.T) {
+	t.Parallel()
+	role := flow.RoleConsensus
+	sporkID := unittest.IdentifierFixture()
+	spammer := corruptlibp2p.NewGossipSubRouterSpammer(t, sporkID, role)
+
+	// number of RPCs sent per control message type.
+	const spamCount = 5
+	subscribedTopic := channels.TopicFromChannel(channels.PushBlocks, sporkID)
+	unsubscribedTopic := channels.TopicFromChannel(channels.RequestCollections, sporkID).String()
+
+	allReported := sync.WaitGroup{}
+	allReported.Add(2 * spamCount)
+	consumer := mockp2p.NewGossipSubInvalidControlMessageNotificationConsumer(t)
+	consumer.On("OnInvalidControlMessageNotification", mock.Anything).Run(func(args mock.Arguments) {
+		notification := args.Get(0).(*p2p.InvalidControlMessageNotification)
+		require.Equal(t, spammer.SpammerNode.Host().ID(), notification.PeerID)
+		require.True(t, validation.IsErrUnsubscribedTopic(notification.Err))
+		require.Contains(t, []p2p.ControlMessageType{p2p.CtrlMsgGraft, p2p.CtrlMsgIHave}, notification.MsgType)
+		allReported.Done()
+	}).Return()
+
+	victimNode := victimNodeFixture(t, sporkID, role, validation.DefaultControlMsgValidationInspectorConfig(), consumer)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	signalerCtx := irrecoverable.NewMockSignalerContext(t, ctx)
+	nodes := []p2p.LibP2PNode{victimNode, spammer.SpammerNode}
+	p2ptest.StartNodes(t, signalerCtx, nodes, 5*time.Second)
+	defer p2ptest.StopNodes(t, nodes, cancel, 5*time.Second)
+	spammer.Start(t)
+
+	_, err := victimNode.Subscribe(subscribedTopic, validator.TopicValidator(unittest.Logger(), unittest.AllowAllPeerFilter()))
+	require.NoError(t, err)
+	require.Equal(t, []channels.Topic{subscribedTopic}, victimNode.GetSubscribedTopics())
+
+	p2ptest.EnsureConnected(t, ctx, nodes)
+
+	// control messages for the subscribed topic are accepted, i.e., not reported to the consumer.
+	spammer.SpamControlMessage(t, victimNode, spammer.GenerateCtlMessages(spamCount, corruptlibp2p.WithGraft(1, subscribedTopic.String())))
+	spammer.SpamControlMessage(t, victimNode, spammer.GenerateCtlMessages(spamCount, corruptlibp2p.WithIHaveTopic(1, 10, subscribedTopic.String())))
+
+	// control messages for topics the victim is not subscribed to are dropped, and reported beyond the tolerance.
+	spammer.SpamControlMessage(t, victimNode, spammer.GenerateCtlMessages(spamCount, corruptlibp2p.WithGraft(1, unsubscribedTopic)))
+	spammer.SpamControlMessage(t, victimNode, spammer.GenerateCtlMessages(spamCount, corruptlibp2p.WithIHaveTopic(1, 10, unsubscribedTopic)))
+
+	unittest.RequireReturnsBefore(t, allReported.Wait, 2*time.Second, "victim did not report all spam control messages")
+
+	// the event loop of the victim's router is not blocked by the inspection, the victim can still unsubscribe.
+	unittest.RequireReturnsBefore(t, func() {
+		require.NoError(t, victimNode.UnSubscribe(subscribedTopic))
+	}, time.Second, "victim could not unsubscribe")
+	require.Empty(t, victimNode.GetSubscribedTopics())
+}
+
+// victimNodeFixture returns a libp2p node running the GossipSub rpc inspector suite with the given validation config,
+// with the given consumer subscribed to the notifications of the suite.
+func victimNodeFixture(
+	t *testing.T,
+	sporkID flow.Identifier,
+	role flow.Role,
+	cfg *validation.ControlMsgValidationInspectorConfig,
+	consumer p2p.GossipSubInvalidControlMessageNotificationConsumer,
+) p2p.LibP2PNode {
+	logger := unittest.Logger()
+	suite, err := gossipsubbuilder.BuildGossipSubRPCInspectorSuite(logger, sporkID, cfg, metrics.NewNoopCollector())
+	require.NoError(t, err)
+	suite.AddInvalidControlMessageConsumer(consumer)
+
+	victimNode, _ := p2ptest.NodeFixture(
+		t,
+		sporkID,
+		t.Name(),
+		p2ptest.WithRole(role),
+		p2ptest.WithGossipSubRPCInspectorSuite(suite),
+	)
+	return victimNode
+}
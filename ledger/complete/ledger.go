@@ -70,7 +70,8 @@ func NewLedger(
 	wal.PauseRecord()
 	defer wal.UnpauseRecord()
 
-	err = wal.ReplayOnForest(forest)
+	// the node is starting up, so a record torn by a crash is discarded
+	err = wal.ReplayOnForestAndRepair(forest)
 	if err != nil {
 		return nil, fmt.Errorf("cannot restore LedgerWAL: %w", err)
 	}
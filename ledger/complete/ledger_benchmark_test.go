@@ -401,3 +401,63 @@ func BenchmarkTrieProve(b *testing.B) {
 	}
 	b.StopTimer()
 }
+
+// BenchmarkLedgerStartup benchmarks the startup of a ledger, which replays the WAL segments
+// written by a previous instance. Use it to track regressions of the execution node startup time.
+func BenchmarkLedgerStartup(b *testing.B) {
+	const (
+		steps              = 50
+		numInsPerStep      = 1000
+		keyNumberOfParts   = 10
+		keyPartMinByteSize = 1
+		keyPartMaxByteSize = 100
+		valueMaxByteSize   = 32
+		checkpointDistance = math.MaxInt // A large number to prevent checkpoint creation.
+		checkpointsToKeep  = 1
+	)
+
+	rand.Seed(time.Now().UnixNano())
+
+	dir := b.TempDir()
+
+	// populate the WAL without creating any checkpoint
+	diskWal, err := wal.NewDiskWAL(zerolog.Nop(), nil, metrics.NewNoopCollector(), dir, steps+1, pathfinder.PathByteSize, wal.SegmentSize)
+	require.NoError(b, err)
+
+	led, err := complete.NewLedger(diskWal, steps+1, &metrics.NoopCollector{}, zerolog.Logger{}, complete.DefaultPathFinderVersion)
+	require.NoError(b, err)
+
+	compactor, err := complete.NewCompactor(led, diskWal, zerolog.Nop(), uint(steps+1), checkpointDistance, checkpointsToKeep, atomic.NewBool(false))
+	require.NoError(b, err)
+	<-compactor.Ready()
+
+	state := led.InitialState()
+	for i := 0; i < steps; i++ {
+		keys := testutils.RandomUniqueKeys(numInsPerStep, keyNumberOfParts, keyPartMinByteSize, keyPartMaxByteSize)
+		values := testutils.RandomValues(numInsPerStep, 1, valueMaxByteSize)
+
+		update, err := ledger.NewUpdate(state, keys, values)
+		require.NoError(b, err)
+
+		state, _, err = led.Set(update)
+		require.NoError(b, err)
+	}
+	<-led.Done()
+	<-compactor.Done()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		diskWal, err := wal.NewDiskWAL(zerolog.Nop(), nil, metrics.NewNoopCollector(), dir, steps+1, pathfinder.PathByteSize, wal.SegmentSize)
+		require.NoError(b, err)
+
+		// creating the ledger replays all WAL segments
+		led, err := complete.NewLedger(diskWal, steps+1, &metrics.NoopCollector{}, zerolog.Logger{}, complete.DefaultPathFinderVersion)
+		require.NoError(b, err)
+
+		b.StopTimer()
+		require.True(b, led.HasState(state))
+		<-led.Done()
+		<-diskWal.Done()
+		b.StartTimer()
+	}
+}
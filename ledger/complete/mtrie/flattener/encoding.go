@@ -31,6 +31,9 @@ const (
 	encodedTrieSize = encNodeIndexSize + encRegCountSize + encRegSizeSize + encHashSize
 )
 
+// EncodedTrieSize is the size of a trie encoded by EncodeTrie.
+const EncodedTrieSize = encodedTrieSize
+
 const payloadEncodingVersion = 1
 
 // encodeLeafNode encodes leaf node in the following format:
@@ -268,6 +271,59 @@ func ReadNode(reader io.Reader, scratch []byte, getNode func(nodeIndex uint64) (
 	return n, nil
 }
 
+// ReadEncodedNode reads a node serialized by EncodeNode from reader without decoding it,
+// and appends the serialized node to buf. It returns the extended buffer and whether the
+// node is a leaf node. The appended node can be decoded with ReadNode. Leaf nodes don't
+// reference other nodes, so they can be decoded independently of the nodes read before.
+func ReadEncodedNode(reader io.Reader, buf []byte) ([]byte, bool, error) {
+	// fixLengthSize is the size of shared data of leaf node and interim node
+	const fixLengthSize = encNodeTypeSize + encHeightSize + encHashSize
+
+	start := len(buf)
+	buf, err := readAppend(reader, buf, fixLengthSize)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to read fixed-length part of serialized node: %w", err)
+	}
+
+	switch buf[start] {
+	case byte(leafNodeType):
+		buf, err = readAppend(reader, buf, encPathSize+encPayloadLengthSize)
+		if err != nil {
+			return nil, false, fmt.Errorf("failed to read path and payload length of serialized node: %w", err)
+		}
+		size := binary.BigEndian.Uint32(buf[len(buf)-encPayloadLengthSize:])
+		buf, err = readAppend(reader, buf, int(size))
+		if err != nil {
+			return nil, false, fmt.Errorf("failed to read payload of serialized node: %w", err)
+		}
+		return buf, true, nil
+	case byte(interimNodeType):
+		buf, err = readAppend(reader, buf, encNodeIndexSize*2)
+		if err != nil {
+			return nil, false, fmt.Errorf("failed to read child index of serialized node: %w", err)
+		}
+		return buf, false, nil
+	default:
+		return nil, false, fmt.Errorf("failed to decode node type %d", buf[start])
+	}
+}
+
+// readAppend reads exactly n bytes from reader and appends them to buf.
+func readAppend(reader io.Reader, buf []byte, n int) ([]byte, error) {
+	start := len(buf)
+	if cap(buf)-start < n {
+		grown := make([]byte, start, 2*cap(buf)+n)
+		copy(grown, buf)
+		buf = grown
+	}
+	buf = buf[:start+n]
+	_, err := io.ReadFull(reader, buf[start:])
+	if err != nil {
+		return nil, err
+	}
+	return buf, nil
+}
+
 // EncodeTrie encodes trie in the following format:
 // - root node index (8 byte)
 // - allocated reg count (8 byte)
@@ -305,6 +361,27 @@ func EncodeTrie(trie *trie.MTrie, rootIndex uint64, scratch []byte) []byte {
 	return buf[:pos]
 }
 
+// ReadTrieRootHash reads a trie encoded by EncodeTrie from reader and returns its
+// root hash, without reconstructing the trie.
+func ReadTrieRootHash(reader io.Reader, scratch []byte) (ledger.RootHash, error) {
+	if len(scratch) < encodedTrieSize {
+		scratch = make([]byte, encodedTrieSize)
+	}
+
+	_, err := io.ReadFull(reader, scratch[:encodedTrieSize])
+	if err != nil {
+		return ledger.RootHash{}, fmt.Errorf("failed to read serialized trie: %w", err)
+	}
+
+	// skip root node index, reg count and reg size
+	pos := encNodeIndexSize + encRegCountSize + encRegSizeSize
+	rootHash, err := hash.ToHash(scratch[pos : pos+encHashSize])
+	if err != nil {
+		return ledger.RootHash{}, fmt.Errorf("failed to decode hash of serialized trie: %w", err)
+	}
+	return ledger.RootHash(rootHash), nil
+}
+
 // ReadTrie reconstructs a trie from data read from reader.
 func ReadTrie(reader io.Reader, scratch []byte, getNode func(nodeIndex uint64) (*node.Node, error)) (*trie.MTrie, error) {
 
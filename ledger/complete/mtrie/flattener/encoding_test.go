@@ -372,3 +372,55 @@ func TestTrieEncodingDecoding(t *testing.T) {
 		})
 	}
 }
+
+func TestReadEncodedNode(t *testing.T) {
+	path1 := testutils.PathByUint8(0)
+	payload1 := testutils.LightPayload8('A', 'a')
+	hashValue1 := hash.Hash([32]byte{1, 1, 1})
+	leafNode1 := node.NewNode(255, nil, nil, ledger.Path(path1), payload1, hashValue1)
+
+	path2 := testutils.PathByUint8(1)
+	payload2 := testutils.LightPayload8('B', 'b')
+	hashValue2 := hash.Hash([32]byte{2, 2, 2})
+	leafNode2 := node.NewNode(255, nil, nil, ledger.Path(path2), payload2, hashValue2)
+
+	hashValue3 := hash.Hash([32]byte{3, 3, 3})
+	interimNode := node.NewNode(256, leafNode1, leafNode2, ledger.DummyPath, nil, hashValue3)
+
+	var encoded []byte
+	encoded = append(encoded, flattener.EncodeNode(leafNode1, 0, 0, nil)...)
+	encoded = append(encoded, flattener.EncodeNode(leafNode2, 0, 0, nil)...)
+	encoded = append(encoded, flattener.EncodeNode(interimNode, 1, 2, nil)...)
+
+	// read all nodes into a small buffer, so that it has to grow
+	reader := bytes.NewReader(encoded)
+	buf := make([]byte, 0, 8)
+	var offsets []int
+	var leaves []bool
+	for i := 0; i < 3; i++ {
+		offsets = append(offsets, len(buf))
+		var isLeaf bool
+		var err error
+		buf, isLeaf, err = flattener.ReadEncodedNode(reader, buf)
+		require.NoError(t, err)
+		leaves = append(leaves, isLeaf)
+	}
+	offsets = append(offsets, len(buf))
+	assert.Equal(t, 0, reader.Len())
+	assert.Equal(t, encoded, buf)
+	assert.Equal(t, []bool{true, true, false}, leaves)
+
+	// the read nodes decode to the original nodes
+	nodes := []*node.Node{leafNode1, leafNode2}
+	for i, expected := range []*node.Node{leafNode1, leafNode2, interimNode} {
+		decoded, err := flattener.ReadNode(bytes.NewReader(buf[offsets[i]:offsets[i+1]]), nil, func(nodeIndex uint64) (*node.Node, error) {
+			return nodes[nodeIndex-1], nil
+		})
+		require.NoError(t, err)
+		assert.Equal(t, expected, decoded)
+	}
+
+	// truncated nodes can't be read
+	_, _, err := flattener.ReadEncodedNode(bytes.NewReader(encoded[:offsets[1]-1]), nil)
+	require.Error(t, err)
+}
@@ -2,15 +2,18 @@ package wal
 
 import (
 	"bufio"
+	"bytes"
 	"errors"
 	"fmt"
 	"io"
 	"os"
 	"path"
 	"path/filepath"
+	"runtime"
 
 	"github.com/rs/zerolog"
 
+	"github.com/onflow/flow-go/ledger"
 	"github.com/onflow/flow-go/ledger/complete/mtrie/flattener"
 	"github.com/onflow/flow-go/ledger/complete/mtrie/node"
 	"github.com/onflow/flow-go/ledger/complete/mtrie/trie"
@@ -100,6 +103,56 @@ func OpenAndReadCheckpointV6(dir string, fileName string, logger *zerolog.Logger
 	return readCheckpointV6(f, logger)
 }
 
+// ReadTriesRootHash reads the root hashes of the tries in the V6 checkpoint with the given
+// file name in dir, in the order they were checkpointed, without loading the tries. The
+// root hashes are stored at the end of the top level tries file, which is not validated
+// against its checksum.
+// any error returned are exceptions
+func ReadTriesRootHash(dir string, fileName string) (
+	rootHashes []ledger.RootHash,
+	errToReturn error,
+) {
+	filepath, _ := filePathTopTries(dir, fileName)
+	file, err := os.Open(filepath)
+	if err != nil {
+		return nil, fmt.Errorf("could not open file %v: %w", filepath, err)
+	}
+	defer func(file *os.File) {
+		errToReturn = closeAndMergeError(file, errToReturn)
+	}(file)
+
+	err = validateFileHeader(MagicBytesCheckpointToptrie, VersionV6, file)
+	if err != nil {
+		return nil, err
+	}
+
+	_, triesCount, _, err := readTopTriesFooter(file)
+	if err != nil {
+		return nil, fmt.Errorf("could not read top tries footer: %w", err)
+	}
+
+	// the tries are encoded right before the footer
+	const footerOffset = encNodeCountSize + encTrieCountSize + crc32SumSize
+	triesOffset := int64(triesCount)*flattener.EncodedTrieSize + footerOffset
+	_, err = file.Seek(-triesOffset, io.SeekEnd)
+	if err != nil {
+		return nil, fmt.Errorf("could not seek to tries: %w", err)
+	}
+
+	reader := bufio.NewReader(file)
+	scratch := make([]byte, flattener.EncodedTrieSize)
+	rootHashes = make([]ledger.RootHash, 0, triesCount)
+	for i := uint16(0); i < triesCount; i++ {
+		rootHash, err := flattener.ReadTrieRootHash(reader, scratch)
+		if err != nil {
+			return nil, fmt.Errorf("cannot read root hash of trie at index %d: %w", i, err)
+		}
+		rootHashes = append(rootHashes, rootHash)
+	}
+
+	return rootHashes, nil
+}
+
 func filePathCheckpointHeader(dir string, fileName string) string {
 	return path.Join(dir, fileName)
 }
@@ -278,6 +331,8 @@ type resultReadSubTrie struct {
 	Err   error
 }
 
+// readSubTriesConcurrently reads the subtrie files of a checkpoint concurrently,
+// and returns the nodes of each subtrie in the same order as their indices.
 func readSubTriesConcurrently(dir string, fileName string, subtrieChecksums []uint32, logger *zerolog.Logger) ([][]*node.Node, error) {
 
 	numOfSubTries := len(subtrieChecksums)
@@ -298,10 +353,15 @@ func readSubTriesConcurrently(dir string, fileName string, subtrieChecksums []ui
 
 	// TODO: make nWorker configable
 	nWorker := numOfSubTries // use as many worker as the jobs to read subtries concurrently
+
+	// the leaf nodes of each subtrie file are decoded by additional workers, so that all cores
+	// are used even if there are more cores than subtrie files
+	decodeWorkers := (runtime.NumCPU() + numOfSubTries - 1) / numOfSubTries
+
 	for i := 0; i < nWorker; i++ {
 		go func() {
 			for job := range jobs {
-				nodes, err := readCheckpointSubTrie(dir, fileName, job.Index, job.Checksum, logger)
+				nodes, err := readCheckpointSubTrieConcurrently(dir, fileName, job.Index, job.Checksum, decodeWorkers, logger)
 				job.Result <- &resultReadSubTrie{
 					Nodes: nodes,
 					Err:   err,
@@ -320,6 +380,7 @@ func readSubTriesConcurrently(dir string, fileName string, subtrieChecksums []ui
 		}
 
 		nodesGroups = append(nodesGroups, result.Nodes)
+		logger.Info().Msgf("finished reading %v/%v subtrie files", i+1, numOfSubTries)
 	}
 
 	return nodesGroups, nil
@@ -333,6 +394,15 @@ func readSubTriesConcurrently(dir string, fileName string, subtrieChecksums []ui
 func readCheckpointSubTrie(dir string, fileName string, index int, checksum uint32, logger *zerolog.Logger) (
 	subtrieRootNodes []*node.Node,
 	errToReturn error,
+) {
+	return readCheckpointSubTrieConcurrently(dir, fileName, index, checksum, 1, logger)
+}
+
+// readCheckpointSubTrieConcurrently reads a subtrie file like readCheckpointSubTrie, decoding
+// the leaf nodes with nWorker workers while the nodes are read from the file.
+func readCheckpointSubTrieConcurrently(dir string, fileName string, index int, checksum uint32, nWorker int, logger *zerolog.Logger) (
+	subtrieRootNodes []*node.Node,
+	errToReturn error,
 ) {
 	filepath, _, err := filePathSubTries(dir, fileName, index)
 	if err != nil {
@@ -386,19 +456,9 @@ func readCheckpointSubTrie(dir string, fileName string, index int, checksum uint
 	scratch := make([]byte, 1024*4) // must not be less than 1024
 	logging := logProgress(fmt.Sprintf("reading %v-th sub trie roots", index), int(nodesCount), logger)
 
-	nodes := make([]*node.Node, nodesCount+1) //+1 for 0 index meaning nil
-	for i := uint64(1); i <= nodesCount; i++ {
-		node, err := flattener.ReadNode(reader, scratch, func(nodeIndex uint64) (*node.Node, error) {
-			if nodeIndex >= i {
-				return nil, fmt.Errorf("sequence of serialized nodes does not satisfy Descendents-First-Relationship")
-			}
-			return nodes[nodeIndex], nil
-		})
-		if err != nil {
-			return nil, fmt.Errorf("cannot read node %d: %w", i, err)
-		}
-		nodes[i] = node
-		logging(i)
+	nodes, err := readSubTrieNodes(reader, nodesCount, nWorker, logging)
+	if err != nil {
+		return nil, err
 	}
 
 	// read footer and discard, since we only care about checksum
@@ -431,6 +491,144 @@ func readCheckpointSubTrie(dir string, fileName string, index int, checksum uint
 	return nodes[1:], nil
 }
 
+// subtrieNodeBatchSize is the number of serialized subtrie nodes read from a subtrie file
+// before their leaf nodes are decoded by a worker.
+const subtrieNodeBatchSize = 1024
+
+// subtrieNodeBatch is a batch of serialized nodes of a subtrie file.
+type subtrieNodeBatch struct {
+	first   uint64 // index of the first node in the batch
+	data    []byte // serialized nodes
+	offsets []int  // start offsets of the serialized nodes in data, followed by the end offset
+	leaves  []bool // whether each node is a leaf node
+	decoded []*node.Node
+	err     error
+	done    chan struct{} // closed once the leaf nodes of the batch were decoded
+}
+
+// readSubTrieNodes reads nodesCount serialized nodes from the reader and returns them, with a nil
+// node at index 0. The nodes are read sequentially in batches, and the leaf nodes of each batch are
+// decoded by one of nWorker workers. As interim nodes reference the nodes before them, they are
+// decoded in order by the calling goroutine, once the leaf nodes of their batch were decoded. All
+// nodes have been read from the reader once readSubTrieNodes returns without error.
+func readSubTrieNodes(reader io.Reader, nodesCount uint64, nWorker int, logging func(uint64)) ([]*node.Node, error) {
+	if nWorker < 1 {
+		nWorker = 1
+	}
+
+	done := make(chan struct{})
+	defer close(done)
+
+	// bound the number of batches being decoded or waiting to be linked
+	jobs := make(chan *subtrieNodeBatch, nWorker)
+	batches := make(chan *subtrieNodeBatch, nWorker*2)
+
+	for w := 0; w < nWorker; w++ {
+		go func() {
+			scratch := make([]byte, 1024*4)
+			for batch := range jobs {
+				for i, isLeaf := range batch.leaves {
+					if !isLeaf {
+						continue
+					}
+					data := batch.data[batch.offsets[i]:batch.offsets[i+1]]
+					leaf, err := flattener.ReadNode(bytes.NewReader(data), scratch, nil)
+					if err != nil {
+						batch.err = fmt.Errorf("cannot read node %d: %w", batch.first+uint64(i), err)
+						break
+					}
+					batch.decoded[i] = leaf
+				}
+				close(batch.done)
+			}
+		}()
+	}
+
+	go func() {
+		defer close(batches)
+		defer close(jobs)
+
+		for first := uint64(1); first <= nodesCount; first += subtrieNodeBatchSize {
+			count := nodesCount - first + 1
+			if count > subtrieNodeBatchSize {
+				count = subtrieNodeBatchSize
+			}
+			batch := &subtrieNodeBatch{
+				first:   first,
+				offsets: make([]int, 0, count+1),
+				leaves:  make([]bool, 0, count),
+				decoded: make([]*node.Node, count),
+				done:    make(chan struct{}),
+			}
+			for i := uint64(0); i < count; i++ {
+				batch.offsets = append(batch.offsets, len(batch.data))
+				var isLeaf bool
+				var err error
+				batch.data, isLeaf, err = flattener.ReadEncodedNode(reader, batch.data)
+				if err != nil {
+					// delivered to the calling goroutine as an undecoded batch
+					batch.err = fmt.Errorf("cannot read node %d: %w", first+i, err)
+					batch.leaves = nil
+					close(batch.done)
+					select {
+					case <-done:
+					case batches <- batch:
+					}
+					return
+				}
+				batch.leaves = append(batch.leaves, isLeaf)
+			}
+			batch.offsets = append(batch.offsets, len(batch.data))
+
+			select {
+			case <-done:
+				return
+			case batches <- batch:
+			}
+			select {
+			case <-done:
+				return
+			case jobs <- batch:
+			}
+		}
+	}()
+
+	scratch := make([]byte, 1024*4)
+	nodes := make([]*node.Node, nodesCount+1) //+1 for 0 index meaning nil
+	read := uint64(0)
+	for batch := range batches {
+		<-batch.done
+		if batch.err != nil {
+			return nil, batch.err
+		}
+		for i, isLeaf := range batch.leaves {
+			index := batch.first + uint64(i)
+			if isLeaf {
+				nodes[index] = batch.decoded[i]
+			} else {
+				data := batch.data[batch.offsets[i]:batch.offsets[i+1]]
+				interim, err := flattener.ReadNode(bytes.NewReader(data), scratch, func(nodeIndex uint64) (*node.Node, error) {
+					if nodeIndex >= index {
+						return nil, fmt.Errorf("sequence of serialized nodes does not satisfy Descendents-First-Relationship")
+					}
+					return nodes[nodeIndex], nil
+				})
+				if err != nil {
+					return nil, fmt.Errorf("cannot read node %d: %w", index, err)
+				}
+				nodes[index] = interim
+			}
+			logging(index)
+		}
+		read += uint64(len(batch.leaves))
+	}
+	if read != nodesCount {
+		return nil, fmt.Errorf("read %d nodes, but expected %d nodes", read, nodesCount)
+	}
+
+	return nodes, nil
+}
+
 func readSubTriesFooter(f *os.File) (uint64, uint32, error) {
 	const footerSize = encNodeCountSize // footer doesn't include crc32 sum
 	const footerOffset = footerSize + crc32SumSize
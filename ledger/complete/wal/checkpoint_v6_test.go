@@ -19,6 +19,7 @@ import (
 	"github.com/onflow/flow-go/ledger"
 	"github.com/onflow/flow-go/ledger/common/hash"
 	"github.com/onflow/flow-go/ledger/common/testutils"
+	"github.com/onflow/flow-go/ledger/complete/mtrie/flattener"
 	"github.com/onflow/flow-go/ledger/complete/mtrie/node"
 	"github.com/onflow/flow-go/ledger/complete/mtrie/trie"
 	"github.com/onflow/flow-go/utils/unittest"
@@ -191,6 +192,39 @@ func TestEncodeSubTrie(t *testing.T) {
 	}
 }
 
+// TestReadSubTrieNodesConcurrently tests that nodes spanning several batches are read and linked in order,
+// independently of the number of workers decoding the leaf nodes.
+func TestReadSubTrieNodesConcurrently(t *testing.T) {
+	paths, payloads := randNPathPayloads(3000)
+	tr, _, err := trie.NewTrieWithUpdatedRegisters(trie.NewEmptyMTrie(), paths, payloads, false)
+	require.NoError(t, err)
+
+	// serialize the nodes in descendants-first order, as in subtrie files
+	var encoded []byte
+	indices := make(map[*node.Node]uint64)
+	for itr := flattener.NewNodeIterator(tr.RootNode()); itr.Next(); {
+		n := itr.Value()
+		indices[n] = uint64(len(indices) + 1)
+		encoded = append(encoded, flattener.EncodeNode(n, indices[n.LeftChild()], indices[n.RightChild()], nil)...)
+	}
+	nodesCount := uint64(len(indices))
+	require.Greater(t, nodesCount, uint64(2*subtrieNodeBatchSize))
+
+	for _, nWorker := range []int{1, 4} {
+		reader := bytes.NewReader(encoded)
+		nodes, err := readSubTrieNodes(reader, nodesCount, nWorker, func(uint64) {})
+		require.NoError(t, err)
+		require.Equal(t, 0, reader.Len())
+		require.Len(t, nodes, int(nodesCount)+1)
+		require.Equal(t, tr.RootNode().Hash(), nodes[nodesCount].Hash())
+		require.Equal(t, tr.RootNode().VerifyCachedHash(), nodes[nodesCount].VerifyCachedHash())
+	}
+
+	// a truncated subtrie fails to read
+	_, err = readSubTrieNodes(bytes.NewReader(encoded[:len(encoded)-1]), nodesCount, 4, func(uint64) {})
+	require.Error(t, err)
+}
+
 func randomNode() *node.Node {
 	var randomPath ledger.Path
 	rand.Read(randomPath[:])
@@ -257,6 +291,21 @@ func TestWriteAndReadCheckpointV6MultipleTries(t *testing.T) {
 	})
 }
 
+func TestReadTriesRootHash(t *testing.T) {
+	unittest.RunWithTempDir(t, func(dir string) {
+		tries := createMultipleRandomTries(t)
+		fileName := "checkpoint-root-hashes"
+		logger := unittest.Logger()
+		require.NoErrorf(t, StoreCheckpointV6Concurrently(tries, dir, fileName, &logger), "fail to store checkpoint")
+		rootHashes, err := ReadTriesRootHash(dir, fileName)
+		require.NoErrorf(t, err, "fail to read root hashes of checkpoint %v/%v", dir, fileName)
+		require.Len(t, rootHashes, len(tries))
+		for i, trie := range tries {
+			require.Equal(t, trie.RootHash(), rootHashes[i], "%v-th root hash is different", i)
+		}
+	})
+}
+
 // test running checkpointing twice will produce the same checkpoint file
 func TestCheckpointV6IsDeterminstic(t *testing.T) {
 	unittest.RunWithTempDir(t, func(dir string) {
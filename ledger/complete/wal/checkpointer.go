@@ -230,7 +230,7 @@ func (c *Checkpointer) Checkpoint(to int) (err error) {
 			return err
 		}, func(rootHash ledger.RootHash) error {
 			return nil
-		}, true)
+		}, true, false)
 
 	if err != nil {
 		return fmt.Errorf("cannot replay WAL: %w", err)
@@ -1,8 +1,12 @@
 package wal
 
 import (
+	"errors"
 	"fmt"
+	"os"
+	"runtime"
 	"sort"
+	"time"
 
 	prometheusWAL "github.com/m4ksio/wal/wal"
 	"github.com/prometheus/client_golang/prometheus"
@@ -22,10 +26,11 @@ type DiskWAL struct {
 	forestCapacity int
 	pathByteSize   int
 	log            zerolog.Logger
+	metrics        module.WALMetrics
 	dir            string
+	decodeWorkers  int
 }
 
-// TODO use real logger and metrics, but that would require passing them to Trie storage
 func NewDiskWAL(logger zerolog.Logger, reg prometheus.Registerer, metrics module.WALMetrics, dir string, forestCapacity int, pathByteSize int, segmentSize int) (*DiskWAL, error) {
 	w, err := prometheusWAL.NewSize(logger, reg, dir, segmentSize, false)
 	if err != nil {
@@ -37,7 +42,9 @@ func NewDiskWAL(logger zerolog.Logger, reg prometheus.Registerer, metrics module
 		forestCapacity: forestCapacity,
 		pathByteSize:   pathByteSize,
 		log:            logger.With().Str("ledger_mod", "diskwal").Logger(),
+		metrics:        metrics,
 		dir:            dir,
+		decodeWorkers:  runtime.NumCPU(),
 	}, nil
 }
 
@@ -87,7 +94,23 @@ func (w *DiskWAL) RecordDelete(rootHash ledger.RootHash) error {
 }
 
 func (w *DiskWAL) ReplayOnForest(forest *mtrie.Forest) error {
-	return w.Replay(
+	return w.replayOnForest(forest, false)
+}
+
+// ReplayOnForestAndRepair replays the WAL on the forest like ReplayOnForest. If the last record
+// of the WAL is torn, which happens when the node crashes while writing it, the records before it
+// are replayed and the WAL is repaired by discarding the torn record. It must only be used by the
+// node on startup, before any record is written to the WAL.
+func (w *DiskWAL) ReplayOnForestAndRepair(forest *mtrie.Forest) error {
+	return w.replayOnForest(forest, true)
+}
+
+func (w *DiskWAL) replayOnForest(forest *mtrie.Forest, repair bool) error {
+	from, to, err := w.Segments()
+	if err != nil {
+		return fmt.Errorf("could not find segments: %w", err)
+	}
+	err = w.replay(from, to,
 		func(tries []*trie.MTrie) error {
 			err := forest.AddTries(tries)
 			if err != nil {
@@ -102,7 +125,13 @@ func (w *DiskWAL) ReplayOnForest(forest *mtrie.Forest) error {
 		func(rootHash ledger.RootHash) error {
 			return nil
 		},
+		true,
+		repair,
 	)
+	if err != nil {
+		return fmt.Errorf("could not replay segments [%v:%v]: %w", from, to, err)
+	}
+	return nil
 }
 
 func (w *DiskWAL) Segments() (first, last int, err error) {
@@ -118,7 +147,7 @@ func (w *DiskWAL) Replay(
 	if err != nil {
 		return fmt.Errorf("could not find segments: %w", err)
 	}
-	err = w.replay(from, to, checkpointFn, updateFn, deleteFn, true)
+	err = w.replay(from, to, checkpointFn, updateFn, deleteFn, true, false)
 	if err != nil {
 		return fmt.Errorf("could not replay segments [%v:%v]: %w", from, to, err)
 	}
@@ -134,19 +163,23 @@ func (w *DiskWAL) ReplayLogsOnly(
 	if err != nil {
 		return fmt.Errorf("could not find segments: %w", err)
 	}
-	err = w.replay(from, to, checkpointFn, updateFn, deleteFn, false)
+	err = w.replay(from, to, checkpointFn, updateFn, deleteFn, false, false)
 	if err != nil {
 		return fmt.Errorf("could not replay WAL only for segments [%v:%v]: %w", from, to, err)
 	}
 	return nil
 }
 
+// replay replays the segments [from, to], starting at the latest checkpoint if useCheckpoints is set.
+// If repair is set and the replay ends at a torn record in the last segment holding data, the WAL is
+// repaired by discarding the torn record. Replays which only read the WAL must not repair it.
 func (w *DiskWAL) replay(
 	from, to int,
 	checkpointFn func(tries []*trie.MTrie) error,
 	updateFn func(update *ledger.TrieUpdate) error,
 	deleteFn func(rootHash ledger.RootHash) error,
 	useCheckpoints bool,
+	repair bool,
 ) error {
 
 	w.log.Info().Msgf("loading checkpoint with WAL from %d to %d", from, to)
@@ -164,6 +197,8 @@ func (w *DiskWAL) replay(
 		return fmt.Errorf("cannot create checkpointer: %w", err)
 	}
 
+	checkpointLoadStart := time.Now()
+
 	if useCheckpoints {
 		allCheckpoints, err := checkpointer.Checkpoints()
 		if err != nil {
@@ -194,7 +229,10 @@ func (w *DiskWAL) replay(
 				continue
 			}
 
-			w.log.Info().Int("checkpoint", latestCheckpoint).Msg("checkpoint loaded")
+			w.log.Info().Int("checkpoint", latestCheckpoint).
+				Dur("duration", time.Since(checkpointLoadStart)).
+				Msg("checkpoint loaded")
+			w.metrics.ExecutionCheckpointLoadDuration(time.Since(checkpointLoadStart))
 
 			err = checkpointFn(forestSequencing)
 			if err != nil {
@@ -231,7 +269,8 @@ func (w *DiskWAL) replay(
 				return fmt.Errorf("error while handling root checkpoint: %w", err)
 			}
 
-			w.log.Info().Msgf("root checkpoint loaded")
+			w.log.Info().Dur("duration", time.Since(checkpointLoadStart)).Msgf("root checkpoint loaded")
+			w.metrics.ExecutionCheckpointLoadDuration(time.Since(checkpointLoadStart))
 			checkpointLoaded = true
 		}
 	}
@@ -241,50 +280,218 @@ func (w *DiskWAL) replay(
 		Int("loaded_checkpoint", loadedCheckpoint).
 		Msgf("replaying segments from %d to %d", startSegment, to)
 
+	err = w.replaySegments(startSegment, to, updateFn, deleteFn)
+	var corruption *prometheusWAL.CorruptionErr
+	if repair && errors.As(err, &corruption) && corruption.Segment >= 0 {
+		// a crash while writing the last record leaves it torn. All records before it were replayed,
+		// so the WAL is repaired by discarding the torn record, as long as no other records follow it.
+		tail, tailErr := w.isTail(corruption.Segment, to)
+		if tailErr != nil {
+			return fmt.Errorf("cannot check corrupted LedgerWAL segment %d: %w", corruption.Segment, tailErr)
+		}
+		if !tail {
+			return err
+		}
+		w.log.Warn().Err(corruption).
+			Int("segment", corruption.Segment).
+			Int64("offset", corruption.Offset).
+			Msg("LedgerWAL ends with a corrupted record, discarding it and all data after it")
+		err = w.wal.Repair(corruption)
+		if err != nil {
+			return fmt.Errorf("cannot repair LedgerWAL: %w", err)
+		}
+	} else if err != nil {
+		return err
+	}
+
+	w.log.Info().Msgf("finished loading checkpoint and replaying WAL from %d to %d", from, to)
+
+	return nil
+}
+
+// isTail returns true if the given segment is the last segment of the WAL holding data. Segment `to`
+// must be the last segment of the WAL. The segments after the given segment may only be empty, as
+// the WAL starts a new segment whenever it is opened.
+func (w *DiskWAL) isTail(segment int, to int) (bool, error) {
+	_, last, err := w.Segments()
+	if err != nil {
+		return false, fmt.Errorf("cannot get segments: %w", err)
+	}
+	if to != last {
+		return false, nil
+	}
+	for i := segment + 1; i <= to; i++ {
+		info, err := os.Stat(prometheusWAL.SegmentName(w.wal.Dir(), i))
+		if err != nil {
+			return false, fmt.Errorf("cannot stat segment %d: %w", i, err)
+		}
+		if info.Size() > 0 {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// walRecord is a decoded LedgerWAL record.
+type walRecord struct {
+	operation WALOperation
+	rootHash  ledger.RootHash
+	update    *ledger.TrieUpdate
+	segment   int
+	err       error
+}
+
+// replaySegments replays the LedgerWAL records of segments [from, to] in order.
+// Reading and decoding the records is pipelined with applying them: records are read sequentially
+// and decoded concurrently by w.decodeWorkers workers, while the decoded records are passed to
+// updateFn and deleteFn in their original order by the calling goroutine.
+func (w *DiskWAL) replaySegments(
+	from, to int,
+	updateFn func(update *ledger.TrieUpdate) error,
+	deleteFn func(rootHash ledger.RootHash) error,
+) error {
 	sr, err := prometheusWAL.NewSegmentsRangeReader(prometheusWAL.SegmentRange{
 		Dir:   w.wal.Dir(),
-		First: startSegment,
+		First: from,
 		Last:  to,
 	})
 	if err != nil {
 		return fmt.Errorf("cannot create segment reader: %w", err)
 	}
 
+	defer sr.Close()
+
 	reader := prometheusWAL.NewReader(sr)
 
-	defer sr.Close()
+	done := make(chan struct{})
+	defer close(done)
+	records := decodeRecordsConcurrently(reader, w.decodeWorkers, done)
+
+	start := time.Now()
+	total := to - from + 1
+	current := from
+	w.metrics.ExecutionWALReplayProgress(0, total)
+
+	for resultCh := range records {
+		record := <-resultCh
+		if record.err != nil {
+			return record.err
+		}
 
-	for reader.Next() {
-		record := reader.Record()
-		operation, rootHash, update, err := Decode(record)
-		if err != nil {
-			return fmt.Errorf("cannot decode LedgerWAL record: %w", err)
+		if record.segment > current {
+			// all records of the previous segments have been applied
+			current = record.segment
+			replayed := current - from
+			w.metrics.ExecutionWALReplayProgress(replayed, total)
+			w.log.Info().
+				Int("segment", current).
+				Int("replayed_segments", replayed).
+				Int("total_segments", total).
+				Dur("elapsed", time.Since(start)).
+				Msgf("replaying LedgerWAL segments: %d%%", replayed*100/total)
 		}
 
-		switch operation {
+		switch record.operation {
 		case WALUpdate:
-			err = updateFn(update)
+			err = updateFn(record.update)
 			if err != nil {
 				return fmt.Errorf("error while processing LedgerWAL update: %w", err)
 			}
 		case WALDelete:
-			err = deleteFn(rootHash)
+			err = deleteFn(record.rootHash)
 			if err != nil {
 				return fmt.Errorf("error while processing LedgerWAL deletion: %w", err)
 			}
 		}
-
-		err = reader.Err()
-		if err != nil {
-			return fmt.Errorf("cannot read LedgerWAL: %w", err)
-		}
 	}
 
-	w.log.Info().Msgf("finished loading checkpoint and replaying WAL from %d to %d", from, to)
+	w.metrics.ExecutionWALReplayProgress(total, total)
+	w.metrics.ExecutionWALReplayDuration(time.Since(start))
 
 	return nil
 }
 
+// decodeRecordsConcurrently reads all records from the given reader and decodes them using nWorker
+// workers. It returns a channel of per-record result channels, in the same order as the records were read,
+// which is closed once all records were read. If reading fails, the read error is returned as the last
+// record. Reading stops early once the done channel is closed.
+func decodeRecordsConcurrently(
+	reader *prometheusWAL.Reader,
+	nWorker int,
+	done <-chan struct{},
+) <-chan chan walRecord {
+	if nWorker < 1 {
+		nWorker = 1
+	}
+
+	type job struct {
+		data    []byte
+		segment int
+		result  chan<- walRecord
+	}
+
+	// bound the number of records being decoded or waiting to be applied
+	jobs := make(chan job, nWorker)
+	records := make(chan chan walRecord, nWorker*4)
+
+	for i := 0; i < nWorker; i++ {
+		go func() {
+			for j := range jobs {
+				operation, rootHash, update, err := Decode(j.data)
+				if err != nil {
+					err = fmt.Errorf("cannot decode LedgerWAL record: %w", err)
+				}
+				// result channels are buffered, so sending never blocks
+				j.result <- walRecord{
+					operation: operation,
+					rootHash:  rootHash,
+					update:    update,
+					segment:   j.segment,
+					err:       err,
+				}
+			}
+		}()
+	}
+
+	go func() {
+		defer close(records)
+		defer close(jobs)
+
+		for reader.Next() {
+			// the reader reuses its record buffer and payloads are decoded without copying,
+			// so each record must be copied before being decoded concurrently
+			data := make([]byte, len(reader.Record()))
+			copy(data, reader.Record())
+
+			result := make(chan walRecord, 1)
+			select {
+			case <-done:
+				return
+			case records <- result:
+			}
+			select {
+			case <-done:
+				return
+			case jobs <- job{data: data, segment: reader.Segment(), result: result}:
+			}
+		}
+
+		// a read error is delivered as the last record, after all records read before it
+		// have been applied, so the caller can decide whether the records read are complete
+		err := reader.Err()
+		if err != nil {
+			result := make(chan walRecord, 1)
+			result <- walRecord{err: fmt.Errorf("cannot read LedgerWAL: %w", err)}
+			select {
+			case <-done:
+			case records <- result:
+			}
+		}
+	}()
+
+	return records
+}
+
 func getPossibleCheckpoints(allCheckpoints []int, from, to int) []int {
 	// list of checkpoints is sorted
 	indexFrom := sort.SearchInts(allCheckpoints, from)
@@ -340,6 +547,7 @@ type LedgerWAL interface {
 	RecordUpdate(update *ledger.TrieUpdate) (int, bool, error)
 	RecordDelete(rootHash ledger.RootHash) error
 	ReplayOnForest(forest *mtrie.Forest) error
+	ReplayOnForestAndRepair(forest *mtrie.Forest) error
 	Segments() (first, last int, err error)
 	Replay(
 		checkpointFn func(tries []*trie.MTrie) error,
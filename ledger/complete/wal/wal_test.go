@@ -1,11 +1,17 @@
 package wal
 
 import (
+	"fmt"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/rs/zerolog"
 	"github.com/stretchr/testify/require"
 
+	"github.com/onflow/flow-go/ledger"
+	"github.com/onflow/flow-go/ledger/common/testutils"
+	"github.com/onflow/flow-go/ledger/complete/mtrie/trie"
 	"github.com/onflow/flow-go/module/metrics"
 	"github.com/onflow/flow-go/storage/util"
 	"github.com/onflow/flow-go/utils/unittest"
@@ -173,3 +179,283 @@ func Test_LatestPossibleCheckpoints(t *testing.T) {
 	require.Equal(t, []int{}, getPossibleCheckpoints([]int{1, 2, 5}, 6, 6))
 
 }
+
+// Test_ReplayKeepsRecordOrder checks that records decoded concurrently are replayed
+// in the order they were written, across several segments.
+func Test_ReplayKeepsRecordOrder(t *testing.T) {
+	unittest.RunWithTempDir(t, func(dir string) {
+		w, err := NewDiskWAL(zerolog.Nop(), nil, metrics.NewNoopCollector(), dir, 10, pathByteSize, segmentSize)
+		require.NoError(t, err)
+
+		updates := make([]*ledger.TrieUpdate, 0)
+		deletes := make(map[int]ledger.RootHash)
+		for i := 0; i < 200; i++ {
+			if i%10 == 9 {
+				deletes[i] = testutils.RootHashFixture()
+				require.NoError(t, w.RecordDelete(deletes[i]))
+				continue
+			}
+			update := testutils.TrieUpdateFixture(5, 100, 500)
+			updates = append(updates, update)
+			_, _, err := w.RecordUpdate(update)
+			require.NoError(t, err)
+		}
+		<-w.Done()
+
+		w, err = NewDiskWAL(zerolog.Nop(), nil, metrics.NewNoopCollector(), dir, 10, pathByteSize, segmentSize)
+		require.NoError(t, err)
+		defer func() { <-w.Done() }()
+
+		from, to, err := w.Segments()
+		require.NoError(t, err)
+		require.Greater(t, to, from, "records should span several segments")
+
+		i := 0
+		replayedUpdates := 0
+		err = w.ReplayLogsOnly(
+			func(tries []*trie.MTrie) error {
+				return fmt.Errorf("no checkpoint expected")
+			},
+			func(update *ledger.TrieUpdate) error {
+				require.NotContains(t, deletes, i)
+				require.True(t, update.Equals(updates[replayedUpdates]))
+				replayedUpdates++
+				i++
+				return nil
+			},
+			func(rootHash ledger.RootHash) error {
+				require.Equal(t, deletes[i], rootHash)
+				i++
+				return nil
+			},
+		)
+		require.NoError(t, err)
+		require.Equal(t, 200, i)
+		require.Equal(t, len(updates), replayedUpdates)
+	})
+}
+
+// Test_ReplayStopsOnError checks that an error returned while applying a record stops the replay.
+func Test_ReplayStopsOnError(t *testing.T) {
+	unittest.RunWithTempDir(t, func(dir string) {
+		w, err := NewDiskWAL(zerolog.Nop(), nil, metrics.NewNoopCollector(), dir, 10, pathByteSize, segmentSize)
+		require.NoError(t, err)
+		defer func() { <-w.Done() }()
+
+		for i := 0; i < 100; i++ {
+			_, _, err := w.RecordUpdate(testutils.TrieUpdateFixture(5, 100, 500))
+			require.NoError(t, err)
+		}
+
+		expectedErr := fmt.Errorf("expected error")
+		applied := 0
+		err = w.ReplayLogsOnly(
+			func(tries []*trie.MTrie) error {
+				return nil
+			},
+			func(update *ledger.TrieUpdate) error {
+				applied++
+				if applied == 10 {
+					return expectedErr
+				}
+				return nil
+			},
+			func(rootHash ledger.RootHash) error {
+				return nil
+			},
+		)
+		require.ErrorIs(t, err, expectedErr)
+		require.Equal(t, 10, applied)
+	})
+}
+
+// Test_ReplayFailsOnCorruptedSegment checks that a record which can't be read from a segment
+// fails the replay, rather than silently replaying only the records before it.
+func Test_ReplayFailsOnCorruptedSegment(t *testing.T) {
+	unittest.RunWithTempDir(t, func(dir string) {
+		w, err := NewDiskWAL(zerolog.Nop(), nil, metrics.NewNoopCollector(), dir, 10, pathByteSize, segmentSize)
+		require.NoError(t, err)
+
+		for i := 0; i < 100; i++ {
+			_, _, err := w.RecordUpdate(testutils.TrieUpdateFixture(5, 100, 500))
+			require.NoError(t, err)
+		}
+		<-w.Done()
+
+		// flip a byte in the middle of the first segment, so the checksum of a record doesn't match
+		segment := filepath.Join(dir, NumberToFilenamePart(0))
+		data, err := os.ReadFile(segment)
+		require.NoError(t, err)
+		data[len(data)/2] ^= 0xff
+		require.NoError(t, os.WriteFile(segment, data, 0644))
+
+		w, err = NewDiskWAL(zerolog.Nop(), nil, metrics.NewNoopCollector(), dir, 10, pathByteSize, segmentSize)
+		require.NoError(t, err)
+		defer func() { <-w.Done() }()
+
+		applied := 0
+		err = w.ReplayLogsOnly(
+			func(tries []*trie.MTrie) error {
+				return nil
+			},
+			func(update *ledger.TrieUpdate) error {
+				applied++
+				return nil
+			},
+			func(rootHash ledger.RootHash) error {
+				return nil
+			},
+		)
+		require.Error(t, err)
+		require.Less(t, applied, 100)
+	})
+}
+
+// tearLastRecord cuts off the end of the last segment of the WAL in dir, tearing its last record.
+// The segment's last page is padded with zeros, so the cut is made before the padding.
+func tearLastRecord(t *testing.T, w *DiskWAL, dir string) {
+	_, last, err := w.Segments()
+	require.NoError(t, err)
+	segment := filepath.Join(dir, NumberToFilenamePart(last))
+	data, err := os.ReadFile(segment)
+	require.NoError(t, err)
+	end := len(data)
+	for end > 0 && data[end-1] == 0 {
+		end--
+	}
+	require.NoError(t, os.WriteFile(segment, data[:end-100], 0644))
+}
+
+// replayCounting replays all segments of the WAL, counting the applied updates.
+func replayCounting(w *DiskWAL, repair bool) (int, error) {
+	from, to, err := w.Segments()
+	if err != nil {
+		return 0, err
+	}
+	applied := 0
+	err = w.replay(from, to,
+		func(tries []*trie.MTrie) error {
+			return nil
+		},
+		func(update *ledger.TrieUpdate) error {
+			applied++
+			return nil
+		},
+		func(rootHash ledger.RootHash) error {
+			return nil
+		},
+		false,
+		repair,
+	)
+	return applied, err
+}
+
+// Test_ReplayRepairsTornTail checks that a record torn by a crash at the end of the WAL doesn't fail
+// the replay on startup: all records before it are replayed, and the WAL is repaired so it can be appended to.
+func Test_ReplayRepairsTornTail(t *testing.T) {
+	unittest.RunWithTempDir(t, func(dir string) {
+		w, err := NewDiskWAL(zerolog.Nop(), nil, metrics.NewNoopCollector(), dir, 10, pathByteSize, segmentSize)
+		require.NoError(t, err)
+
+		for i := 0; i < 100; i++ {
+			_, _, err := w.RecordUpdate(testutils.TrieUpdateFixture(5, 100, 500))
+			require.NoError(t, err)
+		}
+		<-w.Done()
+		tearLastRecord(t, w, dir)
+
+		w, err = NewDiskWAL(zerolog.Nop(), nil, metrics.NewNoopCollector(), dir, 10, pathByteSize, segmentSize)
+		require.NoError(t, err)
+
+		applied, err := replayCounting(w, true)
+		require.NoError(t, err)
+		require.Equal(t, 99, applied)
+
+		// records appended after the repair are replayed after the intact records
+		for i := 0; i < 10; i++ {
+			_, _, err := w.RecordUpdate(testutils.TrieUpdateFixture(5, 100, 500))
+			require.NoError(t, err)
+		}
+		<-w.Done()
+
+		w, err = NewDiskWAL(zerolog.Nop(), nil, metrics.NewNoopCollector(), dir, 10, pathByteSize, segmentSize)
+		require.NoError(t, err)
+		defer func() { <-w.Done() }()
+
+		applied, err = replayCounting(w, true)
+		require.NoError(t, err)
+		require.Equal(t, 109, applied)
+	})
+}
+
+// Test_ReadOnlyReplayDoesNotRepair checks that replays which only read the WAL, such as the replays of
+// tools and of the checkpointer, fail on a torn record and leave the WAL untouched.
+func Test_ReadOnlyReplayDoesNotRepair(t *testing.T) {
+	unittest.RunWithTempDir(t, func(dir string) {
+		w, err := NewDiskWAL(zerolog.Nop(), nil, metrics.NewNoopCollector(), dir, 10, pathByteSize, segmentSize)
+		require.NoError(t, err)
+
+		for i := 0; i < 100; i++ {
+			_, _, err := w.RecordUpdate(testutils.TrieUpdateFixture(5, 100, 500))
+			require.NoError(t, err)
+		}
+		<-w.Done()
+		tearLastRecord(t, w, dir)
+
+		w, err = NewDiskWAL(zerolog.Nop(), nil, metrics.NewNoopCollector(), dir, 10, pathByteSize, segmentSize)
+		require.NoError(t, err)
+		defer func() { <-w.Done() }()
+
+		first, last, err := w.Segments()
+		require.NoError(t, err)
+		sizes := make(map[int]int64)
+		for i := first; i <= last; i++ {
+			info, err := os.Stat(filepath.Join(dir, NumberToFilenamePart(i)))
+			require.NoError(t, err)
+			sizes[i] = info.Size()
+		}
+
+		_, err = replayCounting(w, false)
+		require.Error(t, err)
+
+		err = w.ReplayLogsOnly(
+			func(tries []*trie.MTrie) error {
+				return nil
+			},
+			func(update *ledger.TrieUpdate) error {
+				return nil
+			},
+			func(rootHash ledger.RootHash) error {
+				return nil
+			},
+		)
+		require.Error(t, err)
+
+		// a replay up to a segment before the last one never repairs, even if asked to
+		err = w.replay(first, last-1,
+			func(tries []*trie.MTrie) error {
+				return nil
+			},
+			func(update *ledger.TrieUpdate) error {
+				return nil
+			},
+			func(rootHash ledger.RootHash) error {
+				return nil
+			},
+			false,
+			true,
+		)
+		require.Error(t, err)
+
+		// all segments are unchanged
+		first2, last2, err := w.Segments()
+		require.NoError(t, err)
+		require.Equal(t, first, first2)
+		require.Equal(t, last, last2)
+		for i := first; i <= last; i++ {
+			info, err := os.Stat(filepath.Join(dir, NumberToFilenamePart(i)))
+			require.NoError(t, err)
+			require.Equal(t, sizes[i], info.Size())
+		}
+	})
+}
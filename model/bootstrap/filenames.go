@@ -26,6 +26,9 @@ var (
 	PathRootBlockData             = filepath.Join(DirnamePublicBootstrap, "root-block.json")
 	PathRootProtocolStateSnapshot = filepath.Join(DirnamePublicBootstrap, "root-protocol-state-snapshot.json")
 
+	// checkpoint obtained by checkpoint-based fast sync, the node re-bootstraps from it upon restart
+	PathCheckpointProtocolStateSnapshot = filepath.Join(DirnamePublicBootstrap, "checkpoint-protocol-state-snapshot.json")
+
 	FilenameWALRootCheckpoint = "root.checkpoint"
 	PathRootCheckpoint        = filepath.Join(DirnameExecutionState, FilenameWALRootCheckpoint) // only available on an execution node
 
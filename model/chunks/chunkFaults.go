@@ -174,3 +174,31 @@ func NewCFInvalidVerifiableChunk(reason string, err error, chInx uint64, execRes
 		chunkIndex: chInx,
 		execResID:  execResID}
 }
+
+// Expected returns the final state commitment computed by the verifier, by applying the register updates of the chunk
+func (cf CFNonMatchingFinalState) Expected() flow.StateCommitment {
+	return cf.expected
+}
+
+// Computed returns the final state commitment of the chunk provided by the execution result
+func (cf CFNonMatchingFinalState) Computed() flow.StateCommitment {
+	return cf.computed
+}
+
+// FaultType returns the type of the given chunk fault, and false if the chunk fault is of an unknown type.
+func FaultType(fault ChunkFault) (flow.ChunkFaultType, bool) {
+	switch fault.(type) {
+	case *CFMissingRegisterTouch:
+		return flow.ChunkFaultMissingRegisterTouch, true
+	case *CFNonMatchingFinalState:
+		return flow.ChunkFaultNonMatchingFinalState, true
+	case *CFInvalidEventsCollection:
+		return flow.ChunkFaultInvalidEventsCollection, true
+	case *CFInvalidServiceEventsEmitted:
+		return flow.ChunkFaultInvalidServiceEventsEmitted, true
+	case *CFInvalidVerifiableChunk:
+		return flow.ChunkFaultInvalidVerifiableChunk, true
+	default:
+		return "", false
+	}
+}
@@ -0,0 +1,93 @@
+package flow
+
+import (
+	"github.com/onflow/flow-go/crypto"
+	"github.com/onflow/flow-go/model/fingerprint"
+)
+
+// ChunkFaultType is the type of fault found by a verification node while verifying a chunk.
+type ChunkFaultType string
+
+const (
+	ChunkFaultMissingRegisterTouch        ChunkFaultType = "missing_register_touch"
+	ChunkFaultNonMatchingFinalState       ChunkFaultType = "non_matching_final_state"
+	ChunkFaultInvalidEventsCollection     ChunkFaultType = "invalid_events_collection"
+	ChunkFaultInvalidServiceEventsEmitted ChunkFaultType = "invalid_service_events_emitted"
+	ChunkFaultInvalidVerifiableChunk      ChunkFaultType = "invalid_verifiable_chunk"
+)
+
+// ChunkFaultReportBody holds the body part of a chunk fault report.
+type ChunkFaultReportBody struct {
+	BlockID           Identifier     // ID of the executed block
+	ExecutionResultID Identifier     // ID of the execution result including the faulty chunk
+	ChunkIndex        uint64         // index of the faulty chunk
+	ReporterID        Identifier     // node id of the verification node reporting the fault
+	FaultType         ChunkFaultType // type of the fault found while verifying the chunk
+	FaultDetails      string         // human-readable description of the fault
+	ChunkDataPack     ChunkDataPack  // chunk data pack the chunk was verified against
+	// ComputedEndState is the end state of the chunk computed by the reporter, it is
+	// empty if the verification failed before the end state could be computed.
+	ComputedEndState StateCommitment
+}
+
+// Fingerprint returns the canonical encoding of the chunk fault report body, which encodes the collection of the
+// chunk data pack by its fingerprint.
+func (b ChunkFaultReportBody) Fingerprint() []byte {
+	var collection []byte
+	if b.ChunkDataPack.Collection != nil {
+		collection = b.ChunkDataPack.Collection.Fingerprint()
+	}
+	return fingerprint.Fingerprint(struct {
+		BlockID           Identifier
+		ExecutionResultID Identifier
+		ChunkIndex        uint64
+		ReporterID        Identifier
+		FaultType         string
+		FaultDetails      string
+		ChunkID           Identifier
+		StartState        StateCommitment
+		Proof             StorageProof
+		Collection        []byte
+		ComputedEndState  StateCommitment
+	}{
+		BlockID:           b.BlockID,
+		ExecutionResultID: b.ExecutionResultID,
+		ChunkIndex:        b.ChunkIndex,
+		ReporterID:        b.ReporterID,
+		FaultType:         string(b.FaultType),
+		FaultDetails:      b.FaultDetails,
+		ChunkID:           b.ChunkDataPack.ChunkID,
+		StartState:        b.ChunkDataPack.StartState,
+		Proof:             b.ChunkDataPack.Proof,
+		Collection:        collection,
+		ComputedEndState:  b.ComputedEndState,
+	})
+}
+
+// ID generates a unique identifier using the chunk fault report body
+func (b ChunkFaultReportBody) ID() Identifier {
+	return MakeID(b)
+}
+
+// ChunkFaultReport reports a fault found by a verification node while verifying a chunk of an
+// execution result, along with the evidence needed to investigate the fault.
+type ChunkFaultReport struct {
+	Body              ChunkFaultReportBody
+	ReporterSignature crypto.Signature // signature over the body
+}
+
+// ID generates a unique identifier using the chunk fault report body
+func (r ChunkFaultReport) ID() Identifier {
+	return MakeID(r.Body)
+}
+
+// Checksum generates checksum using the chunk fault report full content
+func (r ChunkFaultReport) Checksum() Identifier {
+	return MakeID(struct {
+		BodyID            Identifier
+		ReporterSignature crypto.Signature
+	}{
+		BodyID:            r.Body.ID(),
+		ReporterSignature: r.ReporterSignature,
+	})
+}
@@ -0,0 +1,42 @@
+package flow_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+// TestChunkFaultReportID evaluates that the ID of a chunk fault report covers the chunk data pack, including its
+// collection, but not the signature of the reporter, which is covered by the checksum.
+func TestChunkFaultReportID(t *testing.T) {
+	report := flow.ChunkFaultReport{
+		Body: flow.ChunkFaultReportBody{
+			BlockID:           unittest.IdentifierFixture(),
+			ExecutionResultID: unittest.IdentifierFixture(),
+			ReporterID:        unittest.IdentifierFixture(),
+			FaultType:         flow.ChunkFaultNonMatchingFinalState,
+			ChunkDataPack:     *unittest.ChunkDataPackFixture(unittest.IdentifierFixture()),
+			ComputedEndState:  unittest.StateCommitmentFixture(),
+		},
+		ReporterSignature: unittest.SignatureFixture(),
+	}
+	id := report.ID()
+	checksum := report.Checksum()
+	assert.NotEqual(t, flow.ZeroID, id)
+
+	signed := report
+	signed.ReporterSignature = unittest.SignatureFixture()
+	assert.Equal(t, id, signed.ID())
+	assert.NotEqual(t, checksum, signed.Checksum())
+
+	collection := unittest.CollectionFixture(1)
+	modified := report
+	modified.Body.ChunkDataPack.Collection = &collection
+	assert.NotEqual(t, id, modified.ID())
+
+	modified.Body.ChunkDataPack.Collection = nil
+	assert.NotEqual(t, id, modified.ID())
+}
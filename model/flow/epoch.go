@@ -309,6 +309,60 @@ func (commit *EpochCommit) EqualTo(other *EpochCommit) bool {
 	return true
 }
 
+// EpochRecover is a service event emitted by governance to exit epoch emergency
+// fallback mode (EECC). It defines a recovery epoch, which is set up and committed
+// atomically, as no epoch preparation protocol is run for it: the cluster assignments
+// and root QCs are generated off-chain and the DKG of the current epoch is reused.
+type EpochRecover struct {
+	EpochSetup  EpochSetup
+	EpochCommit EpochCommit
+}
+
+func (recover *EpochRecover) ServiceEvent() ServiceEvent {
+	return ServiceEvent{
+		Type:  ServiceEventRecover,
+		Event: recover,
+	}
+}
+
+// ID returns the hash of the event contents.
+func (recover *EpochRecover) ID() Identifier {
+	return MakeID(struct {
+		SetupID  Identifier
+		CommitID Identifier
+	}{
+		SetupID:  recover.EpochSetup.ID(),
+		CommitID: recover.EpochCommit.ID(),
+	})
+}
+
+func (recover *EpochRecover) EqualTo(other *EpochRecover) bool {
+	return recover.EpochSetup.EqualTo(&other.EpochSetup) && recover.EpochCommit.EqualTo(&other.EpochCommit)
+}
+
+type encodableRecover struct {
+	EpochSetup  *EpochSetup
+	EpochCommit *EpochCommit
+}
+
+func (recover *EpochRecover) MarshalCBOR() ([]byte, error) {
+	return cbor.Marshal(encodableRecover{EpochSetup: &recover.EpochSetup, EpochCommit: &recover.EpochCommit})
+}
+
+func (recover *EpochRecover) UnmarshalCBOR(b []byte) error {
+	enc := encodableRecover{EpochSetup: &recover.EpochSetup, EpochCommit: &recover.EpochCommit}
+	return cbor.Unmarshal(b, &enc)
+}
+
+func (recover *EpochRecover) MarshalMsgpack() ([]byte, error) {
+	return msgpack.Marshal(encodableRecover{EpochSetup: &recover.EpochSetup, EpochCommit: &recover.EpochCommit})
+}
+
+func (recover *EpochRecover) UnmarshalMsgpack(b []byte) error {
+	enc := encodableRecover{EpochSetup: &recover.EpochSetup, EpochCommit: &recover.EpochCommit}
+	return msgpack.Unmarshal(b, &enc)
+}
+
 // ToDKGParticipantLookup constructs a DKG participant lookup from an identity
 // list and a key list. The identity list must be EXACTLY the same (order and
 // contents) as that used when initializing the corresponding DKG instance.
@@ -415,14 +469,21 @@ type EpochStatus struct {
 	// incorporated in this fork. When this happens, epoch fallback is triggered
 	// AFTER the fork is finalized.
 	InvalidServiceEventIncorporated bool
+	// EpochFallbackTriggered encodes whether epoch emergency fallback is in effect in
+	// this fork as of this block. It is derived from the parent's epoch status and the
+	// block itself only, so it is the same for a given block on all nodes, independent
+	// of their local finalization progress. It is cleared by the first block of a
+	// recovery epoch.
+	EpochFallbackTriggered bool
 }
 
 // Copy returns a copy of the epoch status.
 func (es *EpochStatus) Copy() *EpochStatus {
 	return &EpochStatus{
-		PreviousEpoch: es.PreviousEpoch,
-		CurrentEpoch:  es.CurrentEpoch,
-		NextEpoch:     es.NextEpoch,
+		PreviousEpoch:          es.PreviousEpoch,
+		CurrentEpoch:           es.CurrentEpoch,
+		NextEpoch:              es.NextEpoch,
+		EpochFallbackTriggered: es.EpochFallbackTriggered,
 	}
 }
 
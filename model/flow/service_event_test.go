@@ -165,3 +165,61 @@ func TestEncodeDecode(t *testing.T) {
 		})
 	})
 }
+
+// TestEncodeDecodeRecover tests that EpochRecover service events survive encoding
+// round trips with all supported encodings, both directly and wrapped in a ServiceEvent.
+func TestEncodeDecodeRecover(t *testing.T) {
+	recover := unittest.EpochRecoverFixture()
+
+	t.Run("json", func(t *testing.T) {
+		b, err := json.Marshal(recover.ServiceEvent())
+		require.NoError(t, err)
+
+		outer := new(flow.ServiceEvent)
+		err = json.Unmarshal(b, outer)
+		require.NoError(t, err)
+		gotRecover, ok := outer.Event.(*flow.EpochRecover)
+		require.True(t, ok)
+		require.True(t, recover.EqualTo(gotRecover))
+		require.Equal(t, recover.ID(), gotRecover.ID())
+	})
+
+	t.Run("msgpack", func(t *testing.T) {
+		b, err := msgpack.Marshal(recover.ServiceEvent())
+		require.NoError(t, err)
+
+		outer := new(flow.ServiceEvent)
+		err = msgpack.Unmarshal(b, outer)
+		require.NoError(t, err)
+		gotRecover, ok := outer.Event.(*flow.EpochRecover)
+		require.True(t, ok)
+		require.True(t, recover.EqualTo(gotRecover))
+		require.Equal(t, recover.ID(), gotRecover.ID())
+	})
+
+	t.Run("cbor", func(t *testing.T) {
+		b, err := cborcodec.EncMode.Marshal(recover.ServiceEvent())
+		require.NoError(t, err)
+
+		outer := new(flow.ServiceEvent)
+		err = cbor.Unmarshal(b, outer)
+		require.NoError(t, err)
+		gotRecover, ok := outer.Event.(*flow.EpochRecover)
+		require.True(t, ok)
+		require.True(t, recover.EqualTo(gotRecover))
+		require.Equal(t, recover.ID(), gotRecover.ID())
+	})
+
+	t.Run("equality", func(t *testing.T) {
+		event := recover.ServiceEvent()
+		other := unittest.EpochRecoverFixture().ServiceEvent()
+		equal, err := event.EqualTo(&other)
+		require.NoError(t, err)
+		require.False(t, equal)
+
+		self := recover.ServiceEvent()
+		equal, err = event.EqualTo(&self)
+		require.NoError(t, err)
+		require.True(t, equal)
+	})
+}
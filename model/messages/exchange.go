@@ -22,3 +22,13 @@ type EntityResponse struct {
 	EntityIDs []flow.Identifier
 	Blobs     [][]byte
 }
+
+// RequestID returns the nonce of the request, which is echoed by the response.
+func (r *EntityRequest) RequestID() uint64 {
+	return r.Nonce
+}
+
+// ResponseTo returns the nonce of the request the response responds to.
+func (r *EntityResponse) ResponseTo() uint64 {
+	return r.Nonce
+}
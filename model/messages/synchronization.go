@@ -47,6 +47,65 @@ type BlockResponse struct {
 	Blocks []UntrustedBlock
 }
 
+// SnapshotRequest is part of the synchronization protocol and represents an
+// attempt of a node, which is far behind the network, to obtain a protocol state
+// snapshot at the latest finalized block of the recipient, so it can skip ahead
+// instead of downloading every block since its own finalized height.
+type SnapshotRequest struct {
+	Nonce  uint64
+	Height uint64
+}
+
+// SnapshotResponse is part of the synchronization protocol and represents the
+// reply to a snapshot request. It contains the JSON-encoded protocol state
+// snapshot at the responder's latest finalized block, including its sealing
+// segment and the QC certifying the head. As the head is not guaranteed to be
+// finalized by the QC alone, the response also contains a proof of finality:
+// a direct child of the head (with view exactly one higher than the head) and
+// the QC certifying this child, which together satisfy the 2-chain finalization
+// rule of HotStuff.
+type SnapshotResponse struct {
+	Nonce           uint64
+	Snapshot        []byte
+	FinalityProof   *flow.Header
+	FinalityProofQC *flow.QuorumCertificate
+}
+
+// RequestID returns the nonce of the request, which is echoed by the response.
+func (r *SyncRequest) RequestID() uint64 {
+	return r.Nonce
+}
+
+// ResponseTo returns the nonce of the request the response responds to.
+func (r *SyncResponse) ResponseTo() uint64 {
+	return r.Nonce
+}
+
+// RequestID returns the nonce of the request, which is echoed by the response.
+func (r *RangeRequest) RequestID() uint64 {
+	return r.Nonce
+}
+
+// RequestID returns the nonce of the request, which is echoed by the response.
+func (r *BatchRequest) RequestID() uint64 {
+	return r.Nonce
+}
+
+// RequestID returns the nonce of the request, which is echoed by the response.
+func (r *SnapshotRequest) RequestID() uint64 {
+	return r.Nonce
+}
+
+// ResponseTo returns the nonce of the request the response responds to.
+func (r *SnapshotResponse) ResponseTo() uint64 {
+	return r.Nonce
+}
+
+// ResponseTo returns the nonce of the request the response responds to.
+func (br *BlockResponse) ResponseTo() uint64 {
+	return br.Nonce
+}
+
 func (br *BlockResponse) BlocksInternal() []*flow.Block {
 	internal := make([]*flow.Block, len(br.Blocks))
 	for i, block := range br.Blocks {
@@ -63,6 +122,11 @@ type ClusterBlockResponse struct {
 	Blocks []UntrustedClusterBlock
 }
 
+// ResponseTo returns the nonce of the request the response responds to.
+func (br *ClusterBlockResponse) ResponseTo() uint64 {
+	return br.Nonce
+}
+
 func (br *ClusterBlockResponse) BlocksInternal() []*cluster.Block {
 	internal := make([]*cluster.Block, len(br.Blocks))
 	for i, block := range br.Blocks {
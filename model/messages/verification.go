@@ -15,3 +15,13 @@ type ApprovalResponse struct {
 	Nonce    uint64
 	Approval flow.ResultApproval
 }
+
+// RequestID returns the nonce of the request, which is echoed by the response.
+func (r *ApprovalRequest) RequestID() uint64 {
+	return r.Nonce
+}
+
+// ResponseTo returns the nonce of the request the response responds to.
+func (r *ApprovalResponse) ResponseTo() uint64 {
+	return r.Nonce
+}
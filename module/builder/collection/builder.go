@@ -36,6 +36,7 @@ type Builder struct {
 	payloads       storage.ClusterPayloads
 	transactions   mempool.Transactions
 	tracer         module.Tracer
+	metrics        module.CollectionMetrics
 	config         Config
 	log            zerolog.Logger
 }
@@ -44,10 +45,11 @@ type Builder struct {
 //   - pass in epoch (minimally counter, preferably cluster chain ID as well)
 //   - check candidate reference blocks by view (need to get whole header each time - cheap if header in cache)
 //   - if outside view boundary, look up first+final block height of epoch (can cache both)
-func NewBuilder(db *badger.DB, tracer module.Tracer, mainHeaders storage.Headers, clusterHeaders storage.Headers, payloads storage.ClusterPayloads, transactions mempool.Transactions, log zerolog.Logger, opts ...Opt) (*Builder, error) {
+func NewBuilder(db *badger.DB, tracer module.Tracer, metrics module.CollectionMetrics, mainHeaders storage.Headers, clusterHeaders storage.Headers, payloads storage.ClusterPayloads, transactions mempool.Transactions, log zerolog.Logger, opts ...Opt) (*Builder, error) {
 	b := Builder{
 		db:             db,
 		tracer:         tracer,
+		metrics:        metrics,
 		mainHeaders:    mainHeaders,
 		clusterHeaders: clusterHeaders,
 		payloads:       payloads,
@@ -64,6 +66,15 @@ func NewBuilder(db *badger.DB, tracer module.Tracer, mainHeaders storage.Headers
 	if b.config.ExpiryBuffer >= flow.DefaultTransactionExpiry {
 		return nil, fmt.Errorf("invalid configured expiry buffer exceeds tx expiry (%d > %d)", b.config.ExpiryBuffer, flow.DefaultTransactionExpiry)
 	}
+	switch b.config.TransactionSelection {
+	case FIFOSelection:
+	case PrioritySelection:
+		if _, ok := transactions.(mempool.PrioritizedTransactions); !ok {
+			return nil, fmt.Errorf("priority transaction selection requires a prioritized transaction pool, got %T", transactions)
+		}
+	default:
+		return nil, fmt.Errorf("invalid configured transaction selection %q", b.config.TransactionSelection)
+	}
 
 	return &b, nil
 }
@@ -205,7 +216,11 @@ func (b *Builder) BuildOn(parentID flow.Identifier, setter func(*flow.Header) er
 	var transactions []*flow.TransactionBody
 	var totalByteSize uint64
 	var totalGas uint64
-	for _, tx := range b.transactions.All() {
+	prioritized := b.config.TransactionSelection == PrioritySelection
+	var selected []*mempool.PendingTransaction
+	pendingTransactions := b.pendingTransactions()
+	for pending, ok := pendingTransactions.Next(); ok; pending, ok = pendingTransactions.Next() {
+		tx := pending.TransactionBody
 
 		// if we have reached maximum number of transactions, stop
 		if uint(len(transactions)) >= b.config.MaxCollectionSize {
@@ -221,8 +236,11 @@ func (b *Builder) BuildOn(parentID flow.Identifier, setter func(*flow.Header) er
 		}
 
 		// because the max byte size per tx is way smaller than the max collection byte size, we can stop here and not continue.
-		// to make it more effective in the future we can continue adding smaller ones
+		// when selecting by priority, we fill the collection with the smaller lower priority transactions fitting in.
 		if totalByteSize+txByteSize > b.config.MaxCollectionByteSize {
+			if prioritized {
+				continue
+			}
 			break
 		}
 
@@ -233,8 +251,11 @@ func (b *Builder) BuildOn(parentID flow.Identifier, setter func(*flow.Header) er
 		}
 
 		// cause the max gas limit per tx is way smaller than the total max gas per collection, we can stop here and not continue.
-		// to make it more effective in the future we can continue adding smaller ones
+		// when selecting by priority, we fill the collection with the smaller lower priority transactions fitting in.
 		if totalGas+tx.GasLimit > b.config.MaxCollectionTotalGas {
+			if prioritized {
+				continue
+			}
 			break
 		}
 
@@ -311,6 +332,7 @@ func (b *Builder) BuildOn(parentID flow.Identifier, setter func(*flow.Header) er
 		limiter.transactionIncluded(tx)
 
 		transactions = append(transactions, tx)
+		selected = append(selected, pending)
 		totalByteSize += txByteSize
 		totalGas += tx.GasLimit
 	}
@@ -363,9 +385,42 @@ func (b *Builder) BuildOn(parentID flow.Identifier, setter func(*flow.Header) er
 		return nil, fmt.Errorf("could not insert built block: %w", err)
 	}
 
+	// the FIFO transaction pool does not track the time the transactions were added
+	now := time.Now()
+	for _, pending := range selected {
+		if !pending.Added.IsZero() {
+			b.metrics.TransactionSelected(now.Sub(pending.Added))
+		}
+	}
+
 	return proposal.Header, nil
 }
 
+// pendingTransactions returns an iterator over the transactions of the transaction
+// pool, in the order of the configured transaction selection.
+func (b *Builder) pendingTransactions() mempool.PendingTransactionIterator {
+	if b.config.TransactionSelection == PrioritySelection {
+		return b.transactions.(mempool.PrioritizedTransactions).ByPriority()
+	}
+	return &fifoTransactions{txs: b.transactions.All()}
+}
+
+// fifoTransactions iterates over the transactions of the transaction pool in the
+// order of the transaction pool.
+type fifoTransactions struct {
+	txs []*flow.TransactionBody
+}
+
+// Next returns the next transaction, or false if all transactions have been returned.
+func (f *fifoTransactions) Next() (*mempool.PendingTransaction, bool) {
+	if len(f.txs) == 0 {
+		return nil, false
+	}
+	tx := f.txs[0]
+	f.txs = f.txs[1:]
+	return &mempool.PendingTransaction{TransactionBody: tx}, true
+}
+
 // populateUnfinalizedAncestryLookup traverses the unfinalized ancestry backward
 // to populate the transaction lookup (used for deduplication) and the rate limiter
 // (used to limit transaction submission by payer).
@@ -9,6 +9,7 @@ import (
 
 	"github.com/dgraph-io/badger/v2"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
 
@@ -17,7 +18,10 @@ import (
 	builder "github.com/onflow/flow-go/module/builder/collection"
 	"github.com/onflow/flow-go/module/mempool"
 	"github.com/onflow/flow-go/module/mempool/herocache"
+	"github.com/onflow/flow-go/module/mempool/persistent"
+	"github.com/onflow/flow-go/module/mempool/stdmap"
 	"github.com/onflow/flow-go/module/metrics"
+	mockmodule "github.com/onflow/flow-go/module/mock"
 	"github.com/onflow/flow-go/module/trace"
 	"github.com/onflow/flow-go/state/cluster"
 	clusterkv "github.com/onflow/flow-go/state/cluster/badger"
@@ -115,7 +119,7 @@ func (suite *BuilderSuite) SetupTest() {
 		suite.Assert().True(added)
 	}
 
-	suite.builder, _ = builder.NewBuilder(suite.db, tracer, suite.headers, suite.headers, suite.payloads, suite.pool, unittest.Logger())
+	suite.builder, _ = builder.NewBuilder(suite.db, tracer, metrics, suite.headers, suite.headers, suite.payloads, suite.pool, unittest.Logger())
 }
 
 // runs after each test finishes
@@ -223,6 +227,56 @@ func (suite *BuilderSuite) TestBuildOn_Success() {
 	suite.Assert().True(collectionContains(builtCollection, flow.GetIDs(mempoolTransactions)...))
 }
 
+// TestBuildOn_PersistentPoolRestart evaluates that the transactions of a persistent pool survive a crash in the
+// middle of building collections: after the restart, the transactions of the un-finalized collection are not
+// included again on its fork, while they are still available to the other forks.
+func (suite *BuilderSuite) TestBuildOn_PersistentPoolRestart() {
+	newPersistentPool := func() *persistent.Transactions {
+		pool := herocache.NewTransactions(1000, unittest.Logger(), metrics.NewNoopCollector())
+		return persistent.NewTransactions(unittest.Logger(), suite.db, 0, pool, 2000)
+	}
+	pool := newPersistentPool()
+	for _, tx := range suite.pool.All() {
+		suite.Require().True(pool.Add(tx))
+	}
+	suite.builder, _ = builder.NewBuilder(suite.db, trace.NewNoopTracer(), metrics.NewNoopCollector(), suite.headers, suite.headers, suite.payloads, pool, unittest.Logger())
+
+	// build a collection, which is not finalized when the node crashes
+	header, err := suite.builder.BuildOn(suite.genesis.ID(), noopSetter)
+	suite.Require().NoError(err)
+	var built model.Block
+	err = suite.db.View(procedure.RetrieveClusterBlock(header.ID(), &built))
+	suite.Require().NoError(err)
+	suite.Require().Equal(3, built.Payload.Collection.Len())
+	suite.Require().NoError(pool.Flush())
+
+	// restart with an empty pool, and replay the persisted transactions
+	final, err := suite.protoState.Final().Head()
+	suite.Require().NoError(err)
+	restarted := newPersistentPool()
+	replayed, dropped, err := restarted.Replay(suite.headers, final.Height, flow.DefaultTransactionExpiry)
+	suite.Require().NoError(err)
+	suite.Assert().EqualValues(3, replayed)
+	suite.Assert().EqualValues(0, dropped)
+	suite.builder, _ = builder.NewBuilder(suite.db, trace.NewNoopTracer(), metrics.NewNoopCollector(), suite.headers, suite.headers, suite.payloads, restarted, unittest.Logger())
+
+	// the transactions of the un-finalized collection are not included again on its fork
+	header, err = suite.builder.BuildOn(built.ID(), noopSetter)
+	suite.Require().NoError(err)
+	var child model.Block
+	err = suite.db.View(procedure.RetrieveClusterBlock(header.ID(), &child))
+	suite.Require().NoError(err)
+	suite.Assert().Equal(0, child.Payload.Collection.Len())
+
+	// but are still available to the other forks
+	header, err = suite.builder.BuildOn(suite.genesis.ID(), noopSetter)
+	suite.Require().NoError(err)
+	var sibling model.Block
+	err = suite.db.View(procedure.RetrieveClusterBlock(header.ID(), &sibling))
+	suite.Require().NoError(err)
+	suite.Assert().Equal(3, sibling.Payload.Collection.Len())
+}
+
 // when there are transactions with an unknown reference block in the pool, we should not include them in collections
 func (suite *BuilderSuite) TestBuildOn_WithUnknownReferenceBlock() {
 
@@ -468,7 +522,7 @@ func (suite *BuilderSuite) TestBuildOn_LargeHistory() {
 
 	// use a mempool with 2000 transactions, one per block
 	suite.pool = herocache.NewTransactions(2000, unittest.Logger(), metrics.NewNoopCollector())
-	suite.builder, _ = builder.NewBuilder(suite.db, trace.NewNoopTracer(), suite.headers, suite.headers, suite.payloads, suite.pool, unittest.Logger(), builder.WithMaxCollectionSize(10000))
+	suite.builder, _ = builder.NewBuilder(suite.db, trace.NewNoopTracer(), metrics.NewNoopCollector(), suite.headers, suite.headers, suite.payloads, suite.pool, unittest.Logger(), builder.WithMaxCollectionSize(10000))
 
 	// get a valid reference block ID
 	final, err := suite.protoState.Final().Head()
@@ -548,7 +602,7 @@ func (suite *BuilderSuite) TestBuildOn_LargeHistory() {
 
 func (suite *BuilderSuite) TestBuildOn_MaxCollectionSize() {
 	// set the max collection size to 1
-	suite.builder, _ = builder.NewBuilder(suite.db, trace.NewNoopTracer(), suite.headers, suite.headers, suite.payloads, suite.pool, unittest.Logger(), builder.WithMaxCollectionSize(1))
+	suite.builder, _ = builder.NewBuilder(suite.db, trace.NewNoopTracer(), metrics.NewNoopCollector(), suite.headers, suite.headers, suite.payloads, suite.pool, unittest.Logger(), builder.WithMaxCollectionSize(1))
 
 	// build a block
 	header, err := suite.builder.BuildOn(suite.genesis.ID(), noopSetter)
@@ -566,7 +620,7 @@ func (suite *BuilderSuite) TestBuildOn_MaxCollectionSize() {
 
 func (suite *BuilderSuite) TestBuildOn_MaxCollectionByteSize() {
 	// set the max collection byte size to 400 (each tx is about 150 bytes)
-	suite.builder, _ = builder.NewBuilder(suite.db, trace.NewNoopTracer(), suite.headers, suite.headers, suite.payloads, suite.pool, unittest.Logger(), builder.WithMaxCollectionByteSize(400))
+	suite.builder, _ = builder.NewBuilder(suite.db, trace.NewNoopTracer(), metrics.NewNoopCollector(), suite.headers, suite.headers, suite.payloads, suite.pool, unittest.Logger(), builder.WithMaxCollectionByteSize(400))
 
 	// build a block
 	header, err := suite.builder.BuildOn(suite.genesis.ID(), noopSetter)
@@ -584,7 +638,7 @@ func (suite *BuilderSuite) TestBuildOn_MaxCollectionByteSize() {
 
 func (suite *BuilderSuite) TestBuildOn_MaxCollectionTotalGas() {
 	// set the max gas to 20,000
-	suite.builder, _ = builder.NewBuilder(suite.db, trace.NewNoopTracer(), suite.headers, suite.headers, suite.payloads, suite.pool, unittest.Logger(), builder.WithMaxCollectionTotalGas(20000))
+	suite.builder, _ = builder.NewBuilder(suite.db, trace.NewNoopTracer(), metrics.NewNoopCollector(), suite.headers, suite.headers, suite.payloads, suite.pool, unittest.Logger(), builder.WithMaxCollectionTotalGas(20000))
 
 	// build a block
 	header, err := suite.builder.BuildOn(suite.genesis.ID(), noopSetter)
@@ -600,6 +654,58 @@ func (suite *BuilderSuite) TestBuildOn_MaxCollectionTotalGas() {
 	suite.Assert().Equal(builtCollection.Len(), 2)
 }
 
+// TestBuildOn_PrioritySelection evaluates that the transactions are selected by decreasing priority, that the lower
+// priority transactions fitting in the remaining gas of the collection are included, and that the wait time of the
+// selected transactions is reported.
+func (suite *BuilderSuite) TestBuildOn_PrioritySelection() {
+	final, err := suite.protoState.Final().Head()
+	suite.Require().NoError(err)
+
+	pool := stdmap.NewPrioritizedTransactions(10, func(tx *flow.TransactionBody) float64 { return float64(tx.GasLimit) }, 0)
+	gasLimits := []uint64{500, 4000, 1000, 5000}
+	txs := make(map[uint64]flow.Identifier, len(gasLimits))
+	for i, gasLimit := range gasLimits {
+		transaction := unittest.TransactionBodyFixture(func(tx *flow.TransactionBody) {
+			tx.ReferenceBlockID = final.ID()
+			tx.ProposalKey.SequenceNumber = uint64(i)
+			tx.GasLimit = gasLimit
+		})
+		suite.Require().True(pool.Add(&transaction))
+		txs[gasLimit] = transaction.ID()
+	}
+
+	colMetrics := mockmodule.NewCollectionMetrics(suite.T())
+	colMetrics.On("TransactionSelected", mock.Anything).Twice()
+	suite.builder, _ = builder.NewBuilder(suite.db, trace.NewNoopTracer(), colMetrics, suite.headers, suite.headers, suite.payloads, pool, unittest.Logger(),
+		builder.WithTransactionSelection(builder.PrioritySelection),
+		builder.WithMaxCollectionTotalGas(6000),
+	)
+
+	// build a block
+	header, err := suite.builder.BuildOn(suite.genesis.ID(), noopSetter)
+	suite.Require().NoError(err)
+
+	// retrieve the built block from storage
+	var built model.Block
+	err = suite.db.View(procedure.RetrieveClusterBlock(header.ID(), &built))
+	suite.Require().NoError(err)
+
+	// the highest priority transaction is followed by the next transaction fitting in the remaining gas
+	suite.Assert().Equal([]flow.Identifier{txs[5000], txs[1000]}, built.Payload.Collection.Light().Transactions)
+}
+
+// TestNewBuilder_PrioritySelectionRequiresPrioritizedPool evaluates that the priority selection can't be configured
+// with a transaction pool which doesn't order its transactions by priority.
+func (suite *BuilderSuite) TestNewBuilder_PrioritySelectionRequiresPrioritizedPool() {
+	_, err := builder.NewBuilder(suite.db, trace.NewNoopTracer(), metrics.NewNoopCollector(), suite.headers, suite.headers, suite.payloads, suite.pool, unittest.Logger(),
+		builder.WithTransactionSelection(builder.PrioritySelection))
+	suite.Assert().Error(err)
+
+	_, err = builder.NewBuilder(suite.db, trace.NewNoopTracer(), metrics.NewNoopCollector(), suite.headers, suite.headers, suite.payloads, suite.pool, unittest.Logger(),
+		builder.WithTransactionSelection("unknown"))
+	suite.Assert().Error(err)
+}
+
 func (suite *BuilderSuite) TestBuildOn_ExpiredTransaction() {
 
 	// create enough main-chain blocks that an expired transaction is possible
@@ -621,7 +727,7 @@ func (suite *BuilderSuite) TestBuildOn_ExpiredTransaction() {
 
 	// reset the pool and builder
 	suite.pool = herocache.NewTransactions(10, unittest.Logger(), metrics.NewNoopCollector())
-	suite.builder, _ = builder.NewBuilder(suite.db, trace.NewNoopTracer(), suite.headers, suite.headers, suite.payloads, suite.pool, unittest.Logger())
+	suite.builder, _ = builder.NewBuilder(suite.db, trace.NewNoopTracer(), metrics.NewNoopCollector(), suite.headers, suite.headers, suite.payloads, suite.pool, unittest.Logger())
 
 	// insert a transaction referring genesis (now expired)
 	tx1 := unittest.TransactionBodyFixture(func(tx *flow.TransactionBody) {
@@ -663,7 +769,7 @@ func (suite *BuilderSuite) TestBuildOn_EmptyMempool() {
 
 	// start with an empty mempool
 	suite.pool = herocache.NewTransactions(1000, unittest.Logger(), metrics.NewNoopCollector())
-	suite.builder, _ = builder.NewBuilder(suite.db, trace.NewNoopTracer(), suite.headers, suite.headers, suite.payloads, suite.pool, unittest.Logger())
+	suite.builder, _ = builder.NewBuilder(suite.db, trace.NewNoopTracer(), metrics.NewNoopCollector(), suite.headers, suite.headers, suite.payloads, suite.pool, unittest.Logger())
 
 	header, err := suite.builder.BuildOn(suite.genesis.ID(), noopSetter)
 	suite.Require().NoError(err)
@@ -690,7 +796,7 @@ func (suite *BuilderSuite) TestBuildOn_NoRateLimiting() {
 	suite.ClearPool()
 
 	// create builder with no rate limit and max 10 tx/collection
-	suite.builder, _ = builder.NewBuilder(suite.db, trace.NewNoopTracer(), suite.headers, suite.headers, suite.payloads, suite.pool, unittest.Logger(),
+	suite.builder, _ = builder.NewBuilder(suite.db, trace.NewNoopTracer(), metrics.NewNoopCollector(), suite.headers, suite.headers, suite.payloads, suite.pool, unittest.Logger(),
 		builder.WithMaxCollectionSize(10),
 		builder.WithMaxPayerTransactionRate(0),
 	)
@@ -731,7 +837,7 @@ func (suite *BuilderSuite) TestBuildOn_RateLimitNonPayer() {
 	suite.ClearPool()
 
 	// create builder with 5 tx/payer and max 10 tx/collection
-	suite.builder, _ = builder.NewBuilder(suite.db, trace.NewNoopTracer(), suite.headers, suite.headers, suite.payloads, suite.pool, unittest.Logger(),
+	suite.builder, _ = builder.NewBuilder(suite.db, trace.NewNoopTracer(), metrics.NewNoopCollector(), suite.headers, suite.headers, suite.payloads, suite.pool, unittest.Logger(),
 		builder.WithMaxCollectionSize(10),
 		builder.WithMaxPayerTransactionRate(5),
 	)
@@ -775,7 +881,7 @@ func (suite *BuilderSuite) TestBuildOn_HighRateLimit() {
 	suite.ClearPool()
 
 	// create builder with 5 tx/payer and max 10 tx/collection
-	suite.builder, _ = builder.NewBuilder(suite.db, trace.NewNoopTracer(), suite.headers, suite.headers, suite.payloads, suite.pool, unittest.Logger(),
+	suite.builder, _ = builder.NewBuilder(suite.db, trace.NewNoopTracer(), metrics.NewNoopCollector(), suite.headers, suite.headers, suite.payloads, suite.pool, unittest.Logger(),
 		builder.WithMaxCollectionSize(10),
 		builder.WithMaxPayerTransactionRate(5),
 	)
@@ -813,7 +919,7 @@ func (suite *BuilderSuite) TestBuildOn_LowRateLimit() {
 	suite.ClearPool()
 
 	// create builder with .5 tx/payer and max 10 tx/collection
-	suite.builder, _ = builder.NewBuilder(suite.db, trace.NewNoopTracer(), suite.headers, suite.headers, suite.payloads, suite.pool, unittest.Logger(),
+	suite.builder, _ = builder.NewBuilder(suite.db, trace.NewNoopTracer(), metrics.NewNoopCollector(), suite.headers, suite.headers, suite.payloads, suite.pool, unittest.Logger(),
 		builder.WithMaxCollectionSize(10),
 		builder.WithMaxPayerTransactionRate(.5),
 	)
@@ -855,7 +961,7 @@ func (suite *BuilderSuite) TestBuildOn_UnlimitedPayer() {
 	// create builder with 5 tx/payer and max 10 tx/collection
 	// configure an unlimited payer
 	payer := unittest.RandomAddressFixture()
-	suite.builder, _ = builder.NewBuilder(suite.db, trace.NewNoopTracer(), suite.headers, suite.headers, suite.payloads, suite.pool, unittest.Logger(),
+	suite.builder, _ = builder.NewBuilder(suite.db, trace.NewNoopTracer(), metrics.NewNoopCollector(), suite.headers, suite.headers, suite.payloads, suite.pool, unittest.Logger(),
 		builder.WithMaxCollectionSize(10),
 		builder.WithMaxPayerTransactionRate(5),
 		builder.WithUnlimitedPayers(payer),
@@ -896,7 +1002,7 @@ func (suite *BuilderSuite) TestBuildOn_RateLimitDryRun() {
 	// create builder with 5 tx/payer and max 10 tx/collection
 	// configure an unlimited payer
 	payer := unittest.RandomAddressFixture()
-	suite.builder, _ = builder.NewBuilder(suite.db, trace.NewNoopTracer(), suite.headers, suite.headers, suite.payloads, suite.pool, unittest.Logger(),
+	suite.builder, _ = builder.NewBuilder(suite.db, trace.NewNoopTracer(), metrics.NewNoopCollector(), suite.headers, suite.headers, suite.payloads, suite.pool, unittest.Logger(),
 		builder.WithMaxCollectionSize(10),
 		builder.WithMaxPayerTransactionRate(5),
 		builder.WithRateLimitDryRun(true),
@@ -1001,7 +1107,7 @@ func benchmarkBuildOn(b *testing.B, size int) {
 		}
 
 		// create the builder
-		suite.builder, _ = builder.NewBuilder(suite.db, tracer, suite.headers, suite.headers, suite.payloads, suite.pool, unittest.Logger())
+		suite.builder, _ = builder.NewBuilder(suite.db, tracer, metrics, suite.headers, suite.headers, suite.payloads, suite.pool, unittest.Logger())
 	}
 
 	// create a block history to test performance against
@@ -9,6 +9,18 @@ const (
 	DefaultMaxPayerTransactionRate float64 = 0  // no rate limiting
 )
 
+// TransactionSelection is the policy selecting the transactions of the transaction pool to include in a collection.
+type TransactionSelection string
+
+const (
+	// FIFOSelection selects the transactions in the order of the transaction pool, and stops once the collection
+	// reaches its maximum byte size or total gas.
+	FIFOSelection TransactionSelection = "fifo"
+	// PrioritySelection selects the transactions by decreasing priority, and fills the remaining byte size and gas
+	// of the collection with the lower priority transactions fitting in. It requires a prioritized transaction pool.
+	PrioritySelection TransactionSelection = "priority"
+)
+
 // Config is the configurable options for the collection builder.
 type Config struct {
 
@@ -44,6 +56,10 @@ type Config struct {
 
 	// MaxCollectionTotalGas is the maximum of total of gas per collection (sum of maxGasLimit over transactions)
 	MaxCollectionTotalGas uint64
+
+	// TransactionSelection is the policy selecting the transactions to include
+	// in a collection.
+	TransactionSelection TransactionSelection
 }
 
 func DefaultConfig() Config {
@@ -55,6 +71,7 @@ func DefaultConfig() Config {
 		UnlimitedPayers:         make(map[flow.Address]struct{}), // no unlimited payers
 		MaxCollectionByteSize:   flow.DefaultMaxCollectionByteSize,
 		MaxCollectionTotalGas:   flow.DefaultMaxCollectionTotalGas,
+		TransactionSelection:    FIFOSelection,
 	}
 }
 
@@ -108,3 +125,9 @@ func WithMaxCollectionTotalGas(limit uint64) Opt {
 		c.MaxCollectionTotalGas = limit
 	}
 }
+
+func WithTransactionSelection(selection TransactionSelection) Opt {
+	return func(c *Config) {
+		c.TransactionSelection = selection
+	}
+}
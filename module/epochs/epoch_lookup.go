@@ -99,14 +99,45 @@ func (cache *epochRangeCache) add(epoch epochRange) error {
 	return nil
 }
 
+// addRecovery inserts the range of a recovery epoch to the cache. A recovery epoch
+// is committed while epoch fallback is triggered, hence there may be a gap between
+// the latest cached epoch and the recovery epoch. These views are covered by the
+// EECC extension of the latest cached epoch, which we extend accordingly.
+// Adding the same epoch multiple times is a no-op.
+// No errors are expected during normal operation.
+func (cache *epochRangeCache) addRecovery(epoch epochRange) error {
+	latestCachedEpoch := cache.latest()
+	if !latestCachedEpoch.exists() || latestCachedEpoch == epoch {
+		return cache.add(epoch)
+	}
+
+	// sanity check: ensure counters are sequential and views are increasing
+	if epoch.counter != latestCachedEpoch.counter+1 {
+		return fmt.Errorf("non-sequential epoch counters: adding recovery epoch %d when latest cached epoch is %d", epoch.counter, latestCachedEpoch.counter)
+	}
+	if epoch.firstView <= latestCachedEpoch.finalView {
+		return fmt.Errorf("overlapping epoch view ranges: adding recovery range [%d,%d] when latest cached range is [%d,%d]",
+			epoch.firstView, epoch.finalView, latestCachedEpoch.firstView, latestCachedEpoch.finalView)
+	}
+
+	cache[2].finalView = epoch.firstView - 1
+	return cache.add(epoch)
+}
+
+// committedEpoch is the protocol event for a newly committed epoch.
+type committedEpoch struct {
+	first    *flow.Header // first block of the epoch committed phase
+	recovery bool         // whether the epoch was committed while epoch fallback was triggered
+}
+
 // EpochLookup implements the EpochLookup interface using protocol state to match views to epochs.
 type EpochLookup struct {
 	state                    protocol.State
 	mu                       sync.RWMutex
 	epochs                   epochRangeCache
-	committedEpochsCh        chan *flow.Header // protocol events for newly committed epochs (the first block of the epoch is passed over the channel)
-	epochFallbackIsTriggered *atomic.Bool      // true when epoch fallback is triggered
-	events.Noop                                // implements protocol.Consumer
+	committedEpochsCh        chan committedEpoch // protocol events for newly committed epochs
+	epochFallbackIsTriggered *atomic.Bool        // true when epoch fallback is triggered and no recovery epoch is committed yet
+	events.Noop                                  // implements protocol.Consumer
 	component.Component
 }
 
@@ -117,7 +148,7 @@ var _ module.EpochLookup = (*EpochLookup)(nil)
 func NewEpochLookup(state protocol.State) (*EpochLookup, error) {
 	lookup := &EpochLookup{
 		state:                    state,
-		committedEpochsCh:        make(chan *flow.Header, 1),
+		committedEpochsCh:        make(chan committedEpoch, 1),
 		epochFallbackIsTriggered: atomic.NewBool(false),
 	}
 
@@ -133,45 +164,49 @@ func NewEpochLookup(state protocol.State) (*EpochLookup, error) {
 		return nil, fmt.Errorf("could not check previous epoch exists: %w", err)
 	}
 	if exists {
-		err := lookup.cacheEpoch(final.Epochs().Previous())
+		err := lookup.cacheEpoch(final.Epochs().Previous(), false)
 		if err != nil {
 			return nil, fmt.Errorf("could not prepare previous epoch: %w", err)
 		}
 	}
 
 	// we always cache the current epoch
-	err = lookup.cacheEpoch(final.Epochs().Current())
+	// the current epoch may be a recovery epoch, in which case the previous epoch
+	// has been extended by EECC until the current epoch started
+	err = lookup.cacheEpoch(final.Epochs().Current(), exists)
 	if err != nil {
 		return nil, fmt.Errorf("could not prepare current epoch: %w", err)
 	}
 
-	// we cache the next epoch, if it is committed
+	triggered, err := state.Params().EpochFallbackTriggered()
+	if err != nil {
+		return nil, fmt.Errorf("could not check epoch fallback: %w", err)
+	}
 	phase, err := final.Phase()
 	if err != nil {
 		return nil, fmt.Errorf("could not check epoch phase: %w", err)
 	}
+
+	// we cache the next epoch, if it is committed
+	// if epoch fallback was triggered, the next epoch is a recovery epoch
 	if phase == flow.EpochPhaseCommitted {
-		err := lookup.cacheEpoch(final.Epochs().Next())
+		err := lookup.cacheEpoch(final.Epochs().Next(), triggered)
 		if err != nil {
-			return nil, fmt.Errorf("could not prepare previous epoch: %w", err)
+			return nil, fmt.Errorf("could not prepare next epoch: %w", err)
 		}
 	}
 
-	// if epoch fallback was triggered, note it here
-	triggered, err := state.Params().EpochFallbackTriggered()
-	if err != nil {
-		return nil, fmt.Errorf("could not check epoch fallback: %w", err)
-	}
-	if triggered {
-		lookup.epochFallbackIsTriggered.Store(true)
-	}
+	// epoch fallback remains in effect only until a recovery epoch is committed
+	lookup.epochFallbackIsTriggered.Store(triggered && phase != flow.EpochPhaseCommitted)
 
 	return lookup, nil
 }
 
 // cacheEpoch caches the given epoch's view range. Must only be called with committed epochs.
+// If the given epoch is a recovery epoch, the latest cached epoch is extended until
+// the recovery epoch starts.
 // No errors are expected during normal operation.
-func (lookup *EpochLookup) cacheEpoch(epoch protocol.Epoch) error {
+func (lookup *EpochLookup) cacheEpoch(epoch protocol.Epoch, isRecovery bool) error {
 	counter, err := epoch.Counter()
 	if err != nil {
 		return err
@@ -192,7 +227,11 @@ func (lookup *EpochLookup) cacheEpoch(epoch protocol.Epoch) error {
 	}
 
 	lookup.mu.Lock()
-	err = lookup.epochs.add(cachedEpoch)
+	if isRecovery {
+		err = lookup.epochs.addRecovery(cachedEpoch)
+	} else {
+		err = lookup.epochs.add(cachedEpoch)
+	}
 	lookup.mu.Unlock()
 	if err != nil {
 		return fmt.Errorf("could not add epoch %d: %w", counter, err)
@@ -260,19 +299,26 @@ func (lookup *EpochLookup) handleProtocolEvents(ctx irrecoverable.SignalerContex
 		select {
 		case <-ctx.Done():
 			return
-		case block := <-lookup.committedEpochsCh:
-			epoch := lookup.state.AtBlockID(block.ID()).Epochs().Next()
-			err := lookup.cacheEpoch(epoch)
+		case committed := <-lookup.committedEpochsCh:
+			epoch := lookup.state.AtBlockID(committed.first.ID()).Epochs().Next()
+			err := lookup.cacheEpoch(epoch, committed.recovery)
 			if err != nil {
 				ctx.Throw(err)
 			}
+			// a committed recovery epoch ends epoch fallback
+			if committed.recovery {
+				lookup.epochFallbackIsTriggered.Store(false)
+			}
 		}
 	}
 }
 
 // EpochCommittedPhaseStarted informs the `committee.Consensus` that the block starting the Epoch Committed Phase has been finalized.
 func (lookup *EpochLookup) EpochCommittedPhaseStarted(_ uint64, first *flow.Header) {
-	lookup.committedEpochsCh <- first
+	lookup.committedEpochsCh <- committedEpoch{
+		first:    first,
+		recovery: lookup.epochFallbackIsTriggered.Load(),
+	}
 }
 
 // EpochEmergencyFallbackTriggered passes the protocol event to the worker thread.
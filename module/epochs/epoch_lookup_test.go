@@ -237,12 +237,85 @@ func (suite *EpochLookupSuite) TestProtocolEvents_CommittedEpoch() {
 	testEpochForViewWithFallback(suite.T(), suite.lookup, suite.state, suite.currEpoch, suite.nextEpoch)
 }
 
+// TestProtocolEvents_RecoveryEpoch tests correct processing of an `EpochCommittedPhaseStarted`
+// event for a recovery epoch, which is committed after epoch fallback was triggered.
+// The views between the current and the recovery epoch belong to the current epoch.
+func (suite *EpochLookupSuite) TestProtocolEvents_RecoveryEpoch() {
+	// initially, only current epoch is committed, and epoch fallback is triggered
+	suite.WithLock(func() {
+		suite.epochFallbackTriggered = true
+	})
+	suite.CommitEpochs(suite.currEpoch)
+	suite.CreateAndStartEpochLookup()
+
+	// commit the recovery epoch, and emit a protocol event
+	recoveryEpoch := epochRange{counter: suite.currentEpochCounter + 1, firstView: 400, finalView: 499}
+	firstBlockOfCommittedPhase := unittest.BlockHeaderFixture()
+	suite.state.On("AtBlockID", firstBlockOfCommittedPhase.ID()).Return(suite.snapshot)
+	suite.CommitEpochs(recoveryEpoch)
+	suite.lookup.EpochCommittedPhaseStarted(suite.currentEpochCounter, firstBlockOfCommittedPhase)
+
+	// wait for the protocol event to be processed (async)
+	assert.Eventually(suite.T(), func() bool {
+		counter, err := suite.lookup.EpochForViewWithFallback(recoveryEpoch.firstView)
+		return err == nil && counter == recoveryEpoch.counter
+	}, 5*time.Second, 50*time.Millisecond)
+
+	// the current epoch is extended until the recovery epoch starts
+	extendedEpoch := suite.currEpoch
+	extendedEpoch.finalView = recoveryEpoch.firstView - 1
+	testEpochForViewWithFallback(suite.T(), suite.lookup, suite.state, extendedEpoch, recoveryEpoch)
+
+	// should handle multiple deliveries of the protocol event
+	suite.lookup.EpochCommittedPhaseStarted(suite.currentEpochCounter, firstBlockOfCommittedPhase)
+	suite.lookup.EpochCommittedPhaseStarted(suite.currentEpochCounter, firstBlockOfCommittedPhase)
+	testEpochForViewWithFallback(suite.T(), suite.lookup, suite.state, extendedEpoch, recoveryEpoch)
+
+	// epoch fallback ends with the recovery epoch: views beyond it belong to an unknown epoch
+	assert.False(suite.T(), suite.lookup.epochFallbackIsTriggered.Load())
+	_, err := suite.lookup.EpochForViewWithFallback(recoveryEpoch.finalView + 1)
+	assert.ErrorIs(suite.T(), err, model.ErrViewForUnknownEpoch)
+}
+
+// TestEpochForViewWithFallback_CommittedRecovery tests constructing and subsequently querying
+// EpochLookup with an initial state of epoch fallback triggered and a committed recovery epoch.
+// The current epoch is extended until the recovery epoch starts, and epoch fallback is no longer in effect.
+func (suite *EpochLookupSuite) TestEpochForViewWithFallback_CommittedRecovery() {
+	recoveryEpoch := epochRange{counter: suite.currentEpochCounter + 1, firstView: 400, finalView: 499}
+	suite.WithLock(func() {
+		suite.epochFallbackTriggered = true
+	})
+	suite.CommitEpochs(suite.prevEpoch, suite.currEpoch, recoveryEpoch)
+	suite.CreateAndStartEpochLookup()
+
+	extendedEpoch := suite.currEpoch
+	extendedEpoch.finalView = recoveryEpoch.firstView - 1
+	testEpochForViewWithFallback(suite.T(), suite.lookup, suite.state, suite.prevEpoch, extendedEpoch, recoveryEpoch)
+}
+
+// TestEpochForViewWithFallback_PrevRecovery tests constructing and subsequently querying
+// EpochLookup with an initial state of a previous epoch and a current recovery epoch,
+// where the previous epoch was extended by epoch fallback until the recovery epoch started.
+func (suite *EpochLookupSuite) TestEpochForViewWithFallback_PrevRecovery() {
+	recoveryEpoch := epochRange{counter: suite.currentEpochCounter, firstView: 300, finalView: 399}
+	suite.CommitEpochs(suite.prevEpoch, recoveryEpoch)
+	suite.CreateAndStartEpochLookup()
+
+	extendedEpoch := suite.prevEpoch
+	extendedEpoch.finalView = recoveryEpoch.firstView - 1
+	testEpochForViewWithFallback(suite.T(), suite.lookup, suite.state, extendedEpoch, recoveryEpoch)
+}
+
 // testEpochForViewWithFallback accepts a constructed EpochLookup and state, and
 // validates correctness by issuing various queries, using the input state and
 // epochs as source of truth.
 func testEpochForViewWithFallback(t *testing.T, lookup *EpochLookup, state protocol.State, epochs ...epochRange) {
 	epochFallbackTriggered, err := state.Params().EpochFallbackTriggered()
 	require.NoError(t, err)
+	phase, err := state.Final().Phase()
+	require.NoError(t, err)
+	// a committed next epoch while epoch fallback is triggered is a recovery epoch, which ends epoch fallback
+	epochFallbackTriggered = epochFallbackTriggered && phase != flow.EpochPhaseCommitted
 
 	t.Run("should have set epoch fallback triggered correctly", func(t *testing.T) {
 		assert.Equal(t, epochFallbackTriggered, lookup.epochFallbackIsTriggered.Load())
@@ -38,6 +38,10 @@ type JobConsumer interface {
 	// LastProcessedIndex returns the last processed job index
 	LastProcessedIndex() uint64
 
+	// FastForward moves the processed index forward to the given index, skipping the jobs up to and
+	// including it. It returns an error if the index is not beyond the processed index, or has no job.
+	FastForward(index uint64) error
+
 	// NotifyJobIsDone let the consumer know a job has been finished, so that consumer will take
 	// the next job from the job queue if there are workers available. It returns the last processed job index.
 	NotifyJobIsDone(JobID) uint64
@@ -43,6 +43,8 @@ type Consumer struct {
 	processings      map[uint64]*jobStatus   // keep track of the status of each on going job
 	processingsIndex map[module.JobID]uint64 // lookup the index of the job, useful when fast forwarding the
 	// `processed` variable
+	skippedProcessings map[module.JobID]struct{} // jobs still being processed at or below the processed index after a
+	// fast forward, which count towards the max number of jobs processed concurrently until they are done
 }
 
 func NewConsumer(
@@ -66,11 +68,12 @@ func NewConsumer(
 		maxSearchAhead: maxSearchAhead,
 
 		// init state variables
-		running:          false,
-		isChecking:       atomic.NewBool(false),
-		processedIndex:   0,
-		processings:      make(map[uint64]*jobStatus),
-		processingsIndex: make(map[module.JobID]uint64),
+		running:            false,
+		isChecking:         atomic.NewBool(false),
+		processedIndex:     0,
+		processings:        make(map[uint64]*jobStatus),
+		processingsIndex:   make(map[module.JobID]uint64),
+		skippedProcessings: make(map[module.JobID]struct{}),
 	}
 }
 
@@ -147,6 +150,52 @@ func (c *Consumer) LastProcessedIndex() uint64 {
 	return c.processedIndex
 }
 
+// FastForward moves the processed index of the consumer forward to the given index, so that the jobs up to
+// and including the index are skipped and never given to the workers. Jobs that are still being processed at
+// or below the index are allowed to finish, and they no longer hold back the processed index, but they still
+// count towards the max number of jobs processed concurrently until they are done.
+// The new processed index is persisted before the consumer checks for the next processable jobs.
+// No errors are expected during normal operation, if the index is not beyond the current processed index,
+// or there is no job at the index yet, an error is returned and the processed index is unchanged.
+func (c *Consumer) FastForward(index uint64) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if index <= c.processedIndex {
+		return fmt.Errorf("can not fast forward to index %v, which is not beyond the processed index %v", index, c.processedIndex)
+	}
+
+	_, err := c.jobs.AtIndex(index)
+	if err != nil {
+		return fmt.Errorf("can not fast forward to index %v, which has no job: %w", index, err)
+	}
+
+	err = c.progress.SetProcessedIndex(index)
+	if err != nil {
+		return fmt.Errorf("could not set processed index %v, %w", index, err)
+	}
+
+	for i, status := range c.processings {
+		if i > index {
+			continue
+		}
+		delete(c.processings, i)
+		delete(c.processingsIndex, status.jobID)
+		if !status.done {
+			c.skippedProcessings[status.jobID] = struct{}{}
+		}
+	}
+
+	c.log.Warn().
+		Uint64("processed_from", c.processedIndex).
+		Uint64("processed_to", index).
+		Msg("processed index fast forwarded")
+	c.processedIndex = index
+
+	c.checkProcessable()
+	return nil
+}
+
 // NotifyJobIsDone let the consumer know a job has been finished, so that consumer will take
 // the next job from the job queue if there are workers available. It returns the last processed job index.
 func (c *Consumer) NotifyJobIsDone(jobID module.JobID) uint64 {
@@ -257,10 +306,16 @@ func (c *Consumer) run() (int64, error) {
 }
 
 func (c *Consumer) processableJobs() ([]*jobAtIndex, uint64, error) {
+	// the skipped jobs which are still being processed take up processing capacity
+	maxProcessing := uint64(0)
+	if skipped := uint64(len(c.skippedProcessings)); skipped < c.maxProcessing {
+		maxProcessing = c.maxProcessing - skipped
+	}
+
 	processables, processedTo, err := processableJobs(
 		c.jobs,
 		c.processings,
-		c.maxProcessing,
+		maxProcessing,
 		c.maxSearchAhead,
 		c.processedIndex,
 	)
@@ -347,6 +402,12 @@ func processableJobs(jobs module.Jobs, processings map[uint64]*jobStatus, maxPro
 // return false if the job is already finished, or removed
 func (c *Consumer) doneJob(jobID module.JobID) bool {
 	// lock
+	if _, ok := c.skippedProcessings[jobID]; ok {
+		// job was skipped by a fast forward, it no longer takes up processing capacity
+		delete(c.skippedProcessings, jobID)
+		return true
+	}
+
 	index, ok := c.processingsIndex[jobID]
 	if !ok {
 		// job must has been processed
@@ -81,6 +81,10 @@ func TestConsumer(t *testing.T) {
 
 	t.Run("testMovingProcessedIndex", testMovingProcessedIndex)
 
+	// [+1, +2, +3, +4, +5, +6, FastForward(5)] => [0#, 1!, 2!, 3!, 4, 5#, 6!]
+	// when the processed index is fast forwarded, the skipped jobs are not processed, and the next job is processed
+	t.Run("testFastForwardProcessedIndex", testFastForwardProcessedIndex)
+
 	// [+1, +2, +3, +4, Stop, 2*] => [0#, 1!, 2*, 3!, 4]
 	// when Stop is called, it won't work on any job any more
 	t.Run("testStopRunning", testStopRunning)
@@ -482,6 +486,41 @@ func testWorkOnNextAfterFastforward(t *testing.T) {
 	})
 }
 
+// [+1, +2, +3, +4, +5, +6, FastForward(5), 1*] => [0#, 1*, 2!, 3!, 4, 5#, 6!]
+// when the processed index is fast forwarded, the skipped jobs are not processed, and the next job is processed
+// once a skipped job still being processed is finished
+func testFastForwardProcessedIndex(t *testing.T) {
+	runWith(t, func(c module.JobConsumer, cp storage.ConsumerProgress, w *mockWorker, j *jobqueue.MockJobs, db *badgerdb.DB) {
+		require.NoError(t, c.Start(DefaultIndex))
+		require.NoError(t, j.PushN(6))
+		c.Check()
+
+		time.Sleep(1 * time.Millisecond)
+		w.AssertCalled(t, []int64{1, 2, 3})
+
+		// can not fast forward backwards, nor beyond the last job
+		require.Error(t, c.FastForward(0))
+		require.Error(t, c.FastForward(7))
+		assertProcessed(t, cp, 0)
+
+		require.NoError(t, c.FastForward(5))
+		assertProcessed(t, cp, 5)
+		require.Equal(t, uint64(5), c.LastProcessedIndex())
+
+		// the skipped jobs still being processed take up the processing capacity
+		time.Sleep(1 * time.Millisecond)
+		w.AssertCalled(t, []int64{1, 2, 3})
+
+		// the skipped jobs are no longer holding back the processed index
+		c.NotifyJobIsDone(jobqueue.JobIDAtIndex(1))
+		c.NotifyJobIsDone(jobqueue.JobIDAtIndex(6))
+
+		time.Sleep(1 * time.Millisecond)
+		w.AssertCalled(t, []int64{1, 2, 3, 6})
+		assertProcessed(t, cp, 6)
+	})
+}
+
 // [+1, +2, +3, +4, Stop, 2*] => [0#, 1!, 2*, 3!, 4]
 // when Stop is called, it won't work on any job any more
 func testStopRunning(t *testing.T) {
@@ -0,0 +1,79 @@
+package persistent
+
+import (
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	"github.com/onflow/flow-go/module/component"
+	"github.com/onflow/flow-go/module/irrecoverable"
+)
+
+// DefaultFlushInterval is the default interval between two flushes of the persisted memory pools.
+const DefaultFlushInterval = 100 * time.Millisecond
+
+// Flusher is a component flushing the pending writes of the persisted memory pools to the database periodically, and
+// once more when it shuts down. As the memory pools are created lazily, e.g., when an epoch starts, they are
+// registered with the flusher once created.
+type Flusher struct {
+	component.Component
+	log      zerolog.Logger
+	interval time.Duration
+
+	mu    sync.Mutex
+	pools []*Transactions
+}
+
+// NewFlusher creates a component flushing the registered memory pools at the given interval.
+func NewFlusher(log zerolog.Logger, interval time.Duration) *Flusher {
+	f := &Flusher{
+		log:      log.With().Str("component", "persistent_transactions_flusher").Logger(),
+		interval: interval,
+	}
+	f.Component = component.NewComponentManagerBuilder().
+		AddWorker(f.flushLoop).
+		Build()
+	return f
+}
+
+// Register registers a memory pool to flush.
+func (f *Flusher) Register(pool *Transactions) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.pools = append(f.pools, pool)
+}
+
+// flushLoop flushes the registered memory pools at every interval, until the component shuts down.
+func (f *Flusher) flushLoop(ctx irrecoverable.SignalerContext, ready component.ReadyFunc) {
+	ready()
+
+	ticker := time.NewTicker(f.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			// flush the writes queued since the last interval before shutting down
+			f.flush()
+			return
+		case <-ticker.C:
+			f.flush()
+		}
+	}
+}
+
+// flush flushes the registered memory pools. Failures are logged, as they don't prevent the memory pools from
+// operating in memory.
+func (f *Flusher) flush() {
+	f.mu.Lock()
+	pools := make([]*Transactions, len(f.pools))
+	copy(pools, f.pools)
+	f.mu.Unlock()
+
+	for _, pool := range pools {
+		err := pool.Flush()
+		if err != nil {
+			f.log.Error().Err(err).Uint64("epoch", pool.epoch).Msg("could not flush persisted transactions")
+		}
+	}
+}
@@ -0,0 +1,281 @@
+// Package persistent implements memory pools persisting their content in the database, so that it survives restarts.
+package persistent
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/dgraph-io/badger/v2"
+	"github.com/rs/zerolog"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/module/mempool"
+	"github.com/onflow/flow-go/storage"
+	"github.com/onflow/flow-go/storage/badger/operation"
+)
+
+// Transactions is a transaction memory pool persisting the transactions of a wrapped memory pool in the database,
+// so that they can be replayed into the memory pool after a restart.
+//
+// The memory pool operations never wait for the database: the additions and removals of the wrapped memory pool are
+// queued, and written to the database in a single batch by Flush, which is called periodically by a Flusher. Hence the
+// transactions added during the last flush interval before a crash are not replayed after the restart. As the wrapped
+// memory pool may eject transactions without notice, the persisted transactions which are not in the wrapped memory
+// pool anymore are pruned by Flush once the number of persisted transactions reaches the limit. The limit should be
+// well above the limit of the wrapped memory pool, so that the pruning cost is amortized over many additions.
+//
+// Failures of the database are logged, and don't prevent the memory pool from operating in memory.
+type Transactions struct {
+	log   zerolog.Logger
+	db    *badger.DB
+	epoch uint64
+	pool  mempool.Transactions
+	limit uint
+
+	mu      sync.Mutex
+	queue   map[flow.Identifier]*flow.TransactionBody // pending writes: the transaction to persist, or nil to remove it
+	cleared bool                                      // whether the persisted transactions are pending removal
+
+	flushMu   sync.Mutex // serializes the flushes, and guards the persisted transactions
+	persisted map[flow.Identifier]struct{}
+}
+
+var _ mempool.Transactions = (*Transactions)(nil)
+
+// NewTransactions creates a memory pool persisting the transactions of the wrapped memory pool of the epoch in the
+// database, and keeping at most limit transactions in the database.
+func NewTransactions(log zerolog.Logger, db *badger.DB, epoch uint64, pool mempool.Transactions, limit uint) *Transactions {
+	return &Transactions{
+		log:       log.With().Str("component", "persistent_transactions").Uint64("epoch", epoch).Logger(),
+		db:        db,
+		epoch:     epoch,
+		pool:      pool,
+		limit:     limit,
+		queue:     make(map[flow.Identifier]*flow.TransactionBody),
+		persisted: make(map[flow.Identifier]struct{}),
+	}
+}
+
+// Replay adds the persisted transactions to the wrapped memory pool. The transactions with an unknown reference block,
+// or a reference block more than expiry blocks below the finalized height, are removed from the database.
+// It returns the number of replayed transactions, and the number of dropped transactions.
+// No errors are expected during normal operation.
+func (t *Transactions) Replay(headers storage.Headers, finalizedHeight uint64, expiry uint64) (uint, uint, error) {
+	var txs []*flow.TransactionBody
+	err := t.db.View(operation.RetrieveMempoolTransactions(t.epoch, &txs))
+	if err != nil {
+		return 0, 0, fmt.Errorf("could not retrieve persisted transactions: %w", err)
+	}
+
+	t.flushMu.Lock()
+	defer t.flushMu.Unlock()
+
+	replayed, dropped := uint(0), uint(0)
+	for _, tx := range txs {
+		txID := tx.ID()
+		ref, err := headers.ByBlockID(tx.ReferenceBlockID)
+		if err != nil && !errors.Is(err, storage.ErrNotFound) {
+			return replayed, dropped, fmt.Errorf("could not retrieve reference block of transaction %x: %w", txID, err)
+		}
+		if errors.Is(err, storage.ErrNotFound) || ref.Height+expiry < finalizedHeight || !t.pool.Add(tx) {
+			// the transaction references an unknown block, is expired, or the memory pool has no capacity
+			err = t.db.Update(operation.RemoveMempoolTransaction(t.epoch, txID))
+			if err != nil {
+				return replayed, dropped, fmt.Errorf("could not remove dropped transaction %x: %w", txID, err)
+			}
+			dropped++
+			continue
+		}
+		t.persisted[txID] = struct{}{}
+		replayed++
+	}
+	return replayed, dropped, nil
+}
+
+// Has checks whether the transaction with the given hash is currently in the memory pool.
+func (t *Transactions) Has(id flow.Identifier) bool {
+	return t.pool.Has(id)
+}
+
+// Add adds the transaction to the wrapped memory pool, and queues it for persistence. It returns false if the wrapped
+// memory pool rejected the transaction.
+func (t *Transactions) Add(tx *flow.TransactionBody) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	added := t.pool.Add(tx)
+	if added {
+		t.queue[tx.ID()] = tx
+	}
+	return added
+}
+
+// Remove removes the transaction with the given ID from the wrapped memory pool, and queues its removal from the
+// database.
+func (t *Transactions) Remove(id flow.Identifier) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	removed := t.pool.Remove(id)
+	t.queue[id] = nil
+	return removed
+}
+
+// ByID retrieves the transaction with the given ID from the memory pool.
+func (t *Transactions) ByID(id flow.Identifier) (*flow.TransactionBody, bool) {
+	return t.pool.ByID(id)
+}
+
+// Size will return the current size of the memory pool.
+func (t *Transactions) Size() uint {
+	return t.pool.Size()
+}
+
+// All will retrieve all transactions that are currently in the memory pool.
+func (t *Transactions) All() []*flow.TransactionBody {
+	return t.pool.All()
+}
+
+// Clear removes all transactions from the memory pool, and queues their removal from the database.
+func (t *Transactions) Clear() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.pool.Clear()
+	t.queue = make(map[flow.Identifier]*flow.TransactionBody)
+	t.cleared = true
+}
+
+// Flush writes the queued additions and removals to the database in a single batch, after pruning the persisted
+// transactions which were ejected from the wrapped memory pool if the database reached the limit. The memory pool
+// operations are not blocked while the batch is written.
+// No errors are expected during normal operation.
+func (t *Transactions) Flush() error {
+	t.flushMu.Lock()
+	defer t.flushMu.Unlock()
+
+	t.mu.Lock()
+	queue, cleared := t.queue, t.cleared
+	t.queue = make(map[flow.Identifier]*flow.TransactionBody)
+	t.cleared = false
+	t.mu.Unlock()
+
+	if cleared {
+		err := t.db.Update(operation.RemoveMempoolTransactions(t.epoch))
+		if err != nil {
+			return fmt.Errorf("could not remove persisted transactions: %w", err)
+		}
+		t.persisted = make(map[flow.Identifier]struct{})
+	}
+	if len(queue) == 0 {
+		return nil
+	}
+
+	additions := 0
+	for txID, tx := range queue {
+		if _, ok := t.persisted[txID]; tx != nil && !ok {
+			additions++
+		}
+	}
+	if uint(len(t.persisted)+additions) > t.limit {
+		t.prune(queue)
+	}
+
+	batch := t.db.NewWriteBatch()
+	defer batch.Cancel()
+
+	// write the removals first, so that they make room for the additions
+	var added, removed []flow.Identifier
+	for txID, tx := range queue {
+		if _, ok := t.persisted[txID]; tx != nil || !ok {
+			continue
+		}
+		err := operation.BatchRemoveMempoolTransaction(t.epoch, txID)(batch)
+		if err != nil {
+			return fmt.Errorf("could not remove persisted transaction %x: %w", txID, err)
+		}
+		removed = append(removed, txID)
+	}
+	persisted := uint(len(t.persisted) - len(removed))
+	for txID, tx := range queue {
+		if _, ok := t.persisted[txID]; tx == nil || ok {
+			continue
+		}
+		if persisted >= t.limit {
+			t.log.Warn().Hex("tx_id", txID[:]).Msg("persisted transactions limit reached, transaction is not persisted")
+			continue
+		}
+		err := operation.BatchUpsertMempoolTransaction(t.epoch, tx)(batch)
+		if err != nil {
+			return fmt.Errorf("could not persist transaction %x: %w", txID, err)
+		}
+		added = append(added, txID)
+		persisted++
+	}
+	err := batch.Flush()
+	if err != nil {
+		return fmt.Errorf("could not flush persisted transactions: %w", err)
+	}
+
+	for _, txID := range removed {
+		delete(t.persisted, txID)
+	}
+	for _, txID := range added {
+		t.persisted[txID] = struct{}{}
+	}
+	return nil
+}
+
+// prune queues the removal of the persisted transactions which were ejected from the wrapped memory pool, and have no
+// pending write. The removals are written along with the other pending writes.
+// CAUTION: must be called with the flush lock held.
+func (t *Transactions) prune(queue map[flow.Identifier]*flow.TransactionBody) {
+	pruned := 0
+	for txID := range t.persisted {
+		if _, ok := queue[txID]; ok {
+			continue
+		}
+		if !t.pool.Has(txID) {
+			queue[txID] = nil
+			pruned++
+		}
+	}
+	t.log.Debug().
+		Int("pruned", pruned).
+		Int("persisted", len(t.persisted)).
+		Msg("pruning ejected transactions from the database")
+}
+
+// PrioritizedTransactions is a prioritized transaction memory pool persisting the transactions of a wrapped
+// prioritized memory pool in the database. See Transactions.
+type PrioritizedTransactions struct {
+	*Transactions
+	pool mempool.PrioritizedTransactions
+}
+
+var _ mempool.PrioritizedTransactions = (*PrioritizedTransactions)(nil)
+
+// NewPrioritizedTransactions creates a memory pool persisting the transactions of the wrapped prioritized memory pool
+// of the epoch in the database, and keeping at most limit transactions in the database.
+func NewPrioritizedTransactions(log zerolog.Logger, db *badger.DB, epoch uint64, pool mempool.PrioritizedTransactions, limit uint) *PrioritizedTransactions {
+	return &PrioritizedTransactions{
+		Transactions: NewTransactions(log, db, epoch, pool, limit),
+		pool:         pool,
+	}
+}
+
+// ByPriority returns an iterator over the transactions that are currently in the memory pool, by decreasing priority.
+func (t *PrioritizedTransactions) ByPriority() mempool.PendingTransactionIterator {
+	return t.pool.ByPriority()
+}
+
+// RemoveExpiredEpochs removes the persisted transactions of the transaction pools of the epochs before the given epoch.
+// No errors are expected during normal operation.
+func RemoveExpiredEpochs(db *badger.DB, epoch uint64) error {
+	err := db.Update(operation.RemoveMempoolTransactionsBeforeEpoch(epoch))
+	if err != nil {
+		return fmt.Errorf("could not remove persisted transactions of epochs before %d: %w", epoch, err)
+	}
+	return nil
+}
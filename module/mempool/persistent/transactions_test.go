@@ -0,0 +1,271 @@
+package persistent_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/dgraph-io/badger/v2"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/module/irrecoverable"
+	"github.com/onflow/flow-go/module/mempool"
+	"github.com/onflow/flow-go/module/mempool/herocache"
+	"github.com/onflow/flow-go/module/mempool/persistent"
+	"github.com/onflow/flow-go/module/mempool/stdmap"
+	"github.com/onflow/flow-go/module/metrics"
+	bstorage "github.com/onflow/flow-go/storage/badger"
+	"github.com/onflow/flow-go/storage/badger/operation"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+const epoch = uint64(1)
+
+// transactionsFixture stores a chain of headers up to the given height, and returns transactions referencing each of
+// the headers of the chain, along with the headers storage.
+func transactionsFixture(t *testing.T, db *badger.DB, height uint64) ([]*flow.TransactionBody, *bstorage.Headers) {
+	headers := bstorage.NewHeaders(metrics.NewNoopCollector(), db)
+	txs := make([]*flow.TransactionBody, 0, height+1)
+	header := unittest.BlockHeaderFixture(unittest.WithHeaderHeight(0))
+	for {
+		require.NoError(t, headers.Store(header))
+		tx := unittest.TransactionBodyFixture(func(tx *flow.TransactionBody) {
+			tx.ReferenceBlockID = header.ID()
+			tx.ProposalKey.SequenceNumber = header.Height
+		})
+		txs = append(txs, &tx)
+		if header.Height == height {
+			return txs, headers
+		}
+		header = unittest.BlockHeaderWithParentFixture(header)
+	}
+}
+
+// newPool creates a herocache transaction pool persisted in the database.
+func newPool(db *badger.DB, limit uint) *persistent.Transactions {
+	pool := herocache.NewTransactions(uint32(limit), unittest.Logger(), metrics.NewNoopCollector())
+	return persistent.NewTransactions(unittest.Logger(), db, epoch, pool, 2*limit)
+}
+
+// TestTransactions_Restart evaluates that the transactions of the pool survive a restart, including in the middle of
+// building collections, i.e., with transactions removed from the pool by the builder or the finalizer and
+// transactions which are not finalized yet, and that expired transactions are dropped at replay.
+func TestTransactions_Restart(t *testing.T) {
+	unittest.RunWithBadgerDB(t, func(db *badger.DB) {
+		txs, headers := transactionsFixture(t, db, 10)
+		pool := newPool(db, 100)
+		for _, tx := range txs {
+			require.True(t, pool.Add(tx))
+		}
+		// duplicates are rejected
+		require.False(t, pool.Add(txs[0]))
+		require.NoError(t, pool.Flush())
+
+		// a collection including the 3 latest transactions is finalized, which removes them from the pool, while
+		// a collection including the 3 next transactions is built but not finalized when the node crashes
+		for _, tx := range txs[8:] {
+			require.True(t, pool.Remove(tx.ID()))
+		}
+		require.NoError(t, pool.Flush())
+
+		// restart with an empty pool: the finalized height is 12, transactions referencing blocks below height 2
+		// expire with an expiry of 10 blocks
+		restarted := newPool(db, 100)
+		replayed, dropped, err := restarted.Replay(headers, 12, 10)
+		require.NoError(t, err)
+		assert.EqualValues(t, 6, replayed)
+		assert.EqualValues(t, 2, dropped)
+		assert.ElementsMatch(t, txs[2:8], restarted.All())
+
+		// the dropped transactions are removed from the database
+		again := newPool(db, 100)
+		replayed, dropped, err = again.Replay(headers, 12, 10)
+		require.NoError(t, err)
+		assert.EqualValues(t, 6, replayed)
+		assert.EqualValues(t, 0, dropped)
+	})
+}
+
+// TestTransactions_UnknownReference evaluates that the transactions referencing unknown blocks are dropped at replay.
+func TestTransactions_UnknownReference(t *testing.T) {
+	unittest.RunWithBadgerDB(t, func(db *badger.DB) {
+		_, headers := transactionsFixture(t, db, 0)
+		pool := newPool(db, 10)
+		tx := unittest.TransactionBodyFixture()
+		require.True(t, pool.Add(&tx))
+		require.NoError(t, pool.Flush())
+
+		replayed, dropped, err := newPool(db, 10).Replay(headers, 0, 10)
+		require.NoError(t, err)
+		assert.EqualValues(t, 0, replayed)
+		assert.EqualValues(t, 1, dropped)
+	})
+}
+
+// TestTransactions_Clear evaluates that clearing the pool removes the persisted transactions.
+func TestTransactions_Clear(t *testing.T) {
+	unittest.RunWithBadgerDB(t, func(db *badger.DB) {
+		txs, headers := transactionsFixture(t, db, 5)
+		pool := newPool(db, 10)
+		for _, tx := range txs {
+			require.True(t, pool.Add(tx))
+		}
+		require.NoError(t, pool.Flush())
+		pool.Clear()
+		assert.EqualValues(t, 0, pool.Size())
+		require.NoError(t, pool.Flush())
+
+		replayed, _, err := newPool(db, 10).Replay(headers, 5, 10)
+		require.NoError(t, err)
+		assert.EqualValues(t, 0, replayed)
+	})
+}
+
+// TestTransactions_BoundedDiskUsage evaluates that the transactions ejected from the wrapped pool are pruned from the
+// database, which never holds more transactions than the limit.
+func TestTransactions_BoundedDiskUsage(t *testing.T) {
+	unittest.RunWithBadgerDB(t, func(db *badger.DB) {
+		txs, headers := transactionsFixture(t, db, 99)
+		// the wrapped pool holds 10 transactions, the database up to 20
+		pool := newPool(db, 10)
+		for _, tx := range txs {
+			pool.Add(tx)
+			require.NoError(t, pool.Flush())
+		}
+		assert.EqualValues(t, 10, pool.Size())
+
+		restarted := newPool(db, 100)
+		replayed, _, err := restarted.Replay(headers, 99, 1000)
+		require.NoError(t, err)
+		assert.LessOrEqual(t, replayed, uint(20))
+		for _, tx := range pool.All() {
+			assert.True(t, restarted.Has(tx.ID()))
+		}
+	})
+}
+
+// TestPrioritizedTransactions evaluates that the persisted prioritized pool preserves the priority order of the
+// wrapped pool, including after a restart.
+func TestPrioritizedTransactions(t *testing.T) {
+	unittest.RunWithBadgerDB(t, func(db *badger.DB) {
+		txs, headers := transactionsFixture(t, db, 3)
+		newPrioritizedPool := func() *persistent.PrioritizedTransactions {
+			return persistent.NewPrioritizedTransactions(unittest.Logger(), db, epoch, stdmap.NewPrioritizedTransactions(10, func(tx *flow.TransactionBody) float64 { return float64(tx.GasLimit) }, 0), 20)
+		}
+		pool := newPrioritizedPool()
+		for i, tx := range txs {
+			tx.GasLimit = uint64(i + 1)
+			require.True(t, pool.Add(tx))
+		}
+		require.NoError(t, pool.Flush())
+		var _ mempool.PrioritizedTransactions = pool
+
+		restarted := newPrioritizedPool()
+		_, _, err := restarted.Replay(headers, 3, 10)
+		require.NoError(t, err)
+		pending := restarted.ByPriority()
+		for i := range txs {
+			tx, ok := pending.Next()
+			require.True(t, ok)
+			assert.Equal(t, txs[len(txs)-1-i].ID(), tx.ID())
+		}
+		_, ok := pending.Next()
+		assert.False(t, ok)
+	})
+}
+
+// TestTransactions_QueuedWrites evaluates that the writes are not persisted until they are flushed, and that the
+// removal of a transaction added since the last flush cancels its persistence.
+func TestTransactions_QueuedWrites(t *testing.T) {
+	unittest.RunWithBadgerDB(t, func(db *badger.DB) {
+		txs, headers := transactionsFixture(t, db, 2)
+		pool := newPool(db, 10)
+		for _, tx := range txs {
+			require.True(t, pool.Add(tx))
+		}
+		require.True(t, pool.Remove(txs[0].ID()))
+
+		replayed, _, err := newPool(db, 10).Replay(headers, 2, 10)
+		require.NoError(t, err)
+		assert.EqualValues(t, 0, replayed)
+
+		require.NoError(t, pool.Flush())
+		restarted := newPool(db, 10)
+		replayed, _, err = restarted.Replay(headers, 2, 10)
+		require.NoError(t, err)
+		assert.EqualValues(t, 2, replayed)
+		assert.ElementsMatch(t, txs[1:], restarted.All())
+	})
+}
+
+// TestFlusher evaluates that the flusher flushes the registered pools periodically, and when it shuts down.
+func TestFlusher(t *testing.T) {
+	unittest.RunWithBadgerDB(t, func(db *badger.DB) {
+		txs, headers := transactionsFixture(t, db, 1)
+		pool := newPool(db, 10)
+		flusher := persistent.NewFlusher(unittest.Logger(), 10*time.Millisecond)
+		flusher.Register(pool)
+		ctx, cancel := context.WithCancel(context.Background())
+		flusher.Start(irrecoverable.NewMockSignalerContext(t, ctx))
+		unittest.RequireCloseBefore(t, flusher.Ready(), time.Second, "flusher not ready")
+
+		require.True(t, pool.Add(txs[0]))
+		require.Eventually(t, func() bool {
+			var persisted []*flow.TransactionBody
+			require.NoError(t, db.View(operation.RetrieveMempoolTransactions(epoch, &persisted)))
+			return len(persisted) == 1
+		}, time.Second, 10*time.Millisecond)
+
+		require.True(t, pool.Add(txs[1]))
+		cancel()
+		unittest.RequireCloseBefore(t, flusher.Done(), time.Second, "flusher not done")
+
+		replayed, _, err := newPool(db, 10).Replay(headers, 1, 10)
+		require.NoError(t, err)
+		assert.EqualValues(t, 2, replayed)
+	})
+}
+
+// BenchmarkTransactions_Add measures the ingestion of transactions into a pool, in memory only and with persistence
+// flushed in the background.
+func BenchmarkTransactions_Add(b *testing.B) {
+	const limit = 100_000
+	txs := make([]*flow.TransactionBody, limit)
+	for i := range txs {
+		tx := unittest.TransactionBodyFixture()
+		tx.ProposalKey.SequenceNumber = uint64(i)
+		txs[i] = &tx
+	}
+
+	b.Run("in memory", func(b *testing.B) {
+		pool := herocache.NewTransactions(limit, unittest.Logger(), metrics.NewNoopCollector())
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			pool.Add(txs[i%limit])
+		}
+	})
+
+	b.Run("persisted", func(b *testing.B) {
+		unittest.RunWithBadgerDB(b, func(db *badger.DB) {
+			pool := newPool(db, limit)
+			flusher := persistent.NewFlusher(zerolog.Nop(), persistent.DefaultFlushInterval)
+			flusher.Register(pool)
+			ctx, cancel := context.WithCancel(context.Background())
+			signalerCtx, _ := irrecoverable.WithSignaler(ctx)
+			flusher.Start(signalerCtx)
+			<-flusher.Ready()
+			defer func() {
+				cancel()
+				<-flusher.Done()
+			}()
+
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				pool.Add(txs[i%limit])
+			}
+		})
+	})
+}
@@ -0,0 +1,283 @@
+package stdmap
+
+import (
+	"bytes"
+	"container/heap"
+	"sync"
+	"time"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/module/mempool"
+)
+
+// DefaultPriorityAgingRate is the default priority gained per second by the transactions waiting in the prioritized
+// transactions memory pool. With ComputeLimitPriority, a transaction overtakes the transactions added 10 seconds after
+// it with a compute limit higher by 10_000, i.e. about the maximum compute limit of a transaction.
+const DefaultPriorityAgingRate = 1_000
+
+// ComputeLimitPriority prioritizes the transactions by their compute limit, i.e. the maximum execution effort their
+// payer commits to pay fees for. The payer must hold a balance covering the inclusion fees and the execution fees of
+// the whole compute limit for the transaction to be executed (see fvm.TransactionPayerBalanceChecker), so a higher
+// priority is backed by a higher balance of the payer.
+// The inclusion effort, the other input of the fees, is not part of the priority, as it is the same for all
+// transactions as of now (see flow.TransactionBody.InclusionEffort).
+func ComputeLimitPriority(tx *flow.TransactionBody) float64 {
+	return float64(tx.GasLimit)
+}
+
+// ConstantPriority gives all the transactions the same priority, i.e., the transactions are ordered by age.
+func ConstantPriority(*flow.TransactionBody) float64 {
+	return 0
+}
+
+// PrioritizedTransactions implements a transactions memory pool ordering its transactions by priority.
+// The priority of a transaction is given by the priority function when it is added, and increases with the time the
+// transaction waits in the memory pool at the aging rate, so that low priority transactions are eventually
+// included. As all the transactions age at the same rate, their order does not change over time: a transaction is
+// ordered by its priority minus the aging rate times the duration between the creation of the pool and its addition.
+//
+// When the memory pool is full, the lowest priority transaction is ejected to make room for a higher priority one,
+// and a transaction with a priority not higher than all the transactions of the pool is rejected. The age of the
+// transactions only matters between transactions of the same priority, the older one ranking higher, so that a full
+// pool of transactions with the same priority rejects new transactions rather than ejecting its oldest ones.
+type PrioritizedTransactions struct {
+	mu        sync.RWMutex
+	limit     uint
+	priority  mempool.TransactionPriorityFunc
+	agingRate float64
+	start     time.Time
+	entries   map[flow.Identifier]*prioritizedTransaction
+	lowest    prioritizedTransactionHeap
+}
+
+var _ mempool.PrioritizedTransactions = (*PrioritizedTransactions)(nil)
+
+// prioritizedTransaction is a transaction of the memory pool along with its ordering key.
+type prioritizedTransaction struct {
+	id    flow.Identifier
+	tx    *flow.TransactionBody
+	added time.Time
+	key   float64
+	index int // index in the heap of lowest priority transactions
+}
+
+// NewPrioritizedTransactions creates a new memory pool for at most limit transactions, prioritized by the priority
+// function and aging at the given rate in priority per second.
+func NewPrioritizedTransactions(limit uint, priority mempool.TransactionPriorityFunc, agingRate float64) *PrioritizedTransactions {
+	return &PrioritizedTransactions{
+		limit:     limit,
+		priority:  priority,
+		agingRate: agingRate,
+		start:     time.Now(),
+		entries:   make(map[flow.Identifier]*prioritizedTransaction),
+	}
+}
+
+// Has checks whether the transaction with the given ID is currently in the memory pool.
+func (p *PrioritizedTransactions) Has(txID flow.Identifier) bool {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	_, ok := p.entries[txID]
+	return ok
+}
+
+// Add adds the transaction to the memory pool. If the memory pool is full, the lowest priority transaction is ejected
+// if the added transaction has a higher priority, otherwise the added transaction is rejected. It returns false if the
+// transaction was already in the memory pool, or if it was rejected.
+func (p *PrioritizedTransactions) Add(tx *flow.TransactionBody) bool {
+	txID := tx.ID()
+	now := time.Now()
+	entry := &prioritizedTransaction{
+		id:    txID,
+		tx:    tx,
+		added: now,
+		key:   p.priority(tx) - p.agingRate*now.Sub(p.start).Seconds(),
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if _, ok := p.entries[txID]; ok {
+		return false
+	}
+	if p.limit == 0 {
+		return false
+	}
+	if uint(len(p.entries)) >= p.limit {
+		lowest := p.lowest[0]
+		if !entry.higherPriority(lowest) {
+			return false
+		}
+		p.remove(lowest)
+	}
+
+	p.entries[txID] = entry
+	heap.Push(&p.lowest, entry)
+	return true
+}
+
+// Remove removes the transaction with the given ID from the memory pool. It returns true if the transaction was known
+// and removed.
+func (p *PrioritizedTransactions) Remove(txID flow.Identifier) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	entry, ok := p.entries[txID]
+	if !ok {
+		return false
+	}
+	p.remove(entry)
+	return true
+}
+
+// remove removes the given transaction of the memory pool.
+// Not concurrency safe; the caller must hold the write lock.
+func (p *PrioritizedTransactions) remove(entry *prioritizedTransaction) {
+	heap.Remove(&p.lowest, entry.index)
+	delete(p.entries, entry.id)
+}
+
+// ByID returns the transaction with the given ID from the memory pool.
+func (p *PrioritizedTransactions) ByID(txID flow.Identifier) (*flow.TransactionBody, bool) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	entry, ok := p.entries[txID]
+	if !ok {
+		return nil, false
+	}
+	return entry.tx, true
+}
+
+// Size returns the number of transactions in the memory pool.
+func (p *PrioritizedTransactions) Size() uint {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return uint(len(p.entries))
+}
+
+// All returns all transactions from the memory pool, ordered by decreasing priority.
+func (p *PrioritizedTransactions) All() []*flow.TransactionBody {
+	txs := make([]*flow.TransactionBody, 0, p.Size())
+	it := p.ByPriority()
+	for pending, ok := it.Next(); ok; pending, ok = it.Next() {
+		txs = append(txs, pending.TransactionBody)
+	}
+	return txs
+}
+
+// ByPriority returns an iterator over the transactions of the memory pool, by decreasing priority. The transactions
+// with the same priority are ordered by age.
+// The iterator works on a snapshot of the memory pool, which is ordered lazily: creating the iterator takes linear time
+// in the size of the memory pool, and each returned transaction takes logarithmic time, so that selecting the highest
+// priority transactions of a large memory pool does not require sorting all of them.
+func (p *PrioritizedTransactions) ByPriority() mempool.PendingTransactionIterator {
+	p.mu.RLock()
+	entries := make(highestPriorityHeap, 0, len(p.entries))
+	for _, entry := range p.entries {
+		entries = append(entries, entry)
+	}
+	p.mu.RUnlock()
+
+	heap.Init(&entries)
+	return &priorityIterator{entries: entries}
+}
+
+// Clear removes all transactions from the memory pool.
+func (p *PrioritizedTransactions) Clear() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.entries = make(map[flow.Identifier]*prioritizedTransaction)
+	p.lowest = nil
+}
+
+// higherPriority returns true if the transaction has a higher priority than the other one. The ties are broken by age,
+// then by ID, which makes the order total.
+func (t *prioritizedTransaction) higherPriority(other *prioritizedTransaction) bool {
+	if t.key != other.key {
+		return t.key > other.key
+	}
+	if !t.added.Equal(other.added) {
+		return t.added.Before(other.added)
+	}
+	return bytes.Compare(t.id[:], other.id[:]) < 0
+}
+
+// prioritizedTransactionHeap is a min-heap of the transactions by priority, implementing heap.Interface.
+type prioritizedTransactionHeap []*prioritizedTransaction
+
+func (h prioritizedTransactionHeap) Len() int {
+	return len(h)
+}
+
+func (h prioritizedTransactionHeap) Less(i, j int) bool {
+	return h[j].higherPriority(h[i])
+}
+
+func (h prioritizedTransactionHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].index = i
+	h[j].index = j
+}
+
+func (h *prioritizedTransactionHeap) Push(x interface{}) {
+	entry := x.(*prioritizedTransaction)
+	entry.index = len(*h)
+	*h = append(*h, entry)
+}
+
+func (h *prioritizedTransactionHeap) Pop() interface{} {
+	old := *h
+	n := len(old)
+	entry := old[n-1]
+	old[n-1] = nil
+	*h = old[:n-1]
+	return entry
+}
+
+// priorityIterator iterates over a snapshot of the transactions of the memory pool by decreasing priority.
+type priorityIterator struct {
+	entries highestPriorityHeap
+}
+
+// Next returns the highest priority transaction not returned yet, or false if all transactions have been returned.
+func (it *priorityIterator) Next() (*mempool.PendingTransaction, bool) {
+	if it.entries.Len() == 0 {
+		return nil, false
+	}
+	entry := heap.Pop(&it.entries).(*prioritizedTransaction)
+	return &mempool.PendingTransaction{
+		TransactionBody: entry.tx,
+		Added:           entry.added,
+	}, true
+}
+
+// highestPriorityHeap is a max-heap of the transactions by priority, implementing heap.Interface. Unlike
+// prioritizedTransactionHeap, it does not track the index of the transactions, as it only holds a snapshot of the
+// transactions of the memory pool.
+type highestPriorityHeap []*prioritizedTransaction
+
+func (h highestPriorityHeap) Len() int {
+	return len(h)
+}
+
+func (h highestPriorityHeap) Less(i, j int) bool {
+	return h[i].higherPriority(h[j])
+}
+
+func (h highestPriorityHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+}
+
+func (h *highestPriorityHeap) Push(x interface{}) {
+	*h = append(*h, x.(*prioritizedTransaction))
+}
+
+func (h *highestPriorityHeap) Pop() interface{} {
+	old := *h
+	n := len(old)
+	entry := old[n-1]
+	old[n-1] = nil
+	*h = old[:n-1]
+	return entry
+}
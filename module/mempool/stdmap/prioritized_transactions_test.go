@@ -0,0 +1,214 @@
+package stdmap_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/module/mempool"
+	"github.com/onflow/flow-go/module/mempool/stdmap"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+// transactionWithGasLimit returns a transaction body fixture with the given gas limit.
+func transactionWithGasLimit(gasLimit uint64) *flow.TransactionBody {
+	tx := unittest.TransactionBodyFixture(func(tx *flow.TransactionBody) {
+		tx.GasLimit = gasLimit
+	})
+	return &tx
+}
+
+// gasLimitPriority prioritizes the transactions by their gas limit, to distinguish the transactions of the tests.
+func gasLimitPriority(tx *flow.TransactionBody) float64 {
+	return float64(tx.GasLimit)
+}
+
+// pendingByPriority returns all the transactions of the given iterator.
+func pendingByPriority(it mempool.PendingTransactionIterator) []*mempool.PendingTransaction {
+	var pending []*mempool.PendingTransaction
+	for tx, ok := it.Next(); ok; tx, ok = it.Next() {
+		pending = append(pending, tx)
+	}
+	return pending
+}
+
+func TestPrioritizedTransactionPool(t *testing.T) {
+	low := transactionWithGasLimit(10)
+	high := transactionWithGasLimit(1000)
+	medium := transactionWithGasLimit(100)
+
+	pool := stdmap.NewPrioritizedTransactions(1000, gasLimitPriority, 0)
+
+	t.Run("should be able to add", func(t *testing.T) {
+		assert.True(t, pool.Add(low))
+		assert.True(t, pool.Add(high))
+		assert.True(t, pool.Add(medium))
+		assert.False(t, pool.Add(medium))
+		assert.EqualValues(t, 3, pool.Size())
+	})
+
+	t.Run("should be able to get by id", func(t *testing.T) {
+		got, exists := pool.ByID(medium.ID())
+		assert.True(t, exists)
+		assert.Equal(t, medium, got)
+		assert.True(t, pool.Has(low.ID()))
+	})
+
+	t.Run("should retrieve all by decreasing priority", func(t *testing.T) {
+		assert.Equal(t, []*flow.TransactionBody{high, medium, low}, pool.All())
+
+		pending := pendingByPriority(pool.ByPriority())
+		require.Len(t, pending, 3)
+		assert.Equal(t, high, pending[0].TransactionBody)
+		assert.Equal(t, medium, pending[1].TransactionBody)
+		assert.Equal(t, low, pending[2].TransactionBody)
+		assert.False(t, pending[0].Added.IsZero())
+	})
+
+	t.Run("should be able to remove", func(t *testing.T) {
+		assert.True(t, pool.Remove(medium.ID()))
+		assert.False(t, pool.Remove(medium.ID()))
+		assert.Equal(t, []*flow.TransactionBody{high, low}, pool.All())
+	})
+
+	t.Run("should be able to clear", func(t *testing.T) {
+		pool.Clear()
+		assert.Equal(t, uint(0), pool.Size())
+		_, ok := pool.ByPriority().Next()
+		assert.False(t, ok)
+	})
+}
+
+// TestPrioritizedTransactionPool_Ejection evaluates that a full pool ejects its lowest priority transaction to make
+// room for a higher priority one.
+func TestPrioritizedTransactionPool_Ejection(t *testing.T) {
+	pool := stdmap.NewPrioritizedTransactions(2, gasLimitPriority, 0)
+	low := transactionWithGasLimit(10)
+	medium := transactionWithGasLimit(100)
+	high := transactionWithGasLimit(1000)
+
+	require.True(t, pool.Add(medium))
+	require.True(t, pool.Add(low))
+
+	require.True(t, pool.Add(high))
+	assert.False(t, pool.Has(low.ID()))
+	assert.Equal(t, []*flow.TransactionBody{high, medium}, pool.All())
+}
+
+// TestPrioritizedTransactionPool_RejectLowPriority evaluates that a full pool rejects a transaction with a lower
+// priority than all its transactions, rather than ejecting one of them.
+func TestPrioritizedTransactionPool_RejectLowPriority(t *testing.T) {
+	pool := stdmap.NewPrioritizedTransactions(2, gasLimitPriority, 0)
+	medium := transactionWithGasLimit(100)
+	high := transactionWithGasLimit(1000)
+	require.True(t, pool.Add(medium))
+	require.True(t, pool.Add(high))
+
+	low := transactionWithGasLimit(10)
+	assert.False(t, pool.Add(low))
+	assert.False(t, pool.Has(low.ID()))
+	assert.EqualValues(t, 2, pool.Size())
+	assert.Equal(t, []*flow.TransactionBody{high, medium}, pool.All())
+
+	// once there is room again, the transaction is accepted
+	require.True(t, pool.Remove(high.ID()))
+	assert.True(t, pool.Add(low))
+	assert.Equal(t, []*flow.TransactionBody{medium, low}, pool.All())
+}
+
+// TestPrioritizedTransactionPool_EjectionConstantPriority evaluates that a full pool of transactions with the same
+// priority keeps its oldest transactions, rejecting the new ones.
+func TestPrioritizedTransactionPool_EjectionConstantPriority(t *testing.T) {
+	pool := stdmap.NewPrioritizedTransactions(3, stdmap.ConstantPriority, 0)
+	txs := make([]*flow.TransactionBody, 0, 5)
+	for i := 0; i < 5; i++ {
+		tx := transactionWithGasLimit(uint64(i))
+		txs = append(txs, tx)
+		assert.Equal(t, i < 3, pool.Add(tx))
+		time.Sleep(time.Millisecond)
+	}
+
+	assert.EqualValues(t, 3, pool.Size())
+	assert.Equal(t, txs[:3], pool.All())
+}
+
+// TestPrioritizedTransactionPool_ComputeLimitPriority evaluates that the transactions are prioritized by their compute
+// limit, and that a full pool ejects the transaction with the lowest compute limit for one with a higher compute limit.
+func TestPrioritizedTransactionPool_ComputeLimitPriority(t *testing.T) {
+	pool := stdmap.NewPrioritizedTransactions(2, stdmap.ComputeLimitPriority, stdmap.DefaultPriorityAgingRate)
+	low := transactionWithGasLimit(100)
+	high := transactionWithGasLimit(9999)
+	medium := transactionWithGasLimit(1000)
+
+	require.True(t, pool.Add(low))
+	require.True(t, pool.Add(high))
+	require.True(t, pool.Add(medium))
+	assert.False(t, pool.Has(low.ID()))
+	assert.Equal(t, []*flow.TransactionBody{high, medium}, pool.All())
+
+	assert.False(t, pool.Add(transactionWithGasLimit(10)))
+}
+
+// TestPrioritizedTransactionPool_Aging evaluates that the transactions with the same priority are ordered by age, and
+// that older transactions overtake higher priority transactions added long enough after them.
+func TestPrioritizedTransactionPool_Aging(t *testing.T) {
+	t.Run("constant priority is fifo", func(t *testing.T) {
+		pool := stdmap.NewPrioritizedTransactions(100, stdmap.ConstantPriority, 0)
+		txs := make([]*flow.TransactionBody, 0, 10)
+		for i := 0; i < 10; i++ {
+			tx := transactionWithGasLimit(uint64(i))
+			txs = append(txs, tx)
+			require.True(t, pool.Add(tx))
+			time.Sleep(time.Millisecond)
+		}
+		assert.Equal(t, txs, pool.All())
+	})
+
+	t.Run("aging", func(t *testing.T) {
+		// a transaction gains 1000 priority per millisecond of waiting
+		pool := stdmap.NewPrioritizedTransactions(100, gasLimitPriority, 1_000_000)
+		old := transactionWithGasLimit(10)
+		require.True(t, pool.Add(old))
+		time.Sleep(10 * time.Millisecond)
+		recent := transactionWithGasLimit(1000)
+		require.True(t, pool.Add(recent))
+
+		assert.Equal(t, []*flow.TransactionBody{old, recent}, pool.All())
+	})
+}
+
+// TestPrioritizedTransactionPool_IterateSnapshot evaluates that the transactions can be removed while iterating by
+// priority, without altering the iteration nor the priority order of the pool.
+func TestPrioritizedTransactionPool_IterateSnapshot(t *testing.T) {
+	pool := stdmap.NewPrioritizedTransactions(3, gasLimitPriority, 0)
+	txs := []*flow.TransactionBody{transactionWithGasLimit(1000), transactionWithGasLimit(100), transactionWithGasLimit(10)}
+	for _, tx := range txs {
+		require.True(t, pool.Add(tx))
+	}
+
+	it := pool.ByPriority()
+	first, ok := it.Next()
+	require.True(t, ok)
+	assert.Equal(t, txs[0], first.TransactionBody)
+	require.True(t, pool.Remove(txs[1].ID()))
+	assert.Equal(t, txs[1:], transactionBodies(pendingByPriority(it)))
+
+	// the pool still ejects its lowest priority transaction once full
+	require.True(t, pool.Add(transactionWithGasLimit(500)))
+	highest := transactionWithGasLimit(5000)
+	require.True(t, pool.Add(highest))
+	assert.False(t, pool.Has(txs[2].ID()))
+	assert.Equal(t, highest, pool.All()[0])
+}
+
+// transactionBodies returns the transaction bodies of the given pending transactions.
+func transactionBodies(pending []*mempool.PendingTransaction) []*flow.TransactionBody {
+	txs := make([]*flow.TransactionBody, 0, len(pending))
+	for _, tx := range pending {
+		txs = append(txs, tx.TransactionBody)
+	}
+	return txs
+}
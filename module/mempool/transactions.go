@@ -3,6 +3,8 @@
 package mempool
 
 import (
+	"time"
+
 	"github.com/onflow/flow-go/model/flow"
 )
 
@@ -35,3 +37,29 @@ type Transactions interface {
 	// Clear removes all transactions from the mempool.
 	Clear()
 }
+
+// TransactionPriorityFunc returns the priority of a transaction, from the signals the transaction carries.
+type TransactionPriorityFunc func(tx *flow.TransactionBody) float64
+
+// PendingTransaction is a transaction waiting in a memory pool for its inclusion in a collection.
+type PendingTransaction struct {
+	*flow.TransactionBody
+	// Added is the time the transaction was added to the memory pool.
+	Added time.Time
+}
+
+// PendingTransactionIterator iterates over the transactions pending in a memory pool.
+type PendingTransactionIterator interface {
+	// Next returns the next transaction, or false if all transactions have been returned.
+	Next() (*PendingTransaction, bool)
+}
+
+// PrioritizedTransactions represents a concurrency-safe memory pool for transactions, which orders its transactions by
+// priority.
+type PrioritizedTransactions interface {
+	Transactions
+
+	// ByPriority returns an iterator over the transactions that are currently
+	// in the memory pool, by decreasing priority.
+	ByPriority() PendingTransactionIterator
+}
@@ -88,6 +88,18 @@ type GossipSubLocalMeshMetrics interface {
 	OnLocalMeshSizeUpdated(topic string, size int)
 }
 
+// GossipSubRpcValidationInspectorMetrics encapsulates the metrics collectors for the validation inspector of the
+// incoming GossipSub RPC control messages.
+type GossipSubRpcValidationInspectorMetrics interface {
+	// OnInvalidControlMessage tracks the number of incoming RPCs rejected by the validation inspector due to an invalid
+	// control message of the given type, e.g., GRAFT, for the given reason, e.g., "invalid_topic".
+	OnInvalidControlMessage(msgType string, reason string)
+
+	// OnIHaveMessagesSampled tracks the number of incoming RPCs whose IHAVE control messages exceeded the safety threshold,
+	// and hence only a random sample of them was validated.
+	OnIHaveMessagesSampled()
+}
+
 // UnicastManagerMetrics unicast manager metrics.
 type UnicastManagerMetrics interface {
 	// OnStreamCreated tracks the overall time it takes to create a stream successfully and the number of retry attempts.
@@ -112,6 +124,7 @@ type GossipSubMetrics interface {
 	GossipSubScoringMetrics
 	GossipSubRouterMetrics
 	GossipSubLocalMeshMetrics
+	GossipSubRpcValidationInspectorMetrics
 }
 
 type LibP2PMetrics interface {
@@ -159,6 +172,10 @@ type NetworkInboundQueueMetrics interface {
 
 	// QueueDuration tracks the time spent by a message with the given priority in the queue
 	QueueDuration(duration time.Duration, priority int)
+
+	// MessageDropped increments the metric tracking the number of messages of senders with the given role dropped by
+	// the queue for the given reason.
+	MessageDropped(senderRole string, reason string)
 }
 
 // NetworkCoreMetrics encapsulates the metrics collectors for the core networking layer functionality.
@@ -195,6 +212,27 @@ type NetworkMetrics interface {
 	LibP2PMetrics
 	NetworkSecurityMetrics
 	NetworkCoreMetrics
+	AlspMetrics
+	NetworkMessageTracingMetrics
+}
+
+// NetworkMessageTracingMetrics encapsulates the metrics collectors for the propagation of the messages traced by
+// their origin.
+type NetworkMessageTracingMetrics interface {
+	// OnTracedMessageReceived tracks the propagation latency of a traced message received on the given channel, i.e.,
+	// the duration between its sending by its origin and its receipt, and whether it was relayed by another peer than
+	// its origin. The number of hops of the message is not known (see network/p2p/tracer.MessageTracer).
+	OnTracedMessageReceived(channel string, latency time.Duration, relayed bool)
+}
+
+// AlspMetrics encapsulates the metrics collectors for the Application Layer Spam Prevention (ALSP) module, which
+// penalizes the nodes reported by the engines for misbehaving, and disallow-lists the nodes with too much penalty.
+type AlspMetrics interface {
+	// OnMisbehaviorReported tracks the number of misbehavior reports of the given type on the given channel.
+	OnMisbehaviorReported(channel string, misbehaviorType string)
+
+	// OnMisbehavingNodesDisallowListed tracks the number of nodes that are currently disallow-listed due to misbehavior.
+	OnMisbehavingNodesDisallowListed(count int)
 }
 
 // EngineMetrics is a generic metrics consumer for node-internal data processing
@@ -230,6 +268,7 @@ type ComplianceMetrics interface {
 	CurrentDKGPhase2FinalView(view uint64)
 	CurrentDKGPhase3FinalView(view uint64)
 	EpochEmergencyFallbackTriggered()
+	EpochEmergencyFallbackExited()
 }
 
 type CleanerMetrics interface {
@@ -324,6 +363,11 @@ type CollectionMetrics interface {
 
 	// ClusterBlockFinalized is called when a collection is finalized.
 	ClusterBlockFinalized(block *cluster.Block)
+
+	// TransactionSelected is called when a transaction is selected from the
+	// transaction pool for inclusion in a proposed collection, with the time
+	// the transaction waited in the pool.
+	TransactionSelected(wait time.Duration)
 }
 
 type ConsensusMetrics interface {
@@ -371,6 +415,15 @@ type VerificationMetrics interface {
 	// at assigner engine. Note that it assumes blocks are coming to assigner engine in strictly increasing order of their height.
 	OnFinalizedBlockArrivedAtAssigner(height uint64)
 
+	// OnSealedHeightObservedAtAssigner sets a gauge that keeps track of the latest sealed height observed by assigner
+	// engine while processing a finalized block. Compared against the last processed block job index of block consumer,
+	// it shows how far the verification node is catching up behind the sealed head.
+	OnSealedHeightObservedAtAssigner(height uint64)
+
+	// OnSealedResultSkippedAtAssigner increments a counter that keeps track of the total number of execution results
+	// skipped by assigner engine, since their executed blocks are sealed beyond the catch-up depth.
+	OnSealedResultSkippedAtAssigner()
+
 	// OnChunksAssignmentDoneAtAssigner increments a counter that keeps track of the total number of assigned chunks to
 	// the verification node.
 	OnChunksAssignmentDoneAtAssigner(chunks int)
@@ -417,6 +470,10 @@ type VerificationMetrics interface {
 	// OnResultApprovalDispatchedInNetwork increments a counter that keeps track of number of result approvals dispatched in the network
 	// by verifier engine.
 	OnResultApprovalDispatchedInNetworkByVerifier()
+
+	// OnChunkFaultReportDispatchedInNetworkByVerifier increments a counter that keeps track of number of chunk fault reports
+	// dispatched in the network by verifier engine.
+	OnChunkFaultReportDispatchedInNetworkByVerifier()
 }
 
 // LedgerMetrics provides an interface to record Ledger Storage metrics.
@@ -476,6 +533,15 @@ type LedgerMetrics interface {
 }
 
 type WALMetrics interface {
+	// ExecutionCheckpointLoadDuration records the time it took to load the checkpoint on startup
+	ExecutionCheckpointLoadDuration(duration time.Duration)
+
+	// ExecutionWALReplayProgress records the number of WAL segments replayed so far, out of the
+	// total number of segments to replay on top of the loaded checkpoint
+	ExecutionWALReplayProgress(replayed int, total int)
+
+	// ExecutionWALReplayDuration records the time it took to replay the WAL segments on startup
+	ExecutionWALReplayDuration(duration time.Duration)
 }
 
 type RateLimitedBlockstoreMetrics interface {
@@ -764,6 +830,23 @@ type ChainSyncMetrics interface {
 	BatchRequested(batch chainsync.Batch)
 }
 
+// ChainSyncPeerMetrics tracks the quality of the peers the chain synchronization
+// engine requests blocks from.
+type ChainSyncPeerMetrics interface {
+	// BlockResponseReceived records the latency of a successful response to a range or batch request.
+	BlockResponseReceived(latency time.Duration)
+
+	// BlockRequestFailed records a range or batch request which a peer did not answer
+	// successfully. The reason is one of "timeout", "empty" or "invalid".
+	BlockRequestFailed(reason string)
+
+	// SyncPeerExcluded records that a peer was temporarily excluded from sync requests.
+	SyncPeerExcluded()
+
+	// ExcludedSyncPeers records the number of peers currently excluded from sync requests.
+	ExcludedSyncPeers(count int)
+}
+
 type DHTMetrics interface {
 	RoutingTablePeerAdded()
 	RoutingTablePeerRemoved()
@@ -0,0 +1,48 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"github.com/onflow/flow-go/module"
+)
+
+// AlspMetrics is a metrics collector for the Application Layer Spam Prevention (ALSP) module.
+type AlspMetrics struct {
+	reportedMisbehaviorCount *prometheus.CounterVec
+	disallowListedNodesGauge prometheus.Gauge
+}
+
+var _ module.AlspMetrics = (*AlspMetrics)(nil)
+
+func NewAlspMetrics(prefix string) *AlspMetrics {
+	return &AlspMetrics{
+		reportedMisbehaviorCount: promauto.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: namespaceNetwork,
+				Subsystem: subsystemAlsp,
+				Name:      prefix + "reported_misbehavior_total",
+				Help:      "number of misbehavior reports sent by the engines on the node",
+			},
+			[]string{LabelChannel, LabelMisbehavior},
+		),
+		disallowListedNodesGauge: promauto.NewGauge(
+			prometheus.GaugeOpts{
+				Namespace: namespaceNetwork,
+				Subsystem: subsystemAlsp,
+				Name:      prefix + "disallow_listed_nodes",
+				Help:      "number of nodes currently disallow-listed due to misbehavior",
+			},
+		),
+	}
+}
+
+// OnMisbehaviorReported tracks the number of misbehavior reports of the given type on the given channel.
+func (a *AlspMetrics) OnMisbehaviorReported(channel string, misbehaviorType string) {
+	a.reportedMisbehaviorCount.WithLabelValues(channel, misbehaviorType).Inc()
+}
+
+// OnMisbehavingNodesDisallowListed tracks the number of nodes that are currently disallow-listed due to misbehavior.
+func (a *AlspMetrics) OnMisbehavingNodesDisallowListed(count int) {
+	a.disallowListedNodesGauge.Set(float64(count))
+}
@@ -5,9 +5,11 @@ import (
 	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
 
 	"github.com/onflow/flow-go/model/chainsync"
 	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/module"
 )
 
 type ChainSyncCollector struct {
@@ -103,3 +105,60 @@ func (c *ChainSyncCollector) RangeRequested(ran chainsync.Range) {
 func (c *ChainSyncCollector) BatchRequested(batch chainsync.Batch) {
 	c.totalIdsRequested.Add(float64(len(batch.BlockIDs)))
 }
+
+// ChainSyncPeerCollector collects metrics on the quality of the peers the chain
+// synchronization engine requests blocks from.
+type ChainSyncPeerCollector struct {
+	responseLatency prometheus.Histogram
+	failedRequests  *prometheus.CounterVec
+	peerExclusions  prometheus.Counter
+	excludedPeers   prometheus.Gauge
+}
+
+var _ module.ChainSyncPeerMetrics = (*ChainSyncPeerCollector)(nil)
+
+func NewChainSyncPeerCollector() *ChainSyncPeerCollector {
+	return &ChainSyncPeerCollector{
+		responseLatency: promauto.NewHistogram(prometheus.HistogramOpts{
+			Name:      "block_response_latency_seconds",
+			Namespace: namespaceChainsync,
+			Subsystem: subsystemSyncPeers,
+			Help:      "the time between sending a range or batch request to a peer and receiving its response in seconds",
+			Buckets:   []float64{.05, .1, .25, .5, 1, 2.5, 5, 10},
+		}),
+		failedRequests: promauto.NewCounterVec(prometheus.CounterOpts{
+			Name:      "block_requests_failed_total",
+			Namespace: namespaceChainsync,
+			Subsystem: subsystemSyncPeers,
+			Help:      "the total number of range and batch requests peers did not answer successfully, by 'timeout', 'empty' or 'invalid' response",
+		}, []string{LabelSyncRequestFailure}),
+		peerExclusions: promauto.NewCounter(prometheus.CounterOpts{
+			Name:      "peer_exclusions_total",
+			Namespace: namespaceChainsync,
+			Subsystem: subsystemSyncPeers,
+			Help:      "the total number of times a peer was temporarily excluded from sync requests",
+		}),
+		excludedPeers: promauto.NewGauge(prometheus.GaugeOpts{
+			Name:      "excluded_peers",
+			Namespace: namespaceChainsync,
+			Subsystem: subsystemSyncPeers,
+			Help:      "the number of peers currently excluded from sync requests",
+		}),
+	}
+}
+
+func (c *ChainSyncPeerCollector) BlockResponseReceived(latency time.Duration) {
+	c.responseLatency.Observe(latency.Seconds())
+}
+
+func (c *ChainSyncPeerCollector) BlockRequestFailed(reason string) {
+	c.failedRequests.With(prometheus.Labels{LabelSyncRequestFailure: reason}).Inc()
+}
+
+func (c *ChainSyncPeerCollector) SyncPeerExcluded() {
+	c.peerExclusions.Inc()
+}
+
+func (c *ChainSyncPeerCollector) ExcludedSyncPeers(count int) {
+	c.excludedPeers.Set(float64(count))
+}
@@ -1,6 +1,8 @@
 package metrics
 
 import (
+	"time"
+
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promauto"
 
@@ -15,6 +17,7 @@ type CollectionCollector struct {
 	finalizedHeight      *prometheus.GaugeVec     // tracks the finalized height
 	proposals            *prometheus.HistogramVec // tracks the number/size of PROPOSED collections
 	guarantees           *prometheus.HistogramVec // counts the number/size of FINALIZED collections
+	transactionWait      prometheus.Histogram     // tracks the time transactions wait in the pool before being selected
 }
 
 func NewCollectionCollector(tracer module.Tracer) *CollectionCollector {
@@ -50,6 +53,14 @@ func NewCollectionCollector(tracer module.Tracer) *CollectionCollector {
 			Name:      "guarantees_size_transactions",
 			Help:      "size/number of guaranteed/finalized collections",
 		}, []string{LabelChain, LabelProposer}),
+
+		transactionWait: promauto.NewHistogram(prometheus.HistogramOpts{
+			Namespace: namespaceCollection,
+			Subsystem: subsystemProposal,
+			Buckets:   []float64{0.1, 0.5, 1, 2, 5, 10, 30, 60, 120},
+			Name:      "transaction_wait_seconds",
+			Help:      "time transactions wait in the transaction pool before being selected for a proposed collection",
+		}),
 	}
 
 	return cc
@@ -88,3 +99,9 @@ func (cc *CollectionCollector) ClusterBlockFinalized(block *cluster.Block) {
 		}).
 		Observe(float64(collection.Len()))
 }
+
+// TransactionSelected tracks the time a transaction waited in the transaction
+// pool before being selected for a proposed collection.
+func (cc *CollectionCollector) TransactionSelected(wait time.Duration) {
+	cc.transactionWait.Observe(wait.Seconds())
+}
@@ -214,3 +214,7 @@ func (cc *ComplianceCollector) CurrentDKGPhase3FinalView(view uint64) {
 func (cc *ComplianceCollector) EpochEmergencyFallbackTriggered() {
 	cc.epochEmergencyFallbackTriggered.Set(float64(1))
 }
+
+func (cc *ComplianceCollector) EpochEmergencyFallbackExited() {
+	cc.epochEmergencyFallbackTriggered.Set(float64(0))
+}
@@ -33,6 +33,10 @@ type ExecutionCollector struct {
 	updatedValuesSize                      prometheus.Gauge
 	updatedDuration                        prometheus.Histogram
 	updatedDurationPerValue                prometheus.Histogram
+	checkpointLoadDuration                 prometheus.Gauge
+	walReplayedSegments                    prometheus.Gauge
+	walSegmentsToReplay                    prometheus.Gauge
+	walReplayDuration                      prometheus.Gauge
 	readValuesNumber                       prometheus.Counter
 	readValuesSize                         prometheus.Gauge
 	readDuration                           prometheus.Histogram
@@ -180,6 +184,34 @@ func NewExecutionCollector(tracer module.Tracer) *ExecutionCollector {
 		Buckets:   []float64{0.05, 0.2, 0.5, 1, 2, 5},
 	})
 
+	checkpointLoadDuration := promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespaceExecution,
+		Subsystem: subsystemWAL,
+		Name:      "checkpoint_load_duration_seconds",
+		Help:      "the duration of loading the checkpoint on startup",
+	})
+
+	walReplayedSegments := promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespaceExecution,
+		Subsystem: subsystemWAL,
+		Name:      "replayed_segments",
+		Help:      "the number of WAL segments replayed on top of the checkpoint on startup",
+	})
+
+	walSegmentsToReplay := promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespaceExecution,
+		Subsystem: subsystemWAL,
+		Name:      "segments_to_replay",
+		Help:      "the total number of WAL segments to replay on top of the checkpoint on startup",
+	})
+
+	walReplayDuration := promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespaceExecution,
+		Subsystem: subsystemWAL,
+		Name:      "replay_duration_seconds",
+		Help:      "the duration of replaying the WAL segments on startup",
+	})
+
 	readValuesNumber := promauto.NewCounter(prometheus.CounterOpts{
 		Namespace: namespaceExecution,
 		Subsystem: subsystemMTrie,
@@ -546,6 +578,10 @@ func NewExecutionCollector(tracer module.Tracer) *ExecutionCollector {
 		updatedValuesSize:                      updatedValuesSize,
 		updatedDuration:                        updatedDuration,
 		updatedDurationPerValue:                updatedDurationPerValue,
+		checkpointLoadDuration:                 checkpointLoadDuration,
+		walReplayedSegments:                    walReplayedSegments,
+		walSegmentsToReplay:                    walSegmentsToReplay,
+		walReplayDuration:                      walReplayDuration,
 		readValuesNumber:                       readValuesNumber,
 		readValuesSize:                         readValuesSize,
 		readDuration:                           readDuration,
@@ -851,6 +887,22 @@ func (ec *ExecutionCollector) UpdateDurationPerItem(duration time.Duration) {
 	ec.updatedDurationPerValue.Observe(duration.Seconds())
 }
 
+// ExecutionCheckpointLoadDuration records the time it took to load the checkpoint on startup
+func (ec *ExecutionCollector) ExecutionCheckpointLoadDuration(duration time.Duration) {
+	ec.checkpointLoadDuration.Set(duration.Seconds())
+}
+
+// ExecutionWALReplayProgress records the number of WAL segments replayed so far, out of the total
+func (ec *ExecutionCollector) ExecutionWALReplayProgress(replayed int, total int) {
+	ec.walReplayedSegments.Set(float64(replayed))
+	ec.walSegmentsToReplay.Set(float64(total))
+}
+
+// ExecutionWALReplayDuration records the time it took to replay the WAL segments on startup
+func (ec *ExecutionCollector) ExecutionWALReplayDuration(duration time.Duration) {
+	ec.walReplayDuration.Set(duration.Seconds())
+}
+
 // ReadValuesNumber accumulates number of read values
 func (ec *ExecutionCollector) ReadValuesNumber(number uint64) {
 	ec.readValuesNumber.Add(float64(number))
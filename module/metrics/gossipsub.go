@@ -177,3 +177,46 @@ var _ module.GossipSubLocalMeshMetrics = (*GossipSubLocalMeshMetrics)(nil)
 func (g *GossipSubLocalMeshMetrics) OnLocalMeshSizeUpdated(topic string, size int) {
 	g.localMeshSize.WithLabelValues(topic).Set(float64(size))
 }
+
+// GossipSubRpcValidationInspectorMetrics is a metrics collector for the validation inspector of the incoming GossipSub
+// RPC control messages.
+type GossipSubRpcValidationInspectorMetrics struct {
+	invalidControlMessageCount *prometheus.CounterVec
+	iHaveSampledCount          prometheus.Counter
+}
+
+var _ module.GossipSubRpcValidationInspectorMetrics = (*GossipSubRpcValidationInspectorMetrics)(nil)
+
+func NewGossipSubRpcValidationInspectorMetrics(prefix string) *GossipSubRpcValidationInspectorMetrics {
+	return &GossipSubRpcValidationInspectorMetrics{
+		invalidControlMessageCount: promauto.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: namespaceNetwork,
+				Subsystem: subsystemGossip,
+				Name:      prefix + "gossipsub_rpc_invalid_control_message_total",
+				Help:      "number of incoming rpc messages rejected due to an invalid control message",
+			},
+			[]string{LabelMessage, LabelInvalidControlMessageReason},
+		),
+		iHaveSampledCount: promauto.NewCounter(
+			prometheus.CounterOpts{
+				Namespace: namespaceNetwork,
+				Subsystem: subsystemGossip,
+				Name:      prefix + "gossipsub_rpc_ihave_sampled_total",
+				Help:      "number of incoming rpc messages whose ihave messages were validated only on a random sample",
+			},
+		),
+	}
+}
+
+// OnInvalidControlMessage tracks the number of incoming RPCs rejected by the validation inspector due to an invalid
+// control message of the given type, e.g., GRAFT, for the given reason, e.g., "invalid_topic".
+func (g *GossipSubRpcValidationInspectorMetrics) OnInvalidControlMessage(msgType string, reason string) {
+	g.invalidControlMessageCount.WithLabelValues(msgType, reason).Inc()
+}
+
+// OnIHaveMessagesSampled tracks the number of incoming RPCs whose IHAVE control messages exceeded the safety threshold,
+// and hence only a random sample of them was validated.
+func (g *GossipSubRpcValidationInspectorMetrics) OnIHaveMessagesSampled() {
+	g.iHaveSampledCount.Inc()
+}
@@ -33,6 +33,7 @@ const (
 	EngineConsensusMessageHub = "consensus_message_hub"
 	EngineConsensusIngestion  = "consensus_ingestion"
 	EngineSealing             = "sealing"
+	EngineChunkFaults         = "chunk_faults"
 	EngineSynchronization     = "sync"
 	// common
 	EngineFollower          = "follower"
@@ -113,11 +114,14 @@ const (
 	MessageTimeoutObject       = "timeout_object"
 	MessageExecutionReceipt    = "receipt"
 	MessageResultApproval      = "approval"
+	MessageChunkFaultReport    = "chunk_fault_report"
 	MessageSyncRequest         = "ping"
 	MessageSyncResponse        = "pong"
 	MessageRangeRequest        = "range"
 	MessageBatchRequest        = "batch"
 	MessageBlockResponse       = "block"
+	MessageSnapshotRequest     = "snapshot_request"
+	MessageSnapshotResponse    = "snapshot_response"
 	MessageSyncedBlocks        = "synced_blocks"
 	MessageSyncedClusterBlock  = "synced_cluster_block"
 	MessageTransaction         = "transaction"
@@ -132,3 +136,12 @@ const ExecutionDataRequestRetryable = "retryable"
 
 const LabelViolationReason = "reason"
 const LabelRateLimitReason = "reason"
+const LabelInvalidControlMessageReason = "reason"
+
+const LabelMisbehavior = "misbehavior"
+
+const LabelRelayed = "relayed"
+
+const LabelQueueDropReason = "reason"
+
+const LabelSyncRequestFailure = "reason"
@@ -0,0 +1,51 @@
+package metrics
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"github.com/onflow/flow-go/module"
+)
+
+// MessageTracingMetrics is a metrics collector for the propagation of the messages traced by their origin.
+type MessageTracingMetrics struct {
+	propagationLatency *prometheus.HistogramVec
+	tracedMessages     *prometheus.CounterVec
+}
+
+var _ module.NetworkMessageTracingMetrics = (*MessageTracingMetrics)(nil)
+
+func NewMessageTracingMetrics(prefix string) *MessageTracingMetrics {
+	return &MessageTracingMetrics{
+		propagationLatency: promauto.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Namespace: namespaceNetwork,
+				Subsystem: subsystemTracing,
+				Name:      prefix + "message_propagation_latency_seconds",
+				Help:      "duration between the sending of a traced message by its origin and its receipt by the node",
+				Buckets:   prometheus.ExponentialBuckets(0.005, 2, 12),
+			},
+			[]string{LabelChannel},
+		),
+		tracedMessages: promauto.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: namespaceNetwork,
+				Subsystem: subsystemTracing,
+				Name:      prefix + "traced_messages_received_total",
+				Help:      "number of traced messages received by the node, by whether they were relayed by another peer than their origin",
+			},
+			[]string{LabelChannel, LabelRelayed},
+		),
+	}
+}
+
+// OnTracedMessageReceived tracks the propagation latency of a traced message received on the given channel, i.e.,
+// the duration between its sending by its origin and its receipt, and whether it was relayed by another peer than its
+// origin.
+func (m *MessageTracingMetrics) OnTracedMessageReceived(channel string, latency time.Duration, relayed bool) {
+	m.propagationLatency.WithLabelValues(channel).Observe(latency.Seconds())
+	m.tracedMessages.WithLabelValues(channel, strconv.FormatBool(relayed)).Inc()
+}
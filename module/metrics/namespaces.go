@@ -26,6 +26,8 @@ const (
 	subsystemBitswap      = "bitswap"
 	subsystemAuth         = "authorization"
 	subsystemRateLimiting = "ratelimit"
+	subsystemAlsp         = "alsp"
+	subsystemTracing      = "tracing"
 )
 
 // Storage subsystems represent the various components of the storage layer.
@@ -63,6 +65,7 @@ const (
 const (
 	subsystemStateStorage      = "state_storage"
 	subsystemMTrie             = "mtrie"
+	subsystemWAL               = "wal"
 	subsystemIngestion         = "ingestion"
 	subsystemRuntime           = "runtime"
 	subsystemProvider          = "provider"
@@ -90,7 +93,8 @@ const (
 
 // module/synchronization core
 const (
-	subsystemSyncCore = "sync_core"
+	subsystemSyncCore  = "sync_core"
+	subsystemSyncPeers = "sync_peers"
 )
 
 // METRIC NAMING GUIDELINES
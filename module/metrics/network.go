@@ -26,11 +26,15 @@ type NetworkCollector struct {
 	*GossipSubMetrics
 	*GossipSubScoreMetrics
 	*GossipSubLocalMeshMetrics
+	*GossipSubRpcValidationInspectorMetrics
+	*AlspMetrics
+	*MessageTracingMetrics
 	outboundMessageSize          *prometheus.HistogramVec
 	inboundMessageSize           *prometheus.HistogramVec
 	duplicateMessagesDropped     *prometheus.CounterVec
 	queueSize                    *prometheus.GaugeVec
 	queueDuration                *prometheus.HistogramVec
+	queueDroppedMessages         *prometheus.CounterVec
 	numMessagesProcessing        *prometheus.GaugeVec
 	numDirectMessagesSending     *prometheus.GaugeVec
 	inboundProcessTime           *prometheus.CounterVec
@@ -74,6 +78,9 @@ func NewNetworkCollector(logger zerolog.Logger, opts ...NetworkCollectorOpt) *Ne
 	nc.GossipSubLocalMeshMetrics = NewGossipSubLocalMeshMetrics(nc.prefix)
 	nc.GossipSubMetrics = NewGossipSubMetrics(nc.prefix)
 	nc.GossipSubScoreMetrics = NewGossipSubScoreMetrics(nc.prefix)
+	nc.GossipSubRpcValidationInspectorMetrics = NewGossipSubRpcValidationInspectorMetrics(nc.prefix)
+	nc.AlspMetrics = NewAlspMetrics(nc.prefix)
+	nc.MessageTracingMetrics = NewMessageTracingMetrics(nc.prefix)
 
 	nc.outboundMessageSize = promauto.NewHistogramVec(
 		prometheus.HistogramOpts{
@@ -169,6 +176,15 @@ func NewNetworkCollector(logger zerolog.Logger, opts ...NetworkCollectorOpt) *Ne
 		}, []string{LabelPriority},
 	)
 
+	nc.queueDroppedMessages = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespaceNetwork,
+			Subsystem: subsystemQueue,
+			Name:      nc.prefix + "message_queue_dropped_total",
+			Help:      "the number of messages dropped by the message receive queue, per sender role",
+		}, []string{LabelNodeRole, LabelQueueDropReason},
+	)
+
 	nc.numMessagesProcessing = promauto.NewGaugeVec(
 		prometheus.GaugeOpts{
 			Namespace: namespaceNetwork,
@@ -271,6 +287,12 @@ func (nc *NetworkCollector) QueueDuration(duration time.Duration, priority int)
 	nc.queueDuration.WithLabelValues(strconv.Itoa(priority)).Observe(duration.Seconds())
 }
 
+// MessageDropped increments the metric tracking the number of messages of senders with the given role dropped by the
+// queue for the given reason. The metric is not labeled by sender, as the number of senders is unbounded.
+func (nc *NetworkCollector) MessageDropped(senderRole string, reason string) {
+	nc.queueDroppedMessages.WithLabelValues(senderRole, reason).Inc()
+}
+
 // MessageProcessingStarted increments the metric tracking the number of messages being processed by the node.
 func (nc *NetworkCollector) MessageProcessingStarted(topic string) {
 	nc.numMessagesProcessing.WithLabelValues(topic).Inc()
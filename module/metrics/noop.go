@@ -52,6 +52,7 @@ func (nc *NoopCollector) NetworkDuplicateMessagesDropped(topic string, messageTy
 func (nc *NoopCollector) MessageAdded(priority int)                                              {}
 func (nc *NoopCollector) MessageRemoved(priority int)                                            {}
 func (nc *NoopCollector) QueueDuration(duration time.Duration, priority int)                     {}
+func (nc *NoopCollector) MessageDropped(string, string)                                          {}
 func (nc *NoopCollector) MessageProcessingStarted(topic string)                                  {}
 func (nc *NoopCollector) MessageProcessingFinished(topic string, duration time.Duration)         {}
 func (nc *NoopCollector) DirectMessageStarted(topic string)                                      {}
@@ -93,6 +94,7 @@ func (nc *NoopCollector) CurrentDKGPhase1FinalView(view uint64)
 func (nc *NoopCollector) CurrentDKGPhase2FinalView(view uint64)                                  {}
 func (nc *NoopCollector) CurrentDKGPhase3FinalView(view uint64)                                  {}
 func (nc *NoopCollector) EpochEmergencyFallbackTriggered()                                       {}
+func (nc *NoopCollector) EpochEmergencyFallbackExited()                                          {}
 func (nc *NoopCollector) CacheEntries(resource string, entries uint)                             {}
 func (nc *NoopCollector) CacheHit(resource string)                                               {}
 func (nc *NoopCollector) CacheNotFound(resource string)                                          {}
@@ -118,6 +120,7 @@ func (nc *NoopCollector) PayloadProductionDuration(duration time.Duration)
 func (nc *NoopCollector) TransactionIngested(txID flow.Identifier)                               {}
 func (nc *NoopCollector) ClusterBlockProposed(*cluster.Block)                                    {}
 func (nc *NoopCollector) ClusterBlockFinalized(*cluster.Block)                                   {}
+func (nc *NoopCollector) TransactionSelected(time.Duration)                                      {}
 func (nc *NoopCollector) StartCollectionToFinalized(collectionID flow.Identifier)                {}
 func (nc *NoopCollector) FinishCollectionToFinalized(collectionID flow.Identifier)               {}
 func (nc *NoopCollector) StartBlockToSeal(blockID flow.Identifier)                               {}
@@ -129,9 +132,12 @@ func (nc *NoopCollector) CheckSealingDuration(duration time.Duration)
 func (nc *NoopCollector) OnExecutionResultReceivedAtAssignerEngine()                             {}
 func (nc *NoopCollector) OnVerifiableChunkReceivedAtVerifierEngine()                             {}
 func (nc *NoopCollector) OnResultApprovalDispatchedInNetworkByVerifier()                         {}
+func (nc *NoopCollector) OnChunkFaultReportDispatchedInNetworkByVerifier()                       {}
 func (nc *NoopCollector) SetMaxChunkDataPackAttemptsForNextUnsealedHeightAtRequester(attempts uint64) {
 }
 func (nc *NoopCollector) OnFinalizedBlockArrivedAtAssigner(height uint64)                       {}
+func (nc *NoopCollector) OnSealedHeightObservedAtAssigner(height uint64)                        {}
+func (nc *NoopCollector) OnSealedResultSkippedAtAssigner()                                      {}
 func (nc *NoopCollector) OnChunksAssignmentDoneAtAssigner(chunks int)                           {}
 func (nc *NoopCollector) OnAssignedChunkProcessedAtAssigner()                                   {}
 func (nc *NoopCollector) OnAssignedChunkReceivedAtFetcher()                                     {}
@@ -173,6 +179,9 @@ func (nc *NoopCollector) LatestTrieRegSize(size uint64)
 func (nc *NoopCollector) LatestTrieRegSizeDiff(size int64)                                 {}
 func (nc *NoopCollector) LatestTrieMaxDepthTouched(maxDepth uint16)                        {}
 func (nc *NoopCollector) UpdateCount()                                                     {}
+func (nc *NoopCollector) ExecutionCheckpointLoadDuration(duration time.Duration)           {}
+func (nc *NoopCollector) ExecutionWALReplayProgress(replayed int, total int)               {}
+func (nc *NoopCollector) ExecutionWALReplayDuration(duration time.Duration)                {}
 func (nc *NoopCollector) ProofSize(bytes uint32)                                           {}
 func (nc *NoopCollector) UpdateValuesNumber(number uint64)                                 {}
 func (nc *NoopCollector) UpdateValuesSize(byte uint64)                                     {}
@@ -237,6 +246,10 @@ func (nc *NoopCollector) PrunedBlockByHeight(status *chainsync.Status)
 func (nc *NoopCollector) PrunedBlocks(totalByHeight, totalById, storedByHeight, storedById int) {}
 func (nc *NoopCollector) RangeRequested(ran chainsync.Range)                                    {}
 func (nc *NoopCollector) BatchRequested(batch chainsync.Batch)                                  {}
+func (nc *NoopCollector) BlockResponseReceived(latency time.Duration)                           {}
+func (nc *NoopCollector) BlockRequestFailed(reason string)                                      {}
+func (nc *NoopCollector) SyncPeerExcluded()                                                     {}
+func (nc *NoopCollector) ExcludedSyncPeers(count int)                                           {}
 func (nc *NoopCollector) OnUnauthorizedMessage(role, msgType, topic, offense string)            {}
 func (nc *NoopCollector) ObserveHTTPRequestDuration(context.Context, httpmetrics.HTTPReqProperties, time.Duration) {
 }
@@ -266,6 +279,11 @@ func (nc *NoopCollector) OnIncomingRpcAcceptedOnlyForControlMessages()
 func (nc *NoopCollector) OnIncomingRpcRejected()                                           {}
 func (nc *NoopCollector) OnPublishedGossipMessagesReceived(int)                            {}
 func (nc *NoopCollector) OnLocalMeshSizeUpdated(string, int)                               {}
+func (nc *NoopCollector) OnInvalidControlMessage(string, string)                           {}
+func (nc *NoopCollector) OnIHaveMessagesSampled()                                          {}
+func (nc *NoopCollector) OnMisbehaviorReported(string, string)                             {}
+func (nc *NoopCollector) OnMisbehavingNodesDisallowListed(int)                             {}
+func (nc *NoopCollector) OnTracedMessageReceived(string, time.Duration, bool)              {}
 func (nc *NoopCollector) AllowConn(network.Direction, bool)                                {}
 func (nc *NoopCollector) BlockConn(network.Direction, bool)                                {}
 func (nc *NoopCollector) AllowStream(peer.ID, network.Direction)                           {}
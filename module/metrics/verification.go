@@ -18,6 +18,8 @@ type VerificationCollector struct {
 	assignedChunkTotalAssigner      prometheus.Counter // total chunks assigned to this verification node
 	processedChunkTotalAssigner     prometheus.Counter // total chunks sent by assigner engine to chunk consumer (i.e., fetcher input)
 	receivedResultsTotalAssigner    prometheus.Counter // total execution results arrived at assigner
+	observedSealedHeightAssigner    prometheus.Gauge   // the last sealed height observed by assigner engine
+	skippedSealedResultsAssigner    prometheus.Counter // total execution results skipped by assigner since already sealed
 
 	// Fetcher Engine
 	receivedAssignedChunkTotalFetcher  prometheus.Counter // total assigned chunks received by fetcher engine from assigner engine.
@@ -41,6 +43,7 @@ type VerificationCollector struct {
 	// Verifier Engine
 	receivedVerifiableChunkTotalVerifier prometheus.Counter // total verifiable chunks received by verifier engine
 	sentResultApprovalTotalVerifier      prometheus.Counter // total result approvals sent by verifier engine
+	sentChunkFaultReportTotalVerifier    prometheus.Counter // total chunk fault reports sent by verifier engine
 
 }
 
@@ -75,6 +78,20 @@ func NewVerificationCollector(tracer module.Tracer, registerer prometheus.Regist
 		Help:      "total number of execution results received by assigner engine",
 	})
 
+	observedSealedHeightAssigner := prometheus.NewGauge(prometheus.GaugeOpts{
+		Name:      "sealed_height",
+		Namespace: namespaceVerification,
+		Subsystem: subsystemAssignerEngine,
+		Help:      "the last sealed height observed by assigner engine",
+	})
+
+	skippedSealedResultsAssigner := prometheus.NewCounter(prometheus.CounterOpts{
+		Name:      "sealed_result_skipped_total",
+		Namespace: namespaceVerification,
+		Subsystem: subsystemAssignerEngine,
+		Help:      "total number of execution results skipped by assigner engine since they are sealed beyond the catch-up depth",
+	})
+
 	assignedChunksTotalAssigner := prometheus.NewCounter(prometheus.CounterOpts{
 		Name:      "chunk_assigned_total",
 		Namespace: namespaceVerification,
@@ -173,6 +190,13 @@ func NewVerificationCollector(tracer module.Tracer, registerer prometheus.Regist
 		Help:      "total number of emitted result approvals by verifier engine",
 	})
 
+	sentChunkFaultReportTotalVerifier := prometheus.NewCounter(prometheus.CounterOpts{
+		Name:      "chunk_fault_reports_total",
+		Namespace: namespaceVerification,
+		Subsystem: subsystemVerifierEngine,
+		Help:      "total number of emitted chunk fault reports by verifier engine",
+	})
+
 	// registers all metrics and panics if any fails.
 	registerer.MustRegister(
 		// job consumers
@@ -184,6 +208,8 @@ func NewVerificationCollector(tracer module.Tracer, registerer prometheus.Regist
 		assignedChunksTotalAssigner,
 		sentChunksTotalAssigner,
 		receivedResultsTotalAssigner,
+		observedSealedHeightAssigner,
+		skippedSealedResultsAssigner,
 
 		// fetcher engine
 		receivedAssignedChunksTotalFetcher,
@@ -200,7 +226,8 @@ func NewVerificationCollector(tracer module.Tracer, registerer prometheus.Regist
 
 		// verifier engine
 		receivedVerifiableChunksTotalVerifier,
-		sentResultApprovalTotalVerifier)
+		sentResultApprovalTotalVerifier,
+		sentChunkFaultReportTotalVerifier)
 
 	vc := &VerificationCollector{
 		tracer: tracer,
@@ -214,6 +241,8 @@ func NewVerificationCollector(tracer module.Tracer, registerer prometheus.Regist
 		assignedChunkTotalAssigner:      assignedChunksTotalAssigner,
 		processedChunkTotalAssigner:     sentChunksTotalAssigner,
 		receivedResultsTotalAssigner:    receivedResultsTotalAssigner,
+		observedSealedHeightAssigner:    observedSealedHeightAssigner,
+		skippedSealedResultsAssigner:    skippedSealedResultsAssigner,
 
 		// fetcher
 		receivedAssignedChunkTotalFetcher:  receivedAssignedChunksTotalFetcher,
@@ -223,6 +252,7 @@ func NewVerificationCollector(tracer module.Tracer, registerer prometheus.Regist
 
 		// verifier
 		sentResultApprovalTotalVerifier:      sentResultApprovalTotalVerifier,
+		sentChunkFaultReportTotalVerifier:    sentChunkFaultReportTotalVerifier,
 		receivedVerifiableChunkTotalVerifier: receivedVerifiableChunksTotalVerifier,
 
 		// requester
@@ -257,12 +287,29 @@ func (vc *VerificationCollector) OnResultApprovalDispatchedInNetworkByVerifier()
 	vc.sentResultApprovalTotalVerifier.Inc()
 }
 
+// OnChunkFaultReportDispatchedInNetworkByVerifier is called whenever a chunk fault report is emitted to consensus nodes.
+// It increases the total number of chunk fault reports.
+func (vc *VerificationCollector) OnChunkFaultReportDispatchedInNetworkByVerifier() {
+	vc.sentChunkFaultReportTotalVerifier.Inc()
+}
+
 // OnFinalizedBlockArrivedAtAssigner sets a gauge that keeps track of number of the latest block height arrives
 // at assigner engine. Note that it assumes blocks are coming to assigner engine in strictly increasing order of their height.
 func (vc *VerificationCollector) OnFinalizedBlockArrivedAtAssigner(height uint64) {
 	vc.receivedFinalizedHeightAssigner.Set(float64(height))
 }
 
+// OnSealedHeightObservedAtAssigner sets a gauge that keeps track of the latest sealed height observed by assigner engine.
+func (vc *VerificationCollector) OnSealedHeightObservedAtAssigner(height uint64) {
+	vc.observedSealedHeightAssigner.Set(float64(height))
+}
+
+// OnSealedResultSkippedAtAssigner increments a counter that keeps track of the total number of execution results
+// skipped by assigner engine, since their executed blocks are sealed beyond the catch-up depth.
+func (vc *VerificationCollector) OnSealedResultSkippedAtAssigner() {
+	vc.skippedSealedResultsAssigner.Inc()
+}
+
 // OnChunksAssignmentDoneAtAssigner increments a counter that keeps track of the total number of assigned chunks to
 // the verification node.
 func (vc *VerificationCollector) OnChunksAssignmentDoneAtAssigner(chunks int) {
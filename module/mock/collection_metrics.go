@@ -7,6 +7,8 @@ import (
 	flow "github.com/onflow/flow-go/model/flow"
 
 	mock "github.com/stretchr/testify/mock"
+
+	time "time"
 )
 
 // CollectionMetrics is an autogenerated mock type for the CollectionMetrics type
@@ -29,6 +31,11 @@ func (_m *CollectionMetrics) TransactionIngested(txID flow.Identifier) {
 	_m.Called(txID)
 }
 
+// TransactionSelected provides a mock function with given fields: wait
+func (_m *CollectionMetrics) TransactionSelected(wait time.Duration) {
+	_m.Called(wait)
+}
+
 type mockConstructorTestingTNewCollectionMetrics interface {
 	mock.TestingT
 	Cleanup(func())
@@ -46,6 +46,11 @@ func (_m *ExecutionMetrics) ExecutionBlockExecutionEffortVectorComponent(_a0 str
 	_m.Called(_a0, _a1)
 }
 
+// ExecutionCheckpointLoadDuration provides a mock function with given fields: duration
+func (_m *ExecutionMetrics) ExecutionCheckpointLoadDuration(duration time.Duration) {
+	_m.Called(duration)
+}
+
 // ExecutionChunkDataPackGenerated provides a mock function with given fields: proofSize, numberOfTransactions
 func (_m *ExecutionMetrics) ExecutionChunkDataPackGenerated(proofSize int, numberOfTransactions int) {
 	_m.Called(proofSize, numberOfTransactions)
@@ -101,6 +106,16 @@ func (_m *ExecutionMetrics) ExecutionTransactionExecuted(dur time.Duration, comp
 	_m.Called(dur, compUsed, memoryUsed, actualMemoryUsed, eventCounts, eventSize, failed)
 }
 
+// ExecutionWALReplayDuration provides a mock function with given fields: duration
+func (_m *ExecutionMetrics) ExecutionWALReplayDuration(duration time.Duration) {
+	_m.Called(duration)
+}
+
+// ExecutionWALReplayProgress provides a mock function with given fields: replayed, total
+func (_m *ExecutionMetrics) ExecutionWALReplayProgress(replayed int, total int) {
+	_m.Called(replayed, total)
+}
+
 // FinishBlockReceivedToExecuted provides a mock function with given fields: blockID
 func (_m *ExecutionMetrics) FinishBlockReceivedToExecuted(blockID flow.Identifier) {
 	_m.Called(blockID)
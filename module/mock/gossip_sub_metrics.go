@@ -69,6 +69,16 @@ func (_m *GossipSubMetrics) OnInvalidMessageDeliveredUpdated(_a0 channels.Topic,
 	_m.Called(_a0, _a1)
 }
 
+// OnIHaveMessagesSampled provides a mock function with given fields:
+func (_m *GossipSubMetrics) OnIHaveMessagesSampled() {
+	_m.Called()
+}
+
+// OnInvalidControlMessage provides a mock function with given fields: msgType, reason
+func (_m *GossipSubMetrics) OnInvalidControlMessage(msgType string, reason string) {
+	_m.Called(msgType, reason)
+}
+
 // OnLocalMeshSizeUpdated provides a mock function with given fields: topic, size
 func (_m *GossipSubMetrics) OnLocalMeshSizeUpdated(topic string, size int) {
 	_m.Called(topic, size)
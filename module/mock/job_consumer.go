@@ -17,6 +17,20 @@ func (_m *JobConsumer) Check() {
 	_m.Called()
 }
 
+// FastForward provides a mock function with given fields: index
+func (_m *JobConsumer) FastForward(index uint64) error {
+	ret := _m.Called(index)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(uint64) error); ok {
+		r0 = rf(index)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // LastProcessedIndex provides a mock function with given fields:
 func (_m *JobConsumer) LastProcessedIndex() uint64 {
 	ret := _m.Called()
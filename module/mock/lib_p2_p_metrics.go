@@ -180,6 +180,16 @@ func (_m *LibP2PMetrics) OnInvalidMessageDeliveredUpdated(_a0 channels.Topic, _a
 	_m.Called(_a0, _a1)
 }
 
+// OnIHaveMessagesSampled provides a mock function with given fields:
+func (_m *LibP2PMetrics) OnIHaveMessagesSampled() {
+	_m.Called()
+}
+
+// OnInvalidControlMessage provides a mock function with given fields: msgType, reason
+func (_m *LibP2PMetrics) OnInvalidControlMessage(msgType string, reason string) {
+	_m.Called(msgType, reason)
+}
+
 // OnLocalMeshSizeUpdated provides a mock function with given fields: topic, size
 func (_m *LibP2PMetrics) OnLocalMeshSizeUpdated(topic string, size int) {
 	_m.Called(topic, size)
@@ -28,6 +28,11 @@ func (_m *NetworkCoreMetrics) MessageAdded(priority int) {
 	_m.Called(priority)
 }
 
+// MessageDropped provides a mock function with given fields: senderRole, reason
+func (_m *NetworkCoreMetrics) MessageDropped(senderRole string, reason string) {
+	_m.Called(senderRole, reason)
+}
+
 // MessageProcessingFinished provides a mock function with given fields: topic, duration
 func (_m *NetworkCoreMetrics) MessageProcessingFinished(topic string, duration time.Duration) {
 	_m.Called(topic, duration)
@@ -18,6 +18,11 @@ func (_m *NetworkInboundQueueMetrics) MessageAdded(priority int) {
 	_m.Called(priority)
 }
 
+// MessageDropped provides a mock function with given fields: senderRole, reason
+func (_m *NetworkInboundQueueMetrics) MessageDropped(senderRole string, reason string) {
+	_m.Called(senderRole, reason)
+}
+
 // MessageRemoved provides a mock function with given fields: priority
 func (_m *NetworkInboundQueueMetrics) MessageRemoved(priority int) {
 	_m.Called(priority)
@@ -115,6 +115,11 @@ func (_m *NetworkMetrics) MessageAdded(priority int) {
 	_m.Called(priority)
 }
 
+// MessageDropped provides a mock function with given fields: senderRole, reason
+func (_m *NetworkMetrics) MessageDropped(senderRole string, reason string) {
+	_m.Called(senderRole, reason)
+}
+
 // MessageProcessingFinished provides a mock function with given fields: topic, duration
 func (_m *NetworkMetrics) MessageProcessingFinished(topic string, duration time.Duration) {
 	_m.Called(topic, duration)
@@ -210,6 +215,16 @@ func (_m *NetworkMetrics) OnInvalidMessageDeliveredUpdated(_a0 channels.Topic, _
 	_m.Called(_a0, _a1)
 }
 
+// OnIHaveMessagesSampled provides a mock function with given fields:
+func (_m *NetworkMetrics) OnIHaveMessagesSampled() {
+	_m.Called()
+}
+
+// OnInvalidControlMessage provides a mock function with given fields: msgType, reason
+func (_m *NetworkMetrics) OnInvalidControlMessage(msgType string, reason string) {
+	_m.Called(msgType, reason)
+}
+
 // OnLocalMeshSizeUpdated provides a mock function with given fields: topic, size
 func (_m *NetworkMetrics) OnLocalMeshSizeUpdated(topic string, size int) {
 	_m.Called(topic, size)
@@ -220,6 +235,16 @@ func (_m *NetworkMetrics) OnMeshMessageDeliveredUpdated(_a0 channels.Topic, _a1
 	_m.Called(_a0, _a1)
 }
 
+// OnMisbehaviorReported provides a mock function with given fields: channel, misbehaviorType
+func (_m *NetworkMetrics) OnMisbehaviorReported(channel string, misbehaviorType string) {
+	_m.Called(channel, misbehaviorType)
+}
+
+// OnMisbehavingNodesDisallowListed provides a mock function with given fields: count
+func (_m *NetworkMetrics) OnMisbehavingNodesDisallowListed(count int) {
+	_m.Called(count)
+}
+
 // OnOverallPeerScoreUpdated provides a mock function with given fields: _a0
 func (_m *NetworkMetrics) OnOverallPeerScoreUpdated(_a0 float64) {
 	_m.Called(_a0)
@@ -270,6 +295,11 @@ func (_m *NetworkMetrics) OnTimeInMeshUpdated(_a0 channels.Topic, _a1 time.Durat
 	_m.Called(_a0, _a1)
 }
 
+// OnTracedMessageReceived provides a mock function with given fields: channel, latency, relayed
+func (_m *NetworkMetrics) OnTracedMessageReceived(channel string, latency time.Duration, relayed bool) {
+	_m.Called(channel, latency, relayed)
+}
+
 // OnUnauthorizedMessage provides a mock function with given fields: role, msgType, topic, offense
 func (_m *NetworkMetrics) OnUnauthorizedMessage(role string, msgType string, topic string, offense string) {
 	_m.Called(role, msgType, topic, offense)
@@ -59,6 +59,11 @@ func (_m *VerificationMetrics) OnChunkDataPackSentToFetcher() {
 	_m.Called()
 }
 
+// OnChunkFaultReportDispatchedInNetworkByVerifier provides a mock function with given fields:
+func (_m *VerificationMetrics) OnChunkFaultReportDispatchedInNetworkByVerifier() {
+	_m.Called()
+}
+
 // OnChunksAssignmentDoneAtAssigner provides a mock function with given fields: chunks
 func (_m *VerificationMetrics) OnChunksAssignmentDoneAtAssigner(chunks int) {
 	_m.Called(chunks)
@@ -79,6 +84,16 @@ func (_m *VerificationMetrics) OnResultApprovalDispatchedInNetworkByVerifier() {
 	_m.Called()
 }
 
+// OnSealedHeightObservedAtAssigner provides a mock function with given fields: height
+func (_m *VerificationMetrics) OnSealedHeightObservedAtAssigner(height uint64) {
+	_m.Called(height)
+}
+
+// OnSealedResultSkippedAtAssigner provides a mock function with given fields:
+func (_m *VerificationMetrics) OnSealedResultSkippedAtAssigner() {
+	_m.Called()
+}
+
 // OnVerifiableChunkReceivedAtVerifierEngine provides a mock function with given fields:
 func (_m *VerificationMetrics) OnVerifiableChunkReceivedAtVerifierEngine() {
 	_m.Called()
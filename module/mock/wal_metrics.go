@@ -2,13 +2,32 @@
 
 package mock
 
-import mock "github.com/stretchr/testify/mock"
+import (
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
 
 // WALMetrics is an autogenerated mock type for the WALMetrics type
 type WALMetrics struct {
 	mock.Mock
 }
 
+// ExecutionCheckpointLoadDuration provides a mock function with given fields: duration
+func (_m *WALMetrics) ExecutionCheckpointLoadDuration(duration time.Duration) {
+	_m.Called(duration)
+}
+
+// ExecutionWALReplayDuration provides a mock function with given fields: duration
+func (_m *WALMetrics) ExecutionWALReplayDuration(duration time.Duration) {
+	_m.Called(duration)
+}
+
+// ExecutionWALReplayProgress provides a mock function with given fields: replayed, total
+func (_m *WALMetrics) ExecutionWALReplayProgress(replayed int, total int) {
+	_m.Called(replayed, total)
+}
+
 type mockConstructorTestingTNewWALMetrics interface {
 	mock.TestingT
 	Cleanup(func())
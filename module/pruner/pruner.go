@@ -0,0 +1,210 @@
+package pruner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/rs/zerolog"
+
+	"github.com/onflow/flow-go/engine"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/module/component"
+	"github.com/onflow/flow-go/module/irrecoverable"
+	"github.com/onflow/flow-go/state/protocol"
+	"github.com/onflow/flow-go/state/protocol/events"
+	"github.com/onflow/flow-go/storage"
+)
+
+const (
+	// MinRetention is the minimum number of finalized blocks whose payloads are retained, so that
+	// the collections of transactions which have not expired yet are always available.
+	MinRetention = flow.DefaultTransactionExpiry
+
+	// blocksPerRun is the number of heights pruned before checking for shutdown.
+	blocksPerRun = 1000
+)
+
+// ProcessedHeight returns the highest finalized height processed by the node-specific logic,
+// for example the highest executed block of an execution node. Payloads are only pruned below
+// this height, so that payloads which the node still has to process are never removed.
+// Expected errors during normal operation:
+//   - storage.ErrNotFound if no block has been processed yet
+type ProcessedHeight func() (uint64, error)
+
+// Pruner removes the payloads of finalized blocks once they are more than the configured
+// retention below the latest finalized block. Payloads of the blocks in the sealing segment of
+// the latest finalized block are always retained, so that the node can serve protocol state
+// snapshots, as are the payloads at and above the processed height of the node, if given.
+// Headers, seals, results and epoch data are never pruned.
+type Pruner struct {
+	component.Component
+	events.Noop
+
+	log       zerolog.Logger
+	state     protocol.State
+	pruner    storage.PayloadPruner
+	retention uint64
+	processed ProcessedHeight
+	notifier  engine.Notifier
+}
+
+var _ protocol.Consumer = (*Pruner)(nil)
+
+// New creates a new Pruner retaining the payloads of the latest `retention` finalized blocks.
+// If processed is not nil, payloads are only pruned below the processed height it returns.
+// The Pruner must be subscribed to protocol events to be notified about finalized blocks.
+func New(log zerolog.Logger, state protocol.State, pruner storage.PayloadPruner, retention uint64, processed ProcessedHeight) (*Pruner, error) {
+	if retention < MinRetention {
+		return nil, fmt.Errorf("retention of %d blocks is below the minimum of %d blocks", retention, MinRetention)
+	}
+
+	p := &Pruner{
+		log:       log.With().Str("component", "payload_pruner").Logger(),
+		state:     state,
+		pruner:    pruner,
+		retention: retention,
+		processed: processed,
+		notifier:  engine.NewNotifier(),
+	}
+
+	// prune once on startup, without waiting for the next finalized block
+	p.notifier.Notify()
+
+	p.Component = component.NewComponentManagerBuilder().
+		AddWorker(func(ctx irrecoverable.SignalerContext, ready component.ReadyFunc) {
+			ready()
+			err := p.loop(ctx)
+			if err != nil {
+				ctx.Throw(err)
+			}
+		}).
+		Build()
+
+	return p, nil
+}
+
+// BlockFinalized notifies the pruner that a new block was finalized.
+func (p *Pruner) BlockFinalized(*flow.Header) {
+	p.notifier.Notify()
+}
+
+func (p *Pruner) loop(ctx context.Context) error {
+	notifier := p.notifier.Channel()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-notifier:
+			err := p.prune(ctx)
+			if err != nil {
+				return fmt.Errorf("could not prune payloads: %w", err)
+			}
+		}
+	}
+}
+
+// prune removes the payloads of the finalized blocks below the pruning threshold.
+// No errors are expected during normal operation.
+func (p *Pruner) prune(ctx context.Context) error {
+	threshold, err := p.threshold()
+	if err != nil {
+		return fmt.Errorf("could not determine pruning threshold: %w", err)
+	}
+
+	from, err := p.pruner.PrunedHeight()
+	if errors.Is(err, storage.ErrNotFound) {
+		from, err = p.startHeight()
+	}
+	if err != nil {
+		return fmt.Errorf("could not determine pruned height: %w", err)
+	}
+	if from >= threshold {
+		return nil
+	}
+
+	for height := from; height < threshold; {
+		select {
+		case <-ctx.Done():
+			return nil
+		default:
+		}
+
+		height += blocksPerRun
+		if height > threshold {
+			height = threshold
+		}
+		err = p.pruner.PruneUpToHeight(height)
+		if err != nil {
+			return fmt.Errorf("could not prune up to height %d: %w", height, err)
+		}
+	}
+
+	p.log.Debug().
+		Uint64("from_height", from).
+		Uint64("pruned_height", threshold).
+		Msg("pruned payloads of finalized blocks")
+	return nil
+}
+
+// startHeight returns the height at which pruning starts if nothing has been pruned yet, which is
+// the root block of the node, or the spork root block if it is higher.
+// No errors are expected during normal operation.
+func (p *Pruner) startHeight() (uint64, error) {
+	params := p.state.Params()
+	sporkRootHeight, err := params.SporkRootBlockHeight()
+	if err != nil {
+		return 0, fmt.Errorf("could not get spork root block height: %w", err)
+	}
+	root, err := params.Root()
+	if err != nil {
+		return 0, fmt.Errorf("could not get root block: %w", err)
+	}
+	if root.Height > sporkRootHeight {
+		return root.Height, nil
+	}
+	return sporkRootHeight, nil
+}
+
+// threshold returns the height below which the payloads of finalized blocks are pruned. It is
+// the retention below the latest finalized block, but not above the lowest block of the sealing
+// segment of the latest finalized block, nor above the processed height of the node.
+// No errors are expected during normal operation.
+func (p *Pruner) threshold() (uint64, error) {
+	final := p.state.Final()
+	head, err := final.Head()
+	if err != nil {
+		return 0, fmt.Errorf("could not get finalized block: %w", err)
+	}
+	if head.Height < p.retention {
+		return 0, nil
+	}
+	threshold := head.Height - p.retention
+
+	segment, err := final.SealingSegment()
+	if err != nil {
+		return 0, fmt.Errorf("could not get sealing segment: %w", err)
+	}
+	lowest := segment.Blocks[0].Header.Height
+	if len(segment.ExtraBlocks) > 0 {
+		lowest = segment.ExtraBlocks[0].Header.Height
+	}
+	if lowest < threshold {
+		threshold = lowest
+	}
+
+	if p.processed == nil {
+		return threshold, nil
+	}
+	processed, err := p.processed()
+	if errors.Is(err, storage.ErrNotFound) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("could not get processed height: %w", err)
+	}
+	if processed < threshold {
+		threshold = processed
+	}
+	return threshold, nil
+}
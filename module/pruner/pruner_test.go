@@ -0,0 +1,141 @@
+package pruner
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/model/flow"
+	protocolmock "github.com/onflow/flow-go/state/protocol/mock"
+	"github.com/onflow/flow-go/storage"
+	storagemock "github.com/onflow/flow-go/storage/mock"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+// newState returns a protocol state whose finalized block is at the given height, with a
+// sealing segment whose lowest block is at the given height.
+func newState(t *testing.T, finalized uint64, lowest uint64) *protocolmock.State {
+	return newStateWithRoot(t, finalized, lowest, 0, 0)
+}
+
+// newStateWithRoot returns a protocol state like newState, with the given spork root and root block heights.
+func newStateWithRoot(t *testing.T, finalized uint64, lowest uint64, sporkRoot uint64, root uint64) *protocolmock.State {
+	head := unittest.BlockHeaderFixture(unittest.WithHeaderHeight(finalized))
+	segment := &flow.SealingSegment{
+		Blocks: []*flow.Block{
+			unittest.BlockWithParentFixture(unittest.BlockHeaderFixture(unittest.WithHeaderHeight(lowest - 1))),
+		},
+	}
+
+	snapshot := protocolmock.NewSnapshot(t)
+	snapshot.On("Head").Return(head, nil)
+	snapshot.On("SealingSegment").Return(segment, nil).Maybe()
+
+	params := protocolmock.NewParams(t)
+	params.On("SporkRootBlockHeight").Return(sporkRoot, nil).Maybe()
+	params.On("Root").Return(unittest.BlockHeaderFixture(unittest.WithHeaderHeight(root)), nil).Maybe()
+
+	state := protocolmock.NewState(t)
+	state.On("Final").Return(snapshot)
+	state.On("Params").Return(params).Maybe()
+	return state
+}
+
+func TestNewRejectsLowRetention(t *testing.T) {
+	_, err := New(unittest.Logger(), protocolmock.NewState(t), storagemock.NewPayloadPruner(t), MinRetention-1, nil)
+	require.Error(t, err)
+}
+
+// TestPruneBelowRetention tests that payloads are pruned up to the retention below the finalized block,
+// continuing at the pruned height.
+func TestPruneBelowRetention(t *testing.T) {
+	payloadPruner := storagemock.NewPayloadPruner(t)
+	payloadPruner.On("PrunedHeight").Return(uint64(1000), nil)
+	payloadPruner.On("PruneUpToHeight", uint64(1400)).Return(nil).Once()
+
+	p, err := New(unittest.Logger(), newState(t, 2000, 1500), payloadPruner, 600, nil)
+	require.NoError(t, err)
+	require.NoError(t, p.prune(context.Background()))
+}
+
+// TestPruneRetainsSealingSegment tests that the payloads of the sealing segment are retained.
+func TestPruneRetainsSealingSegment(t *testing.T) {
+	payloadPruner := storagemock.NewPayloadPruner(t)
+	payloadPruner.On("PrunedHeight").Return(uint64(1000), nil)
+	payloadPruner.On("PruneUpToHeight", uint64(1200)).Return(nil).Once()
+
+	p, err := New(unittest.Logger(), newState(t, 2000, 1200), payloadPruner, 600, nil)
+	require.NoError(t, err)
+	require.NoError(t, p.prune(context.Background()))
+}
+
+// TestPruneFromSporkRoot tests that pruning starts at the spork root block, and proceeds in steps.
+func TestPruneFromSporkRoot(t *testing.T) {
+	payloadPruner := storagemock.NewPayloadPruner(t)
+	payloadPruner.On("PrunedHeight").Return(uint64(0), storage.ErrNotFound)
+	payloadPruner.On("PruneUpToHeight", uint64(blocksPerRun)).Return(nil).Once()
+	payloadPruner.On("PruneUpToHeight", uint64(1400)).Return(nil).Once()
+
+	p, err := New(unittest.Logger(), newState(t, 2000, 1500), payloadPruner, 600, nil)
+	require.NoError(t, err)
+	require.NoError(t, p.prune(context.Background()))
+}
+
+// TestPruneNothingWithinRetention tests that nothing is pruned while the chain is shorter than the retention.
+func TestPruneNothingWithinRetention(t *testing.T) {
+	payloadPruner := storagemock.NewPayloadPruner(t)
+	payloadPruner.On("PrunedHeight").Return(uint64(0), storage.ErrNotFound)
+
+	p, err := New(unittest.Logger(), newState(t, 500, 400), payloadPruner, 600, nil)
+	require.NoError(t, err)
+	require.NoError(t, p.prune(context.Background()))
+}
+
+// TestPruneFromRoot tests that pruning starts at the root block of the node if it is above the spork root block.
+func TestPruneFromRoot(t *testing.T) {
+	payloadPruner := storagemock.NewPayloadPruner(t)
+	payloadPruner.On("PrunedHeight").Return(uint64(0), storage.ErrNotFound)
+	payloadPruner.On("PruneUpToHeight", uint64(1400)).Return(nil).Once()
+
+	p, err := New(unittest.Logger(), newStateWithRoot(t, 2000, 1500, 100, 900), payloadPruner, 600, nil)
+	require.NoError(t, err)
+	require.NoError(t, p.prune(context.Background()))
+}
+
+// TestPruneBelowProcessedHeight tests that payloads at and above the processed height of the node are retained,
+// and that nothing is pruned before the node has processed any block.
+func TestPruneBelowProcessedHeight(t *testing.T) {
+	t.Run("processed below retention", func(t *testing.T) {
+		payloadPruner := storagemock.NewPayloadPruner(t)
+		payloadPruner.On("PrunedHeight").Return(uint64(1000), nil)
+		payloadPruner.On("PruneUpToHeight", uint64(1100)).Return(nil).Once()
+
+		processed := func() (uint64, error) { return 1100, nil }
+		p, err := New(unittest.Logger(), newState(t, 2000, 1500), payloadPruner, 600, processed)
+		require.NoError(t, err)
+		require.NoError(t, p.prune(context.Background()))
+	})
+
+	t.Run("processed above retention", func(t *testing.T) {
+		payloadPruner := storagemock.NewPayloadPruner(t)
+		payloadPruner.On("PrunedHeight").Return(uint64(1000), nil)
+		payloadPruner.On("PruneUpToHeight", uint64(1400)).Return(nil).Once()
+
+		processed := func() (uint64, error) { return 1900, nil }
+		p, err := New(unittest.Logger(), newState(t, 2000, 1500), payloadPruner, 600, processed)
+		require.NoError(t, err)
+		require.NoError(t, p.prune(context.Background()))
+	})
+
+	t.Run("nothing processed", func(t *testing.T) {
+		payloadPruner := storagemock.NewPayloadPruner(t)
+		payloadPruner.On("PrunedHeight").Return(uint64(1000), nil)
+
+		processed := func() (uint64, error) { return 0, fmt.Errorf("no processed index: %w", storage.ErrNotFound) }
+		p, err := New(unittest.Logger(), newState(t, 2000, 1500), payloadPruner, 600, processed)
+		require.NoError(t, err)
+		require.NoError(t, p.prune(context.Background()))
+	})
+}
@@ -52,6 +52,8 @@ var (
 	ExecutionReceiptTag = tag("Execution_Receipt")
 	// ResultApprovalTag is used for result approvals
 	ResultApprovalTag = tag("Result_Approval")
+	// ChunkFaultReportTag is used for chunk fault reports
+	ChunkFaultReportTag = tag("Chunk_Fault_Report")
 	// SPOCKTag is used to generate SPoCK proofs
 	SPOCKTag = tag("SPoCK")
 	// DKGMessageTag is used for DKG messages
@@ -136,12 +136,19 @@ const (
 
 	VERProcessExecutionResult SpanName = "ver.processExecutionResult"
 	// children of VERProcessExecutionResult
-	VERMatchHandleExecutionResult SpanName = "ver.match.handleExecutionResult"
-	VERMatchHandleChunkDataPack   SpanName = "ver.match.handleChunkDataPack"
-	VERMatchMyChunkAssignments    SpanName = "ver.match.myChunkAssignments"
-	VERVerVerifyWithMetrics       SpanName = "ver.verify.verifyWithMetrics"
-	VERVerChunkVerify             SpanName = "ver.verify.ChunkVerifier.Verify"
-	VERVerGenerateResultApproval  SpanName = "ver.verify.GenerateResultApproval"
+	VERMatchHandleExecutionResult  SpanName = "ver.match.handleExecutionResult"
+	VERMatchHandleChunkDataPack    SpanName = "ver.match.handleChunkDataPack"
+	VERMatchMyChunkAssignments     SpanName = "ver.match.myChunkAssignments"
+	VERVerVerifyWithMetrics        SpanName = "ver.verify.verifyWithMetrics"
+	VERVerChunkVerify              SpanName = "ver.verify.ChunkVerifier.Verify"
+	VERVerGenerateResultApproval   SpanName = "ver.verify.GenerateResultApproval"
+	VERVerGenerateChunkFaultReport SpanName = "ver.verify.GenerateChunkFaultReport"
+
+	// Networking Layer
+	//
+	// message tracing
+	NETSendTracedMessage    SpanName = "net.message.send"
+	NETReceiveTracedMessage SpanName = "net.message.receive"
 
 	// Flow Virtual Machine
 	FVMVerifyTransaction           SpanName = "fvm.verifyTransaction"
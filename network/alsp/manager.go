@@ -0,0 +1,259 @@
+package alsp
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	"github.com/onflow/flow-go/engine"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/module"
+	"github.com/onflow/flow-go/module/component"
+	"github.com/onflow/flow-go/module/irrecoverable"
+	"github.com/onflow/flow-go/network"
+	"github.com/onflow/flow-go/network/channels"
+	"github.com/onflow/flow-go/utils/logging"
+)
+
+// NodeDisallowLister is the component that disallow-lists misbehaving nodes on behalf of the MisbehaviorReportManager,
+// i.e., disconnects from them and rejects any further connection, e.g., the cache.NodeBlocklistWrapper.
+type NodeDisallowLister interface {
+	// DisallowListNode disallow-lists the given node.
+	// No errors are expected during normal operations.
+	DisallowListNode(nodeID flow.Identifier) error
+
+	// AllowListNode lifts the disallow-listing of the given node.
+	// No errors are expected during normal operations.
+	AllowListNode(nodeID flow.Identifier) error
+}
+
+// MisbehaviorReportManagerConfig is the configuration of the MisbehaviorReportManager.
+type MisbehaviorReportManagerConfig struct {
+	Logger zerolog.Logger
+	// AlspMetrics is the metrics instance for the alsp module (collecting metrics about the misbehavior reports).
+	AlspMetrics module.AlspMetrics
+	// DisablePenalty indicates whether applying the penalty to the misbehaving node is disabled.
+	// When disabled, the misbehavior reports are only logged and tracked in metrics, i.e., a dry run.
+	DisablePenalty bool
+	// DisallowLister disallow-lists the nodes whose penalty drops below the disallow-listing threshold.
+	// It is required unless the penalty is disabled.
+	DisallowLister NodeDisallowLister
+	// HeartBeatInterval is the interval at which the penalties are decayed and the disallow-listing of the
+	// misbehaving nodes is updated.
+	HeartBeatInterval time.Duration
+}
+
+// MisbehaviorReportManager is responsible for handling misbehavior reports.
+// It keeps a ProtocolSpamRecord per misbehaving node, accumulating the penalties of the reports sent by the engines.
+// The penalty of each node decays over time (linearly, with the decay speed of the record). Once the penalty of a node
+// drops below the disallow-listing threshold, the node is disallow-listed, and its decay speed is reduced, so that
+// repeated misbehavior leads to longer disallow-listing. Once the penalty decays back to zero, the node is allow-listed
+// again.
+//
+// The records are kept in memory only. As misbehavior reports are only generated for messages from authorized
+// (i.e., staked) nodes, the number of records is bounded by the size of the identity table. Records of nodes
+// that were never disallow-listed are dropped once their penalty decays to zero.
+type MisbehaviorReportManager struct {
+	component.Component
+	logger            zerolog.Logger
+	metrics           module.AlspMetrics
+	disablePenalty    bool
+	disallowLister    NodeDisallowLister
+	heartBeatInterval time.Duration
+	notifier          engine.Notifier  // notifies the worker about nodes that need to be disallow-listed
+	now               func() time.Time // returns the current time, replaceable for testing
+
+	mu      sync.Mutex
+	records map[flow.Identifier]ProtocolSpamRecord
+}
+
+var _ network.MisbehaviorReportManager = (*MisbehaviorReportManager)(nil)
+
+// NewMisbehaviorReportManager creates a new instance of the MisbehaviorReportManager.
+// All errors returned from this function indicate an invalid configuration.
+func NewMisbehaviorReportManager(cfg *MisbehaviorReportManagerConfig) (*MisbehaviorReportManager, error) {
+	if !cfg.DisablePenalty && cfg.DisallowLister == nil {
+		return nil, fmt.Errorf("disallow lister is required when penalty is enabled")
+	}
+	if cfg.HeartBeatInterval <= 0 {
+		return nil, fmt.Errorf("heart beat interval must be positive, got: %v", cfg.HeartBeatInterval)
+	}
+
+	m := &MisbehaviorReportManager{
+		logger:            cfg.Logger.With().Str("module", "misbehavior_report_manager").Logger(),
+		metrics:           cfg.AlspMetrics,
+		disablePenalty:    cfg.DisablePenalty,
+		disallowLister:    cfg.DisallowLister,
+		heartBeatInterval: cfg.HeartBeatInterval,
+		notifier:          engine.NewNotifier(),
+		now:               time.Now,
+		records:           make(map[flow.Identifier]ProtocolSpamRecord),
+	}
+
+	if m.disablePenalty {
+		// when the penalty is disabled, the misbehavior logs are still reported to the metrics, but the penalty is not applied.
+		m.logger.Warn().Msg("penalty mechanism of alsp is disabled")
+	}
+
+	m.Component = component.NewComponentManagerBuilder().
+		AddWorker(m.heartBeatLoop).
+		Build()
+
+	return m, nil
+}
+
+// SetTimeNowFunc overrides the default time.Now func of the manager. It is meant for testing only, and must be
+// called before the manager is started.
+func (m *MisbehaviorReportManager) SetTimeNowFunc(now func() time.Time) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.now = now
+}
+
+// HandleMisbehaviorReport is called upon a new misbehavior is reported.
+// The penalty of the report is applied to the spam record of the misbehaving node, and in case the penalty drops below
+// the disallow-listing threshold, the node is disallow-listed asynchronously.
+// The implementation is thread-safe and non-blocking.
+func (m *MisbehaviorReportManager) HandleMisbehaviorReport(channel channels.Channel, report network.MisbehaviorReport) {
+	lg := m.logger.With().
+		Str("channel", channel.String()).
+		Hex("misbehaving_id", logging.ID(report.OriginId())).
+		Str("reason", report.Reason().String()).
+		Float64("penalty", report.Penalty()).
+		Logger()
+	m.metrics.OnMisbehaviorReported(channel.String(), report.Reason().String())
+
+	if m.disablePenalty {
+		// when penalty mechanism disabled, the misbehavior is logged and metrics are updated,
+		// but no further actions are taken.
+		lg.Trace().Msg("discarding misbehavior report, as penalty is disabled")
+		return
+	}
+
+	m.mu.Lock()
+	now := m.now()
+	record, ok := m.records[report.OriginId()]
+	if !ok {
+		record = NewProtocolSpamRecord(report.OriginId(), now)
+	}
+	record = record.decayed(now)
+	record.Penalty += report.Penalty()
+	m.records[report.OriginId()] = record
+	disallowListingRequired := !record.DisallowListed && record.Penalty < misbehaviorDisallowListingThreshold
+	m.mu.Unlock()
+
+	lg.Debug().
+		Float64("overall_penalty", record.Penalty).
+		Bool(logging.KeySuspicious, true).
+		Msg("misbehavior report handled")
+
+	if disallowListingRequired {
+		m.notifier.Notify()
+	}
+}
+
+// Penalties returns a snapshot of the spam records of the misbehaving nodes, with their penalties decayed until now,
+// ordered by ascending penalty, i.e., the most misbehaving node first.
+func (m *MisbehaviorReportManager) Penalties() []ProtocolSpamRecord {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := m.now()
+	records := make([]ProtocolSpamRecord, 0, len(m.records))
+	for _, record := range m.records {
+		records = append(records, record.decayed(now))
+	}
+	sort.Slice(records, func(i, j int) bool {
+		return records[i].Penalty < records[j].Penalty
+	})
+	return records
+}
+
+// heartBeatLoop periodically decays the penalties of the misbehaving nodes and updates their disallow-listing. It also
+// updates the disallow-listing right away when notified about a node whose penalty dropped below the threshold.
+func (m *MisbehaviorReportManager) heartBeatLoop(ctx irrecoverable.SignalerContext, ready component.ReadyFunc) {
+	ready()
+
+	ticker := time.NewTicker(m.heartBeatInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-m.notifier.Channel():
+		}
+
+		err := m.onHeartBeat()
+		if err != nil {
+			ctx.Throw(fmt.Errorf("failed to update disallow-listing of misbehaving nodes: %w", err))
+		}
+	}
+}
+
+// onHeartBeat decays the penalties of all the spam records, disallow-lists the nodes whose penalty dropped below the
+// threshold, and allow-lists the disallow-listed nodes whose penalty decayed back to zero.
+// No errors are expected during normal operations.
+func (m *MisbehaviorReportManager) onHeartBeat() error {
+	var toDisallowList, toAllowList flow.IdentifierList
+	disallowListed := 0
+
+	m.mu.Lock()
+	now := m.now()
+	for originId, record := range m.records {
+		record = record.decayed(now)
+
+		switch {
+		case !record.DisallowListed && record.Penalty < misbehaviorDisallowListingThreshold:
+			record.DisallowListed = true
+			record.CutoffCounter++
+			record.Decay *= decaySpeedReductionFactor
+			if record.Decay < minimumDecaySpeed {
+				record.Decay = minimumDecaySpeed
+			}
+			toDisallowList = append(toDisallowList, originId)
+		case record.DisallowListed && record.Penalty == 0:
+			record.DisallowListed = false
+			toAllowList = append(toAllowList, originId)
+		}
+
+		if record.DisallowListed {
+			disallowListed++
+		}
+		if record.Penalty == 0 && record.CutoffCounter == 0 {
+			// the node has never been disallow-listed, there is nothing to remember about it.
+			delete(m.records, originId)
+			continue
+		}
+		m.records[originId] = record
+	}
+	m.mu.Unlock()
+
+	m.metrics.OnMisbehavingNodesDisallowListed(disallowListed)
+
+	for _, originId := range toDisallowList {
+		m.logger.Warn().
+			Hex("misbehaving_id", logging.ID(originId)).
+			Bool(logging.KeySuspicious, true).
+			Msg("disallow-listing misbehaving node, as its penalty dropped below the threshold")
+		err := m.disallowLister.DisallowListNode(originId)
+		if err != nil {
+			return fmt.Errorf("could not disallow-list misbehaving node %v: %w", originId, err)
+		}
+	}
+	for _, originId := range toAllowList {
+		m.logger.Info().
+			Hex("misbehaving_id", logging.ID(originId)).
+			Msg("allow-listing previously misbehaving node, as its penalty decayed to zero")
+		err := m.disallowLister.AllowListNode(originId)
+		if err != nil {
+			return fmt.Errorf("could not allow-list previously misbehaving node %v: %w", originId, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,175 @@
+package alsp_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/module/irrecoverable"
+	"github.com/onflow/flow-go/module/metrics"
+	"github.com/onflow/flow-go/network/alsp"
+	"github.com/onflow/flow-go/network/channels"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+// TestMisbehaviorReportManager_DisallowListing tests that a node is disallow-listed once its penalty drops below the
+// disallow-listing threshold, and is allow-listed again once its penalty decays back to zero.
+func TestMisbehaviorReportManager_DisallowListing(t *testing.T) {
+	lister := newDisallowListerFixture()
+	clock := newClockFixture()
+	manager, err := alsp.NewMisbehaviorReportManager(&alsp.MisbehaviorReportManagerConfig{
+		Logger:            unittest.Logger(),
+		AlspMetrics:       metrics.NewNoopCollector(),
+		DisallowLister:    lister,
+		HeartBeatInterval: 10 * time.Millisecond,
+	})
+	require.NoError(t, err)
+	manager.SetTimeNowFunc(clock.Now)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	manager.Start(irrecoverable.NewMockSignalerContext(t, ctx))
+	unittest.RequireCloseBefore(t, manager.Ready(), 100*time.Millisecond, "could not start manager")
+
+	misbehaving := unittest.IdentifierFixture()
+	honest := unittest.IdentifierFixture()
+
+	// 100 reports with the default penalty bring the penalty exactly to the threshold, which does not trigger disallow-listing.
+	for i := 0; i < 100; i++ {
+		report, err := alsp.NewMisbehaviorReport(misbehaving, alsp.InvalidMessage)
+		require.NoError(t, err)
+		manager.HandleMisbehaviorReport(channels.PushBlocks, report)
+	}
+	report, err := alsp.NewMisbehaviorReport(honest, alsp.StaleMessage)
+	require.NoError(t, err)
+	manager.HandleMisbehaviorReport(channels.PushBlocks, report)
+
+	records := manager.Penalties()
+	require.Len(t, records, 2)
+	require.Equal(t, misbehaving, records[0].OriginId)
+	require.Equal(t, 100*alsp.DefaultPenaltyValue, records[0].Penalty)
+	require.False(t, records[0].DisallowListed)
+	require.Equal(t, honest, records[1].OriginId)
+
+	// one more report drops the penalty below the threshold.
+	report, err = alsp.NewMisbehaviorReport(misbehaving, alsp.InvalidMessage)
+	require.NoError(t, err)
+	manager.HandleMisbehaviorReport(channels.PushBlocks, report)
+
+	require.Eventually(t, func() bool {
+		return lister.IsDisallowListed(misbehaving)
+	}, time.Second, 10*time.Millisecond)
+	require.False(t, lister.IsDisallowListed(honest))
+
+	records = manager.Penalties()
+	require.Equal(t, misbehaving, records[0].OriginId)
+	require.True(t, records[0].DisallowListed)
+	require.Equal(t, uint64(1), records[0].CutoffCounter)
+
+	// once the penalty decays back to zero (with the reduced decay speed after disallow-listing), the node is
+	// allow-listed again, and the honest node is forgotten.
+	clock.Advance(time.Duration(-101*alsp.DefaultPenaltyValue/records[0].Decay+1) * time.Second)
+	require.Eventually(t, func() bool {
+		return !lister.IsDisallowListed(misbehaving)
+	}, time.Second, 10*time.Millisecond)
+
+	require.Eventually(t, func() bool {
+		records := manager.Penalties()
+		return len(records) == 1 && records[0].OriginId == misbehaving && !records[0].DisallowListed && records[0].Penalty == 0
+	}, time.Second, 10*time.Millisecond)
+
+	cancel()
+	unittest.RequireCloseBefore(t, manager.Done(), 100*time.Millisecond, "could not stop manager")
+}
+
+// TestMisbehaviorReportManager_DisablePenalty tests that no penalty is applied when the penalty is disabled.
+func TestMisbehaviorReportManager_DisablePenalty(t *testing.T) {
+	manager, err := alsp.NewMisbehaviorReportManager(&alsp.MisbehaviorReportManagerConfig{
+		Logger:            unittest.Logger(),
+		AlspMetrics:       metrics.NewNoopCollector(),
+		DisablePenalty:    true,
+		HeartBeatInterval: alsp.DefaultHeartBeatInterval,
+	})
+	require.NoError(t, err)
+
+	report, err := alsp.NewMisbehaviorReport(unittest.IdentifierFixture(), alsp.InvalidMessage, alsp.WithPenaltyAmplification(100))
+	require.NoError(t, err)
+	manager.HandleMisbehaviorReport(channels.PushBlocks, report)
+	require.Empty(t, manager.Penalties())
+}
+
+// TestNewMisbehaviorReportManager_InvalidConfig tests that the manager cannot be created with an invalid config.
+func TestNewMisbehaviorReportManager_InvalidConfig(t *testing.T) {
+	// disallow lister is required when penalty is enabled.
+	_, err := alsp.NewMisbehaviorReportManager(&alsp.MisbehaviorReportManagerConfig{
+		Logger:            unittest.Logger(),
+		AlspMetrics:       metrics.NewNoopCollector(),
+		HeartBeatInterval: alsp.DefaultHeartBeatInterval,
+	})
+	require.Error(t, err)
+
+	// heart beat interval must be positive.
+	_, err = alsp.NewMisbehaviorReportManager(&alsp.MisbehaviorReportManagerConfig{
+		Logger:         unittest.Logger(),
+		AlspMetrics:    metrics.NewNoopCollector(),
+		DisallowLister: newDisallowListerFixture(),
+	})
+	require.Error(t, err)
+}
+
+// disallowListerFixture is a concurrency safe in-memory alsp.NodeDisallowLister for testing.
+type disallowListerFixture struct {
+	mu             sync.Mutex
+	disallowListed map[flow.Identifier]struct{}
+}
+
+func newDisallowListerFixture() *disallowListerFixture {
+	return &disallowListerFixture{disallowListed: make(map[flow.Identifier]struct{})}
+}
+
+func (d *disallowListerFixture) DisallowListNode(nodeID flow.Identifier) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.disallowListed[nodeID] = struct{}{}
+	return nil
+}
+
+func (d *disallowListerFixture) AllowListNode(nodeID flow.Identifier) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	delete(d.disallowListed, nodeID)
+	return nil
+}
+
+func (d *disallowListerFixture) IsDisallowListed(nodeID flow.Identifier) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	_, ok := d.disallowListed[nodeID]
+	return ok
+}
+
+// clockFixture is a concurrency safe clock that only moves forward when advanced by the test.
+type clockFixture struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func newClockFixture() *clockFixture {
+	return &clockFixture{now: time.Now()}
+}
+
+func (c *clockFixture) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *clockFixture) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
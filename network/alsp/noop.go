@@ -0,0 +1,18 @@
+package alsp
+
+import (
+	"github.com/onflow/flow-go/network"
+	"github.com/onflow/flow-go/network/channels"
+)
+
+// NoopMisbehaviorReportManager is a misbehavior report manager that drops all the misbehavior reports.
+type NoopMisbehaviorReportManager struct{}
+
+var _ network.MisbehaviorReportManager = (*NoopMisbehaviorReportManager)(nil)
+
+func NewNoopMisbehaviorReportManager() *NoopMisbehaviorReportManager {
+	return &NoopMisbehaviorReportManager{}
+}
+
+func (n *NoopMisbehaviorReportManager) HandleMisbehaviorReport(channels.Channel, network.MisbehaviorReport) {
+}
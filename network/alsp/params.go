@@ -0,0 +1,36 @@
+package alsp
+
+import "time"
+
+const (
+	// misbehaviorDisallowListingThreshold is the threshold for concluding a node behavior is malicious and disallow-listing
+	// the node. If the overall penalty of the node drops below this threshold, the node is reported to be disallow-listed
+	// by the networking layer, i.e., existing connections to the node are closed and the node is no longer allowed to
+	// connect till its penalty is decayed back to zero.
+	// The threshold is set to -86400, which with the default penalty value (see DefaultPenaltyValue) corresponds to
+	// 100 misbehavior reports in a short period of time.
+	misbehaviorDisallowListingThreshold = -24 * 60 * 60 // maximum block-list period is 1 day
+
+	// DefaultPenaltyValue is the default penalty value for misbehaving nodes.
+	// By default, each reported infringement will be penalized by this value. However, the penalty can be amplified
+	// by the engine that reports the misbehavior (see WithPenaltyAmplification). The penalty system is designed in a way
+	// that more than 100 misbehavior reports in a short period of time lead to disallow-listing the node.
+	DefaultPenaltyValue = 0.01 * misbehaviorDisallowListingThreshold // (Don't change this value)
+
+	// initialDecaySpeed is the decay speed of the penalty of a misbehaving node per second, until the node is
+	// disallow-listed for the first time. With this decay speed, an occasional misbehavior is forgiven quickly, while
+	// sustained misbehavior leads to disallow-listing.
+	initialDecaySpeed = 1000 // (Don't change this value)
+
+	// decaySpeedReductionFactor is the factor by which the decay speed of a node is reduced each time the node is
+	// disallow-listed. Hence, a node that is disallow-listed repeatedly stays disallow-listed for increasingly longer
+	// periods: ~15 minutes, ~2.5 hours, and at most a day.
+	decaySpeedReductionFactor = 0.1
+
+	// minimumDecaySpeed is the lower bound of the decay speed of the penalty of a misbehaving node per second.
+	minimumDecaySpeed = 1
+
+	// DefaultHeartBeatInterval is the default interval at which the penalties of the misbehaving nodes are decayed and
+	// the nodes whose penalty decayed back to zero are allow-listed again.
+	DefaultHeartBeatInterval = 1 * time.Second
+)
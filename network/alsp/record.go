@@ -0,0 +1,55 @@
+package alsp
+
+import (
+	"time"
+
+	"github.com/onflow/flow-go/model/flow"
+)
+
+// ProtocolSpamRecord is a record of a misbehaving node. It is used to keep track of the penalty value of the node
+// and the number of times it has been disallow-listed.
+type ProtocolSpamRecord struct {
+	// OriginId is the node id of the misbehaving node. It is assumed an authorized (i.e., staked) node at the
+	// time of the misbehavior report creation (otherwise, the networking layer should not have dispatched the
+	// message to the Flow protocol layer in the first place).
+	OriginId flow.Identifier
+
+	// Decay is the speed at which the penalty value of the node recovers towards zero, per second.
+	Decay float64
+
+	// CutoffCounter is a counter that is used to determine how many times the node has been disallow-listed.
+	CutoffCounter uint64
+
+	// Penalty is the overall (non-positive) penalty value of the node, as of LastUpdated.
+	Penalty float64
+
+	// DisallowListed indicates whether the node is currently disallow-listed due to misbehavior.
+	DisallowListed bool
+
+	// LastUpdated is the time at which the penalty value was last updated.
+	LastUpdated time.Time
+}
+
+// NewProtocolSpamRecord creates a new protocol spam record with the given origin id, the initial decay speed and no
+// penalty.
+func NewProtocolSpamRecord(originId flow.Identifier, now time.Time) ProtocolSpamRecord {
+	return ProtocolSpamRecord{
+		OriginId:    originId,
+		Decay:       initialDecaySpeed,
+		LastUpdated: now,
+	}
+}
+
+// decayed returns a copy of the record with its penalty decayed until the given time.
+func (r ProtocolSpamRecord) decayed(now time.Time) ProtocolSpamRecord {
+	elapsed := now.Sub(r.LastUpdated)
+	if elapsed <= 0 {
+		return r
+	}
+	r.Penalty += r.Decay * elapsed.Seconds()
+	if r.Penalty > 0 {
+		r.Penalty = 0
+	}
+	r.LastUpdated = now
+	return r
+}
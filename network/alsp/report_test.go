@@ -0,0 +1,51 @@
+package alsp_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/network/alsp"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+// TestNewMisbehaviorReport tests the creation of a misbehavior report with the default penalty value.
+func TestNewMisbehaviorReport(t *testing.T) {
+	originID := unittest.IdentifierFixture()
+	report, err := alsp.NewMisbehaviorReport(originID, alsp.InvalidMessage)
+	require.NoError(t, err)
+
+	require.Equal(t, originID, report.OriginId())
+	require.Equal(t, alsp.InvalidMessage, report.Reason())
+	require.Equal(t, alsp.DefaultPenaltyValue, report.Penalty())
+}
+
+// TestNewMisbehaviorReport_WithPenaltyAmplification tests that the penalty amplification is applied to the default
+// penalty value, and that amplifications out of range are rejected.
+func TestNewMisbehaviorReport_WithPenaltyAmplification(t *testing.T) {
+	originID := unittest.IdentifierFixture()
+
+	report, err := alsp.NewMisbehaviorReport(originID, alsp.ResourceIntensiveRequest, alsp.WithPenaltyAmplification(10))
+	require.NoError(t, err)
+	require.Equal(t, 10*alsp.DefaultPenaltyValue, report.Penalty())
+
+	for _, amplification := range []float64{-1, 0, 101} {
+		report, err := alsp.NewMisbehaviorReport(originID, alsp.ResourceIntensiveRequest, alsp.WithPenaltyAmplification(amplification))
+		require.Error(t, err)
+		require.Nil(t, report)
+	}
+}
+
+// TestMustNewMisbehaviorReport tests that a report is created for valid options, and that invalid options panic.
+func TestMustNewMisbehaviorReport(t *testing.T) {
+	originID := unittest.IdentifierFixture()
+
+	report := alsp.MustNewMisbehaviorReport(originID, alsp.InvalidMessage)
+	require.Equal(t, originID, report.OriginId())
+	require.Equal(t, alsp.InvalidMessage, report.Reason())
+	require.Equal(t, alsp.DefaultPenaltyValue, report.Penalty())
+
+	require.Panics(t, func() {
+		alsp.MustNewMisbehaviorReport(originID, alsp.InvalidMessage, alsp.WithPenaltyAmplification(0))
+	})
+}
@@ -135,6 +135,9 @@ const (
 	PushReceipts     = Channel("push-receipts")
 	PushApprovals    = Channel("push-approvals")
 
+	// Channel for pushing the chunk fault reports of verification nodes to consensus nodes
+	PushChunkFaultReports = Channel("push-chunk-fault-reports")
+
 	// Channels for actively requesting missing entities
 	RequestCollections       = Channel("request-collections")
 	RequestChunks            = Channel("request-chunks")
@@ -148,6 +151,8 @@ const (
 	ReceiveReceipts     = PushReceipts
 	ReceiveApprovals    = PushApprovals
 
+	ReceiveChunkFaultReports = PushChunkFaultReports
+
 	ProvideCollections       = RequestCollections
 	ProvideChunks            = RequestChunks
 	ProvideReceiptsByBlockID = RequestReceiptsByBlockID
@@ -191,6 +196,7 @@ func initializeChannelRoleMap() {
 	channelRoleMap[PushReceipts] = flow.RoleList{flow.RoleConsensus, flow.RoleExecution, flow.RoleVerification,
 		flow.RoleAccess}
 	channelRoleMap[PushApprovals] = flow.RoleList{flow.RoleConsensus, flow.RoleVerification}
+	channelRoleMap[PushChunkFaultReports] = flow.RoleList{flow.RoleConsensus, flow.RoleVerification}
 
 	// Channels for actively requesting missing entities
 	channelRoleMap[RequestCollections] = flow.RoleList{flow.RoleCollection, flow.RoleExecution, flow.RoleAccess}
@@ -205,6 +211,7 @@ func initializeChannelRoleMap() {
 	channelRoleMap[ReceiveReceipts] = flow.RoleList{flow.RoleConsensus, flow.RoleExecution, flow.RoleVerification,
 		flow.RoleAccess}
 	channelRoleMap[ReceiveApprovals] = flow.RoleList{flow.RoleConsensus, flow.RoleVerification}
+	channelRoleMap[ReceiveChunkFaultReports] = flow.RoleList{flow.RoleConsensus, flow.RoleVerification}
 
 	channelRoleMap[ProvideCollections] = flow.RoleList{flow.RoleCollection, flow.RoleExecution, flow.RoleAccess}
 	channelRoleMap[ProvideChunks] = flow.RoleList{flow.RoleExecution, flow.RoleVerification}
@@ -277,6 +284,30 @@ func ChannelFromTopic(topic Topic) (Channel, bool) {
 	return "", false
 }
 
+// IsValidFlowTopic ensures the topic is a valid Flow network topic, i.e., it is derived from an existing channel and,
+// unless the channel is a cluster channel, it is suffixed with the given spork ID.
+// Cluster channels are inherently unique for each epoch, hence they are not suffixed with the spork ID.
+// Expected errors:
+//   - InvalidTopicErr if the topic is not a valid Flow topic.
+func IsValidFlowTopic(topic Topic, expectedSporkID flow.Identifier) error {
+	channel, ok := ChannelFromTopic(topic)
+	if !ok {
+		return NewInvalidTopicErr(topic, fmt.Errorf("failed to get channel from topic"))
+	}
+	if !ChannelExists(channel) {
+		return NewInvalidTopicErr(topic, fmt.Errorf("unknown channel: %s", channel))
+	}
+	if IsClusterChannel(channel) {
+		return nil
+	}
+
+	sporkID := strings.TrimPrefix(topic.String(), channel.String()+"/")
+	if sporkID != expectedSporkID.String() {
+		return NewInvalidTopicErr(topic, fmt.Errorf("invalid spork ID %s, expected %s", sporkID, expectedSporkID))
+	}
+	return nil
+}
+
 // ConsensusCluster returns a dynamic cluster consensus channel based on
 // the chain ID of the cluster in question.
 func ConsensusCluster(clusterID flow.ChainID) Channel {
@@ -53,16 +53,18 @@ func TestGetChannelByRole(t *testing.T) {
 	// - PushBlocks
 	// - PushReceipts
 	// - PushApprovals
+	// - PushChunkFaultReports
 	// - ProvideApprovalsByChunk
 	// - ProvideChunks
 	// - TestNetworkChannel
 	// - TestMetric
 	// the roles list should contain collection and consensus roles
 	topics := ChannelsByRole(flow.RoleVerification)
-	assert.Len(t, topics, 8)
+	assert.Len(t, topics, 9)
 	assert.Contains(t, topics, PushBlocks)
 	assert.Contains(t, topics, PushReceipts)
 	assert.Contains(t, topics, PushApprovals)
+	assert.Contains(t, topics, PushChunkFaultReports)
 	assert.Contains(t, topics, ProvideApprovalsByChunk)
 	assert.Contains(t, topics, RequestChunks)
 	assert.Contains(t, topics, TestMetricsChannel)
@@ -129,3 +131,32 @@ func TestUniqueChannels_ClusterChannels(t *testing.T) {
 	require.Contains(t, uniques, consensusCluster) // cluster channel
 	require.Contains(t, uniques, PushTransactions) // non-cluster channel
 }
+
+// TestIsValidFlowTopic evaluates that IsValidFlowTopic accepts the topics derived from existing channels for the
+// expected spork ID, and rejects topics of unknown channels or other sporks.
+func TestIsValidFlowTopic(t *testing.T) {
+	sporkID := flow.Identifier{1}
+
+	// topics of existing channels with the expected spork ID are valid
+	require.NoError(t, IsValidFlowTopic(TopicFromChannel(PushBlocks, sporkID), sporkID))
+	require.NoError(t, IsValidFlowTopic(TopicFromChannel(RequestCollections, sporkID), sporkID))
+
+	// cluster topics are not suffixed with the spork ID
+	require.NoError(t, IsValidFlowTopic(TopicFromChannel(ConsensusCluster("some-cluster-id"), sporkID), sporkID))
+
+	// topic of another spork
+	err := IsValidFlowTopic(TopicFromChannel(PushBlocks, flow.Identifier{2}), sporkID)
+	require.True(t, IsInvalidTopicErr(err))
+
+	// topic of an unknown channel
+	err = IsValidFlowTopic(TopicFromChannel("unknown-channel", sporkID), sporkID)
+	require.True(t, IsInvalidTopicErr(err))
+
+	// topic without spork ID
+	err = IsValidFlowTopic(Topic(PushBlocks), sporkID)
+	require.True(t, IsInvalidTopicErr(err))
+
+	// malformed topic
+	err = IsValidFlowTopic("", sporkID)
+	require.True(t, IsInvalidTopicErr(err))
+}
@@ -0,0 +1,27 @@
+package channels
+
+import (
+	"errors"
+	"fmt"
+)
+
+// InvalidTopicErr error wrapper that indicates an error when checking if a Topic is a valid Flow Topic.
+type InvalidTopicErr struct {
+	topic Topic
+	err   error
+}
+
+func (e InvalidTopicErr) Error() string {
+	return fmt.Errorf("invalid topic %s: %w", e.topic, e.err).Error()
+}
+
+// NewInvalidTopicErr returns a new InvalidTopicErr.
+func NewInvalidTopicErr(topic Topic, err error) InvalidTopicErr {
+	return InvalidTopicErr{topic: topic, err: err}
+}
+
+// IsInvalidTopicErr returns true if an error is InvalidTopicErr.
+func IsInvalidTopicErr(err error) bool {
+	var e InvalidTopicErr
+	return errors.As(err, &e)
+}
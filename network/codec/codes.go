@@ -30,6 +30,8 @@ const (
 	CodeRangeRequest
 	CodeBatchRequest
 	CodeBlockResponse
+	CodeSnapshotRequest
+	CodeSnapshotResponse
 
 	// cluster consensus
 	CodeClusterBlockProposal
@@ -45,6 +47,7 @@ const (
 	// core messages for execution & verification
 	CodeExecutionReceipt
 	CodeResultApproval
+	CodeChunkFaultReport
 
 	// execution state synchronization
 	CodeExecutionStateSyncRequest
@@ -104,6 +107,10 @@ func MessageCodeFromInterface(v interface{}) (MessageCode, string, error) {
 		return CodeBatchRequest, s, nil
 	case *messages.BlockResponse:
 		return CodeBlockResponse, s, nil
+	case *messages.SnapshotRequest:
+		return CodeSnapshotRequest, s, nil
+	case *messages.SnapshotResponse:
+		return CodeSnapshotResponse, s, nil
 
 	// collections, guarantees & transactions
 	case *flow.CollectionGuarantee:
@@ -118,6 +125,8 @@ func MessageCodeFromInterface(v interface{}) (MessageCode, string, error) {
 		return CodeExecutionReceipt, s, nil
 	case *flow.ResultApproval:
 		return CodeResultApproval, s, nil
+	case *flow.ChunkFaultReport:
+		return CodeChunkFaultReport, s, nil
 
 	// execution state synchronization
 	case *messages.ExecutionStateSyncRequest:
@@ -191,6 +200,10 @@ func InterfaceFromMessageCode(code MessageCode) (interface{}, string, error) {
 		return &messages.BatchRequest{}, what(&messages.BatchRequest{}), nil
 	case CodeBlockResponse:
 		return &messages.BlockResponse{}, what(&messages.BlockResponse{}), nil
+	case CodeSnapshotRequest:
+		return &messages.SnapshotRequest{}, what(&messages.SnapshotRequest{}), nil
+	case CodeSnapshotResponse:
+		return &messages.SnapshotResponse{}, what(&messages.SnapshotResponse{}), nil
 
 	// collections, guarantees & transactions
 	case CodeCollectionGuarantee:
@@ -205,6 +218,8 @@ func InterfaceFromMessageCode(code MessageCode) (interface{}, string, error) {
 		return &flow.ExecutionReceipt{}, what(&flow.ExecutionReceipt{}), nil
 	case CodeResultApproval:
 		return &flow.ResultApproval{}, what(&flow.ResultApproval{}), nil
+	case CodeChunkFaultReport:
+		return &flow.ChunkFaultReport{}, what(&flow.ChunkFaultReport{}), nil
 
 	// execution state synchronization
 	case CodeExecutionStateSyncRequest:
@@ -0,0 +1,114 @@
+package compressor_test
+
+import (
+	"bytes"
+	"io"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/network"
+	"github.com/onflow/flow-go/network/compressor/internal/samples"
+)
+
+// benchmarkSamplesPerType is the number of distinct messages per message type the compressors are benchmarked on.
+const benchmarkSamplesPerType = 64
+
+// BenchmarkCompress measures the CPU cost of compressing each network message type with each compressor, and reports
+// the size of the compressed message relative to the uncompressed one ("ratio") as well as the compressed size in bytes.
+// Each message is written and flushed on a fresh writer, as unicast streams are typically short-lived.
+//
+// Run with: go test -run=^$ -bench=BenchmarkCompress ./network/compressor/
+func BenchmarkCompress(b *testing.B) {
+	for _, messageType := range sortedMessageTypes() {
+		msgs, err := samples.Encoded(messageType, benchmarkSamplesPerType)
+		require.NoError(b, err)
+
+		for _, name := range sortedCompressorNames() {
+			comp := allCompressors()[name]
+			b.Run(messageType+"/"+name, func(b *testing.B) {
+				rawSize, compressedSize := 0, 0
+				buf := new(bytes.Buffer)
+				b.ReportAllocs()
+				b.ResetTimer()
+				for i := 0; i < b.N; i++ {
+					msg := msgs[i%len(msgs)]
+					buf.Reset()
+					compressTo(b, comp, buf, msg)
+					rawSize += len(msg)
+					compressedSize += buf.Len()
+				}
+				b.ReportMetric(float64(compressedSize)/float64(rawSize), "ratio")
+				b.ReportMetric(float64(compressedSize)/float64(b.N), "bytes/msg")
+			})
+		}
+	}
+}
+
+// BenchmarkDecompress measures the CPU cost of decompressing each network message type with each compressor.
+//
+// Run with: go test -run=^$ -bench=BenchmarkDecompress ./network/compressor/
+func BenchmarkDecompress(b *testing.B) {
+	for _, messageType := range sortedMessageTypes() {
+		msgs, err := samples.Encoded(messageType, benchmarkSamplesPerType)
+		require.NoError(b, err)
+
+		for _, name := range sortedCompressorNames() {
+			comp := allCompressors()[name]
+			compressed := make([][]byte, 0, len(msgs))
+			for _, msg := range msgs {
+				buf := new(bytes.Buffer)
+				compressTo(b, comp, buf, msg)
+				compressed = append(compressed, buf.Bytes())
+			}
+
+			b.Run(messageType+"/"+name, func(b *testing.B) {
+				b.ReportAllocs()
+				b.ResetTimer()
+				for i := 0; i < b.N; i++ {
+					r, err := comp.NewReader(bytes.NewReader(compressed[i%len(compressed)]))
+					if err != nil {
+						b.Fatal(err)
+					}
+					if _, err := io.Copy(io.Discard, r); err != nil {
+						b.Fatal(err)
+					}
+					_ = r.Close()
+				}
+			})
+		}
+	}
+}
+
+// compressTo writes and flushes the given message on a new writer of the compressor, and closes the writer.
+func compressTo(b *testing.B, comp network.Compressor, buf *bytes.Buffer, msg []byte) {
+	w, err := comp.NewWriter(buf)
+	if err != nil {
+		b.Fatal(err)
+	}
+	if _, err := w.Write(msg); err != nil {
+		b.Fatal(err)
+	}
+	if err := w.Flush(); err != nil {
+		b.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		b.Fatal(err)
+	}
+}
+
+func sortedMessageTypes() []string {
+	types := samples.MessageTypes()
+	sort.Strings(types)
+	return types
+}
+
+func sortedCompressorNames() []string {
+	names := make([]string, 0)
+	for name := range allCompressors() {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,88 @@
+package compressor_test
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/network"
+	"github.com/onflow/flow-go/network/compressor"
+	"github.com/onflow/flow-go/network/compressor/internal/samples"
+)
+
+// allCompressors returns all compressors available to the networking layer, keyed by name.
+func allCompressors() map[string]network.Compressor {
+	return map[string]network.Compressor{
+		"gzip":      compressor.GzipStreamCompressor{},
+		"lz4":       compressor.NewLz4Compressor(),
+		"snappy":    compressor.NewSnappyCompressor(),
+		"zstd":      compressor.NewZstdCompressor(),
+		"zstd-dict": compressor.NewFlowZstdCompressor(),
+	}
+}
+
+// TestRoundTrip_AllCompressors evaluates that for each compressor, the messages written and flushed one after another on a
+// stream are read back in the same order, as done by the compressed unicast streams.
+func TestRoundTrip_AllCompressors(t *testing.T) {
+	msgs, err := samples.Encoded("BlockProposal", 3)
+	require.NoError(t, err)
+
+	for name, comp := range allCompressors() {
+		comp := comp
+		t.Run(name, func(t *testing.T) {
+			buf := new(bytes.Buffer)
+			w, err := comp.NewWriter(buf)
+			require.NoError(t, err)
+
+			for _, msg := range msgs {
+				n, err := w.Write(msg)
+				require.NoError(t, err)
+				require.Equal(t, len(msg), n)
+				require.NoError(t, w.Flush())
+			}
+			require.NoError(t, w.Close())
+
+			r, err := comp.NewReader(buf)
+			require.NoError(t, err)
+			for _, msg := range msgs {
+				b := make([]byte, len(msg))
+				_, err := io.ReadFull(r, b)
+				require.NoError(t, err)
+				require.Equal(t, msg, b)
+			}
+			require.NoError(t, r.Close())
+		})
+	}
+}
+
+// TestZstdCompressor_Dictionary evaluates that the shared dictionary improves the compression of small Flow messages, and
+// that a reader lacking the dictionary cannot decompress the data compressed with the dictionary.
+func TestZstdCompressor_Dictionary(t *testing.T) {
+	for _, messageType := range []string{"BlockVote", "SyncRequest", "ResultApproval"} {
+		msgs, err := samples.Encoded(messageType, 1)
+		require.NoError(t, err)
+		msg := msgs[0]
+
+		withDict := compressMessage(t, compressor.NewFlowZstdCompressor(), msg)
+		withoutDict := compressMessage(t, compressor.NewZstdCompressor(), msg)
+		require.Less(t, len(withDict), len(withoutDict), "dictionary did not improve compression of %s", messageType)
+
+		r, err := compressor.NewZstdCompressor().NewReader(bytes.NewReader(withDict))
+		require.NoError(t, err)
+		_, err = io.ReadAll(r)
+		require.Error(t, err)
+	}
+}
+
+// compressMessage compresses the message on a new writer of the given compressor, and returns the compressed bytes.
+func compressMessage(t *testing.T, comp network.Compressor, msg []byte) []byte {
+	buf := new(bytes.Buffer)
+	w, err := comp.NewWriter(buf)
+	require.NoError(t, err)
+	_, err = w.Write(msg)
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+	return buf.Bytes()
+}
@@ -0,0 +1,83 @@
+// dictgen trains the zstd dictionary shared by the Flow nodes for compressing unicast streams. It generates random
+// samples of the wire encoding of each Flow network message type, and trains the dictionary on them using the zstd
+// command line tool, which must be available on the PATH.
+//
+// Usage (from the network/compressor directory):
+//
+//	go generate ./...
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+
+	"github.com/onflow/flow-go/network/compressor/internal/samples"
+)
+
+const (
+	// flowDictionaryID is the fixed id of the Flow messages dictionary, it is embedded in the header of the
+	// compressed frames, so that the receiver can detect frames compressed with an unknown dictionary.
+	flowDictionaryID = 0x466c6f77 // "Flow"
+
+	// maxDictionarySize is the maximum size of the dictionary in bytes. Since the dictionary is loaded by every
+	// compressed stream, it is kept small.
+	maxDictionarySize = 32 * 1024
+)
+
+func main() {
+	out := flag.String("out", "dictionary/flow_messages.zdict", "output path of the trained dictionary")
+	samplesPerType := flag.Int("samples", 200, "number of training samples per message type")
+	flag.Parse()
+
+	if err := train(*out, *samplesPerType); err != nil {
+		fmt.Fprintf(os.Stderr, "could not train dictionary: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func train(out string, samplesPerType int) error {
+	dir, err := os.MkdirTemp("", "flow-zstd-dict")
+	if err != nil {
+		return fmt.Errorf("could not create samples directory: %w", err)
+	}
+	defer os.RemoveAll(dir)
+
+	messageTypes := samples.MessageTypes()
+	sort.Strings(messageTypes)
+
+	files := make([]string, 0, len(messageTypes)*samplesPerType)
+	for _, messageType := range messageTypes {
+		encoded, err := samples.Encoded(messageType, samplesPerType)
+		if err != nil {
+			return fmt.Errorf("could not generate samples: %w", err)
+		}
+		for i, b := range encoded {
+			file := filepath.Join(dir, fmt.Sprintf("%s-%d", messageType, i))
+			if err := os.WriteFile(file, b, 0600); err != nil {
+				return fmt.Errorf("could not write sample: %w", err)
+			}
+			files = append(files, file)
+		}
+	}
+
+	args := []string{
+		"--train",
+		"-q",
+		"-f",
+		"--maxdict=" + strconv.Itoa(maxDictionarySize),
+		"--dictID=" + strconv.Itoa(flowDictionaryID),
+		"-o", out,
+	}
+	cmd := exec.Command("zstd", append(args, files...)...)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("zstd dictionary training failed: %w", err)
+	}
+	return nil
+}
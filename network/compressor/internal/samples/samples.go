@@ -0,0 +1,130 @@
+// Package samples provides representative wire encodings of the Flow network messages. The samples are used to
+// train the shared zstd dictionary of the compressor package, and to benchmark the compressors per message type.
+package samples
+
+import (
+	"fmt"
+	"math/rand"
+
+	"github.com/vmihailenco/msgpack"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/model/messages"
+	"github.com/onflow/flow-go/network/channels"
+	"github.com/onflow/flow-go/network/codec/cbor"
+	"github.com/onflow/flow-go/network/message"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+// sampleGenerator returns a new random instance of a network message, and the channel the message is sent on.
+type sampleGenerator func() (interface{}, channels.Channel)
+
+// generators contains a sample generator per network message type, keyed by the message type name.
+var generators = map[string]sampleGenerator{
+	"BlockProposal": func() (interface{}, channels.Channel) {
+		block := unittest.FullBlockFixture()
+		block.Payload.Guarantees = unittest.CollectionGuaranteesFixture(4)
+		return messages.NewBlockProposal(&block), channels.ConsensusCommittee
+	},
+	"BlockVote": func() (interface{}, channels.Channel) {
+		return &messages.BlockVote{
+			BlockID: unittest.IdentifierFixture(),
+			View:    rand.Uint64(),
+			SigData: unittest.SignatureFixture(),
+		}, channels.ConsensusCommittee
+	},
+	"SyncRequest": func() (interface{}, channels.Channel) {
+		return &messages.SyncRequest{Nonce: rand.Uint64(), Height: rand.Uint64()}, channels.SyncCommittee
+	},
+	"BatchRequest": func() (interface{}, channels.Channel) {
+		return &messages.BatchRequest{Nonce: rand.Uint64(), BlockIDs: unittest.IdentifierListFixture(16)}, channels.SyncCommittee
+	},
+	"BlockResponse": func() (interface{}, channels.Channel) {
+		blocks := unittest.BlockFixtures(4)
+		untrusted := make([]messages.UntrustedBlock, 0, len(blocks))
+		for _, block := range blocks {
+			untrusted = append(untrusted, messages.UntrustedBlockFromInternal(block))
+		}
+		return &messages.BlockResponse{Nonce: rand.Uint64(), Blocks: untrusted}, channels.SyncCommittee
+	},
+	"ClusterBlockProposal": func() (interface{}, channels.Channel) {
+		block := unittest.ClusterBlockFixture()
+		return messages.NewClusterBlockProposal(&block), channels.ConsensusCluster(flow.Emulator)
+	},
+	"CollectionGuarantee": func() (interface{}, channels.Channel) {
+		return unittest.CollectionGuaranteeFixture(), channels.PushGuarantees
+	},
+	"TransactionBody": func() (interface{}, channels.Channel) {
+		tx := unittest.TransactionBodyFixture()
+		return &tx, channels.PushTransactions
+	},
+	"ExecutionReceipt": func() (interface{}, channels.Channel) {
+		return unittest.ExecutionReceiptFixture(unittest.WithResult(unittest.ExecutionResultFixture(unittest.WithChunks(4)))), channels.PushReceipts
+	},
+	"ResultApproval": func() (interface{}, channels.Channel) {
+		return unittest.ResultApprovalFixture(), channels.PushApprovals
+	},
+	"ChunkDataRequest": func() (interface{}, channels.Channel) {
+		return &messages.ChunkDataRequest{ChunkID: unittest.IdentifierFixture(), Nonce: rand.Uint64()}, channels.RequestChunks
+	},
+	"ChunkDataResponse": func() (interface{}, channels.Channel) {
+		return unittest.ChunkDataResponseMsgFixture(unittest.IdentifierFixture()), channels.RequestChunks
+	},
+	"EntityRequest": func() (interface{}, channels.Channel) {
+		return &messages.EntityRequest{Nonce: rand.Uint64(), EntityIDs: unittest.IdentifierListFixture(16)}, channels.RequestCollections
+	},
+	"EntityResponse": func() (interface{}, channels.Channel) {
+		collection := unittest.CollectionFixture(8)
+		// the provider engine encodes the entities with msgpack.
+		blob, err := msgpack.Marshal(&collection)
+		if err != nil {
+			panic(fmt.Errorf("could not encode collection: %w", err))
+		}
+		return &messages.EntityResponse{
+			Nonce:     rand.Uint64(),
+			EntityIDs: flow.IdentifierList{collection.ID()},
+			Blobs:     [][]byte{blob},
+		}, channels.RequestCollections
+	},
+}
+
+// MessageTypes returns the names of the network message types for which samples are available.
+func MessageTypes() []string {
+	types := make([]string, 0, len(generators))
+	for t := range generators {
+		types = append(types, t)
+	}
+	return types
+}
+
+// Encoded returns n random samples of the given message type, encoded as they are sent over a unicast stream, i.e.,
+// the protobuf encoded network envelope with the cbor encoded message as payload.
+// No errors are expected during normal operations.
+func Encoded(messageType string, n int) ([][]byte, error) {
+	generate, ok := generators[messageType]
+	if !ok {
+		return nil, fmt.Errorf("unknown message type: %s", messageType)
+	}
+
+	codec := cbor.NewCodec()
+	encoded := make([][]byte, 0, n)
+	for i := 0; i < n; i++ {
+		msg, channel := generate()
+		payload, err := codec.Encode(msg)
+		if err != nil {
+			return nil, fmt.Errorf("could not encode %s message: %w", messageType, err)
+		}
+		targetID := unittest.IdentifierFixture()
+		envelope := &message.Message{
+			ChannelID: channel.String(),
+			TargetIDs: [][]byte{targetID[:]},
+			Payload:   payload,
+		}
+		b, err := envelope.Marshal()
+		if err != nil {
+			return nil, fmt.Errorf("could not marshal network envelope of %s message: %w", messageType, err)
+		}
+		encoded = append(encoded, b)
+	}
+	return encoded, nil
+}
@@ -0,0 +1,28 @@
+package compressor
+
+import (
+	"io"
+
+	"github.com/golang/snappy"
+
+	"github.com/onflow/flow-go/network"
+)
+
+var _ network.Compressor = (*SnappyCompressor)(nil)
+
+// SnappyCompressor compresses streams using the snappy framing format. Snappy favours speed over compression
+// ratio, hence it is a good fit for CPU bound nodes exchanging moderately compressible messages.
+type SnappyCompressor struct{}
+
+func NewSnappyCompressor() *SnappyCompressor {
+	return &SnappyCompressor{}
+}
+
+func (snappyComp SnappyCompressor) NewReader(r io.Reader) (io.ReadCloser, error) {
+	return io.NopCloser(snappy.NewReader(r)), nil
+}
+
+func (snappyComp SnappyCompressor) NewWriter(w io.Writer) (network.WriteCloseFlusher, error) {
+	// the buffered writer implements Flush, which emits the buffered data as a single snappy chunk.
+	return snappy.NewBufferedWriter(w), nil
+}
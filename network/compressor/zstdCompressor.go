@@ -0,0 +1,78 @@
+package compressor
+
+import (
+	_ "embed"
+	"fmt"
+	"io"
+
+	"github.com/klauspost/compress/zstd"
+
+	"github.com/onflow/flow-go/network"
+)
+
+//go:generate go run ./internal/dictgen -out dictionary/flow_messages.zdict
+
+// flowMessagesDictionary is a zstd dictionary trained on the wire encoding of the Flow network messages.
+// Sharing the dictionary between sender and receiver considerably improves the compression ratio of small
+// messages, as the recurring structure of the messages does not need to be repeated in each compressed stream.
+//
+//go:embed dictionary/flow_messages.zdict
+var flowMessagesDictionary []byte
+
+var _ network.Compressor = (*ZstdCompressor)(nil)
+
+// ZstdCompressor compresses streams using zstd, optionally with a shared dictionary.
+// Both ends of a stream must be configured with the same dictionary.
+type ZstdCompressor struct {
+	dictionary []byte
+}
+
+// NewZstdCompressor returns a zstd compressor without a dictionary.
+func NewZstdCompressor() *ZstdCompressor {
+	return &ZstdCompressor{}
+}
+
+// NewFlowZstdCompressor returns a zstd compressor using the shared dictionary trained on the Flow network messages.
+func NewFlowZstdCompressor() *ZstdCompressor {
+	return &ZstdCompressor{dictionary: flowMessagesDictionary}
+}
+
+func (zstdComp ZstdCompressor) NewReader(r io.Reader) (io.ReadCloser, error) {
+	// a single decoder goroutine suffices, as the stream is read sequentially.
+	opts := []zstd.DOption{zstd.WithDecoderConcurrency(1)}
+	if zstdComp.dictionary != nil {
+		opts = append(opts, zstd.WithDecoderDicts(zstdComp.dictionary))
+	}
+	d, err := zstd.NewReader(r, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("could not create zstd reader: %w", err)
+	}
+	return &zstdReadCloser{d: d}, nil
+}
+
+func (zstdComp ZstdCompressor) NewWriter(w io.Writer) (network.WriteCloseFlusher, error) {
+	// the fastest level is used, as it is the cheapest in CPU and, unlike the default level, makes use of the dictionary.
+	opts := []zstd.EOption{zstd.WithEncoderConcurrency(1), zstd.WithEncoderLevel(zstd.SpeedFastest)}
+	if zstdComp.dictionary != nil {
+		opts = append(opts, zstd.WithEncoderDict(zstdComp.dictionary))
+	}
+	e, err := zstd.NewWriter(w, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("could not create zstd writer: %w", err)
+	}
+	return e, nil
+}
+
+// zstdReadCloser adapts the zstd decoder to io.ReadCloser, as closing the decoder does not return an error.
+type zstdReadCloser struct {
+	d *zstd.Decoder
+}
+
+func (zstdR *zstdReadCloser) Read(p []byte) (int, error) {
+	return zstdR.d.Read(p)
+}
+
+func (zstdR *zstdReadCloser) Close() error {
+	zstdR.d.Close()
+	return nil
+}
@@ -29,6 +29,7 @@ type ConduitFactory interface {
 // a network-agnostic way. In the background, the network layer connects all
 // engines with the same ID over a shared bus, accessible through the conduit.
 type Conduit interface {
+	MisbehaviorReporter
 
 	// Publish submits an event to the network layer for unreliable delivery
 	// to subscribers of the given event on the network layer. It uses a
@@ -51,6 +52,22 @@ type Conduit interface {
 	// TODO: function errors must be documented.
 	Multicast(event interface{}, num uint, targetIDs ...flow.Identifier) error
 
+	// Request sends the request in a reliable way to the given recipient, and blocks until the recipient responds
+	// with a message of which ResponseTo matches the RequestID of the request, or until the context is done. If the
+	// context has no deadline, DefaultRequestTimeout applies. The response is returned to the caller instead of being
+	// delivered to the engine of the conduit; a response arriving after the request timed out is delivered to the
+	// engine like any other message.
+	// Expected error returns during normal operations:
+	//   - DuplicateRequestError if a request with the same request ID is in flight to the recipient on this channel.
+	//   - an error wrapping context.DeadlineExceeded or context.Canceled if no response arrived in time.
+	//   - an error if sending the request fails, see Unicast.
+	Request(ctx context.Context, request RequestMessage, targetID flow.Identifier) (ResponseMessage, error)
+
+	// Respond sends the response to a request in a reliable way to the given recipient, i.e., the origin of the
+	// request. The ResponseTo of the response must match the RequestID of the request.
+	// It returns an error if the unicast fails.
+	Respond(response ResponseMessage, targetID flow.Identifier) error
+
 	// Close unsubscribes from the channels of this conduit. After calling close,
 	// the conduit can no longer be used to send a message.
 	Close() error
@@ -0,0 +1,127 @@
+package requests
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/network"
+	"github.com/onflow/flow-go/network/channels"
+)
+
+// requestKey identifies a request in flight. The response to a request is only accepted from the target of the
+// request, and on the channel the request was sent on.
+type requestKey struct {
+	channel   channels.Channel
+	targetID  flow.Identifier
+	requestID uint64
+}
+
+// Tracker keeps track of the requests in flight sent by the network on behalf of its conduits, and routes the
+// incoming responses to the requests awaiting them.
+type Tracker struct {
+	mu      sync.Mutex
+	pending map[requestKey]chan network.ResponseMessage
+}
+
+// NewTracker returns a new Tracker with no requests in flight.
+func NewTracker() *Tracker {
+	return &Tracker{
+		pending: make(map[requestKey]chan network.ResponseMessage),
+	}
+}
+
+// Request registers the request as in flight, sends it using the given send function, and waits for the response
+// until the context is done. If the context has no deadline, network.DefaultRequestTimeout applies.
+// Expected error returns during normal operations:
+//   - network.DuplicateRequestError if a request with the same key is already in flight.
+//   - an error wrapping context.DeadlineExceeded or context.Canceled if no response arrived in time.
+//   - any error returned by the send function.
+func (t *Tracker) Request(
+	ctx context.Context,
+	channel channels.Channel,
+	request network.RequestMessage,
+	targetID flow.Identifier,
+	send func() error,
+) (network.ResponseMessage, error) {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, network.DefaultRequestTimeout)
+		defer cancel()
+	}
+
+	key := requestKey{channel: channel, targetID: targetID, requestID: request.RequestID()}
+	// the response may arrive before send returns, hence the request is registered beforehand.
+	responses, err := t.register(key)
+	if err != nil {
+		return nil, err
+	}
+	defer t.unregister(key, responses)
+
+	err = send()
+	if err != nil {
+		return nil, err
+	}
+
+	select {
+	case response := <-responses:
+		return response, nil
+	case <-ctx.Done():
+		return nil, fmt.Errorf("no response to request %d from %x on channel %s: %w", key.requestID, targetID, channel, ctx.Err())
+	}
+}
+
+// Deliver hands the message over to the request awaiting it, if the message is a response from the target of a
+// request in flight on the channel. It returns true if the message was delivered to a request, and false if the
+// message should be processed as a regular message.
+func (t *Tracker) Deliver(channel channels.Channel, originID flow.Identifier, message interface{}) bool {
+	response, ok := message.(network.ResponseMessage)
+	if !ok {
+		return false
+	}
+
+	key := requestKey{channel: channel, targetID: originID, requestID: response.ResponseTo()}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	responses, ok := t.pending[key]
+	if !ok {
+		return false
+	}
+	// only the first response is delivered, any further response is processed as a regular message.
+	delete(t.pending, key)
+	responses <- response
+	return true
+}
+
+// InFlight returns the number of requests in flight.
+func (t *Tracker) InFlight() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return len(t.pending)
+}
+
+func (t *Tracker) register(key requestKey) (chan network.ResponseMessage, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if _, ok := t.pending[key]; ok {
+		return nil, network.NewDuplicateRequestError(key.requestID, key.targetID)
+	}
+	// the channel is buffered so that delivering the response never blocks.
+	responses := make(chan network.ResponseMessage, 1)
+	t.pending[key] = responses
+	return responses, nil
+}
+
+// unregister removes the request from the requests in flight, unless it was already removed by Deliver (in which case
+// the key may have been registered again by a new request).
+func (t *Tracker) unregister(key requestKey, responses chan network.ResponseMessage) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.pending[key] == responses {
+		delete(t.pending, key)
+	}
+}
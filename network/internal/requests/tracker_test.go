@@ -0,0 +1,107 @@
+package requests_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/model/messages"
+	"github.com/onflow/flow-go/network"
+	"github.com/onflow/flow-go/network/channels"
+	"github.com/onflow/flow-go/network/internal/requests"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+// TestTracker_Deliver evaluates that a response is only delivered to the request awaiting it, i.e., if it comes from
+// the target of the request, on the channel of the request, and echoes the request ID of the request.
+func TestTracker_Deliver(t *testing.T) {
+	tracker := requests.NewTracker()
+	targetID := unittest.IdentifierFixture()
+	request := &messages.EntityRequest{Nonce: 42}
+
+	var result network.ResponseMessage
+	done := make(chan struct{})
+	sent := make(chan struct{})
+	go func() {
+		defer close(done)
+		var err error
+		result, err = tracker.Request(context.Background(), channels.RequestCollections, request, targetID, func() error {
+			close(sent)
+			return nil
+		})
+		assert.NoError(t, err)
+	}()
+	unittest.RequireCloseBefore(t, sent, time.Second, "request was not sent")
+
+	// responses from another origin, on another channel, or to another request are not delivered.
+	require.False(t, tracker.Deliver(channels.RequestCollections, unittest.IdentifierFixture(), &messages.EntityResponse{Nonce: 42}))
+	require.False(t, tracker.Deliver(channels.RequestChunks, targetID, &messages.EntityResponse{Nonce: 42}))
+	require.False(t, tracker.Deliver(channels.RequestCollections, targetID, &messages.EntityResponse{Nonce: 43}))
+	require.False(t, tracker.Deliver(channels.RequestCollections, targetID, request))
+
+	response := &messages.EntityResponse{Nonce: 42}
+	require.True(t, tracker.Deliver(channels.RequestCollections, targetID, response))
+	unittest.RequireCloseBefore(t, done, time.Second, "request did not return")
+	require.Equal(t, response, result)
+
+	// a second response to the same request is processed as a regular message.
+	require.False(t, tracker.Deliver(channels.RequestCollections, targetID, response))
+	require.Zero(t, tracker.InFlight())
+}
+
+// TestTracker_Timeout evaluates that a request without response returns the error of its context, and that a late
+// response is processed as a regular message.
+func TestTracker_Timeout(t *testing.T) {
+	tracker := requests.NewTracker()
+	targetID := unittest.IdentifierFixture()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err := tracker.Request(ctx, channels.RequestCollections, &messages.EntityRequest{Nonce: 1}, targetID, func() error {
+		return nil
+	})
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+
+	require.False(t, tracker.Deliver(channels.RequestCollections, targetID, &messages.EntityResponse{Nonce: 1}))
+	require.Zero(t, tracker.InFlight())
+}
+
+// TestTracker_Errors evaluates that a request fails if it can't be sent, or if the same request is already in flight.
+func TestTracker_Errors(t *testing.T) {
+	tracker := requests.NewTracker()
+	targetID := unittest.IdentifierFixture()
+	request := &messages.EntityRequest{Nonce: 7}
+
+	sendErr := errors.New("peer unreachable")
+	_, err := tracker.Request(context.Background(), channels.RequestCollections, request, targetID, func() error {
+		return sendErr
+	})
+	require.ErrorIs(t, err, sendErr)
+	require.Zero(t, tracker.InFlight())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	sent := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_, err := tracker.Request(ctx, channels.RequestCollections, request, targetID, func() error {
+			close(sent)
+			return nil
+		})
+		assert.ErrorIs(t, err, context.Canceled)
+	}()
+	unittest.RequireCloseBefore(t, sent, time.Second, "request was not sent")
+
+	_, err = tracker.Request(context.Background(), channels.RequestCollections, request, targetID, func() error {
+		assert.Fail(t, "duplicate request must not be sent")
+		return nil
+	})
+	require.True(t, network.IsDuplicateRequestError(err))
+
+	cancel()
+	unittest.RequireCloseBefore(t, done, time.Second, "request did not return")
+}
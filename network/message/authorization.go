@@ -176,6 +176,30 @@ func initializeMessageAuthConfigsMap() {
 			},
 		},
 	}
+	authorizationConfigs[SnapshotRequest] = MsgAuthConfig{
+		Name: SnapshotRequest,
+		Type: func() interface{} {
+			return new(messages.SnapshotRequest)
+		},
+		Config: map[channels.Channel]ChannelAuthConfig{
+			channels.SyncCommittee: {
+				AuthorizedRoles:  flow.Roles(),
+				AllowedProtocols: Protocols{ProtocolTypePubSub},
+			},
+		},
+	}
+	authorizationConfigs[SnapshotResponse] = MsgAuthConfig{
+		Name: SnapshotResponse,
+		Type: func() interface{} {
+			return new(messages.SnapshotResponse)
+		},
+		Config: map[channels.Channel]ChannelAuthConfig{
+			channels.SyncCommittee: {
+				AuthorizedRoles:  flow.RoleList{flow.RoleConsensus},
+				AllowedProtocols: Protocols{ProtocolTypeUnicast},
+			},
+		},
+	}
 
 	// cluster consensus
 	authorizationConfigs[ClusterBlockProposal] = MsgAuthConfig{
@@ -278,6 +302,18 @@ func initializeMessageAuthConfigsMap() {
 			}, // channel alias ReceiveApprovals = PushApprovals
 		},
 	}
+	authorizationConfigs[ChunkFaultReport] = MsgAuthConfig{
+		Name: ChunkFaultReport,
+		Type: func() interface{} {
+			return new(flow.ChunkFaultReport)
+		},
+		Config: map[channels.Channel]ChannelAuthConfig{
+			channels.PushChunkFaultReports: {
+				AuthorizedRoles:  flow.RoleList{flow.RoleVerification},
+				AllowedProtocols: Protocols{ProtocolTypeUnicast},
+			}, // channel alias ReceiveChunkFaultReports = PushChunkFaultReports
+		},
+	}
 
 	// data exchange for execution of blocks
 	authorizationConfigs[ChunkDataRequest] = MsgAuthConfig{
@@ -423,6 +459,10 @@ func GetMessageAuthConfig(v interface{}) (MsgAuthConfig, error) {
 		return authorizationConfigs[BatchRequest], nil
 	case *messages.BlockResponse:
 		return authorizationConfigs[BlockResponse], nil
+	case *messages.SnapshotRequest:
+		return authorizationConfigs[SnapshotRequest], nil
+	case *messages.SnapshotResponse:
+		return authorizationConfigs[SnapshotResponse], nil
 
 	// cluster consensus
 	case *messages.ClusterBlockProposal:
@@ -445,6 +485,8 @@ func GetMessageAuthConfig(v interface{}) (MsgAuthConfig, error) {
 		return authorizationConfigs[ExecutionReceipt], nil
 	case *flow.ResultApproval:
 		return authorizationConfigs[ResultApproval], nil
+	case *flow.ChunkFaultReport:
+		return authorizationConfigs[ChunkFaultReport], nil
 
 	// data exchange for execution of blocks
 	case *messages.ChunkDataRequest:
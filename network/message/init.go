@@ -52,6 +52,8 @@ const (
 	RangeRequest         = "RangeRequest"
 	BatchRequest         = "BatchRequest"
 	BlockResponse        = "BlockResponse"
+	SnapshotRequest      = "SnapshotRequest"
+	SnapshotResponse     = "SnapshotResponse"
 	ClusterBlockProposal = "ClusterBlockProposal"
 	ClusterBlockVote     = "ClusterBlockVote"
 	ClusterTimeoutObject = "ClusterTimeout"
@@ -60,6 +62,7 @@ const (
 	TransactionBody      = "TransactionBody"
 	ExecutionReceipt     = "ExecutionReceipt"
 	ResultApproval       = "ResultApproval"
+	ChunkFaultReport     = "ChunkFaultReport"
 	ChunkDataRequest     = "ChunkDataRequest"
 	ChunkDataResponse    = "ChunkDataResponse"
 	ApprovalRequest      = "ApprovalRequest"
@@ -28,6 +28,8 @@ type Message struct {
 	ChannelID            string   `protobuf:"bytes,1,opt,name=ChannelID,proto3" json:"ChannelID,omitempty"`
 	TargetIDs            [][]byte `protobuf:"bytes,2,rep,name=TargetIDs,proto3" json:"TargetIDs,omitempty"`
 	Payload              []byte   `protobuf:"bytes,3,opt,name=Payload,proto3" json:"Payload,omitempty"`
+	TraceID              []byte   `protobuf:"bytes,7,opt,name=TraceID,proto3" json:"TraceID,omitempty"`
+	OriginTimestamp      int64    `protobuf:"varint,8,opt,name=OriginTimestamp,proto3" json:"OriginTimestamp,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -87,6 +89,20 @@ func (m *Message) GetPayload() []byte {
 	return nil
 }
 
+func (m *Message) GetTraceID() []byte {
+	if m != nil {
+		return m.TraceID
+	}
+	return nil
+}
+
+func (m *Message) GetOriginTimestamp() int64 {
+	if m != nil {
+		return m.OriginTimestamp
+	}
+	return 0
+}
+
 func init() {
 	proto.RegisterType((*Message)(nil), "message.Message")
 }
@@ -94,16 +110,19 @@ func init() {
 func init() { proto.RegisterFile("message.proto", fileDescriptor_33c57e4bae7b9afd) }
 
 var fileDescriptor_33c57e4bae7b9afd = []byte{
-	// 135 bytes of a gzipped FileDescriptorProto
+	// 179 bytes of a gzipped FileDescriptorProto
 	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xe2, 0xe2, 0xcd, 0x4d, 0x2d, 0x2e,
-	0x4e, 0x4c, 0x4f, 0xd5, 0x2b, 0x28, 0xca, 0x2f, 0xc9, 0x17, 0x62, 0x87, 0x72, 0x95, 0xe2, 0xb9,
-	0xd8, 0x7d, 0x21, 0x4c, 0x21, 0x19, 0x2e, 0x4e, 0xe7, 0x8c, 0xc4, 0xbc, 0xbc, 0xd4, 0x1c, 0x4f,
-	0x17, 0x09, 0x46, 0x05, 0x46, 0x0d, 0xce, 0x20, 0x84, 0x00, 0x48, 0x36, 0x24, 0xb1, 0x28, 0x3d,
-	0xb5, 0xc4, 0xd3, 0xa5, 0x58, 0x82, 0x49, 0x81, 0x59, 0x83, 0x27, 0x08, 0x21, 0x20, 0x24, 0xc1,
-	0xc5, 0x1e, 0x90, 0x58, 0x99, 0x93, 0x9f, 0x98, 0x22, 0xc1, 0xac, 0xc0, 0xa8, 0xc1, 0x13, 0x04,
-	0xe3, 0x3a, 0x09, 0x9c, 0x78, 0x24, 0xc7, 0x78, 0xe1, 0x91, 0x1c, 0xe3, 0x83, 0x47, 0x72, 0x8c,
-	0x33, 0x1e, 0xcb, 0x31, 0x24, 0xb1, 0x81, 0x9d, 0x60, 0x0c, 0x08, 0x00, 0x00, 0xff, 0xff, 0xdd,
-	0xef, 0xa5, 0x7b, 0x93, 0x00, 0x00, 0x00,
+	0x4e, 0x4c, 0x4f, 0xd5, 0x2b, 0x28, 0xca, 0x2f, 0xc9, 0x17, 0x62, 0x87, 0x72, 0x95, 0x16, 0x33,
+	0x72, 0xb1, 0xfb, 0x42, 0xd8, 0x42, 0x32, 0x5c, 0x9c, 0xce, 0x19, 0x89, 0x79, 0x79, 0xa9, 0x39,
+	0x9e, 0x2e, 0x12, 0x8c, 0x0a, 0x8c, 0x1a, 0x9c, 0x41, 0x08, 0x01, 0x90, 0x6c, 0x48, 0x62, 0x51,
+	0x7a, 0x6a, 0x89, 0xa7, 0x4b, 0xb1, 0x04, 0x93, 0x02, 0xb3, 0x06, 0x4f, 0x10, 0x42, 0x40, 0x48,
+	0x82, 0x8b, 0x3d, 0x20, 0xb1, 0x32, 0x27, 0x3f, 0x31, 0x45, 0x82, 0x59, 0x81, 0x51, 0x83, 0x27,
+	0x08, 0xc6, 0x05, 0xc9, 0x84, 0x14, 0x25, 0x26, 0xa7, 0x7a, 0xba, 0x48, 0xb0, 0x43, 0x64, 0xa0,
+	0x5c, 0x21, 0x0d, 0x2e, 0x7e, 0xff, 0xa2, 0xcc, 0xf4, 0xcc, 0xbc, 0x90, 0xcc, 0xdc, 0xd4, 0xe2,
+	0x92, 0xc4, 0xdc, 0x02, 0x09, 0x0e, 0x05, 0x46, 0x0d, 0xe6, 0x20, 0x74, 0x61, 0x27, 0x81, 0x13,
+	0x8f, 0xe4, 0x18, 0x2f, 0x3c, 0x92, 0x63, 0x7c, 0xf0, 0x48, 0x8e, 0x71, 0xc6, 0x63, 0x39, 0x86,
+	0x24, 0x36, 0xb0, 0x3f, 0x8c, 0x01, 0x01, 0x00, 0x00, 0xff, 0xff, 0xc4, 0x06, 0xe0, 0x30, 0xd8,
+	0x00, 0x00, 0x00,
 }
 
 func (m *Message) Marshal() (dAtA []byte, err error) {
@@ -130,6 +149,18 @@ func (m *Message) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 		i -= len(m.XXX_unrecognized)
 		copy(dAtA[i:], m.XXX_unrecognized)
 	}
+	if m.OriginTimestamp != 0 {
+		i = encodeVarintMessage(dAtA, i, uint64(m.OriginTimestamp))
+		i--
+		dAtA[i] = 0x40
+	}
+	if len(m.TraceID) > 0 {
+		i -= len(m.TraceID)
+		copy(dAtA[i:], m.TraceID)
+		i = encodeVarintMessage(dAtA, i, uint64(len(m.TraceID)))
+		i--
+		dAtA[i] = 0x3a
+	}
 	if len(m.Payload) > 0 {
 		i -= len(m.Payload)
 		copy(dAtA[i:], m.Payload)
@@ -187,6 +218,13 @@ func (m *Message) Size() (n int) {
 	if l > 0 {
 		n += 1 + l + sovMessage(uint64(l))
 	}
+	l = len(m.TraceID)
+	if l > 0 {
+		n += 1 + l + sovMessage(uint64(l))
+	}
+	if m.OriginTimestamp != 0 {
+		n += 1 + sovMessage(uint64(m.OriginTimestamp))
+	}
 	if m.XXX_unrecognized != nil {
 		n += len(m.XXX_unrecognized)
 	}
@@ -326,6 +364,59 @@ func (m *Message) Unmarshal(dAtA []byte) error {
 				m.Payload = []byte{}
 			}
 			iNdEx = postIndex
+		case 7:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field TraceID", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMessage
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthMessage
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthMessage
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.TraceID = append(m.TraceID[:0], dAtA[iNdEx:postIndex]...)
+			if m.TraceID == nil {
+				m.TraceID = []byte{}
+			}
+			iNdEx = postIndex
+		case 8:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field OriginTimestamp", wireType)
+			}
+			m.OriginTimestamp = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMessage
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.OriginTimestamp |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
 		default:
 			iNdEx = preIndex
 			skippy, err := skipMessage(dAtA[iNdEx:])
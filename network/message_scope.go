@@ -144,6 +144,11 @@ func (o OutgoingMessageScope) Size() int {
 	return o.msg.Size()
 }
 
+// Payload returns the payload of the message, before encoding.
+func (o OutgoingMessageScope) Payload() interface{} {
+	return o.payload
+}
+
 func (o OutgoingMessageScope) PayloadType() string {
 	return MessageType(o.payload)
 }
@@ -3,10 +3,14 @@
 package mocknetwork
 
 import (
+	context "context"
+
 	flow "github.com/onflow/flow-go/model/flow"
 	channels "github.com/onflow/flow-go/network/channels"
 
 	mock "github.com/stretchr/testify/mock"
+
+	network "github.com/onflow/flow-go/network"
 )
 
 // Adapter is an autogenerated mock type for the Adapter type
@@ -56,6 +60,32 @@ func (_m *Adapter) PublishOnChannel(_a0 channels.Channel, _a1 interface{}, _a2 .
 	return r0
 }
 
+// RequestOnChannel provides a mock function with given fields: _a0, _a1, _a2, _a3
+func (_m *Adapter) RequestOnChannel(_a0 context.Context, _a1 channels.Channel, _a2 network.RequestMessage, _a3 flow.Identifier) (network.ResponseMessage, error) {
+	ret := _m.Called(_a0, _a1, _a2, _a3)
+
+	var r0 network.ResponseMessage
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, channels.Channel, network.RequestMessage, flow.Identifier) (network.ResponseMessage, error)); ok {
+		return rf(_a0, _a1, _a2, _a3)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, channels.Channel, network.RequestMessage, flow.Identifier) network.ResponseMessage); ok {
+		r0 = rf(_a0, _a1, _a2, _a3)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(network.ResponseMessage)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, channels.Channel, network.RequestMessage, flow.Identifier) error); ok {
+		r1 = rf(_a0, _a1, _a2, _a3)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // UnRegisterChannel provides a mock function with given fields: channel
 func (_m *Adapter) UnRegisterChannel(channel channels.Channel) error {
 	ret := _m.Called(channel)
@@ -3,8 +3,12 @@
 package mocknetwork
 
 import (
+	context "context"
+
 	flow "github.com/onflow/flow-go/model/flow"
 	mock "github.com/stretchr/testify/mock"
+
+	network "github.com/onflow/flow-go/network"
 )
 
 // Conduit is an autogenerated mock type for the Conduit type
@@ -68,6 +72,51 @@ func (_m *Conduit) Publish(event interface{}, targetIDs ...flow.Identifier) erro
 	return r0
 }
 
+// ReportMisbehavior provides a mock function with given fields: _a0
+func (_m *Conduit) ReportMisbehavior(_a0 network.MisbehaviorReport) {
+	_m.Called(_a0)
+}
+
+// Request provides a mock function with given fields: ctx, request, targetID
+func (_m *Conduit) Request(ctx context.Context, request network.RequestMessage, targetID flow.Identifier) (network.ResponseMessage, error) {
+	ret := _m.Called(ctx, request, targetID)
+
+	var r0 network.ResponseMessage
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, network.RequestMessage, flow.Identifier) (network.ResponseMessage, error)); ok {
+		return rf(ctx, request, targetID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, network.RequestMessage, flow.Identifier) network.ResponseMessage); ok {
+		r0 = rf(ctx, request, targetID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(network.ResponseMessage)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, network.RequestMessage, flow.Identifier) error); ok {
+		r1 = rf(ctx, request, targetID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Respond provides a mock function with given fields: response, targetID
+func (_m *Conduit) Respond(response network.ResponseMessage, targetID flow.Identifier) error {
+	ret := _m.Called(response, targetID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(network.ResponseMessage, flow.Identifier) error); ok {
+		r0 = rf(response, targetID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // Unicast provides a mock function with given fields: event, targetID
 func (_m *Conduit) Unicast(event interface{}, targetID flow.Identifier) error {
 	ret := _m.Called(event, targetID)
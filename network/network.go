@@ -1,6 +1,8 @@
 package network
 
 import (
+	"context"
+
 	"github.com/ipfs/go-datastore"
 	"github.com/libp2p/go-libp2p/core/protocol"
 
@@ -44,6 +46,10 @@ type Adapter interface {
 	// selected from the specified targetIDs.
 	MulticastOnChannel(channels.Channel, interface{}, uint, ...flow.Identifier) error
 
+	// RequestOnChannel sends the request in a reliable way to the given recipient, and waits for its response until
+	// the context is done.
+	RequestOnChannel(context.Context, channels.Channel, RequestMessage, flow.Identifier) (ResponseMessage, error)
+
 	// UnRegisterChannel unregisters the engine for the specified channel. The engine will no longer be able to send or
 	// receive messages from that channel.
 	UnRegisterChannel(channel channels.Channel) error
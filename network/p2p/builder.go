@@ -63,6 +63,17 @@ type GossipSubBuilder interface {
 	// If the routing system has already been set, a fatal error is logged.
 	SetRoutingSystem(routing.Routing)
 
+	// SetGossipSubRPCInspectorSuite sets the rpc inspector suite of the builder, which inspects and validates the
+	// incoming RPCs of the GossipSub router. If not set, a suite with the default configuration is used.
+	// If the rpc inspector suite has already been set, a fatal error is logged.
+	SetGossipSubRPCInspectorSuite(GossipSubInspectorSuite)
+
+	// SetTopicOracle sets the function returning whether the local node is subscribed to a topic, against which the rpc
+	// inspector suite validates the incoming control messages. The oracle is called on the event loop of the GossipSub
+	// router for each topic of a control message, hence it must be cheap and must not call into the router itself.
+	// If the topic oracle has already been set, a fatal error is logged.
+	SetTopicOracle(func(topic string) bool)
+
 	// Build creates a new GossipSub pubsub system.
 	// It returns the newly created GossipSub pubsub system and any errors encountered during its creation.
 	//
@@ -108,6 +119,7 @@ type NodeBuilder interface {
 	SetRateLimiterDistributor(UnicastRateLimiterDistributor) NodeBuilder
 	SetGossipSubTracer(PubSubTracer) NodeBuilder
 	SetGossipSubScoreTracerInterval(time.Duration) NodeBuilder
+	SetGossipSubRPCInspectorSuite(GossipSubInspectorSuite) NodeBuilder
 	Build() (LibP2PNode, error)
 }
 
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"sync"
+	"time"
 
 	"github.com/dgraph-io/badger/v2"
 	"github.com/libp2p/go-libp2p/core/peer"
@@ -33,16 +34,26 @@ func (s IdentifierSet) Contains(id flow.Identifier) bool {
 // performant lookup. However, the exported API works with `flow.IdentifierList` for
 // blocklist, as this is a broadly supported data structure which lends itself better
 // to config or command-line inputs.
+// Besides the operator-defined `blocklist`, which is persisted in the database, the wrapper
+// maintains a transient set of nodes that are disallow-listed due to misbehavior. This set is
+// managed by the application layer spam prevention (see `alsp` package) and is not persisted,
+// as nodes are allow-listed again once their misbehavior penalty decays.
+// Lastly, operators can disallow-list a node for a limited duration (e.g., to force-disconnect a
+// peer via an admin command). Such entries are not persisted either and lapse once they expire.
 type NodeBlocklistWrapper struct {
 	m  sync.RWMutex
 	db *badger.DB
 
-	identityProvider module.IdentityProvider
-	blocklist        IdentifierSet                           // `IdentifierSet` is a map, hence efficient O(1) lookup
-	distributor      p2p.DisallowListNotificationDistributor // distributor for the blocklist update notifications
+	identityProvider        module.IdentityProvider
+	blocklist               IdentifierSet                           // `IdentifierSet` is a map, hence efficient O(1) lookup
+	misbehaviorDisallowList IdentifierSet                           // nodes disallow-listed due to misbehavior, not persisted
+	timedDisallowList       map[flow.Identifier]time.Time           // nodes disallow-listed until the given expiry time, not persisted
+	distributor             p2p.DisallowListNotificationDistributor // distributor for the blocklist update notifications
+	timeNow                 func() time.Time                        // returns the current time, used to expire the timed disallow list
 }
 
 var _ module.IdentityProvider = (*NodeBlocklistWrapper)(nil)
+var _ p2p.TimedDisallowLister = (*NodeBlocklistWrapper)(nil)
 
 // NewNodeBlocklistWrapper wraps the given `IdentityProvider`. The blocklist is
 // loaded from the database (or assumed to be empty if no database entry is present).
@@ -57,13 +68,24 @@ func NewNodeBlocklistWrapper(
 	}
 
 	return &NodeBlocklistWrapper{
-		db:               db,
-		identityProvider: identityProvider,
-		blocklist:        blocklist,
-		distributor:      distributor,
+		db:                      db,
+		identityProvider:        identityProvider,
+		blocklist:               blocklist,
+		misbehaviorDisallowList: make(IdentifierSet),
+		timedDisallowList:       make(map[flow.Identifier]time.Time),
+		distributor:             distributor,
+		timeNow:                 time.Now,
 	}, nil
 }
 
+// SetTimeNowFunc overrides the function used to retrieve the current time, which determines the
+// expiry of the timed disallow list. Intended for testing only.
+func (w *NodeBlocklistWrapper) SetTimeNowFunc(timeNow func() time.Time) {
+	w.m.Lock()
+	defer w.m.Unlock()
+	w.timeNow = timeNow
+}
+
 // Update sets the wrapper's internal set of blocked nodes to `blocklist`. Empty list and `nil`
 // (equivalent to empty list) are accepted inputs. To avoid legacy entries in the data base, this
 // function purges the entire data base entry if `blocklist` is empty.
@@ -80,15 +102,115 @@ func (w *NodeBlocklistWrapper) Update(blocklist flow.IdentifierList) error {
 		return fmt.Errorf("failed to persist set of blocked nodes to the data base: %w", err)
 	}
 	w.blocklist = b
-	err = w.distributor.DistributeBlockListNotification(blocklist)
+	err = w.distributor.DistributeBlockListNotification(w.withTransientDisallowLists(blocklist))
+
+	if err != nil {
+		return fmt.Errorf("failed to distribute blocklist update notification: %w", err)
+	}
+
+	return nil
+}
+
+// DisallowListNode adds the given node to the transient set of nodes that are disallow-listed due to
+// misbehavior, and notifies the consumers of the updated disallow list, i.e., the node is disconnected.
+// Contrary to the operator-defined blocklist, this set is not persisted in the data base.
+// No errors are expected during normal operations.
+func (w *NodeBlocklistWrapper) DisallowListNode(nodeID flow.Identifier) error {
+	w.m.Lock()
+	defer w.m.Unlock()
 
+	w.misbehaviorDisallowList[nodeID] = struct{}{}
+	err := w.distributor.DistributeBlockListNotification(w.withTransientDisallowLists(w.blocklistAsList()))
 	if err != nil {
 		return fmt.Errorf("failed to distribute blocklist update notification: %w", err)
 	}
+	return nil
+}
+
+// AllowListNode removes the given node from the transient set of nodes that are disallow-listed due to
+// misbehavior. The node remains blocked if it is on the operator-defined blocklist.
+// No errors are expected during normal operations.
+func (w *NodeBlocklistWrapper) AllowListNode(nodeID flow.Identifier) error {
+	w.m.Lock()
+	defer w.m.Unlock()
+
+	delete(w.misbehaviorDisallowList, nodeID)
+	err := w.distributor.DistributeBlockListNotification(w.withTransientDisallowLists(w.blocklistAsList()))
+	if err != nil {
+		return fmt.Errorf("failed to distribute blocklist update notification: %w", err)
+	}
+	return nil
+}
+
+// DisallowListNodeFor disallow-lists the given node for the given duration, and notifies the consumers of
+// the updated disallow list, i.e., the node is disconnected. Once the duration elapsed, the node is no
+// longer reported as ejected, so that connections to it can be re-established. Disallow-listing a node
+// that is already on the timed disallow list overrides its expiry time.
+// Contrary to the operator-defined blocklist, the timed disallow list is not persisted in the data base.
+// No errors are expected during normal operations.
+func (w *NodeBlocklistWrapper) DisallowListNodeFor(nodeID flow.Identifier, duration time.Duration) error {
+	w.m.Lock()
+	defer w.m.Unlock()
 
+	w.timedDisallowList[nodeID] = w.timeNow().Add(duration)
+	err := w.distributor.DistributeBlockListNotification(w.withTransientDisallowLists(w.blocklistAsList()))
+	if err != nil {
+		return fmt.Errorf("failed to distribute blocklist update notification: %w", err)
+	}
 	return nil
 }
 
+// withTransientDisallowLists returns the given operator-defined blocklist extended by the nodes that are
+// disallow-listed due to misbehavior or on the timed disallow list (and not already on the blocklist).
+// Expired entries of the timed disallow list are pruned as a side effect.
+// Not concurrency safe; the caller must hold the (write) lock.
+func (w *NodeBlocklistWrapper) withTransientDisallowLists(blocklist flow.IdentifierList) flow.IdentifierList {
+	now := w.timeNow()
+	for nodeID, expiry := range w.timedDisallowList {
+		if !now.Before(expiry) {
+			delete(w.timedDisallowList, nodeID)
+		}
+	}
+
+	if len(w.misbehaviorDisallowList) == 0 && len(w.timedDisallowList) == 0 {
+		return blocklist
+	}
+	identifiers := make(flow.IdentifierList, 0, len(blocklist)+len(w.misbehaviorDisallowList)+len(w.timedDisallowList))
+	identifiers = append(identifiers, blocklist...)
+	for i := range w.misbehaviorDisallowList {
+		if !w.blocklist.Contains(i) {
+			identifiers = append(identifiers, i)
+		}
+	}
+	for i := range w.timedDisallowList {
+		if !w.blocklist.Contains(i) && !w.misbehaviorDisallowList.Contains(i) {
+			identifiers = append(identifiers, i)
+		}
+	}
+	return identifiers
+}
+
+// blocklistAsList returns the operator-defined blocklist as a list.
+// Not concurrency safe; the caller must hold the lock.
+func (w *NodeBlocklistWrapper) blocklistAsList() flow.IdentifierList {
+	identifiers := make(flow.IdentifierList, 0, len(w.blocklist))
+	for i := range w.blocklist {
+		identifiers = append(identifiers, i)
+	}
+	return identifiers
+}
+
+// isBlocked returns true if the node is either on the operator-defined blocklist, disallow-listed due to
+// misbehavior, or on the timed disallow list with an expiry time in the future.
+// Not concurrency safe; the caller must hold the lock.
+func (w *NodeBlocklistWrapper) isBlocked(nodeID flow.Identifier) bool {
+	if w.blocklist.Contains(nodeID) || w.misbehaviorDisallowList.Contains(nodeID) {
+		return true
+	}
+	expiry, ok := w.timedDisallowList[nodeID]
+	return ok && w.timeNow().Before(expiry)
+}
+
 // ClearBlocklist purges the set of blocked node IDs. Convenience function
 // equivalent to w.Update(nil). No errors are expected during normal operations.
 func (w *NodeBlocklistWrapper) ClearBlocklist() error {
@@ -100,11 +222,7 @@ func (w *NodeBlocklistWrapper) GetBlocklist() flow.IdentifierList {
 	w.m.RLock()
 	defer w.m.RUnlock()
 
-	identifiers := make(flow.IdentifierList, 0, len(w.blocklist))
-	for i := range w.blocklist {
-		identifiers = append(identifiers, i)
-	}
-	return identifiers
+	return w.blocklistAsList()
 }
 
 // Identities returns the full identities of _all_ nodes currently known to the
@@ -123,7 +241,7 @@ func (w *NodeBlocklistWrapper) Identities(filter flow.IdentityFilter) flow.Ident
 	idtx := make(flow.IdentityList, 0, len(identities))
 	w.m.RLock()
 	for _, identity := range identities {
-		if w.blocklist.Contains(identity.NodeID) {
+		if w.isBlocked(identity.NodeID) {
 			var i = *identity // shallow copy is sufficient, because `Ejected` flag is in top-level struct
 			i.Ejected = true
 			if filter(&i) { // we need to check the filter here again, because the filter might drop ejected nodes and we are modifying the ejected status here
@@ -148,7 +266,8 @@ func (w *NodeBlocklistWrapper) ByNodeID(identifier flow.Identifier) (*flow.Ident
 	return w.setEjectedIfBlocked(identity), b
 }
 
-// setEjectedIfBlocked checks whether the node with the given identity is on the `blocklist`.
+// setEjectedIfBlocked checks whether the node with the given identity is on the `blocklist`, or
+// disallow-listed due to misbehavior.
 // Shortcuts:
 //   - If the node's identity is nil, there is nothing to do because we don't generate identities here.
 //   - If the node is already ejected, we don't have to check the blocklist.
@@ -158,7 +277,7 @@ func (w *NodeBlocklistWrapper) setEjectedIfBlocked(identity *flow.Identity) *flo
 	}
 
 	w.m.RLock()
-	isBlocked := w.blocklist.Contains(identity.NodeID)
+	isBlocked := w.isBlocked(identity.NodeID)
 	w.m.RUnlock()
 	if !isBlocked {
 		return identity
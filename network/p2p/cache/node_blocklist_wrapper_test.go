@@ -3,6 +3,7 @@ package cache_test
 import (
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/dgraph-io/badger/v2"
 	"github.com/libp2p/go-libp2p/core/peer"
@@ -266,6 +267,74 @@ func (s *NodeBlocklistWrapperTestSuite) TestBlocklistAddRemove() {
 	}
 }
 
+// TestMisbehaviorDisallowList tests disallow-listing nodes due to misbehavior:
+//   - a node disallow-listed due to misbehavior is returned with `Ejected = true`, and the
+//     disallow list notification includes the node in addition to the operator-defined blocklist.
+//   - the misbehavior disallow list is not part of the operator-defined blocklist, i.e., not
+//     returned by `GetBlocklist` and hence not persisted.
+//   - after allow-listing the node again, its original `Ejected` flag is returned.
+func (s *NodeBlocklistWrapperTestSuite) TestMisbehaviorDisallowList() {
+	originalIdentity := unittest.IdentityFixture()
+	s.provider.On("ByNodeID", originalIdentity.NodeID).Return(originalIdentity, true)
+
+	blocklist := unittest.IdentifierListFixture(3)
+	s.distributor.On("DistributeBlockListNotification", blocklist).Return(nil).Once()
+	require.NoError(s.T(), s.wrapper.Update(blocklist))
+
+	s.distributor.On("DistributeBlockListNotification", mock.Anything).Run(func(args mock.Arguments) {
+		list := args.Get(0).(flow.IdentifierList)
+		require.Equal(s.T(), append(blocklist.Copy(), originalIdentity.NodeID).Lookup(), list.Lookup())
+	}).Return(nil).Once()
+	require.NoError(s.T(), s.wrapper.DisallowListNode(originalIdentity.NodeID))
+
+	i, found := s.wrapper.ByNodeID(originalIdentity.NodeID)
+	require.True(s.T(), found)
+	require.True(s.T(), i.Ejected)
+	require.False(s.T(), originalIdentity.Ejected)
+	require.Equal(s.T(), blocklist.Lookup(), s.wrapper.GetBlocklist().Lookup())
+
+	s.distributor.On("DistributeBlockListNotification", mock.Anything).Run(func(args mock.Arguments) {
+		list := args.Get(0).(flow.IdentifierList)
+		require.Equal(s.T(), blocklist.Lookup(), list.Lookup())
+	}).Return(nil).Once()
+	require.NoError(s.T(), s.wrapper.AllowListNode(originalIdentity.NodeID))
+
+	i, found = s.wrapper.ByNodeID(originalIdentity.NodeID)
+	require.True(s.T(), found)
+	require.False(s.T(), i.Ejected)
+}
+
+// TestTimedDisallowList tests disallow-listing nodes for a limited duration:
+//   - a node on the timed disallow list is returned with `Ejected = true`, and the
+//     disallow list notification includes the node in addition to the operator-defined blocklist.
+//   - the timed disallow list is not part of the operator-defined blocklist.
+//   - once the duration elapsed, the original `Ejected` flag is returned, and the node is
+//     no longer included in subsequent disallow list notifications.
+func (s *NodeBlocklistWrapperTestSuite) TestTimedDisallowList() {
+	now := time.Now()
+	s.wrapper.SetTimeNowFunc(func() time.Time { return now })
+
+	originalIdentity := unittest.IdentityFixture()
+	s.provider.On("ByNodeID", originalIdentity.NodeID).Return(originalIdentity, true)
+
+	s.distributor.On("DistributeBlockListNotification", flow.IdentifierList{originalIdentity.NodeID}).Return(nil).Once()
+	require.NoError(s.T(), s.wrapper.DisallowListNodeFor(originalIdentity.NodeID, time.Minute))
+
+	i, found := s.wrapper.ByNodeID(originalIdentity.NodeID)
+	require.True(s.T(), found)
+	require.True(s.T(), i.Ejected)
+	require.Empty(s.T(), s.wrapper.GetBlocklist())
+
+	now = now.Add(time.Minute)
+	i, found = s.wrapper.ByNodeID(originalIdentity.NodeID)
+	require.True(s.T(), found)
+	require.False(s.T(), i.Ejected)
+
+	blocklist := unittest.IdentifierListFixture(3)
+	s.distributor.On("DistributeBlockListNotification", blocklist).Return(nil).Once()
+	require.NoError(s.T(), s.wrapper.Update(blocklist))
+}
+
 // TestUpdate tests updating, clearing and retrieving the blocklist.
 // This test verifies that the wrapper updates _its own internal state_ correctly.
 // Note:
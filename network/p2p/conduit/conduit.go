@@ -8,6 +8,7 @@ import (
 	"github.com/onflow/flow-go/module/component"
 	"github.com/onflow/flow-go/module/irrecoverable"
 	"github.com/onflow/flow-go/network"
+	"github.com/onflow/flow-go/network/alsp"
 	"github.com/onflow/flow-go/network/channels"
 )
 
@@ -16,11 +17,30 @@ import (
 // network Adapter.
 type DefaultConduitFactory struct {
 	*component.ComponentManager
-	adapter network.Adapter
+	adapter            network.Adapter
+	misbehaviorManager network.MisbehaviorReportManager
 }
 
-func NewDefaultConduitFactory() *DefaultConduitFactory {
-	d := &DefaultConduitFactory{}
+// DefaultConduitFactoryOpt is a function that applies an option to the DefaultConduitFactory.
+type DefaultConduitFactoryOpt func(*DefaultConduitFactory)
+
+// WithMisbehaviorManager overrides the misbehavior manager for the conduit factory. The misbehavior manager handles
+// the misbehavior reports of the engines on the conduits created by the factory. By default, the reports are dropped.
+func WithMisbehaviorManager(misbehaviorManager network.MisbehaviorReportManager) DefaultConduitFactoryOpt {
+	return func(d *DefaultConduitFactory) {
+		d.misbehaviorManager = misbehaviorManager
+	}
+}
+
+func NewDefaultConduitFactory(opts ...DefaultConduitFactoryOpt) *DefaultConduitFactory {
+	d := &DefaultConduitFactory{
+		misbehaviorManager: alsp.NewNoopMisbehaviorReportManager(),
+	}
+
+	for _, opt := range opts {
+		opt(d)
+	}
+
 	// worker added so conduit factory doesn't immediately shut down when it's started
 	cm := component.NewComponentManagerBuilder().
 		AddWorker(func(ctx irrecoverable.SignalerContext, ready component.ReadyFunc) {
@@ -57,10 +77,11 @@ func (d *DefaultConduitFactory) NewConduit(ctx context.Context, channel channels
 	child, cancel := context.WithCancel(ctx)
 
 	return &Conduit{
-		ctx:     child,
-		cancel:  cancel,
-		channel: channel,
-		adapter: d.adapter,
+		ctx:                child,
+		cancel:             cancel,
+		channel:            channel,
+		adapter:            d.adapter,
+		misbehaviorManager: d.misbehaviorManager,
 	}, nil
 }
 
@@ -68,12 +89,15 @@ func (d *DefaultConduitFactory) NewConduit(ctx context.Context, channel channels
 // sending messages within a single engine process. It sends all messages to
 // what can be considered a bus reserved for that specific engine.
 type Conduit struct {
-	ctx     context.Context
-	cancel  context.CancelFunc
-	channel channels.Channel
-	adapter network.Adapter
+	ctx                context.Context
+	cancel             context.CancelFunc
+	channel            channels.Channel
+	adapter            network.Adapter
+	misbehaviorManager network.MisbehaviorReportManager
 }
 
+var _ network.Conduit = (*Conduit)(nil)
+
 // Publish sends an event to the network layer for unreliable delivery
 // to subscribers of the given event on the network layer. It uses a
 // publish-subscribe layer and can thus not guarantee that the specified
@@ -104,6 +128,32 @@ func (c *Conduit) Multicast(event interface{}, num uint, targetIDs ...flow.Ident
 	return c.adapter.MulticastOnChannel(c.channel, event, num, targetIDs...)
 }
 
+// Request sends the request in a reliable way to the given recipient, and blocks until the recipient responds, or
+// until the context is done. See network.Conduit for the expected errors.
+func (c *Conduit) Request(ctx context.Context, request network.RequestMessage, targetID flow.Identifier) (network.ResponseMessage, error) {
+	if c.ctx.Err() != nil {
+		return nil, fmt.Errorf("conduit for channel %s closed", c.channel)
+	}
+	return c.adapter.RequestOnChannel(ctx, c.channel, request, targetID)
+}
+
+// Respond sends the response to a request in a reliable way to the given recipient.
+// It returns an error if the unicast fails.
+func (c *Conduit) Respond(response network.ResponseMessage, targetID flow.Identifier) error {
+	if c.ctx.Err() != nil {
+		return fmt.Errorf("conduit for channel %s closed", c.channel)
+	}
+	return c.adapter.UnicastOnChannel(c.channel, response, targetID)
+}
+
+// ReportMisbehavior reports the misbehavior of a node on sending a message to the current node that appears valid
+// based on the networking layer but is considered invalid by the current node based on the Flow protocol.
+// The misbehavior is reported to the networking layer to penalize the misbehaving node.
+// The implementation is thread-safe and non-blocking.
+func (c *Conduit) ReportMisbehavior(report network.MisbehaviorReport) {
+	c.misbehaviorManager.HandleMisbehaviorReport(c.channel, report)
+}
+
 func (c *Conduit) Close() error {
 	if c.ctx.Err() != nil {
 		return fmt.Errorf("conduit for channel %s already closed", c.channel)
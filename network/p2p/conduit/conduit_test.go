@@ -1,6 +1,7 @@
 package conduit_test
 
 import (
+	"context"
 	"fmt"
 	"testing"
 
@@ -8,6 +9,11 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/onflow/flow-go/network"
+	"github.com/onflow/flow-go/network/alsp"
+	"github.com/onflow/flow-go/network/channels"
+	"github.com/onflow/flow-go/network/mocknetwork"
+	"github.com/onflow/flow-go/network/p2p/conduit"
+	"github.com/onflow/flow-go/utils/unittest"
 )
 
 func TestWrappedByMultiError(t *testing.T) {
@@ -32,3 +38,20 @@ func TestNestedWrappedMultiError(t *testing.T) {
 	outerError = multierror.Append(outerError, fmt.Errorf("inner: %w", err))
 	require.True(t, network.AllPeerUnreachableError(outerError.WrappedErrors()...))
 }
+
+// TestConduit_ReportMisbehavior tests that the misbehavior reports of the engines on a conduit are forwarded to
+// the misbehavior manager of the conduit factory, together with the channel of the conduit.
+func TestConduit_ReportMisbehavior(t *testing.T) {
+	manager := mocknetwork.NewMisbehaviorReportManager(t)
+	factory := conduit.NewDefaultConduitFactory(conduit.WithMisbehaviorManager(manager))
+	require.NoError(t, factory.RegisterAdapter(mocknetwork.NewAdapter(t)))
+
+	c, err := factory.NewConduit(context.Background(), channels.TestNetworkChannel)
+	require.NoError(t, err)
+
+	report, err := alsp.NewMisbehaviorReport(unittest.IdentifierFixture(), alsp.InvalidMessage)
+	require.NoError(t, err)
+	manager.On("HandleMisbehaviorReport", channels.TestNetworkChannel, report).Return().Once()
+
+	c.ReportMisbehavior(report)
+}
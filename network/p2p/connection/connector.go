@@ -110,6 +110,8 @@ func (l *Libp2pConnector) connectToPeers(ctx context.Context, peerIDs peer.IDSli
 // pruneAllConnectionsExcept trims all connections of the node from peers not part of peerIDs.
 // A node would have created such extra connections earlier when the identity list may have been different, or
 // it may have been target of such connections from node which have now been excluded.
+// Pruning requires the topology to be symmetric, i.e., a peer is part of the fanout of the node if and only if the node
+// is part of the fanout of the peer. Otherwise, the node would close the connections that its peers keep dialing.
 func (l *Libp2pConnector) pruneAllConnectionsExcept(peerIDs peer.IDSlice) {
 	// convert the peerInfos to a peer.ID -> bool map
 	peersToKeep := make(map[peer.ID]bool, len(peerIDs))
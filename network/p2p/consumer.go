@@ -1,6 +1,9 @@
 package p2p
 
 import (
+	"math/rand"
+	"time"
+
 	"github.com/libp2p/go-libp2p/core/peer"
 
 	"github.com/onflow/flow-go/model/flow"
@@ -19,6 +22,15 @@ type DisallowListConsumer interface {
 	OnNodeDisallowListUpdate(list flow.IdentifierList)
 }
 
+// TimedDisallowLister disallow-lists nodes for a limited duration, e.g., to force-disconnect a peer on
+// operator request. Once the duration elapsed, connections to the node can be re-established.
+type TimedDisallowLister interface {
+	// DisallowListNodeFor disallow-lists the given node for the given duration, i.e., the node is disconnected
+	// and is considered ejected until the duration elapsed.
+	// Implementation must be concurrency safe. No errors are expected during normal operations.
+	DisallowListNodeFor(nodeID flow.Identifier, duration time.Duration) error
+}
+
 // ControlMessageType is the type of control message, as defined in the libp2p pubsub spec.
 type ControlMessageType string
 
@@ -29,6 +41,11 @@ const (
 	CtrlMsgPrune ControlMessageType = "PRUNE"
 )
 
+// ControlMessageTypes returns list of all libp2p control message types.
+func ControlMessageTypes() []ControlMessageType {
+	return []ControlMessageType{CtrlMsgIHave, CtrlMsgIWant, CtrlMsgGraft, CtrlMsgPrune}
+}
+
 // DisallowListUpdateNotification is the event that is submitted to the distributor when the disallow list is updated.
 type DisallowListUpdateNotification struct {
 	DisallowList flow.IdentifierList
@@ -79,6 +96,22 @@ type InvalidControlMessageNotification struct {
 	MsgType ControlMessageType
 	// Count is the number of invalid control messages received from the peer that is reported in this notification.
 	Count uint64
+	// Err any error associated with the invalid control message.
+	Err error
+	// Nonce makes each notification unique. The distributor queues notifications in a store that drops
+	// identical entries, hence without the nonce repeated reports of the same misbehavior would be discarded.
+	Nonce uint64
+}
+
+// NewInvalidControlMessageNotification returns a new *InvalidControlMessageNotification.
+func NewInvalidControlMessageNotification(peerID peer.ID, msgType ControlMessageType, count uint64, err error) *InvalidControlMessageNotification {
+	return &InvalidControlMessageNotification{
+		PeerID:  peerID,
+		MsgType: msgType,
+		Count:   count,
+		Err:     err,
+		Nonce:   rand.Uint64(),
+	}
 }
 
 // GossipSubInvalidControlMessageNotificationConsumer is the interface for the consumer that consumes gossip sub inspector notifications.
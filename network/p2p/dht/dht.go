@@ -59,3 +59,10 @@ func AsServer() dht.Option {
 func AsClient() dht.Option {
 	return dht.Mode(dht.ModeClient)
 }
+
+// RoutingTableFilter restricts the routing table of the DHT to the peers passing the filter.
+func RoutingTableFilter(allowed func(peer.ID) bool) dht.Option {
+	return dht.RoutingTableFilter(func(_ interface{}, pid peer.ID) bool {
+		return allowed(pid)
+	})
+}
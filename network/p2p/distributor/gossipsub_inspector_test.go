@@ -2,6 +2,7 @@ package distributor_test
 
 import (
 	"context"
+	"fmt"
 	"math/rand"
 	"sync"
 	"testing"
@@ -83,6 +84,43 @@ func TestGossipSubInspectorNotification(t *testing.T) {
 	unittest.RequireCloseBefore(t, g.Done(), 100*time.Millisecond, "could not stop distributor")
 }
 
+// TestGossipSubInspectorNotification_RepeatedMisbehavior tests that repeated reports of the same misbehavior by the same
+// peer are all delivered to the consumer, i.e., they are not discarded as duplicates by the notification queue before the
+// distributor processes them.
+func TestGossipSubInspectorNotification_RepeatedMisbehavior(t *testing.T) {
+	g := distributor.DefaultGossipSubInspectorNotificationDistributor(unittest.Logger())
+
+	c := mockp2p.NewGossipSubInvalidControlMessageNotificationConsumer(t)
+	g.AddConsumer(c)
+
+	peerID := p2ptest.PeerIdFixture(t)
+	count := 10
+	done := sync.WaitGroup{}
+	done.Add(count)
+	c.On("OnInvalidControlMessageNotification", mock.Anything).Run(func(args mock.Arguments) {
+		notification, ok := args.Get(0).(*p2p.InvalidControlMessageNotification)
+		require.True(t, ok)
+		require.Equal(t, peerID, notification.PeerID)
+		done.Done()
+	}).Return()
+
+	// distribute the notifications before starting the distributor, so that all of them are queued at once.
+	for i := 0; i < count; i++ {
+		notification := p2p.NewInvalidControlMessageNotification(peerID, p2p.CtrlMsgGraft, 1, fmt.Errorf("invalid graft"))
+		require.NoError(t, g.DistributeInvalidControlMessageNotification(notification))
+	}
+
+	cancelCtx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ctx, _ := irrecoverable.WithSignaler(cancelCtx)
+	g.Start(ctx)
+
+	unittest.RequireCloseBefore(t, g.Ready(), 100*time.Millisecond, "could not start distributor")
+	unittest.RequireReturnsBefore(t, done.Wait, 1*time.Second, "repeated events are not received by consumer")
+	cancel()
+	unittest.RequireCloseBefore(t, g.Done(), 100*time.Millisecond, "could not stop distributor")
+}
+
 func invalidControlMessageNotificationListFixture(t *testing.T, n int) []*p2p.InvalidControlMessageNotification {
 	list := make([]*p2p.InvalidControlMessageNotification, n)
 	for i := 0; i < n; i++ {
@@ -0,0 +1,47 @@
+package inspector
+
+import (
+	pubsub "github.com/libp2p/go-libp2p-pubsub"
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/rs/zerolog"
+
+	"github.com/onflow/flow-go/module"
+	"github.com/onflow/flow-go/module/component"
+	"github.com/onflow/flow-go/network/p2p"
+	"github.com/onflow/flow-go/network/p2p/p2pnode"
+)
+
+const (
+	// rpcInspectorComponentName the rpc inspector component name.
+	rpcInspectorComponentName = "gossipsub_rpc_metrics_observer_inspector"
+)
+
+// ControlMsgMetricsInspector is an RPC inspector that records metrics on the control messages of the incoming RPCs.
+// It never rejects an RPC.
+type ControlMsgMetricsInspector struct {
+	component.Component
+	metrics *p2pnode.GossipSubControlMessageMetrics
+}
+
+var _ p2p.GossipSubRPCInspector = (*ControlMsgMetricsInspector)(nil)
+
+// NewControlMsgMetricsInspector returns a new ControlMsgMetricsInspector.
+func NewControlMsgMetricsInspector(logger zerolog.Logger, metrics module.GossipSubRouterMetrics) *ControlMsgMetricsInspector {
+	return &ControlMsgMetricsInspector{
+		Component: component.NewComponentManagerBuilder().
+			AddWorker(component.NoopWorker).
+			Build(),
+		metrics: p2pnode.NewGossipSubControlMessageMetrics(metrics, logger),
+	}
+}
+
+// Name returns the name of the rpc inspector.
+func (c *ControlMsgMetricsInspector) Name() string {
+	return rpcInspectorComponentName
+}
+
+// Inspect records metrics on the control messages of the incoming RPC. It never returns an error.
+func (c *ControlMsgMetricsInspector) Inspect(from peer.ID, rpc *pubsub.RPC) error {
+	c.metrics.ObserveRPC(from, rpc)
+	return nil
+}
@@ -0,0 +1,84 @@
+package inspector
+
+import (
+	"fmt"
+
+	pubsub "github.com/libp2p/go-libp2p-pubsub"
+	"github.com/libp2p/go-libp2p/core/peer"
+
+	"github.com/onflow/flow-go/module/component"
+	"github.com/onflow/flow-go/module/irrecoverable"
+	"github.com/onflow/flow-go/network/p2p"
+	"github.com/onflow/flow-go/network/p2p/inspector/validation"
+)
+
+// GossipSubInspectorSuite encapsulates the RPC inspectors of a GossipSub router, i.e., the metrics inspector and the
+// control message validation inspector, as well as the notification distributor that reports the invalid control
+// messages detected by the validation inspector to its consumers.
+type GossipSubInspectorSuite struct {
+	component.Component
+	metricsInspector    *ControlMsgMetricsInspector
+	validationInspector *validation.ControlMsgValidationInspector
+	distributor         p2p.GossipSubInspectorNotificationDistributor
+}
+
+var _ p2p.GossipSubInspectorSuite = (*GossipSubInspectorSuite)(nil)
+
+// NewGossipSubInspectorSuite returns a new GossipSubInspectorSuite. The suite starts the inspectors and the distributor,
+// and is ready once all of them are ready.
+func NewGossipSubInspectorSuite(
+	metricsInspector *ControlMsgMetricsInspector,
+	validationInspector *validation.ControlMsgValidationInspector,
+	distributor p2p.GossipSubInspectorNotificationDistributor,
+) *GossipSubInspectorSuite {
+	s := &GossipSubInspectorSuite{
+		metricsInspector:    metricsInspector,
+		validationInspector: validationInspector,
+		distributor:         distributor,
+	}
+
+	builder := component.NewComponentManagerBuilder()
+	for _, c := range []component.Component{metricsInspector, validationInspector, distributor} {
+		c := c // capture loop variable
+		builder.AddWorker(func(ctx irrecoverable.SignalerContext, ready component.ReadyFunc) {
+			c.Start(ctx)
+			select {
+			case <-ctx.Done():
+			case <-c.Ready():
+				ready()
+			}
+			<-c.Done()
+		})
+	}
+	s.Component = builder.Build()
+
+	return s
+}
+
+// InspectFunc returns the inspect function that is invoked by the GossipSub router on each incoming RPC.
+// The RPC is first observed by the metrics inspector, and then validated by the validation inspector; it is dropped
+// if the validation fails.
+func (s *GossipSubInspectorSuite) InspectFunc() func(peer.ID, *pubsub.RPC) error {
+	return func(from peer.ID, rpc *pubsub.RPC) error {
+		for _, inspector := range []p2p.GossipSubRPCInspector{s.metricsInspector, s.validationInspector} {
+			err := inspector.Inspect(from, rpc)
+			if err != nil {
+				return fmt.Errorf("rpc rejected by inspector %s: %w", inspector.Name(), err)
+			}
+		}
+		return nil
+	}
+}
+
+// AddInvalidControlMessageConsumer adds a consumer to the notifications of invalid control messages detected by the
+// validation inspector.
+func (s *GossipSubInspectorSuite) AddInvalidControlMessageConsumer(consumer p2p.GossipSubInvalidControlMessageNotificationConsumer) {
+	s.distributor.AddConsumer(consumer)
+}
+
+// SetTopicOracle sets the topic oracle of the validation inspector, i.e., a function that returns whether the
+// local node is subscribed to a topic.
+// The topic oracle can be set only once; any attempt to set it again results in an error.
+func (s *GossipSubInspectorSuite) SetTopicOracle(topicOracle func(topic string) bool) error {
+	return s.validationInspector.SetTopicOracle(topicOracle)
+}
@@ -0,0 +1,356 @@
+package validation
+
+import (
+	"fmt"
+	"math/rand"
+	"sync"
+	"time"
+
+	lru "github.com/hashicorp/golang-lru"
+	pubsub "github.com/libp2p/go-libp2p-pubsub"
+	pubsub_pb "github.com/libp2p/go-libp2p-pubsub/pb"
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/rs/zerolog"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/module"
+	"github.com/onflow/flow-go/module/component"
+	"github.com/onflow/flow-go/network/channels"
+	"github.com/onflow/flow-go/network/p2p"
+	"github.com/onflow/flow-go/utils/logging"
+)
+
+const (
+	// rpcInspectorComponentName the rpc inspector component name.
+	rpcInspectorComponentName = "gossipsub_rpc_validation_inspector"
+
+	// reasons of rejecting an RPC, used as metrics labels.
+	reasonDiscardThreshold  = "discard_threshold"
+	reasonInvalidTopic      = "invalid_topic"
+	reasonUnsubscribedTopic = "unsubscribed_topic"
+	reasonIWantFlood        = "iwant_flood"
+
+	// unsubscribedTopicTrackerSize is the maximum number of peers whose GRAFT and IHAVE messages for unsubscribed topics
+	// are counted against the tolerance. It is well above the number of peers a node is connected to.
+	unsubscribedTopicTrackerSize = 10_000
+)
+
+// ControlMsgValidationInspector is an RPC inspector that validates the control messages of the incoming GossipSub RPCs.
+// An RPC with an invalid control message is dropped, and the sender is reported to the consumers of the notification
+// distributor, e.g., the peer scoring, which penalizes the misbehaving peer. The control messages are validated
+// against the following rules:
+//   - the number of control messages of each type must not exceed the configured discard threshold.
+//   - GRAFT, PRUNE and IHAVE messages must refer to valid Flow topics of the current spork.
+//   - the IWANT messages must not request more message IDs than configured.
+//
+// An honest peer may send a GRAFT or IHAVE for a topic the local node has just unsubscribed from, until it learns about
+// the unsubscription. Therefore, once a topic oracle is set, GRAFT and IHAVE messages for topics the local node is not
+// subscribed to are only removed from the RPC, while the rest of the RPC is processed. Their sender is reported once it
+// exceeds the configured tolerance of such messages within the tolerance window.
+//
+// Validation is done synchronously on the receive path of the RPC. Its cost is bounded by the configured discard
+// thresholds, and large IHAVE lists are validated only on a random sample.
+type ControlMsgValidationInspector struct {
+	component.Component
+	logger      zerolog.Logger
+	sporkID     flow.Identifier
+	config      *ControlMsgValidationInspectorConfig
+	distributor p2p.GossipSubInspectorNotificationDistributor
+	metrics     module.GossipSubRpcValidationInspectorMetrics
+
+	topicOracleLock sync.RWMutex
+	topicOracle     func(topic string) bool // returns whether the local node is subscribed to a topic, nil until set
+
+	// unsubscribedTopics counts the GRAFT and IHAVE messages for unsubscribed topics of each peer within the
+	// current tolerance window, keyed by peer ID.
+	unsubscribedTopicsLock sync.Mutex
+	unsubscribedTopics     *lru.Cache
+}
+
+// toleranceWindow is the number of GRAFT and IHAVE messages for unsubscribed topics received from a peer since the
+// start of the window.
+type toleranceWindow struct {
+	start time.Time
+	count uint64
+}
+
+var _ p2p.GossipSubRPCInspector = (*ControlMsgValidationInspector)(nil)
+
+// NewControlMsgValidationInspector returns a new ControlMsgValidationInspector.
+// All errors returned from this function indicate an invalid configuration.
+func NewControlMsgValidationInspector(
+	logger zerolog.Logger,
+	sporkID flow.Identifier,
+	config *ControlMsgValidationInspectorConfig,
+	distributor p2p.GossipSubInspectorNotificationDistributor,
+	metrics module.GossipSubRpcValidationInspectorMetrics,
+) (*ControlMsgValidationInspector, error) {
+	err := config.Validate()
+	if err != nil {
+		return nil, fmt.Errorf("invalid control message validation inspector config: %w", err)
+	}
+
+	unsubscribedTopics, err := lru.New(unsubscribedTopicTrackerSize)
+	if err != nil {
+		return nil, fmt.Errorf("could not create unsubscribed topic tracker: %w", err)
+	}
+
+	c := &ControlMsgValidationInspector{
+		logger:             logger.With().Str("component", rpcInspectorComponentName).Logger(),
+		sporkID:            sporkID,
+		config:             config,
+		distributor:        distributor,
+		metrics:            metrics,
+		unsubscribedTopics: unsubscribedTopics,
+	}
+	c.Component = component.NewComponentManagerBuilder().
+		AddWorker(component.NoopWorker).
+		Build()
+
+	return c, nil
+}
+
+// Name returns the name of the rpc inspector.
+func (c *ControlMsgValidationInspector) Name() string {
+	return rpcInspectorComponentName
+}
+
+// SetTopicOracle sets the topic oracle of the inspector, i.e., a function that returns whether the local node is
+// subscribed to a topic. Until the oracle is set, control messages are not checked against the subscriptions of the node.
+// The topic oracle can be set only once; any attempt to set it again results in an error.
+func (c *ControlMsgValidationInspector) SetTopicOracle(topicOracle func(topic string) bool) error {
+	c.topicOracleLock.Lock()
+	defer c.topicOracleLock.Unlock()
+
+	if c.topicOracle != nil {
+		return fmt.Errorf("topic oracle has already been set")
+	}
+	c.topicOracle = topicOracle
+	return nil
+}
+
+// Inspect validates the control messages of the incoming RPC. It returns an error, and hence the RPC is dropped,
+// if any of the control messages is invalid. In that case, the sender is also reported to the notification distributor.
+// GRAFT and IHAVE messages for topics the node is not subscribed to are removed from the RPC without rejecting it.
+func (c *ControlMsgValidationInspector) Inspect(from peer.ID, rpc *pubsub.RPC) error {
+	control := rpc.GetControl()
+	if control == nil {
+		return nil
+	}
+
+	for _, ctrlMsgType := range p2p.ControlMessageTypes() {
+		count, err := c.validateCtrlMsg(ctrlMsgType, control)
+		if err != nil {
+			c.reportInvalidCtrlMsg(from, ctrlMsgType, count, err)
+			return fmt.Errorf("rpc with invalid %s control message from peer %s: %w", ctrlMsgType, from, err)
+		}
+	}
+
+	c.dropUnsubscribedTopics(from, control)
+
+	return nil
+}
+
+// dropUnsubscribedTopics removes the GRAFT and IHAVE messages for topics the node is not subscribed to from the
+// control message, and counts them against the tolerance of the sender. Once the sender exceeds the tolerance within
+// the tolerance window, it is reported for each control message type with dropped messages.
+// It is a no-op until the topic oracle is set.
+func (c *ControlMsgValidationInspector) dropUnsubscribedTopics(from peer.ID, control *pubsub_pb.ControlMessage) {
+	isSubscribed := c.getTopicOracle()
+	if isSubscribed == nil {
+		return
+	}
+
+	var droppedGrafts, droppedIHaves []channels.Topic
+	grafts := control.Graft[:0]
+	for _, graft := range control.GetGraft() {
+		if !isSubscribed(graft.GetTopicID()) {
+			droppedGrafts = append(droppedGrafts, channels.Topic(graft.GetTopicID()))
+			continue
+		}
+		grafts = append(grafts, graft)
+	}
+	control.Graft = grafts
+
+	iHaves := control.Ihave[:0]
+	for _, iHave := range control.GetIhave() {
+		if !isSubscribed(iHave.GetTopicID()) {
+			droppedIHaves = append(droppedIHaves, channels.Topic(iHave.GetTopicID()))
+			continue
+		}
+		iHaves = append(iHaves, iHave)
+	}
+	control.Ihave = iHaves
+
+	dropped := uint64(len(droppedGrafts) + len(droppedIHaves))
+	if dropped == 0 || !c.exceedsUnsubscribedTopicTolerance(from, dropped) {
+		return
+	}
+	if len(droppedGrafts) > 0 {
+		c.reportInvalidCtrlMsg(from, p2p.CtrlMsgGraft, uint64(len(droppedGrafts)), NewUnsubscribedTopicErr(p2p.CtrlMsgGraft, droppedGrafts[0]))
+	}
+	if len(droppedIHaves) > 0 {
+		c.reportInvalidCtrlMsg(from, p2p.CtrlMsgIHave, uint64(len(droppedIHaves)), NewUnsubscribedTopicErr(p2p.CtrlMsgIHave, droppedIHaves[0]))
+	}
+}
+
+// exceedsUnsubscribedTopicTolerance counts the given number of GRAFT and IHAVE messages for unsubscribed topics
+// against the tolerance of the peer, and returns true if the peer exceeds the tolerance within the current window.
+func (c *ControlMsgValidationInspector) exceedsUnsubscribedTopicTolerance(from peer.ID, dropped uint64) bool {
+	c.unsubscribedTopicsLock.Lock()
+	defer c.unsubscribedTopicsLock.Unlock()
+
+	now := time.Now()
+	window := &toleranceWindow{start: now}
+	if cached, ok := c.unsubscribedTopics.Get(from); ok {
+		window = cached.(*toleranceWindow)
+		if now.Sub(window.start) > c.config.UnsubscribedTopicToleranceWindow {
+			window = &toleranceWindow{start: now}
+		}
+	}
+	window.count += dropped
+	c.unsubscribedTopics.Add(from, window)
+	return window.count > c.config.UnsubscribedTopicTolerance
+}
+
+// validateCtrlMsg validates the control messages of the given type, and returns the number of such messages.
+// Expected errors during normal operations:
+//   - ErrDiscardThreshold if the number of control messages exceeds the discard threshold.
+//   - ErrInvalidTopic if a control message refers to a topic that is not a valid Flow topic.
+//   - ErrIWantFlood if the IWANT messages request more message IDs than allowed.
+func (c *ControlMsgValidationInspector) validateCtrlMsg(ctrlMsgType p2p.ControlMessageType, control *pubsub_pb.ControlMessage) (uint64, error) {
+	count := ctrlMsgCount(ctrlMsgType, control)
+	if count == 0 {
+		return 0, nil
+	}
+	if discardThreshold := c.config.discardThreshold(ctrlMsgType); count > discardThreshold {
+		return count, NewDiscardThresholdErr(ctrlMsgType, count, discardThreshold)
+	}
+
+	switch ctrlMsgType {
+	case p2p.CtrlMsgGraft:
+		for _, graft := range control.GetGraft() {
+			err := c.validateTopic(ctrlMsgType, channels.Topic(graft.GetTopicID()))
+			if err != nil {
+				return count, err
+			}
+		}
+	case p2p.CtrlMsgPrune:
+		for _, prune := range control.GetPrune() {
+			err := c.validateTopic(ctrlMsgType, channels.Topic(prune.GetTopicID()))
+			if err != nil {
+				return count, err
+			}
+		}
+	case p2p.CtrlMsgIHave:
+		iHaves := control.GetIhave()
+		indices := c.iHaveSampleIndices(len(iHaves))
+		for _, i := range indices {
+			err := c.validateTopic(ctrlMsgType, channels.Topic(iHaves[i].GetTopicID()))
+			if err != nil {
+				return count, err
+			}
+		}
+	case p2p.CtrlMsgIWant:
+		messageIDs := uint64(0)
+		for _, iWant := range control.GetIwant() {
+			messageIDs += uint64(len(iWant.GetMessageIDs()))
+		}
+		if messageIDs > c.config.IWantMaxMessageIDs {
+			return count, NewIWantFloodErr(messageIDs, c.config.IWantMaxMessageIDs)
+		}
+	}
+
+	return count, nil
+}
+
+// validateTopic ensures the topic is a valid Flow topic of the current spork.
+// Expected errors during normal operations:
+//   - ErrInvalidTopic if the topic is not a valid Flow topic.
+func (c *ControlMsgValidationInspector) validateTopic(ctrlMsgType p2p.ControlMessageType, topic channels.Topic) error {
+	err := channels.IsValidFlowTopic(topic, c.sporkID)
+	if err != nil {
+		return NewInvalidTopicErr(ctrlMsgType, topic, err)
+	}
+	return nil
+}
+
+// iHaveSampleIndices returns the indices of the IHAVE messages to validate. If the number of IHAVE messages exceeds the
+// safety threshold, a random sample of the configured size is returned, otherwise all the indices are returned.
+func (c *ControlMsgValidationInspector) iHaveSampleIndices(count int) []int {
+	if uint64(count) <= c.config.IHaveSafetyThreshold {
+		indices := make([]int, count)
+		for i := range indices {
+			indices[i] = i
+		}
+		return indices
+	}
+
+	c.metrics.OnIHaveMessagesSampled()
+	// the sample size is at most the safety threshold as per config validation, we cap it anyway to never slice beyond count.
+	sampleSize := count
+	if c.config.IHaveSampleSize < uint64(count) {
+		sampleSize = int(c.config.IHaveSampleSize)
+	}
+	return rand.Perm(count)[:sampleSize]
+}
+
+// getTopicOracle returns the topic oracle of the inspector, or nil if no topic oracle has been set.
+func (c *ControlMsgValidationInspector) getTopicOracle() func(topic string) bool {
+	c.topicOracleLock.RLock()
+	defer c.topicOracleLock.RUnlock()
+
+	return c.topicOracle
+}
+
+// reportInvalidCtrlMsg logs the invalid control message, updates the metrics and distributes a notification about the
+// misbehaving peer to the consumers of the notification distributor.
+func (c *ControlMsgValidationInspector) reportInvalidCtrlMsg(from peer.ID, ctrlMsgType p2p.ControlMessageType, count uint64, err error) {
+	c.logger.Warn().
+		Err(err).
+		Str("peer_id", from.String()).
+		Str("ctrl_msg_type", string(ctrlMsgType)).
+		Uint64("ctrl_msg_count", count).
+		Bool(logging.KeySuspicious, true).
+		Msg("invalid control message")
+
+	c.metrics.OnInvalidControlMessage(string(ctrlMsgType), invalidCtrlMsgReason(err))
+
+	err = c.distributor.DistributeInvalidControlMessageNotification(p2p.NewInvalidControlMessageNotification(from, ctrlMsgType, count, err))
+	if err != nil {
+		// distribution errors are irrecoverable as per the contract of the distributor.
+		c.logger.Fatal().Err(err).Msg("failed to distribute invalid control message notification")
+	}
+}
+
+// ctrlMsgCount returns the number of control messages of the given type.
+func ctrlMsgCount(ctrlMsgType p2p.ControlMessageType, control *pubsub_pb.ControlMessage) uint64 {
+	switch ctrlMsgType {
+	case p2p.CtrlMsgGraft:
+		return uint64(len(control.GetGraft()))
+	case p2p.CtrlMsgPrune:
+		return uint64(len(control.GetPrune()))
+	case p2p.CtrlMsgIHave:
+		return uint64(len(control.GetIhave()))
+	case p2p.CtrlMsgIWant:
+		return uint64(len(control.GetIwant()))
+	default:
+		return 0
+	}
+}
+
+// invalidCtrlMsgReason returns the reason of rejecting an RPC due to the given error, for use as a metrics label.
+func invalidCtrlMsgReason(err error) string {
+	switch {
+	case IsErrDiscardThreshold(err):
+		return reasonDiscardThreshold
+	case IsErrInvalidTopic(err):
+		return reasonInvalidTopic
+	case IsErrUnsubscribedTopic(err):
+		return reasonUnsubscribedTopic
+	case IsErrIWantFlood(err):
+		return reasonIWantFlood
+	default:
+		return "unknown"
+	}
+}
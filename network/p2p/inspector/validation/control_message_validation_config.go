@@ -0,0 +1,120 @@
+package validation
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/onflow/flow-go/network/p2p"
+)
+
+const (
+	// DefaultGraftDiscardThreshold is the default maximum number of GRAFT messages in a single RPC.
+	// A node sends at most one GRAFT per topic to a peer in each heartbeat, hence the threshold is set well above
+	// the number of topics a node subscribes to.
+	DefaultGraftDiscardThreshold = 30
+	// DefaultPruneDiscardThreshold is the default maximum number of PRUNE messages in a single RPC.
+	// A node sends at most one PRUNE per topic to a peer in each heartbeat, hence the threshold is set well above
+	// the number of topics a node subscribes to.
+	DefaultPruneDiscardThreshold = 30
+	// DefaultIHaveDiscardThreshold is the default maximum number of IHAVE messages in a single RPC.
+	DefaultIHaveDiscardThreshold = 1000
+	// DefaultIHaveSafetyThreshold is the default number of IHAVE messages in a single RPC above which only a random
+	// sample of the IHAVE messages is validated.
+	DefaultIHaveSafetyThreshold = 100
+	// DefaultIHaveSampleSize is the default number of IHAVE messages that are validated when the number of IHAVE
+	// messages in a single RPC exceeds the safety threshold.
+	DefaultIHaveSampleSize = 50
+	// DefaultIWantDiscardThreshold is the default maximum number of IWANT messages in a single RPC.
+	DefaultIWantDiscardThreshold = 100
+	// DefaultIWantMaxMessageIDs is the default maximum total number of message IDs requested by the IWANT messages of a
+	// single RPC. It matches the maximum number of message IDs that GossipSub advertises in the IHAVE messages of a
+	// single heartbeat (GossipSubMaxIHaveLength).
+	DefaultIWantMaxMessageIDs = 5000
+	// DefaultUnsubscribedTopicTolerance is the default number of GRAFT and IHAVE messages for topics the node is not
+	// subscribed to, which are dropped without reporting their sender within a tolerance window. Honest peers send such
+	// messages for a short while after the node unsubscribed from a topic, until they learn about the unsubscription.
+	DefaultUnsubscribedTopicTolerance = 10
+	// DefaultUnsubscribedTopicToleranceWindow is the default length of the window over which the GRAFT and IHAVE
+	// messages for unsubscribed topics of a peer are counted against the tolerance.
+	DefaultUnsubscribedTopicToleranceWindow = time.Minute
+)
+
+// ControlMsgValidationInspectorConfig is the configuration of the validation inspector of the incoming GossipSub RPC
+// control messages. Any RPC exceeding one of the discard thresholds is dropped, and the sender is reported as misbehaving.
+type ControlMsgValidationInspectorConfig struct {
+	// GraftDiscardThreshold is the maximum number of GRAFT messages in a single RPC.
+	GraftDiscardThreshold uint64
+	// PruneDiscardThreshold is the maximum number of PRUNE messages in a single RPC.
+	PruneDiscardThreshold uint64
+	// IHaveDiscardThreshold is the maximum number of IHAVE messages in a single RPC.
+	IHaveDiscardThreshold uint64
+	// IHaveSafetyThreshold is the number of IHAVE messages in a single RPC above which only a random sample of
+	// IHaveSampleSize IHAVE messages is validated, bounding the validation cost of large IHAVE lists.
+	IHaveSafetyThreshold uint64
+	// IHaveSampleSize is the number of IHAVE messages validated when the number of IHAVE messages in a single RPC
+	// exceeds the IHaveSafetyThreshold.
+	IHaveSampleSize uint64
+	// IWantDiscardThreshold is the maximum number of IWANT messages in a single RPC.
+	IWantDiscardThreshold uint64
+	// IWantMaxMessageIDs is the maximum total number of message IDs requested by the IWANT messages of a single RPC.
+	IWantMaxMessageIDs uint64
+	// UnsubscribedTopicTolerance is the number of GRAFT and IHAVE messages for topics the node is not subscribed to,
+	// which are dropped from the RPCs of a peer within UnsubscribedTopicToleranceWindow before the peer is reported.
+	UnsubscribedTopicTolerance uint64
+	// UnsubscribedTopicToleranceWindow is the length of the window over which the GRAFT and IHAVE messages for
+	// unsubscribed topics of a peer are counted against the UnsubscribedTopicTolerance.
+	UnsubscribedTopicToleranceWindow time.Duration
+}
+
+// DefaultControlMsgValidationInspectorConfig returns the default configuration of the control message validation inspector.
+func DefaultControlMsgValidationInspectorConfig() *ControlMsgValidationInspectorConfig {
+	return &ControlMsgValidationInspectorConfig{
+		GraftDiscardThreshold: DefaultGraftDiscardThreshold,
+		PruneDiscardThreshold: DefaultPruneDiscardThreshold,
+		IHaveDiscardThreshold: DefaultIHaveDiscardThreshold,
+		IHaveSafetyThreshold:  DefaultIHaveSafetyThreshold,
+		IHaveSampleSize:       DefaultIHaveSampleSize,
+		IWantDiscardThreshold: DefaultIWantDiscardThreshold,
+		IWantMaxMessageIDs:    DefaultIWantMaxMessageIDs,
+
+		UnsubscribedTopicTolerance:       DefaultUnsubscribedTopicTolerance,
+		UnsubscribedTopicToleranceWindow: DefaultUnsubscribedTopicToleranceWindow,
+	}
+}
+
+// Validate checks the consistency of the configuration.
+// All errors returned from this function indicate an invalid configuration.
+func (c *ControlMsgValidationInspectorConfig) Validate() error {
+	if c.GraftDiscardThreshold == 0 || c.PruneDiscardThreshold == 0 || c.IHaveDiscardThreshold == 0 || c.IWantDiscardThreshold == 0 {
+		return fmt.Errorf("discard thresholds must be positive")
+	}
+	if c.IHaveSafetyThreshold == 0 || c.IHaveSafetyThreshold > c.IHaveDiscardThreshold {
+		return fmt.Errorf("ihave safety threshold (%d) must be positive and at most the ihave discard threshold (%d)",
+			c.IHaveSafetyThreshold, c.IHaveDiscardThreshold)
+	}
+	if c.IHaveSampleSize == 0 || c.IHaveSampleSize > c.IHaveSafetyThreshold {
+		return fmt.Errorf("ihave sample size (%d) must be positive and at most the ihave safety threshold (%d)",
+			c.IHaveSampleSize, c.IHaveSafetyThreshold)
+	}
+	if c.IWantMaxMessageIDs == 0 {
+		return fmt.Errorf("iwant max message ids must be positive")
+	}
+	if c.UnsubscribedTopicToleranceWindow <= 0 {
+		return fmt.Errorf("unsubscribed topic tolerance window must be positive")
+	}
+	return nil
+}
+
+// discardThreshold returns the discard threshold of the given control message type.
+func (c *ControlMsgValidationInspectorConfig) discardThreshold(msgType p2p.ControlMessageType) uint64 {
+	switch msgType {
+	case p2p.CtrlMsgGraft:
+		return c.GraftDiscardThreshold
+	case p2p.CtrlMsgPrune:
+		return c.PruneDiscardThreshold
+	case p2p.CtrlMsgIHave:
+		return c.IHaveDiscardThreshold
+	default:
+		return c.IWantDiscardThreshold
+	}
+}
@@ -0,0 +1,302 @@
+package validation_test
+
+import (
+	"testing"
+
+	pubsub "github.com/libp2p/go-libp2p-pubsub"
+	pb "github.com/libp2p/go-libp2p-pubsub/pb"
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/model/flow"
+	mockmodule "github.com/onflow/flow-go/module/mock"
+	"github.com/onflow/flow-go/network/channels"
+	"github.com/onflow/flow-go/network/p2p"
+	"github.com/onflow/flow-go/network/p2p/inspector/validation"
+	mockp2p "github.com/onflow/flow-go/network/p2p/mock"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+// TestNewControlMsgValidationInspector_InvalidConfig checks that the inspector cannot be created with an invalid configuration.
+func TestNewControlMsgValidationInspector_InvalidConfig(t *testing.T) {
+	testCases := map[string]func(*validation.ControlMsgValidationInspectorConfig){
+		"zero discard threshold": func(cfg *validation.ControlMsgValidationInspectorConfig) {
+			cfg.GraftDiscardThreshold = 0
+		},
+		"safety threshold above discard threshold": func(cfg *validation.ControlMsgValidationInspectorConfig) {
+			cfg.IHaveSafetyThreshold = cfg.IHaveDiscardThreshold + 1
+		},
+		"sample size above safety threshold": func(cfg *validation.ControlMsgValidationInspectorConfig) {
+			cfg.IHaveSampleSize = cfg.IHaveSafetyThreshold + 1
+		},
+		"zero sample size": func(cfg *validation.ControlMsgValidationInspectorConfig) {
+			cfg.IHaveSampleSize = 0
+		},
+		"zero iwant max message ids": func(cfg *validation.ControlMsgValidationInspectorConfig) {
+			cfg.IWantMaxMessageIDs = 0
+		},
+		"zero unsubscribed topic tolerance window": func(cfg *validation.ControlMsgValidationInspectorConfig) {
+			cfg.UnsubscribedTopicToleranceWindow = 0
+		},
+	}
+
+	for name, invalidate := range testCases {
+		t.Run(name, func(t *testing.T) {
+			cfg := validation.DefaultControlMsgValidationInspectorConfig()
+			invalidate(cfg)
+
+			_, err := validation.NewControlMsgValidationInspector(
+				unittest.Logger(),
+				unittest.IdentifierFixture(),
+				cfg,
+				mockp2p.NewGossipSubInspectorNotificationDistributor(t),
+				mockmodule.NewGossipSubRpcValidationInspectorMetrics(t))
+			require.Error(t, err)
+		})
+	}
+}
+
+// TestInspect_ValidControlMessages checks that control messages for subscribed, valid Flow topics are accepted
+// without any notification being distributed.
+func TestInspect_ValidControlMessages(t *testing.T) {
+	sporkID := unittest.IdentifierFixture()
+	topic := channels.TopicFromChannel(channels.PushBlocks, sporkID).String()
+	inspector, _, _ := inspectorFixture(t, sporkID, validation.DefaultControlMsgValidationInspectorConfig())
+	require.NoError(t, inspector.SetTopicOracle(func(t string) bool { return t == topic }))
+
+	rpc := rpcFixture(&pb.ControlMessage{
+		Graft: graftsFixture(5, topic),
+		Prune: prunesFixture(5, topic),
+		Ihave: iHavesFixture(5, topic),
+		Iwant: iWantsFixture(5, 10),
+	})
+	require.NoError(t, inspector.Inspect(peer.ID("peer"), rpc))
+
+	// rpc without control messages is accepted as well.
+	require.NoError(t, inspector.Inspect(peer.ID("peer"), rpcFixture(nil)))
+}
+
+// TestInspect_DiscardThreshold checks that an RPC exceeding the discard threshold of any control message type is
+// rejected and the sender is reported.
+func TestInspect_DiscardThreshold(t *testing.T) {
+	sporkID := unittest.IdentifierFixture()
+	topic := channels.TopicFromChannel(channels.PushBlocks, sporkID).String()
+	cfg := validation.DefaultControlMsgValidationInspectorConfig()
+
+	testCases := map[p2p.ControlMessageType]*pb.ControlMessage{
+		p2p.CtrlMsgGraft: {Graft: graftsFixture(int(cfg.GraftDiscardThreshold)+1, topic)},
+		p2p.CtrlMsgPrune: {Prune: prunesFixture(int(cfg.PruneDiscardThreshold)+1, topic)},
+		p2p.CtrlMsgIHave: {Ihave: iHavesFixture(int(cfg.IHaveDiscardThreshold)+1, topic)},
+		p2p.CtrlMsgIWant: {Iwant: iWantsFixture(int(cfg.IWantDiscardThreshold)+1, 1)},
+	}
+
+	for msgType, control := range testCases {
+		t.Run(string(msgType), func(t *testing.T) {
+			inspector, distributor, metrics := inspectorFixture(t, sporkID, cfg)
+			from := peer.ID("spammer")
+			expectedCount := uint64(0)
+			switch msgType {
+			case p2p.CtrlMsgGraft:
+				expectedCount = cfg.GraftDiscardThreshold + 1
+			case p2p.CtrlMsgPrune:
+				expectedCount = cfg.PruneDiscardThreshold + 1
+			case p2p.CtrlMsgIHave:
+				expectedCount = cfg.IHaveDiscardThreshold + 1
+			case p2p.CtrlMsgIWant:
+				expectedCount = cfg.IWantDiscardThreshold + 1
+			}
+
+			metrics.On("OnInvalidControlMessage", string(msgType), "discard_threshold").Return().Once()
+			distributor.On("DistributeInvalidControlMessageNotification", mock.Anything).Run(func(args mock.Arguments) {
+				notification := args.Get(0).(*p2p.InvalidControlMessageNotification)
+				require.Equal(t, from, notification.PeerID)
+				require.Equal(t, msgType, notification.MsgType)
+				require.Equal(t, expectedCount, notification.Count)
+				require.True(t, validation.IsErrDiscardThreshold(notification.Err))
+			}).Return(nil).Once()
+
+			err := inspector.Inspect(from, rpcFixture(control))
+			require.True(t, validation.IsErrDiscardThreshold(err))
+		})
+	}
+}
+
+// TestInspect_InvalidTopic checks that GRAFT, PRUNE and IHAVE messages referring to topics that are not valid Flow
+// topics of the current spork are rejected and the sender is reported.
+func TestInspect_InvalidTopic(t *testing.T) {
+	sporkID := unittest.IdentifierFixture()
+	// valid channel, but of another spork.
+	wrongSporkTopic := channels.TopicFromChannel(channels.PushBlocks, unittest.IdentifierFixture()).String()
+	// unknown channel.
+	unknownTopic := "invalid-topic/" + sporkID.String()
+
+	for _, topic := range []string{wrongSporkTopic, unknownTopic} {
+		testCases := map[p2p.ControlMessageType]*pb.ControlMessage{
+			p2p.CtrlMsgGraft: {Graft: graftsFixture(1, topic)},
+			p2p.CtrlMsgPrune: {Prune: prunesFixture(1, topic)},
+			p2p.CtrlMsgIHave: {Ihave: iHavesFixture(1, topic)},
+		}
+		for msgType, control := range testCases {
+			t.Run(string(msgType), func(t *testing.T) {
+				inspector, distributor, metrics := inspectorFixture(t, sporkID, validation.DefaultControlMsgValidationInspectorConfig())
+				metrics.On("OnInvalidControlMessage", string(msgType), "invalid_topic").Return().Once()
+				distributor.On("DistributeInvalidControlMessageNotification", mock.Anything).Run(func(args mock.Arguments) {
+					notification := args.Get(0).(*p2p.InvalidControlMessageNotification)
+					require.Equal(t, msgType, notification.MsgType)
+					require.True(t, validation.IsErrInvalidTopic(notification.Err))
+				}).Return(nil).Once()
+
+				err := inspector.Inspect(peer.ID("spammer"), rpcFixture(control))
+				require.True(t, validation.IsErrInvalidTopic(err))
+			})
+		}
+	}
+}
+
+// TestInspect_UnsubscribedTopic checks that GRAFT and IHAVE messages for topics the node is not subscribed to are
+// removed from the RPC once the topic oracle is set, without rejecting the RPC, while PRUNE messages for such topics are
+// kept. The sender is only reported once it exceeds the tolerance.
+func TestInspect_UnsubscribedTopic(t *testing.T) {
+	sporkID := unittest.IdentifierFixture()
+	subscribedTopic := channels.TopicFromChannel(channels.PushBlocks, sporkID).String()
+	unsubscribedTopic := channels.TopicFromChannel(channels.SyncCommittee, sporkID).String()
+
+	cfg := validation.DefaultControlMsgValidationInspectorConfig()
+	cfg.UnsubscribedTopicTolerance = 3
+	inspector, distributor, metrics := inspectorFixture(t, sporkID, cfg)
+
+	// without a topic oracle, subscriptions are not checked.
+	rpc := rpcFixture(&pb.ControlMessage{Graft: graftsFixture(1, unsubscribedTopic)})
+	require.NoError(t, inspector.Inspect(peer.ID("peer"), rpc))
+	require.Len(t, rpc.GetControl().GetGraft(), 1)
+
+	require.NoError(t, inspector.SetTopicOracle(func(t string) bool { return t == subscribedTopic }))
+	// topic oracle can only be set once.
+	require.Error(t, inspector.SetTopicOracle(func(string) bool { return false }))
+
+	rpc = rpcFixture(&pb.ControlMessage{Prune: prunesFixture(1, unsubscribedTopic)})
+	require.NoError(t, inspector.Inspect(peer.ID("peer"), rpc))
+	require.Len(t, rpc.GetControl().GetPrune(), 1)
+
+	// within the tolerance, only the messages for unsubscribed topics are dropped, and the published messages and
+	// the messages for subscribed topics are kept.
+	rpc = rpcFixture(&pb.ControlMessage{
+		Graft: append(graftsFixture(1, unsubscribedTopic), graftsFixture(1, subscribedTopic)...),
+		Ihave: append(iHavesFixture(2, unsubscribedTopic), iHavesFixture(1, subscribedTopic)...),
+	})
+	rpc.Publish = []*pb.Message{{Topic: &subscribedTopic, Data: []byte{1}}}
+	require.NoError(t, inspector.Inspect(peer.ID("peer"), rpc))
+	require.Len(t, rpc.GetPublish(), 1)
+	require.Len(t, rpc.GetControl().GetGraft(), 1)
+	require.Equal(t, subscribedTopic, rpc.GetControl().GetGraft()[0].GetTopicID())
+	require.Len(t, rpc.GetControl().GetIhave(), 1)
+	require.Equal(t, subscribedTopic, rpc.GetControl().GetIhave()[0].GetTopicID())
+
+	// beyond the tolerance, the sender is reported, but its RPC is still accepted.
+	metrics.On("OnInvalidControlMessage", string(p2p.CtrlMsgGraft), "unsubscribed_topic").Return().Once()
+	metrics.On("OnInvalidControlMessage", string(p2p.CtrlMsgIHave), "unsubscribed_topic").Return().Once()
+	distributor.On("DistributeInvalidControlMessageNotification", mock.Anything).Run(func(args mock.Arguments) {
+		notification := args.Get(0).(*p2p.InvalidControlMessageNotification)
+		require.Equal(t, peer.ID("peer"), notification.PeerID)
+		require.Equal(t, uint64(1), notification.Count)
+		require.True(t, validation.IsErrUnsubscribedTopic(notification.Err))
+	}).Return(nil).Twice()
+
+	rpc = rpcFixture(&pb.ControlMessage{Graft: graftsFixture(1, unsubscribedTopic), Ihave: iHavesFixture(1, unsubscribedTopic)})
+	require.NoError(t, inspector.Inspect(peer.ID("peer"), rpc))
+	require.Empty(t, rpc.GetControl().GetGraft())
+	require.Empty(t, rpc.GetControl().GetIhave())
+
+	// the tolerance is tracked per peer.
+	rpc = rpcFixture(&pb.ControlMessage{Graft: graftsFixture(1, unsubscribedTopic)})
+	require.NoError(t, inspector.Inspect(peer.ID("other"), rpc))
+	require.Empty(t, rpc.GetControl().GetGraft())
+}
+
+// TestInspect_IHaveSampling checks that IHAVE messages above the safety threshold are only validated on a sample.
+func TestInspect_IHaveSampling(t *testing.T) {
+	sporkID := unittest.IdentifierFixture()
+	topic := channels.TopicFromChannel(channels.PushBlocks, sporkID).String()
+	cfg := validation.DefaultControlMsgValidationInspectorConfig()
+
+	inspector, _, metrics := inspectorFixture(t, sporkID, cfg)
+	metrics.On("OnIHaveMessagesSampled").Return().Once()
+
+	rpc := rpcFixture(&pb.ControlMessage{Ihave: iHavesFixture(int(cfg.IHaveSafetyThreshold)+1, topic)})
+	require.NoError(t, inspector.Inspect(peer.ID("peer"), rpc))
+}
+
+// TestInspect_IWantFlood checks that IWANT messages requesting more message IDs than allowed are rejected.
+func TestInspect_IWantFlood(t *testing.T) {
+	cfg := validation.DefaultControlMsgValidationInspectorConfig()
+	cfg.IWantMaxMessageIDs = 100
+	inspector, distributor, metrics := inspectorFixture(t, unittest.IdentifierFixture(), cfg)
+
+	metrics.On("OnInvalidControlMessage", string(p2p.CtrlMsgIWant), "iwant_flood").Return().Once()
+	distributor.On("DistributeInvalidControlMessageNotification", mock.Anything).Run(func(args mock.Arguments) {
+		notification := args.Get(0).(*p2p.InvalidControlMessageNotification)
+		require.Equal(t, p2p.CtrlMsgIWant, notification.MsgType)
+		require.Equal(t, uint64(11), notification.Count)
+		require.True(t, validation.IsErrIWantFlood(notification.Err))
+	}).Return(nil).Once()
+
+	err := inspector.Inspect(peer.ID("spammer"), rpcFixture(&pb.ControlMessage{Iwant: iWantsFixture(11, 10)}))
+	require.True(t, validation.IsErrIWantFlood(err))
+}
+
+// inspectorFixture returns a new control message validation inspector with mocked distributor and metrics.
+func inspectorFixture(
+	t *testing.T,
+	sporkID flow.Identifier,
+	cfg *validation.ControlMsgValidationInspectorConfig,
+) (*validation.ControlMsgValidationInspector, *mockp2p.GossipSubInspectorNotificationDistributor, *mockmodule.GossipSubRpcValidationInspectorMetrics) {
+	distributor := mockp2p.NewGossipSubInspectorNotificationDistributor(t)
+	metrics := mockmodule.NewGossipSubRpcValidationInspectorMetrics(t)
+	inspector, err := validation.NewControlMsgValidationInspector(unittest.Logger(), sporkID, cfg, distributor, metrics)
+	require.NoError(t, err)
+	return inspector, distributor, metrics
+}
+
+func rpcFixture(control *pb.ControlMessage) *pubsub.RPC {
+	return &pubsub.RPC{RPC: pb.RPC{Control: control}}
+}
+
+func graftsFixture(count int, topic string) []*pb.ControlGraft {
+	grafts := make([]*pb.ControlGraft, count)
+	for i := range grafts {
+		topic := topic
+		grafts[i] = &pb.ControlGraft{TopicID: &topic}
+	}
+	return grafts
+}
+
+func prunesFixture(count int, topic string) []*pb.ControlPrune {
+	prunes := make([]*pb.ControlPrune, count)
+	for i := range prunes {
+		topic := topic
+		prunes[i] = &pb.ControlPrune{TopicID: &topic}
+	}
+	return prunes
+}
+
+func iHavesFixture(count int, topic string) []*pb.ControlIHave {
+	iHaves := make([]*pb.ControlIHave, count)
+	for i := range iHaves {
+		topic := topic
+		iHaves[i] = &pb.ControlIHave{TopicID: &topic, MessageIDs: []string{unittest.GenerateRandomStringWithLen(10)}}
+	}
+	return iHaves
+}
+
+func iWantsFixture(count int, msgIDs int) []*pb.ControlIWant {
+	iWants := make([]*pb.ControlIWant, count)
+	for i := range iWants {
+		ids := make([]string, msgIDs)
+		for j := range ids {
+			ids[j] = unittest.GenerateRandomStringWithLen(10)
+		}
+		iWants[i] = &pb.ControlIWant{MessageIDs: ids}
+	}
+	return iWants
+}
@@ -0,0 +1,95 @@
+package validation
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/onflow/flow-go/network/channels"
+	"github.com/onflow/flow-go/network/p2p"
+)
+
+// ErrDiscardThreshold indicates that the number of control messages of a single RPC exceeds the discard threshold.
+type ErrDiscardThreshold struct {
+	controlMsg       p2p.ControlMessageType // the type of the control message
+	amount           uint64                 // the number of control messages of the RPC
+	discardThreshold uint64                 // the configured discard threshold
+}
+
+func (e ErrDiscardThreshold) Error() string {
+	return fmt.Sprintf("number of %s messages received exceeds the configured discard threshold: received %d discard threshold %d", e.controlMsg, e.amount, e.discardThreshold)
+}
+
+// NewDiscardThresholdErr returns a new ErrDiscardThreshold.
+func NewDiscardThresholdErr(controlMsg p2p.ControlMessageType, amount, discardThreshold uint64) ErrDiscardThreshold {
+	return ErrDiscardThreshold{controlMsg: controlMsg, amount: amount, discardThreshold: discardThreshold}
+}
+
+// IsErrDiscardThreshold returns true if an error is ErrDiscardThreshold.
+func IsErrDiscardThreshold(err error) bool {
+	var e ErrDiscardThreshold
+	return errors.As(err, &e)
+}
+
+// ErrInvalidTopic indicates that a control message refers to a topic that is not a valid Flow topic.
+type ErrInvalidTopic struct {
+	controlMsg p2p.ControlMessageType
+	topic      channels.Topic
+	err        error
+}
+
+func (e ErrInvalidTopic) Error() string {
+	return fmt.Errorf("invalid topic %s in %s message: %w", e.topic, e.controlMsg, e.err).Error()
+}
+
+// NewInvalidTopicErr returns a new ErrInvalidTopic.
+func NewInvalidTopicErr(controlMsg p2p.ControlMessageType, topic channels.Topic, err error) ErrInvalidTopic {
+	return ErrInvalidTopic{controlMsg: controlMsg, topic: topic, err: err}
+}
+
+// IsErrInvalidTopic returns true if an error is ErrInvalidTopic.
+func IsErrInvalidTopic(err error) bool {
+	var e ErrInvalidTopic
+	return errors.As(err, &e)
+}
+
+// ErrUnsubscribedTopic indicates that a control message refers to a topic the local node is not subscribed to.
+type ErrUnsubscribedTopic struct {
+	controlMsg p2p.ControlMessageType
+	topic      channels.Topic
+}
+
+func (e ErrUnsubscribedTopic) Error() string {
+	return fmt.Sprintf("received %s message for topic %s the node is not subscribed to", e.controlMsg, e.topic)
+}
+
+// NewUnsubscribedTopicErr returns a new ErrUnsubscribedTopic.
+func NewUnsubscribedTopicErr(controlMsg p2p.ControlMessageType, topic channels.Topic) ErrUnsubscribedTopic {
+	return ErrUnsubscribedTopic{controlMsg: controlMsg, topic: topic}
+}
+
+// IsErrUnsubscribedTopic returns true if an error is ErrUnsubscribedTopic.
+func IsErrUnsubscribedTopic(err error) bool {
+	var e ErrUnsubscribedTopic
+	return errors.As(err, &e)
+}
+
+// ErrIWantFlood indicates that the IWANT messages of a single RPC request more message IDs than allowed.
+type ErrIWantFlood struct {
+	messageIDs    uint64 // the total number of message IDs requested by the RPC
+	maxMessageIDs uint64 // the configured maximum
+}
+
+func (e ErrIWantFlood) Error() string {
+	return fmt.Sprintf("number of message ids requested by iwant messages exceeds the configured maximum: requested %d maximum %d", e.messageIDs, e.maxMessageIDs)
+}
+
+// NewIWantFloodErr returns a new ErrIWantFlood.
+func NewIWantFloodErr(messageIDs, maxMessageIDs uint64) ErrIWantFlood {
+	return ErrIWantFlood{messageIDs: messageIDs, maxMessageIDs: maxMessageIDs}
+}
+
+// IsErrIWantFlood returns true if an error is ErrIWantFlood.
+func IsErrIWantFlood(err error) bool {
+	var e ErrIWantFlood
+	return errors.As(err, &e)
+}
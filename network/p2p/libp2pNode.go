@@ -48,6 +48,10 @@ type LibP2PNode interface {
 	RoutingTable() *kbucket.RoutingTable
 	// ListPeers returns list of peer IDs for peers subscribed to the topic.
 	ListPeers(topic string) []peer.ID
+	// GetLocalMeshPeers returns the local mesh peers of the node for the given topic.
+	GetLocalMeshPeers(topic channels.Topic) []peer.ID
+	// GetSubscribedTopics returns the topics the node is currently subscribed to.
+	GetSubscribedTopics() []channels.Topic
 	// Subscribe subscribes the node to the given topic and returns the subscription
 	Subscribe(topic channels.Topic, topicValidator TopicValidatorFunc) (Subscription, error)
 	// UnSubscribe cancels the subscriber and closes the topic.
@@ -8,6 +8,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"net"
 	"sync"
 	"time"
 
@@ -32,6 +33,7 @@ import (
 	"github.com/onflow/flow-go/network/p2p/blob"
 	"github.com/onflow/flow-go/network/p2p/p2pnode"
 	"github.com/onflow/flow-go/network/p2p/ping"
+	"github.com/onflow/flow-go/network/p2p/tracer"
 	"github.com/onflow/flow-go/network/p2p/unicast/protocols"
 	"github.com/onflow/flow-go/network/p2p/unicast/ratelimit"
 	"github.com/onflow/flow-go/network/p2p/utils"
@@ -100,6 +102,10 @@ type Middleware struct {
 	slashingViolationsConsumer slashing.ViolationsConsumer
 	unicastRateLimiters        *ratelimit.RateLimiters
 	authorizedSenderValidator  *validator.AuthorizedSenderValidator
+	maxIdleStreamsPerPeer      int
+	streamIdleTimeout          time.Duration
+	streamPool                 *streamPool           // used to reuse the outbound unicast streams across messages
+	messageTracer              *tracer.MessageTracer // nil if message tracing is disabled
 	component.Component
 }
 
@@ -132,6 +138,23 @@ func WithUnicastRateLimiters(rateLimiters *ratelimit.RateLimiters) MiddlewareOpt
 	}
 }
 
+// WithUnicastStreamPool sets the maximum number of idle outbound unicast streams kept open per peer for reuse, and the
+// duration after which an idle stream is closed. Setting the maximum number of idle streams to zero disables the reuse
+// of streams, i.e., a new stream is created for each message.
+func WithUnicastStreamPool(maxIdleStreamsPerPeer int, idleTimeout time.Duration) MiddlewareOption {
+	return func(mw *Middleware) {
+		mw.maxIdleStreamsPerPeer = maxIdleStreamsPerPeer
+		mw.streamIdleTimeout = idleTimeout
+	}
+}
+
+// WithMessageTracer enables the tracing of the messages sent and received by the middleware.
+func WithMessageTracer(messageTracer *tracer.MessageTracer) MiddlewareOption {
+	return func(mw *Middleware) {
+		mw.messageTracer = messageTracer
+	}
+}
+
 // NewMiddleware creates a new middleware instance
 // libP2PNodeFactory is the factory used to create a LibP2PNode
 // flowID is this node's Flow ID
@@ -170,12 +193,16 @@ func NewMiddleware(
 		codec:                      codec,
 		slashingViolationsConsumer: slashingViolationsConsumer,
 		unicastRateLimiters:        ratelimit.NoopRateLimiters(),
+		maxIdleStreamsPerPeer:      DefaultMaxIdleStreamsPerPeer,
+		streamIdleTimeout:          DefaultStreamIdleTimeout,
 	}
 
 	for _, opt := range opts {
 		opt(mw)
 	}
 
+	mw.streamPool = newStreamPool(log, mw.maxIdleStreamsPerPeer, mw.streamIdleTimeout, libP2PNode.CreateStream)
+
 	cm := component.NewComponentManagerBuilder().
 		AddWorker(func(ctx irrecoverable.SignalerContext, ready component.ReadyFunc) {
 			// TODO: refactor to avoid storing ctx altogether
@@ -199,7 +226,8 @@ func NewMiddleware(
 			mw.unicastRateLimiters.Stop()
 			mw.log.Info().Str("component", "middleware").Msg("cleaned up unicast rate limiter resources")
 
-		}).Build()
+		}).
+		AddWorker(mw.closeIdleStreamsLoop).Build()
 
 	mw.Component = cm
 	return mw
@@ -346,10 +374,37 @@ func (m *Middleware) topologyPeers() peer.IDSlice {
 	return peerIDs
 }
 
+// closeIdleStreamsLoop periodically closes the outbound unicast streams that have been idle for the idle timeout, and
+// closes all the idle streams on shutdown.
+func (m *Middleware) closeIdleStreamsLoop(ctx irrecoverable.SignalerContext, ready component.ReadyFunc) {
+	ready()
+
+	if m.streamIdleTimeout <= 0 {
+		// streams expire as soon as they are idle, hence are never reused and closed when acquired.
+		<-ctx.Done()
+		m.streamPool.closeAll()
+		return
+	}
+
+	ticker := time.NewTicker(m.streamIdleTimeout / 2)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			m.streamPool.closeAll()
+			return
+		case <-ticker.C:
+			m.streamPool.closeExpired()
+		}
+	}
+}
+
 // OnDisallowListNotification is called when a new disallow list update notification is distributed.
 // It disconnects from all peers in the disallow list.
 func (m *Middleware) OnDisallowListNotification(notification *p2p.DisallowListUpdateNotification) {
 	for _, pid := range m.peerIDs(notification.DisallowList) {
+		m.streamPool.closePeer(pid)
 		err := m.libP2PNode.RemovePeer(pid)
 		if err != nil {
 			m.log.Error().Err(err).Str("peer_id", pid.String()).Msg("failed to disconnect from blocklisted peer")
@@ -371,6 +426,10 @@ func (m *Middleware) OnDisallowListNotification(notification *p2p.DisallowListUp
 //
 // All errors returned from this function can be considered benign.
 func (m *Middleware) SendDirect(msg *network.OutgoingMessageScope) error {
+	if m.messageTracer != nil {
+		m.messageTracer.OnOutgoingMessage(msg.Proto(), msg.Payload())
+	}
+
 	// since it is a unicast, we only need to get the first peer ID.
 	peerID, err := m.idTranslator.GetPeerID(msg.TargetIds()[0])
 	if err != nil {
@@ -392,56 +451,71 @@ func (m *Middleware) SendDirect(msg *network.OutgoingMessageScope) error {
 	defer cancel()
 
 	// protect the underlying connection from being inadvertently pruned by the peer manager while the stream and
-	// connection creation is being attempted, and remove it from protected list once stream created.
+	// connection creation is being attempted, and remove it from protected list once the message is sent.
 	tag := fmt.Sprintf("%v:%v", msg.Channel(), msg.PayloadType())
 	m.libP2PNode.Host().ConnManager().Protect(peerID, tag)
 	defer m.libP2PNode.Host().ConnManager().Unprotect(peerID, tag)
 
-	// create new stream
-	// streams don't need to be reused and are fairly inexpensive to be created for each send.
-	// A stream creation does NOT incur an RTT as stream negotiation happens as part of the first message
-	// sent out the receiver
-	stream, err := m.libP2PNode.CreateStream(ctx, peerID)
+	// streams are reused across the messages sent to the peer, see streamPool. A reused stream may have been reset by
+	// the receiver in the meantime (e.g., when the receiver rate limits this node), in which case the message is sent
+	// again on a new stream.
+	stream, reused, err := m.streamPool.acquire(ctx, peerID)
 	if err != nil {
 		return fmt.Errorf("failed to create stream for %s: %w", msg.TargetIds()[0], err)
 	}
+	err = m.writeToStream(ctx, stream, msg)
+	if err != nil && reused {
+		m.log.Debug().
+			Err(err).
+			Str("peer_id", peerID.String()).
+			Msg("failed to send message on reused stream, retrying on a new stream")
+		stream, _, err = m.streamPool.acquireNew(ctx, peerID)
+		if err != nil {
+			return fmt.Errorf("failed to create stream for %s: %w", msg.TargetIds()[0], err)
+		}
+		err = m.writeToStream(ctx, stream, msg)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to send message to %s: %w", msg.TargetIds()[0], err)
+	}
 
-	success := false
+	return nil
+}
 
+// writeToStream writes the message on the stream within the deadline of the context, and hands the stream back to
+// the stream pool: the stream is released for reuse if the message is written, and discarded otherwise.
+// All errors returned from this function can be considered benign.
+func (m *Middleware) writeToStream(ctx context.Context, stream *pooledStream, msg *network.OutgoingMessageScope) error {
+	success := false
 	defer func() {
 		if success {
-			// close the stream immediately
-			err = stream.Close()
-			if err != nil {
-				err = fmt.Errorf("failed to close the stream for %s: %w", msg.TargetIds()[0], err)
-			}
+			m.streamPool.release(stream)
 		} else {
-			resetErr := stream.Reset()
-			if resetErr != nil {
-				m.log.Err(resetErr).Msg("failed to reset stream")
-			}
+			m.streamPool.discard(stream)
 		}
 	}()
 
 	deadline, _ := ctx.Deadline()
-	err = stream.SetWriteDeadline(deadline)
+	err := stream.stream.SetWriteDeadline(deadline)
 	if err != nil {
 		return fmt.Errorf("failed to set write deadline for stream: %w", err)
 	}
 
-	// create a gogo protobuf writer
-	bufw := bufio.NewWriter(stream)
-	writer := ggio.NewDelimitedWriter(bufw)
-
-	err = writer.WriteMsg(msg.Proto())
+	err = stream.writer.WriteMsg(msg.Proto())
 	if err != nil {
-		return fmt.Errorf("failed to send message to %s: %w", msg.TargetIds()[0], err)
+		return fmt.Errorf("failed to write message: %w", err)
 	}
 
 	// flush the stream
-	err = bufw.Flush()
+	err = stream.buffer.Flush()
+	if err != nil {
+		return fmt.Errorf("failed to flush stream: %w", err)
+	}
+
+	// the deadline of the next message on the stream is set when it is written
+	err = stream.stream.SetWriteDeadline(time.Time{})
 	if err != nil {
-		return fmt.Errorf("failed to flush stream for %s: %w", msg.TargetIds()[0], err)
+		return fmt.Errorf("failed to clear write deadline for stream: %w", err)
 	}
 
 	success = true
@@ -483,38 +557,59 @@ func (m *Middleware) handleIncomingStream(s libp2pnetwork.Stream) {
 		return
 	}
 
-	// TODO: We need to allow per-topic timeouts and message size limits.
-	// This allows us to configure higher limits for topics on which we expect
-	// to receive large messages (e.g. Chunk Data Packs), and use the normal
-	// limits for other topics. In order to enable this, we will need to register
-	// a separate stream handler for each topic.
-	ctx, cancel := context.WithTimeout(m.ctx, LargeMsgUnicastTimeout)
-	defer cancel()
-
-	deadline, _ := ctx.Deadline()
-
-	err := s.SetReadDeadline(deadline)
-	if err != nil {
-		log.Err(err).Msg("failed to set read deadline for stream")
-		return
+	// streams are reused by their senders across messages, hence the stream is read until the sender closes it, or
+	// until no message arrives for the inbound idle timeout.
+	idleTimeout := m.streamIdleTimeout * inboundStreamIdleTimeoutFactor
+	if idleTimeout < m.unicastMessageTimeout {
+		idleTimeout = m.unicastMessageTimeout
 	}
 
-	// create the reader
-	r := ggio.NewDelimitedReader(s, LargeMsgMaxUnicastMsgSize)
+	// create the reader; the delimited reader reads through the buffered reader, which allows waiting for the next
+	// message separately from reading it.
+	br := bufio.NewReader(s)
+	r := ggio.NewDelimitedReader(br, LargeMsgMaxUnicastMsgSize)
 	for {
-		if ctx.Err() != nil {
+		if m.ctx.Err() != nil {
 			return
 		}
 
-		// Note: message fields must not be trusted until explicitly validated
-		var msg message.Message
-		// read the next message (blocking call)
-		err = r.ReadMsg(&msg)
+		// wait for the next message
+		err := s.SetReadDeadline(time.Now().Add(idleTimeout))
+		if err != nil {
+			log.Err(err).Msg("failed to set read deadline for stream")
+			return
+		}
+		_, err = br.Peek(1)
 		if err != nil {
 			if err == io.EOF {
 				break
 			}
+			var netErr net.Error
+			if errors.As(err, &netErr) && netErr.Timeout() {
+				log.Debug().Msg("closing idle stream")
+				break
+			}
+
+			m.log.Err(err).Msg("failed to read message")
+			return
+		}
+
+		// TODO: We need to allow per-topic timeouts and message size limits.
+		// This allows us to configure higher limits for topics on which we expect
+		// to receive large messages (e.g. Chunk Data Packs), and use the normal
+		// limits for other topics. In order to enable this, we will need to register
+		// a separate stream handler for each topic.
+		err = s.SetReadDeadline(time.Now().Add(LargeMsgUnicastTimeout))
+		if err != nil {
+			log.Err(err).Msg("failed to set read deadline for stream")
+			return
+		}
 
+		// Note: message fields must not be trusted until explicitly validated
+		var msg message.Message
+		// read the next message (blocking call)
+		err = r.ReadMsg(&msg)
+		if err != nil {
 			m.log.Err(err).Msg("failed to read message")
 			return
 		}
@@ -626,8 +721,12 @@ func (m *Middleware) Subscribe(channel channels.Channel) error {
 }
 
 // processPubSubMessages processes messages received from the pubsub subscription.
-func (m *Middleware) processPubSubMessages(msg *message.Message, peerID peer.ID) {
-	m.processAuthenticatedMessage(msg, peerID, message.ProtocolTypePubSub)
+func (m *Middleware) processPubSubMessages(msg *message.Message, peerID peer.ID, receivedFrom peer.ID) {
+	// the messages are signed by their origin and forwarded as is by the pubsub routers, hence the number of hops of a
+	// message is unknown, and it is only known whether the message was relayed by another peer. A hop counter cannot be
+	// added to the message, as the relaying peers would have to update the signed data (see tracer.MessageTracer).
+	relayed := receivedFrom != peerID
+	m.processAuthenticatedMessage(msg, peerID, message.ProtocolTypePubSub, relayed)
 }
 
 // Unsubscribe unsubscribes the middleware from a channel.
@@ -688,12 +787,14 @@ func (m *Middleware) processUnicastStreamMessage(remotePeer peer.ID, msg *messag
 			return
 		}
 	}
-	m.processAuthenticatedMessage(msg, remotePeer, message.ProtocolTypeUnicast)
+	m.processAuthenticatedMessage(msg, remotePeer, message.ProtocolTypeUnicast, false)
 }
 
 // processAuthenticatedMessage processes a message and a source (indicated by its peer ID) and eventually passes it to the overlay
 // In particular, it populates the `OriginID` field of the message with a Flow ID translated from this source.
-func (m *Middleware) processAuthenticatedMessage(msg *message.Message, peerID peer.ID, protocol message.ProtocolType) {
+// The relayed flag indicates whether the message was relayed by another peer than its origin, which is only used for
+// message tracing.
+func (m *Middleware) processAuthenticatedMessage(msg *message.Message, peerID peer.ID, protocol message.ProtocolType, relayed bool) {
 	originId, err := m.idTranslator.GetFlowID(peerID)
 	if err != nil {
 		// this error should never happen. by the time the message gets here, the peer should be
@@ -735,6 +836,10 @@ func (m *Middleware) processAuthenticatedMessage(msg *message.Message, peerID pe
 		return
 	}
 
+	if m.messageTracer != nil {
+		m.messageTracer.OnIncomingMessage(msg, decodedMsgPayload, originId, relayed)
+	}
+
 	scope, err := network.NewIncomingScope(originId, protocol, msg, decodedMsgPayload)
 	if err != nil {
 		m.log.Error().
@@ -786,6 +891,10 @@ func (m *Middleware) processMessage(scope *network.IncomingMessageScope) {
 //
 // All errors returned from this function can be considered benign.
 func (m *Middleware) Publish(msg *network.OutgoingMessageScope) error {
+	if m.messageTracer != nil {
+		m.messageTracer.OnOutgoingMessage(msg.Proto(), msg.Payload())
+	}
+
 	m.log.Debug().
 		Str("channel", msg.Channel().String()).
 		Interface("msg", msg.Proto()).
@@ -830,7 +939,7 @@ func (m *Middleware) IsConnected(nodeID flow.Identifier) (bool, error) {
 // unicastMaxMsgSize returns the max permissible size for a unicast message
 func unicastMaxMsgSize(messageType string) int {
 	switch messageType {
-	case "messages.ChunkDataResponse":
+	case "messages.ChunkDataResponse", "flow.ChunkFaultReport":
 		return LargeMsgMaxUnicastMsgSize
 	default:
 		return DefaultMaxUnicastMsgSize
@@ -855,7 +964,7 @@ func unicastMaxMsgSizeByCode(payload []byte) (int, error) {
 // unicastMaxMsgDuration returns the max duration to allow for a unicast send to complete
 func (m *Middleware) unicastMaxMsgDuration(messageType string) time.Duration {
 	switch messageType {
-	case "messages.ChunkDataResponse":
+	case "messages.ChunkDataResponse", "flow.ChunkFaultReport":
 		if LargeMsgUnicastTimeout > m.unicastMessageTimeout {
 			return LargeMsgUnicastTimeout
 		}
@@ -16,8 +16,9 @@ import (
 	"github.com/onflow/flow-go/utils/logging"
 )
 
-// ReadSubscriptionCallBackFunction the callback called when a new message is received on the read subscription
-type ReadSubscriptionCallBackFunction func(msg *message.Message, peerID peer.ID)
+// ReadSubscriptionCallBackFunction the callback called when a new message is received on the read subscription, peerID is
+// the origin of the message and receivedFrom is the peer that forwarded the message to this node.
+type ReadSubscriptionCallBackFunction func(msg *message.Message, peerID peer.ID, receivedFrom peer.ID)
 
 // readSubscription reads the messages coming in on the subscription and calls the given callback until
 // the context of the subscription is cancelled.
@@ -77,6 +78,6 @@ func (r *readSubscription) receiveLoop(ctx context.Context) {
 		}
 
 		// call the callback
-		r.callback(validatorData.Message, validatorData.From)
+		r.callback(validatorData.Message, validatorData.From, rawMsg.ReceivedFrom)
 	}
 }
@@ -0,0 +1,206 @@
+package middleware
+
+import (
+	"bufio"
+	"context"
+	"sync"
+	"time"
+
+	ggio "github.com/gogo/protobuf/io"
+	libp2pnetwork "github.com/libp2p/go-libp2p/core/network"
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/rs/zerolog"
+)
+
+const (
+	// DefaultMaxIdleStreamsPerPeer is the default maximum number of idle outbound unicast streams kept open per peer
+	// for reuse. A stream is only used by one message at a time, hence concurrent sends to the same peer open
+	// additional streams, of which at most this many are kept once the sends complete.
+	DefaultMaxIdleStreamsPerPeer = 4
+
+	// DefaultStreamIdleTimeout is the default duration after which an idle outbound unicast stream is closed.
+	DefaultStreamIdleTimeout = time.Minute
+
+	// inboundStreamIdleTimeoutFactor is the factor applied to the idle timeout of the outbound streams to obtain the
+	// idle timeout of the inbound streams. The receiver waits longer than the sender so that a pooled stream is
+	// always closed by its sender, rather than by the receiver while the sender is writing a new message on it.
+	inboundStreamIdleTimeoutFactor = 2
+)
+
+// pooledStream is an outbound unicast stream along with the writer used to write messages on it.
+type pooledStream struct {
+	peerID   peer.ID
+	stream   libp2pnetwork.Stream
+	buffer   *bufio.Writer
+	writer   ggio.Writer
+	lastUsed time.Time
+}
+
+// streamPool keeps the outbound unicast streams to each peer open after a message is sent, so that the streams are
+// reused by the next messages sent to the peer rather than created for each message. A stream is used by at most one
+// message at a time; the streams not in use are idle, and are closed once they have been idle for the idle timeout.
+type streamPool struct {
+	mu             sync.Mutex
+	log            zerolog.Logger
+	idle           map[peer.ID][]*pooledStream
+	maxIdlePerPeer int
+	idleTimeout    time.Duration
+	createStream   func(context.Context, peer.ID) (libp2pnetwork.Stream, error)
+}
+
+func newStreamPool(
+	log zerolog.Logger,
+	maxIdlePerPeer int,
+	idleTimeout time.Duration,
+	createStream func(context.Context, peer.ID) (libp2pnetwork.Stream, error),
+) *streamPool {
+	return &streamPool{
+		log:            log.With().Str("component", "unicast_stream_pool").Logger(),
+		idle:           make(map[peer.ID][]*pooledStream),
+		maxIdlePerPeer: maxIdlePerPeer,
+		idleTimeout:    idleTimeout,
+		createStream:   createStream,
+	}
+}
+
+// acquire returns a stream to the peer for the exclusive use of the caller, who must hand it back using release or
+// discard. It returns the most recently used idle stream to the peer if any, and creates a new stream otherwise. The
+// returned boolean is true if the stream is reused.
+// All errors returned by acquire are benign, and are the errors of the stream creation.
+func (p *streamPool) acquire(ctx context.Context, peerID peer.ID) (*pooledStream, bool, error) {
+	if s := p.popIdle(peerID); s != nil {
+		return s, true, nil
+	}
+	return p.acquireNew(ctx, peerID)
+}
+
+// acquireNew creates a new stream to the peer for the exclusive use of the caller, who must hand it back using
+// release or discard. The returned boolean is always false, as the stream is not reused.
+// All errors returned by acquireNew are benign, and are the errors of the stream creation.
+func (p *streamPool) acquireNew(ctx context.Context, peerID peer.ID) (*pooledStream, bool, error) {
+	stream, err := p.createStream(ctx, peerID)
+	if err != nil {
+		return nil, false, err
+	}
+	buffer := bufio.NewWriter(stream)
+	return &pooledStream{
+		peerID: peerID,
+		stream: stream,
+		buffer: buffer,
+		writer: ggio.NewDelimitedWriter(buffer),
+	}, false, nil
+}
+
+// popIdle removes and returns the most recently used idle stream to the peer, closing the expired idle streams on
+// the way. It returns nil if there is no idle stream to the peer.
+func (p *streamPool) popIdle(peerID peer.ID) *pooledStream {
+	p.mu.Lock()
+	var expired []*pooledStream
+	var reused *pooledStream
+	streams := p.idle[peerID]
+	for len(streams) > 0 {
+		s := streams[len(streams)-1]
+		streams = streams[:len(streams)-1]
+		if time.Since(s.lastUsed) < p.idleTimeout {
+			reused = s
+			break
+		}
+		// the stream is about to be closed by the idle cleanup, hence it is not safe to reuse it.
+		expired = append(expired, s)
+	}
+	p.setIdle(peerID, streams)
+	p.mu.Unlock()
+
+	p.close(expired...)
+	return reused
+}
+
+// release hands the stream back to the pool after a message was successfully sent on it. The stream is kept idle for
+// reuse, unless the pool already holds the maximum number of idle streams to the peer.
+func (p *streamPool) release(s *pooledStream) {
+	p.mu.Lock()
+	if len(p.idle[s.peerID]) >= p.maxIdlePerPeer {
+		p.mu.Unlock()
+		p.close(s)
+		return
+	}
+	s.lastUsed = time.Now()
+	p.idle[s.peerID] = append(p.idle[s.peerID], s)
+	p.mu.Unlock()
+}
+
+// discard resets the stream after a failure to send a message on it.
+func (p *streamPool) discard(s *pooledStream) {
+	err := s.stream.Reset()
+	if err != nil {
+		p.log.Err(err).Str("peer_id", s.peerID.String()).Msg("failed to reset stream")
+	}
+}
+
+// closeExpired closes the streams that have been idle for the idle timeout.
+func (p *streamPool) closeExpired() {
+	p.mu.Lock()
+	var expired []*pooledStream
+	for peerID, streams := range p.idle {
+		// the idle streams of a peer are ordered by last use, hence the expired ones come first.
+		n := 0
+		for n < len(streams) && time.Since(streams[n].lastUsed) >= p.idleTimeout {
+			n++
+		}
+		expired = append(expired, streams[:n]...)
+		p.setIdle(peerID, streams[n:])
+	}
+	p.mu.Unlock()
+
+	p.close(expired...)
+}
+
+// closePeer closes the idle streams to the peer.
+func (p *streamPool) closePeer(peerID peer.ID) {
+	p.mu.Lock()
+	streams := p.idle[peerID]
+	delete(p.idle, peerID)
+	p.mu.Unlock()
+
+	p.close(streams...)
+}
+
+// closeAll closes all the idle streams.
+func (p *streamPool) closeAll() {
+	p.mu.Lock()
+	var streams []*pooledStream
+	for peerID, idle := range p.idle {
+		streams = append(streams, idle...)
+		delete(p.idle, peerID)
+	}
+	p.mu.Unlock()
+
+	p.close(streams...)
+}
+
+// idleCount returns the number of idle streams to the peer.
+func (p *streamPool) idleCount(peerID peer.ID) int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return len(p.idle[peerID])
+}
+
+// setIdle sets the idle streams to the peer. The caller must hold the lock of the pool.
+func (p *streamPool) setIdle(peerID peer.ID, streams []*pooledStream) {
+	if len(streams) == 0 {
+		delete(p.idle, peerID)
+		return
+	}
+	p.idle[peerID] = streams
+}
+
+// close gracefully closes the streams, so that the receivers read all the messages sent on them before the end of
+// the streams. The streams must not be held by the pool anymore.
+func (p *streamPool) close(streams ...*pooledStream) {
+	for _, s := range streams {
+		err := s.stream.Close()
+		if err != nil {
+			p.log.Err(err).Str("peer_id", s.peerID.String()).Msg("failed to close stream")
+		}
+	}
+}
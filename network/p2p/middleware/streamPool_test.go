@@ -0,0 +1,135 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	libp2pnetwork "github.com/libp2p/go-libp2p/core/network"
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeStream is a stream recording the bytes written on it, and whether it was closed or reset.
+type fakeStream struct {
+	libp2pnetwork.Stream
+	written bytes.Buffer
+	closed  bool
+	reset   bool
+}
+
+func (s *fakeStream) Write(p []byte) (int, error) {
+	return s.written.Write(p)
+}
+
+func (s *fakeStream) Close() error {
+	s.closed = true
+	return nil
+}
+
+func (s *fakeStream) Reset() error {
+	s.reset = true
+	return nil
+}
+
+// newFakeStreamPool returns a stream pool creating fake streams, and the list of the streams it created.
+func newFakeStreamPool(maxIdlePerPeer int, idleTimeout time.Duration) (*streamPool, *[]*fakeStream) {
+	created := make([]*fakeStream, 0)
+	pool := newStreamPool(zerolog.Nop(), maxIdlePerPeer, idleTimeout, func(context.Context, peer.ID) (libp2pnetwork.Stream, error) {
+		s := &fakeStream{}
+		created = append(created, s)
+		return s, nil
+	})
+	return pool, &created
+}
+
+// TestStreamPool_Reuse evaluates that the streams released to the pool are reused for the next messages to the same
+// peer, and that the pool keeps at most the configured number of idle streams per peer.
+func TestStreamPool_Reuse(t *testing.T) {
+	pool, created := newFakeStreamPool(2, time.Minute)
+	peerA, peerB := peer.ID("a"), peer.ID("b")
+
+	s1, reused, err := pool.acquire(context.Background(), peerA)
+	require.NoError(t, err)
+	require.False(t, reused)
+	pool.release(s1)
+
+	// the idle stream is reused for the same peer, but not for another peer.
+	s2, reused, err := pool.acquire(context.Background(), peerA)
+	require.NoError(t, err)
+	require.True(t, reused)
+	require.Same(t, s1, s2)
+	_, reused, err = pool.acquire(context.Background(), peerB)
+	require.NoError(t, err)
+	require.False(t, reused)
+
+	// concurrent sends open new streams, of which only the maximum number of idle streams is kept.
+	s3, _, err := pool.acquire(context.Background(), peerA)
+	require.NoError(t, err)
+	s4, _, err := pool.acquire(context.Background(), peerA)
+	require.NoError(t, err)
+	pool.release(s2)
+	pool.release(s3)
+	pool.release(s4)
+	require.Equal(t, 2, pool.idleCount(peerA))
+	require.Len(t, *created, 4)
+	require.True(t, s4.stream.(*fakeStream).closed)
+
+	// a discarded stream is reset and not reused.
+	s5, _, err := pool.acquire(context.Background(), peerA)
+	require.NoError(t, err)
+	pool.discard(s5)
+	require.True(t, s5.stream.(*fakeStream).reset)
+	require.Equal(t, 1, pool.idleCount(peerA))
+
+	pool.closeAll()
+	require.Zero(t, pool.idleCount(peerA))
+	require.True(t, s2.stream.(*fakeStream).closed)
+}
+
+// TestStreamPool_Expiry evaluates that the streams idle for the idle timeout are closed rather than reused.
+func TestStreamPool_Expiry(t *testing.T) {
+	pool, created := newFakeStreamPool(2, 50*time.Millisecond)
+	peerA := peer.ID("a")
+
+	s1, _, err := pool.acquire(context.Background(), peerA)
+	require.NoError(t, err)
+	pool.release(s1)
+	time.Sleep(100 * time.Millisecond)
+
+	// the expired stream is closed when acquiring a stream.
+	s2, reused, err := pool.acquire(context.Background(), peerA)
+	require.NoError(t, err)
+	require.False(t, reused)
+	require.True(t, s1.stream.(*fakeStream).closed)
+	pool.release(s2)
+
+	// the expired stream is closed by the cleanup.
+	time.Sleep(100 * time.Millisecond)
+	pool.closeExpired()
+	require.Zero(t, pool.idleCount(peerA))
+	require.True(t, s2.stream.(*fakeStream).closed)
+	require.Len(t, *created, 2)
+}
+
+// TestStreamPool_Disabled evaluates that a pool without idle streams closes each stream once released.
+func TestStreamPool_Disabled(t *testing.T) {
+	pool, created := newFakeStreamPool(0, time.Minute)
+	peerA := peer.ID("a")
+
+	for i := 0; i < 3; i++ {
+		s, reused, err := pool.acquire(context.Background(), peerA)
+		require.NoError(t, err)
+		require.False(t, reused)
+		_, err = s.buffer.WriteString(fmt.Sprintf("message %d", i))
+		require.NoError(t, err)
+		require.NoError(t, s.buffer.Flush())
+		pool.release(s)
+		require.True(t, s.stream.(*fakeStream).closed)
+	}
+	require.Len(t, *created, 3)
+	require.Equal(t, "message 2", (*created)[2].written.String())
+}
@@ -83,6 +83,16 @@ func (_m *GossipSubBuilder) SetGossipSubPeerScoring(_a0 bool) {
 	_m.Called(_a0)
 }
 
+// SetGossipSubRPCInspectorSuite provides a mock function with given fields: _a0
+func (_m *GossipSubBuilder) SetGossipSubRPCInspectorSuite(_a0 p2p.GossipSubInspectorSuite) {
+	_m.Called(_a0)
+}
+
+// SetTopicOracle provides a mock function with given fields: _a0
+func (_m *GossipSubBuilder) SetTopicOracle(_a0 func(string) bool) {
+	_m.Called(_a0)
+}
+
 // SetGossipSubScoreTracerInterval provides a mock function with given fields: _a0
 func (_m *GossipSubBuilder) SetGossipSubScoreTracerInterval(_a0 time.Duration) {
 	_m.Called(_a0)
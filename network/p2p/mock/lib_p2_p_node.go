@@ -121,6 +121,22 @@ func (_m *LibP2PNode) GetIPPort() (string, string, error) {
 	return r0, r1, r2
 }
 
+// GetLocalMeshPeers provides a mock function with given fields: topic
+func (_m *LibP2PNode) GetLocalMeshPeers(topic channels.Topic) []peer.ID {
+	ret := _m.Called(topic)
+
+	var r0 []peer.ID
+	if rf, ok := ret.Get(0).(func(channels.Topic) []peer.ID); ok {
+		r0 = rf(topic)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]peer.ID)
+		}
+	}
+
+	return r0
+}
+
 // GetPeersForProtocol provides a mock function with given fields: pid
 func (_m *LibP2PNode) GetPeersForProtocol(pid protocol.ID) peer.IDSlice {
 	ret := _m.Called(pid)
@@ -137,6 +153,22 @@ func (_m *LibP2PNode) GetPeersForProtocol(pid protocol.ID) peer.IDSlice {
 	return r0
 }
 
+// GetSubscribedTopics provides a mock function with given fields:
+func (_m *LibP2PNode) GetSubscribedTopics() []channels.Topic {
+	ret := _m.Called()
+
+	var r0 []channels.Topic
+	if rf, ok := ret.Get(0).(func() []channels.Topic); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]channels.Topic)
+		}
+	}
+
+	return r0
+}
+
 // HasSubscription provides a mock function with given fields: topic
 func (_m *LibP2PNode) HasSubscription(topic channels.Topic) bool {
 	ret := _m.Called(topic)
@@ -153,6 +153,22 @@ func (_m *NodeBuilder) SetGossipSubFactory(_a0 p2p.GossipSubFactoryFunc, _a1 p2p
 	return r0
 }
 
+// SetGossipSubRPCInspectorSuite provides a mock function with given fields: _a0
+func (_m *NodeBuilder) SetGossipSubRPCInspectorSuite(_a0 p2p.GossipSubInspectorSuite) p2p.NodeBuilder {
+	ret := _m.Called(_a0)
+
+	var r0 p2p.NodeBuilder
+	if rf, ok := ret.Get(0).(func(p2p.GossipSubInspectorSuite) p2p.NodeBuilder); ok {
+		r0 = rf(_a0)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(p2p.NodeBuilder)
+		}
+	}
+
+	return r0
+}
+
 // SetGossipSubScoreTracerInterval provides a mock function with given fields: _a0
 func (_m *NodeBuilder) SetGossipSubScoreTracerInterval(_a0 time.Duration) p2p.NodeBuilder {
 	ret := _m.Called(_a0)
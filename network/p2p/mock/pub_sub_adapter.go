@@ -32,6 +32,22 @@ func (_m *PubSubAdapter) Done() <-chan struct{} {
 	return r0
 }
 
+// GetLocalMeshPeers provides a mock function with given fields: topic
+func (_m *PubSubAdapter) GetLocalMeshPeers(topic string) []peer.ID {
+	ret := _m.Called(topic)
+
+	var r0 []peer.ID
+	if rf, ok := ret.Get(0).(func(string) []peer.ID); ok {
+		r0 = rf(topic)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]peer.ID)
+		}
+	}
+
+	return r0
+}
+
 // GetTopics provides a mock function with given fields:
 func (_m *PubSubAdapter) GetTopics() []string {
 	ret := _m.Called()
@@ -54,6 +54,22 @@ func (_m *PubSubTracer) DuplicateMessage(msg *pubsub.Message) {
 	_m.Called(msg)
 }
 
+// GetMeshPeers provides a mock function with given fields: topic
+func (_m *PubSubTracer) GetMeshPeers(topic string) []peer.ID {
+	ret := _m.Called(topic)
+
+	var r0 []peer.ID
+	if rf, ok := ret.Get(0).(func(string) []peer.ID); ok {
+		r0 = rf(topic)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]peer.ID)
+		}
+	}
+
+	return r0
+}
+
 // Graft provides a mock function with given fields: p, topic
 func (_m *PubSubTracer) Graft(p peer.ID, topic string) {
 	_m.Called(p, topic)
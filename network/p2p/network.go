@@ -1,6 +1,7 @@
 package p2p
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"sync"
@@ -19,6 +20,7 @@ import (
 	"github.com/onflow/flow-go/network"
 	netcache "github.com/onflow/flow-go/network/cache"
 	"github.com/onflow/flow-go/network/channels"
+	"github.com/onflow/flow-go/network/internal/requests"
 	"github.com/onflow/flow-go/network/message"
 	"github.com/onflow/flow-go/network/p2p/conduit"
 	"github.com/onflow/flow-go/network/queue"
@@ -48,6 +50,13 @@ func WithConduitFactory(f network.ConduitFactory) NetworkOptFunction {
 	}
 }
 
+// WithInboundQueueConfig sets the configuration of the inbound message queue of the network.
+func WithInboundQueueConfig(config queue.FairQueueConfig) NetworkOptFunction {
+	return func(n *Network) {
+		n.queueConfig = config
+	}
+}
+
 // Network represents the overlay network of our peer-to-peer network, including
 // the protocols for handshakes, authentication, gossiping and heartbeats.
 type Network struct {
@@ -61,6 +70,8 @@ type Network struct {
 	metrics                     module.NetworkCoreMetrics
 	receiveCache                *netcache.ReceiveCache // used to deduplicate incoming messages
 	queue                       network.MessageQueue
+	queueConfig                 queue.FairQueueConfig       // configuration of the inbound message queue
+	requests                    *requests.Tracker           // used to route incoming responses to the requests in flight
 	subscriptionManager         network.SubscriptionManager // used to keep track of subscribed channels
 	conduitFactory              network.ConduitFactory
 	topology                    network.Topology
@@ -131,6 +142,8 @@ func NewNetwork(param *NetworkParameters) (*Network, error) {
 		subscriptionManager:         param.SubscriptionManager,
 		identityProvider:            param.IdentityProvider,
 		conduitFactory:              conduit.NewDefaultConduitFactory(),
+		queueConfig:                 queue.DefaultFairQueueConfig(),
+		requests:                    requests.NewTracker(),
 		registerEngineRequests:      make(chan *registerEngineRequest),
 		registerBlobServiceRequests: make(chan *registerBlobServiceRequest),
 	}
@@ -139,6 +152,10 @@ func NewNetwork(param *NetworkParameters) (*Network, error) {
 		opt(n)
 	}
 
+	if err := n.queueConfig.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid inbound queue config: %w", err)
+	}
+
 	n.mw.SetOverlay(n)
 
 	if err := n.conduitFactory.RegisterAdapter(n); err != nil {
@@ -203,8 +220,12 @@ func (n *Network) processRegisterBlobServiceRequests(parent irrecoverable.Signal
 
 func (n *Network) runMiddleware(ctx irrecoverable.SignalerContext, ready component.ReadyFunc) {
 	// setup the message queue
-	// create priority queue
-	n.queue = queue.NewMessageQueue(ctx, queue.GetEventPriority, n.metrics)
+	// create priority queue, shared fairly between the senders of the messages
+	mq, err := queue.NewFairMessageQueue(ctx, queue.GetEventPriority, n.identityProvider, n.metrics, n.queueConfig)
+	if err != nil {
+		ctx.Throw(fmt.Errorf("could not create inbound message queue: %w", err))
+	}
+	n.queue = mq
 
 	// create workers to read from the queue and call queueSubmitFunc
 	queue.CreateQueueWorkers(ctx, queue.DefaultNumWorkers, n.queue, n.queueSubmitFunc)
@@ -347,6 +368,11 @@ func (n *Network) processNetworkMessage(msg *network.IncomingMessageScope) error
 		return nil
 	}
 
+	// responses to the requests in flight are handed over to the requests, rather than queued for the engines
+	if n.requests.Deliver(msg.Channel(), msg.OriginId(), msg.DecodedPayload()) {
+		return nil
+	}
+
 	// create queue message
 	qm := queue.QMessage{
 		Payload:  msg.DecodedPayload(),
@@ -395,6 +421,22 @@ func (n *Network) UnicastOnChannel(channel channels.Channel, payload interface{}
 	return nil
 }
 
+// RequestOnChannel sends the request in a reliable way to the given recipient, and waits for the response of the
+// recipient until the context is done. If the context has no deadline, network.DefaultRequestTimeout applies.
+// Expected error returns during normal operations:
+//   - network.DuplicateRequestError if a request with the same request ID is in flight to the recipient on the channel.
+//   - an error wrapping context.DeadlineExceeded or context.Canceled if no response arrived in time.
+//   - an error if unicasting the request fails.
+func (n *Network) RequestOnChannel(ctx context.Context, channel channels.Channel, request network.RequestMessage, targetID flow.Identifier) (network.ResponseMessage, error) {
+	if targetID == n.me.NodeID() {
+		return nil, fmt.Errorf("network cannot send request %d to self", request.RequestID())
+	}
+
+	return n.requests.Request(ctx, channel, request, targetID, func() error {
+		return n.UnicastOnChannel(channel, request, targetID)
+	})
+}
+
 // PublishOnChannel sends the message in an unreliable way to the given recipients.
 // In this context, unreliable means that the message is published over a libp2p pub-sub
 // channel and can be read by any node subscribed to that channel.
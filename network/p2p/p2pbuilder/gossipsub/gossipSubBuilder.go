@@ -11,10 +11,15 @@ import (
 	"github.com/libp2p/go-libp2p/core/routing"
 	"github.com/rs/zerolog"
 
+	"github.com/onflow/flow-go/model/flow"
 	"github.com/onflow/flow-go/module"
 	"github.com/onflow/flow-go/module/irrecoverable"
+	"github.com/onflow/flow-go/module/mempool/queue"
 	"github.com/onflow/flow-go/network/channels"
 	"github.com/onflow/flow-go/network/p2p"
+	"github.com/onflow/flow-go/network/p2p/distributor"
+	"github.com/onflow/flow-go/network/p2p/inspector"
+	"github.com/onflow/flow-go/network/p2p/inspector/validation"
 	"github.com/onflow/flow-go/network/p2p/p2pnode"
 	"github.com/onflow/flow-go/network/p2p/scoring"
 	"github.com/onflow/flow-go/network/p2p/tracer"
@@ -37,6 +42,13 @@ type Builder struct {
 	peerScoringParameterOptions []scoring.PeerScoreParamsOption
 	idProvider                  module.IdentityProvider
 	routingSystem               routing.Routing
+	sporkID                     flow.Identifier
+	// rpcInspectorSuite inspects and validates the incoming RPCs of the GossipSub router. If not set, a suite with the
+	// default configuration is created upon build.
+	rpcInspectorSuite p2p.GossipSubInspectorSuite
+	// topicOracle returns whether the local node is subscribed to a topic. If not set, the rpc inspector suite does not
+	// validate control messages against the subscriptions of the node.
+	topicOracle func(topic string) bool
 }
 
 var _ p2p.GossipSubBuilder = (*Builder)(nil)
@@ -129,6 +141,27 @@ func (g *Builder) SetRoutingSystem(routingSystem routing.Routing) {
 	g.routingSystem = routingSystem
 }
 
+// SetGossipSubRPCInspectorSuite sets the rpc inspector suite of the builder.
+// If the rpc inspector suite has already been set, a fatal error is logged.
+func (g *Builder) SetGossipSubRPCInspectorSuite(inspectorSuite p2p.GossipSubInspectorSuite) {
+	if g.rpcInspectorSuite != nil {
+		g.logger.Fatal().Msg("rpc inspector suite has already been set")
+		return
+	}
+	g.rpcInspectorSuite = inspectorSuite
+}
+
+// SetTopicOracle sets the function returning whether the local node is subscribed to a topic, against which the rpc
+// inspector suite validates the incoming control messages.
+// If the topic oracle has already been set, a fatal error is logged.
+func (g *Builder) SetTopicOracle(topicOracle func(topic string) bool) {
+	if g.topicOracle != nil {
+		g.logger.Fatal().Msg("topic oracle has already been set")
+		return
+	}
+	g.topicOracle = topicOracle
+}
+
 func (g *Builder) SetTopicScoreParams(topic channels.Topic, topicScoreParams *pubsub.TopicScoreParams) {
 	g.peerScoringParameterOptions = append(g.peerScoringParameterOptions, scoring.WithTopicScoreParams(topic, topicScoreParams))
 }
@@ -137,10 +170,11 @@ func (g *Builder) SetAppSpecificScoreParams(f func(peer.ID) float64) {
 	g.peerScoringParameterOptions = append(g.peerScoringParameterOptions, scoring.WithAppSpecificScoreFunction(f))
 }
 
-func NewGossipSubBuilder(logger zerolog.Logger, metrics module.GossipSubMetrics) *Builder {
+func NewGossipSubBuilder(logger zerolog.Logger, metrics module.GossipSubMetrics, sporkID flow.Identifier) *Builder {
 	return &Builder{
 		logger:                      logger.With().Str("component", "gossipsub").Logger(),
 		metrics:                     metrics,
+		sporkID:                     sporkID,
 		gossipSubFactory:            defaultGossipSubFactory(),
 		gossipSubConfigFunc:         defaultGossipSubAdapterConfig(),
 		peerScoringParameterOptions: make([]scoring.PeerScoreParamsOption, 0),
@@ -159,6 +193,26 @@ func defaultGossipSubAdapterConfig() p2p.GossipSubAdapterConfigFunc {
 	}
 }
 
+// BuildGossipSubRPCInspectorSuite builds the rpc inspector suite of a GossipSub router, which records metrics on the
+// incoming control messages and validates them against the given configuration. The invalid control messages are
+// reported through a notification distributor, configured by the given hero store options.
+// All errors returned from this function indicate an invalid configuration.
+func BuildGossipSubRPCInspectorSuite(
+	logger zerolog.Logger,
+	sporkID flow.Identifier,
+	validationConfig *validation.ControlMsgValidationInspectorConfig,
+	metrics module.GossipSubMetrics,
+	distributorOpts ...queue.HeroStoreConfigOption,
+) (*inspector.GossipSubInspectorSuite, error) {
+	notificationDistributor := distributor.DefaultGossipSubInspectorNotificationDistributor(logger, distributorOpts...)
+	validationInspector, err := validation.NewControlMsgValidationInspector(logger, sporkID, validationConfig, notificationDistributor, metrics)
+	if err != nil {
+		return nil, fmt.Errorf("could not create control message validation inspector: %w", err)
+	}
+	metricsInspector := inspector.NewControlMsgMetricsInspector(logger, metrics)
+	return inspector.NewGossipSubInspectorSuite(metricsInspector, validationInspector, notificationDistributor), nil
+}
+
 // Build creates a new GossipSub pubsub system.
 // It returns the newly created GossipSub pubsub system and any errors encountered during its creation.
 // Arguments:
@@ -200,11 +254,15 @@ func (g *Builder) Build(ctx irrecoverable.SignalerContext) (p2p.PubSubAdapter, p
 		}
 	}
 
-	gossipSubMetrics := p2pnode.NewGossipSubControlMessageMetrics(g.metrics, g.logger)
-	gossipSubConfigs.WithAppSpecificRpcInspector(func(from peer.ID, rpc *pubsub.RPC) error {
-		gossipSubMetrics.ObserveRPC(from, rpc)
-		return nil
-	})
+	inspectorSuite := g.rpcInspectorSuite
+	if inspectorSuite == nil {
+		var err error
+		inspectorSuite, err = BuildGossipSubRPCInspectorSuite(g.logger, g.sporkID, validation.DefaultControlMsgValidationInspectorConfig(), g.metrics)
+		if err != nil {
+			return nil, nil, fmt.Errorf("could not create gossipsub rpc inspector suite: %w", err)
+		}
+	}
+	gossipSubConfigs.WithInspectorSuite(inspectorSuite)
 
 	if g.gossipSubTracer != nil {
 		gossipSubConfigs.WithTracer(g.gossipSubTracer)
@@ -219,8 +277,19 @@ func (g *Builder) Build(ctx irrecoverable.SignalerContext) (p2p.PubSubAdapter, p
 		return nil, nil, fmt.Errorf("could not create gossipsub: %w", err)
 	}
 
+	// the rpc inspectors run on the event loop of the router, hence the topic oracle must not query the router
+	// for its topics (e.g., through gossipSub.GetTopics), which would deadlock the event loop.
+	if g.topicOracle != nil {
+		err = inspectorSuite.SetTopicOracle(g.topicOracle)
+		if err != nil {
+			return nil, nil, fmt.Errorf("could not set topic oracle of the rpc inspector suite: %w", err)
+		}
+	}
+
 	if scoreOpt != nil {
 		scoreOpt.SetSubscriptionProvider(scoring.NewSubscriptionProvider(g.logger, gossipSub))
+		// peers reported by the rpc inspectors for invalid control messages are penalized by the app specific score.
+		inspectorSuite.AddInvalidControlMessageConsumer(scoreOpt)
 	}
 
 	return gossipSub, scoreTracer, nil
@@ -26,9 +26,13 @@ import (
 	"github.com/onflow/flow-go/module"
 	"github.com/onflow/flow-go/module/component"
 	"github.com/onflow/flow-go/module/irrecoverable"
+	"github.com/onflow/flow-go/module/mempool/queue"
+	"github.com/onflow/flow-go/network/channels"
 	"github.com/onflow/flow-go/network/p2p"
 	"github.com/onflow/flow-go/network/p2p/connection"
 	"github.com/onflow/flow-go/network/p2p/dht"
+	"github.com/onflow/flow-go/network/p2p/distributor"
+	"github.com/onflow/flow-go/network/p2p/inspector/validation"
 	"github.com/onflow/flow-go/network/p2p/keyutils"
 	gossipsubbuilder "github.com/onflow/flow-go/network/p2p/p2pbuilder/gossipsub"
 	"github.com/onflow/flow-go/network/p2p/p2pnode"
@@ -65,6 +69,15 @@ func DefaultGossipSubConfig() *GossipSubConfig {
 		PeerScoring:          defaultPeerScoringEnabled,
 		LocalMeshLogInterval: defaultMeshTracerLoggingInterval,
 		ScoreTracerInterval:  defaultGossipSubScoreTracerInterval,
+		RpcInspector:         DefaultGossipSubRPCInspectorConfig(),
+	}
+}
+
+// DefaultGossipSubRPCInspectorConfig returns the default configuration for the gossipsub rpc inspector suite.
+func DefaultGossipSubRPCInspectorConfig() *GossipSubRPCInspectorConfig {
+	return &GossipSubRPCInspectorConfig{
+		NotificationCacheSize: distributor.DefaultGossipSubInspectorNotificationQueueCacheSize,
+		ValidationConfig:      validation.DefaultControlMsgValidationInspectorConfig(),
 	}
 }
 
@@ -123,6 +136,16 @@ type GossipSubConfig struct {
 	ScoreTracerInterval time.Duration
 	// PeerScoring is whether to enable GossipSub peer scoring.
 	PeerScoring bool
+	// RpcInspector is the configuration of the rpc inspector suite of GossipSub.
+	RpcInspector *GossipSubRPCInspectorConfig
+}
+
+// GossipSubRPCInspectorConfig is the configuration for the rpc inspector suite of GossipSub.
+type GossipSubRPCInspectorConfig struct {
+	// NotificationCacheSize is the size of the queue of notifications for invalid control messages.
+	NotificationCacheSize uint32
+	// ValidationConfig is the configuration of the control message validation inspector.
+	ValidationConfig *validation.ControlMsgValidationInspectorConfig
 }
 
 func DefaultResourceManagerConfig() *ResourceManagerConfig {
@@ -168,7 +191,7 @@ func NewNodeBuilder(logger zerolog.Logger,
 		createNode:         DefaultCreateNodeFunc,
 		metrics:            metrics,
 		resourceManagerCfg: rCfg,
-		gossipSubBuilder:   gossipsubbuilder.NewGossipSubBuilder(logger, metrics),
+		gossipSubBuilder:   gossipsubbuilder.NewGossipSubBuilder(logger, metrics, sporkID),
 	}
 }
 
@@ -268,6 +291,13 @@ func (builder *LibP2PNodeBuilder) SetGossipSubScoreTracerInterval(interval time.
 	return builder
 }
 
+// SetGossipSubRPCInspectorSuite sets the rpc inspector suite which inspects and validates the incoming RPCs of the
+// GossipSub router. If not set, a suite with the default configuration is used.
+func (builder *LibP2PNodeBuilder) SetGossipSubRPCInspectorSuite(inspectorSuite p2p.GossipSubInspectorSuite) p2p.NodeBuilder {
+	builder.gossipSubBuilder.SetGossipSubRPCInspectorSuite(inspectorSuite)
+	return builder
+}
+
 // Build creates a new libp2p node using the configured options.
 func (builder *LibP2PNodeBuilder) Build() (p2p.LibP2PNode, error) {
 	if builder.routingFactory == nil {
@@ -354,6 +384,9 @@ func (builder *LibP2PNodeBuilder) Build() (p2p.LibP2PNode, error) {
 	}
 
 	node := builder.createNode(builder.logger, h, pCache, peerManager)
+	builder.gossipSubBuilder.SetTopicOracle(func(topic string) bool {
+		return node.HasSubscription(channels.Topic(topic))
+	})
 
 	unicastManager := unicast.NewUnicastManager(builder.logger,
 		unicast.NewLibP2PStreamFactory(h),
@@ -521,6 +554,16 @@ func DefaultNodeBuilder(log zerolog.Logger,
 	builder.SetGossipSubTracer(meshTracer)
 	builder.SetGossipSubScoreTracerInterval(gossipCfg.ScoreTracerInterval)
 
+	rpcInspectorSuite, err := gossipsubbuilder.BuildGossipSubRPCInspectorSuite(log,
+		sporkId,
+		gossipCfg.RpcInspector.ValidationConfig,
+		metrics,
+		queue.WithHeroStoreSizeLimit(gossipCfg.RpcInspector.NotificationCacheSize))
+	if err != nil {
+		return nil, fmt.Errorf("could not create gossipsub rpc inspector suite: %w", err)
+	}
+	builder.SetGossipSubRPCInspectorSuite(rpcInspectorSuite)
+
 	if role != "ghost" {
 		r, _ := flow.ParseRole(role)
 		builder.SetSubscriptionFilter(subscription.NewRoleBasedFilter(r, idProvider))
@@ -21,6 +21,8 @@ type GossipSubAdapter struct {
 	component.Component
 	gossipSub *pubsub.PubSub
 	logger    zerolog.Logger
+	// localMeshTracer is the tracer keeping track of the local mesh peers of the node, nil if no tracer is configured.
+	localMeshTracer p2p.PubSubTracer
 }
 
 var _ p2p.PubSubAdapter = (*GossipSubAdapter)(nil)
@@ -56,6 +58,7 @@ func NewGossipSubAdapter(ctx context.Context, logger zerolog.Logger, h host.Host
 	}
 
 	if tracer := gossipSubConfig.PubSubTracer(); tracer != nil {
+		a.localMeshTracer = tracer
 		builder.AddWorker(func(ctx irrecoverable.SignalerContext, ready component.ReadyFunc) {
 			ready()
 			a.logger.Debug().Str("component", "gossipsub_tracer").Msg("starting tracer")
@@ -67,6 +70,22 @@ func NewGossipSubAdapter(ctx context.Context, logger zerolog.Logger, h host.Host
 		})
 	}
 
+	if inspectorSuite := gossipSubConfig.InspectorSuite(); inspectorSuite != nil {
+		builder.AddWorker(func(ctx irrecoverable.SignalerContext, ready component.ReadyFunc) {
+			a.logger.Debug().Str("component", "gossipsub_inspector_suite").Msg("starting inspector suite")
+			inspectorSuite.Start(ctx)
+			select {
+			case <-ctx.Done():
+			case <-inspectorSuite.Ready():
+				ready()
+				a.logger.Debug().Str("component", "gossipsub_inspector_suite").Msg("inspector suite started")
+			}
+
+			<-inspectorSuite.Done()
+			a.logger.Debug().Str("component", "gossipsub_inspector_suite").Msg("inspector suite stopped")
+		})
+	}
+
 	a.Component = builder.Build()
 
 	return a, nil
@@ -115,3 +134,12 @@ func (g *GossipSubAdapter) GetTopics() []string {
 func (g *GossipSubAdapter) ListPeers(topic string) []peer.ID {
 	return g.gossipSub.ListPeers(topic)
 }
+
+// GetLocalMeshPeers returns the local mesh peers for the given topic, as tracked by the pubsub tracer.
+// If no tracer is configured, an empty list is returned.
+func (g *GossipSubAdapter) GetLocalMeshPeers(topic string) []peer.ID {
+	if g.localMeshTracer == nil {
+		return []peer.ID{}
+	}
+	return g.localMeshTracer.GetMeshPeers(topic)
+}
@@ -13,9 +13,10 @@ import (
 // GossipSubAdapterConfig is a wrapper around libp2p pubsub options that
 // implements the PubSubAdapterConfig interface for the Flow network.
 type GossipSubAdapterConfig struct {
-	options      []pubsub.Option
-	scoreTracer  p2p.PeerScoreTracer
-	pubsubTracer p2p.PubSubTracer
+	options        []pubsub.Option
+	scoreTracer    p2p.PeerScoreTracer
+	pubsubTracer   p2p.PubSubTracer
+	inspectorSuite p2p.GossipSubInspectorSuite
 }
 
 var _ p2p.PubSubAdapterConfig = (*GossipSubAdapterConfig)(nil)
@@ -44,8 +45,11 @@ func (g *GossipSubAdapterConfig) WithMessageIdFunction(f func([]byte) string) {
 	}))
 }
 
-func (g *GossipSubAdapterConfig) WithAppSpecificRpcInspector(f func(peer.ID, *pubsub.RPC) error) {
-	g.options = append(g.options, pubsub.WithAppSpecificRpcInspector(f))
+// WithInspectorSuite adds the inspector suite to the config. The inspect function of the suite is set as the
+// app specific rpc inspector of the GossipSub router, and the suite is started by the GossipSub adapter.
+func (g *GossipSubAdapterConfig) WithInspectorSuite(suite p2p.GossipSubInspectorSuite) {
+	g.options = append(g.options, pubsub.WithAppSpecificRpcInspector(suite.InspectFunc()))
+	g.inspectorSuite = suite
 }
 
 func (g *GossipSubAdapterConfig) WithTracer(tracer p2p.PubSubTracer) {
@@ -61,6 +65,11 @@ func (g *GossipSubAdapterConfig) PubSubTracer() p2p.PubSubTracer {
 	return g.pubsubTracer
 }
 
+// InspectorSuite returns the inspector suite of the config, or nil if no inspector suite has been set.
+func (g *GossipSubAdapterConfig) InspectorSuite() p2p.GossipSubInspectorSuite {
+	return g.inspectorSuite
+}
+
 func (g *GossipSubAdapterConfig) WithScoreTracer(tracer p2p.PeerScoreTracer) {
 	g.scoreTracer = tracer
 	g.options = append(g.options, pubsub.WithPeerScoreInspect(func(snapshot map[peer.ID]*pubsub.PeerScoreSnapshot) {
@@ -5,6 +5,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"sort"
 	"sync"
 	"time"
 
@@ -60,6 +61,14 @@ type Node struct {
 	pCache           p2p.ProtocolPeerCache
 	peerManager      p2p.PeerManager
 	peerScoreExposer p2p.PeerScoreExposer
+
+	// subscribedTopics is the set of topics the node is subscribed to, and sortedSubscribedTopics the same topics
+	// sorted alphabetically, rebuilt whenever the subscriptions change. Both are guarded by their own lock, which is
+	// never held while calling into the pubsub system, so that they can be read from the pubsub event loop (e.g., by
+	// the rpc inspectors) without deadlocking it.
+	subscribedTopicsLock   sync.RWMutex
+	subscribedTopics       map[channels.Topic]struct{}
+	sortedSubscribedTopics []channels.Topic
 }
 
 // NewNode creates a new libp2p node and sets its parameters.
@@ -70,12 +79,13 @@ func NewNode(
 	peerManager p2p.PeerManager,
 ) *Node {
 	return &Node{
-		host:        host,
-		logger:      logger.With().Str("component", "libp2p-node").Logger(),
-		topics:      make(map[channels.Topic]p2p.Topic),
-		subs:        make(map[channels.Topic]p2p.Subscription),
-		pCache:      pCache,
-		peerManager: peerManager,
+		host:             host,
+		logger:           logger.With().Str("component", "libp2p-node").Logger(),
+		topics:           make(map[channels.Topic]p2p.Topic),
+		subs:             make(map[channels.Topic]p2p.Subscription),
+		subscribedTopics: make(map[channels.Topic]struct{}),
+		pCache:           pCache,
+		peerManager:      peerManager,
 	}
 }
 
@@ -223,6 +233,44 @@ func (n *Node) ListPeers(topic string) []peer.ID {
 	return n.pubSub.ListPeers(topic)
 }
 
+// GetLocalMeshPeers returns the local mesh peers of the node for the given topic.
+func (n *Node) GetLocalMeshPeers(topic channels.Topic) []peer.ID {
+	return n.pubSub.GetLocalMeshPeers(topic.String())
+}
+
+// GetSubscribedTopics returns the topics the node is currently subscribed to, sorted alphabetically.
+// The returned slice is shared and must not be modified by the caller.
+// It is safe to call from the pubsub event loop.
+func (n *Node) GetSubscribedTopics() []channels.Topic {
+	n.subscribedTopicsLock.RLock()
+	defer n.subscribedTopicsLock.RUnlock()
+
+	return n.sortedSubscribedTopics
+}
+
+// setSubscribed adds the topic to or removes it from the subscribed topics of the node, and rebuilds the sorted list
+// of subscribed topics.
+func (n *Node) setSubscribed(topic channels.Topic, subscribed bool) {
+	n.subscribedTopicsLock.Lock()
+	defer n.subscribedTopicsLock.Unlock()
+
+	if subscribed {
+		n.subscribedTopics[topic] = struct{}{}
+	} else {
+		delete(n.subscribedTopics, topic)
+	}
+
+	// a new slice is allocated, as the previous one may still be read by callers of GetSubscribedTopics
+	topics := make([]channels.Topic, 0, len(n.subscribedTopics))
+	for t := range n.subscribedTopics {
+		topics = append(topics, t)
+	}
+	sort.Slice(topics, func(i, j int) bool {
+		return topics[i] < topics[j]
+	})
+	n.sortedSubscribedTopics = topics
+}
+
 // Subscribe subscribes the node to the given topic and returns the subscription
 // All errors returned from this function can be considered benign.
 func (n *Node) Subscribe(topic channels.Topic, topicValidator p2p.TopicValidatorFunc) (p2p.Subscription, error) {
@@ -259,6 +307,7 @@ func (n *Node) Subscribe(topic channels.Topic, topicValidator p2p.TopicValidator
 
 	// Add the subscription to the cache
 	n.subs[topic] = s
+	n.setSubscribed(topic, true)
 
 	n.logger.Debug().
 		Str("topic", topic.String()).
@@ -271,6 +320,8 @@ func (n *Node) Subscribe(topic channels.Topic, topicValidator p2p.TopicValidator
 func (n *Node) UnSubscribe(topic channels.Topic) error {
 	n.Lock()
 	defer n.Unlock()
+	n.setSubscribed(topic, false)
+
 	// Remove the Subscriber from the cache
 	if s, found := n.subs[topic]; found {
 		s.Cancel()
@@ -318,10 +369,11 @@ func (n *Node) Publish(ctx context.Context, topic channels.Topic, data []byte) e
 }
 
 // HasSubscription returns true if the node currently has an active subscription to the topic.
+// It is safe to call from the pubsub event loop.
 func (n *Node) HasSubscription(topic channels.Topic) bool {
-	n.RLock()
-	defer n.RUnlock()
-	_, ok := n.subs[topic]
+	n.subscribedTopicsLock.RLock()
+	defer n.subscribedTopicsLock.RUnlock()
+	_, ok := n.subscribedTopics[topic]
 	return ok
 }
 
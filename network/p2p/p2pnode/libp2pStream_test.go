@@ -141,6 +141,25 @@ func TestCreateStream_WithPreferredGzipUnicast(t *testing.T) {
 		protocols.FlowGzipProtocolId(sporkId))
 }
 
+// TestCreateStream_WithPreferredSnappyUnicast evaluates correctness of creating snappy-compressed tcp unicast streams between two libp2p nodes.
+func TestCreateStream_WithPreferredSnappyUnicast(t *testing.T) {
+	sporkId := unittest.IdentifierFixture()
+	testCreateStream(t,
+		sporkId,
+		[]protocols.ProtocolName{protocols.SnappyCompressionUnicast},
+		protocols.FlowSnappyProtocolId(sporkId))
+}
+
+// TestCreateStream_WithPreferredZstdUnicast evaluates correctness of creating zstd-compressed tcp unicast streams between two libp2p nodes.
+// The preferred stream type is the one with the largest index, i.e., zstd.
+func TestCreateStream_WithPreferredZstdUnicast(t *testing.T) {
+	sporkId := unittest.IdentifierFixture()
+	testCreateStream(t,
+		sporkId,
+		[]protocols.ProtocolName{protocols.GzipCompressionUnicast, protocols.ZstdCompressionUnicast},
+		protocols.FlowZstdProtocolId(sporkId))
+}
+
 // testCreateStreams checks if a new streams of "preferred" type is created each time when CreateStream is called and an existing stream is not
 // reused. The "preferred" stream type is the one with the largest index in `unicasts` list.
 // To check that the streams are of "preferred" type, it evaluates the protocol id of established stream against the input `protocolID`.
@@ -459,6 +478,51 @@ func TestUnicastOverStream_Fallback(t *testing.T) {
 	p2pfixtures.EnsureMessageExchangeOverUnicast(t, ctx, nodes, []chan string{inbound1, inbound2}, p2pfixtures.LongStringMessageFactoryFixture(t))
 }
 
+// TestCreateStream_Negotiation checks that a node creates streams on its most preferred unicast protocol that is also
+// supported by the remote node.
+// To do this, a node preferring zstd over gzip creates streams to a node supporting only snappy and gzip. The test evaluates
+// that the streams established between the two nodes are gzip-compressed.
+func TestCreateStream_Negotiation(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	signalerCtx := irrecoverable.NewMockSignalerContext(t, ctx)
+
+	sporkId := unittest.IdentifierFixture()
+	thisNode, _ := p2ptest.NodeFixture(t,
+		sporkId,
+		"test_create_stream_negotiation",
+		p2ptest.WithPreferredUnicasts([]protocols.ProtocolName{protocols.GzipCompressionUnicast, protocols.ZstdCompressionUnicast}))
+	otherNode, otherId := p2ptest.NodeFixture(t,
+		sporkId,
+		"test_create_stream_negotiation",
+		p2ptest.WithPreferredUnicasts([]protocols.ProtocolName{protocols.GzipCompressionUnicast, protocols.SnappyCompressionUnicast}))
+
+	nodes := []p2p.LibP2PNode{thisNode, otherNode}
+	p2ptest.StartNodes(t, signalerCtx, nodes, 100*time.Millisecond)
+	defer p2ptest.StopNodes(t, nodes, cancel, 100*time.Millisecond)
+
+	pInfo, err := utils.PeerAddressInfo(otherId)
+	require.NoError(t, err)
+	thisNode.Host().Peerstore().AddAddrs(pInfo.ID, pInfo.Addrs, peerstore.AddressTTL)
+
+	streamCount := 10
+	for i := 0; i < streamCount; i++ {
+		s, err := thisNode.CreateStream(ctx, pInfo.ID)
+		require.NoError(t, err)
+		require.NotNil(t, s)
+		require.Equal(t, protocols.FlowGzipProtocolId(sporkId), s.Protocol())
+	}
+
+	require.Equal(t, streamCount, p2putils.CountStream(thisNode.Host(), otherNode.Host().ID(), protocols.FlowGzipProtocolId(sporkId), network.DirOutbound))
+	require.Equal(t, 0, p2putils.CountStream(thisNode.Host(), otherNode.Host().ID(), protocols.FlowZstdProtocolId(sporkId), network.DirOutbound))
+	require.Equal(t, 0, p2putils.CountStream(thisNode.Host(), otherNode.Host().ID(), protocols.FlowProtocolID(sporkId), network.DirOutbound))
+}
+
+// TestUnicastOverStream_WithZstdStreamCompression checks two nodes can send and receive unicast messages on zstd compressed streams
+// when both nodes have zstd stream compression enabled.
+func TestUnicastOverStream_WithZstdStreamCompression(t *testing.T) {
+	testUnicastOverStream(t, p2ptest.WithPreferredUnicasts([]protocols.ProtocolName{protocols.ZstdCompressionUnicast}))
+}
+
 // TestCreateStreamTimeoutWithUnresponsiveNode tests that the CreateStream call does not block longer than the
 // timeout interval
 func TestCreateStreamTimeoutWithUnresponsiveNode(t *testing.T) {
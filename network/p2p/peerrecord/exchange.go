@@ -0,0 +1,361 @@
+package peerrecord
+
+import (
+	"context"
+	"fmt"
+	"io"
+	mrand "math/rand"
+	"time"
+
+	"github.com/hashicorp/go-multierror"
+	libp2pnet "github.com/libp2p/go-libp2p/core/network"
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/libp2p/go-libp2p/core/protocol"
+	"github.com/rs/zerolog"
+
+	"github.com/onflow/flow-go/crypto"
+	"github.com/onflow/flow-go/model/encoding/cbor"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/module/component"
+	"github.com/onflow/flow-go/module/irrecoverable"
+	"github.com/onflow/flow-go/network/p2p"
+	"github.com/onflow/flow-go/network/p2p/unicast/protocols"
+	"github.com/onflow/flow-go/network/p2p/utils"
+)
+
+const (
+	// DefaultRecordTTL is the default validity of the records published by the staked access nodes.
+	DefaultRecordTTL = time.Hour
+	// DefaultExchangeInterval is the default interval between two exchanges of records with the peers.
+	DefaultExchangeInterval = time.Minute
+	// DefaultUpstreams is the default number of verified staked access nodes an observer keeps connections with.
+	DefaultUpstreams = 3
+)
+
+const (
+	// exchangeTimeout is the timeout of an exchange of records with a peer.
+	exchangeTimeout = 10 * time.Second
+	// maxExchangePeers is the maximum number of peers records are requested from in an exchange round.
+	maxExchangePeers = 8
+	// maxRecordsPerResponse is the maximum number of records sent in response to a request.
+	maxRecordsPerResponse = 1000
+	// maxResponseSize is the maximum size of a response read from a peer.
+	maxResponseSize = 1 << 20
+)
+
+// Exchange exchanges the signed peer records of the staked access nodes with the peers of the node on the public
+// network. Each node serves the valid records of its store to its peers, and periodically requests the records of a
+// sample of its peers, which are verified before being stored.
+//
+// A staked access node publishes its own record (see WithLocalRecord), which it renews before expiry.
+// An observer keeps connections with a number of verified staked access nodes (see WithUpstreams), and removes the
+// peers which are neither verified nor allowed from the routing table of its DHT, so that it can't be eclipsed by
+// unverified DHT peers.
+type Exchange struct {
+	component.Component
+	log        zerolog.Logger
+	node       p2p.LibP2PNode
+	protocolID protocol.ID
+	store      *Store
+	codec      *cbor.Codec
+	interval   time.Duration
+
+	// local record, only set for staked access nodes publishing their record
+	nodeID     flow.Identifier
+	address    string
+	networkKey crypto.PrivateKey
+	ttl        time.Duration
+
+	// upstreams, only set for observers
+	upstreams int
+	allowed   func(peer.ID) bool
+}
+
+// ExchangeOption configures the exchange.
+type ExchangeOption func(*Exchange)
+
+// WithLocalRecord configures the exchange to publish the record of the node with its public network address, signed
+// with its networking key and valid for the ttl.
+func WithLocalRecord(nodeID flow.Identifier, address string, networkKey crypto.PrivateKey, ttl time.Duration) ExchangeOption {
+	return func(e *Exchange) {
+		e.nodeID = nodeID
+		e.address = address
+		e.networkKey = networkKey
+		e.ttl = ttl
+	}
+}
+
+// WithUpstreams configures the exchange to keep connections with the given number of verified staked access nodes,
+// and to restrict the routing table of the DHT to the peers passing the filter.
+func WithUpstreams(upstreams int, allowed func(peer.ID) bool) ExchangeOption {
+	return func(e *Exchange) {
+		e.upstreams = upstreams
+		e.allowed = allowed
+	}
+}
+
+// NewExchange creates an exchange of the records of the store with the peers of the libp2p node, running an exchange
+// round at each interval.
+// No errors are expected during normal operation.
+func NewExchange(
+	log zerolog.Logger,
+	node p2p.LibP2PNode,
+	sporkID flow.Identifier,
+	store *Store,
+	interval time.Duration,
+	opts ...ExchangeOption,
+) (*Exchange, error) {
+	e := &Exchange{
+		log:        log.With().Str("component", "peer_record_exchange").Logger(),
+		node:       node,
+		protocolID: protocols.PeerRecordsProtocolID(sporkID),
+		store:      store,
+		codec:      cbor.NewCodec(),
+		interval:   interval,
+	}
+	for _, opt := range opts {
+		opt(e)
+	}
+	if e.networkKey != nil {
+		err := validateAddress(e.address)
+		if err != nil {
+			return nil, fmt.Errorf("invalid public network address: %w", err)
+		}
+		if e.ttl <= 0 || e.ttl > MaxRecordTTL {
+			return nil, fmt.Errorf("record ttl must be positive and at most %v, got %v", MaxRecordTTL, e.ttl)
+		}
+	}
+
+	builder := component.NewComponentManagerBuilder().
+		AddWorker(func(ctx irrecoverable.SignalerContext, ready component.ReadyFunc) {
+			e.node.Host().SetStreamHandler(e.protocolID, e.handleStream)
+			ready()
+			<-ctx.Done()
+			e.node.Host().RemoveStreamHandler(e.protocolID)
+		}).
+		AddWorker(func(ctx irrecoverable.SignalerContext, ready component.ReadyFunc) {
+			ready()
+			e.exchangeLoop(ctx)
+		})
+	if e.networkKey != nil {
+		builder.AddWorker(func(ctx irrecoverable.SignalerContext, ready component.ReadyFunc) {
+			e.publish()
+			ready()
+			e.publishLoop(ctx)
+		})
+	}
+	e.Component = builder.Build()
+
+	return e, nil
+}
+
+// publishLoop renews the local record halfway through its validity.
+func (e *Exchange) publishLoop(ctx irrecoverable.SignalerContext) {
+	ticker := time.NewTicker(e.ttl / 2)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			e.publish()
+		}
+	}
+}
+
+// publish signs a new local record, which supersedes the previous one, and stores it to be served to the peers.
+func (e *Exchange) publish() {
+	record, err := NewSignedPeerRecord(e.nodeID, e.address, e.networkKey, time.Now(), e.ttl)
+	if err != nil {
+		e.log.Error().Err(err).Msg("could not sign local peer record")
+		return
+	}
+	_, err = e.store.Add(record)
+	if err != nil {
+		// the node is not a staked access node of the identity table, e.g., it was ejected
+		e.log.Error().Err(err).Msg("could not publish local peer record")
+		return
+	}
+	e.log.Info().
+		Str("address", record.Address).
+		Time("expiry", record.ExpiresAt()).
+		Msg("local peer record published")
+}
+
+// exchangeLoop runs an exchange round at each interval.
+func (e *Exchange) exchangeLoop(ctx irrecoverable.SignalerContext) {
+	ticker := time.NewTicker(e.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			e.exchangeRound(ctx)
+		}
+	}
+}
+
+// exchangeRound requests the records of a sample of the peers, prunes the records which are not valid anymore, and
+// for observers, updates the routing table and the upstream connections.
+func (e *Exchange) exchangeRound(ctx context.Context) {
+	peers := e.node.Host().Network().Peers()
+	mrand.Shuffle(len(peers), func(i, j int) {
+		peers[i], peers[j] = peers[j], peers[i]
+	})
+	if len(peers) > maxExchangePeers {
+		peers = peers[:maxExchangePeers]
+	}
+
+	added := 0
+	for _, pid := range peers {
+		records, err := e.fetch(ctx, pid)
+		if err != nil {
+			// the peer may not support the exchange of records
+			e.log.Debug().Err(err).Str("peer_id", pid.String()).Msg("could not fetch peer records")
+			continue
+		}
+		for _, record := range records {
+			ok, err := e.store.Add(record)
+			if IsInvalidRecordError(err) {
+				e.log.Warn().Err(err).Str("peer_id", pid.String()).Msg("received invalid peer record")
+				continue
+			}
+			if err != nil {
+				e.log.Error().Err(err).Str("peer_id", pid.String()).Msg("could not add peer record")
+				continue
+			}
+			if ok {
+				added++
+			}
+		}
+	}
+	pruned := e.store.Prune()
+
+	e.log.Debug().
+		Int("peers", len(peers)).
+		Int("added", added).
+		Int("pruned", pruned).
+		Msg("peer record exchange round completed")
+
+	if e.allowed != nil {
+		e.pruneRoutingTable()
+		e.connectUpstreams(ctx)
+	}
+}
+
+// fetch requests the records of the peer.
+// No errors are expected during normal operation, the errors are due to the peer or the connection to the peer.
+func (e *Exchange) fetch(ctx context.Context, pid peer.ID) ([]*SignedPeerRecord, error) {
+	ctx, cancel := context.WithTimeout(ctx, exchangeTimeout)
+	defer cancel()
+
+	s, err := e.node.Host().NewStream(ctx, pid, e.protocolID)
+	if err != nil {
+		return nil, fmt.Errorf("could not create stream: %w", err)
+	}
+	defer func() {
+		_ = s.Close()
+	}()
+	err = s.SetDeadline(time.Now().Add(exchangeTimeout))
+	if err != nil {
+		return nil, fmt.Errorf("could not set stream deadline: %w", err)
+	}
+
+	var records []*SignedPeerRecord
+	err = e.codec.NewDecoder(io.LimitReader(s, maxResponseSize)).Decode(&records)
+	if err != nil {
+		return nil, fmt.Errorf("could not decode peer records: %w", err)
+	}
+	return records, nil
+}
+
+// handleStream serves the valid records of the store.
+func (e *Exchange) handleStream(s libp2pnet.Stream) {
+	lg := e.log.With().Str("peer_id", s.Conn().RemotePeer().String()).Logger()
+
+	err := s.SetDeadline(time.Now().Add(exchangeTimeout))
+	if err != nil {
+		lg.Error().Err(err).Msg("could not set stream deadline")
+		_ = s.Reset()
+		return
+	}
+
+	records := e.store.Records()
+	if len(records) > maxRecordsPerResponse {
+		records = records[:maxRecordsPerResponse]
+	}
+	err = e.codec.NewEncoder(s).Encode(records)
+	if err != nil {
+		lg.Debug().Err(err).Msg("could not send peer records")
+		_ = s.Reset()
+		return
+	}
+	err = s.Close()
+	if err != nil {
+		lg.Debug().Err(err).Msg("could not close stream")
+	}
+}
+
+// pruneRoutingTable removes the peers which are not allowed from the routing table of the DHT.
+func (e *Exchange) pruneRoutingTable() {
+	rt := e.node.RoutingTable()
+	if rt == nil {
+		return
+	}
+	for _, pid := range rt.ListPeers() {
+		if !e.allowed(pid) {
+			rt.RemovePeer(pid)
+			e.log.Debug().Str("peer_id", pid.String()).Msg("unverified peer removed from routing table")
+		}
+	}
+}
+
+// connectUpstreams connects to verified staked access nodes until the node is connected to the configured number of
+// upstreams, or there is no more verified staked access node to connect to.
+func (e *Exchange) connectUpstreams(ctx context.Context) {
+	identities := e.store.Identities()
+	mrand.Shuffle(len(identities), func(i, j int) {
+		identities[i], identities[j] = identities[j], identities[i]
+	})
+
+	connected := 0
+	var candidates []peer.AddrInfo
+	var errs *multierror.Error
+	for _, identity := range identities {
+		pi, err := utils.PeerAddressInfo(*identity)
+		if err != nil {
+			errs = multierror.Append(errs, fmt.Errorf("could not get peer address info of node %v: %w", identity.NodeID, err))
+			continue
+		}
+		if e.node.Host().Network().Connectedness(pi.ID) == libp2pnet.Connected {
+			connected++
+			continue
+		}
+		candidates = append(candidates, pi)
+	}
+
+	for _, pi := range candidates {
+		if connected >= e.upstreams {
+			break
+		}
+		err := e.node.AddPeer(ctx, pi)
+		if err != nil {
+			errs = multierror.Append(errs, fmt.Errorf("could not connect to peer %v: %w", pi.ID, err))
+			continue
+		}
+		connected++
+	}
+
+	if errs.ErrorOrNil() != nil {
+		e.log.Warn().Err(errs).Msg("could not connect to some verified upstreams")
+	}
+	if connected < e.upstreams {
+		e.log.Warn().
+			Int("connected", connected).
+			Int("upstreams", e.upstreams).
+			Msg("not enough verified upstreams")
+	}
+}
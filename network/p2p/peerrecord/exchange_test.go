@@ -0,0 +1,79 @@
+package peerrecord_test
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	libp2pnet "github.com/libp2p/go-libp2p/core/network"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/module/id"
+	"github.com/onflow/flow-go/module/irrecoverable"
+	"github.com/onflow/flow-go/network/p2p"
+	"github.com/onflow/flow-go/network/p2p/peerrecord"
+	p2ptest "github.com/onflow/flow-go/network/p2p/test"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+// TestExchange evaluates that the records published by the staked access nodes propagate through the exchanges of
+// records, and that an observer only connected to a bootstrap access node discovers and connects to the other staked
+// access node from its verified record.
+func TestExchange(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	signalerCtx := irrecoverable.NewMockSignalerContext(t, ctx)
+	sporkID := unittest.IdentifierFixture()
+
+	an1Key := p2ptest.NetworkingKeyFixtures(t)
+	an1, an1ID := p2ptest.NodeFixture(t, sporkID, t.Name(), p2ptest.WithRole(flow.RoleAccess), p2ptest.WithNetworkingPrivateKey(an1Key))
+	an2Key := p2ptest.NetworkingKeyFixtures(t)
+	an2, an2ID := p2ptest.NodeFixture(t, sporkID, t.Name(), p2ptest.WithRole(flow.RoleAccess), p2ptest.WithNetworkingPrivateKey(an2Key))
+	observer, _ := p2ptest.NodeFixture(t, sporkID, t.Name(), p2ptest.WithRole(flow.RoleAccess))
+	nodes := []p2p.LibP2PNode{an1, an2, observer}
+	p2ptest.StartNodes(t, signalerCtx, nodes, 100*time.Millisecond)
+	defer p2ptest.StopNodes(t, nodes, cancel, 100*time.Millisecond)
+
+	// the identity table only contains the staked access nodes
+	idProvider := id.NewFixedIdentityProvider(flow.IdentityList{&an1ID, &an2ID})
+
+	// the observer is only connected to its bootstrap access node, which is connected to the other access node
+	require.NoError(t, observer.Host().Connect(ctx, an1.Host().Peerstore().PeerInfo(an1.Host().ID())))
+	require.NoError(t, an1.Host().Connect(ctx, an2.Host().Peerstore().PeerInfo(an2.Host().ID())))
+
+	an1Exchange, err := peerrecord.NewExchange(unittest.Logger(), an1, sporkID, peerrecord.NewStore(idProvider), 50*time.Millisecond,
+		peerrecord.WithLocalRecord(an1ID.NodeID, publicAddress(t, an1), an1Key, time.Hour))
+	require.NoError(t, err)
+	an2Exchange, err := peerrecord.NewExchange(unittest.Logger(), an2, sporkID, peerrecord.NewStore(idProvider), 50*time.Millisecond,
+		peerrecord.WithLocalRecord(an2ID.NodeID, publicAddress(t, an2), an2Key, time.Hour))
+	require.NoError(t, err)
+	observerStore := peerrecord.NewStore(idProvider)
+	observerExchange, err := peerrecord.NewExchange(unittest.Logger(), observer, sporkID, observerStore, 50*time.Millisecond,
+		peerrecord.WithUpstreams(2, peerrecord.VerifiedPeerFilter(observerStore, an1.Host().ID())))
+	require.NoError(t, err)
+
+	exchanges := []*peerrecord.Exchange{an1Exchange, an2Exchange, observerExchange}
+	for _, exchange := range exchanges {
+		exchange.Start(signalerCtx)
+	}
+	unittest.RequireComponentsReadyBefore(t, time.Second, an1Exchange, an2Exchange, observerExchange)
+
+	require.Eventually(t, func() bool {
+		return len(observerStore.Records()) == 2 &&
+			observer.Host().Network().Connectedness(an2.Host().ID()) == libp2pnet.Connected
+	}, 5*time.Second, 50*time.Millisecond)
+	require.True(t, observerStore.Verified(an1.Host().ID()))
+	require.True(t, observerStore.Verified(an2.Host().ID()))
+	require.False(t, observerStore.Verified(observer.Host().ID()))
+
+	cancel()
+	unittest.RequireComponentsDoneBefore(t, time.Second, an1Exchange, an2Exchange, observerExchange)
+}
+
+// publicAddress returns the address the node listens on, in host:port format.
+func publicAddress(t *testing.T, node p2p.LibP2PNode) string {
+	ip, port, err := node.GetIPPort()
+	require.NoError(t, err)
+	return net.JoinHostPort(ip, port)
+}
@@ -0,0 +1,98 @@
+package peerrecord
+
+import (
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/onflow/flow-go/crypto"
+	"github.com/onflow/flow-go/crypto/hash"
+	"github.com/onflow/flow-go/model/fingerprint"
+	"github.com/onflow/flow-go/model/flow"
+)
+
+// recordTag is the domain separation tag of the signatures of the peer records, which prevents a signature of a peer
+// record from being valid for any other message signed with the networking key of the node.
+const recordTag = "FLOW-Peer_Record-V00-"
+
+// PeerRecord is the peer information a staked access node publishes for the observers on the public network.
+type PeerRecord struct {
+	// NodeID is the ID of the staked access node.
+	NodeID flow.Identifier
+	// Address is the address of the node on the public network, in host:port format.
+	Address string
+	// Sequence orders the records of the node: a record supersedes all the records of the node with a lower sequence.
+	// The node uses the time of signing of the record in nanoseconds, which keeps the sequence increasing across
+	// restarts without persisting it.
+	Sequence uint64
+	// Expiry is the unix time in milliseconds after which the record is not valid anymore.
+	Expiry uint64
+}
+
+// SignedPeerRecord is a peer record signed with the networking key of the node.
+type SignedPeerRecord struct {
+	PeerRecord
+	Signature crypto.Signature
+}
+
+// NewSignedPeerRecord creates a peer record of the node with the given public network address, valid for the ttl
+// from the given time, and signs it with the networking key of the node.
+// No errors are expected during normal operation.
+func NewSignedPeerRecord(nodeID flow.Identifier, address string, networkKey crypto.PrivateKey, now time.Time, ttl time.Duration) (*SignedPeerRecord, error) {
+	record := PeerRecord{
+		NodeID:   nodeID,
+		Address:  address,
+		Sequence: uint64(now.UnixNano()),
+		Expiry:   uint64(now.Add(ttl).UnixMilli()),
+	}
+	sig, err := networkKey.Sign(record.message(), newHasher())
+	if err != nil {
+		return nil, fmt.Errorf("could not sign peer record: %w", err)
+	}
+	return &SignedPeerRecord{
+		PeerRecord: record,
+		Signature:  sig,
+	}, nil
+}
+
+// ExpiresAt returns the time after which the record is not valid anymore.
+func (r *PeerRecord) ExpiresAt() time.Time {
+	return time.UnixMilli(int64(r.Expiry))
+}
+
+// Expired returns true if the record is not valid anymore at the given time.
+func (r *PeerRecord) Expired(now time.Time) bool {
+	return !now.Before(r.ExpiresAt())
+}
+
+// Verify verifies the signature of the record against the networking key of the node.
+// No errors are expected during normal operation.
+func (r *SignedPeerRecord) Verify(networkPubKey crypto.PublicKey) (bool, error) {
+	valid, err := networkPubKey.Verify(r.Signature, r.PeerRecord.message(), newHasher())
+	if err != nil {
+		return false, fmt.Errorf("could not verify peer record signature: %w", err)
+	}
+	return valid, nil
+}
+
+// message returns the signed message of the record, i.e., its canonical encoding prefixed with the domain tag.
+func (r *PeerRecord) message() []byte {
+	return append([]byte(recordTag), fingerprint.Fingerprint(r)...)
+}
+
+// validateAddress checks that the address is in host:port format.
+func validateAddress(address string) error {
+	host, port, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	if host == "" || port == "" {
+		return fmt.Errorf("missing host or port in address %s", address)
+	}
+	return nil
+}
+
+// newHasher returns the hasher of the peer record signatures.
+func newHasher() hash.Hasher {
+	return hash.NewSHA3_256()
+}
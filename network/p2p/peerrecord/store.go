@@ -0,0 +1,213 @@
+package peerrecord
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/libp2p/go-libp2p/core/peer"
+
+	"github.com/onflow/flow-go/crypto"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/model/flow/filter"
+	"github.com/onflow/flow-go/module"
+)
+
+// MaxRecordTTL is the maximum validity of the peer records. Records expiring later are rejected, so that a node can't
+// publish records outliving its ejection, or the rotation of its address, for long.
+const MaxRecordTTL = 24 * time.Hour
+
+// InvalidRecordError indicates that a peer record is not valid, i.e., it is expired, it is not the record of a staked
+// access node, or its signature does not verify against the networking key of the node in the identity table.
+type InvalidRecordError struct {
+	Err error
+}
+
+func (err InvalidRecordError) Error() string {
+	return fmt.Sprintf("invalid peer record: %s", err.Err.Error())
+}
+
+func (err InvalidRecordError) Unwrap() error {
+	return err.Err
+}
+
+func NewInvalidRecordErrorf(msg string, args ...interface{}) InvalidRecordError {
+	return InvalidRecordError{
+		Err: fmt.Errorf(msg, args...),
+	}
+}
+
+func IsInvalidRecordError(err error) bool {
+	var errInvalidRecord InvalidRecordError
+	return errors.As(err, &errInvalidRecord)
+}
+
+// storedRecord is a verified record, along with the networking key it was verified against.
+type storedRecord struct {
+	record        *SignedPeerRecord
+	networkPubKey crypto.PublicKey
+}
+
+// Store stores the latest valid peer record of each staked access node. The records are verified against the identity
+// table of the identity provider when added, and re-validated against it when read: the record of a node which left
+// the identity table, was ejected, or whose networking key rotated is not valid anymore.
+type Store struct {
+	idProvider module.IdentityProvider
+	now        func() time.Time
+
+	mu      sync.RWMutex
+	records map[flow.Identifier]storedRecord
+}
+
+// NewStore creates a peer record store verifying the records against the identities of the identity provider.
+func NewStore(idProvider module.IdentityProvider) *Store {
+	return &Store{
+		idProvider: idProvider,
+		now:        time.Now,
+		records:    make(map[flow.Identifier]storedRecord),
+	}
+}
+
+// Add verifies the record and stores it, unless the store already holds a record of the node with the same or a
+// higher sequence. It returns true if the record was stored.
+// Expected errors during normal operation:
+//   - InvalidRecordError if the record is not valid.
+func (s *Store) Add(record *SignedPeerRecord) (bool, error) {
+	now := s.now()
+	if record.Expired(now) {
+		return false, NewInvalidRecordErrorf("record of node %v expired at %v", record.NodeID, record.ExpiresAt())
+	}
+	if record.ExpiresAt().After(now.Add(MaxRecordTTL)) {
+		return false, NewInvalidRecordErrorf("record of node %v expires at %v, after the maximum record ttl", record.NodeID, record.ExpiresAt())
+	}
+	err := validateAddress(record.Address)
+	if err != nil {
+		return false, NewInvalidRecordErrorf("invalid address of node %v: %w", record.NodeID, err)
+	}
+	identity, err := s.stakedAccessNode(record.NodeID)
+	if err != nil {
+		return false, err
+	}
+	valid, err := record.Verify(identity.NetworkPubKey)
+	if err != nil {
+		return false, fmt.Errorf("could not verify record of node %v: %w", record.NodeID, err)
+	}
+	if !valid {
+		return false, NewInvalidRecordErrorf("invalid signature of record of node %v", record.NodeID)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	stored, ok := s.records[record.NodeID]
+	if ok && stored.networkPubKey.Equals(identity.NetworkPubKey) && stored.record.Sequence >= record.Sequence {
+		return false, nil
+	}
+	s.records[record.NodeID] = storedRecord{
+		record:        record,
+		networkPubKey: identity.NetworkPubKey,
+	}
+	return true, nil
+}
+
+// Records returns the valid records of the store.
+func (s *Store) Records() []*SignedPeerRecord {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	now := s.now()
+	records := make([]*SignedPeerRecord, 0, len(s.records))
+	for _, stored := range s.records {
+		if s.valid(stored, now) {
+			records = append(records, stored.record)
+		}
+	}
+	return records
+}
+
+// Identities returns the identities of the nodes with a valid record, with their address on the public network.
+func (s *Store) Identities() flow.IdentityList {
+	records := s.Records()
+	identities := make(flow.IdentityList, 0, len(records))
+	for _, record := range records {
+		identity, ok := s.idProvider.ByNodeID(record.NodeID)
+		if !ok {
+			continue
+		}
+		public := *identity
+		public.Address = record.Address
+		identities = append(identities, &public)
+	}
+	return identities
+}
+
+// Verified returns true if the peer is a staked access node with a valid record.
+func (s *Store) Verified(pid peer.ID) bool {
+	identity, ok := s.idProvider.ByPeerID(pid)
+	if !ok {
+		return false
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	stored, ok := s.records[identity.NodeID]
+	return ok && s.valid(stored, s.now())
+}
+
+// Prune removes the records which are not valid anymore, and returns the number of removed records.
+func (s *Store) Prune() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	pruned := 0
+	for nodeID, stored := range s.records {
+		if !s.valid(stored, now) {
+			delete(s.records, nodeID)
+			pruned++
+		}
+	}
+	return pruned
+}
+
+// valid returns true if the stored record is not expired, and the node is still a staked access node with the
+// networking key the record was verified against.
+func (s *Store) valid(stored storedRecord, now time.Time) bool {
+	if stored.record.Expired(now) {
+		return false
+	}
+	identity, err := s.stakedAccessNode(stored.record.NodeID)
+	if err != nil {
+		return false
+	}
+	return identity.NetworkPubKey.Equals(stored.networkPubKey)
+}
+
+// stakedAccessNode returns the identity of the node if it is a staked access node.
+// Expected errors during normal operation:
+//   - InvalidRecordError if the node is not a staked access node.
+func (s *Store) stakedAccessNode(nodeID flow.Identifier) (*flow.Identity, error) {
+	identity, ok := s.idProvider.ByNodeID(nodeID)
+	if !ok {
+		return nil, NewInvalidRecordErrorf("node %v is not part of the identity table", nodeID)
+	}
+	if !filter.And(filter.HasRole(flow.RoleAccess), filter.HasWeight(true), filter.Not(filter.Ejected))(identity) {
+		return nil, NewInvalidRecordErrorf("node %v is not a staked access node", nodeID)
+	}
+	return identity, nil
+}
+
+// VerifiedPeerFilter returns a filter allowing the verified peers of the store, and the trusted peers, e.g., the
+// bootstrap peers of the node.
+func VerifiedPeerFilter(store *Store, trusted ...peer.ID) func(peer.ID) bool {
+	trustedPeers := make(map[peer.ID]struct{}, len(trusted))
+	for _, pid := range trusted {
+		trustedPeers[pid] = struct{}{}
+	}
+	return func(pid peer.ID) bool {
+		if _, ok := trustedPeers[pid]; ok {
+			return true
+		}
+		return store.Verified(pid)
+	}
+}
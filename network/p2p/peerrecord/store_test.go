@@ -0,0 +1,166 @@
+package peerrecord_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/crypto"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/module/id"
+	"github.com/onflow/flow-go/network/p2p/keyutils"
+	"github.com/onflow/flow-go/network/p2p/peerrecord"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+// accessNodeFixture returns a staked access node identity along with its networking key.
+func accessNodeFixture() (*flow.Identity, crypto.PrivateKey) {
+	key := unittest.NetworkingPrivKeyFixture()
+	identity := unittest.IdentityFixture(unittest.WithRole(flow.RoleAccess), unittest.WithNetworkingKey(key.PublicKey()))
+	return identity, key
+}
+
+// TestSignedPeerRecord_Verify evaluates that the signature of a peer record only verifies against the networking key
+// of the node, and is bound to the content of the record.
+func TestSignedPeerRecord_Verify(t *testing.T) {
+	identity, key := accessNodeFixture()
+	record, err := peerrecord.NewSignedPeerRecord(identity.NodeID, "access-001.flow.org:3570", key, time.Now(), time.Hour)
+	require.NoError(t, err)
+
+	valid, err := record.Verify(key.PublicKey())
+	require.NoError(t, err)
+	require.True(t, valid)
+
+	valid, err = record.Verify(unittest.NetworkingPrivKeyFixture().PublicKey())
+	require.NoError(t, err)
+	require.False(t, valid)
+
+	tampered := *record
+	tampered.Address = "attacker.org:3570"
+	valid, err = tampered.Verify(key.PublicKey())
+	require.NoError(t, err)
+	require.False(t, valid)
+}
+
+// TestStore_Add evaluates that the store only accepts valid records of staked access nodes, and keeps the record with
+// the highest sequence of each node.
+func TestStore_Add(t *testing.T) {
+	identity, key := accessNodeFixture()
+	other, otherKey := accessNodeFixture()
+	collector := unittest.IdentityFixture(unittest.WithRole(flow.RoleCollection))
+	collectorKey := unittest.NetworkingPrivKeyFixture()
+	collector.NetworkPubKey = collectorKey.PublicKey()
+	store := peerrecord.NewStore(id.NewFixedIdentityProvider(flow.IdentityList{identity, other, collector}))
+	now := time.Now()
+
+	first, err := peerrecord.NewSignedPeerRecord(identity.NodeID, "access-001.flow.org:3570", key, now, time.Hour)
+	require.NoError(t, err)
+	added, err := store.Add(first)
+	require.NoError(t, err)
+	require.True(t, added)
+	require.Equal(t, []*peerrecord.SignedPeerRecord{first}, store.Records())
+
+	pid, err := keyutils.PeerIDFromFlowPublicKey(identity.NetworkPubKey)
+	require.NoError(t, err)
+	require.True(t, store.Verified(pid))
+	identities := store.Identities()
+	require.Len(t, identities, 1)
+	require.Equal(t, identity.NodeID, identities[0].NodeID)
+	require.Equal(t, "access-001.flow.org:3570", identities[0].Address)
+
+	// a rotated record supersedes the previous one, but not the other way around
+	rotated, err := peerrecord.NewSignedPeerRecord(identity.NodeID, "access-002.flow.org:3570", key, now.Add(time.Minute), time.Hour)
+	require.NoError(t, err)
+	added, err = store.Add(rotated)
+	require.NoError(t, err)
+	require.True(t, added)
+	added, err = store.Add(first)
+	require.NoError(t, err)
+	require.False(t, added)
+	require.Equal(t, []*peerrecord.SignedPeerRecord{rotated}, store.Records())
+
+	t.Run("expired record", func(t *testing.T) {
+		record, err := peerrecord.NewSignedPeerRecord(other.NodeID, "access-003.flow.org:3570", otherKey, now.Add(-2*time.Hour), time.Hour)
+		require.NoError(t, err)
+		_, err = store.Add(record)
+		require.True(t, peerrecord.IsInvalidRecordError(err))
+	})
+
+	t.Run("record outliving the maximum ttl", func(t *testing.T) {
+		record, err := peerrecord.NewSignedPeerRecord(other.NodeID, "access-003.flow.org:3570", otherKey, now, 2*peerrecord.MaxRecordTTL)
+		require.NoError(t, err)
+		_, err = store.Add(record)
+		require.True(t, peerrecord.IsInvalidRecordError(err))
+	})
+
+	t.Run("invalid address", func(t *testing.T) {
+		record, err := peerrecord.NewSignedPeerRecord(other.NodeID, "access-003.flow.org", otherKey, now, time.Hour)
+		require.NoError(t, err)
+		_, err = store.Add(record)
+		require.True(t, peerrecord.IsInvalidRecordError(err))
+	})
+
+	t.Run("record signed by another node", func(t *testing.T) {
+		record, err := peerrecord.NewSignedPeerRecord(other.NodeID, "access-003.flow.org:3570", key, now, time.Hour)
+		require.NoError(t, err)
+		_, err = store.Add(record)
+		require.True(t, peerrecord.IsInvalidRecordError(err))
+	})
+
+	t.Run("unknown node", func(t *testing.T) {
+		unknown, unknownKey := accessNodeFixture()
+		record, err := peerrecord.NewSignedPeerRecord(unknown.NodeID, "access-003.flow.org:3570", unknownKey, now, time.Hour)
+		require.NoError(t, err)
+		_, err = store.Add(record)
+		require.True(t, peerrecord.IsInvalidRecordError(err))
+	})
+
+	t.Run("not an access node", func(t *testing.T) {
+		record, err := peerrecord.NewSignedPeerRecord(collector.NodeID, "collection-001.flow.org:3570", collectorKey, now, time.Hour)
+		require.NoError(t, err)
+		_, err = store.Add(record)
+		require.True(t, peerrecord.IsInvalidRecordError(err))
+	})
+
+	require.Equal(t, []*peerrecord.SignedPeerRecord{rotated}, store.Records())
+}
+
+// TestStore_Revalidation evaluates that the records of the nodes which are not staked access nodes anymore, or whose
+// networking key rotated, are not valid anymore, and that the expired records are pruned.
+func TestStore_Revalidation(t *testing.T) {
+	ejected, ejectedKey := accessNodeFixture()
+	rotated, rotatedKey := accessNodeFixture()
+	expiring, expiringKey := accessNodeFixture()
+	store := peerrecord.NewStore(id.NewFixedIdentityProvider(flow.IdentityList{ejected, rotated, expiring}))
+	now := time.Now()
+
+	for _, node := range []struct {
+		identity *flow.Identity
+		key      crypto.PrivateKey
+		ttl      time.Duration
+	}{
+		{ejected, ejectedKey, time.Hour},
+		{rotated, rotatedKey, time.Hour},
+		{expiring, expiringKey, 100 * time.Millisecond},
+	} {
+		record, err := peerrecord.NewSignedPeerRecord(node.identity.NodeID, "access.flow.org:3570", node.key, now, node.ttl)
+		require.NoError(t, err)
+		added, err := store.Add(record)
+		require.NoError(t, err)
+		require.True(t, added)
+	}
+	require.Len(t, store.Records(), 3)
+
+	ejected.Ejected = true
+	ejectedPID, err := keyutils.PeerIDFromFlowPublicKey(ejected.NetworkPubKey)
+	require.NoError(t, err)
+	require.False(t, store.Verified(ejectedPID))
+
+	rotated.NetworkPubKey = unittest.NetworkingPrivKeyFixture().PublicKey()
+	require.Eventually(t, func() bool {
+		return len(store.Records()) == 0
+	}, time.Second, 10*time.Millisecond)
+	require.Equal(t, 3, store.Prune())
+	require.Equal(t, 0, store.Prune())
+}
@@ -46,6 +46,11 @@ type PubSubAdapter interface {
 	// For example, if current peer has subscribed to topics A and B, then ListPeers only return
 	// subscribed peers for topics A and B, and querying for topic C will return an empty list.
 	ListPeers(topic string) []peer.ID
+
+	// GetLocalMeshPeers returns the local mesh peers of the current peer for the given topic, as tracked
+	// by the pubsub tracer. If no tracer is configured, or the current peer has no mesh for the topic,
+	// an empty list is returned.
+	GetLocalMeshPeers(topic string) []peer.ID
 }
 
 // PubSubAdapterConfig abstracts the configuration for the underlying pubsub implementation.
@@ -54,14 +59,51 @@ type PubSubAdapterConfig interface {
 	WithSubscriptionFilter(SubscriptionFilter)
 	WithScoreOption(ScoreOptionBuilder)
 	WithMessageIdFunction(f func([]byte) string)
-	WithAppSpecificRpcInspector(f func(peer.ID, *pubsub.RPC) error)
 	WithTracer(t PubSubTracer)
 
+	// WithInspectorSuite sets the inspector suite of the underlying pubsub implementation, which inspects all the
+	// incoming RPCs before they are processed by the pubsub implementation.
+	WithInspectorSuite(GossipSubInspectorSuite)
+
 	// WithScoreTracer sets the tracer for the underlying pubsub score implementation.
 	// This is used to expose the local scoring table of the GossipSub node to its higher level components.
 	WithScoreTracer(tracer PeerScoreTracer)
 }
 
+// GossipSubRPCInspector is an application specific inspector of the incoming RPCs of the GossipSub router. It is invoked
+// for each incoming RPC before the RPC is processed by the router. Inspection is done synchronously on the receive path
+// of the RPC, hence the implementation must be concurrency safe and non-blocking.
+type GossipSubRPCInspector interface {
+	component.Component
+
+	// Name returns the name of the rpc inspector.
+	Name() string
+
+	// Inspect inspects an incoming RPC message. This callback func is invoked on every RPC message received before the
+	// message is processed by libp2p.
+	// If this func returns any error the RPC message will be dropped.
+	Inspect(peer.ID, *pubsub.RPC) error
+}
+
+// GossipSubInspectorSuite encapsulates the set of RPC inspectors of a GossipSub router, as well as the notification
+// distributor that reports the misbehaviour detected by the inspectors to its consumers.
+type GossipSubInspectorSuite interface {
+	component.Component
+
+	// InspectFunc returns the inspect function that is invoked by the GossipSub router on each incoming RPC.
+	// The RPC is dropped if any of the inspectors returns an error.
+	InspectFunc() func(peer.ID, *pubsub.RPC) error
+
+	// AddInvalidControlMessageConsumer adds a consumer to the notifications of invalid control messages detected by the
+	// inspectors of the suite.
+	AddInvalidControlMessageConsumer(GossipSubInvalidControlMessageNotificationConsumer)
+
+	// SetTopicOracle sets the topic oracle of the suite, i.e., a function that returns whether the local node
+	// is currently subscribed to a topic. The oracle is used to detect control messages on topics the node is not subscribed to.
+	// The topic oracle can be set only once; any attempt to set it again results in an error.
+	SetTopicOracle(topicOracle func(topic string) bool) error
+}
+
 // Topic is the abstraction of the underlying pubsub topic that is used by the Flow network.
 type Topic interface {
 	// String returns the topic name as a string.
@@ -118,6 +160,8 @@ type SubscriptionFilter interface {
 type PubSubTracer interface {
 	component.Component
 	pubsub.RawTracer
+	// GetMeshPeers returns the local mesh peers for the given topic.
+	GetMeshPeers(topic string) []peer.ID
 }
 
 // PeerScoreSnapshot is a snapshot of the overall peer score at a given time.
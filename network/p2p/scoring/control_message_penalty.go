@@ -0,0 +1,132 @@
+package scoring
+
+import (
+	"math"
+	"sync"
+	"time"
+
+	"github.com/libp2p/go-libp2p/core/peer"
+
+	"github.com/onflow/flow-go/network/p2p"
+)
+
+const (
+	// DefaultInvalidControlMessagePenalty is the penalty applied to the app specific score of a peer for each
+	// reported invalid control message (i.e., each rejected RPC) received from the peer.
+	// With the maximum reward of 100 for a well-behaved peer, a peer is penalized below zero after 11 rejected RPCs,
+	// and reaches the maximum penalty after 20 rejected RPCs within a short period of time.
+	DefaultInvalidControlMessagePenalty = -10
+
+	// DefaultInvalidControlMessagePenaltyHalfLife is the time after which the accumulated penalty of a peer
+	// for invalid control messages is halved, so that misbehaviour does not have a permanent effect on the score.
+	DefaultInvalidControlMessagePenaltyHalfLife = 10 * time.Minute
+
+	// DefaultInvalidControlMessagePenaltiesSizeLimit is the default maximum number of peers whose penalties are tracked.
+	// It is well above the number of peers a node is connected to, so that only an attacker cycling through many peer
+	// identities can reach it.
+	DefaultInvalidControlMessagePenaltiesSizeLimit = 10_000
+
+	// penaltyDecayToZero is the (absolute) value below which a decayed penalty is reset to zero.
+	penaltyDecayToZero = 0.1
+)
+
+// InvalidControlMessagePenalties keeps track of the penalties of peers that sent invalid control messages, as reported
+// by the GossipSub rpc inspectors. The penalty of a peer accumulates with each report, and decays exponentially
+// over time. It is applied on top of the app specific score of the peer.
+// The number of tracked peers is bounded; once the limit is reached, the smallest penalty is evicted to make room for a
+// new peer, so that the most misbehaving peers are kept.
+// InvalidControlMessagePenalties is concurrency safe.
+type InvalidControlMessagePenalties struct {
+	mu        sync.Mutex
+	penalties map[peer.ID]*decayingPenalty
+	penalty   float64       // the penalty applied for each reported invalid control message
+	halfLife  time.Duration // the half life of the accumulated penalty
+	sizeLimit int           // the maximum number of tracked peers
+	now       p2p.GetTimeNow
+}
+
+// decayingPenalty is the accumulated penalty of a peer, as of the last update.
+type decayingPenalty struct {
+	value   float64
+	updated time.Time
+}
+
+var _ p2p.GossipSubInvalidControlMessageNotificationConsumer = (*InvalidControlMessagePenalties)(nil)
+
+// NewInvalidControlMessagePenalties returns a new InvalidControlMessagePenalties, applying the given (negative) penalty
+// for each reported invalid control message, with the given half life of the accumulated penalty. The penalties of at
+// most sizeLimit peers are tracked.
+func NewInvalidControlMessagePenalties(penalty float64, halfLife time.Duration, sizeLimit int) *InvalidControlMessagePenalties {
+	return &InvalidControlMessagePenalties{
+		penalties: make(map[peer.ID]*decayingPenalty),
+		penalty:   penalty,
+		halfLife:  halfLife,
+		sizeLimit: sizeLimit,
+		now:       time.Now,
+	}
+}
+
+// SetTimeNowFunc overrides the default time.Now func with the GetTimeNow func provided.
+func (p *InvalidControlMessagePenalties) SetTimeNowFunc(now p2p.GetTimeNow) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.now = now
+}
+
+// OnInvalidControlMessageNotification penalizes the peer that sent the invalid control message.
+func (p *InvalidControlMessagePenalties) OnInvalidControlMessageNotification(notification *p2p.InvalidControlMessageNotification) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := p.now()
+	value := p.decayed(notification.PeerID, now) + p.penalty
+	if _, ok := p.penalties[notification.PeerID]; !ok && len(p.penalties) >= p.sizeLimit {
+		p.evict(now)
+	}
+	p.penalties[notification.PeerID] = &decayingPenalty{value: value, updated: now}
+}
+
+// Penalty returns the current (non-positive) penalty of the peer for invalid control messages.
+func (p *InvalidControlMessagePenalties) Penalty(pid peer.ID) float64 {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.decayed(pid, p.now())
+}
+
+// decayed returns the penalty of the peer decayed until the given time, and drops the penalty of the peer
+// once it has decayed to zero.
+// Not concurrency safe; the caller must hold the lock.
+func (p *InvalidControlMessagePenalties) decayed(pid peer.ID, now time.Time) float64 {
+	penalty, ok := p.penalties[pid]
+	if !ok {
+		return 0
+	}
+
+	elapsed := now.Sub(penalty.updated)
+	value := penalty.value
+	if elapsed > 0 {
+		value *= math.Pow(0.5, float64(elapsed)/float64(p.halfLife))
+	}
+	if math.Abs(value) < penaltyDecayToZero {
+		delete(p.penalties, pid)
+		return 0
+	}
+	return value
+}
+
+// evict drops all penalties that have decayed to zero. If none has, it drops the smallest penalty.
+// Not concurrency safe; the caller must hold the lock.
+func (p *InvalidControlMessagePenalties) evict(now time.Time) {
+	var smallest peer.ID
+	smallestValue := math.Inf(-1)
+	for pid := range p.penalties {
+		value := p.decayed(pid, now)
+		if value > smallestValue {
+			smallest, smallestValue = pid, value
+		}
+	}
+	if len(p.penalties) >= p.sizeLimit {
+		delete(p.penalties, smallest)
+	}
+}
@@ -0,0 +1,91 @@
+package scoring_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/network/internal/p2pfixtures"
+	"github.com/onflow/flow-go/network/p2p"
+	"github.com/onflow/flow-go/network/p2p/scoring"
+)
+
+// TestInvalidControlMessagePenalties_Accumulate checks that the penalty of a peer accumulates with each reported
+// invalid control message, and that other peers are not affected.
+func TestInvalidControlMessagePenalties_Accumulate(t *testing.T) {
+	penalties := scoring.NewInvalidControlMessagePenalties(scoring.DefaultInvalidControlMessagePenalty, time.Minute, scoring.DefaultInvalidControlMessagePenaltiesSizeLimit)
+	now := time.Now()
+	penalties.SetTimeNowFunc(func() time.Time { return now })
+
+	misbehaving := p2pfixtures.PeerIdFixture(t)
+	honest := p2pfixtures.PeerIdFixture(t)
+
+	for i := 1; i <= 3; i++ {
+		penalties.OnInvalidControlMessageNotification(p2p.NewInvalidControlMessageNotification(misbehaving, p2p.CtrlMsgGraft, 1, nil))
+		require.Equal(t, float64(i*scoring.DefaultInvalidControlMessagePenalty), penalties.Penalty(misbehaving))
+	}
+	require.Equal(t, float64(0), penalties.Penalty(honest))
+}
+
+// TestInvalidControlMessagePenalties_Decay checks that the accumulated penalty of a peer is halved after each half life,
+// and is eventually reset to zero.
+func TestInvalidControlMessagePenalties_Decay(t *testing.T) {
+	halfLife := time.Minute
+	penalties := scoring.NewInvalidControlMessagePenalties(scoring.DefaultInvalidControlMessagePenalty, halfLife, scoring.DefaultInvalidControlMessagePenaltiesSizeLimit)
+	now := time.Now()
+	penalties.SetTimeNowFunc(func() time.Time { return now })
+
+	pid := p2pfixtures.PeerIdFixture(t)
+	penalties.OnInvalidControlMessageNotification(p2p.NewInvalidControlMessageNotification(pid, p2p.CtrlMsgIHave, 1, nil))
+
+	now = now.Add(halfLife)
+	require.InDelta(t, scoring.DefaultInvalidControlMessagePenalty/2.0, penalties.Penalty(pid), 1e-9)
+
+	now = now.Add(halfLife)
+	require.InDelta(t, scoring.DefaultInvalidControlMessagePenalty/4.0, penalties.Penalty(pid), 1e-9)
+
+	// a new report is added on top of the decayed penalty.
+	penalties.OnInvalidControlMessageNotification(p2p.NewInvalidControlMessageNotification(pid, p2p.CtrlMsgIHave, 1, nil))
+	require.InDelta(t, scoring.DefaultInvalidControlMessagePenalty*1.25, penalties.Penalty(pid), 1e-9)
+
+	// after a long enough time the penalty decays to zero.
+	now = now.Add(20 * halfLife)
+	require.Equal(t, float64(0), penalties.Penalty(pid))
+}
+
+// TestInvalidControlMessagePenalties_SizeLimit checks that the number of tracked peers is bounded, and that decayed
+// penalties are evicted first, followed by the smallest penalties.
+func TestInvalidControlMessagePenalties_SizeLimit(t *testing.T) {
+	halfLife := time.Minute
+	penalties := scoring.NewInvalidControlMessagePenalties(scoring.DefaultInvalidControlMessagePenalty, halfLife, 2)
+	now := time.Now()
+	penalties.SetTimeNowFunc(func() time.Time { return now })
+	report := func(pid peer.ID, times int) {
+		for i := 0; i < times; i++ {
+			penalties.OnInvalidControlMessageNotification(p2p.NewInvalidControlMessageNotification(pid, p2p.CtrlMsgGraft, 1, nil))
+		}
+	}
+
+	worst := p2pfixtures.PeerIdFixture(t)
+	bad := p2pfixtures.PeerIdFixture(t)
+	report(worst, 3)
+	report(bad, 1)
+
+	// the limit is reached, the smallest penalty is evicted to make room for a new peer.
+	newcomer := p2pfixtures.PeerIdFixture(t)
+	report(newcomer, 2)
+	require.Equal(t, float64(3*scoring.DefaultInvalidControlMessagePenalty), penalties.Penalty(worst))
+	require.Equal(t, float64(0), penalties.Penalty(bad))
+	require.Equal(t, float64(2*scoring.DefaultInvalidControlMessagePenalty), penalties.Penalty(newcomer))
+
+	// reports for tracked peers do not evict any other peer.
+	report(newcomer, 1)
+	require.Equal(t, float64(3*scoring.DefaultInvalidControlMessagePenalty), penalties.Penalty(worst))
+
+	// once all penalties have decayed to zero, a new peer is tracked without evicting the smallest penalty.
+	now = now.Add(20 * halfLife)
+	report(bad, 1)
+	require.Equal(t, float64(scoring.DefaultInvalidControlMessagePenalty), penalties.Penalty(bad))
+}
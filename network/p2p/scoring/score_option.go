@@ -1,6 +1,7 @@
 package scoring
 
 import (
+	"math"
 	"time"
 
 	pubsub "github.com/libp2p/go-libp2p-pubsub"
@@ -10,6 +11,7 @@ import (
 	"github.com/onflow/flow-go/model/flow"
 	"github.com/onflow/flow-go/module"
 	"github.com/onflow/flow-go/network/channels"
+	"github.com/onflow/flow-go/network/p2p"
 	"github.com/onflow/flow-go/utils/logging"
 )
 
@@ -79,12 +81,15 @@ const (
 type ScoreOption struct {
 	logger                   zerolog.Logger
 	validator                *SubscriptionValidator
+	penalties                *InvalidControlMessagePenalties
 	idProvider               module.IdentityProvider
 	peerScoreParams          *pubsub.PeerScoreParams
 	peerThresholdParams      *pubsub.PeerScoreThresholds
 	appSpecificScoreFunction func(peer.ID) float64
 }
 
+var _ p2p.GossipSubInvalidControlMessageNotificationConsumer = (*ScoreOption)(nil)
+
 type PeerScoreParamsOption func(option *ScoreOption)
 
 func WithAppSpecificScoreFunction(appSpecificScoreFunction func(peer.ID) float64) PeerScoreParamsOption {
@@ -115,10 +120,15 @@ func NewScoreOption(logger zerolog.Logger, idProvider module.IdentityProvider, o
 			DebugSampler: throttledSampler,
 		})
 	validator := NewSubscriptionValidator()
-	appSpecificScore := defaultAppSpecificScoreFunction(logger, idProvider, validator)
+	penalties := NewInvalidControlMessagePenalties(
+		DefaultInvalidControlMessagePenalty,
+		DefaultInvalidControlMessagePenaltyHalfLife,
+		DefaultInvalidControlMessagePenaltiesSizeLimit)
+	appSpecificScore := defaultAppSpecificScoreFunction(logger, idProvider, validator, penalties)
 	s := &ScoreOption{
 		logger:                   logger,
 		validator:                validator,
+		penalties:                penalties,
 		idProvider:               idProvider,
 		appSpecificScoreFunction: appSpecificScore,
 		peerScoreParams:          defaultPeerScoreParams(),
@@ -137,6 +147,17 @@ func (s *ScoreOption) SetSubscriptionProvider(provider *SubscriptionProvider) {
 	s.validator.RegisterSubscriptionProvider(provider)
 }
 
+// OnInvalidControlMessageNotification penalizes the peer that sent an invalid control message, as reported by the
+// GossipSub rpc inspectors. The penalty is applied by the default app specific score function, and decays over time.
+func (s *ScoreOption) OnInvalidControlMessageNotification(notification *p2p.InvalidControlMessageNotification) {
+	s.logger.Debug().
+		Str("peer_id", notification.PeerID.String()).
+		Str("ctrl_msg_type", string(notification.MsgType)).
+		Uint64("ctrl_msg_count", notification.Count).
+		Msg("penalizing peer for invalid control message")
+	s.penalties.OnInvalidControlMessageNotification(notification)
+}
+
 func (s *ScoreOption) BuildFlowPubSubScoreOption() pubsub.Option {
 	s.preparePeerScoreThresholds()
 
@@ -200,7 +221,7 @@ func (s *ScoreOption) BuildGossipSubScoreOption() pubsub.Option {
 	)
 }
 
-func defaultAppSpecificScoreFunction(logger zerolog.Logger, idProvider module.IdentityProvider, validator *SubscriptionValidator) func(peer.ID) float64 {
+func defaultAppSpecificScoreFunction(logger zerolog.Logger, idProvider module.IdentityProvider, validator *SubscriptionValidator, penalties *InvalidControlMessagePenalties) func(peer.ID) float64 {
 	return func(pid peer.ID) float64 {
 		lg := logger.With().Str("peer_id", pid.String()).Logger()
 
@@ -227,16 +248,27 @@ func defaultAppSpecificScoreFunction(logger zerolog.Logger, idProvider module.Id
 			return MaxAppSpecificPenalty
 		}
 
+		score := float64(MaxAppSpecificReward)
+
 		// checks if peer is an access node, and if so, pushes it to the
 		// edges of the network by giving the minimum penalty.
 		if flowId.Role == flow.RoleAccess {
 			lg.Trace().
 				Msg("pushing access node to edge by penalizing with minimum penalty value")
-			return MinAppSpecificPenalty
+			score = MinAppSpecificPenalty
+		} else {
+			lg.Trace().
+				Msg("rewarding well-behaved non-access node peer with maximum reward value")
 		}
 
-		lg.Trace().
-			Msg("rewarding well-behaved non-access node peer with maximum reward value")
-		return MaxAppSpecificReward
+		// checks if peer has recently sent invalid control messages, and if so, applies the
+		// accumulated penalty on top of its score.
+		if penalty := penalties.Penalty(pid); penalty < 0 {
+			lg.Debug().
+				Float64("penalty", penalty).
+				Msg("applying penalty for invalid control messages")
+			score = math.Max(score+penalty, MaxAppSpecificPenalty)
+		}
+		return score
 	}
 }
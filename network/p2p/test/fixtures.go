@@ -131,6 +131,10 @@ func NodeFixture(
 		builder.SetGossipSubTracer(parameters.PubSubTracer)
 	}
 
+	if parameters.GossipSubRPCInspectorSuite != nil {
+		builder.SetGossipSubRPCInspectorSuite(parameters.GossipSubRPCInspectorSuite)
+	}
+
 	builder.SetGossipSubScoreTracerInterval(parameters.GossipSubPeerScoreTracerInterval)
 
 	n, err := builder.Build()
@@ -176,6 +180,13 @@ type NodeFixtureParameters struct {
 	PubSubTracer                     p2p.PubSubTracer
 	GossipSubPeerScoreTracerInterval time.Duration // intervals at which the peer score is updated and logged.
 	CreateStreamRetryDelay           time.Duration
+	GossipSubRPCInspectorSuite       p2p.GossipSubInspectorSuite
+}
+
+func WithGossipSubRPCInspectorSuite(suite p2p.GossipSubInspectorSuite) NodeFixtureParameterOption {
+	return func(p *NodeFixtureParameters) {
+		p.GossipSubRPCInspectorSuite = suite
+	}
 }
 
 func WithCreateStreamRetryDelay(delay time.Duration) NodeFixtureParameterOption {
@@ -0,0 +1,144 @@
+package tracer
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"time"
+
+	"github.com/rs/zerolog"
+	"go.opentelemetry.io/otel/attribute"
+	otelTrace "go.opentelemetry.io/otel/trace"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/model/messages"
+	"github.com/onflow/flow-go/module"
+	"github.com/onflow/flow-go/module/trace"
+	"github.com/onflow/flow-go/network/message"
+	"github.com/onflow/flow-go/utils/logging"
+)
+
+// traceIDLength is the length of the trace IDs of the traced messages, i.e., the length of an OpenTelemetry trace ID.
+const traceIDLength = 16
+
+// MessageTracer traces the propagation of the messages of the node through the network. It stamps the outgoing
+// messages with a trace ID and their origin time, and records for each incoming traced message its receipt time and
+// whether it was relayed by another peer than its origin, from which the propagation latency is derived and exported
+// per channel.
+//
+// The number of hops of a message is not traced. The messages published on pubsub are signed by their origin, with
+// strict signature verification, and identified by the hash of their data (see p2pnode.GossipSubAdapterConfig), so
+// the relaying peers cannot update a hop counter in the message without invalidating its signature and its
+// deduplication. The unicast messages are always received directly from their origin.
+//
+// The messages carrying a block proposal, a block vote or a collection guarantee are traced as part of the trace of
+// their entity (see module/trace.Tracer), i.e., their trace ID is derived from the entity ID the same way on all
+// nodes. Hence, the journey of a block proposal through the network is visible alongside its processing on each node.
+// Other messages are stamped with a random trace ID for correlating their logs across the nodes.
+type MessageTracer struct {
+	log     zerolog.Logger
+	tracer  module.Tracer
+	metrics module.NetworkMessageTracingMetrics
+}
+
+func NewMessageTracer(log zerolog.Logger, tracer module.Tracer, metrics module.NetworkMessageTracingMetrics) *MessageTracer {
+	return &MessageTracer{
+		log:     log.With().Str("component", "message_tracer").Logger(),
+		tracer:  tracer,
+		metrics: metrics,
+	}
+}
+
+// OnOutgoingMessage stamps the outgoing message with a trace ID and its origin time. The payload is the message
+// before encoding.
+func (t *MessageTracer) OnOutgoingMessage(msg *message.Message, payload interface{}) {
+	now := time.Now()
+	msg.OriginTimestamp = now.UnixNano()
+
+	entityID, entityType, ok := tracedEntity(payload)
+	if !ok {
+		msg.TraceID = make([]byte, traceIDLength)
+		_, err := rand.Read(msg.TraceID)
+		if err != nil {
+			// the message is sent untraced
+			t.log.Error().Err(err).Msg("could not generate trace id")
+			msg.TraceID = nil
+		}
+		return
+	}
+
+	msg.TraceID = entityID[:traceIDLength]
+	span := t.startEntitySpan(entityID, entityType, trace.NETSendTracedMessage, otelTrace.WithTimestamp(now))
+	span.SetAttributes(
+		attribute.String("channel", msg.ChannelID),
+		attribute.Int("targets", len(msg.TargetIDs)),
+	)
+	span.End(otelTrace.WithTimestamp(now))
+}
+
+// OnIncomingMessage records the propagation of the incoming message if it is traced, i.e., its propagation latency
+// and whether it was relayed by another peer than its origin. The payload is the decoded message.
+func (t *MessageTracer) OnIncomingMessage(msg *message.Message, payload interface{}, originID flow.Identifier, relayed bool) {
+	if len(msg.TraceID) != traceIDLength || msg.OriginTimestamp <= 0 {
+		// the message is not traced by its origin
+		return
+	}
+
+	received := time.Now()
+	sent := time.Unix(0, msg.OriginTimestamp)
+	latency := received.Sub(sent)
+	if latency < 0 {
+		// the clock of the origin is ahead of the clock of the node
+		latency = 0
+		sent = received
+	}
+	t.metrics.OnTracedMessageReceived(msg.ChannelID, latency, relayed)
+
+	t.log.Trace().
+		Str("trace_id", hex.EncodeToString(msg.TraceID)).
+		Str("channel", msg.ChannelID).
+		Hex("origin_id", logging.ID(originID)).
+		Dur("latency", latency).
+		Bool("relayed", relayed).
+		Msg("traced message received")
+
+	entityID, entityType, ok := tracedEntity(payload)
+	if !ok {
+		return
+	}
+	// the span covers the propagation of the message, from its sending by its origin to its receipt.
+	span := t.startEntitySpan(entityID, entityType, trace.NETReceiveTracedMessage, otelTrace.WithTimestamp(sent))
+	span.SetAttributes(
+		attribute.String("channel", msg.ChannelID),
+		attribute.String("origin_id", originID.String()),
+		attribute.Bool("relayed", relayed),
+	)
+	span.End(otelTrace.WithTimestamp(received))
+}
+
+// startEntitySpan starts a span of the given entity.
+func (t *MessageTracer) startEntitySpan(entityID flow.Identifier, entityType string, spanName trace.SpanName, opts ...otelTrace.SpanStartOption) otelTrace.Span {
+	if entityType == trace.EntityTypeCollection {
+		span, _ := t.tracer.StartCollectionSpan(context.Background(), entityID, spanName, opts...)
+		return span
+	}
+	span, _ := t.tracer.StartBlockSpan(context.Background(), entityID, spanName, opts...)
+	return span
+}
+
+// tracedEntity returns the ID and the type of the entity carried by the payload, for the payloads traced as part of
+// the trace of their entity.
+func tracedEntity(payload interface{}) (flow.Identifier, string, bool) {
+	switch p := payload.(type) {
+	case *messages.BlockProposal:
+		return p.Block.Header.ID(), trace.EntityTypeBlock, true
+	case *messages.BlockVote:
+		return p.BlockID, trace.EntityTypeBlock, true
+	case *messages.ClusterBlockProposal:
+		return p.Block.Header.ID(), trace.EntityTypeBlock, true
+	case *flow.CollectionGuarantee:
+		return p.CollectionID, trace.EntityTypeCollection, true
+	default:
+		return flow.ZeroID, "", false
+	}
+}
@@ -0,0 +1,78 @@
+package tracer_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	mockmodule "github.com/onflow/flow-go/module/mock"
+	"github.com/onflow/flow-go/module/trace"
+	"github.com/onflow/flow-go/network/channels"
+	"github.com/onflow/flow-go/network/message"
+	"github.com/onflow/flow-go/network/p2p/tracer"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+// TestMessageTracer_Outgoing evaluates that the outgoing messages are stamped with their origin time, and with a trace ID
+// derived from their entity if they carry one, or a random trace ID otherwise. The stamps must survive the encoding of
+// the messages.
+func TestMessageTracer_Outgoing(t *testing.T) {
+	messageTracer := tracer.NewMessageTracer(zerolog.Nop(), trace.NewNoopTracer(), mockmodule.NewNetworkMessageTracingMetrics(t))
+
+	proposal := unittest.ProposalFixture()
+	msg := &message.Message{ChannelID: channels.ConsensusCommittee.String(), Payload: []byte{1, 2, 3}}
+	before := time.Now().UnixNano()
+	messageTracer.OnOutgoingMessage(msg, proposal)
+	blockID := proposal.Block.Header.ID()
+	require.Equal(t, blockID[:16], msg.TraceID)
+	require.GreaterOrEqual(t, msg.OriginTimestamp, before)
+	require.LessOrEqual(t, msg.OriginTimestamp, time.Now().UnixNano())
+
+	data, err := msg.Marshal()
+	require.NoError(t, err)
+	decoded := &message.Message{}
+	require.NoError(t, decoded.Unmarshal(data))
+	require.Equal(t, msg.TraceID, decoded.TraceID)
+	require.Equal(t, msg.OriginTimestamp, decoded.OriginTimestamp)
+	require.Equal(t, msg.Payload, decoded.Payload)
+
+	// messages without entity are stamped with distinct random trace IDs.
+	first := &message.Message{ChannelID: channels.SyncCommittee.String()}
+	second := &message.Message{ChannelID: channels.SyncCommittee.String()}
+	messageTracer.OnOutgoingMessage(first, "payload")
+	messageTracer.OnOutgoingMessage(second, "payload")
+	require.Len(t, first.TraceID, 16)
+	require.Len(t, second.TraceID, 16)
+	require.NotEqual(t, first.TraceID, second.TraceID)
+}
+
+// TestMessageTracer_Incoming evaluates that the propagation latency and the relaying of the incoming traced messages are
+// recorded, and that untraced messages are ignored.
+func TestMessageTracer_Incoming(t *testing.T) {
+	metrics := mockmodule.NewNetworkMessageTracingMetrics(t)
+	messageTracer := tracer.NewMessageTracer(zerolog.Nop(), trace.NewNoopTracer(), metrics)
+	originID := unittest.IdentifierFixture()
+	proposal := unittest.ProposalFixture()
+
+	// messages of nodes not tracing messages are ignored.
+	messageTracer.OnIncomingMessage(&message.Message{ChannelID: channels.ConsensusCommittee.String()}, proposal, originID, false)
+
+	msg := &message.Message{ChannelID: channels.ConsensusCommittee.String()}
+	messageTracer.OnOutgoingMessage(msg, proposal)
+	msg.OriginTimestamp = time.Now().Add(-time.Second).UnixNano()
+	metrics.On("OnTracedMessageReceived", channels.ConsensusCommittee.String(), mock.Anything, true).
+		Run(func(args mock.Arguments) {
+			latency := args.Get(1).(time.Duration)
+			require.GreaterOrEqual(t, latency, time.Second)
+			require.Less(t, latency, 10*time.Second)
+		}).Once()
+	messageTracer.OnIncomingMessage(msg, proposal, originID, true)
+
+	// a message stamped by an origin with a clock ahead of the node has no latency.
+	msg.OriginTimestamp = time.Now().Add(time.Minute).UnixNano()
+	metrics.On("OnTracedMessageReceived", channels.ConsensusCommittee.String(), time.Duration(0), false).Once()
+	messageTracer.OnIncomingMessage(msg, proposal, originID, false)
+}
@@ -102,23 +102,35 @@ func (m *Manager) Register(protocol protocols.ProtocolName) error {
 	return nil
 }
 
-// CreateStream tries establishing a libp2p stream to the remote peer id. It tries creating streams in the descending order of preference until
-// it either creates a successful stream or runs out of options. Creating stream on each protocol is tried at most `maxAttempts`, and then falls
-// back to the less preferred one.
+// CreateStream tries establishing a libp2p stream to the remote peer id. The unicast protocol of the stream (e.g., its compression) is
+// negotiated with the remote peer while creating the stream: all registered protocols are offered in the descending order of preference,
+// and the most preferred one supported by the remote peer is selected. The stream is then upgraded by the selected protocol.
+// Creating the stream is tried at most `maxAttempts`.
 func (m *Manager) CreateStream(ctx context.Context, peerID peer.ID, maxAttempts int) (libp2pnet.Stream, []multiaddr.Multiaddr, error) {
-	var errs error
-	for i := len(m.protocols) - 1; i >= 0; i-- {
-		s, addrs, err := m.tryCreateStream(ctx, peerID, uint64(maxAttempts), m.protocols[i])
-		if err != nil {
-			errs = multierror.Append(errs, err)
-			continue
-		}
+	s, addrs, err := m.tryCreateStream(ctx, peerID, uint64(maxAttempts))
+	if err != nil {
+		return nil, nil, fmt.Errorf("could not create stream on any available unicast protocol: %w", err)
+	}
+	return s, addrs, nil
+}
 
-		// return first successful stream
-		return s, addrs, nil
+// preferredProtocolIds returns the protocol ids of the registered unicast protocols in the descending order of preference.
+func (m *Manager) preferredProtocolIds() []protocol.ID {
+	pids := make([]protocol.ID, 0, len(m.protocols))
+	for i := len(m.protocols) - 1; i >= 0; i-- {
+		pids = append(pids, m.protocols[i].ProtocolId())
 	}
+	return pids
+}
 
-	return nil, nil, fmt.Errorf("could not create stream on any available unicast protocol: %w", errs)
+// protocolById returns the registered unicast protocol with the given protocol id.
+func (m *Manager) protocolById(pid protocol.ID) (protocols.Protocol, bool) {
+	for _, p := range m.protocols {
+		if p.ProtocolId() == pid {
+			return p, true
+		}
+	}
+	return nil, false
 }
 
 // tryCreateStream will retry createStream with the configured exponential backoff delay and maxAttempts.
@@ -126,7 +138,7 @@ func (m *Manager) CreateStream(ctx context.Context, peerID peer.ID, maxAttempts
 // stream can be successfully the multierror will be returned. During stream creation when IsErrDialInProgress
 // is encountered during retries this would indicate that no connection to the peer exists yet.
 // In this case we will retry creating the stream with a backoff until a connection is established.
-func (m *Manager) tryCreateStream(ctx context.Context, peerID peer.ID, maxAttempts uint64, protocol protocols.Protocol) (libp2pnet.Stream, []multiaddr.Multiaddr, error) {
+func (m *Manager) tryCreateStream(ctx context.Context, peerID peer.ID, maxAttempts uint64) (libp2pnet.Stream, []multiaddr.Multiaddr, error) {
 	var err error
 	var s libp2pnet.Stream
 	var addrs []multiaddr.Multiaddr // address on which we dial peerID
@@ -142,7 +154,7 @@ func (m *Manager) tryCreateStream(ctx context.Context, peerID peer.ID, maxAttemp
 	// retryable func will attempt to create the stream and only retry if dialing the peer is in progress
 	f := func(context.Context) error {
 		attempts++
-		s, addrs, err = m.createStream(ctx, peerID, maxAttempts, protocol)
+		s, addrs, err = m.createStream(ctx, peerID, maxAttempts)
 		if err != nil {
 			if IsErrDialInProgress(err) {
 				m.logger.Warn().
@@ -170,14 +182,22 @@ func (m *Manager) tryCreateStream(ctx context.Context, peerID peer.ID, maxAttemp
 	return s, addrs, nil
 }
 
-// createStream creates a stream to the peerID with the provided protocol.
-func (m *Manager) createStream(ctx context.Context, peerID peer.ID, maxAttempts uint64, protocol protocols.Protocol) (libp2pnet.Stream, []multiaddr.Multiaddr, error) {
-	s, addrs, err := m.rawStreamWithProtocol(ctx, protocol.ProtocolId(), peerID, maxAttempts)
+// createStream creates a stream to the peerID on the most preferred unicast protocol supported by the remote peer,
+// and upgrades the stream by the negotiated protocol.
+func (m *Manager) createStream(ctx context.Context, peerID peer.ID, maxAttempts uint64) (libp2pnet.Stream, []multiaddr.Multiaddr, error) {
+	s, addrs, err := m.rawStreamWithProtocol(ctx, m.preferredProtocolIds(), peerID, maxAttempts)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	s, err = protocol.UpgradeRawStream(s)
+	negotiated, ok := m.protocolById(s.Protocol())
+	if !ok {
+		// should never happen, as the stream is created on one of the offered protocols.
+		_ = s.Reset()
+		return nil, nil, fmt.Errorf("stream negotiated on unknown unicast protocol: %s", s.Protocol())
+	}
+
+	s, err = negotiated.UpgradeRawStream(s)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -185,9 +205,9 @@ func (m *Manager) createStream(ctx context.Context, peerID peer.ID, maxAttempts
 	return s, addrs, nil
 }
 
-// rawStreamWithProtocol creates a stream raw libp2p stream on specified protocol.
+// rawStreamWithProtocol creates a raw libp2p stream on the most preferred of the specified protocols that is supported by the remote peer.
 //
-// Note: a raw stream must be upgraded by the given unicast protocol id.
+// Note: a raw stream must be upgraded by the negotiated unicast protocol.
 //
 // It makes at most `maxAttempts` to create a stream with the peer.
 // This was put in as a fix for #2416. PubSub and 1-1 communication compete with each other when trying to connect to
@@ -203,7 +223,7 @@ func (m *Manager) createStream(ctx context.Context, peerID peer.ID, maxAttempts
 // Unexpected errors during normal operations:
 //   - network.ErrIllegalConnectionState indicates bug in libpp2p when checking IsConnected status of peer.
 func (m *Manager) rawStreamWithProtocol(ctx context.Context,
-	protocolID protocol.ID,
+	protocolIDs []protocol.ID,
 	peerID peer.ID,
 	maxAttempts uint64,
 ) (libp2pnet.Stream, []multiaddr.Multiaddr, error) {
@@ -226,7 +246,7 @@ func (m *Manager) rawStreamWithProtocol(ctx context.Context,
 	}
 
 	// at this point dialing should have completed, we are already connected we can attempt to create the stream
-	s, err := m.rawStream(ctx, peerID, protocolID, maxAttempts)
+	s, err := m.rawStream(ctx, peerID, protocolIDs, maxAttempts)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -292,7 +312,7 @@ func (m *Manager) dialPeer(ctx context.Context, peerID peer.ID, maxAttempts uint
 // rawStream creates a stream to peer with retries.
 // Expected errors during normal operations:
 //   - ErrMaxRetries if retry attempts are exhausted
-func (m *Manager) rawStream(ctx context.Context, peerID peer.ID, protocolID protocol.ID, maxAttempts uint64) (libp2pnet.Stream, error) {
+func (m *Manager) rawStream(ctx context.Context, peerID peer.ID, protocolIDs []protocol.ID, maxAttempts uint64) (libp2pnet.Stream, error) {
 	// aggregated retryable errors that occur during retries, errs will be returned
 	// if retry context times out or maxAttempts have been made before a successful retry occurs
 	var errs error
@@ -311,9 +331,10 @@ func (m *Manager) rawStream(ctx context.Context, peerID peer.ID, protocolID prot
 		// we've already ensured that a connection already exists.
 		ctx = libp2pnet.WithNoDial(ctx, "application ensured connection to peer exists")
 		// creates stream using stream factory
-		s, err = m.streamFactory.NewStream(ctx, peerID, protocolID)
+		// the first of the protocol ids supported by the remote peer is selected, i.e., the ids are in the descending order of preference.
+		s, err = m.streamFactory.NewStream(ctx, peerID, protocolIDs...)
 		if err != nil {
-			// if the stream creation failed due to invalid protocol id, skip the re-attempt
+			// if the stream creation failed due to none of the protocol ids being supported, skip the re-attempt
 			if IsErrProtocolNotSupported(err) {
 				return err
 			}
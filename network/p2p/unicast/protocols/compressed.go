@@ -0,0 +1,55 @@
+package protocols
+
+import (
+	libp2pnet "github.com/libp2p/go-libp2p/core/network"
+	"github.com/libp2p/go-libp2p/core/protocol"
+	"github.com/rs/zerolog"
+
+	flownet "github.com/onflow/flow-go/network"
+	"github.com/onflow/flow-go/network/p2p/compressed"
+)
+
+// CompressedUnicast is a unicast protocol that creates and returns compressed streams out of the plain libp2p streams,
+// using the compressor it is configured with. The compression algorithm is identified by the protocol id, hence both
+// ends of a stream negotiate the compression algorithm while negotiating the protocol.
+type CompressedUnicast struct {
+	protocolId     protocol.ID
+	compressor     flownet.Compressor
+	defaultHandler libp2pnet.StreamHandler
+	logger         zerolog.Logger
+}
+
+var _ Protocol = (*CompressedUnicast)(nil)
+
+func newCompressedUnicast(
+	logger zerolog.Logger,
+	protocolId protocol.ID,
+	compressor flownet.Compressor,
+	defaultHandler libp2pnet.StreamHandler,
+) *CompressedUnicast {
+	return &CompressedUnicast{
+		protocolId:     protocolId,
+		compressor:     compressor,
+		defaultHandler: defaultHandler,
+		logger:         logger,
+	}
+}
+
+// UpgradeRawStream wraps compression and decompression around the plain libp2p stream.
+func (c CompressedUnicast) UpgradeRawStream(s libp2pnet.Stream) (libp2pnet.Stream, error) {
+	return compressed.NewCompressedStream(s, c.compressor)
+}
+
+func (c CompressedUnicast) Handler(s libp2pnet.Stream) {
+	// converts native libp2p stream to compressed stream
+	s, err := c.UpgradeRawStream(s)
+	if err != nil {
+		c.logger.Error().Err(err).Msg("could not create compressed stream")
+		return
+	}
+	c.defaultHandler(s)
+}
+
+func (c CompressedUnicast) ProtocolId() protocol.ID {
+	return c.protocolId
+}
@@ -26,8 +26,19 @@ const (
 	// FlowLibP2PPingProtocolPrefix is the Flow Ping protocol prefix
 	FlowLibP2PPingProtocolPrefix = FlowLibP2PProtocolCommonPrefix + "/ping/"
 
+	// FlowLibP2PPeerRecordsProtocolPrefix is the prefix of the protocol exchanging the signed peer records of the staked
+	// access nodes on the public network.
+	FlowLibP2PPeerRecordsProtocolPrefix = FlowLibP2PProtocolCommonPrefix + "/peer-records/"
+
 	// FlowLibP2PProtocolGzipCompressedOneToOne represents the protocol id for compressed streams under gzip compressor.
 	FlowLibP2PProtocolGzipCompressedOneToOne = FlowLibP2POneToOneProtocolIDPrefix + "/gzip/"
+
+	// FlowLibP2PProtocolSnappyCompressedOneToOne represents the protocol id for compressed streams under snappy compressor.
+	FlowLibP2PProtocolSnappyCompressedOneToOne = FlowLibP2POneToOneProtocolIDPrefix + "/snappy/"
+
+	// FlowLibP2PProtocolZstdCompressedOneToOne represents the protocol id for compressed streams under zstd compressor
+	// with the shared Flow messages dictionary.
+	FlowLibP2PProtocolZstdCompressedOneToOne = FlowLibP2POneToOneProtocolIDPrefix + "/zstd/"
 )
 
 // IsFlowProtocolStream returns true if the libp2p stream is for a Flow protocol
@@ -52,6 +63,10 @@ func PingProtocolId(sporkId flow.Identifier) protocol.ID {
 	return protocol.ID(FlowLibP2PPingProtocolPrefix + sporkId.String())
 }
 
+func PeerRecordsProtocolID(sporkId flow.Identifier) protocol.ID {
+	return protocol.ID(FlowLibP2PPeerRecordsProtocolPrefix + sporkId.String())
+}
+
 type ProtocolName string
 type ProtocolFactory func(zerolog.Logger, flow.Identifier, libp2pnet.StreamHandler) Protocol
 
@@ -69,6 +84,14 @@ func ToProtocolFactory(name ProtocolName) (ProtocolFactory, error) {
 		return func(logger zerolog.Logger, sporkId flow.Identifier, handler libp2pnet.StreamHandler) Protocol {
 			return NewGzipCompressedUnicast(logger, sporkId, handler)
 		}, nil
+	case SnappyCompressionUnicast:
+		return func(logger zerolog.Logger, sporkId flow.Identifier, handler libp2pnet.StreamHandler) Protocol {
+			return NewSnappyCompressedUnicast(logger, sporkId, handler)
+		}, nil
+	case ZstdCompressionUnicast:
+		return func(logger zerolog.Logger, sporkId flow.Identifier, handler libp2pnet.StreamHandler) Protocol {
+			return NewZstdCompressedUnicast(logger, sporkId, handler)
+		}, nil
 	default:
 		return nil, fmt.Errorf("unknown unicast protocol name: %s", name)
 	}
@@ -0,0 +1,25 @@
+package protocols
+
+import (
+	libp2pnet "github.com/libp2p/go-libp2p/core/network"
+	"github.com/libp2p/go-libp2p/core/protocol"
+	"github.com/rs/zerolog"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/network/compressor"
+)
+
+const SnappyCompressionUnicast = ProtocolName("snappy-compression")
+
+func FlowSnappyProtocolId(sporkId flow.Identifier) protocol.ID {
+	return protocol.ID(FlowLibP2PProtocolSnappyCompressedOneToOne + sporkId.String())
+}
+
+// NewSnappyCompressedUnicast creates a unicast protocol that creates and returns snappy-compressed streams out of input streams.
+func NewSnappyCompressedUnicast(logger zerolog.Logger, sporkId flow.Identifier, defaultHandler libp2pnet.StreamHandler) *CompressedUnicast {
+	return newCompressedUnicast(
+		logger.With().Str("subsystem", "snappy-unicast").Logger(),
+		FlowSnappyProtocolId(sporkId),
+		compressor.NewSnappyCompressor(),
+		defaultHandler)
+}
@@ -0,0 +1,27 @@
+package protocols
+
+import (
+	libp2pnet "github.com/libp2p/go-libp2p/core/network"
+	"github.com/libp2p/go-libp2p/core/protocol"
+	"github.com/rs/zerolog"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/network/compressor"
+)
+
+const ZstdCompressionUnicast = ProtocolName("zstd-compression")
+
+func FlowZstdProtocolId(sporkId flow.Identifier) protocol.ID {
+	return protocol.ID(FlowLibP2PProtocolZstdCompressedOneToOne + sporkId.String())
+}
+
+// NewZstdCompressedUnicast creates a unicast protocol that creates and returns zstd-compressed streams out of input streams.
+// The streams are compressed with the shared dictionary trained on the Flow network messages, which is part of the
+// protocol, i.e., a change of the dictionary requires a new protocol id.
+func NewZstdCompressedUnicast(logger zerolog.Logger, sporkId flow.Identifier, defaultHandler libp2pnet.StreamHandler) *CompressedUnicast {
+	return newCompressedUnicast(
+		logger.With().Str("subsystem", "zstd-unicast").Logger(),
+		FlowZstdProtocolId(sporkId),
+		compressor.NewFlowZstdCompressor(),
+		defaultHandler)
+}
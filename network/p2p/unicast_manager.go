@@ -19,9 +19,9 @@ type UnicastManager interface {
 	// over previously registered ones.
 	// All errors returned from this function can be considered benign.
 	Register(unicast protocols.ProtocolName) error
-	// CreateStream tries establishing a libp2p stream to the remote peer id. It tries creating streams in the descending order of preference until
-	// it either creates a successful stream or runs out of options. Creating stream on each protocol is tried at most `maxAttempts`, and then falls
-	// back to the less preferred one.
+	// CreateStream tries establishing a libp2p stream to the remote peer id. The unicast protocol of the stream is negotiated with the remote
+	// peer, i.e., the most preferred registered protocol that is also supported by the remote peer is selected. Creating the stream is tried
+	// at most `maxAttempts`.
 	// All errors returned from this function can be considered benign.
 	CreateStream(ctx context.Context, peerID peer.ID, maxAttempts int) (libp2pnet.Stream, []multiaddr.Multiaddr, error)
 }
@@ -56,6 +56,10 @@ func getPriorityByType(message interface{}) Priority {
 		return MediumPriority
 	case *messages.BlockResponse:
 		return HighPriority
+	case *messages.SnapshotRequest:
+		return LowPriority
+	case *messages.SnapshotResponse:
+		return MediumPriority
 
 	// cluster consensus
 	case *messages.ClusterBlockProposal:
@@ -78,6 +82,8 @@ func getPriorityByType(message interface{}) Priority {
 		return HighPriority
 	case *flow.ResultApproval:
 		return HighPriority
+	case *flow.ChunkFaultReport:
+		return MediumPriority
 
 	// execution state synchronization
 	case *messages.ExecutionStateSyncRequest:
@@ -0,0 +1,508 @@
+package queue
+
+import (
+	"container/heap"
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/module"
+	"github.com/onflow/flow-go/network"
+	"github.com/onflow/flow-go/network/channels"
+)
+
+const (
+	// DefaultMaxMessagesPerSender is the default maximum number of messages of a single sender in the inbound queue.
+	DefaultMaxMessagesPerSender = 1_000
+
+	// DefaultMaxQueueSize is the default maximum number of messages in the inbound queue.
+	DefaultMaxQueueSize = 20_000
+)
+
+// reasons for dropping messages reported to the metrics.
+const (
+	// DropReasonSenderQueueFull is reported when a message is dropped as the sender reached its maximum number of
+	// messages in the queue.
+	DropReasonSenderQueueFull = "sender_queue_full"
+	// DropReasonQueueFull is reported when a message is dropped (or evicted) as the queue reached its maximum size.
+	DropReasonQueueFull = "queue_full"
+)
+
+// FairQueueConfig is the configuration of the FairMessageQueue.
+type FairQueueConfig struct {
+	// MaxMessagesPerSender is the maximum number of messages of a single sender in the queue.
+	MaxMessagesPerSender int
+	// MaxSize is the maximum number of messages in the queue.
+	MaxSize int
+	// RoleWeights are the weights of the roles of the senders when sharing the queue. Roles without weight have weight 1.
+	RoleWeights map[flow.Role]float64
+	// ChannelWeights are the weights of the messages per channel. Channels without weight have weight 1.
+	ChannelWeights map[channels.Channel]float64
+}
+
+// DefaultFairQueueConfig returns the default configuration of the FairMessageQueue, where all roles and channels
+// have the same weight.
+func DefaultFairQueueConfig() FairQueueConfig {
+	return FairQueueConfig{
+		MaxMessagesPerSender: DefaultMaxMessagesPerSender,
+		MaxSize:              DefaultMaxQueueSize,
+		RoleWeights:          make(map[flow.Role]float64),
+		ChannelWeights:       make(map[channels.Channel]float64),
+	}
+}
+
+// Validate returns an error if the configuration is invalid, i.e., if the queue (or the queue of a sender) has no room
+// for any message, in which case the queue would drop all messages, or if any of the weights is not strictly positive.
+func (c FairQueueConfig) Validate() error {
+	if c.MaxMessagesPerSender <= 0 {
+		return fmt.Errorf("maximum number of messages per sender must be positive, got: %d", c.MaxMessagesPerSender)
+	}
+	if c.MaxSize <= 0 {
+		return fmt.Errorf("maximum queue size must be positive, got: %d", c.MaxSize)
+	}
+	for role, weight := range c.RoleWeights {
+		if weight <= 0 {
+			return fmt.Errorf("weight of role %s must be positive, got: %v", role, weight)
+		}
+	}
+	for channel, weight := range c.ChannelWeights {
+		if weight <= 0 {
+			return fmt.Errorf("weight of channel %s must be positive, got: %v", channel, weight)
+		}
+	}
+	return nil
+}
+
+// ParseRoleWeights parses the weights of the roles of the FairQueueConfig from their string representation keyed by
+// role name, e.g., {"consensus": "2"}.
+// All errors indicate invalid weights.
+func ParseRoleWeights(weights map[string]string) (map[flow.Role]float64, error) {
+	parsed := make(map[flow.Role]float64, len(weights))
+	for name, value := range weights {
+		role, err := flow.ParseRole(name)
+		if err != nil {
+			return nil, fmt.Errorf("invalid role %s: %w", name, err)
+		}
+		weight, err := parseWeight(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid weight of role %s: %w", name, err)
+		}
+		parsed[role] = weight
+	}
+	return parsed, nil
+}
+
+// ParseChannelWeights parses the weights of the channels of the FairQueueConfig from their string representation keyed
+// by channel name, e.g., {"consensus-committee": "2"}.
+// All errors indicate invalid weights.
+func ParseChannelWeights(weights map[string]string) (map[channels.Channel]float64, error) {
+	parsed := make(map[channels.Channel]float64, len(weights))
+	for name, value := range weights {
+		channel := channels.Channel(name)
+		if !channels.ChannelExists(channel) {
+			return nil, fmt.Errorf("unknown channel: %s", name)
+		}
+		weight, err := parseWeight(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid weight of channel %s: %w", name, err)
+		}
+		parsed[channel] = weight
+	}
+	return parsed, nil
+}
+
+// parseWeight parses a strictly positive weight.
+func parseWeight(value string) (float64, error) {
+	weight, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0, fmt.Errorf("could not parse weight: %w", err)
+	}
+	if weight <= 0 {
+		return 0, fmt.Errorf("weight must be positive, got: %v", weight)
+	}
+	return weight, nil
+}
+
+// FairMessageQueue is the inbound message queue of the networking layer that shares the processing capacity fairly
+// between the senders of the messages, so that a single sender cannot starve the others by flooding the queue.
+//
+// The queue implements weighted fair queueing (start-time fair queueing) at two levels: first across the roles of the
+// senders, weighted by the role weights, and then across the senders of the same role. Each message has a cost
+// inversely proportional to its priority and to the weight of its channel, and the queue serves next the sender whose
+// next message finishes first in the virtual time of the queue. Hence, each sender (resp. role) with pending messages
+// gets a share of the queue proportional to its weight, regardless of the number of messages it sends, while within
+// the messages of a sender, the messages are served in priority order, and in insertion order for the same priority.
+//
+// The size of the queue is bounded per sender and in total. When the queue of a sender is full, its lowest priority
+// message is dropped. When the queue is full, the lowest priority message of the sender with the most messages in the
+// queue is dropped, which protects the messages of the other senders.
+type FairMessageQueue struct {
+	cond         *sync.Cond
+	ctx          context.Context
+	priorityFunc MessagePriorityFunc
+	idProvider   module.IdentityProvider
+	metrics      module.NetworkInboundQueueMetrics
+	config       FairQueueConfig
+
+	roles       map[flow.Role]*roleQueue
+	active      schedHeap[*roleQueue] // roles with pending messages, ordered by the virtual finish time of their next message.
+	virtualTime float64               // virtual time of the queue, i.e., the finish time of the last served role.
+	seq         uint64                // activation counter, used to break ties between equal finish times.
+	size        int
+}
+
+var _ network.MessageQueue = (*FairMessageQueue)(nil)
+
+// NewFairMessageQueue creates a new FairMessageQueue. The identity provider is used to look up the roles of the senders,
+// senders unknown to the identity provider share the queue as a role of their own.
+// All errors indicate an invalid configuration.
+func NewFairMessageQueue(ctx context.Context,
+	priorityFunc MessagePriorityFunc,
+	idProvider module.IdentityProvider,
+	metrics module.NetworkInboundQueueMetrics,
+	config FairQueueConfig) (*FairMessageQueue, error) {
+
+	if err := config.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid fair queue config: %w", err)
+	}
+
+	mq := &FairMessageQueue{
+		ctx:          ctx,
+		priorityFunc: priorityFunc,
+		idProvider:   idProvider,
+		metrics:      metrics,
+		config:       config,
+		roles:        make(map[flow.Role]*roleQueue),
+	}
+	m := sync.Mutex{}
+	mq.cond = sync.NewCond(&m)
+
+	// kick off a go routine to unblock queue readers on shutdown
+	go func() {
+		<-ctx.Done()
+		// unblock receive
+		mq.cond.Broadcast()
+	}()
+
+	return mq, nil
+}
+
+// Insert inserts the message in the queue. The message must be a QMessage.
+// Messages dropped due to the size limits of the queue are not reported as errors, but are reported to the metrics.
+func (mq *FairMessageQueue) Insert(message interface{}) error {
+	if err := mq.ctx.Err(); err != nil {
+		return err
+	}
+
+	qm, ok := message.(QMessage)
+	if !ok {
+		return fmt.Errorf("invalid message format: %T", message)
+	}
+
+	priority, err := mq.priorityFunc(message)
+	if err != nil {
+		return fmt.Errorf("failed to derive message priority: %w", err)
+	}
+
+	it := &item{
+		message:   message,
+		priority:  int(priority),
+		timestamp: time.Now(),
+		cost:      mq.cost(qm.Target, priority),
+	}
+
+	role := flow.Role(0)
+	if identity, ok := mq.idProvider.ByNodeID(qm.SenderID); ok {
+		role = identity.Role
+	}
+
+	mq.cond.L.Lock()
+	defer mq.cond.L.Unlock()
+
+	sender := mq.senderQueue(role, qm.SenderID)
+	admitted := true
+	if sender.items.Len() >= mq.config.MaxMessagesPerSender {
+		admitted = mq.dropLowest(sender, it, DropReasonSenderQueueFull)
+	} else if mq.size >= mq.config.MaxSize {
+		admitted = mq.dropLowest(mq.longestSenderQueue(sender), it, DropReasonQueueFull)
+	}
+	if !admitted {
+		// cleans up the queue of the sender in case it was created for the dropped message.
+		mq.refresh(sender)
+		return nil
+	}
+	// the queue of the sender may have been released when making room for the message.
+	sender = mq.senderQueue(role, qm.SenderID)
+
+	heap.Push(&sender.items, it)
+	mq.size++
+	mq.refresh(sender)
+	mq.metrics.MessageAdded(it.priority)
+
+	// signal a waiting routine that a message is now available
+	mq.cond.Signal()
+
+	return nil
+}
+
+// Remove removes the next message of the queue. If the queue is empty, this call blocks until a message is inserted
+// or the context of the queue is canceled, in which case it returns nil.
+func (mq *FairMessageQueue) Remove() interface{} {
+	mq.cond.L.Lock()
+	defer mq.cond.L.Unlock()
+	for mq.size == 0 {
+		// if the context has been canceled, don't wait
+		if err := mq.ctx.Err(); err != nil {
+			return nil
+		}
+
+		mq.cond.Wait()
+	}
+
+	role := mq.active[0]
+	sender := role.active[0]
+	it := heap.Pop(&sender.items).(*item)
+	mq.size--
+
+	// advances the virtual times of the queue and the role to the finish time of the served message, and starts the
+	// next messages of the role and the sender at that time.
+	mq.virtualTime = role.finish
+	role.virtualTime = sender.finish
+	role.start = role.finish
+	sender.start = sender.finish
+	mq.refresh(sender)
+
+	// record metrics
+	mq.metrics.QueueDuration(time.Since(it.timestamp), it.priority)
+	mq.metrics.MessageRemoved(it.priority)
+
+	return it.message
+}
+
+// Len returns the number of messages in the queue.
+func (mq *FairMessageQueue) Len() int {
+	mq.cond.L.Lock()
+	defer mq.cond.L.Unlock()
+	return mq.size
+}
+
+// cost returns the cost of a message in the virtual time of the queue, which is inversely proportional to the priority
+// of the message and the weight of its channel.
+func (mq *FairMessageQueue) cost(channel channels.Channel, priority Priority) float64 {
+	weight, ok := mq.config.ChannelWeights[channel]
+	if !ok || weight <= 0 {
+		weight = 1
+	}
+	if priority <= 0 {
+		priority = LowPriority
+	}
+	return 1 / (weight * float64(priority))
+}
+
+// senderQueue returns the queue of the sender, creating it (and the queue of its role) if needed.
+// The caller must hold the lock of the queue.
+func (mq *FairMessageQueue) senderQueue(role flow.Role, senderID flow.Identifier) *senderQueue {
+	r, ok := mq.roles[role]
+	if !ok {
+		weight, ok := mq.config.RoleWeights[role]
+		if !ok || weight <= 0 {
+			weight = 1
+		}
+		r = &roleQueue{
+			schedule: newSchedule(),
+			role:     role,
+			weight:   weight,
+			senders:  make(map[flow.Identifier]*senderQueue),
+		}
+		mq.roles[role] = r
+	}
+
+	s, ok := r.senders[senderID]
+	if !ok {
+		s = &senderQueue{
+			schedule: newSchedule(),
+			senderID: senderID,
+			role:     r,
+			items:    priorityQueue(make([]*item, 0)),
+		}
+		r.senders[senderID] = s
+	}
+	return s
+}
+
+// refresh updates the scheduling of the sender and its role after the messages of the sender changed, i.e., activates
+// them if they were idle, recomputes their finish times, and deactivates them if they have no pending message left.
+// The caller must hold the lock of the queue.
+func (mq *FairMessageQueue) refresh(s *senderQueue) {
+	r := s.role
+
+	if s.items.Len() == 0 {
+		if s.index >= 0 {
+			heap.Remove(&r.active, s.index)
+		}
+		delete(r.senders, s.senderID)
+	} else {
+		if s.index < 0 {
+			// an idle sender starts at the current virtual time of its role, so that it does not accumulate credit
+			// while idle.
+			s.start = r.virtualTime
+			mq.seq++
+			s.seq = mq.seq
+		}
+		s.finish = s.start + s.items[0].cost
+		if s.index < 0 {
+			heap.Push(&r.active, s)
+		} else {
+			heap.Fix(&r.active, s.index)
+		}
+	}
+
+	if r.active.Len() == 0 {
+		if r.index >= 0 {
+			heap.Remove(&mq.active, r.index)
+		}
+		if len(r.senders) == 0 {
+			delete(mq.roles, r.role)
+		}
+		return
+	}
+	if r.index < 0 {
+		r.start = mq.virtualTime
+		mq.seq++
+		r.seq = mq.seq
+	}
+	r.finish = r.start + r.active[0].items[0].cost/r.weight
+	if r.index < 0 {
+		heap.Push(&mq.active, r)
+	} else {
+		heap.Fix(&mq.active, r.index)
+	}
+}
+
+// longestSenderQueue returns the sender with the most messages in the queue, preferring the given sender on ties.
+// The caller must hold the lock of the queue.
+func (mq *FairMessageQueue) longestSenderQueue(preferred *senderQueue) *senderQueue {
+	longest := preferred
+	for _, r := range mq.roles {
+		for _, s := range r.senders {
+			if s.items.Len() > longest.items.Len() {
+				longest = s
+			}
+		}
+	}
+	return longest
+}
+
+// dropLowest makes room for the incoming message by dropping the lowest priority message of the given sender, or the
+// incoming message itself if it belongs to the same sender and does not have a higher priority than the lowest one.
+// Among the messages with the lowest priority, the most recent one is dropped. It returns true if the incoming message
+// should be inserted, and false if it was dropped.
+// The caller must hold the lock of the queue.
+func (mq *FairMessageQueue) dropLowest(s *senderQueue, incoming *item, reason string) bool {
+	incomingSender := incoming.message.(QMessage).SenderID
+	lowest := -1
+	for i, it := range s.items {
+		if lowest == -1 || it.priority < s.items[lowest].priority ||
+			(it.priority == s.items[lowest].priority && it.timestamp.After(s.items[lowest].timestamp)) {
+			lowest = i
+		}
+	}
+
+	if lowest == -1 || (s.senderID == incomingSender && incoming.priority <= s.items[lowest].priority) {
+		mq.metrics.MessageDropped(s.role.label(), reason)
+		return false
+	}
+
+	evicted := heap.Remove(&s.items, lowest).(*item)
+	mq.size--
+	mq.refresh(s)
+	mq.metrics.MessageRemoved(evicted.priority)
+	mq.metrics.MessageDropped(s.role.label(), reason)
+	return true
+}
+
+// schedule is the scheduling state of a role or a sender in the fair queue.
+type schedule struct {
+	start  float64 // virtual start time of the next message.
+	finish float64 // virtual finish time of the next message.
+	seq    uint64  // activation sequence number, used to break ties.
+	index  int     // index in the heap of active roles or senders, -1 if idle.
+}
+
+func newSchedule() schedule {
+	return schedule{index: -1}
+}
+
+func (s *schedule) sched() *schedule {
+	return s
+}
+
+// roleQueue is the queue of the messages of all senders with the same role.
+type roleQueue struct {
+	schedule
+	role        flow.Role
+	weight      float64
+	senders     map[flow.Identifier]*senderQueue
+	active      schedHeap[*senderQueue] // senders with pending messages, ordered by the virtual finish time of their next message.
+	virtualTime float64                 // virtual time of the role, i.e., the finish time of the last served sender.
+}
+
+// label returns the label of the role in the metrics.
+func (r *roleQueue) label() string {
+	if r.role == flow.Role(0) {
+		return "unknown"
+	}
+	return r.role.String()
+}
+
+// senderQueue is the queue of the messages of a single sender, in priority order.
+type senderQueue struct {
+	schedule
+	senderID flow.Identifier
+	role     *roleQueue
+	items    priorityQueue
+}
+
+// scheduled is implemented by the roles and senders of the fair queue.
+type scheduled interface {
+	sched() *schedule
+}
+
+// schedHeap is a min-heap of roles or senders ordered by the virtual finish time of their next message.
+type schedHeap[T scheduled] []T
+
+func (h schedHeap[T]) Len() int { return len(h) }
+
+func (h schedHeap[T]) Less(i, j int) bool {
+	a, b := h[i].sched(), h[j].sched()
+	if a.finish != b.finish {
+		return a.finish < b.finish
+	}
+	return a.seq < b.seq
+}
+
+func (h schedHeap[T]) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].sched().index = i
+	h[j].sched().index = j
+}
+
+func (h *schedHeap[T]) Push(x interface{}) {
+	e := x.(T)
+	e.sched().index = len(*h)
+	*h = append(*h, e)
+}
+
+func (h *schedHeap[T]) Pop() interface{} {
+	old := *h
+	n := len(old)
+	e := old[n-1]
+	var zero T
+	old[n-1] = zero // avoid memory leak
+	e.sched().index = -1
+	*h = old[:n-1]
+	return e
+}
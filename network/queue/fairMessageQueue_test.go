@@ -0,0 +1,240 @@
+package queue_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/module/id"
+	"github.com/onflow/flow-go/module/metrics"
+	"github.com/onflow/flow-go/network/channels"
+	"github.com/onflow/flow-go/network/queue"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+// dropCountingMetrics counts the messages dropped by the queue per sender role.
+type dropCountingMetrics struct {
+	*metrics.NoopCollector
+	mu      sync.Mutex
+	dropped map[string]int
+}
+
+func newDropCountingMetrics() *dropCountingMetrics {
+	return &dropCountingMetrics{
+		NoopCollector: metrics.NewNoopCollector(),
+		dropped:       make(map[string]int),
+	}
+}
+
+func (m *dropCountingMetrics) MessageDropped(senderRole string, _ string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.dropped[senderRole]++
+}
+
+func (m *dropCountingMetrics) Dropped(role flow.Role) int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.dropped[role.String()]
+}
+
+// fairQueueMessage returns a queue message of the sender with the given priority on the given channel. The priority
+// is carried in the payload, see payloadPriority.
+func fairQueueMessage(sender flow.Identifier, channel channels.Channel, priority queue.Priority) queue.QMessage {
+	return queue.QMessage{
+		Payload:  priority,
+		Target:   channel,
+		SenderID: sender,
+	}
+}
+
+// payloadPriority returns the priority carried in the payload of the queue message.
+func payloadPriority(message interface{}) (queue.Priority, error) {
+	return message.(queue.QMessage).Payload.(queue.Priority), nil
+}
+
+func identityWithRole(role flow.Role) *flow.Identity {
+	return &flow.Identity{NodeID: unittest.IdentifierFixture(), Role: role}
+}
+
+func newFairQueue(t *testing.T, config queue.FairQueueConfig, m *dropCountingMetrics, ids ...*flow.Identity) *queue.FairMessageQueue {
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	mq, err := queue.NewFairMessageQueue(ctx, payloadPriority, id.NewFixedIdentityProvider(ids), m, config)
+	require.NoError(t, err)
+	return mq
+}
+
+// removeN removes n messages from the queue and returns the number of removed messages per sender.
+func removeN(t *testing.T, mq *queue.FairMessageQueue, n int) map[flow.Identifier]int {
+	removed := make(map[flow.Identifier]int)
+	for i := 0; i < n; i++ {
+		msg := mq.Remove()
+		require.NotNil(t, msg)
+		removed[msg.(queue.QMessage).SenderID]++
+	}
+	return removed
+}
+
+// TestFairQueue_NoisySenderDoesNotStarveOthers evaluates that the messages of a sender are served alongside the messages
+// of a sender flooding the queue, rather than after them.
+func TestFairQueue_NoisySenderDoesNotStarveOthers(t *testing.T) {
+	noisy, honest := identityWithRole(flow.RoleConsensus), identityWithRole(flow.RoleConsensus)
+	mq := newFairQueue(t, queue.DefaultFairQueueConfig(), newDropCountingMetrics(), noisy, honest)
+
+	for i := 0; i < 500; i++ {
+		require.NoError(t, mq.Insert(fairQueueMessage(noisy.NodeID, channels.ConsensusCommittee, queue.HighPriority)))
+	}
+	for i := 0; i < 10; i++ {
+		require.NoError(t, mq.Insert(fairQueueMessage(honest.NodeID, channels.ConsensusCommittee, queue.HighPriority)))
+	}
+
+	// both senders share the queue equally, hence all messages of the honest sender are served among the first 20.
+	removed := removeN(t, mq, 20)
+	require.Equal(t, 10, removed[honest.NodeID])
+	require.Equal(t, 10, removed[noisy.NodeID])
+	require.Equal(t, 490, mq.Len())
+}
+
+// TestFairQueue_RoleWeights evaluates that roles share the queue proportionally to their weights, regardless of the
+// number of senders of each role.
+func TestFairQueue_RoleWeights(t *testing.T) {
+	consensus := identityWithRole(flow.RoleConsensus)
+	executions := []*flow.Identity{identityWithRole(flow.RoleExecution), identityWithRole(flow.RoleExecution), identityWithRole(flow.RoleExecution)}
+
+	config := queue.DefaultFairQueueConfig()
+	config.RoleWeights[flow.RoleConsensus] = 2
+	mq := newFairQueue(t, config, newDropCountingMetrics(), append(executions, consensus)...)
+
+	for i := 0; i < 100; i++ {
+		require.NoError(t, mq.Insert(fairQueueMessage(consensus.NodeID, channels.ConsensusCommittee, queue.MediumPriority)))
+		for _, execution := range executions {
+			require.NoError(t, mq.Insert(fairQueueMessage(execution.NodeID, channels.PushReceipts, queue.MediumPriority)))
+		}
+	}
+
+	removed := removeN(t, mq, 90)
+	require.Equal(t, 60, removed[consensus.NodeID])
+	for _, execution := range executions {
+		require.Equal(t, 10, removed[execution.NodeID])
+	}
+}
+
+// TestFairQueue_PriorityAndChannelWeights evaluates that the messages of a sender are served in priority order, and that
+// the priority and channel weight of the messages determine the share of the queue of their senders.
+func TestFairQueue_PriorityAndChannelWeights(t *testing.T) {
+	a, b := identityWithRole(flow.RoleConsensus), identityWithRole(flow.RoleConsensus)
+	config := queue.DefaultFairQueueConfig()
+	config.ChannelWeights[channels.ConsensusCommittee] = 2
+	mq := newFairQueue(t, config, newDropCountingMetrics(), a, b)
+
+	// messages of a sender are served in priority order.
+	require.NoError(t, mq.Insert(fairQueueMessage(a.NodeID, channels.SyncCommittee, queue.LowPriority)))
+	require.NoError(t, mq.Insert(fairQueueMessage(a.NodeID, channels.SyncCommittee, queue.HighPriority)))
+	require.Equal(t, queue.HighPriority, mq.Remove().(queue.QMessage).Payload)
+	require.Equal(t, queue.LowPriority, mq.Remove().(queue.QMessage).Payload)
+
+	// messages on a channel with twice the weight cost half as much.
+	for i := 0; i < 30; i++ {
+		require.NoError(t, mq.Insert(fairQueueMessage(a.NodeID, channels.ConsensusCommittee, queue.LowPriority)))
+		require.NoError(t, mq.Insert(fairQueueMessage(b.NodeID, channels.SyncCommittee, queue.LowPriority)))
+	}
+	removed := removeN(t, mq, 30)
+	require.Equal(t, 20, removed[a.NodeID])
+	require.Equal(t, 10, removed[b.NodeID])
+}
+
+// TestFairQueue_DropPolicy evaluates that the queue of a sender is bounded, and that a full queue drops the messages of
+// the sender with the most queued messages.
+func TestFairQueue_DropPolicy(t *testing.T) {
+	// the senders have different roles, so that their drops can be told apart by the metrics.
+	noisy, honest := identityWithRole(flow.RoleExecution), identityWithRole(flow.RoleVerification)
+	config := queue.DefaultFairQueueConfig()
+	config.MaxMessagesPerSender = 15
+	config.MaxSize = 20
+	m := newDropCountingMetrics()
+	mq := newFairQueue(t, config, m, noisy, honest)
+
+	// the queue of the noisy sender is bounded, and its extra messages of the same priority are dropped.
+	for i := 0; i < 20; i++ {
+		require.NoError(t, mq.Insert(fairQueueMessage(noisy.NodeID, channels.PushReceipts, queue.LowPriority)))
+	}
+	require.Equal(t, 15, mq.Len())
+	require.Equal(t, 5, m.Dropped(noisy.Role))
+
+	// a higher priority message of the noisy sender replaces one of its lower priority messages.
+	require.NoError(t, mq.Insert(fairQueueMessage(noisy.NodeID, channels.PushReceipts, queue.HighPriority)))
+	require.Equal(t, 15, mq.Len())
+	require.Equal(t, 6, m.Dropped(noisy.Role))
+
+	// once the queue is full, the messages of the noisy sender are evicted in favor of the honest sender.
+	for i := 0; i < 10; i++ {
+		require.NoError(t, mq.Insert(fairQueueMessage(honest.NodeID, channels.PushReceipts, queue.LowPriority)))
+	}
+	require.Equal(t, 20, mq.Len())
+	require.Equal(t, 0, m.Dropped(honest.Role))
+	require.Equal(t, 11, m.Dropped(noisy.Role))
+
+	removed := removeN(t, mq, 20)
+	require.Equal(t, 10, removed[honest.NodeID])
+	require.Equal(t, 10, removed[noisy.NodeID])
+}
+
+// TestFairQueue_UnknownSenders evaluates that senders unknown to the identity provider are queued, and that an
+// invalid message is rejected.
+func TestFairQueue_UnknownSenders(t *testing.T) {
+	mq := newFairQueue(t, queue.DefaultFairQueueConfig(), newDropCountingMetrics())
+
+	sender := unittest.IdentifierFixture()
+	require.NoError(t, mq.Insert(fairQueueMessage(sender, channels.TestNetworkChannel, queue.LowPriority)))
+	require.Equal(t, sender, mq.Remove().(queue.QMessage).SenderID)
+
+	require.Error(t, mq.Insert("not a queue message"))
+}
+
+// TestFairQueue_InvalidConfig evaluates that a queue which would drop all messages, or with non-positive weights,
+// cannot be created.
+func TestFairQueue_InvalidConfig(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	for name, apply := range map[string]func(*queue.FairQueueConfig){
+		"zero max messages per sender":     func(c *queue.FairQueueConfig) { c.MaxMessagesPerSender = 0 },
+		"negative max messages per sender": func(c *queue.FairQueueConfig) { c.MaxMessagesPerSender = -1 },
+		"zero max size":                    func(c *queue.FairQueueConfig) { c.MaxSize = 0 },
+		"negative max size":                func(c *queue.FairQueueConfig) { c.MaxSize = -1 },
+		"zero role weight":                 func(c *queue.FairQueueConfig) { c.RoleWeights[flow.RoleConsensus] = 0 },
+		"negative channel weight":          func(c *queue.FairQueueConfig) { c.ChannelWeights[channels.PushReceipts] = -1 },
+	} {
+		t.Run(name, func(t *testing.T) {
+			config := queue.DefaultFairQueueConfig()
+			apply(&config)
+			require.Error(t, config.Validate())
+			_, err := queue.NewFairMessageQueue(ctx, payloadPriority, id.NewFixedIdentityProvider(nil), metrics.NewNoopCollector(), config)
+			require.Error(t, err)
+		})
+	}
+
+	require.NoError(t, queue.DefaultFairQueueConfig().Validate())
+}
+
+// TestFairQueue_ParseWeights evaluates the parsing of the role and channel weights.
+func TestFairQueue_ParseWeights(t *testing.T) {
+	roleWeights, err := queue.ParseRoleWeights(map[string]string{"consensus": "2", "execution": "0.5"})
+	require.NoError(t, err)
+	require.Equal(t, map[flow.Role]float64{flow.RoleConsensus: 2, flow.RoleExecution: 0.5}, roleWeights)
+
+	channelWeights, err := queue.ParseChannelWeights(map[string]string{channels.ConsensusCommittee.String(): "3"})
+	require.NoError(t, err)
+	require.Equal(t, map[channels.Channel]float64{channels.ConsensusCommittee: 3}, channelWeights)
+
+	for _, invalid := range []map[string]string{{"consensus": "0"}, {"consensus": "-1"}, {"consensus": "x"}, {"unknown": "1"}} {
+		_, err := queue.ParseRoleWeights(invalid)
+		require.Error(t, err)
+	}
+	_, err = queue.ParseChannelWeights(map[string]string{"not-a-channel": "1"})
+	require.Error(t, err)
+}
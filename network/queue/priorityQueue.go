@@ -8,6 +8,7 @@ type item struct {
 	// The index is needed by update and is maintained by the heap.Interface methods.
 	index     int       // The index of the item in the heap.
 	timestamp time.Time // timestamp to maintain insertions order for items with the same priority and for telemetry
+	cost      float64   // cost of the item in the virtual time of the fair queue, unused by the plain priority queue.
 
 }
 
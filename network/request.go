@@ -0,0 +1,93 @@
+package network
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/onflow/flow-go/model/flow"
+)
+
+// DefaultRequestTimeout is the time Conduit.Request waits for the response to a request, unless the context of the
+// request specifies an earlier deadline.
+const DefaultRequestTimeout = 10 * time.Second
+
+// RequestMessage is a message sent using Conduit.Request. The request ID of the message correlates the request with
+// its response, hence it must be unique among the requests in flight to the same target on the same channel.
+type RequestMessage interface {
+	// RequestID returns the identifier of the request, which is echoed by its response.
+	RequestID() uint64
+}
+
+// ResponseMessage is a message sent using Conduit.Respond in response to a RequestMessage.
+type ResponseMessage interface {
+	// ResponseTo returns the identifier of the request the message responds to.
+	ResponseTo() uint64
+}
+
+// RequestAs sends the request to the target using the conduit, and returns its response as the expected response
+// type R. It is a typed wrapper around Conduit.Request.
+// Expected error returns during normal operations:
+//   - UnexpectedResponseError if the target responded with a message of a different type.
+//   - all the errors returned by Conduit.Request.
+func RequestAs[R ResponseMessage](ctx context.Context, con Conduit, request RequestMessage, targetID flow.Identifier) (R, error) {
+	var typed R
+	response, err := con.Request(ctx, request, targetID)
+	if err != nil {
+		return typed, err
+	}
+	typed, ok := response.(R)
+	if !ok {
+		return typed, NewUnexpectedResponseError(request, response, typed)
+	}
+	return typed, nil
+}
+
+// UnexpectedResponseError indicates that the response to a request is not of the expected type.
+type UnexpectedResponseError struct {
+	requestType  string
+	responseType string
+	expectedType string
+}
+
+// NewUnexpectedResponseError returns a new UnexpectedResponseError.
+func NewUnexpectedResponseError(request RequestMessage, response ResponseMessage, expected ResponseMessage) UnexpectedResponseError {
+	return UnexpectedResponseError{
+		requestType:  fmt.Sprintf("%T", request),
+		responseType: fmt.Sprintf("%T", response),
+		expectedType: fmt.Sprintf("%T", expected),
+	}
+}
+
+func (e UnexpectedResponseError) Error() string {
+	return fmt.Sprintf("unexpected response of type %s to request of type %s (expected %s)", e.responseType, e.requestType, e.expectedType)
+}
+
+// IsUnexpectedResponseError returns whether the given error is an UnexpectedResponseError.
+func IsUnexpectedResponseError(err error) bool {
+	var e UnexpectedResponseError
+	return errors.As(err, &e)
+}
+
+// DuplicateRequestError indicates that a request with the same request ID is already in flight to the same target
+// on the same channel.
+type DuplicateRequestError struct {
+	requestID uint64
+	targetID  flow.Identifier
+}
+
+// NewDuplicateRequestError returns a new DuplicateRequestError.
+func NewDuplicateRequestError(requestID uint64, targetID flow.Identifier) DuplicateRequestError {
+	return DuplicateRequestError{requestID: requestID, targetID: targetID}
+}
+
+func (e DuplicateRequestError) Error() string {
+	return fmt.Sprintf("request %d to %x is already in flight", e.requestID, e.targetID)
+}
+
+// IsDuplicateRequestError returns whether the given error is a DuplicateRequestError.
+func IsDuplicateRequestError(err error) bool {
+	var e DuplicateRequestError
+	return errors.As(err, &e)
+}
@@ -0,0 +1,107 @@
+package stub
+
+import (
+	"math/rand"
+	"time"
+
+	"github.com/onflow/flow-go/model/flow"
+)
+
+// LatencyDistribution is the distribution the Simulator samples the latency of a message on a link from.
+type LatencyDistribution interface {
+	// Sample returns a latency sampled from the distribution using the given source of randomness.
+	// The returned latency must be non-negative.
+	Sample(rng *rand.Rand) time.Duration
+}
+
+// ConstantLatency is a latency distribution that always returns the same latency.
+type ConstantLatency time.Duration
+
+var _ LatencyDistribution = ConstantLatency(0)
+
+func (c ConstantLatency) Sample(_ *rand.Rand) time.Duration {
+	return time.Duration(c)
+}
+
+// UniformLatency is a latency distribution that returns latencies uniformly distributed in [Min, Max).
+type UniformLatency struct {
+	Min time.Duration
+	Max time.Duration
+}
+
+var _ LatencyDistribution = UniformLatency{}
+
+func (u UniformLatency) Sample(rng *rand.Rand) time.Duration {
+	if u.Max <= u.Min {
+		return u.Min
+	}
+	return u.Min + time.Duration(rng.Int63n(int64(u.Max-u.Min)))
+}
+
+// NormalLatency is a latency distribution that returns normally distributed latencies with the given mean and standard
+// deviation. Negative samples are truncated to zero.
+type NormalLatency struct {
+	Mean   time.Duration
+	StdDev time.Duration
+}
+
+var _ LatencyDistribution = NormalLatency{}
+
+func (n NormalLatency) Sample(rng *rand.Rand) time.Duration {
+	latency := time.Duration(rng.NormFloat64()*float64(n.StdDev)) + n.Mean
+	if latency < 0 {
+		return 0
+	}
+	return latency
+}
+
+// LinkConfig defines the behavior of the directed link between two nodes of the Simulator.
+type LinkConfig struct {
+	// Latency is the distribution of the latency of the messages sent on the link. A nil distribution
+	// delivers the messages without latency.
+	Latency LatencyDistribution
+	// LossRate is the probability in [0, 1] that a message sent on the link is dropped.
+	LossRate float64
+	// DuplicationRate is the probability in [0, 1] that a message sent on the link is delivered twice.
+	// The duplicate is delivered even if the receiver has already seen the message, and its latency is
+	// sampled independently of the original message.
+	DuplicationRate float64
+	// ReorderRate is the probability in [0, 1] that a message sent on the link is held back for an additional
+	// delay uniformly distributed in [0, ReorderDelay), so that messages sent after it on the link may overtake it.
+	ReorderRate float64
+	// ReorderDelay is the maximum additional delay of the messages held back for reordering.
+	ReorderDelay time.Duration
+}
+
+// Link is a directed link between two nodes of the Simulator.
+type Link struct {
+	From flow.Identifier
+	To   flow.Identifier
+}
+
+// Partition is a network partition active during the virtual time interval [Start, End).
+// While the partition is active, messages between nodes of different groups are dropped, both when they are sent and
+// when they are due for delivery. Nodes that are not listed in any group are not affected by the partition.
+type Partition struct {
+	Start  time.Duration
+	End    time.Duration
+	Groups []flow.IdentifierList
+}
+
+// separates returns true if the partition is active at the given virtual time, and the two nodes belong to
+// different groups of the partition.
+func (p Partition) separates(now time.Duration, from flow.Identifier, to flow.Identifier) bool {
+	if now < p.Start || now >= p.End {
+		return false
+	}
+	fromGroup, toGroup := -1, -1
+	for i, group := range p.Groups {
+		if group.Contains(from) {
+			fromGroup = i
+		}
+		if group.Contains(to) {
+			toGroup = i
+		}
+	}
+	return fromGroup != -1 && toGroup != -1 && fromGroup != toGroup
+}
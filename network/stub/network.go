@@ -14,6 +14,7 @@ import (
 	"github.com/onflow/flow-go/model/flow"
 	"github.com/onflow/flow-go/network"
 	"github.com/onflow/flow-go/network/channels"
+	"github.com/onflow/flow-go/network/internal/requests"
 	"github.com/onflow/flow-go/network/mocknetwork"
 	"github.com/onflow/flow-go/network/p2p/conduit"
 )
@@ -32,6 +33,7 @@ type Network struct {
 	engines        map[channels.Channel]network.MessageProcessor // used to keep track of attached engines of the node.
 	seenEventIDs   map[string]struct{}                           // used to keep track of event IDs seen by attached engines.
 	qCD            chan struct{}                                 // used to stop continuous delivery mode of the Network.
+	requests       *requests.Tracker                             // used to route delivered responses to the requests in flight.
 	conduitFactory network.ConduitFactory
 }
 
@@ -52,6 +54,7 @@ func NewNetwork(t testing.TB, myId flow.Identifier, hub *Hub, opts ...func(*Netw
 		engines:        make(map[channels.Channel]network.MessageProcessor),
 		seenEventIDs:   make(map[string]struct{}),
 		qCD:            make(chan struct{}),
+		requests:       requests.NewTracker(),
 		conduitFactory: conduit.NewDefaultConduitFactory(),
 	}
 
@@ -141,6 +144,15 @@ func (n *Network) UnicastOnChannel(channel channels.Channel, event interface{},
 	return nil
 }
 
+// RequestOnChannel is called when the attached Engine to the channel is sending a request to a single target Engine
+// attached to the same channel on another node. It blocks until the response of the target is delivered, or until
+// the context is done, hence the messages must be delivered concurrently to the request.
+func (n *Network) RequestOnChannel(ctx context.Context, channel channels.Channel, request network.RequestMessage, targetID flow.Identifier) (network.ResponseMessage, error) {
+	return n.requests.Request(ctx, channel, request, targetID, func() error {
+		return n.UnicastOnChannel(channel, request, targetID)
+	})
+}
+
 // publish is called when the attached Engine is sending an event to a group of Engines attached to the
 // same channel on other nodes based on selector.
 // In this test helper implementation, publish uses submit method under the hood.
@@ -252,6 +264,27 @@ func (n *Network) processWithEngine(syncOnProcess bool, key string, m *PendingMe
 	}
 	n.seenEventIDs[key] = struct{}{}
 
+	return n.deliverToEngine(syncOnProcess, m)
+}
+
+// processDuplicateWithEngine delivers a duplicate of an already delivered message to the engine attached to its channel.
+// Contrary to processWithEngine, the message is delivered even if the node has already seen it, hence it allows the
+// Simulator to exercise the engines against duplicate messages.
+func (n *Network) processDuplicateWithEngine(syncOnProcess bool, m *PendingMessage) error {
+	n.Lock()
+	defer n.Unlock()
+
+	return n.deliverToEngine(syncOnProcess, m)
+}
+
+// deliverToEngine delivers the message to the engine attached to its channel.
+// The caller must hold the lock of the Network.
+func (n *Network) deliverToEngine(syncOnProcess bool, m *PendingMessage) error {
+	// responses to the requests in flight are handed over to the requests, rather than to the engine
+	if n.requests.Deliver(m.Channel, m.From, m.Event) {
+		return nil
+	}
+
 	receiverEngine, ok := n.engines[m.Channel]
 	if !ok {
 		return fmt.Errorf("could find engine ID: %v", m.Channel)
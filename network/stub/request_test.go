@@ -0,0 +1,78 @@
+package stub
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/atomic"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/model/messages"
+	"github.com/onflow/flow-go/network"
+	"github.com/onflow/flow-go/network/channels"
+)
+
+// respondingEngine responds to the entity requests it receives with an empty entity response, and counts the entity
+// responses delivered to it as regular messages.
+type respondingEngine struct {
+	con       network.Conduit
+	responses atomic.Uint64
+}
+
+func (e *respondingEngine) Process(_ channels.Channel, originID flow.Identifier, event interface{}) error {
+	switch msg := event.(type) {
+	case *messages.EntityRequest:
+		return e.con.Respond(&messages.EntityResponse{Nonce: msg.Nonce, EntityIDs: msg.EntityIDs}, originID)
+	case *messages.EntityResponse:
+		e.responses.Inc()
+	}
+	return nil
+}
+
+// TestRequest_Response evaluates that the response to a request is returned to the requester rather than delivered to
+// its engine, and that a response which is not awaited anymore is delivered to the engine.
+func TestRequest_Response(t *testing.T) {
+	hub := NewNetworkHub()
+	requesterNet := NewNetwork(t, flow.Identifier{1}, hub)
+	providerNet := NewNetwork(t, flow.Identifier{2}, hub)
+
+	requester, provider := &respondingEngine{}, &respondingEngine{}
+	var err error
+	requester.con, err = requesterNet.Register(channels.RequestCollections, requester)
+	require.NoError(t, err)
+	provider.con, err = providerNet.Register(channels.RequestCollections, provider)
+	require.NoError(t, err)
+
+	request := &messages.EntityRequest{Nonce: 1, EntityIDs: []flow.Identifier{{3}}}
+	responses := make(chan *messages.EntityResponse, 1)
+	go func() {
+		response, err := network.RequestAs[*messages.EntityResponse](context.Background(), requester.con, request, providerNet.GetID())
+		require.NoError(t, err)
+		responses <- response
+	}()
+
+	// the request and its response are delivered once the request is sent.
+	var response *messages.EntityResponse
+	require.Eventually(t, func() bool {
+		requesterNet.DeliverAll(true)
+		select {
+		case response = <-responses:
+			return true
+		default:
+			return false
+		}
+	}, time.Second, 10*time.Millisecond)
+	require.Equal(t, request.Nonce, response.Nonce)
+	require.Equal(t, request.EntityIDs, response.EntityIDs)
+	require.Zero(t, requester.responses.Load())
+
+	// the response to a timed out request is delivered to the engine.
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err = requester.con.Request(ctx, &messages.EntityRequest{Nonce: 2}, providerNet.GetID())
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	requesterNet.DeliverAll(true)
+	require.Equal(t, uint64(1), requester.responses.Load())
+}
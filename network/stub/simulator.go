@@ -0,0 +1,455 @@
+package stub
+
+import (
+	"bytes"
+	"container/heap"
+	"fmt"
+	"math/rand"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/onflow/flow-go/model/flow"
+)
+
+// Simulator is a deterministic delivery mode of the stub network. Instead of delivering the buffered messages of the
+// Hub instantly and in order, it schedules each message on a virtual clock according to the configuration of the link
+// between the sender and the receiver, which allows injecting latency, loss, duplication, reordering and partitions.
+//
+// All random decisions are drawn from a source seeded with the seed of the Simulator, and the messages buffered
+// between two scheduling rounds are sorted by sender, receiver, channel and content before the decisions are drawn.
+// Hence, the decisions do not depend on the order in which the engines happened to send the messages, and running the
+// same test with the same seed makes the same decisions. Every decision is recorded in a trace, which can be written
+// on test failure (see WriteTraceFile) and replayed with WithReplay, in which case the decisions are taken from the
+// trace instead of the random source.
+//
+// The clock of the Simulator is virtual, and only governs the delivery of the messages: the engines' own timers keep
+// running on wall-clock time. For exact reproductions, the engines should be synchronized on the processing of the
+// messages (see WithSyncOnProcess).
+//
+// The Simulator takes over the delivery of the Hub's buffered messages, hence it must not be combined with the
+// DeliverAll methods of the Hub and the Networks attached to it.
+type Simulator struct {
+	mu            sync.Mutex
+	hub           *Hub
+	seed          int64
+	rng           *rand.Rand
+	defaultLink   LinkConfig
+	links         map[Link]LinkConfig
+	partitions    []Partition
+	syncOnProcess bool
+	replay        *replayDecisions
+	replayMisses  uint64
+
+	now         time.Duration            // current virtual time.
+	seq         uint64                   // sequence number of the last scheduled delivery, used to break ties.
+	scheduled   deliveryQueue            // deliveries ordered by virtual time.
+	occurrences map[linkMessageID]uint64 // number of times each message was sent on each link.
+	trace       []TraceEvent             // recorded trace of the simulation.
+}
+
+// SimulatorOption is a functional option of the Simulator.
+type SimulatorOption func(*Simulator)
+
+// WithDefaultLink sets the configuration of the links that are not configured explicitly with WithLink.
+// By default, links deliver all messages without latency.
+func WithDefaultLink(config LinkConfig) SimulatorOption {
+	return func(s *Simulator) {
+		s.defaultLink = config
+	}
+}
+
+// WithLink sets the configuration of the directed link from one node to another.
+func WithLink(from flow.Identifier, to flow.Identifier, config LinkConfig) SimulatorOption {
+	return func(s *Simulator) {
+		s.links[Link{From: from, To: to}] = config
+	}
+}
+
+// WithPartition partitions the nodes into the given groups during the virtual time interval [start, end).
+func WithPartition(start time.Duration, end time.Duration, groups ...flow.IdentifierList) SimulatorOption {
+	return func(s *Simulator) {
+		s.partitions = append(s.partitions, Partition{Start: start, End: end, Groups: groups})
+	}
+}
+
+// WithSyncOnProcess synchronizes the Simulator with the processing of the delivered messages by the engines, i.e.,
+// the delivery of a message returns once the engine of the receiver has processed it. Otherwise, the Simulator is
+// synchronized on the delivery of the messages only.
+func WithSyncOnProcess() SimulatorOption {
+	return func(s *Simulator) {
+		s.syncOnProcess = true
+	}
+}
+
+// WithReplay replays the delivery decisions recorded in the given trace instead of drawing them randomly. Messages
+// that are not part of the trace are delivered without latency, and counted by ReplayMisses.
+func WithReplay(trace []TraceEvent) SimulatorOption {
+	return func(s *Simulator) {
+		s.replay = newReplayDecisions(trace)
+	}
+}
+
+// NewSimulator creates a Simulator delivering the messages of the Networks attached to the Hub.
+func NewSimulator(hub *Hub, seed int64, opts ...SimulatorOption) *Simulator {
+	s := &Simulator{
+		hub:         hub,
+		seed:        seed,
+		rng:         rand.New(rand.NewSource(seed)),
+		links:       make(map[Link]LinkConfig),
+		occurrences: make(map[linkMessageID]uint64),
+		trace:       make([]TraceEvent, 0),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// Seed returns the seed of the Simulator.
+func (s *Simulator) Seed() int64 {
+	return s.seed
+}
+
+// Now returns the current virtual time of the Simulator.
+func (s *Simulator) Now() time.Duration {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.now
+}
+
+// Trace returns a copy of the trace recorded so far.
+func (s *Simulator) Trace() []TraceEvent {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	trace := make([]TraceEvent, len(s.trace))
+	copy(trace, s.trace)
+	return trace
+}
+
+// ReplayMisses returns the number of messages that were not found in the replayed trace, i.e., the number of times the
+// replayed execution diverged from the recorded one.
+func (s *Simulator) ReplayMisses() uint64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.replayMisses
+}
+
+// Step schedules the messages buffered so far, advances the virtual clock to the next scheduled delivery, and delivers
+// all messages scheduled at that time. It returns false if there is no message to deliver.
+func (s *Simulator) Step() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.schedulePending()
+	if s.scheduled.Len() == 0 {
+		return false
+	}
+	at := s.scheduled[0].at
+	s.now = at
+	for s.scheduled.Len() > 0 && s.scheduled[0].at == at {
+		s.deliver(heap.Pop(&s.scheduled).(*scheduledDelivery))
+	}
+	return true
+}
+
+// AdvanceBy advances the virtual clock by the given duration, delivering all messages that are due in the meantime,
+// including the messages sent by the receivers in response, if they are due in time.
+func (s *Simulator) AdvanceBy(d time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	target := s.now + d
+	for {
+		s.schedulePending()
+		if s.scheduled.Len() == 0 || s.scheduled[0].at > target {
+			break
+		}
+		next := heap.Pop(&s.scheduled).(*scheduledDelivery)
+		s.now = next.at
+		s.deliver(next)
+	}
+	s.now = target
+}
+
+// RunUntilIdle delivers messages until there is no message left to deliver. Similar to the recursive delivery of the
+// Hub, it does not return as long as the engines keep responding to the delivered messages.
+func (s *Simulator) RunUntilIdle() {
+	for s.Step() {
+	}
+}
+
+// AdvanceUntil advances the virtual clock by `step` at every `tick` interval of wall-clock time, until the condition is
+// satisfied. It returns an error if the condition is not satisfied within `waitFor` of wall-clock time.
+func (s *Simulator) AdvanceUntil(condition func() bool, waitFor time.Duration, tick time.Duration, step time.Duration) error {
+	timeout := time.NewTimer(waitFor)
+	defer timeout.Stop()
+	ticker := time.NewTicker(tick)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-timeout.C:
+			return fmt.Errorf("condition not satisfied within %v (virtual time %v)", waitFor, s.Now())
+		case <-ticker.C:
+			s.AdvanceBy(step)
+			if condition() {
+				return nil
+			}
+		}
+	}
+}
+
+// linkSend is a message buffered by a Network, expanded to a single receiver.
+type linkSend struct {
+	msg *PendingMessage
+	key string
+}
+
+// schedulePending takes all buffered messages of the Hub, and schedules their delivery to each of their receivers.
+// The caller must hold the lock of the Simulator.
+func (s *Simulator) schedulePending() {
+	sends := make([]linkSend, 0)
+	for _, m := range s.hub.Buffer.takeAll() {
+		key, err := eventKey(m.From, m.Channel, m.Event)
+		if err != nil {
+			// not expected in practice, as the events are marshalled by the networking layer as well.
+			key = fmt.Sprintf("unkeyed: %v", err)
+		}
+		for _, to := range m.TargetIDs {
+			sends = append(sends, linkSend{
+				msg: &PendingMessage{
+					From:      m.From,
+					Channel:   m.Channel,
+					Event:     m.Event,
+					TargetIDs: []flow.Identifier{to},
+				},
+				key: key,
+			})
+		}
+	}
+
+	// sorts the messages, so that the decisions do not depend on the order in which the messages were buffered.
+	sort.SliceStable(sends, func(i, j int) bool {
+		a, b := sends[i], sends[j]
+		if c := bytes.Compare(a.msg.From[:], b.msg.From[:]); c != 0 {
+			return c < 0
+		}
+		if c := bytes.Compare(a.msg.TargetIDs[0][:], b.msg.TargetIDs[0][:]); c != 0 {
+			return c < 0
+		}
+		if a.msg.Channel != b.msg.Channel {
+			return a.msg.Channel < b.msg.Channel
+		}
+		return a.key < b.key
+	})
+
+	for _, send := range sends {
+		s.schedule(send)
+	}
+}
+
+// schedule draws the delivery decision of the message and schedules its delivery accordingly.
+// The caller must hold the lock of the Simulator.
+func (s *Simulator) schedule(send linkSend) {
+	from, to := send.msg.From, send.msg.TargetIDs[0]
+	sent := linkMessageID{from: from, to: to, key: send.key}
+	link := sent
+	link.occurrence = s.occurrences[sent]
+	s.occurrences[sent]++
+
+	decision := s.decide(link)
+	if decision.dropReason != "" {
+		s.record(TraceEventDropped, send.msg, link, func(e *TraceEvent) {
+			e.Reason = decision.dropReason
+		})
+		return
+	}
+
+	for i, delay := range decision.delays {
+		s.seq++
+		d := &scheduledDelivery{
+			at:        s.now + delay,
+			seq:       s.seq,
+			msg:       send.msg,
+			id:        link,
+			duplicate: i > 0,
+		}
+		heap.Push(&s.scheduled, d)
+		s.record(TraceEventScheduled, send.msg, link, func(e *TraceEvent) {
+			e.DeliverAt = d.at
+			e.Duplicate = d.duplicate
+		})
+	}
+}
+
+// deliveryDecision is the decision of the Simulator on a message sent on a link. A message is either dropped for the
+// given reason, or delivered after each of the delays, the delays beyond the first one being duplicates.
+type deliveryDecision struct {
+	dropReason string
+	delays     []time.Duration
+}
+
+// decide returns the delivery decision of the message.
+// The caller must hold the lock of the Simulator.
+func (s *Simulator) decide(id linkMessageID) deliveryDecision {
+	if s.partitioned(id.from, id.to) {
+		return deliveryDecision{dropReason: dropReasonPartition}
+	}
+
+	if s.replay != nil {
+		decision, ok := s.replay.decision(id)
+		if !ok {
+			s.replayMisses++
+			return deliveryDecision{delays: []time.Duration{0}}
+		}
+		return decision
+	}
+
+	// all random values are drawn regardless of the outcome of the decisions, so that the decisions on a message
+	// do not shift the random values drawn for the following messages.
+	config := s.linkConfig(id.from, id.to)
+	lossRoll := s.rng.Float64()
+	latency := s.sampleLatency(config)
+	duplicationRoll := s.rng.Float64()
+	duplicateLatency := s.sampleLatency(config)
+
+	if lossRoll < config.LossRate {
+		return deliveryDecision{dropReason: dropReasonLoss}
+	}
+	decision := deliveryDecision{delays: []time.Duration{latency}}
+	if duplicationRoll < config.DuplicationRate {
+		decision.delays = append(decision.delays, duplicateLatency)
+	}
+	return decision
+}
+
+// sampleLatency samples the latency of a message on the link, including the additional delay if the message is held
+// back for reordering.
+// The caller must hold the lock of the Simulator.
+func (s *Simulator) sampleLatency(config LinkConfig) time.Duration {
+	latency := time.Duration(0)
+	if config.Latency != nil {
+		latency = config.Latency.Sample(s.rng)
+	}
+	reorderRoll := s.rng.Float64()
+	if config.ReorderDelay > 0 {
+		extra := time.Duration(s.rng.Int63n(int64(config.ReorderDelay)))
+		if reorderRoll < config.ReorderRate {
+			latency += extra
+		}
+	}
+	return latency
+}
+
+// linkConfig returns the configuration of the link from one node to another.
+func (s *Simulator) linkConfig(from flow.Identifier, to flow.Identifier) LinkConfig {
+	if config, ok := s.links[Link{From: from, To: to}]; ok {
+		return config
+	}
+	return s.defaultLink
+}
+
+// partitioned returns true if one of the partitions separates the two nodes at the current virtual time.
+func (s *Simulator) partitioned(from flow.Identifier, to flow.Identifier) bool {
+	for _, partition := range s.partitions {
+		if partition.separates(s.now, from, to) {
+			return true
+		}
+	}
+	return false
+}
+
+// deliver delivers the scheduled message to the Network of its receiver.
+// The caller must hold the lock of the Simulator.
+func (s *Simulator) deliver(d *scheduledDelivery) {
+	if s.partitioned(d.id.from, d.id.to) {
+		s.record(TraceEventDropped, d.msg, d.id, func(e *TraceEvent) {
+			e.Reason = dropReasonPartition
+			e.Duplicate = d.duplicate
+		})
+		return
+	}
+
+	receiverNetwork, ok := s.hub.GetNetwork(d.id.to)
+	if !ok {
+		s.record(TraceEventDropped, d.msg, d.id, func(e *TraceEvent) {
+			e.Reason = dropReasonNoNetwork
+			e.Duplicate = d.duplicate
+		})
+		return
+	}
+
+	var err error
+	if d.duplicate {
+		err = receiverNetwork.processDuplicateWithEngine(s.syncOnProcess, d.msg)
+	} else {
+		err = receiverNetwork.processWithEngine(s.syncOnProcess, d.id.key, d.msg)
+	}
+	if err != nil {
+		s.record(TraceEventDropped, d.msg, d.id, func(e *TraceEvent) {
+			e.Reason = err.Error()
+			e.Duplicate = d.duplicate
+		})
+		return
+	}
+	s.record(TraceEventDelivered, d.msg, d.id, func(e *TraceEvent) {
+		e.Duplicate = d.duplicate
+	})
+}
+
+// record appends an event to the trace.
+// The caller must hold the lock of the Simulator.
+func (s *Simulator) record(eventType TraceEventType, m *PendingMessage, id linkMessageID, fill func(*TraceEvent)) {
+	event := TraceEvent{
+		Type:       eventType,
+		Time:       s.now,
+		From:       id.from,
+		To:         id.to,
+		Channel:    m.Channel,
+		EventType:  fmt.Sprintf("%T", m.Event),
+		EventKey:   id.key,
+		Occurrence: id.occurrence,
+	}
+	fill(&event)
+	s.trace = append(s.trace, event)
+}
+
+// scheduledDelivery is a delivery of a message to a single receiver scheduled by the Simulator.
+type scheduledDelivery struct {
+	at        time.Duration
+	seq       uint64
+	msg       *PendingMessage
+	id        linkMessageID
+	duplicate bool
+}
+
+// deliveryQueue is a min-heap of scheduled deliveries, ordered by virtual time, and by the order of scheduling
+// for deliveries due at the same time.
+type deliveryQueue []*scheduledDelivery
+
+var _ heap.Interface = (*deliveryQueue)(nil)
+
+func (q deliveryQueue) Len() int { return len(q) }
+
+func (q deliveryQueue) Less(i, j int) bool {
+	if q[i].at != q[j].at {
+		return q[i].at < q[j].at
+	}
+	return q[i].seq < q[j].seq
+}
+
+func (q deliveryQueue) Swap(i, j int) { q[i], q[j] = q[j], q[i] }
+
+func (q *deliveryQueue) Push(x interface{}) {
+	*q = append(*q, x.(*scheduledDelivery))
+}
+
+func (q *deliveryQueue) Pop() interface{} {
+	old := *q
+	n := len(old)
+	item := old[n-1]
+	old[n-1] = nil
+	*q = old[:n-1]
+	return item
+}
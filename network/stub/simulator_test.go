@@ -0,0 +1,225 @@
+package stub
+
+import (
+	"bytes"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/model/libp2p/message"
+	"github.com/onflow/flow-go/network/channels"
+)
+
+// recordingEngine is a message processor recording the messages it receives.
+type recordingEngine struct {
+	sync.Mutex
+	received []string
+}
+
+func (e *recordingEngine) Process(_ channels.Channel, originID flow.Identifier, event interface{}) error {
+	e.Lock()
+	defer e.Unlock()
+	e.received = append(e.received, fmt.Sprintf("%x:%s", originID[:2], event.(*message.TestMessage).Text))
+	return nil
+}
+
+func (e *recordingEngine) Received() []string {
+	e.Lock()
+	defer e.Unlock()
+	return append([]string{}, e.received...)
+}
+
+// simulatedNodes creates a hub with n networks, each with a recording engine registered on the test channel.
+// The node identifiers are fixed, so that the runs of the simulator on different hubs are comparable.
+func simulatedNodes(t *testing.T, n int) (*Hub, []*Network, []*recordingEngine) {
+	hub := NewNetworkHub()
+	nets := make([]*Network, 0, n)
+	engines := make([]*recordingEngine, 0, n)
+	for i := 0; i < n; i++ {
+		net := NewNetwork(t, flow.Identifier{byte(i + 1)}, hub)
+		engine := &recordingEngine{}
+		_, err := net.Register(channels.TestNetworkChannel, engine)
+		require.NoError(t, err)
+		nets = append(nets, net)
+		engines = append(engines, engine)
+	}
+	return hub, nets, engines
+}
+
+// sendAll makes every node send `count` distinct messages to every other node. If reversed is true, the messages are
+// buffered in the reverse order.
+func sendAll(t *testing.T, nets []*Network, count int, reversed bool) {
+	sends := make([]func(), 0)
+	for _, from := range nets {
+		for _, to := range nets {
+			if from == to {
+				continue
+			}
+			for i := 0; i < count; i++ {
+				from, to, text := from, to, fmt.Sprintf("msg-%d", i)
+				sends = append(sends, func() {
+					require.NoError(t, from.UnicastOnChannel(channels.TestNetworkChannel, &message.TestMessage{Text: text}, to.GetID()))
+				})
+			}
+		}
+	}
+	for i := range sends {
+		if reversed {
+			sends[len(sends)-1-i]()
+		} else {
+			sends[i]()
+		}
+	}
+}
+
+var unreliableLink = LinkConfig{
+	Latency:         NormalLatency{Mean: 50 * time.Millisecond, StdDev: 20 * time.Millisecond},
+	LossRate:        0.2,
+	DuplicationRate: 0.2,
+	ReorderRate:     0.3,
+	ReorderDelay:    100 * time.Millisecond,
+}
+
+// TestSimulator_Deterministic evaluates that the same seed results in the same deliveries and trace, regardless of the
+// order in which the messages were sent, and that a different seed results in different decisions.
+func TestSimulator_Deterministic(t *testing.T) {
+	run := func(seed int64, reversed bool) ([][]string, []TraceEvent) {
+		hub, nets, engines := simulatedNodes(t, 3)
+		sim := NewSimulator(hub, seed, WithDefaultLink(unreliableLink), WithSyncOnProcess())
+		sendAll(t, nets, 20, reversed)
+		sim.RunUntilIdle()
+
+		received := make([][]string, 0, len(engines))
+		for _, engine := range engines {
+			received = append(received, engine.Received())
+		}
+		return received, sim.Trace()
+	}
+
+	received1, trace1 := run(42, false)
+	received2, trace2 := run(42, true)
+	require.Equal(t, received1, received2)
+	require.Equal(t, trace1, trace2)
+
+	// a different seed makes different decisions.
+	_, trace3 := run(43, false)
+	require.NotEqual(t, trace1, trace3)
+
+	// unreliable links lose and duplicate messages.
+	var dropped, duplicated int
+	for _, event := range trace1 {
+		if event.Type == TraceEventDropped {
+			dropped++
+		}
+		if event.Type == TraceEventDelivered && event.Duplicate {
+			duplicated++
+		}
+	}
+	require.Greater(t, dropped, 0)
+	require.Greater(t, duplicated, 0)
+}
+
+// TestSimulator_Latency evaluates that messages are delivered once the virtual clock reaches their latency.
+func TestSimulator_Latency(t *testing.T) {
+	hub, nets, engines := simulatedNodes(t, 2)
+	sim := NewSimulator(hub, 1,
+		WithLink(nets[0].GetID(), nets[1].GetID(), LinkConfig{Latency: ConstantLatency(100 * time.Millisecond)}),
+		WithSyncOnProcess())
+
+	require.NoError(t, nets[0].UnicastOnChannel(channels.TestNetworkChannel, &message.TestMessage{Text: "first"}, nets[1].GetID()))
+	sim.AdvanceBy(50 * time.Millisecond)
+	require.Empty(t, engines[1].Received())
+
+	// the second message is sent at 50ms, hence it is due at 150ms.
+	require.NoError(t, nets[0].UnicastOnChannel(channels.TestNetworkChannel, &message.TestMessage{Text: "second"}, nets[1].GetID()))
+	sim.AdvanceBy(50 * time.Millisecond)
+	require.Len(t, engines[1].Received(), 1)
+	sim.AdvanceBy(50 * time.Millisecond)
+	require.Len(t, engines[1].Received(), 2)
+	require.Equal(t, 150*time.Millisecond, sim.Now())
+
+	// the third message is due at 250ms, which is reached after the second step of 50ms.
+	require.NoError(t, nets[0].UnicastOnChannel(channels.TestNetworkChannel, &message.TestMessage{Text: "third"}, nets[1].GetID()))
+	err := sim.AdvanceUntil(func() bool {
+		return len(engines[1].Received()) == 3
+	}, time.Second, time.Millisecond, 50*time.Millisecond)
+	require.NoError(t, err)
+	require.Equal(t, 250*time.Millisecond, sim.Now())
+
+	// no further message is sent, hence the condition is never satisfied.
+	err = sim.AdvanceUntil(func() bool {
+		return len(engines[1].Received()) == 4
+	}, 50*time.Millisecond, time.Millisecond, 50*time.Millisecond)
+	require.Error(t, err)
+}
+
+// TestSimulator_Partition evaluates that messages sent across a partition, or due during the partition, are dropped,
+// while messages sent after the partition heals are delivered.
+func TestSimulator_Partition(t *testing.T) {
+	hub, nets, engines := simulatedNodes(t, 3)
+	a, b, c := nets[0].GetID(), nets[1].GetID(), nets[2].GetID()
+	sim := NewSimulator(hub, 1,
+		WithDefaultLink(LinkConfig{Latency: ConstantLatency(10 * time.Millisecond)}),
+		WithPartition(100*time.Millisecond, 200*time.Millisecond, flow.IdentifierList{a, b}, flow.IdentifierList{c}),
+		WithSyncOnProcess())
+
+	send := func(from *Network, to flow.Identifier, text string) {
+		require.NoError(t, from.UnicastOnChannel(channels.TestNetworkChannel, &message.TestMessage{Text: text}, to))
+	}
+
+	// due during the partition.
+	sim.AdvanceBy(95 * time.Millisecond)
+	send(nets[0], c, "in flight")
+	// sent during the partition, within and across the groups.
+	sim.AdvanceBy(10 * time.Millisecond)
+	send(nets[0], c, "across")
+	send(nets[0], b, "within")
+	// sent after the partition heals.
+	sim.AdvanceBy(100 * time.Millisecond)
+	send(nets[0], c, "healed")
+	sim.RunUntilIdle()
+
+	require.Len(t, engines[1].Received(), 1)
+	require.Len(t, engines[2].Received(), 1)
+	require.Contains(t, engines[2].Received()[0], "healed")
+}
+
+// TestSimulator_Replay evaluates that replaying a recorded trace with a different seed reproduces the same deliveries.
+func TestSimulator_Replay(t *testing.T) {
+	hub, nets, engines := simulatedNodes(t, 3)
+	sim := NewSimulator(hub, 7, WithDefaultLink(unreliableLink), WithSyncOnProcess())
+	sendAll(t, nets, 10, false)
+	sim.RunUntilIdle()
+	recorded := make([][]string, 0)
+	for _, engine := range engines {
+		recorded = append(recorded, engine.Received())
+	}
+
+	buf := new(bytes.Buffer)
+	require.NoError(t, WriteTrace(buf, sim.Trace()))
+	trace, err := ReadTrace(buf)
+	require.NoError(t, err)
+	require.Equal(t, sim.Trace(), trace)
+
+	path, err := sim.WriteTraceFile(t.TempDir(), t.Name())
+	require.NoError(t, err)
+	require.Contains(t, path, "TestSimulator_Replay-seed-7.trace")
+	trace, err = ReadTraceFile(path)
+	require.NoError(t, err)
+	require.Equal(t, sim.Trace(), trace)
+
+	hub, nets, engines = simulatedNodes(t, 3)
+	replay := NewSimulator(hub, 8, WithDefaultLink(unreliableLink), WithReplay(trace), WithSyncOnProcess())
+	sendAll(t, nets, 10, true)
+	replay.RunUntilIdle()
+
+	require.Zero(t, replay.ReplayMisses())
+	require.Equal(t, sim.Trace(), replay.Trace())
+	for i, engine := range engines {
+		require.Equal(t, recorded[i], engine.Received())
+	}
+}
@@ -0,0 +1,176 @@
+package stub
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/network/channels"
+)
+
+// TraceEventType is the type of the events recorded in the trace of the Simulator.
+type TraceEventType string
+
+const (
+	// TraceEventScheduled is recorded when a message (or a duplicate of it) is scheduled for delivery on a link.
+	TraceEventScheduled TraceEventType = "scheduled"
+	// TraceEventDropped is recorded when a message is dropped, either when it is sent or when it is due for delivery.
+	TraceEventDropped TraceEventType = "dropped"
+	// TraceEventDelivered is recorded when a message is delivered to the engine of the receiver.
+	TraceEventDelivered TraceEventType = "delivered"
+)
+
+// reasons for dropping messages recorded in the trace.
+const (
+	dropReasonLoss      = "loss"
+	dropReasonPartition = "partition"
+	dropReasonNoNetwork = "unknown receiver"
+)
+
+// TraceEvent is a single event of the trace of the Simulator.
+type TraceEvent struct {
+	Type TraceEventType `json:"type"`
+	// Time is the virtual time at which the event happened.
+	Time    time.Duration    `json:"time"`
+	From    flow.Identifier  `json:"from"`
+	To      flow.Identifier  `json:"to"`
+	Channel channels.Channel `json:"channel"`
+	// EventType is the Go type of the message.
+	EventType string `json:"event_type"`
+	// EventKey is the fingerprint of the message, see eventKey.
+	EventKey string `json:"event_key"`
+	// Occurrence is the number of times the same message was sent on the same link before this one.
+	Occurrence uint64 `json:"occurrence"`
+	// DeliverAt is the virtual time the message is scheduled to be delivered at (only for scheduled events).
+	DeliverAt time.Duration `json:"deliver_at,omitempty"`
+	// Duplicate is true if the event concerns a duplicate injected by the Simulator.
+	Duplicate bool `json:"duplicate,omitempty"`
+	// Reason is the reason the message was dropped (only for dropped events).
+	Reason string `json:"reason,omitempty"`
+}
+
+// String returns a human-readable representation of the trace event.
+func (e TraceEvent) String() string {
+	s := fmt.Sprintf("%v %s %s -> %s on %s: %s (%s#%d)", e.Time, e.Type, e.From, e.To, e.Channel, e.EventType, e.EventKey, e.Occurrence)
+	switch e.Type {
+	case TraceEventScheduled:
+		s += fmt.Sprintf(" at %v", e.DeliverAt)
+	case TraceEventDropped:
+		s += fmt.Sprintf(" reason: %s", e.Reason)
+	}
+	if e.Duplicate {
+		s += " (duplicate)"
+	}
+	return s
+}
+
+// WriteTrace writes the trace as newline-delimited JSON to the writer.
+func WriteTrace(w io.Writer, trace []TraceEvent) error {
+	encoder := json.NewEncoder(w)
+	for _, event := range trace {
+		if err := encoder.Encode(event); err != nil {
+			return fmt.Errorf("could not encode trace event: %w", err)
+		}
+	}
+	return nil
+}
+
+// ReadTrace reads a trace written by WriteTrace.
+func ReadTrace(r io.Reader) ([]TraceEvent, error) {
+	trace := make([]TraceEvent, 0)
+	scanner := bufio.NewScanner(r)
+	// blob messages may result in long event types, hence the buffer is enlarged.
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		var event TraceEvent
+		if err := json.Unmarshal([]byte(line), &event); err != nil {
+			return nil, fmt.Errorf("could not decode trace event: %w", err)
+		}
+		trace = append(trace, event)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("could not read trace: %w", err)
+	}
+	return trace, nil
+}
+
+// ReadTraceFile reads a trace file written by WriteTrace.
+func ReadTraceFile(path string) ([]TraceEvent, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("could not open trace file: %w", err)
+	}
+	defer f.Close()
+	return ReadTrace(f)
+}
+
+// WriteTraceFile writes the trace recorded so far to a file in the given directory, named after the given name (e.g.,
+// the name of the failing test) and the seed of the Simulator, and returns the path of the file. The written trace can
+// be read with ReadTraceFile and replayed using WithReplay.
+func (s *Simulator) WriteTraceFile(dir string, name string) (string, error) {
+	name = strings.NewReplacer("/", "_", " ", "_").Replace(name)
+	path := filepath.Join(dir, fmt.Sprintf("%s-seed-%d.trace", name, s.Seed()))
+	f, err := os.Create(path)
+	if err != nil {
+		return "", fmt.Errorf("could not create trace file: %w", err)
+	}
+	defer f.Close()
+	if err := WriteTrace(f, s.Trace()); err != nil {
+		return "", fmt.Errorf("could not write trace: %w", err)
+	}
+	return path, nil
+}
+
+// linkMessageID identifies a message sent on a link. Since the same message can be sent several times on a link, the
+// occurrence of the message distinguishes the sends.
+type linkMessageID struct {
+	from       flow.Identifier
+	to         flow.Identifier
+	key        string
+	occurrence uint64
+}
+
+// replayDecisions contains the delivery decisions of a recorded trace, keyed by the sent message.
+type replayDecisions struct {
+	decisions map[linkMessageID]deliveryDecision
+}
+
+// newReplayDecisions extracts the delivery decisions from the recorded trace. The delays of the deliveries are
+// recorded relative to the time the message was sent, so that the replay tolerates shifts of the virtual time.
+func newReplayDecisions(trace []TraceEvent) *replayDecisions {
+	decisions := make(map[linkMessageID]deliveryDecision)
+	for _, event := range trace {
+		id := linkMessageID{from: event.From, to: event.To, key: event.EventKey, occurrence: event.Occurrence}
+		decision := decisions[id]
+		switch event.Type {
+		case TraceEventScheduled:
+			decision.delays = append(decision.delays, event.DeliverAt-event.Time)
+		case TraceEventDropped:
+			// only drops decided when sending the message are decisions of the simulator, drops on delivery are the
+			// consequence of partitions and missing receivers which are replayed as they are configured.
+			if event.Reason == dropReasonLoss || (event.Reason == dropReasonPartition && len(decision.delays) == 0) {
+				decision.dropReason = event.Reason
+			}
+		default:
+			continue
+		}
+		decisions[id] = decision
+	}
+	return &replayDecisions{decisions: decisions}
+}
+
+// decision returns the recorded decision for the message, and false if the message was not recorded in the trace.
+func (r *replayDecisions) decision(id linkMessageID) (deliveryDecision, bool) {
+	decision, ok := r.decisions[id]
+	return decision, ok
+}
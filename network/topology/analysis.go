@@ -0,0 +1,206 @@
+package topology
+
+import (
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/onflow/flow-go/model/flow"
+)
+
+// ConnectivityReport summarizes the graph of the connections formed by the fanouts of a set of nodes. As the peer
+// manager of each node prunes the connections to the nodes outside its fanout, a connection between two nodes only
+// persists if each of them is in the fanout of the other. Hence, the graph has an edge between two nodes if and only
+// if each of them is in the fanout of the other, while the fanout entries which are not reciprocated are reported as
+// asymmetric, as the corresponding connections are pruned by the other end.
+type ConnectivityReport struct {
+	// Nodes is the number of nodes of the graph.
+	Nodes int
+	// Connections is the number of edges of the graph, i.e., the number of pairs of nodes in the fanout of each other.
+	Connections int
+	// AsymmetricEdges is the number of fanout entries of the nodes which are not reciprocated by the other end.
+	AsymmetricEdges int
+	// MinDegree, MaxDegree and MeanDegree are the minimum, maximum and mean number of connections of the nodes.
+	MinDegree  int
+	MaxDegree  int
+	MeanDegree float64
+	// Connected is true if the graph is connected.
+	Connected bool
+	// Diameter is the longest shortest path between two nodes, or -1 if the graph is not connected.
+	Diameter int
+	// Roles summarizes the subgraph of the nodes of each role present in the graph, i.e., with only the connections
+	// between nodes of the role.
+	Roles map[flow.Role]RoleConnectivity
+}
+
+// RoleConnectivity summarizes the subgraph of the nodes of a role.
+type RoleConnectivity struct {
+	// Nodes is the number of nodes of the role.
+	Nodes int
+	// Components is the number of connected components of the subgraph.
+	Components int
+}
+
+// Passed returns true if the graph is connected, and the subgraph of each role is connected.
+func (r *ConnectivityReport) Passed() bool {
+	if !r.Connected {
+		return false
+	}
+	for _, role := range r.Roles {
+		if role.Components != 1 {
+			return false
+		}
+	}
+	return true
+}
+
+// Write writes a human-readable version of the report.
+// No errors are expected during normal operation.
+func (r *ConnectivityReport) Write(w io.Writer) error {
+	_, err := fmt.Fprintf(w, "nodes: %d\nconnections: %d\nasymmetric edges: %d\ndegree: min %d, max %d, mean %.2f\nconnected: %t\ndiameter: %d\n",
+		r.Nodes, r.Connections, r.AsymmetricEdges, r.MinDegree, r.MaxDegree, r.MeanDegree, r.Connected, r.Diameter)
+	if err != nil {
+		return fmt.Errorf("could not write report: %w", err)
+	}
+
+	roles := make(flow.RoleList, 0, len(r.Roles))
+	for role := range r.Roles {
+		roles = append(roles, role)
+	}
+	sort.Slice(roles, func(i, j int) bool { return roles[i] < roles[j] })
+	for _, role := range roles {
+		c := r.Roles[role]
+		_, err = fmt.Fprintf(w, "role %s: nodes %d, components %d\n", role, c.Nodes, c.Components)
+		if err != nil {
+			return fmt.Errorf("could not write report: %w", err)
+		}
+	}
+	return nil
+}
+
+// AnalyzeConnectivity analyzes the graph of the connections formed by the fanouts of the identities, keyed by node ID.
+// The nodes of the fanouts that are not part of the identities are ignored.
+func AnalyzeConnectivity(ids flow.IdentityList, fanouts map[flow.Identifier]flow.IdentityList) *ConnectivityReport {
+	index := make(map[flow.Identifier]int, len(ids))
+	for i, identity := range ids {
+		index[identity.NodeID] = i
+	}
+
+	// the fanout of each node, as a set of node indices
+	selected := make([]map[int]struct{}, len(ids))
+	for i, identity := range ids {
+		selected[i] = make(map[int]struct{})
+		for _, peer := range fanouts[identity.NodeID] {
+			j, ok := index[peer.NodeID]
+			if !ok || j == i {
+				continue
+			}
+			selected[i][j] = struct{}{}
+		}
+	}
+
+	// the adjacency lists of the graph of the connections, which only persist if both ends select each other
+	edges := make([][]int, len(ids))
+	report := &ConnectivityReport{
+		Nodes:     len(ids),
+		MinDegree: -1,
+		Roles:     make(map[flow.Role]RoleConnectivity),
+	}
+	for i := range ids {
+		for j := range selected[i] {
+			if _, ok := selected[j][i]; !ok {
+				report.AsymmetricEdges++
+				continue
+			}
+			edges[i] = append(edges[i], j)
+		}
+		// sorts the adjacency list, as the iteration order of the fanout set is random
+		sort.Ints(edges[i])
+		report.Connections += len(edges[i])
+		if report.MinDegree == -1 || len(edges[i]) < report.MinDegree {
+			report.MinDegree = len(edges[i])
+		}
+		if len(edges[i]) > report.MaxDegree {
+			report.MaxDegree = len(edges[i])
+		}
+	}
+	if len(ids) == 0 {
+		report.MinDegree = 0
+		report.Connected = true
+		return report
+	}
+	report.MeanDegree = float64(report.Connections) / float64(len(ids))
+	// each connection is counted at both ends
+	report.Connections /= 2
+
+	all := func(int) bool { return true }
+	report.Connected = len(reachable(0, edges, all)) == len(ids)
+	report.Diameter = -1
+	if report.Connected {
+		report.Diameter = diameter(edges)
+	}
+
+	for _, role := range flow.Roles() {
+		var members []int
+		for i, identity := range ids {
+			if identity.Role == role {
+				members = append(members, i)
+			}
+		}
+		if len(members) == 0 {
+			continue
+		}
+		isMember := func(i int) bool { return ids[i].Role == role }
+
+		components := 0
+		visited := make(map[int]struct{}, len(members))
+		for _, i := range members {
+			if _, ok := visited[i]; ok {
+				continue
+			}
+			components++
+			for j := range reachable(i, edges, isMember) {
+				visited[j] = struct{}{}
+			}
+		}
+
+		report.Roles[role] = RoleConnectivity{
+			Nodes:      len(members),
+			Components: components,
+		}
+	}
+
+	return report
+}
+
+// reachable returns the nodes reachable from the start node following the edges, only traversing the included nodes.
+func reachable(start int, edges [][]int, include func(int) bool) map[int]int {
+	distances := map[int]int{start: 0}
+	queue := []int{start}
+	for len(queue) > 0 {
+		i := queue[0]
+		queue = queue[1:]
+		for _, j := range edges[i] {
+			if _, ok := distances[j]; ok || !include(j) {
+				continue
+			}
+			distances[j] = distances[i] + 1
+			queue = append(queue, j)
+		}
+	}
+	return distances
+}
+
+// diameter returns the longest shortest path between two nodes of the connected graph.
+func diameter(edges [][]int) int {
+	all := func(int) bool { return true }
+	longest := 0
+	for i := range edges {
+		for _, distance := range reachable(i, edges, all) {
+			if distance > longest {
+				longest = distance
+			}
+		}
+	}
+	return longest
+}
@@ -0,0 +1,304 @@
+package topology
+
+import (
+	"fmt"
+	"strconv"
+	"sync"
+
+	"github.com/rs/zerolog"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/model/flow/filter"
+	"github.com/onflow/flow-go/model/flow/order"
+	"github.com/onflow/flow-go/network"
+	"github.com/onflow/flow-go/state/protocol"
+	"github.com/onflow/flow-go/state/protocol/events"
+	"github.com/onflow/flow-go/state/protocol/seed"
+)
+
+const (
+	// FullyConnectedTopologyName is the name of the FullyConnectedTopology.
+	FullyConnectedTopologyName = "fully-connected"
+	// RoleAwareTopologyName is the name of the RoleAwareTopology.
+	RoleAwareTopologyName = "role-aware"
+)
+
+// DefaultRoleFanout is the default maximum number of nodes of a role sampled by a node for its fanout.
+const DefaultRoleFanout = 8
+
+// minOwnRoleFanout is the minimum number of nodes of its own role sampled by a node, i.e., its two neighbors on the
+// ring of the nodes of its role.
+const minOwnRoleFanout = 2
+
+// RoleAwareTopology selects a bounded number of nodes per role, deterministically derived from the source of randomness
+// of the current epoch. The fanouts of the nodes are symmetric, and the subgraph of the nodes of each role is guaranteed
+// to be connected (see RoleAwareFanouts).
+// The topology consumes the protocol events to switch to the source of randomness of the new epoch at epoch
+// transitions, and requests the peer manager to update the peer connections of the node accordingly.
+type RoleAwareTopology struct {
+	events.Noop
+	log               zerolog.Logger
+	state             protocol.State
+	nodeID            flow.Identifier
+	fanouts           map[flow.Role]uint
+	requestPeerUpdate func()
+
+	mu           sync.RWMutex
+	randomSource []byte
+}
+
+var _ network.Topology = (*RoleAwareTopology)(nil)
+var _ protocol.Consumer = (*RoleAwareTopology)(nil)
+
+// NewRoleAwareTopology creates a role-aware topology for the given node, seeded by the source of randomness of the
+// current epoch. The fanouts are the maximum number of nodes of each role sampled by the node, roles without
+// fanout default to DefaultRoleFanout. The requestPeerUpdate function is called once the topology changed at an epoch
+// transition, and is expected to request an update of the peer connections of the node from the peer manager.
+// No errors are expected during normal operation.
+func NewRoleAwareTopology(
+	log zerolog.Logger,
+	state protocol.State,
+	nodeID flow.Identifier,
+	fanouts map[flow.Role]uint,
+	requestPeerUpdate func(),
+) (*RoleAwareTopology, error) {
+	randomSource, err := state.Final().Epochs().Current().RandomSource()
+	if err != nil {
+		return nil, fmt.Errorf("could not get random source of current epoch: %w", err)
+	}
+
+	return &RoleAwareTopology{
+		log:               log.With().Str("component", "role_aware_topology").Logger(),
+		state:             state,
+		nodeID:            nodeID,
+		fanouts:           fanouts,
+		requestPeerUpdate: requestPeerUpdate,
+		randomSource:      randomSource,
+	}, nil
+}
+
+// Fanout returns the fanout of the node among the given identities (see RoleAwareFanout).
+// In the unexpected case the fanout can't be computed, all the identities are returned, so that the node stays
+// connected to the network.
+func (t *RoleAwareTopology) Fanout(ids flow.IdentityList) flow.IdentityList {
+	t.mu.RLock()
+	randomSource := t.randomSource
+	t.mu.RUnlock()
+
+	fanout, err := RoleAwareFanout(t.nodeID, ids, t.fanouts, randomSource)
+	if err != nil {
+		t.log.Error().Err(err).Msg("could not compute fanout, falling back to fully connected topology")
+		return ids
+	}
+	return fanout
+}
+
+// EpochTransition switches the topology to the source of randomness of the new epoch, and requests an update of the
+// peer connections of the node.
+//
+// TODO: per API contract, implementations of `EpochTransition` should be non-blocking
+// and virtually latency free. However, we run data base queries here, which is undesired.
+func (t *RoleAwareTopology) EpochTransition(newEpochCounter uint64, header *flow.Header) {
+	randomSource, err := t.state.AtBlockID(header.ID()).Epochs().Current().RandomSource()
+	if err != nil {
+		// the topology remains valid with the source of randomness of the previous epoch
+		t.log.Error().Err(err).
+			Uint64("new_epoch_counter", newEpochCounter).
+			Msg("could not get random source of new epoch, keeping topology of previous epoch")
+		return
+	}
+
+	t.mu.Lock()
+	t.randomSource = randomSource
+	t.mu.Unlock()
+
+	t.log.Info().Uint64("new_epoch_counter", newEpochCounter).Msg("topology updated for new epoch")
+	t.requestPeerUpdate()
+}
+
+// RoleAwareFanout returns the fanout of the node among the identities (see RoleAwareFanouts). If the node is not part
+// of the identities, its fanout only includes the nodes it samples itself, as no other node samples it.
+// No errors are expected during normal operation.
+func RoleAwareFanout(nodeID flow.Identifier, ids flow.IdentityList, fanouts map[flow.Role]uint, randomSource []byte) (flow.IdentityList, error) {
+	if _, isMember := ids.ByNodeID(nodeID); !isMember {
+		return sampleFanout(nodeID, ids.Sort(order.Canonical), nil, fanouts, randomSource)
+	}
+	all, err := RoleAwareFanouts(ids, fanouts, randomSource)
+	if err != nil {
+		return nil, err
+	}
+	return all[nodeID], nil
+}
+
+// RoleAwareFanouts returns the fanouts of all the identities, keyed by node ID. The fanout of a node includes the nodes
+// it samples itself (see sampleFanout), as well as the nodes which sample it. Hence, the fanouts are symmetric: two
+// nodes are either in the fanout of each other, or in neither. This matters as the peer manager prunes the connections
+// to the nodes outside the fanout of the node, so that with asymmetric fanouts the node would close the connections
+// that the other end selected. As a consequence, the fanout of a node includes at least the nodes it samples for each
+// role, but is not bounded by the fanout of the role: the nodes of a small role are sampled by many nodes of larger
+// roles. The number of connections of the network is however at most the sum of the sampled fanouts.
+//
+// The fanouts are deterministic: all nodes compute the same fanouts given the same identities and random source.
+// No errors are expected during normal operation.
+func RoleAwareFanouts(ids flow.IdentityList, fanouts map[flow.Role]uint, randomSource []byte) (map[flow.Identifier]flow.IdentityList, error) {
+	sorted := ids.Sort(order.Canonical)
+	rings, err := roleRings(sorted, randomSource)
+	if err != nil {
+		return nil, fmt.Errorf("could not compute rings: %w", err)
+	}
+
+	edges := make(map[flow.Identifier]map[flow.Identifier]struct{}, len(sorted))
+	for _, identity := range sorted {
+		edges[identity.NodeID] = make(map[flow.Identifier]struct{})
+	}
+	for _, identity := range sorted {
+		sampled, err := sampleFanout(identity.NodeID, sorted, rings, fanouts, randomSource)
+		if err != nil {
+			return nil, fmt.Errorf("could not sample fanout of node %v: %w", identity.NodeID, err)
+		}
+		for _, peer := range sampled {
+			edges[identity.NodeID][peer.NodeID] = struct{}{}
+			edges[peer.NodeID][identity.NodeID] = struct{}{}
+		}
+	}
+
+	all := make(map[flow.Identifier]flow.IdentityList, len(sorted))
+	for _, identity := range sorted {
+		peers := edges[identity.NodeID]
+		all[identity.NodeID] = sorted.Filter(func(other *flow.Identity) bool {
+			_, ok := peers[other.NodeID]
+			return ok
+		})
+	}
+	return all, nil
+}
+
+// sampleFanout returns the nodes sampled by the node among the identities. For each role, the node samples at most the
+// fanout of the role (DefaultRoleFanout if the role has no fanout) of nodes of the role:
+//   - the nodes of each role are arranged on a ring, in an order derived from the random source which is the same for
+//     all nodes (see roleRings). The node samples its two neighbors on the ring of its role, which guarantees that the
+//     subgraph of the nodes of each role is connected. Hence, the node samples at least 2 nodes of its own role.
+//   - the remaining fanout of each role is sampled among the nodes of the role, using a PRG seeded by the random source
+//     and the node ID.
+//
+// The identities must be in canonical order, and rings must hold the rings of the identities, it is only used if the
+// node is part of the identities.
+// No errors are expected during normal operation.
+func sampleFanout(nodeID flow.Identifier, sorted flow.IdentityList, rings map[flow.Role]flow.IdentityList, fanouts map[flow.Role]uint, randomSource []byte) (flow.IdentityList, error) {
+	me, isMember := sorted.ByNodeID(nodeID)
+
+	// the PRG sampling the fanout of the node is seeded by both the random source and the node ID, so that nodes sample
+	// independently from each other.
+	nodeSource := make([]byte, 0, len(randomSource)+len(nodeID))
+	nodeSource = append(append(nodeSource, randomSource...), nodeID[:]...)
+	rng, err := seed.PRGFromRandomSource(nodeSource, seed.NetworkTopologySampling)
+	if err != nil {
+		return nil, fmt.Errorf("could not create fanout sampling PRG: %w", err)
+	}
+
+	fanout := make(flow.IdentityList, 0)
+	for _, role := range flow.Roles() {
+		members := sorted.Filter(filter.HasRole(role))
+		limit := RoleFanout(fanouts, role)
+
+		selected := make(flow.IdentityList, 0, limit)
+		if isMember && me.Role == role {
+			selected = ringNeighbors(nodeID, rings[role])
+			if limit < minOwnRoleFanout {
+				limit = minOwnRoleFanout
+			}
+		}
+
+		candidates := members.Filter(filter.Not(filter.HasNodeID(append(selected.NodeIDs(), nodeID)...)))
+		count := int(limit) - len(selected)
+		if count > len(candidates) {
+			count = len(candidates)
+		}
+		if count > 0 {
+			err = rng.Samples(len(candidates), count, func(i, j int) {
+				candidates[i], candidates[j] = candidates[j], candidates[i]
+			})
+			if err != nil {
+				return nil, fmt.Errorf("could not sample fanout of role %s: %w", role, err)
+			}
+			selected = append(selected, candidates[:count]...)
+		}
+
+		fanout = append(fanout, selected...)
+	}
+
+	return fanout, nil
+}
+
+// RoleFanout returns the fanout of the role, i.e., DefaultRoleFanout if the role has no fanout.
+func RoleFanout(fanouts map[flow.Role]uint, role flow.Role) uint {
+	fanout, ok := fanouts[role]
+	if !ok {
+		return DefaultRoleFanout
+	}
+	return fanout
+}
+
+// roleRings returns the ring of the nodes of each role, i.e., the nodes of the role ordered by a permutation derived
+// from the random source. The identities must be in canonical order.
+// No errors are expected during normal operation.
+func roleRings(sorted flow.IdentityList, randomSource []byte) (map[flow.Role]flow.IdentityList, error) {
+	rings := make(map[flow.Role]flow.IdentityList)
+	for _, role := range flow.Roles() {
+		ring := sorted.Filter(filter.HasRole(role))
+		if len(ring) == 0 {
+			continue
+		}
+		rng, err := seed.PRGFromRandomSource(randomSource, seed.NetworkTopologyRing(role))
+		if err != nil {
+			return nil, fmt.Errorf("could not create ring PRG of role %s: %w", role, err)
+		}
+		err = rng.Shuffle(len(ring), func(i, j int) {
+			ring[i], ring[j] = ring[j], ring[i]
+		})
+		if err != nil {
+			return nil, fmt.Errorf("could not shuffle ring of role %s: %w", role, err)
+		}
+		rings[role] = ring
+	}
+	return rings, nil
+}
+
+// ringNeighbors returns the neighbors of the node on the ring of its role.
+func ringNeighbors(nodeID flow.Identifier, ring flow.IdentityList) flow.IdentityList {
+	neighbors := make(flow.IdentityList, 0, minOwnRoleFanout)
+	for i, identity := range ring {
+		if identity.NodeID != nodeID {
+			continue
+		}
+		next := ring[(i+1)%len(ring)]
+		previous := ring[(i+len(ring)-1)%len(ring)]
+		if next.NodeID != nodeID {
+			neighbors = append(neighbors, next)
+		}
+		if previous.NodeID != nodeID && previous.NodeID != next.NodeID {
+			neighbors = append(neighbors, previous)
+		}
+		break
+	}
+	return neighbors
+}
+
+// ParseRoleFanouts parses the fanouts of the roles from their string representation keyed by role name,
+// e.g., {"consensus": "4"}.
+// All errors indicate invalid fanouts.
+func ParseRoleFanouts(fanouts map[string]string) (map[flow.Role]uint, error) {
+	parsed := make(map[flow.Role]uint, len(fanouts))
+	for name, value := range fanouts {
+		role, err := flow.ParseRole(name)
+		if err != nil {
+			return nil, fmt.Errorf("invalid role %s: %w", name, err)
+		}
+		fanout, err := strconv.ParseUint(value, 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid fanout of role %s: %w", name, err)
+		}
+		parsed[role] = uint(fanout)
+	}
+	return parsed, nil
+}
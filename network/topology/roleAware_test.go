@@ -0,0 +1,163 @@
+package topology_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/model/flow/filter"
+	"github.com/onflow/flow-go/network/topology"
+	"github.com/onflow/flow-go/state/protocol/seed"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+// roleAwareFanouts computes the role-aware fanout of all the identities.
+func roleAwareFanouts(t *testing.T, ids flow.IdentityList, fanouts map[flow.Role]uint, randomSource []byte) map[flow.Identifier]flow.IdentityList {
+	all, err := topology.RoleAwareFanouts(ids, fanouts, randomSource)
+	require.NoError(t, err)
+	require.Len(t, all, len(ids))
+	return all
+}
+
+// TestRoleAwareFanout_Deterministic evaluates that the fanout of a node only depends on the identities and the random
+// source, and not on the order of the identities.
+func TestRoleAwareFanout_Deterministic(t *testing.T) {
+	ids := unittest.IdentityListFixture(100, unittest.WithAllRoles())
+	randomSource := unittest.SeedFixture(seed.RandomSourceLength)
+	nodeID := ids[0].NodeID
+
+	fanout, err := topology.RoleAwareFanout(nodeID, ids, nil, randomSource)
+	require.NoError(t, err)
+	require.Equal(t, roleAwareFanouts(t, ids, nil, randomSource)[nodeID], fanout)
+	shuffled, err := topology.RoleAwareFanout(nodeID, ids.DeterministicShuffle(42), nil, randomSource)
+	require.NoError(t, err)
+	require.Equal(t, fanout, shuffled)
+
+	reseeded, err := topology.RoleAwareFanout(nodeID, ids, nil, unittest.SeedFixture(seed.RandomSourceLength))
+	require.NoError(t, err)
+	require.NotEqual(t, fanout, reseeded)
+}
+
+// TestRoleAwareFanout_Symmetric evaluates that two nodes are either in the fanout of each other, or in neither, so that
+// pruning the connections to the nodes outside the fanout never closes a connection selected by the other end.
+func TestRoleAwareFanout_Symmetric(t *testing.T) {
+	ids := unittest.IdentityListFixture(200, unittest.WithAllRoles())
+	fanouts := map[flow.Role]uint{
+		flow.RoleCollection: 3,
+		flow.RoleConsensus:  1,
+	}
+	all := roleAwareFanouts(t, ids, fanouts, unittest.SeedFixture(seed.RandomSourceLength))
+
+	for _, identity := range ids {
+		for _, peer := range all[identity.NodeID] {
+			_, reciprocated := all[peer.NodeID].ByNodeID(identity.NodeID)
+			require.True(t, reciprocated, "node %v is in the fanout of %v, but not the other way around", peer.NodeID, identity.NodeID)
+		}
+	}
+	require.Zero(t, topology.AnalyzeConnectivity(ids, all).AsymmetricEdges)
+}
+
+// TestRoleAwareFanout_Bounded evaluates that the fanout of a node includes at least the nodes it samples of each role,
+// and never the node itself, while the number of connections is bounded by the number of sampled nodes.
+func TestRoleAwareFanout_Bounded(t *testing.T) {
+	ids := unittest.IdentityListFixture(200, unittest.WithAllRoles())
+	fanouts := map[flow.Role]uint{
+		flow.RoleCollection:   3,
+		flow.RoleConsensus:    0,
+		flow.RoleVerification: 100,
+	}
+	all := roleAwareFanouts(t, ids, fanouts, unittest.SeedFixture(seed.RandomSourceLength))
+
+	sampled := 0
+	for _, identity := range ids {
+		fanout := all[identity.NodeID]
+		_, self := fanout.ByNodeID(identity.NodeID)
+		require.False(t, self)
+		require.Len(t, fanout.Lookup(), len(fanout), "fanout must not contain duplicates")
+
+		for _, role := range flow.Roles() {
+			members := ids.Filter(filter.HasRole(role))
+			selected := fanout.Filter(filter.HasRole(role))
+			limit := topology.RoleFanout(fanouts, role)
+			if role == identity.Role {
+				// the node itself is not part of its fanout
+				members = members.Filter(filter.Not(filter.HasNodeID(identity.NodeID)))
+				if limit < 2 {
+					limit = 2
+				}
+			}
+			if uint(len(members)) < limit {
+				limit = uint(len(members))
+			}
+			require.GreaterOrEqual(t, len(selected), int(limit), "unexpected fanout of role %s for a node of role %s", role, identity.Role)
+			sampled += int(limit)
+		}
+	}
+
+	report := topology.AnalyzeConnectivity(ids, all)
+	require.LessOrEqual(t, report.Connections, sampled)
+}
+
+// TestRoleAwareFanout_Connectivity evaluates that the subgraph of the nodes of each role is connected, and that the
+// graph of the connections is connected, even with minimal fanouts.
+func TestRoleAwareFanout_Connectivity(t *testing.T) {
+	ids := unittest.IdentityListFixture(300, unittest.WithAllRoles())
+	fanouts := map[flow.Role]uint{
+		flow.RoleCollection:   1,
+		flow.RoleConsensus:    1,
+		flow.RoleExecution:    1,
+		flow.RoleVerification: 1,
+		flow.RoleAccess:       1,
+	}
+
+	for i := 0; i < 10; i++ {
+		all := roleAwareFanouts(t, ids, fanouts, unittest.SeedFixture(seed.RandomSourceLength))
+		report := topology.AnalyzeConnectivity(ids, all)
+		require.True(t, report.Passed())
+		require.Zero(t, report.AsymmetricEdges)
+		for role, connectivity := range report.Roles {
+			require.Equal(t, 1, connectivity.Components, "subgraph of role %s is not connected", role)
+		}
+	}
+}
+
+// TestAnalyzeConnectivity evaluates the connectivity analysis of a topology graph, in which only the fanout entries
+// reciprocated by the other end form connections.
+func TestAnalyzeConnectivity(t *testing.T) {
+	collectors := unittest.IdentityListFixture(3, unittest.WithRole(flow.RoleCollection))
+	consensus := unittest.IdentityListFixture(2, unittest.WithRole(flow.RoleConsensus))
+	ids := append(collectors.Copy(), consensus...)
+
+	// a directed chain of collectors, which doesn't form any connection, and a pair of consensus nodes selecting
+	// each other
+	fanouts := map[flow.Identifier]flow.IdentityList{
+		collectors[0].NodeID: {collectors[1]},
+		collectors[1].NodeID: {collectors[2]},
+		consensus[0].NodeID:  {consensus[1]},
+		consensus[1].NodeID:  {consensus[0]},
+	}
+	report := topology.AnalyzeConnectivity(ids, fanouts)
+	require.Equal(t, 5, report.Nodes)
+	require.Equal(t, 1, report.Connections)
+	require.Equal(t, 2, report.AsymmetricEdges)
+	require.Equal(t, 0, report.MinDegree)
+	require.Equal(t, 1, report.MaxDegree)
+	require.False(t, report.Connected)
+	require.Equal(t, -1, report.Diameter)
+	require.Equal(t, topology.RoleConnectivity{Nodes: 3, Components: 3}, report.Roles[flow.RoleCollection])
+	require.Equal(t, topology.RoleConnectivity{Nodes: 2, Components: 1}, report.Roles[flow.RoleConsensus])
+	require.False(t, report.Passed())
+
+	// reciprocating the chain, and connecting both roles
+	fanouts[collectors[1].NodeID] = flow.IdentityList{collectors[0], collectors[2]}
+	fanouts[collectors[2].NodeID] = flow.IdentityList{collectors[1], consensus[0]}
+	fanouts[consensus[0].NodeID] = flow.IdentityList{consensus[1], collectors[2]}
+	report = topology.AnalyzeConnectivity(ids, fanouts)
+	require.True(t, report.Connected)
+	require.Equal(t, 4, report.Connections)
+	require.Zero(t, report.AsymmetricEdges)
+	require.Equal(t, 4, report.Diameter)
+	require.Equal(t, topology.RoleConnectivity{Nodes: 3, Components: 1}, report.Roles[flow.RoleCollection])
+	require.True(t, report.Passed())
+}
@@ -601,22 +601,18 @@ func (m *FollowerState) Finalize(ctx context.Context, blockID flow.Identifier) e
 	if err != nil {
 		return fmt.Errorf("could not retrieve setup event for current epoch: %w", err)
 	}
-	epochFallbackTriggered, err := m.isEpochEmergencyFallbackTriggered()
+	// The persisted flag reflects whether epoch fallback is in effect as of the parent (the
+	// latest finalized block). Whether it is in effect as of this block is determined by the
+	// block's epoch status, which is the same for this block on every node.
+	epochFallbackPreviouslyTriggered, err := m.isEpochEmergencyFallbackTriggered()
 	if err != nil {
 		return fmt.Errorf("could not check persisted epoch emergency fallback flag: %w", err)
 	}
-
-	// if epoch fallback was not previously triggered, check whether this block triggers it
-	if !epochFallbackTriggered {
-		epochFallbackTriggered, err = m.epochFallbackTriggeredByFinalizedBlock(header, epochStatus, currentEpochSetup)
-		if err != nil {
-			return fmt.Errorf("could not check whether finalized block triggers epoch fallback: %w", err)
-		}
-		if epochFallbackTriggered {
-			// emit the protocol event only the first time epoch fallback is triggered
-			events = append(events, m.consumer.EpochEmergencyFallbackTriggered)
-			metrics = append(metrics, m.metrics.EpochEmergencyFallbackTriggered)
-		}
+	epochFallbackTriggered := epochStatus.EpochFallbackTriggered
+	if epochFallbackTriggered && !epochFallbackPreviouslyTriggered {
+		// emit the protocol event only the first time epoch fallback is triggered
+		events = append(events, m.consumer.EpochEmergencyFallbackTriggered)
+		metrics = append(metrics, m.metrics.EpochEmergencyFallbackTriggered)
 	}
 
 	isFirstBlockOfEpoch, err := m.isFirstBlockOfEpoch(header, currentEpochSetup)
@@ -627,8 +623,33 @@ func (m *FollowerState) Finalize(ctx context.Context, blockID flow.Identifier) e
 	// Determine metric updates and protocol events related to epoch phase
 	// changes and epoch transitions.
 	// If epoch emergency fallback is triggered, the current epoch continues until
-	// the next spork - so skip these updates.
-	if !epochFallbackTriggered {
+	// a recovery epoch is committed and started (or until the next spork) - so
+	// only track the recovery epoch in this case.
+	exitEpochFallback := false
+	if epochFallbackPreviouslyTriggered {
+		parentStatus, err := m.epoch.statuses.ByBlockID(header.ParentID)
+		if err != nil {
+			return fmt.Errorf("could not retrieve epoch state for parent: %w", err)
+		}
+		epochRecoveryMetrics, epochRecoveryEvents, err := m.epochRecoveryMetricsAndEventsOnBlockFinalized(header, epochStatus, parentStatus)
+		if err != nil {
+			return fmt.Errorf("could not determine epoch recovery metrics/events for finalized block: %w", err)
+		}
+		metrics = append(metrics, epochRecoveryMetrics...)
+		events = append(events, epochRecoveryEvents...)
+
+		// finalizing the first block of the recovery epoch exits epoch fallback mode
+		if !epochFallbackTriggered {
+			if !isFirstBlockOfEpoch {
+				return fmt.Errorf("epoch fallback can only be exited by the first block of the recovery epoch")
+			}
+			exitEpochFallback = true
+			epochTransitionMetrics, epochTransitionEvents := m.epochTransitionMetricsAndEventsOnBlockFinalized(header, currentEpochSetup)
+			metrics = append(metrics, epochTransitionMetrics...)
+			metrics = append(metrics, m.metrics.EpochEmergencyFallbackExited)
+			events = append(events, epochTransitionEvents...)
+		}
+	} else if !epochFallbackTriggered {
 		epochPhaseMetrics, epochPhaseEvents, err := m.epochPhaseMetricsAndEventsOnBlockFinalized(header, epochStatus)
 		if err != nil {
 			return fmt.Errorf("could not determine epoch phase metrics/events for finalized block: %w", err)
@@ -653,6 +674,7 @@ func (m *FollowerState) Finalize(ctx context.Context, blockID flow.Identifier) e
 	//   This value could actually stay the same if it has no seals in
 	//   its payload, in which case the parent's seal is the same.
 	// * set the epoch fallback flag, if it is triggered
+	// * unset the epoch fallback flag, if this block starts the recovery epoch
 	err = operation.RetryOnConflict(m.db.Update, func(tx *badger.Txn) error {
 		err = operation.IndexBlockHeight(header.Height, blockID)(tx)
 		if err != nil {
@@ -666,13 +688,18 @@ func (m *FollowerState) Finalize(ctx context.Context, blockID flow.Identifier) e
 		if err != nil {
 			return fmt.Errorf("could not update sealed height: %w", err)
 		}
-		if epochFallbackTriggered {
+		if exitEpochFallback {
+			err = operation.UnsetEpochEmergencyFallbackTriggered()(tx)
+			if err != nil {
+				return fmt.Errorf("could not unset epoch fallback flag: %w", err)
+			}
+		} else if epochFallbackTriggered {
 			err = operation.SetEpochEmergencyFallbackTriggered(blockID)(tx)
 			if err != nil {
 				return fmt.Errorf("could not set epoch fallback flag: %w", err)
 			}
 		}
-		if isFirstBlockOfEpoch && !epochFallbackTriggered {
+		if isFirstBlockOfEpoch && (!epochFallbackTriggered || exitEpochFallback) {
 			err = operation.InsertEpochFirstHeight(currentEpochSetup.Counter, header.Height)(tx)
 			if err != nil {
 				return fmt.Errorf("could not insert epoch first block height: %w", err)
@@ -723,43 +750,6 @@ func (m *FollowerState) Finalize(ctx context.Context, blockID flow.Identifier) e
 	return nil
 }
 
-// epochFallbackTriggeredByFinalizedBlock checks whether finalizing the input block
-// would trigger epoch emergency fallback mode. In particular, we trigger epoch
-// fallback mode while finalizing block B in either of the following cases:
-//  1. B is the head of a fork in which epoch fallback was tentatively triggered,
-//     due to incorporating an invalid service event.
-//  2. (a) B is the first finalized block with view greater than or equal to the epoch
-//     commitment deadline for the current epoch AND
-//     (b) the next epoch has not been committed as of B.
-//
-// This function should only be called when epoch fallback *has not already been triggered*.
-// See protocol.Params for more details on the epoch commitment deadline.
-//
-// No errors are expected during normal operation.
-func (m *FollowerState) epochFallbackTriggeredByFinalizedBlock(block *flow.Header, epochStatus *flow.EpochStatus, currentEpochSetup *flow.EpochSetup) (bool, error) {
-	// 1. Epoch fallback is tentatively triggered on this fork
-	if epochStatus.InvalidServiceEventIncorporated {
-		return true, nil
-	}
-
-	// 2.(a) determine whether block B is past the epoch commitment deadline
-	safetyThreshold, err := m.Params().EpochCommitSafetyThreshold()
-	if err != nil {
-		return false, fmt.Errorf("could not get epoch commit safety threshold: %w", err)
-	}
-	blockExceedsDeadline := block.View+safetyThreshold >= currentEpochSetup.FinalView
-
-	// 2.(b) determine whether the next epoch is committed w.r.t. block B
-	currentEpochPhase, err := epochStatus.Phase()
-	if err != nil {
-		return false, fmt.Errorf("could not get current epoch phase: %w", err)
-	}
-	isNextEpochCommitted := currentEpochPhase == flow.EpochPhaseCommitted
-
-	blockTriggersEpochFallback := blockExceedsDeadline && !isNextEpochCommitted
-	return blockTriggersEpochFallback, nil
-}
-
 // isFirstBlockOfEpoch returns true if the given block is the first block of a new epoch.
 // We accept the EpochSetup event for the current epoch (w.r.t. input block B) which contains
 // the FirstView for the epoch (denoted W). By construction, B.View >= W.
@@ -868,6 +858,8 @@ func (m *FollowerState) epochPhaseMetricsAndEventsOnBlockFinalized(block *flow.H
 					return nil, nil, fmt.Errorf("could not retrieve setup event for next epoch: %w", err)
 				}
 				events = append(events, func() { m.metrics.CommittedEpochFinalView(nextEpochSetup.FinalView) })
+			case *flow.EpochRecover:
+				// recovery events are ignored outside of epoch fallback mode
 			default:
 				return nil, nil, fmt.Errorf("invalid service event type in payload (%T)", event)
 			}
@@ -877,6 +869,40 @@ func (m *FollowerState) epochPhaseMetricsAndEventsOnBlockFinalized(block *flow.H
 	return
 }
 
+// epochRecoveryMetricsAndEventsOnBlockFinalized determines metrics to update and
+// protocol events to emit, if this block is the first block for which a recovery
+// epoch is committed. The recovery epoch is committed directly, without going
+// through the EpochSetup phase, hence we only emit the EpochCommittedPhaseStarted
+// event. Same as for regular epoch phase changes, the events are emitted when the
+// child of the block sealing the EpochRecover service event is finalized.
+//
+// This function should only be called when epoch fallback *has been triggered*.
+// No errors are expected during normal operation.
+func (m *FollowerState) epochRecoveryMetricsAndEventsOnBlockFinalized(block *flow.Header, epochStatus *flow.EpochStatus, parentStatus *flow.EpochStatus) (
+	metrics []func(),
+	events []func(),
+	err error,
+) {
+	// the recovery epoch becomes committed with this block, iff the parent's next
+	// epoch is not committed, while this block's next epoch is
+	if parentStatus.NextEpoch.CommitID != flow.ZeroID || epochStatus.NextEpoch.CommitID == flow.ZeroID {
+		return nil, nil, nil
+	}
+	recoverySetup, err := m.epoch.setups.ByID(epochStatus.NextEpoch.SetupID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("could not retrieve setup event for recovery epoch: %w", err)
+	}
+
+	// update current epoch phase
+	metrics = append(metrics, func() { m.metrics.CurrentEpochPhase(flow.EpochPhaseCommitted) })
+	// track final view of committed recovery epoch
+	metrics = append(metrics, func() { m.metrics.CommittedEpochFinalView(recoverySetup.FinalView) })
+	// track epoch phase transition (fallback->committed)
+	events = append(events, func() { m.consumer.EpochCommittedPhaseStarted(recoverySetup.Counter-1, block) })
+
+	return
+}
+
 // epochStatus computes the EpochStatus for the given block *before* applying
 // any service event state changes which come into effect with this block.
 //
@@ -885,21 +911,33 @@ func (m *FollowerState) epochPhaseMetricsAndEventsOnBlockFinalized(block *flow.H
 // the Epoch data from its parent. If the block's view is _larger_ than the
 // final View of the parent's epoch, the block starts a new Epoch.
 //
+// Whether epoch fallback is in effect is determined per fork, from the parent's
+// EpochStatus (see flow.EpochStatus.EpochFallbackTriggered). It is never derived
+// from the locally finalized state, so that all nodes compute the same EpochStatus
+// for the block.
+//
 // Possible outcomes:
 //  1. Block is in same Epoch as parent (block.View < epoch.FinalView)
 //     -> the parent's EpochStatus.CurrentEpoch also applies for the current block
 //  2. Block enters the next Epoch (block.View ≥ epoch.FinalView)
 //     a) HAPPY PATH: Epoch fallback is not triggered, we enter the next epoch:
 //     -> the parent's EpochStatus.NextEpoch is the current block's EpochStatus.CurrentEpoch
-//     b) FALLBACK PATH: Epoch fallback is triggered, we continue the current epoch:
+//     b) FALLBACK PATH: Epoch fallback is triggered, or the next epoch is not committed
+//     as of the parent, we continue the current epoch:
+//     -> the parent's EpochStatus.CurrentEpoch also applies for the current block
+//  3. Epoch fallback is triggered and a recovery epoch has been committed:
+//     a) Block is below the recovery epoch's first view (block.View < recovery.FirstView)
 //     -> the parent's EpochStatus.CurrentEpoch also applies for the current block
+//     b) Block enters the recovery epoch (block.View ≥ recovery.FirstView)
+//     -> the parent's EpochStatus.NextEpoch is the current block's EpochStatus.CurrentEpoch,
+//     and epoch fallback is no longer in effect
 //
 // As the parent was a valid extension of the chain, by induction, the parent
 // satisfies all consistency requirements of the protocol.
 //
 // Returns the EpochStatus for the input block.
 // No error returns are expected under normal operations
-func (m *FollowerState) epochStatus(block *flow.Header, epochFallbackTriggered bool) (*flow.EpochStatus, error) {
+func (m *FollowerState) epochStatus(block *flow.Header) (*flow.EpochStatus, error) {
 	parentStatus, err := m.epoch.statuses.ByBlockID(block.ParentID)
 	if err != nil {
 		return nil, fmt.Errorf("could not retrieve epoch state for parent: %w", err)
@@ -909,10 +947,27 @@ func (m *FollowerState) epochStatus(block *flow.Header, epochFallbackTriggered b
 		return nil, fmt.Errorf("could not retrieve EpochSetup event for parent: %w", err)
 	}
 
-	// Case 1 or 2b (still in parent block's epoch or epoch fallback triggered):
-	if block.View <= parentSetup.FinalView || epochFallbackTriggered {
+	if parentStatus.EpochFallbackTriggered {
+		// Case 3a (epoch fallback triggered, no recovery epoch committed, or not yet started):
+		isRecoveryEpochStarted, err := m.isRecoveryEpochStarted(block, parentStatus)
+		if err != nil {
+			return nil, err
+		}
+		if !isRecoveryEpochStarted {
+			// IMPORTANT: copy the status to avoid modifying the parent status in the cache
+			return parentStatus.Copy(), nil
+		}
+		// Case 3b (first block of recovery epoch): continue below like case 2a
+	} else if block.View <= parentSetup.FinalView {
+		// Case 1 (still in parent block's epoch):
 		// IMPORTANT: copy the status to avoid modifying the parent status in the cache
 		return parentStatus.Copy(), nil
+	} else if parentStatus.NextEpoch.CommitID == flow.ZeroID {
+		// Case 2b (the block passes the final view of the current epoch, without the next
+		// epoch being committed): continue the current epoch in epoch fallback mode
+		epochStatus := parentStatus.Copy()
+		epochStatus.EpochFallbackTriggered = true
+		return epochStatus, nil
 	}
 
 	// Case 2a (first block of new epoch):
@@ -932,6 +987,58 @@ func (m *FollowerState) epochStatus(block *flow.Header, epochFallbackTriggered b
 
 }
 
+// epochFallbackTriggeredByBlock checks whether the input block B triggers epoch
+// fallback mode in its fork, given B's epoch status after applying the service
+// events sealed by B's parent. This is the case if either:
+//  1. an invalid service event is incorporated by B, or
+//  2. (a) B's view is greater than or equal to the epoch commitment deadline for the
+//     current epoch AND
+//     (b) the next epoch has not been committed as of B.
+//
+// Epoch fallback is persisted as triggered when B is finalized.
+// See protocol.Params for more details on the epoch commitment deadline.
+//
+// No errors are expected during normal operation.
+func (m *FollowerState) epochFallbackTriggeredByBlock(block *flow.Header, epochStatus *flow.EpochStatus, currentEpochSetup *flow.EpochSetup) (bool, error) {
+	// 1. Epoch fallback is triggered by an invalid service event in this fork
+	if epochStatus.InvalidServiceEventIncorporated {
+		return true, nil
+	}
+
+	// 2.(a) determine whether block B is past the epoch commitment deadline
+	safetyThreshold, err := m.Params().EpochCommitSafetyThreshold()
+	if err != nil {
+		return false, fmt.Errorf("could not get epoch commit safety threshold: %w", err)
+	}
+	blockExceedsDeadline := block.View+safetyThreshold >= currentEpochSetup.FinalView
+
+	// 2.(b) determine whether the next epoch is committed w.r.t. block B
+	currentEpochPhase, err := epochStatus.Phase()
+	if err != nil {
+		return false, fmt.Errorf("could not get current epoch phase: %w", err)
+	}
+	isNextEpochCommitted := currentEpochPhase == flow.EpochPhaseCommitted
+
+	return blockExceedsDeadline && !isNextEpochCommitted, nil
+}
+
+// isRecoveryEpochStarted returns true if epoch fallback is triggered, a recovery epoch
+// has been committed as of the parent block, and the input block is at or above the
+// recovery epoch's first view. In contrast to regular epoch transitions, there may be
+// a gap between the final view of the current epoch and the first view of the recovery
+// epoch, which is covered by the EECC extension of the current epoch.
+// No error returns are expected under normal operations
+func (m *FollowerState) isRecoveryEpochStarted(block *flow.Header, parentStatus *flow.EpochStatus) (bool, error) {
+	if parentStatus.NextEpoch.CommitID == flow.ZeroID {
+		return false, nil
+	}
+	recoverySetup, err := m.epoch.setups.ByID(parentStatus.NextEpoch.SetupID)
+	if err != nil {
+		return false, fmt.Errorf("could not retrieve EpochSetup event for recovery epoch: %w", err)
+	}
+	return block.View >= recoverySetup.FirstView, nil
+}
+
 // handleEpochServiceEvents handles applying state changes which occur as a result
 // of service events being included in a block payload:
 // * inserting incorporated service events
@@ -964,11 +1071,7 @@ func (m *FollowerState) epochStatus(block *flow.Header, epochFallbackTriggered b
 //
 // No errors are expected during normal operation.
 func (m *FollowerState) handleEpochServiceEvents(candidate *flow.Block) (dbUpdates []func(*transaction.Tx) error, err error) {
-	epochFallbackTriggered, err := m.isEpochEmergencyFallbackTriggered()
-	if err != nil {
-		return nil, fmt.Errorf("could not retrieve epoch fallback status: %w", err)
-	}
-	epochStatus, err := m.epochStatus(candidate.Header, epochFallbackTriggered)
+	epochStatus, err := m.epochStatus(candidate.Header)
 	if err != nil {
 		return nil, fmt.Errorf("could not determine epoch status for candidate block: %w", err)
 	}
@@ -976,6 +1079,8 @@ func (m *FollowerState) handleEpochServiceEvents(candidate *flow.Block) (dbUpdat
 	if err != nil {
 		return nil, fmt.Errorf("could not retrieve current epoch setup event: %w", err)
 	}
+	// whether epoch fallback is in effect when applying the service events sealed by the parent
+	epochFallbackTriggered := epochStatus.EpochFallbackTriggered
 
 	// always persist the candidate's epoch status
 	// note: We are scheduling the operation to store the Epoch status using the _pointer_ variable `epochStatus`.
@@ -983,8 +1088,21 @@ func (m *FollowerState) handleEpochServiceEvents(candidate *flow.Block) (dbUpdat
 	blockID := candidate.ID()
 	dbUpdates = append(dbUpdates, m.epoch.statuses.StoreTx(blockID, epochStatus))
 
-	// never process service events after epoch fallback is triggered
-	if epochStatus.InvalidServiceEventIncorporated || epochFallbackTriggered {
+	// once all service events are applied, determine whether the candidate triggers epoch fallback in its fork
+	defer func() {
+		if err != nil || epochStatus.EpochFallbackTriggered {
+			return
+		}
+		epochStatus.EpochFallbackTriggered, err = m.epochFallbackTriggeredByBlock(candidate.Header, epochStatus, activeSetup)
+		if err != nil {
+			dbUpdates = nil
+			err = fmt.Errorf("could not check whether candidate block triggers epoch fallback: %w", err)
+		}
+	}()
+
+	// never process service events after an invalid service event was incorporated in this fork
+	// or after epoch fallback is triggered - with the exception of EpochRecover events (see below)
+	if epochStatus.InvalidServiceEventIncorporated && !epochFallbackTriggered {
 		return dbUpdates, nil
 	}
 
@@ -1016,6 +1134,38 @@ func (m *FollowerState) handleEpochServiceEvents(candidate *flow.Block) (dbUpdat
 
 		for _, event := range result.ServiceEvents {
 
+			// After epoch fallback is triggered, the only service event we accept is an
+			// EpochRecover event, which defines the recovery epoch and exits EECC once
+			// the first block of the recovery epoch is finalized.
+			if epochFallbackTriggered {
+				ev, ok := event.Event.(*flow.EpochRecover)
+				if !ok {
+					continue
+				}
+				safetyThreshold, err := m.Params().EpochCommitSafetyThreshold()
+				if err != nil {
+					return nil, fmt.Errorf("could not get epoch commit safety threshold: %w", err)
+				}
+				err = isValidEpochRecover(ev, activeSetup, epochStatus, candidate.Header.View, safetyThreshold)
+				if err != nil {
+					if protocol.IsInvalidServiceEventError(err) {
+						// we are already in epoch fallback mode, an invalid recovery event is ignored
+						// and governance can issue another one
+						continue
+					}
+					return nil, fmt.Errorf("unexpected error validating EpochRecover service event: %w", err)
+				}
+
+				// the recovery epoch replaces any EpochSetup event for the next epoch observed before EECC
+				epochStatus.NextEpoch.SetupID = ev.EpochSetup.ID()
+				epochStatus.NextEpoch.CommitID = ev.EpochCommit.ID()
+
+				// we'll insert the setup and commit events when we insert the block
+				dbUpdates = append(dbUpdates, m.epoch.setups.StoreTx(&ev.EpochSetup))
+				dbUpdates = append(dbUpdates, m.epoch.commits.StoreTx(&ev.EpochCommit))
+				continue
+			}
+
 			switch ev := event.Event.(type) {
 			case *flow.EpochSetup:
 				// validate the service event
@@ -1071,6 +1221,10 @@ func (m *FollowerState) handleEpochServiceEvents(candidate *flow.Block) (dbUpdat
 				// we'll insert the commit event when we insert the block
 				dbUpdates = append(dbUpdates, m.epoch.commits.StoreTx(ev))
 
+			case *flow.EpochRecover:
+				// recovery events are only meaningful in epoch fallback mode, otherwise ignore them
+				continue
+
 			default:
 				return nil, fmt.Errorf("invalid service event type (type_name=%s, go_type=%T)", event.Type, ev)
 			}
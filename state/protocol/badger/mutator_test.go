@@ -1755,6 +1755,318 @@ func TestEmergencyEpochFallback(t *testing.T) {
 			metricsMock.AssertNotCalled(t, "CurrentEpochCounter", epoch2Setup.Counter)
 		})
 	})
+
+	// if a valid EpochRecover service event is incorporated after EECC was triggered, we should:
+	//  * commit the recovery epoch when the service event comes into effect
+	//  * continue the current epoch until the recovery epoch starts
+	//  * exit EECC when the first block of the recovery epoch is finalized
+	//
+	//       Epoch Commitment Deadline                Epoch Boundary     Recovery Epoch Start
+	//       |                                        |                  |
+	//       v                                        v                  v
+	// ROOT <- B1 <- B2(R1) <- B3(S1) <- B4 <- ... <- B5 <- ... <- B6 <- B7
+	t.Run("recovery epoch committed in EECC - should exit EECC on first block of recovery epoch", func(t *testing.T) {
+
+		rootSnapshot := unittest.RootSnapshotFixture(participants)
+		metricsMock := mockmodule.NewComplianceMetrics(t)
+		mockMetricsForRootSnapshot(metricsMock, rootSnapshot)
+		protoEventsMock := mockprotocol.NewConsumer(t)
+		protoEventsMock.On("BlockFinalized", mock.Anything)
+		protoEventsMock.On("BlockProcessable", mock.Anything, mock.Anything)
+
+		util.RunWithFullProtocolStateAndMetricsAndConsumer(t, rootSnapshot, metricsMock, protoEventsMock, func(db *badger.DB, state *protocol.ParticipantState) {
+			head, err := rootSnapshot.Head()
+			require.NoError(t, err)
+			result, _, err := rootSnapshot.SealedResult()
+			require.NoError(t, err)
+			safetyThreshold, err := rootSnapshot.Params().EpochCommitSafetyThreshold()
+			require.NoError(t, err)
+
+			epoch1Setup := result.ServiceEvents[0].Event.(*flow.EpochSetup)
+			epoch1FinalView := epoch1Setup.FinalView
+			epoch1CommitmentDeadline := epoch1FinalView - safetyThreshold
+
+			// finalizing block 1 should trigger EECC
+			metricsMock.On("EpochEmergencyFallbackTriggered").Once()
+			protoEventsMock.On("EpochEmergencyFallbackTriggered").Once()
+
+			// block 1 will be the first block on or past the epoch commitment deadline
+			block1 := unittest.BlockWithParentFixture(head)
+			block1.SetPayload(flow.EmptyPayload())
+			block1.Header.View = epoch1CommitmentDeadline
+			err = state.Extend(context.Background(), block1)
+			require.NoError(t, err)
+			err = state.Finalize(context.Background(), block1.ID())
+			require.NoError(t, err)
+			assertEpochEmergencyFallbackTriggered(t, state, true)
+
+			// create the recovery epoch, which starts some time after the current epoch ends
+			recoveryFirstView := epoch1FinalView + 100
+			epochRecover := unittest.EpochRecoverFixture(
+				unittest.WithParticipants(participants),
+				unittest.SetupWithCounter(epoch1Setup.Counter+1),
+				unittest.WithFirstView(recoveryFirstView),
+				unittest.WithFinalView(recoveryFirstView+1000),
+			)
+
+			receipt1, seal1 := unittest.ReceiptAndSealForBlock(block1)
+			receipt1.ExecutionResult.ServiceEvents = []flow.ServiceEvent{epochRecover.ServiceEvent()}
+			seal1.ResultID = receipt1.ExecutionResult.ID()
+
+			// add a block containing a receipt for block 1
+			block2 := unittest.BlockWithParentFixture(block1.Header)
+			block2.SetPayload(unittest.PayloadFixture(unittest.WithReceipts(receipt1)))
+			err = state.Extend(context.Background(), block2)
+			require.NoError(t, err)
+			err = state.Finalize(context.Background(), block2.ID())
+			require.NoError(t, err)
+
+			// block 3 seals block 1
+			block3 := unittest.BlockWithParentFixture(block2.Header)
+			block3.SetPayload(flow.Payload{
+				Seals: []*flow.Seal{seal1},
+			})
+			err = state.Extend(context.Background(), block3)
+			require.NoError(t, err)
+			err = state.Finalize(context.Background(), block3.ID())
+			require.NoError(t, err)
+
+			// block 4 is where the recovery epoch is committed
+			block4 := unittest.BlockWithParentFixture(block3.Header)
+			err = state.Extend(context.Background(), block4)
+			require.NoError(t, err)
+			phase, err := state.AtBlockID(block4.ID()).Phase()
+			require.NoError(t, err)
+			assert.Equal(t, flow.EpochPhaseCommitted, phase)
+
+			// finalizing block 4 should emit the EpochCommittedPhaseStarted event, but no EpochSetupPhaseStarted
+			metricsMock.On("CurrentEpochPhase", flow.EpochPhaseCommitted).Once()
+			metricsMock.On("CommittedEpochFinalView", epochRecover.EpochSetup.FinalView).Once()
+			protoEventsMock.On("EpochCommittedPhaseStarted", epoch1Setup.Counter, block4.Header).Once()
+			err = state.Finalize(context.Background(), block4.ID())
+			require.NoError(t, err)
+			assertEpochEmergencyFallbackTriggered(t, state, true)
+
+			// block 5 is past the current epoch boundary, but before the recovery epoch starts
+			block5 := unittest.BlockWithParentFixture(block4.Header)
+			block5.Header.View = epoch1FinalView + 1
+			err = state.Extend(context.Background(), block5)
+			require.NoError(t, err)
+			err = state.Finalize(context.Background(), block5.ID())
+			require.NoError(t, err)
+			counter, err := state.AtBlockID(block5.ID()).Epochs().Current().Counter()
+			require.NoError(t, err)
+			assert.Equal(t, epoch1Setup.Counter, counter)
+
+			// block 6 is the last block before the recovery epoch starts
+			block6 := unittest.BlockWithParentFixture(block5.Header)
+			block6.Header.View = recoveryFirstView - 1
+			err = state.Extend(context.Background(), block6)
+			require.NoError(t, err)
+			err = state.Finalize(context.Background(), block6.ID())
+			require.NoError(t, err)
+			assertEpochEmergencyFallbackTriggered(t, state, true)
+
+			// block 7 is the first block of the recovery epoch
+			block7 := unittest.BlockWithParentFixture(block6.Header)
+			block7.Header.View = recoveryFirstView
+			err = state.Extend(context.Background(), block7)
+			require.NoError(t, err)
+			counter, err = state.AtBlockID(block7.ID()).Epochs().Current().Counter()
+			require.NoError(t, err)
+			assert.Equal(t, epochRecover.EpochSetup.Counter, counter)
+
+			// finalizing block 7 should transition to the recovery epoch and exit EECC
+			metricsMock.On("CurrentEpochCounter", epochRecover.EpochSetup.Counter).Once()
+			metricsMock.On("CurrentEpochPhase", flow.EpochPhaseStaking)
+			metricsMock.On("CurrentEpochFinalView", epochRecover.EpochSetup.FinalView).Once()
+			metricsMock.On("CurrentDKGPhase1FinalView", epochRecover.EpochSetup.DKGPhase1FinalView)
+			metricsMock.On("CurrentDKGPhase2FinalView", epochRecover.EpochSetup.DKGPhase2FinalView)
+			metricsMock.On("CurrentDKGPhase3FinalView", epochRecover.EpochSetup.DKGPhase3FinalView)
+			metricsMock.On("EpochEmergencyFallbackExited").Once()
+			protoEventsMock.On("EpochTransition", epochRecover.EpochSetup.Counter, block7.Header).Once()
+			err = state.Finalize(context.Background(), block7.ID())
+			require.NoError(t, err)
+			assertEpochEmergencyFallbackTriggered(t, state, false)
+
+			firstHeight, err := state.AtBlockID(block7.ID()).Epochs().Current().FirstHeight()
+			require.NoError(t, err)
+			assert.Equal(t, block7.Header.Height, firstHeight)
+		})
+	})
+
+	// whether an EpochRecover service event is applied must only depend on the fork it is
+	// incorporated in, not on the local finalization progress of the node:
+	//  * in the fork which passed the epoch commitment deadline, the recovery epoch is committed,
+	//    even though no block of this fork is finalized yet
+	//  * in the conflicting fork, which did not pass the deadline, the same event is ignored
+	//
+	//       Epoch Commitment Deadline
+	//       |
+	//       v
+	// ROOT <- B1 <- B2(R1) <- B3(S1) <- B4
+	//   ^
+	//   +-- B1' <- B2'(R1') <- B3'(S1') <- B4'
+	t.Run("recovery epoch is applied per fork, independent of finalization", func(t *testing.T) {
+
+		rootSnapshot := unittest.RootSnapshotFixture(participants)
+		metricsMock := mockmodule.NewComplianceMetrics(t)
+		mockMetricsForRootSnapshot(metricsMock, rootSnapshot)
+		protoEventsMock := mockprotocol.NewConsumer(t)
+		protoEventsMock.On("BlockProcessable", mock.Anything, mock.Anything)
+
+		util.RunWithFullProtocolStateAndMetricsAndConsumer(t, rootSnapshot, metricsMock, protoEventsMock, func(db *badger.DB, state *protocol.ParticipantState) {
+			head, err := rootSnapshot.Head()
+			require.NoError(t, err)
+			result, _, err := rootSnapshot.SealedResult()
+			require.NoError(t, err)
+			safetyThreshold, err := rootSnapshot.Params().EpochCommitSafetyThreshold()
+			require.NoError(t, err)
+
+			epoch1Setup := result.ServiceEvents[0].Event.(*flow.EpochSetup)
+			epoch1FinalView := epoch1Setup.FinalView
+			epoch1CommitmentDeadline := epoch1FinalView - safetyThreshold
+
+			recoveryFirstView := epoch1FinalView + 100
+			epochRecover := unittest.EpochRecoverFixture(
+				unittest.WithParticipants(participants),
+				unittest.SetupWithCounter(epoch1Setup.Counter+1),
+				unittest.WithFirstView(recoveryFirstView),
+				unittest.WithFinalView(recoveryFirstView+1000),
+			)
+
+			// extendFork extends the state with a fork starting at the given view, which incorporates
+			// and seals the EpochRecover service event, and returns the head of the fork
+			extendFork := func(view uint64) *flow.Block {
+				block1 := unittest.BlockWithParentFixture(head)
+				block1.SetPayload(flow.EmptyPayload())
+				block1.Header.View = view
+				err := state.Extend(context.Background(), block1)
+				require.NoError(t, err)
+
+				receipt1, seal1 := unittest.ReceiptAndSealForBlock(block1)
+				receipt1.ExecutionResult.ServiceEvents = []flow.ServiceEvent{epochRecover.ServiceEvent()}
+				seal1.ResultID = receipt1.ExecutionResult.ID()
+
+				block2 := unittest.BlockWithParentFixture(block1.Header)
+				block2.SetPayload(unittest.PayloadFixture(unittest.WithReceipts(receipt1)))
+				err = state.Extend(context.Background(), block2)
+				require.NoError(t, err)
+
+				block3 := unittest.BlockWithParentFixture(block2.Header)
+				block3.SetPayload(flow.Payload{
+					Seals: []*flow.Seal{seal1},
+				})
+				err = state.Extend(context.Background(), block3)
+				require.NoError(t, err)
+
+				block4 := unittest.BlockWithParentFixture(block3.Header)
+				err = state.Extend(context.Background(), block4)
+				require.NoError(t, err)
+				return block4
+			}
+
+			// the fork past the epoch commitment deadline is in EECC, hence commits the recovery epoch
+			block4 := extendFork(epoch1CommitmentDeadline)
+			phase, err := state.AtBlockID(block4.ID()).Phase()
+			require.NoError(t, err)
+			assert.Equal(t, flow.EpochPhaseCommitted, phase)
+
+			// the conflicting fork before the deadline is not in EECC, hence ignores the recovery epoch
+			block4Conflicting := extendFork(epoch1CommitmentDeadline - 100)
+			phase, err = state.AtBlockID(block4Conflicting.ID()).Phase()
+			require.NoError(t, err)
+			assert.Equal(t, flow.EpochPhaseStaking, phase)
+
+			// nothing was finalized, so epoch fallback is not (yet) triggered globally
+			assertEpochEmergencyFallbackTriggered(t, state, false)
+		})
+	})
+
+	// if an invalid EpochRecover service event is incorporated after EECC was triggered,
+	// it should be ignored and the current epoch should continue
+	//
+	//       Epoch Commitment Deadline
+	//       |
+	//       v
+	// ROOT <- B1 <- B2(R1) <- B3(S1) <- B4
+	t.Run("invalid recovery epoch in EECC - should be ignored", func(t *testing.T) {
+
+		rootSnapshot := unittest.RootSnapshotFixture(participants)
+		metricsMock := mockmodule.NewComplianceMetrics(t)
+		mockMetricsForRootSnapshot(metricsMock, rootSnapshot)
+		protoEventsMock := mockprotocol.NewConsumer(t)
+		protoEventsMock.On("BlockFinalized", mock.Anything)
+		protoEventsMock.On("BlockProcessable", mock.Anything, mock.Anything)
+
+		util.RunWithFullProtocolStateAndMetricsAndConsumer(t, rootSnapshot, metricsMock, protoEventsMock, func(db *badger.DB, state *protocol.ParticipantState) {
+			head, err := rootSnapshot.Head()
+			require.NoError(t, err)
+			result, _, err := rootSnapshot.SealedResult()
+			require.NoError(t, err)
+			safetyThreshold, err := rootSnapshot.Params().EpochCommitSafetyThreshold()
+			require.NoError(t, err)
+
+			epoch1Setup := result.ServiceEvents[0].Event.(*flow.EpochSetup)
+			epoch1FinalView := epoch1Setup.FinalView
+			epoch1CommitmentDeadline := epoch1FinalView - safetyThreshold
+
+			// finalizing block 1 should trigger EECC
+			metricsMock.On("EpochEmergencyFallbackTriggered").Once()
+			protoEventsMock.On("EpochEmergencyFallbackTriggered").Once()
+
+			block1 := unittest.BlockWithParentFixture(head)
+			block1.SetPayload(flow.EmptyPayload())
+			block1.Header.View = epoch1CommitmentDeadline
+			err = state.Extend(context.Background(), block1)
+			require.NoError(t, err)
+			err = state.Finalize(context.Background(), block1.ID())
+			require.NoError(t, err)
+			assertEpochEmergencyFallbackTriggered(t, state, true)
+
+			// the recovery epoch is invalid, because it overlaps with the current epoch
+			epochRecover := unittest.EpochRecoverFixture(
+				unittest.WithParticipants(participants),
+				unittest.SetupWithCounter(epoch1Setup.Counter+1),
+				unittest.WithFirstView(epoch1FinalView),
+				unittest.WithFinalView(epoch1FinalView+1000),
+			)
+
+			receipt1, seal1 := unittest.ReceiptAndSealForBlock(block1)
+			receipt1.ExecutionResult.ServiceEvents = []flow.ServiceEvent{epochRecover.ServiceEvent()}
+			seal1.ResultID = receipt1.ExecutionResult.ID()
+
+			block2 := unittest.BlockWithParentFixture(block1.Header)
+			block2.SetPayload(unittest.PayloadFixture(unittest.WithReceipts(receipt1)))
+			err = state.Extend(context.Background(), block2)
+			require.NoError(t, err)
+			err = state.Finalize(context.Background(), block2.ID())
+			require.NoError(t, err)
+
+			block3 := unittest.BlockWithParentFixture(block2.Header)
+			block3.SetPayload(flow.Payload{
+				Seals: []*flow.Seal{seal1},
+			})
+			err = state.Extend(context.Background(), block3)
+			require.NoError(t, err)
+			err = state.Finalize(context.Background(), block3.ID())
+			require.NoError(t, err)
+
+			// block 4 is where the recovery epoch would come into effect
+			block4 := unittest.BlockWithParentFixture(block3.Header)
+			err = state.Extend(context.Background(), block4)
+			require.NoError(t, err)
+			err = state.Finalize(context.Background(), block4.ID())
+			require.NoError(t, err)
+
+			// the invalid recovery epoch should have been ignored
+			phase, err := state.AtBlockID(block4.ID()).Phase()
+			require.NoError(t, err)
+			assert.Equal(t, flow.EpochPhaseStaking, phase)
+			assertEpochEmergencyFallbackTriggered(t, state, true)
+			protoEventsMock.AssertNotCalled(t, "EpochCommittedPhaseStarted", mock.Anything, mock.Anything)
+		})
+	})
 }
 
 func TestExtendInvalidSealsInBlock(t *testing.T) {
@@ -1,6 +1,7 @@
 package badger
 
 import (
+	"errors"
 	"fmt"
 
 	"github.com/onflow/flow-go/consensus/hotstuff/committees"
@@ -193,6 +194,58 @@ func isValidEpochCommit(commit *flow.EpochCommit, setup *flow.EpochSetup) error
 	return nil
 }
 
+// isValidEpochRecover checks whether an epoch recover service event being added
+// to the state while in epoch emergency fallback mode (EECC) is valid. The recovery
+// epoch must directly follow the current epoch (which is continued by EECC) and
+// must begin at least `safetyThreshold` views after the incorporating block, so
+// all nodes learn about the recovery epoch before it starts. Both the setup and
+// commit parts of the event must be intrinsically valid.
+// Assumes all inputs besides recover are already validated.
+// Expected errors during normal operations:
+// * protocol.InvalidServiceEventError if the recover service event is invalid
+func isValidEpochRecover(recover *flow.EpochRecover, activeSetup *flow.EpochSetup, status *flow.EpochStatus, view uint64, safetyThreshold uint64) error {
+	// We should only have a single recovery epoch.
+	if status.NextEpoch.CommitID != flow.ZeroID {
+		return protocol.NewInvalidServiceEventErrorf("next epoch already committed: %x", status.NextEpoch.CommitID)
+	}
+
+	setup := &recover.EpochSetup
+	if setup.Counter != activeSetup.Counter+1 {
+		return protocol.NewInvalidServiceEventErrorf("recovery epoch has invalid counter (%d => %d)", activeSetup.Counter, setup.Counter)
+	}
+
+	// The recovery epoch may only start after the current epoch, as the current epoch's views
+	// have already been assigned. Views between the final view of the current epoch and the
+	// first view of the recovery epoch are covered by the EECC extension of the current epoch.
+	if setup.FirstView <= activeSetup.FinalView {
+		return protocol.NewInvalidServiceEventErrorf(
+			"recovery epoch first view must be greater than current epoch final view (%d <= %d)",
+			setup.FirstView,
+			activeSetup.FinalView,
+		)
+	}
+	if setup.FirstView < view+safetyThreshold {
+		return protocol.NewInvalidServiceEventErrorf(
+			"recovery epoch first view must be at least %d views after incorporating view (%d < %d+%d)",
+			safetyThreshold,
+			setup.FirstView,
+			view,
+			safetyThreshold,
+		)
+	}
+
+	err := verifyEpochSetup(setup, true)
+	if err != nil {
+		return protocol.NewInvalidServiceEventErrorf("invalid recovery epoch setup: %w", err)
+	}
+	err = isValidEpochCommit(&recover.EpochCommit, setup)
+	if err != nil {
+		return protocol.NewInvalidServiceEventErrorf("invalid recovery epoch commit: %w", err)
+	}
+
+	return nil
+}
+
 // IsValidRootSnapshot checks internal consistency of root state snapshot
 // if verifyResultID allows/disallows Result ID verification
 func IsValidRootSnapshot(snap protocol.Snapshot, verifyResultID bool) error {
@@ -268,6 +321,112 @@ func IsValidRootSnapshot(snap protocol.Snapshot, verifyResultID bool) error {
 	return nil
 }
 
+// IsValidRootSnapshotEpochs checks internal consistency of the epoch information included
+// in the root state snapshot. The setup and commit events of each epoch must be valid and
+// coherent with each other, and the previous and next epochs (if they exist) must be
+// contiguous with the current epoch. The epoch phase must match the available next epoch
+// information.
+// The boolean parameter `verifyNetworkAddress` controls, whether we want to permit
+// nodes to share a networking address.
+func IsValidRootSnapshotEpochs(snap protocol.Snapshot, verifyNetworkAddress bool) error {
+	epochs := snap.Epochs()
+
+	// current epoch - both setup and commit events must exist
+	currentSetup, err := protocol.ToEpochSetup(epochs.Current())
+	if err != nil {
+		return fmt.Errorf("could not get current epoch setup event: %w", err)
+	}
+	currentCommit, err := protocol.ToEpochCommit(epochs.Current())
+	if err != nil {
+		return fmt.Errorf("could not get current epoch commit event: %w", err)
+	}
+	err = verifyEpochSetup(currentSetup, verifyNetworkAddress)
+	if err != nil {
+		return fmt.Errorf("invalid current epoch setup: %w", err)
+	}
+	err = isValidEpochCommit(currentCommit, currentSetup)
+	if err != nil {
+		return fmt.Errorf("invalid current epoch commit: %w", err)
+	}
+
+	// previous epoch, if it exists - both setup and commit events must exist
+	previousSetup, err := protocol.ToEpochSetup(epochs.Previous())
+	if err == nil {
+		previousCommit, err := protocol.ToEpochCommit(epochs.Previous())
+		if err != nil {
+			return fmt.Errorf("could not get previous epoch commit event: %w", err)
+		}
+		err = verifyEpochSetup(previousSetup, verifyNetworkAddress)
+		if err != nil {
+			return fmt.Errorf("invalid previous epoch setup: %w", err)
+		}
+		err = isValidEpochCommit(previousCommit, previousSetup)
+		if err != nil {
+			return fmt.Errorf("invalid previous epoch commit: %w", err)
+		}
+		if currentSetup.Counter != previousSetup.Counter+1 {
+			return fmt.Errorf("current epoch has invalid counter (%d => %d)", previousSetup.Counter, currentSetup.Counter)
+		}
+		if currentSetup.FirstView != previousSetup.FinalView+1 {
+			return fmt.Errorf("current epoch first view must be exactly 1 more than previous epoch final view (%d != %d+1)",
+				currentSetup.FirstView, previousSetup.FinalView)
+		}
+	} else if !errors.Is(err, protocol.ErrNoPreviousEpoch) {
+		return fmt.Errorf("could not get previous epoch setup event: %w", err)
+	}
+
+	phase, err := snap.Phase()
+	if err != nil {
+		return fmt.Errorf("could not get epoch phase: %w", err)
+	}
+
+	// next epoch, if it exists - either only the setup event, or both the setup and commit events must exist
+	nextSetup, err := protocol.ToEpochSetup(epochs.Next())
+	if errors.Is(err, protocol.ErrNextEpochNotSetup) {
+		if phase != flow.EpochPhaseStaking {
+			return fmt.Errorf("missing next epoch in epoch phase %s", phase)
+		}
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("could not get next epoch setup event: %w", err)
+	}
+	if phase == flow.EpochPhaseStaking {
+		return fmt.Errorf("unexpected next epoch in epoch phase %s", phase)
+	}
+	err = verifyEpochSetup(nextSetup, verifyNetworkAddress)
+	if err != nil {
+		return fmt.Errorf("invalid next epoch setup: %w", err)
+	}
+	if nextSetup.Counter != currentSetup.Counter+1 {
+		return fmt.Errorf("next epoch has invalid counter (%d => %d)", currentSetup.Counter, nextSetup.Counter)
+	}
+	if nextSetup.FirstView != currentSetup.FinalView+1 {
+		return fmt.Errorf("next epoch first view must be exactly 1 more than current epoch final view (%d != %d+1)",
+			nextSetup.FirstView, currentSetup.FinalView)
+	}
+
+	nextCommit, err := protocol.ToEpochCommit(epochs.Next())
+	if errors.Is(err, protocol.ErrNextEpochNotCommitted) {
+		if phase != flow.EpochPhaseSetup {
+			return fmt.Errorf("missing next epoch commit in epoch phase %s", phase)
+		}
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("could not get next epoch commit event: %w", err)
+	}
+	if phase != flow.EpochPhaseCommitted {
+		return fmt.Errorf("unexpected next epoch commit in epoch phase %s", phase)
+	}
+	err = isValidEpochCommit(nextCommit, nextSetup)
+	if err != nil {
+		return fmt.Errorf("invalid next epoch commit: %w", err)
+	}
+
+	return nil
+}
+
 // IsValidRootSnapshotQCs checks internal consistency of QCs that are included in the root state snapshot
 // It verifies QCs for main consensus and for each collection cluster.
 func IsValidRootSnapshotQCs(snap protocol.Snapshot) error {
@@ -9,6 +9,7 @@ import (
 	"github.com/onflow/flow-go/crypto"
 	"github.com/onflow/flow-go/model/flow"
 	"github.com/onflow/flow-go/model/flow/filter"
+	"github.com/onflow/flow-go/state/protocol/inmem"
 	"github.com/onflow/flow-go/utils/unittest"
 )
 
@@ -145,3 +146,106 @@ func TestEntityExpirySnapshotValidation(t *testing.T) {
 		require.NoError(t, err)
 	})
 }
+
+// TestRootSnapshotEpochsValidation tests that we check the consistency of the
+// epoch information included in a root snapshot.
+func TestRootSnapshotEpochsValidation(t *testing.T) {
+	// epochsFixture returns an encodable root snapshot, whose current epoch spans views
+	// [10_000, 19_999], together with a previous epoch spanning views [0, 9_999] and a
+	// next epoch spanning views [20_000, 29_999], which are contiguous with the current
+	// epoch. The previous and next epoch are not included in the snapshot by default.
+	epochsFixture := func() (*inmem.EncodableSnapshot, inmem.EncodableEpoch, inmem.EncodableEpoch) {
+		encodable := unittest.RootSnapshotFixture(participants).Encodable()
+		encodable.Epochs.Current.FirstView = 10_000
+		encodable.Epochs.Current.DKGPhase1FinalView = 10_100
+		encodable.Epochs.Current.DKGPhase2FinalView = 10_200
+		encodable.Epochs.Current.DKGPhase3FinalView = 10_300
+		encodable.Epochs.Current.FinalView = 19_999
+		current := encodable.Epochs.Current
+
+		previous := current
+		previous.Counter = current.Counter - 1
+		previous.FirstView = 0
+		previous.DKGPhase1FinalView = 100
+		previous.DKGPhase2FinalView = 200
+		previous.DKGPhase3FinalView = 300
+		previous.FinalView = 9_999
+
+		next := current
+		next.Counter = current.Counter + 1
+		next.FirstView = 20_000
+		next.DKGPhase1FinalView = 20_100
+		next.DKGPhase2FinalView = 20_200
+		next.DKGPhase3FinalView = 20_300
+		next.FinalView = 29_999
+
+		return &encodable, previous, next
+	}
+	validate := func(encodable *inmem.EncodableSnapshot) error {
+		return IsValidRootSnapshotEpochs(inmem.SnapshotFromEncodable(*encodable), true)
+	}
+
+	t.Run("valid", func(t *testing.T) {
+		encodable, _, _ := epochsFixture()
+		require.NoError(t, validate(encodable))
+	})
+	t.Run("invalid current epoch final view", func(t *testing.T) {
+		encodable, _, _ := epochsFixture()
+		encodable.Epochs.Current.FinalView = encodable.Epochs.Current.FirstView
+		require.ErrorContains(t, validate(encodable), "invalid current epoch setup")
+	})
+	t.Run("contiguous previous epoch", func(t *testing.T) {
+		encodable, previous, _ := epochsFixture()
+		encodable.Epochs.Previous = &previous
+		require.NoError(t, validate(encodable))
+	})
+	t.Run("overlapping previous epoch", func(t *testing.T) {
+		encodable, previous, _ := epochsFixture()
+		previous.FinalView = 10_000
+		encodable.Epochs.Previous = &previous
+		require.ErrorContains(t, validate(encodable), "current epoch first view must be exactly 1 more than previous epoch final view")
+	})
+	t.Run("non-contiguous previous epoch", func(t *testing.T) {
+		encodable, previous, _ := epochsFixture()
+		previous.FinalView = 9_989
+		encodable.Epochs.Previous = &previous
+		require.ErrorContains(t, validate(encodable), "current epoch first view must be exactly 1 more than previous epoch final view")
+	})
+	t.Run("previous epoch with invalid counter", func(t *testing.T) {
+		encodable, previous, _ := epochsFixture()
+		previous.Counter = encodable.Epochs.Current.Counter
+		encodable.Epochs.Previous = &previous
+		require.ErrorContains(t, validate(encodable), "current epoch has invalid counter")
+	})
+	t.Run("committed next epoch", func(t *testing.T) {
+		encodable, _, next := epochsFixture()
+		encodable.Epochs.Next = &next
+		encodable.Phase = flow.EpochPhaseCommitted
+		require.NoError(t, validate(encodable))
+	})
+	t.Run("next epoch in staking phase", func(t *testing.T) {
+		encodable, _, next := epochsFixture()
+		encodable.Epochs.Next = &next
+		encodable.Phase = flow.EpochPhaseStaking
+		require.ErrorContains(t, validate(encodable), "unexpected next epoch in epoch phase")
+	})
+	t.Run("missing next epoch in setup phase", func(t *testing.T) {
+		encodable, _, _ := epochsFixture()
+		encodable.Phase = flow.EpochPhaseSetup
+		require.ErrorContains(t, validate(encodable), "missing next epoch in epoch phase")
+	})
+	t.Run("non-contiguous next epoch", func(t *testing.T) {
+		encodable, _, next := epochsFixture()
+		next.FirstView = 20_010
+		encodable.Epochs.Next = &next
+		encodable.Phase = flow.EpochPhaseCommitted
+		require.ErrorContains(t, validate(encodable), "next epoch first view must be exactly 1 more than current epoch final view")
+	})
+	t.Run("next epoch with invalid counter", func(t *testing.T) {
+		encodable, _, next := epochsFixture()
+		next.Counter = encodable.Epochs.Current.Counter + 2
+		encodable.Epochs.Next = &next
+		encodable.Phase = flow.EpochPhaseCommitted
+		require.ErrorContains(t, validate(encodable), "next epoch has invalid counter")
+	})
+}
@@ -1,6 +1,10 @@
 package seed
 
-import "encoding/binary"
+import (
+	"encoding/binary"
+
+	"github.com/onflow/flow-go/model/flow"
+)
 
 // list of customizers used for different sub-protocol PRNGs.
 // These customizers help instantiate different PRNGs from the
@@ -15,6 +19,10 @@ var (
 	collectorClusterLeaderSelectionPrefix = []uint16{0, 0}
 	// executionChunkPrefix is the prefix of the customizer for executing chunks
 	executionChunkPrefix = []uint16{1}
+	// networkTopologyRingPrefix is the prefix of the customizer for the ring of the nodes of a role in the network topology
+	networkTopologyRingPrefix = []uint16{2, 0}
+	// NetworkTopologySampling is the customizer for the sampling of the fanout of a node in the network topology
+	NetworkTopologySampling = customizerFromIndices([]uint16{2, 1})
 )
 
 // ProtocolCollectorClusterLeaderSelection returns the indices for the leader selection for the i-th collector cluster
@@ -29,6 +37,12 @@ func ExecutionChunk(chunkIndex uint16) []byte {
 	return customizerFromIndices(indices)
 }
 
+// NetworkTopologyRing returns the indices for the ring of the nodes of the given role in the network topology
+func NetworkTopologyRing(role flow.Role) []byte {
+	indices := append(networkTopologyRingPrefix, uint16(role))
+	return customizerFromIndices(indices)
+}
+
 // customizerFromIndices maps the input indices into a slice of bytes.
 // The implementation ensures there are no collisions of mapping of different indices.
 //
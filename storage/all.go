@@ -20,4 +20,5 @@ type All struct {
 	TransactionResults TransactionResults
 	Collections        Collections
 	Events             Events
+	PayloadPruner      PayloadPruner
 }
@@ -27,6 +27,7 @@ func InitAll(metrics module.CacheMetrics, db *badger.DB) *storage.All {
 	collections := NewCollections(db, transactions)
 	events := NewEvents(metrics, db)
 	chunkDataPacks := NewChunkDataPacks(metrics, db, collections, 1000)
+	payloadPruner := NewPayloadPruner(db, index, guarantees, receipts, transactions)
 
 	return &storage.All{
 		Headers:            headers,
@@ -47,5 +48,6 @@ func InitAll(metrics module.CacheMetrics, db *badger.DB) *storage.All {
 		TransactionResults: transactionResults,
 		Collections:        collections,
 		Events:             events,
+		PayloadPruner:      payloadPruner,
 	}
 }
@@ -0,0 +1,767 @@
+package backup
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/dgraph-io/badger/v2"
+	"github.com/dgraph-io/badger/v2/pb"
+	"github.com/golang/protobuf/proto"
+	prometheusWAL "github.com/m4ksio/wal/wal"
+
+	"github.com/onflow/flow-go/ledger"
+	"github.com/onflow/flow-go/ledger/complete/wal"
+	"github.com/onflow/flow-go/model/bootstrap"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/storage"
+	"github.com/onflow/flow-go/storage/badger/operation"
+	utilsio "github.com/onflow/flow-go/utils/io"
+)
+
+const (
+	// ManifestFilename is the name of the file describing the content of a backup.
+	ManifestFilename = "manifest.json"
+	// ProtocolDBFilename is the name of the badger stream backup of the protocol database.
+	ProtocolDBFilename = "protocol.bak"
+	// CheckpointDir is the directory of a backup holding the ledger checkpoint files.
+	CheckpointDir = "checkpoint"
+)
+
+// bitDelete is the badger meta bit marking deleted entries in a stream backup.
+const bitDelete byte = 1 << 0
+
+// ErrInvalidBackup is returned when a backup does not match its manifest.
+var ErrInvalidBackup = errors.New("invalid backup")
+
+// File describes a single file of a backup.
+type File struct {
+	Name   string `json:"name"`
+	Size   int64  `json:"size"`
+	SHA256 string `json:"sha256"`
+}
+
+// Checkpoint describes the ledger checkpoint included in the backup of an execution node.
+type Checkpoint struct {
+	// Name is the file name of the checkpoint, e.g. "checkpoint.00000042" or "root.checkpoint".
+	Name string `json:"name"`
+	// Height, BlockID and StateCommitment identify the most recent block, up to the executed
+	// block of the backup, whose execution state is included in the checkpoint.
+	Height          uint64               `json:"height"`
+	BlockID         flow.Identifier      `json:"block_id"`
+	StateCommitment flow.StateCommitment `json:"state_commitment"`
+	Files           []File               `json:"files"`
+	// WAL holds the ledger WAL segments following the checkpoint. They are only included if the
+	// checkpoint is older than the executed block of the backup, in which case the executed state
+	// is restored by replaying the segments on top of the checkpoint.
+	WAL []File `json:"wal,omitempty"`
+}
+
+// Manifest describes the content of a backup and the protocol state it corresponds to.
+type Manifest struct {
+	CreatedAt time.Time `json:"created_at"`
+	// Version is the badger version up to which the protocol database was backed up.
+	Version          uint64          `json:"version"`
+	FinalizedHeight  uint64          `json:"finalized_height"`
+	FinalizedBlockID flow.Identifier `json:"finalized_block_id"`
+	SealedHeight     uint64          `json:"sealed_height"`
+	SealedBlockID    flow.Identifier `json:"sealed_block_id"`
+	ProtocolDB       File            `json:"protocol_db"`
+	// ExecutedHeight, ExecutedBlockID and ExecutedStateCommitment describe the highest executed
+	// block of the protocol database backup. Like Checkpoint, they are only set for backups of
+	// execution nodes.
+	ExecutedHeight          uint64               `json:"executed_height,omitempty"`
+	ExecutedBlockID         flow.Identifier      `json:"executed_block_id"`
+	ExecutedStateCommitment flow.StateCommitment `json:"executed_state_commitment"`
+	Checkpoint              *Checkpoint          `json:"checkpoint,omitempty"`
+}
+
+// Create writes a backup of the protocol database into dir, which must not exist yet.
+// The database can be in use while the backup is created: all keys are read from a single
+// read transaction, hence the backup is a consistent snapshot of the database. The finalized
+// and sealed heights recorded in the manifest are read from the backup itself.
+// If checkpointDir is not empty, the latest ledger checkpoint in checkpointDir is copied into
+// the backup as well. Checkpoints are written independently of the protocol database, so the
+// ledger state of the checkpoint is usually older than the executed block of the backup. In this
+// case, the WAL segments following the checkpoint are copied too. They are copied after the
+// protocol database was backed up, and the ledger writes the WAL before the execution of a block
+// is stored, hence the segments include the execution state of every block executed in the backup.
+// No errors are expected during normal operation.
+func Create(db *badger.DB, dir string, checkpointDir string) (*Manifest, error) {
+	if _, err := os.Stat(dir); err == nil {
+		return nil, fmt.Errorf("backup directory %s already exists", dir)
+	} else if !os.IsNotExist(err) {
+		return nil, fmt.Errorf("could not check backup directory %s: %w", dir, err)
+	}
+	err := os.MkdirAll(dir, 0700)
+	if err != nil {
+		return nil, fmt.Errorf("could not create backup directory %s: %w", dir, err)
+	}
+
+	manifest := &Manifest{
+		CreatedAt: time.Now().UTC(),
+	}
+
+	manifest.Version, err = backupProtocolDB(db, filepath.Join(dir, ProtocolDBFilename))
+	if err != nil {
+		return nil, fmt.Errorf("could not back up protocol database: %w", err)
+	}
+
+	manifest.ProtocolDB, err = describeFile(dir, ProtocolDBFilename)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot, err := readSnapshot(filepath.Join(dir, ProtocolDBFilename))
+	if err != nil {
+		return nil, fmt.Errorf("could not read heights from backup: %w", err)
+	}
+	manifest.FinalizedHeight, manifest.SealedHeight = snapshot.finalized, snapshot.sealed
+
+	// the height index is never changed for finalized heights, so it can be read from the live database
+	err = db.View(func(tx *badger.Txn) error {
+		err := operation.LookupBlockHeight(manifest.FinalizedHeight, &manifest.FinalizedBlockID)(tx)
+		if err != nil {
+			return fmt.Errorf("could not look up finalized block: %w", err)
+		}
+		err = operation.LookupBlockHeight(manifest.SealedHeight, &manifest.SealedBlockID)(tx)
+		if err != nil {
+			return fmt.Errorf("could not look up sealed block: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if checkpointDir != "" {
+		if snapshot.executed == nil {
+			return nil, fmt.Errorf("backup of protocol database has no executed block")
+		}
+		manifest.ExecutedBlockID = *snapshot.executed
+
+		// headers and state commitments are never changed once stored, so they can be read from the live database
+		err = db.View(func(tx *badger.Txn) error {
+			var header flow.Header
+			err := operation.RetrieveHeader(manifest.ExecutedBlockID, &header)(tx)
+			if err != nil {
+				return fmt.Errorf("could not retrieve executed block: %w", err)
+			}
+			manifest.ExecutedHeight = header.Height
+			err = operation.LookupStateCommitment(manifest.ExecutedBlockID, &manifest.ExecutedStateCommitment)(tx)
+			if err != nil {
+				return fmt.Errorf("could not look up state commitment of executed block: %w", err)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		manifest.Checkpoint, err = backupCheckpoint(db, checkpointDir, dir, manifest)
+		if err != nil {
+			return nil, fmt.Errorf("could not back up checkpoint: %w", err)
+		}
+	}
+
+	err = writeManifest(dir, manifest)
+	if err != nil {
+		return nil, err
+	}
+
+	return manifest, nil
+}
+
+// Validate checks that all files of the backup in dir exist and match the checksums of the
+// manifest, and that the protocol database backup can be decoded and holds the heights and
+// executed block recorded in the manifest. For backups including a checkpoint, it also checks
+// that the checkpoint includes the state commitment recorded in the manifest, and that WAL
+// segments are included if the checkpoint is older than the executed block.
+// Expected errors during normal operation:
+//   - ErrInvalidBackup if the backup does not match its manifest
+func Validate(dir string) (*Manifest, error) {
+	manifest, err := ReadManifest(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	err = validateFile(dir, manifest.ProtocolDB)
+	if err != nil {
+		return nil, err
+	}
+	if manifest.Checkpoint != nil {
+		if len(manifest.Checkpoint.Files) == 0 {
+			return nil, fmt.Errorf("%w: checkpoint %s has no files", ErrInvalidBackup, manifest.Checkpoint.Name)
+		}
+		for _, file := range manifest.Checkpoint.Files {
+			err = validateFile(filepath.Join(dir, CheckpointDir), file)
+			if err != nil {
+				return nil, err
+			}
+		}
+		for _, file := range manifest.Checkpoint.WAL {
+			err = validateFile(filepath.Join(dir, CheckpointDir), file)
+			if err != nil {
+				return nil, err
+			}
+		}
+		err = validateCheckpoint(dir, manifest)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	snapshot, err := readSnapshot(filepath.Join(dir, manifest.ProtocolDB.Name))
+	if err != nil {
+		return nil, fmt.Errorf("%w: could not decode protocol database backup: %s", ErrInvalidBackup, err)
+	}
+	if snapshot.finalized != manifest.FinalizedHeight || snapshot.sealed != manifest.SealedHeight {
+		return nil, fmt.Errorf("%w: backup holds finalized height %d and sealed height %d, manifest records %d and %d",
+			ErrInvalidBackup, snapshot.finalized, snapshot.sealed, manifest.FinalizedHeight, manifest.SealedHeight)
+	}
+	if manifest.Checkpoint != nil && (snapshot.executed == nil || *snapshot.executed != manifest.ExecutedBlockID) {
+		return nil, fmt.Errorf("%w: backup does not hold executed block %x recorded in the manifest",
+			ErrInvalidBackup, manifest.ExecutedBlockID)
+	}
+
+	return manifest, nil
+}
+
+// Restore validates the backup in dir and restores it. The protocol database is loaded into
+// datadir, which must not exist or be empty. If the backup includes a ledger checkpoint, it
+// is copied into trieDir together with the included WAL segments. trieDir must be set in this
+// case, and must not exist or be empty. After loading, the finalized, sealed and executed blocks
+// of the restored database, and the blocks of the checkpoint, are checked against the manifest.
+// If restoring fails, the content of datadir and trieDir must not be used.
+// Expected errors during normal operation:
+//   - ErrInvalidBackup if the backup does not match its manifest
+func Restore(dir string, datadir string, trieDir string) (*Manifest, error) {
+	manifest, err := Validate(dir)
+	if err != nil {
+		return nil, err
+	}
+	if manifest.Checkpoint != nil && trieDir == "" {
+		return nil, fmt.Errorf("backup includes checkpoint %s, but no trie directory was given", manifest.Checkpoint.Name)
+	}
+
+	err = ensureEmpty(datadir)
+	if err != nil {
+		return nil, err
+	}
+	if manifest.Checkpoint != nil {
+		err = ensureEmpty(trieDir)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = restoreProtocolDB(filepath.Join(dir, manifest.ProtocolDB.Name), datadir, manifest)
+	if err != nil {
+		return nil, fmt.Errorf("could not restore protocol database: %w", err)
+	}
+
+	if manifest.Checkpoint != nil {
+		_, err = wal.CopyCheckpointFile(manifest.Checkpoint.Name, filepath.Join(dir, CheckpointDir), trieDir)
+		if err != nil {
+			return nil, fmt.Errorf("could not restore checkpoint: %w", err)
+		}
+		for _, segment := range manifest.Checkpoint.WAL {
+			err = utilsio.Copy(filepath.Join(dir, CheckpointDir, segment.Name), filepath.Join(trieDir, segment.Name))
+			if err != nil {
+				return nil, fmt.Errorf("could not restore WAL segment: %w", err)
+			}
+		}
+	}
+
+	return manifest, nil
+}
+
+// ReadManifest reads the manifest of the backup in dir.
+// No errors are expected during normal operation.
+func ReadManifest(dir string) (*Manifest, error) {
+	data, err := os.ReadFile(filepath.Join(dir, ManifestFilename))
+	if err != nil {
+		return nil, fmt.Errorf("could not read manifest: %w", err)
+	}
+	var manifest Manifest
+	err = json.Unmarshal(data, &manifest)
+	if err != nil {
+		return nil, fmt.Errorf("%w: could not decode manifest: %s", ErrInvalidBackup, err)
+	}
+	return &manifest, nil
+}
+
+// backupProtocolDB writes a badger stream backup of the database into the given file.
+// The stream uses a single goroutine, so that all keys are read within the same
+// read transaction.
+func backupProtocolDB(db *badger.DB, path string) (uint64, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+	if err != nil {
+		return 0, fmt.Errorf("could not create backup file: %w", err)
+	}
+	defer file.Close()
+
+	writer := bufio.NewWriterSize(file, 1<<20)
+
+	stream := db.NewStream()
+	stream.NumGo = 1
+	stream.LogPrefix = "protocol db backup"
+	version, err := stream.Backup(writer, 0)
+	if err != nil {
+		return 0, err
+	}
+
+	err = writer.Flush()
+	if err != nil {
+		return 0, fmt.Errorf("could not flush backup file: %w", err)
+	}
+	err = file.Sync()
+	if err != nil {
+		return 0, fmt.Errorf("could not sync backup file: %w", err)
+	}
+	return version, nil
+}
+
+// restoreProtocolDB loads the backup into a new database at datadir and checks the finalized
+// and sealed blocks of the restored database against the manifest.
+func restoreProtocolDB(path string, datadir string, manifest *Manifest) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("could not open backup file: %w", err)
+	}
+	defer file.Close()
+
+	db, err := badger.Open(badger.DefaultOptions(datadir).WithLogger(nil))
+	if err != nil {
+		return fmt.Errorf("could not open database: %w", err)
+	}
+	defer db.Close()
+
+	err = db.Load(file, 256)
+	if err != nil {
+		return fmt.Errorf("could not load backup: %w", err)
+	}
+
+	return db.View(func(tx *badger.Txn) error {
+		err := checkHeights(tx, manifest)
+		if err != nil {
+			return err
+		}
+		if manifest.Checkpoint == nil {
+			return nil
+		}
+		return checkExecution(tx, manifest)
+	})
+}
+
+// checkHeights checks the finalized and sealed heights and blocks against the manifest.
+func checkHeights(tx *badger.Txn, manifest *Manifest) error {
+	var finalized, sealed uint64
+	var finalizedID, sealedID flow.Identifier
+	err := operation.RetrieveFinalizedHeight(&finalized)(tx)
+	if err != nil {
+		return fmt.Errorf("could not retrieve finalized height: %w", err)
+	}
+	err = operation.RetrieveSealedHeight(&sealed)(tx)
+	if err != nil {
+		return fmt.Errorf("could not retrieve sealed height: %w", err)
+	}
+	err = operation.LookupBlockHeight(finalized, &finalizedID)(tx)
+	if err != nil {
+		return fmt.Errorf("could not look up finalized block: %w", err)
+	}
+	err = operation.LookupBlockHeight(sealed, &sealedID)(tx)
+	if err != nil {
+		return fmt.Errorf("could not look up sealed block: %w", err)
+	}
+
+	if finalized != manifest.FinalizedHeight || finalizedID != manifest.FinalizedBlockID {
+		return fmt.Errorf("%w: restored finalized block %x at height %d, manifest records %x at height %d",
+			ErrInvalidBackup, finalizedID, finalized, manifest.FinalizedBlockID, manifest.FinalizedHeight)
+	}
+	if sealed != manifest.SealedHeight || sealedID != manifest.SealedBlockID {
+		return fmt.Errorf("%w: restored sealed block %x at height %d, manifest records %x at height %d",
+			ErrInvalidBackup, sealedID, sealed, manifest.SealedBlockID, manifest.SealedHeight)
+	}
+	return nil
+}
+
+// checkExecution checks the executed block of the restored database, and the block of the
+// checkpoint, against the manifest.
+func checkExecution(tx *badger.Txn, manifest *Manifest) error {
+	var executedID flow.Identifier
+	err := operation.RetrieveExecutedBlock(&executedID)(tx)
+	if err != nil {
+		return fmt.Errorf("could not retrieve executed block: %w", err)
+	}
+	if executedID != manifest.ExecutedBlockID {
+		return fmt.Errorf("%w: restored executed block %x, manifest records %x",
+			ErrInvalidBackup, executedID, manifest.ExecutedBlockID)
+	}
+
+	blocks := []struct {
+		name    string
+		blockID flow.Identifier
+		height  uint64
+		commit  flow.StateCommitment
+	}{
+		{"executed", manifest.ExecutedBlockID, manifest.ExecutedHeight, manifest.ExecutedStateCommitment},
+		{"checkpoint", manifest.Checkpoint.BlockID, manifest.Checkpoint.Height, manifest.Checkpoint.StateCommitment},
+	}
+	for _, block := range blocks {
+		var header flow.Header
+		err = operation.RetrieveHeader(block.blockID, &header)(tx)
+		if err != nil {
+			return fmt.Errorf("could not retrieve %s block %x: %w", block.name, block.blockID, err)
+		}
+		var commit flow.StateCommitment
+		err = operation.LookupStateCommitment(block.blockID, &commit)(tx)
+		if err != nil {
+			return fmt.Errorf("could not look up state commitment of %s block %x: %w", block.name, block.blockID, err)
+		}
+		if header.Height != block.height || commit != block.commit {
+			return fmt.Errorf("%w: restored %s block %x has height %d and state commitment %x, manifest records %d and %x",
+				ErrInvalidBackup, block.name, block.blockID, header.Height, commit, block.height, block.commit)
+		}
+	}
+	if manifest.Checkpoint.Height > manifest.ExecutedHeight {
+		return fmt.Errorf("%w: checkpoint block at height %d is above executed block at height %d",
+			ErrInvalidBackup, manifest.Checkpoint.Height, manifest.ExecutedHeight)
+	}
+	return nil
+}
+
+// snapshot holds the heights and the executed block of a protocol database backup.
+type snapshot struct {
+	finalized uint64
+	sealed    uint64
+	// executed is nil if no block was executed, which is the case for all nodes except execution nodes
+	executed *flow.Identifier
+}
+
+// readSnapshot decodes the badger stream backup in the given file and returns the finalized
+// and sealed heights and the executed block it holds. They are stored under single byte keys,
+// the latest versions of which are written into an in-memory database to read them with the
+// regular storage operations. Decoding the complete file also ensures the backup is not
+// truncated or corrupted.
+func readSnapshot(path string) (*snapshot, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("could not open backup file: %w", err)
+	}
+	defer file.Close()
+
+	latest := make(map[byte]*pb.KV)
+	reader := bufio.NewReaderSize(file, 1<<20)
+	var buf []byte
+	for {
+		var size uint64
+		err := binary.Read(reader, binary.LittleEndian, &size)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("could not read list size: %w", err)
+		}
+		if uint64(cap(buf)) < size {
+			buf = make([]byte, size)
+		}
+		_, err = io.ReadFull(reader, buf[:size])
+		if err != nil {
+			return nil, fmt.Errorf("could not read list: %w", err)
+		}
+		var list pb.KVList
+		err = proto.Unmarshal(buf[:size], &list)
+		if err != nil {
+			return nil, fmt.Errorf("could not decode list: %w", err)
+		}
+		for _, kv := range list.Kv {
+			if len(kv.Key) != 1 {
+				continue
+			}
+			if prev, ok := latest[kv.Key[0]]; !ok || prev.Version < kv.Version {
+				latest[kv.Key[0]] = kv
+			}
+		}
+	}
+
+	db, err := badger.Open(badger.DefaultOptions("").WithInMemory(true).WithLogger(nil))
+	if err != nil {
+		return nil, fmt.Errorf("could not open in-memory database: %w", err)
+	}
+	defer db.Close()
+
+	err = db.Update(func(tx *badger.Txn) error {
+		for _, kv := range latest {
+			if len(kv.Meta) > 0 && kv.Meta[0]&bitDelete != 0 {
+				continue
+			}
+			err := tx.Set(kv.Key, kv.Value)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not write keys: %w", err)
+	}
+
+	var result snapshot
+	err = db.View(func(tx *badger.Txn) error {
+		err := operation.RetrieveFinalizedHeight(&result.finalized)(tx)
+		if err != nil {
+			return fmt.Errorf("could not retrieve finalized height: %w", err)
+		}
+		err = operation.RetrieveSealedHeight(&result.sealed)(tx)
+		if err != nil {
+			return fmt.Errorf("could not retrieve sealed height: %w", err)
+		}
+		var executed flow.Identifier
+		err = operation.RetrieveExecutedBlock(&executed)(tx)
+		if errors.Is(err, storage.ErrNotFound) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("could not retrieve executed block: %w", err)
+		}
+		result.executed = &executed
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// backupCheckpoint copies the latest checkpoint in checkpointDir into the backup, together
+// with the WAL segments following it if the checkpoint does not include the state of the
+// executed block of the manifest. If there is no numbered checkpoint yet, the root checkpoint
+// is copied.
+func backupCheckpoint(db *badger.DB, checkpointDir string, dir string, manifest *Manifest) (*Checkpoint, error) {
+	_, last, err := wal.ListCheckpoints(checkpointDir)
+	if err != nil {
+		return nil, fmt.Errorf("could not list checkpoints: %w", err)
+	}
+
+	var name string
+	if last >= 0 {
+		name = wal.NumberToFilename(last)
+	} else {
+		hasRoot, err := wal.HasRootCheckpoint(checkpointDir)
+		if err != nil {
+			return nil, fmt.Errorf("could not check root checkpoint: %w", err)
+		}
+		if !hasRoot {
+			return nil, fmt.Errorf("no checkpoint found in %s", checkpointDir)
+		}
+		name = bootstrap.FilenameWALRootCheckpoint
+	}
+
+	to := filepath.Join(dir, CheckpointDir)
+	paths, err := wal.CopyCheckpointFile(name, checkpointDir, to)
+	if err != nil {
+		return nil, err
+	}
+
+	checkpoint := &Checkpoint{
+		Name:  name,
+		Files: make([]File, 0, len(paths)),
+	}
+	for _, path := range paths {
+		file, err := describeFile(to, filepath.Base(path))
+		if err != nil {
+			return nil, err
+		}
+		checkpoint.Files = append(checkpoint.Files, file)
+	}
+
+	rootHashes, err := wal.ReadTriesRootHash(to, name)
+	if err != nil {
+		return nil, fmt.Errorf("could not read root hashes of checkpoint %s: %w", name, err)
+	}
+	checkpoint.Height, checkpoint.BlockID, checkpoint.StateCommitment, err = checkpointedBlock(db, manifest.ExecutedBlockID, rootHashes)
+	if err != nil {
+		return nil, fmt.Errorf("could not find block of checkpoint %s: %w", name, err)
+	}
+	if checkpoint.StateCommitment == manifest.ExecutedStateCommitment {
+		return checkpoint, nil
+	}
+
+	// replaying starts with the segment following the checkpoint, or with the first segment for the root checkpoint
+	checkpoint.WAL, err = backupWAL(checkpointDir, to, last+1)
+	if err != nil {
+		return nil, fmt.Errorf("could not back up WAL following checkpoint %s: %w", name, err)
+	}
+	return checkpoint, nil
+}
+
+// checkpointedBlock returns the height, ID and state commitment of the most recent block, starting
+// with the given executed block and following its ancestors, whose state commitment is the root hash
+// of one of the checkpointed tries.
+func checkpointedBlock(db *badger.DB, executedID flow.Identifier, rootHashes []ledger.RootHash) (uint64, flow.Identifier, flow.StateCommitment, error) {
+	checkpointed := make(map[flow.StateCommitment]struct{}, len(rootHashes))
+	for _, rootHash := range rootHashes {
+		checkpointed[flow.StateCommitment(rootHash)] = struct{}{}
+	}
+
+	var header flow.Header
+	var commit flow.StateCommitment
+	blockID := executedID
+	err := db.View(func(tx *badger.Txn) error {
+		for {
+			err := operation.LookupStateCommitment(blockID, &commit)(tx)
+			if errors.Is(err, storage.ErrNotFound) {
+				return fmt.Errorf("checkpoint includes neither the state of executed block %x nor of any of its ancestors", executedID)
+			}
+			if err != nil {
+				return fmt.Errorf("could not look up state commitment of block %x: %w", blockID, err)
+			}
+			err = operation.RetrieveHeader(blockID, &header)(tx)
+			if err != nil {
+				return fmt.Errorf("could not retrieve block %x: %w", blockID, err)
+			}
+			if _, ok := checkpointed[commit]; ok {
+				return nil
+			}
+			blockID = header.ParentID
+		}
+	})
+	if err != nil {
+		return 0, flow.ZeroID, flow.DummyStateCommitment, err
+	}
+	return header.Height, blockID, commit, nil
+}
+
+// backupWAL copies the WAL segments in checkpointDir, starting with segment from, into the
+// backup directory to. The last segment might be written to while it is copied, which leaves
+// a torn record at its end. Such a record is discarded when the WAL is replayed.
+func backupWAL(checkpointDir string, to string, from int) ([]File, error) {
+	first, last, err := prometheusWAL.Segments(checkpointDir)
+	if err != nil {
+		return nil, fmt.Errorf("could not list segments: %w", err)
+	}
+	if first < 0 || first > from || last < from {
+		return nil, fmt.Errorf("segments %d to %d do not include segment %d", first, last, from)
+	}
+
+	files := make([]File, 0, last-from+1)
+	for segment := from; segment <= last; segment++ {
+		path := prometheusWAL.SegmentName(checkpointDir, segment)
+		name := filepath.Base(path)
+		err = utilsio.Copy(path, filepath.Join(to, name))
+		if err != nil {
+			return nil, fmt.Errorf("could not copy segment %d: %w", segment, err)
+		}
+		file, err := describeFile(to, name)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, file)
+	}
+	return files, nil
+}
+
+// validateCheckpoint checks that the checkpoint of the backup in dir includes the state commitment
+// recorded in the manifest, and that the backup includes the WAL segments following the checkpoint
+// if the checkpoint is older than the executed block.
+func validateCheckpoint(dir string, manifest *Manifest) error {
+	checkpoint := manifest.Checkpoint
+	rootHashes, err := wal.ReadTriesRootHash(filepath.Join(dir, CheckpointDir), checkpoint.Name)
+	if err != nil {
+		return fmt.Errorf("%w: could not read root hashes of checkpoint %s: %s", ErrInvalidBackup, checkpoint.Name, err)
+	}
+	included := false
+	for _, rootHash := range rootHashes {
+		if flow.StateCommitment(rootHash) == checkpoint.StateCommitment {
+			included = true
+			break
+		}
+	}
+	if !included {
+		return fmt.Errorf("%w: checkpoint %s does not include state commitment %x of block %x",
+			ErrInvalidBackup, checkpoint.Name, checkpoint.StateCommitment, checkpoint.BlockID)
+	}
+	if checkpoint.StateCommitment != manifest.ExecutedStateCommitment && len(checkpoint.WAL) == 0 {
+		return fmt.Errorf("%w: checkpoint %s does not include state commitment %x of executed block %x, and no WAL segments are included",
+			ErrInvalidBackup, checkpoint.Name, manifest.ExecutedStateCommitment, manifest.ExecutedBlockID)
+	}
+	return nil
+}
+
+// ensureEmpty checks that the given directory does not exist or is empty.
+func ensureEmpty(dir string) error {
+	entries, err := os.ReadDir(dir)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("could not read directory %s: %w", dir, err)
+	}
+	if len(entries) > 0 {
+		return fmt.Errorf("directory %s is not empty", dir)
+	}
+	return nil
+}
+
+// describeFile returns the size and checksum of the file with the given name in dir.
+func describeFile(dir string, name string) (File, error) {
+	size, sum, err := checksum(filepath.Join(dir, name))
+	if err != nil {
+		return File{}, err
+	}
+	return File{
+		Name:   name,
+		Size:   size,
+		SHA256: sum,
+	}, nil
+}
+
+// validateFile checks the size and checksum of the file in dir against the given description.
+func validateFile(dir string, file File) error {
+	// names are joined with the backup directory, so they must not point outside of it
+	if file.Name != filepath.Base(file.Name) {
+		return fmt.Errorf("%w: invalid file name %q", ErrInvalidBackup, file.Name)
+	}
+	size, sum, err := checksum(filepath.Join(dir, file.Name))
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrInvalidBackup, err)
+	}
+	if size != file.Size || sum != file.SHA256 {
+		return fmt.Errorf("%w: file %s has size %d and checksum %s, manifest records %d and %s",
+			ErrInvalidBackup, file.Name, size, sum, file.Size, file.SHA256)
+	}
+	return nil
+}
+
+func checksum(path string) (int64, string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return 0, "", fmt.Errorf("could not open file: %w", err)
+	}
+	defer file.Close()
+
+	hasher := sha256.New()
+	size, err := io.Copy(hasher, file)
+	if err != nil {
+		return 0, "", fmt.Errorf("could not hash file %s: %w", path, err)
+	}
+	return size, hex.EncodeToString(hasher.Sum(nil)), nil
+}
+
+func writeManifest(dir string, manifest *Manifest) error {
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return fmt.Errorf("could not encode manifest: %w", err)
+	}
+	err = os.WriteFile(filepath.Join(dir, ManifestFilename), data, 0600)
+	if err != nil {
+		return fmt.Errorf("could not write manifest: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,308 @@
+package backup_test
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/dgraph-io/badger/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/ledger"
+	"github.com/onflow/flow-go/ledger/common/testutils"
+	"github.com/onflow/flow-go/ledger/complete/mtrie/trie"
+	"github.com/onflow/flow-go/ledger/complete/wal"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/storage/badger/backup"
+	"github.com/onflow/flow-go/storage/badger/operation"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+// TestBackupAndRestore tests that a backup records the finalized, sealed and executed blocks of
+// the database, and that restoring it yields the same database, checkpoint and WAL segments.
+func TestBackupAndRestore(t *testing.T) {
+	unittest.RunWithBadgerDB(t, func(db *badger.DB) {
+		headers, commits := executionFixture(t, db, 7)
+		// later updates of the heights are contained in the backup
+		require.NoError(t, db.Update(operation.UpdateFinalizedHeight(9)))
+		require.NoError(t, db.Update(operation.UpdateSealedHeight(7)))
+
+		// the checkpoint includes the state up to height 2, the executed state is only in the WAL
+		trieDir := unittest.TempDir(t)
+		defer os.RemoveAll(trieDir)
+		checkpointFixture(t, trieDir, 2, commits[:3])
+		require.NoError(t, os.WriteFile(filepath.Join(trieDir, wal.NumberToFilenamePart(2)), []byte("old"), 0600))
+		require.NoError(t, os.WriteFile(filepath.Join(trieDir, wal.NumberToFilenamePart(3)), []byte("segment 3"), 0600))
+		require.NoError(t, os.WriteFile(filepath.Join(trieDir, wal.NumberToFilenamePart(4)), []byte("segment 4"), 0600))
+
+		dir := filepath.Join(unittest.TempDir(t), "backup")
+		defer os.RemoveAll(filepath.Dir(dir))
+
+		manifest, err := backup.Create(db, dir, trieDir)
+		require.NoError(t, err)
+		assert.Equal(t, uint64(9), manifest.FinalizedHeight)
+		assert.Equal(t, headers[9].ID(), manifest.FinalizedBlockID)
+		assert.Equal(t, uint64(7), manifest.SealedHeight)
+		assert.Equal(t, headers[7].ID(), manifest.SealedBlockID)
+		assert.Equal(t, headers[7].Height, manifest.ExecutedHeight)
+		assert.Equal(t, headers[7].ID(), manifest.ExecutedBlockID)
+		assert.Equal(t, commits[7], manifest.ExecutedStateCommitment)
+		require.NotNil(t, manifest.Checkpoint)
+		assert.Equal(t, wal.NumberToFilename(2), manifest.Checkpoint.Name)
+		assert.Equal(t, headers[2].Height, manifest.Checkpoint.Height)
+		assert.Equal(t, headers[2].ID(), manifest.Checkpoint.BlockID)
+		assert.Equal(t, commits[2], manifest.Checkpoint.StateCommitment)
+		require.Len(t, manifest.Checkpoint.WAL, 2)
+		assert.Equal(t, wal.NumberToFilenamePart(3), manifest.Checkpoint.WAL[0].Name)
+		assert.Equal(t, wal.NumberToFilenamePart(4), manifest.Checkpoint.WAL[1].Name)
+
+		// creating a backup into an existing directory fails
+		_, err = backup.Create(db, dir, "")
+		require.Error(t, err)
+
+		validated, err := backup.Validate(dir)
+		require.NoError(t, err)
+		assert.Equal(t, manifest.FinalizedBlockID, validated.FinalizedBlockID)
+
+		// a checkpoint can only be restored into a trie directory
+		datadir := filepath.Join(unittest.TempDir(t), "data")
+		defer os.RemoveAll(filepath.Dir(datadir))
+		_, err = backup.Restore(dir, datadir, "")
+		require.Error(t, err)
+
+		restoredTrieDir := unittest.TempDir(t)
+		defer os.RemoveAll(restoredTrieDir)
+		_, err = backup.Restore(dir, datadir, restoredTrieDir)
+		require.NoError(t, err)
+
+		rootHashes, err := wal.ReadTriesRootHash(restoredTrieDir, wal.NumberToFilename(2))
+		require.NoError(t, err)
+		assert.Len(t, rootHashes, 3)
+		segment, err := os.ReadFile(filepath.Join(restoredTrieDir, wal.NumberToFilenamePart(4)))
+		require.NoError(t, err)
+		assert.Equal(t, []byte("segment 4"), segment)
+		assert.NoFileExists(t, filepath.Join(restoredTrieDir, wal.NumberToFilenamePart(2)))
+
+		restored, err := badger.Open(badger.DefaultOptions(datadir).WithLogger(nil))
+		require.NoError(t, err)
+		defer restored.Close()
+		var height uint64
+		var blockID flow.Identifier
+		require.NoError(t, restored.View(operation.RetrieveFinalizedHeight(&height)))
+		assert.Equal(t, uint64(9), height)
+		require.NoError(t, restored.View(operation.LookupBlockHeight(headers[4].Height, &blockID)))
+		assert.Equal(t, headers[4].ID(), blockID)
+
+		// restoring into a non-empty data directory fails
+		_, err = backup.Restore(dir, datadir, unittest.TempDir(t))
+		require.Error(t, err)
+	})
+}
+
+// TestBackupCheckpointIncludingExecutedState tests that no WAL segments are included in a backup
+// if the checkpoint includes the state of the executed block.
+func TestBackupCheckpointIncludingExecutedState(t *testing.T) {
+	unittest.RunWithBadgerDB(t, func(db *badger.DB) {
+		headers, commits := executionFixture(t, db, 5)
+
+		trieDir := unittest.TempDir(t)
+		defer os.RemoveAll(trieDir)
+		checkpointFixture(t, trieDir, 6, commits)
+		require.NoError(t, os.WriteFile(filepath.Join(trieDir, wal.NumberToFilenamePart(7)), []byte("segment 7"), 0600))
+
+		dir := filepath.Join(unittest.TempDir(t), "backup")
+		defer os.RemoveAll(filepath.Dir(dir))
+
+		manifest, err := backup.Create(db, dir, trieDir)
+		require.NoError(t, err)
+		assert.Equal(t, headers[5].ID(), manifest.ExecutedBlockID)
+		assert.Equal(t, headers[5].ID(), manifest.Checkpoint.BlockID)
+		assert.Equal(t, commits[5], manifest.Checkpoint.StateCommitment)
+		assert.Empty(t, manifest.Checkpoint.WAL)
+
+		_, err = backup.Validate(dir)
+		require.NoError(t, err)
+	})
+}
+
+// TestRestoreMismatchingCheckpoint tests that backups are rejected if their checkpoint does not
+// match the blocks recorded in the manifest, or if the WAL segments needed to reach the executed
+// state are missing.
+func TestRestoreMismatchingCheckpoint(t *testing.T) {
+	unittest.RunWithBadgerDB(t, func(db *badger.DB) {
+		headers, commits := executionFixture(t, db, 7)
+
+		trieDir := unittest.TempDir(t)
+		defer os.RemoveAll(trieDir)
+		checkpointFixture(t, trieDir, 2, commits[:3])
+		require.NoError(t, os.WriteFile(filepath.Join(trieDir, wal.NumberToFilenamePart(3)), []byte("segment 3"), 0600))
+
+		dir := filepath.Join(unittest.TempDir(t), "backup")
+		defer os.RemoveAll(filepath.Dir(dir))
+		manifest, err := backup.Create(db, dir, trieDir)
+		require.NoError(t, err)
+
+		for name, modify := range map[string]func(*backup.Manifest){
+			"state commitment not in checkpoint": func(manifest *backup.Manifest) {
+				manifest.Checkpoint.StateCommitment = commits[5]
+			},
+			"missing WAL": func(manifest *backup.Manifest) {
+				manifest.Checkpoint.WAL = nil
+			},
+			"different executed block": func(manifest *backup.Manifest) {
+				manifest.ExecutedBlockID = headers[6].ID()
+			},
+		} {
+			t.Run(name, func(t *testing.T) {
+				modified := *manifest
+				checkpoint := *manifest.Checkpoint
+				modified.Checkpoint = &checkpoint
+				modify(&modified)
+				writeManifest(t, dir, &modified)
+
+				_, err := backup.Validate(dir)
+				require.ErrorIs(t, err, backup.ErrInvalidBackup)
+			})
+		}
+
+		// the checkpoint includes the state commitment, but of a different block
+		modified := *manifest
+		checkpoint := *manifest.Checkpoint
+		checkpoint.BlockID = headers[1].ID()
+		modified.Checkpoint = &checkpoint
+		writeManifest(t, dir, &modified)
+
+		_, err = backup.Validate(dir)
+		require.NoError(t, err)
+		datadir := filepath.Join(unittest.TempDir(t), "data")
+		defer os.RemoveAll(filepath.Dir(datadir))
+		restoredTrieDir := unittest.TempDir(t)
+		defer os.RemoveAll(restoredTrieDir)
+		_, err = backup.Restore(dir, datadir, restoredTrieDir)
+		require.ErrorIs(t, err, backup.ErrInvalidBackup)
+	})
+}
+
+// executionFixture stores a chain of 10 finalized blocks, of which the blocks up to the given
+// height are executed, and returns the headers and the state commitments of the executed blocks.
+// The state commitments of the blocks from height 5 on are the same, as if they had no transactions.
+func executionFixture(t *testing.T, db *badger.DB, executed int) ([]*flow.Header, []flow.StateCommitment) {
+	tries := triesFixture(t, 5)
+	headers := make([]*flow.Header, 0, 10)
+	commits := make([]flow.StateCommitment, 0, executed+1)
+	parent := &flow.Header{ChainID: flow.Emulator}
+	for height := 0; height < 10; height++ {
+		header := unittest.BlockHeaderWithParentFixture(parent)
+		header.Height = uint64(height)
+		headers = append(headers, header)
+		parent = header
+	}
+
+	require.NoError(t, db.Update(func(tx *badger.Txn) error {
+		for height, header := range headers {
+			err := operation.InsertHeader(header.ID(), header)(tx)
+			if err != nil {
+				return err
+			}
+			err = operation.IndexBlockHeight(uint64(height), header.ID())(tx)
+			if err != nil {
+				return err
+			}
+			if height > executed {
+				continue
+			}
+			state := tries[len(tries)-1]
+			if height < len(tries) {
+				state = tries[height]
+			}
+			commit := flow.StateCommitment(state.RootHash())
+			err = operation.IndexStateCommitment(header.ID(), commit)(tx)
+			if err != nil {
+				return err
+			}
+			commits = append(commits, commit)
+		}
+		err := operation.InsertExecutedBlock(headers[executed].ID())(tx)
+		if err != nil {
+			return err
+		}
+		err = operation.InsertFinalizedHeight(5)(tx)
+		if err != nil {
+			return err
+		}
+		return operation.InsertSealedHeight(3)(tx)
+	}))
+	return headers, commits
+}
+
+// triesFixture returns n tries, each updating a register of the previous one.
+func triesFixture(t *testing.T, n int) []*trie.MTrie {
+	tries := []*trie.MTrie{trie.NewEmptyMTrie()}
+	for i := 1; i < n; i++ {
+		path := testutils.PathByUint8(uint8(i))
+		payload := testutils.LightPayload8(uint8(i), uint8(i))
+		updated, _, err := trie.NewTrieWithUpdatedRegisters(tries[i-1], []ledger.Path{path}, []ledger.Payload{*payload}, true)
+		require.NoError(t, err)
+		tries = append(tries, updated)
+	}
+	return tries
+}
+
+// checkpointFixture writes a checkpoint with the given number into dir, holding tries with the
+// given state commitments. The commitments must be of tries returned by triesFixture.
+func checkpointFixture(t *testing.T, dir string, number int, commits []flow.StateCommitment) {
+	tries := triesFixture(t, 5)
+	checkpointed := make([]*trie.MTrie, 0, len(commits))
+	for _, commit := range commits {
+		for _, tr := range tries {
+			if flow.StateCommitment(tr.RootHash()) == commit {
+				checkpointed = append(checkpointed, tr)
+				break
+			}
+		}
+	}
+	require.Len(t, checkpointed, len(commits))
+	logger := unittest.Logger()
+	require.NoError(t, wal.StoreCheckpointV6SingleThread(checkpointed, dir, wal.NumberToFilename(number), &logger))
+}
+
+func writeManifest(t *testing.T, dir string, manifest *backup.Manifest) {
+	data, err := json.Marshal(manifest)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, backup.ManifestFilename), data, 0600))
+}
+
+// TestValidateCorruptedBackup tests that backups not matching their manifest are rejected.
+func TestValidateCorruptedBackup(t *testing.T) {
+	unittest.RunWithBadgerDB(t, func(db *badger.DB) {
+		blockID := unittest.IdentifierFixture()
+		require.NoError(t, db.Update(func(tx *badger.Txn) error {
+			err := operation.IndexBlockHeight(0, blockID)(tx)
+			if err != nil {
+				return err
+			}
+			err = operation.InsertFinalizedHeight(0)(tx)
+			if err != nil {
+				return err
+			}
+			return operation.InsertSealedHeight(0)(tx)
+		}))
+
+		dir := filepath.Join(unittest.TempDir(t), "backup")
+		defer os.RemoveAll(filepath.Dir(dir))
+		_, err := backup.Create(db, dir, "")
+		require.NoError(t, err)
+
+		path := filepath.Join(dir, backup.ProtocolDBFilename)
+		data, err := os.ReadFile(path)
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(path, data[:len(data)-1], 0600))
+
+		_, err = backup.Validate(dir)
+		require.ErrorIs(t, err, backup.ErrInvalidBackup)
+		_, err = backup.Restore(dir, filepath.Join(filepath.Dir(dir), "data"), "")
+		require.ErrorIs(t, err, backup.ErrInvalidBackup)
+	})
+}
@@ -12,6 +12,7 @@ import (
 	"github.com/onflow/flow-go/module/metrics"
 	"github.com/onflow/flow-go/storage"
 	badgerstorage "github.com/onflow/flow-go/storage/badger"
+	"github.com/onflow/flow-go/storage/util"
 	"github.com/onflow/flow-go/utils/unittest"
 
 	"github.com/stretchr/testify/assert"
@@ -45,24 +46,26 @@ func TestChunkDataPacks_Store(t *testing.T) {
 
 // TestChunkDataPack_BatchStore evaluates correct batch storage and retrieval of chunk data packs in the storage.
 func TestChunkDataPacks_BatchStore(t *testing.T) {
-	WithChunkDataPacks(t, 100, func(t *testing.T, chunkDataPacks []*flow.ChunkDataPack, chunkDataPackStore *badgerstorage.ChunkDataPacks, db *badger.DB) {
-		batch := badgerstorage.NewBatch(db)
+	util.RunWithBatchDBs(t, func(t *testing.T, db *badger.DB, batchDB storage.DB) {
+		withChunkDataPacks(t, db, 100, func(t *testing.T, chunkDataPacks []*flow.ChunkDataPack, chunkDataPackStore *badgerstorage.ChunkDataPacks, _ *badger.DB) {
+			batch := batchDB.NewBatch()
 
-		wg := sync.WaitGroup{}
-		wg.Add(len(chunkDataPacks))
-		for _, chunkDataPack := range chunkDataPacks {
-			go func(cdp flow.ChunkDataPack) {
-				err := chunkDataPackStore.BatchStore(&cdp, batch)
-				require.NoError(t, err)
+			wg := sync.WaitGroup{}
+			wg.Add(len(chunkDataPacks))
+			for _, chunkDataPack := range chunkDataPacks {
+				go func(cdp flow.ChunkDataPack) {
+					err := chunkDataPackStore.BatchStore(&cdp, batch)
+					require.NoError(t, err)
 
-				wg.Done()
-			}(*chunkDataPack)
-		}
+					wg.Done()
+				}(*chunkDataPack)
+			}
 
-		unittest.RequireReturnsBefore(t, wg.Wait, 1*time.Second, "could not store chunk data packs on time")
+			unittest.RequireReturnsBefore(t, wg.Wait, 1*time.Second, "could not store chunk data packs on time")
 
-		err := batch.Flush()
-		require.NoError(t, err)
+			err := batch.Flush()
+			require.NoError(t, err)
+		})
 	})
 }
 
@@ -83,27 +86,31 @@ func TestChunkDataPacks_MissingItem(t *testing.T) {
 // then evaluates whether they are successfully retrieved from storage.
 func WithChunkDataPacks(t *testing.T, chunks int, storeFunc func(*testing.T, []*flow.ChunkDataPack, *badgerstorage.ChunkDataPacks, *badger.DB)) {
 	unittest.RunWithBadgerDB(t, func(db *badger.DB) {
-		transactions := badgerstorage.NewTransactions(&metrics.NoopCollector{}, db)
-		collections := badgerstorage.NewCollections(db, transactions)
-		// keep the cache size at 1 to make sure that entries are written and read from storage itself.
-		store := badgerstorage.NewChunkDataPacks(&metrics.NoopCollector{}, db, collections, 1)
+		withChunkDataPacks(t, db, chunks, storeFunc)
+	})
+}
 
-		chunkDataPacks := unittest.ChunkDataPacksFixture(chunks)
-		for _, chunkDataPack := range chunkDataPacks {
-			// stores collection in Collections storage (which ChunkDataPacks store uses internally)
-			err := collections.Store(chunkDataPack.Collection)
-			require.NoError(t, err)
-		}
+func withChunkDataPacks(t *testing.T, db *badger.DB, chunks int, storeFunc func(*testing.T, []*flow.ChunkDataPack, *badgerstorage.ChunkDataPacks, *badger.DB)) {
+	transactions := badgerstorage.NewTransactions(&metrics.NoopCollector{}, db)
+	collections := badgerstorage.NewCollections(db, transactions)
+	// keep the cache size at 1 to make sure that entries are written and read from storage itself.
+	store := badgerstorage.NewChunkDataPacks(&metrics.NoopCollector{}, db, collections, 1)
 
-		// stores chunk data packs in the memory using provided store function.
-		storeFunc(t, chunkDataPacks, store, db)
+	chunkDataPacks := unittest.ChunkDataPacksFixture(chunks)
+	for _, chunkDataPack := range chunkDataPacks {
+		// stores collection in Collections storage (which ChunkDataPacks store uses internally)
+		err := collections.Store(chunkDataPack.Collection)
+		require.NoError(t, err)
+	}
 
-		// stored chunk data packs should be retrieved successfully.
-		for _, expected := range chunkDataPacks {
-			actual, err := store.ByChunkID(expected.ChunkID)
-			require.NoError(t, err)
+	// stores chunk data packs in the memory using provided store function.
+	storeFunc(t, chunkDataPacks, store, db)
 
-			assert.Equal(t, expected, actual)
-		}
-	})
+	// stored chunk data packs should be retrieved successfully.
+	for _, expected := range chunkDataPacks {
+		actual, err := store.ByChunkID(expected.ChunkID)
+		require.NoError(t, err)
+
+		assert.Equal(t, expected, actual)
+	}
 }
@@ -0,0 +1,165 @@
+package badger
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/dgraph-io/badger/v2"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/storage"
+	"github.com/onflow/flow-go/storage/badger/operation"
+)
+
+// ChunkFaultReports implements persistent storage for chunk fault reports.
+type ChunkFaultReports struct {
+	db *badger.DB
+}
+
+func NewChunkFaultReports(db *badger.DB) *ChunkFaultReports {
+	return &ChunkFaultReports{
+		db: db,
+	}
+}
+
+// Store stores a chunk fault report and indexes it by the ID of its execution result, by its reporter within the
+// given epoch of its executed block, and by the epoch. Storing the same report multiple times is a no-op.
+func (c *ChunkFaultReports) Store(report *flow.ChunkFaultReport, epoch uint64) error {
+	err := operation.RetryOnConflict(c.db.Update, func(tx *badger.Txn) error {
+		err := operation.SkipDuplicates(operation.InsertChunkFaultReport(report))(tx)
+		if err != nil {
+			return fmt.Errorf("could not insert chunk fault report: %w", err)
+		}
+		err = operation.SkipDuplicates(operation.IndexChunkFaultReport(report.Body.ExecutionResultID, report.ID()))(tx)
+		if err != nil {
+			return fmt.Errorf("could not index chunk fault report: %w", err)
+		}
+		err = operation.SkipDuplicates(operation.IndexChunkFaultReportByReporter(report.Body.ReporterID, epoch, report.Body.ExecutionResultID, report.ID()))(tx)
+		if err != nil {
+			return fmt.Errorf("could not index chunk fault report by reporter: %w", err)
+		}
+		err = operation.SkipDuplicates(operation.IndexChunkFaultReportByEpoch(epoch, report.ID()))(tx)
+		if err != nil {
+			return fmt.Errorf("could not index chunk fault report by epoch: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("could not store chunk fault report: %w", err)
+	}
+	return nil
+}
+
+// ByID retrieves a chunk fault report by its ID.
+func (c *ChunkFaultReports) ByID(reportID flow.Identifier) (*flow.ChunkFaultReport, error) {
+	var report flow.ChunkFaultReport
+	err := c.db.View(operation.RetrieveChunkFaultReport(reportID, &report))
+	if err != nil {
+		return nil, fmt.Errorf("could not retrieve chunk fault report: %w", err)
+	}
+	return &report, nil
+}
+
+// ByResultID retrieves all chunk fault reports of the given execution result.
+func (c *ChunkFaultReports) ByResultID(resultID flow.Identifier) ([]*flow.ChunkFaultReport, error) {
+	var reports []*flow.ChunkFaultReport
+	err := c.db.View(func(tx *badger.Txn) error {
+		var reportIDs []flow.Identifier
+		err := operation.LookupChunkFaultReports(resultID, &reportIDs)(tx)
+		if err != nil {
+			return fmt.Errorf("could not lookup chunk fault reports: %w", err)
+		}
+		reports = make([]*flow.ChunkFaultReport, 0, len(reportIDs))
+		for _, reportID := range reportIDs {
+			var report flow.ChunkFaultReport
+			err = operation.RetrieveChunkFaultReport(reportID, &report)(tx)
+			if err != nil {
+				return fmt.Errorf("could not retrieve chunk fault report %x: %w", reportID, err)
+			}
+			reports = append(reports, &report)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return reports, nil
+}
+
+// CountByReporterIDAndEpoch returns the number of chunk fault reports of the given reporter on the blocks of the given
+// epoch.
+func (c *ChunkFaultReports) CountByReporterIDAndEpoch(reporterID flow.Identifier, epoch uint64) (uint, error) {
+	var reportIDs []flow.Identifier
+	err := c.db.View(operation.LookupChunkFaultReportsByReporterAndEpoch(reporterID, epoch, &reportIDs))
+	if err != nil {
+		return 0, fmt.Errorf("could not lookup chunk fault reports of reporter in epoch: %w", err)
+	}
+	return uint(len(reportIDs)), nil
+}
+
+// CountByReporterIDAndResultID returns the number of chunk fault reports of the given reporter for the given
+// execution result of a block of the given epoch.
+func (c *ChunkFaultReports) CountByReporterIDAndResultID(reporterID flow.Identifier, epoch uint64, resultID flow.Identifier) (uint, error) {
+	var reportIDs []flow.Identifier
+	err := c.db.View(operation.LookupChunkFaultReportsByReporterAndResult(reporterID, epoch, resultID, &reportIDs))
+	if err != nil {
+		return 0, fmt.Errorf("could not lookup chunk fault reports of reporter for result: %w", err)
+	}
+	return uint(len(reportIDs)), nil
+}
+
+// RemoveBeforeEpoch removes the chunk fault reports on the blocks of the epochs before the given epoch, with their
+// indexes, and returns the number of removed reports. Each report is removed in its own transaction, so that the
+// transaction size is bounded regardless of the number of reports, and an interrupted removal is resumed by the next
+// call, as the epoch index of a report is removed last.
+func (c *ChunkFaultReports) RemoveBeforeEpoch(epoch uint64) (uint, error) {
+	reportIDs := make(map[uint64][]flow.Identifier)
+	err := c.db.View(operation.LookupChunkFaultReportsBeforeEpoch(epoch, reportIDs))
+	if err != nil {
+		return 0, fmt.Errorf("could not lookup chunk fault reports before epoch %d: %w", epoch, err)
+	}
+
+	removed := uint(0)
+	for reportEpoch, ids := range reportIDs {
+		for _, reportID := range ids {
+			err = operation.RetryOnConflict(c.db.Update, func(tx *badger.Txn) error {
+				return removeChunkFaultReport(tx, reportEpoch, reportID)
+			})
+			if err != nil {
+				return removed, fmt.Errorf("could not remove chunk fault report %x: %w", reportID, err)
+			}
+			removed++
+		}
+	}
+	return removed, nil
+}
+
+// removeChunkFaultReport removes the chunk fault report with the given ID on a block of the given epoch, with its
+// indexes. Already removed entries are skipped, so that a partially removed report can be removed again.
+func removeChunkFaultReport(tx *badger.Txn, epoch uint64, reportID flow.Identifier) error {
+	var report flow.ChunkFaultReport
+	err := operation.RetrieveChunkFaultReport(reportID, &report)(tx)
+	if err != nil && !errors.Is(err, storage.ErrNotFound) {
+		return fmt.Errorf("could not retrieve chunk fault report: %w", err)
+	}
+	if err == nil {
+		resultID := report.Body.ExecutionResultID
+		err = operation.SkipNonExist(operation.RemoveChunkFaultReportIndex(resultID, reportID))(tx)
+		if err != nil {
+			return fmt.Errorf("could not remove index of chunk fault report: %w", err)
+		}
+		err = operation.SkipNonExist(operation.RemoveChunkFaultReportIndexByReporter(report.Body.ReporterID, epoch, resultID, reportID))(tx)
+		if err != nil {
+			return fmt.Errorf("could not remove reporter index of chunk fault report: %w", err)
+		}
+		err = operation.RemoveChunkFaultReport(reportID)(tx)
+		if err != nil {
+			return fmt.Errorf("could not remove chunk fault report: %w", err)
+		}
+	}
+	err = operation.RemoveChunkFaultReportIndexByEpoch(epoch, reportID)(tx)
+	if err != nil {
+		return fmt.Errorf("could not remove epoch index of chunk fault report: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,147 @@
+package badger_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/dgraph-io/badger/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/storage"
+	bstorage "github.com/onflow/flow-go/storage/badger"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+func chunkFaultReportFixture(resultID flow.Identifier, chunkIndex uint64) *flow.ChunkFaultReport {
+	return &flow.ChunkFaultReport{
+		Body: flow.ChunkFaultReportBody{
+			BlockID:           unittest.IdentifierFixture(),
+			ExecutionResultID: resultID,
+			ChunkIndex:        chunkIndex,
+			ReporterID:        unittest.IdentifierFixture(),
+			FaultType:         flow.ChunkFaultInvalidEventsCollection,
+			FaultDetails:      "events collection hash differs",
+			ChunkDataPack:     *unittest.ChunkDataPackFixture(unittest.IdentifierFixture()),
+			ComputedEndState:  unittest.StateCommitmentFixture(),
+		},
+		ReporterSignature: unittest.SignatureFixture(),
+	}
+}
+
+func TestChunkFaultReportsStoreAndRetrieve(t *testing.T) {
+	unittest.RunWithBadgerDB(t, func(db *badger.DB) {
+		store := bstorage.NewChunkFaultReports(db)
+
+		resultID := unittest.IdentifierFixture()
+		first := chunkFaultReportFixture(resultID, 0)
+		second := chunkFaultReportFixture(resultID, 1)
+		other := chunkFaultReportFixture(unittest.IdentifierFixture(), 0)
+		for _, report := range []*flow.ChunkFaultReport{first, second, other} {
+			require.NoError(t, store.Store(report, 1))
+		}
+		// storing the same report twice is a no-op
+		require.NoError(t, store.Store(first, 1))
+
+		byID, err := store.ByID(first.ID())
+		require.NoError(t, err)
+		assert.Equal(t, first.Checksum(), byID.Checksum())
+
+		byResult, err := store.ByResultID(resultID)
+		require.NoError(t, err)
+		require.Len(t, byResult, 2)
+		assert.ElementsMatch(t,
+			[]flow.Identifier{first.Checksum(), second.Checksum()},
+			[]flow.Identifier{byResult[0].Checksum(), byResult[1].Checksum()})
+
+		byResult, err = store.ByResultID(unittest.IdentifierFixture())
+		require.NoError(t, err)
+		assert.Empty(t, byResult)
+
+		_, err = store.ByID(unittest.IdentifierFixture())
+		assert.True(t, errors.Is(err, storage.ErrNotFound))
+	})
+}
+
+// TestChunkFaultReportsCountByReporter evaluates that the reports are counted per reporter and epoch, overall and per
+// execution result, and that storing the same report twice does not change the counts.
+func TestChunkFaultReportsCountByReporter(t *testing.T) {
+	unittest.RunWithBadgerDB(t, func(db *badger.DB) {
+		store := bstorage.NewChunkFaultReports(db)
+
+		resultID := unittest.IdentifierFixture()
+		first := chunkFaultReportFixture(resultID, 0)
+		second := chunkFaultReportFixture(resultID, 1)
+		second.Body.ReporterID = first.Body.ReporterID
+		other := chunkFaultReportFixture(unittest.IdentifierFixture(), 0)
+		other.Body.ReporterID = first.Body.ReporterID
+		previous := chunkFaultReportFixture(unittest.IdentifierFixture(), 0)
+		previous.Body.ReporterID = first.Body.ReporterID
+		require.NoError(t, store.Store(previous, 1))
+		for _, report := range []*flow.ChunkFaultReport{first, second, other, first} {
+			require.NoError(t, store.Store(report, 2))
+		}
+
+		count, err := store.CountByReporterIDAndEpoch(first.Body.ReporterID, 2)
+		require.NoError(t, err)
+		assert.Equal(t, uint(3), count)
+		count, err = store.CountByReporterIDAndEpoch(first.Body.ReporterID, 1)
+		require.NoError(t, err)
+		assert.Equal(t, uint(1), count)
+		count, err = store.CountByReporterIDAndResultID(first.Body.ReporterID, 2, resultID)
+		require.NoError(t, err)
+		assert.Equal(t, uint(2), count)
+		count, err = store.CountByReporterIDAndResultID(first.Body.ReporterID, 1, resultID)
+		require.NoError(t, err)
+		assert.Equal(t, uint(0), count)
+		count, err = store.CountByReporterIDAndEpoch(unittest.IdentifierFixture(), 2)
+		require.NoError(t, err)
+		assert.Equal(t, uint(0), count)
+	})
+}
+
+// TestChunkFaultReportsRemoveBeforeEpoch evaluates that the reports on the blocks of the epochs before the given epoch
+// are removed with their indexes, while the reports of the later epochs are kept.
+func TestChunkFaultReportsRemoveBeforeEpoch(t *testing.T) {
+	unittest.RunWithBadgerDB(t, func(db *badger.DB) {
+		store := bstorage.NewChunkFaultReports(db)
+
+		resultID := unittest.IdentifierFixture()
+		first := chunkFaultReportFixture(resultID, 0)
+		second := chunkFaultReportFixture(resultID, 1)
+		second.Body.ReporterID = first.Body.ReporterID
+		kept := chunkFaultReportFixture(resultID, 2)
+		kept.Body.ReporterID = first.Body.ReporterID
+		require.NoError(t, store.Store(first, 1))
+		require.NoError(t, store.Store(second, 2))
+		require.NoError(t, store.Store(kept, 3))
+
+		removed, err := store.RemoveBeforeEpoch(0)
+		require.NoError(t, err)
+		assert.Equal(t, uint(0), removed)
+
+		removed, err = store.RemoveBeforeEpoch(3)
+		require.NoError(t, err)
+		assert.Equal(t, uint(2), removed)
+
+		for _, report := range []*flow.ChunkFaultReport{first, second} {
+			_, err = store.ByID(report.ID())
+			assert.True(t, errors.Is(err, storage.ErrNotFound))
+		}
+		byResult, err := store.ByResultID(resultID)
+		require.NoError(t, err)
+		require.Len(t, byResult, 1)
+		assert.Equal(t, kept.Checksum(), byResult[0].Checksum())
+		for epoch, expected := range map[uint64]uint{1: 0, 2: 0, 3: 1} {
+			count, err := store.CountByReporterIDAndEpoch(first.Body.ReporterID, epoch)
+			require.NoError(t, err)
+			assert.Equal(t, expected, count)
+		}
+
+		// removing again is a no-op
+		removed, err = store.RemoveBeforeEpoch(3)
+		require.NoError(t, err)
+		assert.Equal(t, uint(0), removed)
+	})
+}
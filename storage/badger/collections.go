@@ -9,6 +9,7 @@ import (
 	"github.com/onflow/flow-go/model/flow"
 	"github.com/onflow/flow-go/storage"
 	"github.com/onflow/flow-go/storage/badger/operation"
+	"github.com/onflow/flow-go/storage/badger/procedure"
 	"github.com/onflow/flow-go/storage/badger/transaction"
 )
 
@@ -60,7 +61,7 @@ func (c *Collections) ByID(colID flow.Identifier) (*flow.Collection, error) {
 	)
 
 	err := c.db.View(func(btx *badger.Txn) error {
-		err := operation.RetrieveCollection(colID, &light)(btx)
+		err := retrieveLightCollection(colID, &light)(btx)
 		if err != nil {
 			return fmt.Errorf("could not retrieve collection: %w", err)
 		}
@@ -87,7 +88,7 @@ func (c *Collections) LightByID(colID flow.Identifier) (*flow.LightCollection, e
 	var collection flow.LightCollection
 
 	err := c.db.View(func(tx *badger.Txn) error {
-		err := operation.RetrieveCollection(colID, &collection)(tx)
+		err := retrieveLightCollection(colID, &collection)(tx)
 		if err != nil {
 			return fmt.Errorf("could not retrieve collection: %w", err)
 		}
@@ -141,7 +142,7 @@ func (c *Collections) LightByTransactionID(txID flow.Identifier) (*flow.LightCol
 			return fmt.Errorf("could not retrieve collection id: %w", err)
 		}
 
-		err = operation.RetrieveCollection(*collID, &collection)(tx)
+		err = retrieveLightCollection(*collID, &collection)(tx)
 		if err != nil {
 			return fmt.Errorf("could not retrieve collection: %w", err)
 		}
@@ -154,3 +155,17 @@ func (c *Collections) LightByTransactionID(txID flow.Identifier) (*flow.LightCol
 
 	return &collection, nil
 }
+
+// retrieveLightCollection retrieves the light collection with the given ID.
+// Expected errors during normal operation:
+//   - storage.ErrPruned if the collection is part of a block whose payload has been pruned
+//   - storage.ErrNotFound if no collection with the given ID is stored
+func retrieveLightCollection(colID flow.Identifier, collection *flow.LightCollection) func(*badger.Txn) error {
+	return func(tx *badger.Txn) error {
+		err := operation.RetrieveCollection(colID, collection)(tx)
+		if errors.Is(err, storage.ErrNotFound) {
+			return procedure.CheckCollectionPruned(colID, err)(tx)
+		}
+		return err
+	}
+}
@@ -10,6 +10,7 @@ import (
 
 	"github.com/onflow/flow-go/module/metrics"
 	"github.com/onflow/flow-go/storage"
+	"github.com/onflow/flow-go/storage/util"
 	"github.com/onflow/flow-go/utils/unittest"
 
 	badgerstorage "github.com/onflow/flow-go/storage/badger"
@@ -41,3 +42,33 @@ func TestCommitsStoreAndRetrieve(t *testing.T) {
 		require.NoError(t, err)
 	})
 }
+
+// TestCommitsBatchStoreAndRemove tests that a commit can be stored and removed in write batches
+func TestCommitsBatchStoreAndRemove(t *testing.T) {
+	util.RunWithBatchDBs(t, func(t *testing.T, db *badger.DB, batchDB storage.DB) {
+		metrics := metrics.NewNoopCollector()
+		store := badgerstorage.NewCommits(metrics, db)
+
+		// store a commit in a batch
+		blockID := unittest.IdentifierFixture()
+		expected := unittest.StateCommitmentFixture()
+		batch := batchDB.NewBatch()
+		err := store.BatchStore(blockID, expected, batch)
+		require.NoError(t, err)
+		require.NoError(t, batch.Flush())
+
+		actual, err := store.ByBlockID(blockID)
+		require.NoError(t, err)
+		assert.Equal(t, expected, actual)
+
+		// remove the commit in a batch, using a new store, since the commit is cached on read
+		store = badgerstorage.NewCommits(metrics, db)
+		batch = batchDB.NewBatch()
+		err = store.BatchRemoveByBlockID(blockID, batch)
+		require.NoError(t, err)
+		require.NoError(t, batch.Flush())
+
+		_, err = store.ByBlockID(blockID)
+		assert.True(t, errors.Is(err, storage.ErrNotFound))
+	})
+}
@@ -0,0 +1,81 @@
+package badger
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/dgraph-io/badger/v2"
+
+	"github.com/onflow/flow-go/storage"
+)
+
+var _ storage.DB = (*DB)(nil)
+
+// DB implements the backend-agnostic storage.DB on top of a badger database.
+// Every read runs in its own read-only transaction, hence consecutive reads
+// are not guaranteed to observe the same state of the database.
+type DB struct {
+	db *badger.DB
+}
+
+func NewDB(db *badger.DB) *DB {
+	return &DB{db: db}
+}
+
+// Get returns a copy of the value stored under the given key.
+// Expected errors during normal operations:
+//   - storage.ErrNotFound if no value is stored under the key
+func (d *DB) Get(key []byte) ([]byte, error) {
+	var value []byte
+	err := d.db.View(func(tx *badger.Txn) error {
+		item, err := tx.Get(key)
+		if errors.Is(err, badger.ErrKeyNotFound) {
+			return storage.ErrNotFound
+		}
+		if err != nil {
+			return fmt.Errorf("could not load data: %w", err)
+		}
+		value, err = item.ValueCopy(nil)
+		if err != nil {
+			return fmt.Errorf("could not copy value: %w", err)
+		}
+		return nil
+	})
+	return value, err
+}
+
+// IterateByPrefix calls fn for every key-value pair whose key starts with the given
+// prefix, in ascending key order, until fn returns false or an error.
+// Errors returned by fn are returned unchanged. No other errors are expected during
+// normal operation.
+func (d *DB) IterateByPrefix(prefix []byte, fn func(key, value []byte) (bool, error)) error {
+	return d.db.View(func(tx *badger.Txn) error {
+		opts := badger.DefaultIteratorOptions
+		opts.Prefix = prefix
+		it := tx.NewIterator(opts)
+		defer it.Close()
+
+		for it.Seek(prefix); it.ValidForPrefix(prefix); it.Next() {
+			item := it.Item()
+			var proceed bool
+			err := item.Value(func(value []byte) error {
+				var err error
+				proceed, err = fn(item.Key(), value)
+				return err
+			})
+			if err != nil {
+				return err
+			}
+			if !proceed {
+				return nil
+			}
+		}
+		return nil
+	})
+}
+
+// NewBatch creates a new badger write batch, which supports callbacks
+// executed after the batch was successfully flushed.
+func (d *DB) NewBatch() storage.BatchStorage {
+	return NewBatch(d.db)
+}
@@ -10,12 +10,14 @@ import (
 	"github.com/onflow/flow-go/fvm/systemcontracts"
 	"github.com/onflow/flow-go/model/flow"
 	"github.com/onflow/flow-go/module/metrics"
+	"github.com/onflow/flow-go/storage"
 	badgerstorage "github.com/onflow/flow-go/storage/badger"
+	"github.com/onflow/flow-go/storage/util"
 	"github.com/onflow/flow-go/utils/unittest"
 )
 
 func TestEventStoreRetrieve(t *testing.T) {
-	unittest.RunWithBadgerDB(t, func(db *badger.DB) {
+	util.RunWithBatchDBs(t, func(t *testing.T, db *badger.DB, batchDB storage.DB) {
 		metrics := metrics.NewNoopCollector()
 		store := badgerstorage.NewEvents(metrics, db)
 
@@ -32,7 +34,7 @@ func TestEventStoreRetrieve(t *testing.T) {
 			{evt2_1},
 		}
 
-		batch := badgerstorage.NewBatch(db)
+		batch := batchDB.NewBatch()
 		// store event
 		err := store.BatchStore(blockID, expected, batch)
 		require.NoError(t, err)
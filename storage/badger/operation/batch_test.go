@@ -0,0 +1,65 @@
+package operation_test
+
+import (
+	"testing"
+
+	"github.com/dgraph-io/badger/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/storage"
+	badgerstorage "github.com/onflow/flow-go/storage/badger"
+	"github.com/onflow/flow-go/storage/badger/operation"
+	"github.com/onflow/flow-go/storage/migration"
+	"github.com/onflow/flow-go/storage/util"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+// TestBatchOperations tests the batch operations against every storage backend. The data
+// written to each backend is copied into badger and read back with the regular operations.
+func TestBatchOperations(t *testing.T) {
+	util.RunWithDBs(t, func(t *testing.T, db storage.DB) {
+		blockID := unittest.IdentifierFixture()
+		removedID := unittest.IdentifierFixture()
+		result := unittest.ExecutionResultFixture()
+		commit := unittest.StateCommitmentFixture()
+		event := unittest.EventFixture(flow.EventAccountCreated, 0, 0, unittest.IdentifierFixture(), 0)
+
+		batch := db.NewBatch()
+		writer := batch.GetWriter()
+		require.NoError(t, operation.BatchInsertExecutionResult(result)(writer))
+		require.NoError(t, operation.BatchIndexExecutionResult(blockID, result.ID())(writer))
+		require.NoError(t, operation.BatchIndexExecutionResult(removedID, result.ID())(writer))
+		require.NoError(t, operation.BatchIndexStateCommitment(blockID, commit)(writer))
+		require.NoError(t, operation.BatchInsertEvent(blockID, event)(writer))
+		require.NoError(t, batch.Flush())
+
+		batch = db.NewBatch()
+		require.NoError(t, operation.BatchRemoveExecutionResultIndex(removedID)(batch.GetWriter()))
+		require.NoError(t, batch.Flush())
+
+		unittest.RunWithBadgerDB(t, func(read *badger.DB) {
+			_, err := migration.Copy(db, badgerstorage.NewDB(read), migration.DefaultBatchSize)
+			require.NoError(t, err)
+
+			var resultID flow.Identifier
+			require.NoError(t, read.View(operation.LookupExecutionResult(blockID, &resultID)))
+			assert.Equal(t, result.ID(), resultID)
+			err = read.View(operation.LookupExecutionResult(removedID, &resultID))
+			require.ErrorIs(t, err, storage.ErrNotFound)
+
+			var actualResult flow.ExecutionResult
+			require.NoError(t, read.View(operation.RetrieveExecutionResult(resultID, &actualResult)))
+			assert.Equal(t, result.ID(), actualResult.ID())
+
+			var actualCommit flow.StateCommitment
+			require.NoError(t, read.View(operation.LookupStateCommitment(blockID, &actualCommit)))
+			assert.Equal(t, commit, actualCommit)
+
+			var events []flow.Event
+			require.NoError(t, read.View(operation.LookupEventsByBlockID(blockID, &events)))
+			assert.Equal(t, []flow.Event{event}, events)
+		})
+	})
+}
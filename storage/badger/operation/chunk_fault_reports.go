@@ -0,0 +1,99 @@
+package operation
+
+import (
+	"encoding/binary"
+
+	"github.com/dgraph-io/badger/v2"
+
+	"github.com/onflow/flow-go/model/flow"
+)
+
+// InsertChunkFaultReport inserts a chunk fault report by ID.
+func InsertChunkFaultReport(report *flow.ChunkFaultReport) func(*badger.Txn) error {
+	return insert(makePrefix(codeChunkFaultReport, report.ID()), report)
+}
+
+// RetrieveChunkFaultReport retrieves a chunk fault report by ID.
+func RetrieveChunkFaultReport(reportID flow.Identifier, report *flow.ChunkFaultReport) func(*badger.Txn) error {
+	return retrieve(makePrefix(codeChunkFaultReport, reportID), report)
+}
+
+// IndexChunkFaultReport inserts a chunk fault report ID keyed by execution result ID and report ID.
+// An execution result can have multiple fault reports, for different chunks and from different reporters.
+func IndexChunkFaultReport(resultID flow.Identifier, reportID flow.Identifier) func(*badger.Txn) error {
+	return insert(makePrefix(codeResultChunkFaultReports, resultID, reportID), reportID)
+}
+
+// LookupChunkFaultReports finds the IDs of all chunk fault reports by execution result ID.
+func LookupChunkFaultReports(resultID flow.Identifier, reportIDs *[]flow.Identifier) func(*badger.Txn) error {
+	return traverse(makePrefix(codeResultChunkFaultReports, resultID), lookup(reportIDs))
+}
+
+// IndexChunkFaultReportByReporter inserts a chunk fault report ID keyed by reporter ID, epoch of the executed block,
+// execution result ID and report ID.
+func IndexChunkFaultReportByReporter(reporterID flow.Identifier, epoch uint64, resultID flow.Identifier, reportID flow.Identifier) func(*badger.Txn) error {
+	return insert(makePrefix(codeReporterChunkFaultReports, reporterID, epoch, resultID, reportID), reportID)
+}
+
+// LookupChunkFaultReportsByReporterAndEpoch finds the IDs of all chunk fault reports by reporter ID and epoch of the
+// executed block.
+func LookupChunkFaultReportsByReporterAndEpoch(reporterID flow.Identifier, epoch uint64, reportIDs *[]flow.Identifier) func(*badger.Txn) error {
+	return traverse(makePrefix(codeReporterChunkFaultReports, reporterID, epoch), lookup(reportIDs))
+}
+
+// LookupChunkFaultReportsByReporterAndResult finds the IDs of all chunk fault reports by reporter ID, epoch of the
+// executed block and execution result ID.
+func LookupChunkFaultReportsByReporterAndResult(reporterID flow.Identifier, epoch uint64, resultID flow.Identifier, reportIDs *[]flow.Identifier) func(*badger.Txn) error {
+	return traverse(makePrefix(codeReporterChunkFaultReports, reporterID, epoch, resultID), lookup(reportIDs))
+}
+
+// IndexChunkFaultReportByEpoch inserts a chunk fault report ID keyed by the epoch of the executed block and report ID.
+func IndexChunkFaultReportByEpoch(epoch uint64, reportID flow.Identifier) func(*badger.Txn) error {
+	return insert(makePrefix(codeEpochChunkFaultReports, epoch, reportID), reportID)
+}
+
+// LookupChunkFaultReportsBeforeEpoch finds the IDs of all chunk fault reports on the blocks of the epochs before the
+// given epoch, keyed by the epoch of their executed block.
+func LookupChunkFaultReportsBeforeEpoch(epoch uint64, reportIDs map[uint64][]flow.Identifier) func(*badger.Txn) error {
+	if epoch == 0 {
+		return func(*badger.Txn) error { return nil }
+	}
+	return iterate(makePrefix(codeEpochChunkFaultReports, uint64(0)), makePrefix(codeEpochChunkFaultReports, epoch-1),
+		func() (checkFunc, createFunc, handleFunc) {
+			var reportEpoch uint64
+			check := func(key []byte) bool {
+				reportEpoch = binary.BigEndian.Uint64(key[1:9])
+				return true
+			}
+			var reportID flow.Identifier
+			create := func() interface{} {
+				return &reportID
+			}
+			handle := func() error {
+				reportIDs[reportEpoch] = append(reportIDs[reportEpoch], reportID)
+				return nil
+			}
+			return check, create, handle
+		})
+}
+
+// RemoveChunkFaultReport removes a chunk fault report by ID.
+func RemoveChunkFaultReport(reportID flow.Identifier) func(*badger.Txn) error {
+	return remove(makePrefix(codeChunkFaultReport, reportID))
+}
+
+// RemoveChunkFaultReportIndex removes the index of a chunk fault report by execution result ID.
+func RemoveChunkFaultReportIndex(resultID flow.Identifier, reportID flow.Identifier) func(*badger.Txn) error {
+	return remove(makePrefix(codeResultChunkFaultReports, resultID, reportID))
+}
+
+// RemoveChunkFaultReportIndexByReporter removes the index of a chunk fault report by reporter ID, epoch of the
+// executed block and execution result ID.
+func RemoveChunkFaultReportIndexByReporter(reporterID flow.Identifier, epoch uint64, resultID flow.Identifier, reportID flow.Identifier) func(*badger.Txn) error {
+	return remove(makePrefix(codeReporterChunkFaultReports, reporterID, epoch, resultID, reportID))
+}
+
+// RemoveChunkFaultReportIndexByEpoch removes the index of a chunk fault report by epoch of the executed block.
+func RemoveChunkFaultReportIndexByEpoch(epoch uint64, reportID flow.Identifier) func(*badger.Txn) error {
+	return remove(makePrefix(codeEpochChunkFaultReports, epoch, reportID))
+}
@@ -6,6 +6,7 @@ import (
 	"github.com/dgraph-io/badger/v2"
 
 	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/storage"
 )
 
 // IndexStateCommitment indexes a state commitment.
@@ -18,7 +19,7 @@ func IndexStateCommitment(blockID flow.Identifier, commit flow.StateCommitment)
 // BatchIndexStateCommitment indexes a state commitment into a batch
 //
 // State commitments are keyed by the block whose execution results in the state with the given commit.
-func BatchIndexStateCommitment(blockID flow.Identifier, commit flow.StateCommitment) func(batch *badger.WriteBatch) error {
+func BatchIndexStateCommitment(blockID flow.Identifier, commit flow.StateCommitment) func(batch storage.Writer) error {
 	return batchWrite(makePrefix(codeCommit, blockID), commit)
 }
 
@@ -37,6 +38,6 @@ func RemoveStateCommitment(blockID flow.Identifier) func(*badger.Txn) error {
 // BatchRemoveStateCommitment batch removes the state commitment by block ID
 // No errors are expected during normal operation, even if no entries are matched.
 // If Badger unexpectedly fails to process the request, the error is wrapped in a generic error and returned.
-func BatchRemoveStateCommitment(blockID flow.Identifier) func(batch *badger.WriteBatch) error {
+func BatchRemoveStateCommitment(blockID flow.Identifier) func(batch storage.Writer) error {
 	return batchRemove(makePrefix(codeCommit, blockID))
 }
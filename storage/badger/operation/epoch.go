@@ -2,6 +2,7 @@ package operation
 
 import (
 	"errors"
+	"fmt"
 
 	"github.com/dgraph-io/badger/v2"
 
@@ -29,8 +30,67 @@ func InsertEpochStatus(blockID flow.Identifier, status *flow.EpochStatus) func(*
 	return insert(makePrefix(codeBlockEpochStatus, blockID), status)
 }
 
+// RetrieveEpochStatus retrieves the epoch status for the given block.
+//
+// Epoch statuses persisted before EpochFallbackTriggered was added to the
+// status do not contain the field. For those, the field is derived from the
+// epoch emergency fallback flag: at that time the flag could not be unset, so
+// the block is in fallback mode iff the flag is set and the block is not below
+// the block which triggered it.
+//
+// Error returns:
+//   - storage.ErrNotFound if no epoch status is stored for the block
 func RetrieveEpochStatus(blockID flow.Identifier, status *flow.EpochStatus) func(*badger.Txn) error {
-	return retrieve(makePrefix(codeBlockEpochStatus, blockID), status)
+	return func(tx *badger.Txn) error {
+		// decoded separately to tell a stored false apart from a missing field
+		var stored struct {
+			EpochFallbackTriggered *bool
+		}
+		err := retrieve(makePrefix(codeBlockEpochStatus, blockID), &stored)(tx)
+		if err != nil {
+			return err
+		}
+		err = retrieve(makePrefix(codeBlockEpochStatus, blockID), status)(tx)
+		if err != nil {
+			return err
+		}
+		if stored.EpochFallbackTriggered != nil {
+			return nil
+		}
+
+		triggered, err := epochFallbackTriggeredAt(blockID)(tx)
+		if err != nil {
+			return fmt.Errorf("could not derive epoch fallback flag of legacy epoch status: %w", err)
+		}
+		status.EpochFallbackTriggered = triggered
+		return nil
+	}
+}
+
+// epochFallbackTriggeredAt returns whether epoch emergency fallback, as recorded
+// by the global flag, was triggered at or below the height of the given block.
+func epochFallbackTriggeredAt(blockID flow.Identifier) func(*badger.Txn) (bool, error) {
+	return func(tx *badger.Txn) (bool, error) {
+		var triggerBlockID flow.Identifier
+		err := RetrieveEpochEmergencyFallbackTriggeredBlockID(&triggerBlockID)(tx)
+		if errors.Is(err, storage.ErrNotFound) {
+			return false, nil
+		}
+		if err != nil {
+			return false, fmt.Errorf("could not retrieve epoch fallback trigger block: %w", err)
+		}
+
+		var block, trigger flow.Header
+		err = RetrieveHeader(blockID, &block)(tx)
+		if err != nil {
+			return false, fmt.Errorf("could not retrieve header of block %x: %w", blockID, err)
+		}
+		err = RetrieveHeader(triggerBlockID, &trigger)(tx)
+		if err != nil {
+			return false, fmt.Errorf("could not retrieve header of epoch fallback trigger block %x: %w", triggerBlockID, err)
+		}
+		return block.Height >= trigger.Height, nil
+	}
 }
 
 // SetEpochEmergencyFallbackTriggered sets a flag in the DB indicating that
@@ -46,6 +106,16 @@ func SetEpochEmergencyFallbackTriggered(blockID flow.Identifier) func(txn *badge
 	return SkipDuplicates(insert(makePrefix(codeEpochEmergencyFallbackTriggered), blockID))
 }
 
+// UnsetEpochEmergencyFallbackTriggered removes the flag indicating that epoch
+// emergency fallback has been triggered. This happens when finalizing the first
+// block of a recovery epoch, which was committed by an EpochRecover service event.
+//
+// Error returns:
+//   - storage.ErrNotFound if epoch emergency fallback has not been triggered
+func UnsetEpochEmergencyFallbackTriggered() func(txn *badger.Txn) error {
+	return remove(makePrefix(codeEpochEmergencyFallbackTriggered))
+}
+
 // RetrieveEpochEmergencyFallbackTriggeredBlockID gets the block ID where epoch
 // emergency was triggered.
 func RetrieveEpochEmergencyFallbackTriggeredBlockID(blockID *flow.Identifier) func(*badger.Txn) error {
@@ -5,8 +5,10 @@ import (
 
 	"github.com/dgraph-io/badger/v2"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/storage"
 	"github.com/onflow/flow-go/utils/unittest"
 )
 
@@ -65,4 +67,113 @@ func TestEpochEmergencyFallback(t *testing.T) {
 			assert.Equal(t, blockID, storedBlockID)
 		})
 	})
+	t.Run("should be able to unset flag", func(t *testing.T) {
+		unittest.RunWithBadgerDB(t, func(db *badger.DB) {
+			// unsetting the flag when it was never set should fail
+			err := db.Update(UnsetEpochEmergencyFallbackTriggered())
+			assert.ErrorIs(t, err, storage.ErrNotFound)
+
+			err = db.Update(SetEpochEmergencyFallbackTriggered(blockID))
+			assert.NoError(t, err)
+			err = db.Update(UnsetEpochEmergencyFallbackTriggered())
+			assert.NoError(t, err)
+
+			// read the flag, should be false again
+			var triggered bool
+			err = db.View(CheckEpochEmergencyFallbackTriggered(&triggered))
+			assert.NoError(t, err)
+			assert.False(t, triggered)
+
+			// setting the flag again should store the new block ID
+			otherBlockID := unittest.IdentifierFixture()
+			err = db.Update(SetEpochEmergencyFallbackTriggered(otherBlockID))
+			assert.NoError(t, err)
+			var storedBlockID flow.Identifier
+			err = db.View(RetrieveEpochEmergencyFallbackTriggeredBlockID(&storedBlockID))
+			assert.NoError(t, err)
+			assert.Equal(t, otherBlockID, storedBlockID)
+		})
+	})
+}
+
+// TestRetrieveLegacyEpochStatus verifies that epoch statuses stored before the
+// EpochFallbackTriggered field was added are decoded with the field derived from
+// the epoch emergency fallback flag.
+func TestRetrieveLegacyEpochStatus(t *testing.T) {
+
+	// legacyEpochStatus is the EpochStatus as persisted before EpochFallbackTriggered was added
+	type legacyEpochStatus struct {
+		PreviousEpoch                   flow.EventIDs
+		CurrentEpoch                    flow.EventIDs
+		NextEpoch                       flow.EventIDs
+		InvalidServiceEventIncorporated bool
+	}
+
+	trigger := unittest.BlockHeaderFixture(unittest.WithHeaderHeight(100))
+	below := unittest.BlockHeaderFixture(unittest.WithHeaderHeight(99))
+	above := unittest.BlockHeaderFixture(unittest.WithHeaderHeight(101))
+
+	// storeLegacy stores the headers and a legacy encoded epoch status for each of them
+	storeLegacy := func(t *testing.T, db *badger.DB, expected *flow.EpochStatus) {
+		legacy := legacyEpochStatus{
+			PreviousEpoch:                   expected.PreviousEpoch,
+			CurrentEpoch:                    expected.CurrentEpoch,
+			NextEpoch:                       expected.NextEpoch,
+			InvalidServiceEventIncorporated: expected.InvalidServiceEventIncorporated,
+		}
+		for _, header := range []*flow.Header{trigger, below, above} {
+			require.NoError(t, db.Update(InsertHeader(header.ID(), header)))
+			require.NoError(t, db.Update(insert(makePrefix(codeBlockEpochStatus, header.ID()), legacy)))
+		}
+	}
+
+	t.Run("epoch fallback not triggered", func(t *testing.T) {
+		unittest.RunWithBadgerDB(t, func(db *badger.DB) {
+			expected := unittest.EpochStatusFixture()
+			storeLegacy(t, db, expected)
+
+			for _, header := range []*flow.Header{trigger, below, above} {
+				var actual flow.EpochStatus
+				err := db.View(RetrieveEpochStatus(header.ID(), &actual))
+				require.NoError(t, err)
+				assert.Equal(t, *expected, actual)
+			}
+		})
+	})
+
+	t.Run("epoch fallback triggered", func(t *testing.T) {
+		unittest.RunWithBadgerDB(t, func(db *badger.DB) {
+			expected := unittest.EpochStatusFixture()
+			storeLegacy(t, db, expected)
+			require.NoError(t, db.Update(SetEpochEmergencyFallbackTriggered(trigger.ID())))
+
+			var actual flow.EpochStatus
+			err := db.View(RetrieveEpochStatus(below.ID(), &actual))
+			require.NoError(t, err)
+			assert.Equal(t, *expected, actual)
+
+			expected.EpochFallbackTriggered = true
+			for _, header := range []*flow.Header{trigger, above} {
+				var actual flow.EpochStatus
+				err := db.View(RetrieveEpochStatus(header.ID(), &actual))
+				require.NoError(t, err)
+				assert.Equal(t, *expected, actual)
+			}
+		})
+	})
+
+	t.Run("stored flag takes precedence", func(t *testing.T) {
+		unittest.RunWithBadgerDB(t, func(db *badger.DB) {
+			expected := unittest.EpochStatusFixture()
+			require.NoError(t, db.Update(InsertHeader(above.ID(), above)))
+			require.NoError(t, db.Update(InsertHeader(trigger.ID(), trigger)))
+			require.NoError(t, db.Update(InsertEpochStatus(above.ID(), expected)))
+			require.NoError(t, db.Update(SetEpochEmergencyFallbackTriggered(trigger.ID())))
+
+			var actual flow.EpochStatus
+			err := db.View(RetrieveEpochStatus(above.ID(), &actual))
+			require.NoError(t, err)
+			assert.Equal(t, *expected, actual)
+		})
+	})
 }
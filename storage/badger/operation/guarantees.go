@@ -21,3 +21,15 @@ func IndexPayloadGuarantees(blockID flow.Identifier, guarIDs []flow.Identifier)
 func LookupPayloadGuarantees(blockID flow.Identifier, guarIDs *[]flow.Identifier) func(*badger.Txn) error {
 	return retrieve(makePrefix(codePayloadGuarantees, blockID), guarIDs)
 }
+
+// RemoveGuarantee removes the collection guarantee with the given collection ID.
+// Returns storage.ErrNotFound if the guarantee does not exist.
+func RemoveGuarantee(collID flow.Identifier) func(*badger.Txn) error {
+	return remove(makePrefix(codeGuarantee, collID))
+}
+
+// RemovePayloadGuarantees removes the index of the guarantees in the payload of the given block.
+// Returns storage.ErrNotFound if the index does not exist.
+func RemovePayloadGuarantees(blockID flow.Identifier) func(*badger.Txn) error {
+	return remove(makePrefix(codePayloadGuarantees, blockID))
+}
@@ -69,3 +69,19 @@ func UpdateLastCompleteBlockHeight(height uint64) func(*badger.Txn) error {
 func RetrieveLastCompleteBlockHeight(height *uint64) func(*badger.Txn) error {
 	return retrieve(makePrefix(codeLastCompleteBlockHeight), height)
 }
+
+// InsertPrunedHeight inserts the lowest finalized height whose block payload has not been pruned.
+func InsertPrunedHeight(height uint64) func(*badger.Txn) error {
+	return insert(makePrefix(codePrunedHeight), height)
+}
+
+// UpdatePrunedHeight updates the lowest finalized height whose block payload has not been pruned.
+func UpdatePrunedHeight(height uint64) func(*badger.Txn) error {
+	return update(makePrefix(codePrunedHeight), height)
+}
+
+// RetrievePrunedHeight retrieves the lowest finalized height whose block payload has not been pruned.
+// Returns storage.ErrNotFound if no block payload has been pruned yet.
+func RetrievePrunedHeight(height *uint64) func(*badger.Txn) error {
+	return retrieve(makePrefix(codePrunedHeight), height)
+}
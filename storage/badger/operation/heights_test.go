@@ -138,3 +138,27 @@ func TestLastCompleteBlockHeightInsertIfNotExists(t *testing.T) {
 		assert.Equal(t, retrieved, height1)
 	})
 }
+
+func TestPrunedHeightInsertUpdateRetrieve(t *testing.T) {
+	unittest.RunWithBadgerDB(t, func(db *badger.DB) {
+		var retrieved uint64
+		err := db.View(RetrievePrunedHeight(&retrieved))
+		require.ErrorIs(t, err, storage.ErrNotFound)
+
+		height := uint64(1337)
+		err = db.Update(InsertPrunedHeight(height))
+		require.NoError(t, err)
+
+		err = db.View(RetrievePrunedHeight(&retrieved))
+		require.NoError(t, err)
+		assert.Equal(t, height, retrieved)
+
+		height = 9999
+		err = db.Update(UpdatePrunedHeight(height))
+		require.NoError(t, err)
+
+		err = db.View(RetrievePrunedHeight(&retrieved))
+		require.NoError(t, err)
+		assert.Equal(t, height, retrieved)
+	})
+}
@@ -0,0 +1,92 @@
+package operation
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	"github.com/dgraph-io/badger/v2"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/storage"
+)
+
+// UpsertMempoolTransaction persists a transaction of the transaction pool of the given epoch.
+// No errors are expected during normal operation.
+func UpsertMempoolTransaction(epoch uint64, tx *flow.TransactionBody) func(*badger.Txn) error {
+	return upsert(makePrefix(codeMempoolTransaction, epoch, tx.ID()), tx)
+}
+
+// RemoveMempoolTransaction removes a persisted transaction of the transaction pool of the given epoch.
+// Error returns:
+//   - storage.ErrNotFound if the transaction is not persisted.
+func RemoveMempoolTransaction(epoch uint64, txID flow.Identifier) func(*badger.Txn) error {
+	return remove(makePrefix(codeMempoolTransaction, epoch, txID))
+}
+
+// BatchUpsertMempoolTransaction persists a transaction of the transaction pool of the given epoch in a write batch.
+// No errors are expected during normal operation.
+func BatchUpsertMempoolTransaction(epoch uint64, tx *flow.TransactionBody) func(batch storage.Writer) error {
+	return batchWrite(makePrefix(codeMempoolTransaction, epoch, tx.ID()), tx)
+}
+
+// BatchRemoveMempoolTransaction removes a persisted transaction of the transaction pool of the given epoch in a write
+// batch. It is a no-op if the transaction is not persisted.
+// No errors are expected during normal operation.
+func BatchRemoveMempoolTransaction(epoch uint64, txID flow.Identifier) func(batch storage.Writer) error {
+	return batchRemove(makePrefix(codeMempoolTransaction, epoch, txID))
+}
+
+// RetrieveMempoolTransactions retrieves the persisted transactions of the transaction pool of the given epoch.
+// No errors are expected during normal operation.
+func RetrieveMempoolTransactions(epoch uint64, txs *[]*flow.TransactionBody) func(*badger.Txn) error {
+	return traverse(makePrefix(codeMempoolTransaction, epoch), func() (checkFunc, createFunc, handleFunc) {
+		check := func(key []byte) bool {
+			return true
+		}
+		var tx flow.TransactionBody
+		create := func() interface{} {
+			return &tx
+		}
+		handle := func() error {
+			body := tx
+			*txs = append(*txs, &body)
+			return nil
+		}
+		return check, create, handle
+	})
+}
+
+// RemoveMempoolTransactions removes the persisted transactions of the transaction pool of the given epoch.
+// No errors are expected during normal operation, even if no transaction is persisted.
+func RemoveMempoolTransactions(epoch uint64) func(*badger.Txn) error {
+	return removeByPrefix(makePrefix(codeMempoolTransaction, epoch))
+}
+
+// RemoveMempoolTransactionsBeforeEpoch removes the persisted transactions of the transaction pools of the epochs
+// before the given epoch.
+// No errors are expected during normal operation, even if no transaction is persisted.
+func RemoveMempoolTransactionsBeforeEpoch(epoch uint64) func(*badger.Txn) error {
+	return func(tx *badger.Txn) error {
+		prefix := makePrefix(codeMempoolTransaction)
+		opts := badger.DefaultIteratorOptions
+		opts.PrefetchValues = false
+		it := tx.NewIterator(opts)
+		defer it.Close()
+
+		// the keys are ordered by epoch, stop at the first key of the given epoch
+		for it.Seek(prefix); it.ValidForPrefix(prefix); it.Next() {
+			key := it.Item().KeyCopy(nil)
+			if len(key) < 1+8 {
+				return fmt.Errorf("invalid mempool transaction key %x", key)
+			}
+			if binary.BigEndian.Uint64(key[1:9]) >= epoch {
+				break
+			}
+			err := tx.Delete(key)
+			if err != nil {
+				return fmt.Errorf("could not remove mempool transaction: %w", err)
+			}
+		}
+		return nil
+	}
+}
@@ -0,0 +1,53 @@
+package operation
+
+import (
+	"testing"
+
+	"github.com/dgraph-io/badger/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/storage"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+func TestMempoolTransactions(t *testing.T) {
+	unittest.RunWithBadgerDB(t, func(db *badger.DB) {
+		txs := make(map[uint64][]*flow.TransactionBody)
+		for epoch := uint64(1); epoch <= 3; epoch++ {
+			for i := 0; i < 3; i++ {
+				tx := unittest.TransactionBodyFixture(func(tx *flow.TransactionBody) {
+					tx.ProposalKey.SequenceNumber = epoch*10 + uint64(i)
+				})
+				require.NoError(t, db.Update(UpsertMempoolTransaction(epoch, &tx)))
+				// persisting a transaction is idempotent
+				require.NoError(t, db.Update(UpsertMempoolTransaction(epoch, &tx)))
+				txs[epoch] = append(txs[epoch], &tx)
+			}
+		}
+
+		var actual []*flow.TransactionBody
+		require.NoError(t, db.View(RetrieveMempoolTransactions(2, &actual)))
+		assert.ElementsMatch(t, txs[2], actual)
+
+		require.NoError(t, db.Update(RemoveMempoolTransaction(2, txs[2][0].ID())))
+		err := db.Update(RemoveMempoolTransaction(2, txs[2][0].ID()))
+		assert.ErrorIs(t, err, storage.ErrNotFound)
+		actual = nil
+		require.NoError(t, db.View(RetrieveMempoolTransactions(2, &actual)))
+		assert.ElementsMatch(t, txs[2][1:], actual)
+
+		require.NoError(t, db.Update(RemoveMempoolTransactionsBeforeEpoch(3)))
+		for epoch := uint64(1); epoch <= 2; epoch++ {
+			actual = nil
+			require.NoError(t, db.View(RetrieveMempoolTransactions(epoch, &actual)))
+			assert.Empty(t, actual)
+		}
+
+		require.NoError(t, db.Update(RemoveMempoolTransactions(3)))
+		actual = nil
+		require.NoError(t, db.View(RetrieveMempoolTransactions(3, &actual)))
+		assert.Empty(t, actual)
+	})
+}
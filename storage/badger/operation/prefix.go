@@ -33,6 +33,7 @@ const (
 	codeRootHeight              = 24 // the height of the highest block contained in the root snapshot
 	codeLastCompleteBlockHeight = 25 // the height of the last block for which all collections were received
 	codeEpochFirstHeight        = 26 // the height of the first block in a given epoch
+	codePrunedHeight            = 27 // the lowest finalized height whose block payload has not been pruned
 
 	// codes for single entity storage
 	// 31 was used for identities before epochs
@@ -45,6 +46,7 @@ const (
 	codeExecutionReceiptMeta = 36
 	codeResultApproval       = 37
 	codeChunk                = 38
+	codeChunkFaultReport     = 39
 
 	// codes for indexing single identifier by identifier/integeter
 	codeHeightToBlock              = 40 // index mapping height to block ID
@@ -84,6 +86,14 @@ const (
 	codeJobQueue             = 71
 	codeJobQueuePointer      = 72
 
+	// memory pools persisted across restarts
+	codeMempoolTransaction = 80 // transactions of the collection node transaction pools, keyed by epoch
+
+	// codes for indexing chunk fault reports
+	codeResultChunkFaultReports   = 81 // index mapping execution result ID to chunk fault report IDs
+	codeReporterChunkFaultReports = 82 // index mapping reporter ID, epoch and execution result ID to chunk fault report IDs
+	codeEpochChunkFaultReports    = 83 // index mapping epoch to chunk fault report IDs, for pruning the reports of past epochs
+
 	// legacy codes (should be cleaned up)
 	codeChunkDataPack                = 100
 	codeCommit                       = 101
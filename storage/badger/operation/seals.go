@@ -38,6 +38,12 @@ func LookupPayloadResults(blockID flow.Identifier, resultIDs *[]flow.Identifier)
 	return retrieve(makePrefix(codePayloadResults, blockID), resultIDs)
 }
 
+// RemovePayloadReceipts removes the index of the receipts in the payload of the given block.
+// Returns storage.ErrNotFound if the index does not exist.
+func RemovePayloadReceipts(blockID flow.Identifier) func(*badger.Txn) error {
+	return remove(makePrefix(codePayloadReceipts, blockID))
+}
+
 // IndexLatestSealAtBlock persists the highest seal that was included in the fork up to (and including) blockID.
 // In most cases, it is the highest seal included in this block's payload. However, if there are no
 // seals in this block, sealID should reference the highest seal in blockID's ancestor.
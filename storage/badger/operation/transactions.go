@@ -15,3 +15,9 @@ func InsertTransaction(txID flow.Identifier, tx *flow.TransactionBody) func(*bad
 func RetrieveTransaction(txID flow.Identifier, tx *flow.TransactionBody) func(*badger.Txn) error {
 	return retrieve(makePrefix(codeTransaction, txID), tx)
 }
+
+// RemoveTransaction removes a transaction by fingerprint.
+// Returns storage.ErrNotFound if the transaction does not exist.
+func RemoveTransaction(txID flow.Identifier) func(*badger.Txn) error {
+	return remove(makePrefix(codeTransaction, txID))
+}
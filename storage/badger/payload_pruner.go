@@ -0,0 +1,280 @@
+package badger
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/dgraph-io/badger/v2"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/storage"
+	"github.com/onflow/flow-go/storage/badger/operation"
+	"github.com/onflow/flow-go/storage/badger/procedure"
+)
+
+var _ storage.PayloadPruner = (*PayloadPruner)(nil)
+
+// PayloadPruner removes the payloads of finalized blocks, and the guarantees, collections and
+// receipts they contain. The seal and result indexes of the payloads are retained, as are the
+// indexes from collections to blocks and from transactions to collections, so that lookups of
+// pruned collections and transactions can report storage.ErrPruned. Entities removed from the
+// database are evicted from the caches of the given storages as well.
+type PayloadPruner struct {
+	db           *badger.DB
+	index        *Index
+	guarantees   *Guarantees
+	receipts     *ExecutionReceipts
+	transactions *Transactions
+}
+
+func NewPayloadPruner(db *badger.DB, index *Index, guarantees *Guarantees, receipts *ExecutionReceipts, transactions *Transactions) *PayloadPruner {
+	return &PayloadPruner{
+		db:           db,
+		index:        index,
+		guarantees:   guarantees,
+		receipts:     receipts,
+		transactions: transactions,
+	}
+}
+
+// prunedPayload holds the IDs of the entities removed with a payload.
+type prunedPayload struct {
+	collectionIDs  []flow.Identifier
+	transactionIDs []flow.Identifier
+	receiptIDs     []flow.Identifier
+}
+
+// PrunedHeight returns the lowest finalized height whose payload has not been pruned.
+// Expected errors during normal operation:
+//   - storage.ErrNotFound if no payload has been pruned yet
+func (p *PayloadPruner) PrunedHeight() (uint64, error) {
+	var height uint64
+	err := p.db.View(operation.RetrievePrunedHeight(&height))
+	return height, err
+}
+
+// PruneUpToHeight removes the payloads of the finalized blocks below the given height.
+// The payload of each block is removed in the same transaction which advances the pruned
+// height, so pruning can be interrupted and resumed at any point. If no payload was pruned
+// before, pruning starts at the root block of the node, or the spork root block if it is
+// higher; heights without a finalized block are skipped.
+// No errors are expected during normal operation.
+func (p *PayloadPruner) PruneUpToHeight(height uint64) error {
+	var start, finalized uint64
+	err := p.db.View(func(tx *badger.Txn) error {
+		err := operation.RetrieveFinalizedHeight(&finalized)(tx)
+		if err != nil {
+			return fmt.Errorf("could not retrieve finalized height: %w", err)
+		}
+		err = operation.RetrievePrunedHeight(&start)(tx)
+		if errors.Is(err, storage.ErrNotFound) {
+			start, err = pruningStartHeight(tx)
+		}
+		if err != nil {
+			return fmt.Errorf("could not retrieve pruning start height: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if height > finalized+1 {
+		return fmt.Errorf("cannot prune up to height %d above finalized height %d", height, finalized)
+	}
+
+	for h := start; h < height; h++ {
+		var blockID flow.Identifier
+		err := p.db.View(operation.LookupBlockHeight(h, &blockID))
+		if errors.Is(err, storage.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("could not look up block at height %d: %w", h, err)
+		}
+
+		var pruned prunedPayload
+		err = operation.RetryOnConflict(p.db.Update, func(tx *badger.Txn) error {
+			pruned = prunedPayload{}
+			err := p.prunePayload(blockID, &pruned)(tx)
+			if err != nil {
+				return err
+			}
+			return setPrunedHeight(h + 1)(tx)
+		})
+		if err != nil {
+			return fmt.Errorf("could not prune payload of block %x at height %d: %w", blockID, h, err)
+		}
+		p.evict(blockID, &pruned)
+	}
+
+	if start < height {
+		err = operation.RetryOnConflict(p.db.Update, setPrunedHeight(height))
+		if err != nil {
+			return fmt.Errorf("could not update pruned height: %w", err)
+		}
+	}
+	return nil
+}
+
+// prunePayload removes the guarantee and receipt indexes of the payload of the given block
+// together with the guarantees, collections and receipts of the payload. Blocks without stored
+// payload are skipped. Receipts which are indexed as own receipts of an execution node are
+// retained.
+func (p *PayloadPruner) prunePayload(blockID flow.Identifier, pruned *prunedPayload) func(*badger.Txn) error {
+	return func(tx *badger.Txn) error {
+		var index flow.Index
+		err := procedure.RetrieveIndex(blockID, &index)(tx)
+		if errors.Is(err, storage.ErrNotFound) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("could not retrieve index: %w", err)
+		}
+
+		for _, collID := range index.CollectionIDs {
+			err = operation.SkipNonExist(operation.RemoveGuarantee(collID))(tx)
+			if err != nil {
+				return fmt.Errorf("could not remove guarantee %x: %w", collID, err)
+			}
+			err = p.pruneCollection(collID, pruned)(tx)
+			if err != nil {
+				return fmt.Errorf("could not remove collection %x: %w", collID, err)
+			}
+			pruned.collectionIDs = append(pruned.collectionIDs, collID)
+		}
+
+		for _, receiptID := range index.ReceiptIDs {
+			removed, err := p.pruneReceipt(receiptID)(tx)
+			if err != nil {
+				return fmt.Errorf("could not remove receipt %x: %w", receiptID, err)
+			}
+			if removed {
+				pruned.receiptIDs = append(pruned.receiptIDs, receiptID)
+			}
+		}
+
+		err = procedure.PruneIndex(blockID)(tx)
+		if err != nil {
+			return fmt.Errorf("could not remove index: %w", err)
+		}
+		return nil
+	}
+}
+
+// pruneCollection removes the light collection with the given ID and its transactions, if the
+// collection is stored. The indexes of the collection by block and of its transactions by
+// collection are retained to identify pruned collections and transactions.
+func (p *PayloadPruner) pruneCollection(collID flow.Identifier, pruned *prunedPayload) func(*badger.Txn) error {
+	return func(tx *badger.Txn) error {
+		var light flow.LightCollection
+		err := operation.RetrieveCollection(collID, &light)(tx)
+		if errors.Is(err, storage.ErrNotFound) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("could not retrieve collection: %w", err)
+		}
+
+		for _, txID := range light.Transactions {
+			err = operation.SkipNonExist(operation.RemoveTransaction(txID))(tx)
+			if err != nil {
+				return fmt.Errorf("could not remove transaction %x: %w", txID, err)
+			}
+			pruned.transactionIDs = append(pruned.transactionIDs, txID)
+		}
+
+		err = operation.RemoveCollection(collID)(tx)
+		if err != nil {
+			return fmt.Errorf("could not remove light collection: %w", err)
+		}
+		return nil
+	}
+}
+
+// pruneReceipt removes the receipt meta with the given ID and its entry in the index of the
+// receipts of the executed block, and returns whether the receipt was removed. The execution
+// result is retained, as it is needed for sealing segments.
+func (p *PayloadPruner) pruneReceipt(receiptID flow.Identifier) func(*badger.Txn) (bool, error) {
+	return func(tx *badger.Txn) (bool, error) {
+		var meta flow.ExecutionReceiptMeta
+		err := operation.RetrieveExecutionReceiptMeta(receiptID, &meta)(tx)
+		if errors.Is(err, storage.ErrNotFound) {
+			return false, nil
+		}
+		if err != nil {
+			return false, fmt.Errorf("could not retrieve receipt meta: %w", err)
+		}
+		var result flow.ExecutionResult
+		err = operation.RetrieveExecutionResult(meta.ResultID, &result)(tx)
+		if err != nil {
+			return false, fmt.Errorf("could not retrieve result %x: %w", meta.ResultID, err)
+		}
+
+		// execution nodes keep their own receipts
+		var ownReceiptID flow.Identifier
+		err = operation.LookupOwnExecutionReceipt(result.BlockID, &ownReceiptID)(tx)
+		if err == nil && ownReceiptID == receiptID {
+			return false, nil
+		}
+		if err != nil && !errors.Is(err, storage.ErrNotFound) {
+			return false, fmt.Errorf("could not look up own receipt: %w", err)
+		}
+
+		err = operation.RemoveExecutionReceiptMeta(receiptID)(tx)
+		if err != nil {
+			return false, fmt.Errorf("could not remove receipt meta: %w", err)
+		}
+		err = operation.SkipNonExist(operation.RemoveExecutionReceiptIndex(result.BlockID, receiptID))(tx)
+		if err != nil {
+			return false, fmt.Errorf("could not remove receipt index: %w", err)
+		}
+		return true, nil
+	}
+}
+
+// evict removes the pruned entities from the caches, once they have been removed from the database.
+func (p *PayloadPruner) evict(blockID flow.Identifier, pruned *prunedPayload) {
+	p.index.cache.Remove(blockID)
+	for _, collID := range pruned.collectionIDs {
+		p.guarantees.cache.Remove(collID)
+	}
+	for _, txID := range pruned.transactionIDs {
+		p.transactions.cache.Remove(txID)
+	}
+	for _, receiptID := range pruned.receiptIDs {
+		p.receipts.cache.Remove(receiptID)
+	}
+}
+
+// pruningStartHeight returns the height at which pruning starts if no payload was pruned before,
+// which is the root block height, or the spork root block height if it is higher.
+func pruningStartHeight(tx *badger.Txn) (uint64, error) {
+	var sporkRootHeight, rootHeight uint64
+	err := operation.RetrieveSporkRootBlockHeight(&sporkRootHeight)(tx)
+	if err != nil {
+		return 0, fmt.Errorf("could not retrieve spork root block height: %w", err)
+	}
+	err = operation.RetrieveRootHeight(&rootHeight)(tx)
+	if err != nil {
+		return 0, fmt.Errorf("could not retrieve root height: %w", err)
+	}
+	if rootHeight > sporkRootHeight {
+		return rootHeight, nil
+	}
+	return sporkRootHeight, nil
+}
+
+// setPrunedHeight inserts or updates the pruned height.
+func setPrunedHeight(height uint64) func(*badger.Txn) error {
+	return func(tx *badger.Txn) error {
+		var current uint64
+		err := operation.RetrievePrunedHeight(&current)(tx)
+		if errors.Is(err, storage.ErrNotFound) {
+			return operation.InsertPrunedHeight(height)(tx)
+		}
+		if err != nil {
+			return fmt.Errorf("could not retrieve pruned height: %w", err)
+		}
+		return operation.UpdatePrunedHeight(height)(tx)
+	}
+}
@@ -0,0 +1,157 @@
+package badger_test
+
+import (
+	"testing"
+
+	"github.com/dgraph-io/badger/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/module/metrics"
+	"github.com/onflow/flow-go/storage"
+	"github.com/onflow/flow-go/storage/badger/operation"
+	"github.com/onflow/flow-go/utils/unittest"
+
+	badgerstorage "github.com/onflow/flow-go/storage/badger"
+)
+
+// TestPayloadPruner tests that the payloads of finalized blocks below the pruning height are
+// removed together with their guarantees, collections and receipts, while headers, seals and
+// results are retained, and that pruning starts at the root block.
+func TestPayloadPruner(t *testing.T) {
+	unittest.RunWithBadgerDB(t, func(db *badger.DB) {
+		all := badgerstorage.InitAll(metrics.NewNoopCollector(), db)
+
+		collections := make([]flow.Collection, 0, 4)
+		receipts := make([]*flow.ExecutionReceipt, 0, 4)
+		seals := make([]*flow.Seal, 0, 4)
+		blocks := make([]*flow.Block, 0, 4)
+		parent := unittest.BlockHeaderFixture(unittest.WithHeaderHeight(10))
+		for i := 0; i < 4; i++ {
+			collection := unittest.CollectionFixture(2)
+			require.NoError(t, all.Collections.Store(&collection))
+			receipt := unittest.ExecutionReceiptFixture()
+			seal := unittest.Seal.Fixture()
+
+			block := unittest.BlockWithParentFixture(parent)
+			block.SetPayload(unittest.PayloadFixture(
+				unittest.WithGuarantees(&flow.CollectionGuarantee{CollectionID: collection.ID()}),
+				unittest.WithReceipts(receipt),
+				unittest.WithSeals(seal),
+			))
+			require.NoError(t, all.Blocks.Store(block))
+			require.NoError(t, db.Update(operation.IndexBlockHeight(block.Header.Height, block.ID())))
+			require.NoError(t, db.Update(operation.IndexLatestSealAtBlock(block.ID(), seal.ID())))
+			require.NoError(t, db.Update(operation.IndexFinalizedSealByBlockID(seal.BlockID, seal.ID())))
+			require.NoError(t, all.Results.Index(receipt.ExecutionResult.BlockID, receipt.ExecutionResult.ID()))
+
+			// index the collection and its transactions like access nodes do
+			require.NoError(t, all.Blocks.IndexBlockForCollections(block.ID(), []flow.Identifier{collection.ID()}))
+			for _, tx := range collection.Transactions {
+				require.NoError(t, db.Update(operation.IndexCollectionByTransaction(tx.ID(), collection.ID())))
+			}
+
+			// populate the caches
+			_, err := all.Blocks.ByID(block.ID())
+			require.NoError(t, err)
+
+			collections = append(collections, collection)
+			receipts = append(receipts, receipt)
+			seals = append(seals, seal)
+			blocks = append(blocks, block)
+			parent = block.Header
+		}
+		// the node is bootstrapped from a root block above the spork root block
+		require.NoError(t, db.Update(operation.InsertSporkRootBlockHeight(10)))
+		require.NoError(t, db.Update(operation.InsertRootHeight(12)))
+		require.NoError(t, db.Update(operation.InsertFinalizedHeight(14)))
+
+		// execution nodes keep their own receipts
+		ownReceipt := receipts[2]
+		require.NoError(t, db.Update(operation.IndexOwnExecutionReceipt(ownReceipt.ExecutionResult.BlockID, ownReceipt.ID())))
+
+		_, err := all.PayloadPruner.PrunedHeight()
+		require.ErrorIs(t, err, storage.ErrNotFound)
+
+		// pruning above the finalized height fails
+		err = all.PayloadPruner.PruneUpToHeight(16)
+		require.Error(t, err)
+
+		// prune the blocks at heights 12 and 13, starting at the root block
+		require.NoError(t, all.PayloadPruner.PruneUpToHeight(14))
+		prunedHeight, err := all.PayloadPruner.PrunedHeight()
+		require.NoError(t, err)
+		assert.Equal(t, uint64(14), prunedHeight)
+
+		for i, block := range blocks[1:3] {
+			i := i + 1
+			_, err := all.Blocks.ByID(block.ID())
+			assert.ErrorIs(t, err, storage.ErrPruned)
+			_, err = all.Blocks.ByHeight(block.Header.Height)
+			assert.ErrorIs(t, err, storage.ErrPruned)
+			_, err = all.Index.ByBlockID(block.ID())
+			assert.ErrorIs(t, err, storage.ErrPruned)
+
+			header, err := all.Headers.ByBlockID(block.ID())
+			require.NoError(t, err)
+			assert.Equal(t, block.Header.Height, header.Height)
+
+			collection := collections[i]
+			_, err = all.Guarantees.ByCollectionID(collection.ID())
+			assert.ErrorIs(t, err, storage.ErrNotFound)
+			_, err = all.Collections.LightByID(collection.ID())
+			assert.ErrorIs(t, err, storage.ErrPruned)
+			_, err = all.Collections.ByID(collection.ID())
+			assert.ErrorIs(t, err, storage.ErrPruned)
+			_, err = all.Collections.LightByTransactionID(collection.Transactions[0].ID())
+			assert.ErrorIs(t, err, storage.ErrPruned)
+			_, err = all.Transactions.ByID(collection.Transactions[0].ID())
+			assert.ErrorIs(t, err, storage.ErrPruned)
+
+			// seals and results of the pruned payload remain available
+			var sealIDs, resultIDs []flow.Identifier
+			require.NoError(t, db.View(operation.LookupPayloadSeals(block.ID(), &sealIDs)))
+			assert.Equal(t, block.Payload.Index().SealIDs, sealIDs)
+			require.NoError(t, db.View(operation.LookupPayloadResults(block.ID(), &resultIDs)))
+			assert.Equal(t, block.Payload.Index().ResultIDs, resultIDs)
+
+			seal, err := all.Seals.HighestInFork(block.ID())
+			require.NoError(t, err)
+			assert.Equal(t, seals[i].ID(), seal.ID())
+			seal, err = all.Seals.FinalizedSealForBlock(seals[i].BlockID)
+			require.NoError(t, err)
+			assert.Equal(t, seals[i].ID(), seal.ID())
+
+			result, err := all.Results.ByID(receipts[i].ExecutionResult.ID())
+			require.NoError(t, err)
+			assert.Equal(t, receipts[i].ExecutionResult.ID(), result.ID())
+			result, err = all.Results.ByBlockID(receipts[i].ExecutionResult.BlockID)
+			require.NoError(t, err)
+			assert.Equal(t, receipts[i].ExecutionResult.ID(), result.ID())
+		}
+		_, err = all.Receipts.ByID(receipts[1].ID())
+		assert.ErrorIs(t, err, storage.ErrNotFound)
+		_, err = all.Receipts.ByID(ownReceipt.ID())
+		assert.NoError(t, err)
+
+		// the blocks below the root block and at and above the pruned height are untouched
+		for _, i := range []int{0, 3} {
+			retrieved, err := all.Blocks.ByID(blocks[i].ID())
+			require.NoError(t, err)
+			assert.Equal(t, blocks[i].ID(), retrieved.ID())
+			_, err = all.Collections.ByID(collections[i].ID())
+			assert.NoError(t, err)
+			_, err = all.Transactions.ByID(collections[i].Transactions[0].ID())
+			assert.NoError(t, err)
+		}
+
+		// pruning resumes at the pruned height, and is a no-op for lower heights
+		require.NoError(t, all.PayloadPruner.PruneUpToHeight(13))
+		require.NoError(t, all.PayloadPruner.PruneUpToHeight(15))
+		_, err = all.Blocks.ByID(blocks[3].ID())
+		assert.ErrorIs(t, err, storage.ErrPruned)
+		_, err = all.Blocks.ByID(blocks[0].ID())
+		assert.NoError(t, err)
+	})
+}
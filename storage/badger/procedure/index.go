@@ -1,14 +1,17 @@
 package procedure
 
 import (
+	"errors"
 	"fmt"
 
 	"github.com/dgraph-io/badger/v2"
 
 	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/storage"
 	"github.com/onflow/flow-go/storage/badger/operation"
 )
 
+// InsertIndex stores the payload index of the given block.
 func InsertIndex(blockID flow.Identifier, index *flow.Index) func(tx *badger.Txn) error {
 	return func(tx *badger.Txn) error {
 		err := operation.IndexPayloadGuarantees(blockID, index.CollectionIDs)(tx)
@@ -31,10 +34,17 @@ func InsertIndex(blockID flow.Identifier, index *flow.Index) func(tx *badger.Txn
 	}
 }
 
+// RetrieveIndex retrieves the payload index of the given block.
+// Expected errors during normal operation:
+//   - storage.ErrPruned if the block is finalized and its payload has been pruned
+//   - storage.ErrNotFound if no index is stored for the block
 func RetrieveIndex(blockID flow.Identifier, index *flow.Index) func(tx *badger.Txn) error {
 	return func(tx *badger.Txn) error {
 		var collIDs []flow.Identifier
 		err := operation.LookupPayloadGuarantees(blockID, &collIDs)(tx)
+		if errors.Is(err, storage.ErrNotFound) {
+			err = checkPruned(blockID, err)(tx)
+		}
 		if err != nil {
 			return fmt.Errorf("could not retrieve guarantee index: %w", err)
 		}
@@ -63,3 +73,82 @@ func RetrieveIndex(blockID flow.Identifier, index *flow.Index) func(tx *badger.T
 		return nil
 	}
 }
+
+// PruneIndex removes the guarantee and receipt indexes of the payload of the given block. The
+// seal and result indexes are retained, as they are needed to look up the seals and results
+// of the block, which are never pruned.
+// Returns storage.ErrNotFound if no index is stored for the block.
+func PruneIndex(blockID flow.Identifier) func(tx *badger.Txn) error {
+	return func(tx *badger.Txn) error {
+		err := operation.RemovePayloadGuarantees(blockID)(tx)
+		if err != nil {
+			return fmt.Errorf("could not remove guarantee index: %w", err)
+		}
+		err = operation.RemovePayloadReceipts(blockID)(tx)
+		if err != nil {
+			return fmt.Errorf("could not remove receipts index: %w", err)
+		}
+		return nil
+	}
+}
+
+// checkPruned returns storage.ErrPruned if the given block is known and below the pruned height,
+// in which case its payload index has been removed. Otherwise, notFound is returned unchanged.
+func checkPruned(blockID flow.Identifier, notFound error) func(tx *badger.Txn) error {
+	return func(tx *badger.Txn) error {
+		var prunedHeight uint64
+		err := operation.RetrievePrunedHeight(&prunedHeight)(tx)
+		if errors.Is(err, storage.ErrNotFound) {
+			return notFound
+		}
+		if err != nil {
+			return fmt.Errorf("could not retrieve pruned height: %w", err)
+		}
+		var header flow.Header
+		err = operation.RetrieveHeader(blockID, &header)(tx)
+		if errors.Is(err, storage.ErrNotFound) {
+			return notFound
+		}
+		if err != nil {
+			return fmt.Errorf("could not retrieve header: %w", err)
+		}
+		if header.Height < prunedHeight {
+			return fmt.Errorf("payload of block %x at height %d below pruned height %d: %w",
+				blockID, header.Height, prunedHeight, storage.ErrPruned)
+		}
+		return notFound
+	}
+}
+
+// CheckCollectionPruned returns storage.ErrPruned if the collection with the given ID is indexed
+// as part of a block whose payload has been pruned. Otherwise, notFound is returned unchanged.
+func CheckCollectionPruned(collID flow.Identifier, notFound error) func(tx *badger.Txn) error {
+	return func(tx *badger.Txn) error {
+		var blockID flow.Identifier
+		err := operation.LookupCollectionBlock(collID, &blockID)(tx)
+		if errors.Is(err, storage.ErrNotFound) {
+			return notFound
+		}
+		if err != nil {
+			return fmt.Errorf("could not look up block of collection: %w", err)
+		}
+		return checkPruned(blockID, notFound)(tx)
+	}
+}
+
+// CheckTransactionPruned returns storage.ErrPruned if the transaction with the given ID is indexed
+// as part of a collection whose block payload has been pruned. Otherwise, notFound is returned
+// unchanged.
+func CheckTransactionPruned(txID flow.Identifier, notFound error) func(tx *badger.Txn) error {
+	return func(tx *badger.Txn) error {
+		var collID flow.Identifier
+		err := operation.RetrieveCollectionID(txID, &collID)(tx)
+		if errors.Is(err, storage.ErrNotFound) {
+			return notFound
+		}
+		if err != nil {
+			return fmt.Errorf("could not look up collection of transaction: %w", err)
+		}
+		return CheckCollectionPruned(collID, notFound)(tx)
+	}
+}
@@ -7,6 +7,8 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/storage"
+	"github.com/onflow/flow-go/storage/badger/operation"
 	"github.com/onflow/flow-go/utils/unittest"
 )
 
@@ -25,3 +27,69 @@ func TestInsertRetrieveIndex(t *testing.T) {
 		require.Equal(t, index, &retrieved)
 	})
 }
+
+func TestPruneIndex(t *testing.T) {
+	unittest.RunWithBadgerDB(t, func(db *badger.DB) {
+		header := unittest.BlockHeaderFixture(unittest.WithHeaderHeight(10))
+		blockID := header.ID()
+		index := unittest.IndexFixture()
+
+		require.NoError(t, db.Update(operation.InsertHeader(blockID, header)))
+		require.NoError(t, db.Update(InsertIndex(blockID, index)))
+		require.NoError(t, db.Update(PruneIndex(blockID)))
+
+		// the seal and result indexes are retained
+		var sealIDs, resultIDs []flow.Identifier
+		require.NoError(t, db.View(operation.LookupPayloadSeals(blockID, &sealIDs)))
+		require.Equal(t, index.SealIDs, sealIDs)
+		require.NoError(t, db.View(operation.LookupPayloadResults(blockID, &resultIDs)))
+		require.Equal(t, index.ResultIDs, resultIDs)
+
+		// without pruned height, the pruned index is not found
+		var retrieved flow.Index
+		err := db.View(RetrieveIndex(blockID, &retrieved))
+		require.ErrorIs(t, err, storage.ErrNotFound)
+
+		// a missing index of a block above the pruned height is not found
+		require.NoError(t, db.Update(operation.InsertPrunedHeight(10)))
+		err = db.View(RetrieveIndex(blockID, &retrieved))
+		require.ErrorIs(t, err, storage.ErrNotFound)
+
+		// a missing index of a block below the pruned height has been pruned
+		require.NoError(t, db.Update(operation.UpdatePrunedHeight(11)))
+		err = db.View(RetrieveIndex(blockID, &retrieved))
+		require.ErrorIs(t, err, storage.ErrPruned)
+
+		// pruning a missing index fails
+		err = db.Update(PruneIndex(blockID))
+		require.ErrorIs(t, err, storage.ErrNotFound)
+	})
+}
+
+func TestCheckCollectionAndTransactionPruned(t *testing.T) {
+	unittest.RunWithBadgerDB(t, func(db *badger.DB) {
+		header := unittest.BlockHeaderFixture(unittest.WithHeaderHeight(10))
+		blockID := header.ID()
+		collID := unittest.IdentifierFixture()
+		txID := unittest.IdentifierFixture()
+		notFound := storage.ErrNotFound
+
+		require.NoError(t, db.Update(operation.InsertHeader(blockID, header)))
+		require.NoError(t, db.Update(operation.InsertPrunedHeight(11)))
+
+		// without indexes, the collection and transaction are not found
+		require.ErrorIs(t, db.View(CheckCollectionPruned(collID, notFound)), storage.ErrNotFound)
+		require.ErrorIs(t, db.View(CheckTransactionPruned(txID, notFound)), storage.ErrNotFound)
+
+		// indexed as part of a block below the pruned height, they have been pruned
+		require.NoError(t, db.Update(operation.IndexCollectionBlock(collID, blockID)))
+		require.NoError(t, db.Update(operation.IndexCollectionByTransaction(txID, collID)))
+		require.ErrorIs(t, db.View(CheckCollectionPruned(collID, notFound)), storage.ErrPruned)
+		require.ErrorIs(t, db.View(CheckTransactionPruned(txID, notFound)), storage.ErrPruned)
+
+		// indexed as part of a block above the pruned height, they are not found
+		require.NoError(t, db.Update(operation.UpdatePrunedHeight(10)))
+		require.ErrorIs(t, db.View(CheckCollectionPruned(collID, notFound)), storage.ErrNotFound)
+		require.ErrorIs(t, db.View(CheckTransactionPruned(txID, notFound)), storage.ErrNotFound)
+	})
+}
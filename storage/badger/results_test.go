@@ -10,6 +10,7 @@ import (
 	"github.com/onflow/flow-go/module/metrics"
 	"github.com/onflow/flow-go/storage"
 	bstorage "github.com/onflow/flow-go/storage/badger"
+	"github.com/onflow/flow-go/storage/util"
 	"github.com/onflow/flow-go/utils/unittest"
 )
 
@@ -55,14 +56,14 @@ func TestResultStoreTwice(t *testing.T) {
 }
 
 func TestResultBatchStoreTwice(t *testing.T) {
-	unittest.RunWithBadgerDB(t, func(db *badger.DB) {
+	util.RunWithBatchDBs(t, func(t *testing.T, db *badger.DB, batchDB storage.DB) {
 		metrics := metrics.NewNoopCollector()
 		store := bstorage.NewExecutionResults(metrics, db)
 
 		result := unittest.ExecutionResultFixture()
 		blockID := unittest.IdentifierFixture()
 
-		batch := bstorage.NewBatch(db)
+		batch := batchDB.NewBatch()
 		err := store.BatchStore(result, batch)
 		require.NoError(t, err)
 
@@ -71,7 +72,7 @@ func TestResultBatchStoreTwice(t *testing.T) {
 
 		require.NoError(t, batch.Flush())
 
-		batch = bstorage.NewBatch(db)
+		batch = batchDB.NewBatch()
 		err = store.BatchStore(result, batch)
 		require.NoError(t, err)
 
@@ -79,6 +80,10 @@ func TestResultBatchStoreTwice(t *testing.T) {
 		require.NoError(t, err)
 
 		require.NoError(t, batch.Flush())
+
+		actual, err := store.ByBlockID(blockID)
+		require.NoError(t, err)
+		require.Equal(t, result, actual)
 	})
 }
 
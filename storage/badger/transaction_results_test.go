@@ -13,13 +13,14 @@ import (
 	"github.com/onflow/flow-go/model/flow"
 	"github.com/onflow/flow-go/module/metrics"
 	"github.com/onflow/flow-go/storage"
+	"github.com/onflow/flow-go/storage/util"
 	"github.com/onflow/flow-go/utils/unittest"
 
 	bstorage "github.com/onflow/flow-go/storage/badger"
 )
 
 func TestBatchStoringTransactionResults(t *testing.T) {
-	unittest.RunWithBadgerDB(t, func(db *badger.DB) {
+	util.RunWithBatchDBs(t, func(t *testing.T, db *badger.DB, batchDB storage.DB) {
 		metrics := metrics.NewNoopCollector()
 		store := bstorage.NewTransactionResults(metrics, db, 1000)
 
@@ -33,7 +34,7 @@ func TestBatchStoringTransactionResults(t *testing.T) {
 			}
 			txResults = append(txResults, expected)
 		}
-		writeBatch := bstorage.NewBatch(db)
+		writeBatch := batchDB.NewBatch()
 		err := store.BatchStore(blockID, txResults, writeBatch)
 		require.NoError(t, err)
 
@@ -1,12 +1,16 @@
 package badger
 
 import (
+	"errors"
+
 	"github.com/dgraph-io/badger/v2"
 
 	"github.com/onflow/flow-go/model/flow"
 	"github.com/onflow/flow-go/module"
 	"github.com/onflow/flow-go/module/metrics"
+	"github.com/onflow/flow-go/storage"
 	"github.com/onflow/flow-go/storage/badger/operation"
+	"github.com/onflow/flow-go/storage/badger/procedure"
 	"github.com/onflow/flow-go/storage/badger/transaction"
 )
 
@@ -29,6 +33,9 @@ func NewTransactions(cacheMetrics module.CacheMetrics, db *badger.DB) *Transacti
 		var flowTx flow.TransactionBody
 		return func(tx *badger.Txn) (interface{}, error) {
 			err := operation.RetrieveTransaction(txID, &flowTx)(tx)
+			if errors.Is(err, storage.ErrNotFound) {
+				err = procedure.CheckTransactionPruned(txID, err)(tx)
+			}
 			return &flowTx, err
 		}
 	}
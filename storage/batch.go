@@ -1,7 +1,6 @@
 package storage
 
-import "github.com/dgraph-io/badger/v2"
-
+// Transaction is the write access shared by database transactions and write batches.
 type Transaction interface {
 	Set(key, val []byte) error
 }
@@ -9,7 +8,9 @@ type Transaction interface {
 // BatchStorage serves as an abstraction over batch storage, adding ability to add ability to add extra
 // callbacks which fire after the batch is successfully flushed.
 type BatchStorage interface {
-	GetWriter() *badger.WriteBatch
+	// GetWriter returns the writer to add updates to the batch with. The updates
+	// only become visible to readers once the batch has been flushed.
+	GetWriter() Writer
 
 	// OnSucceed adds a callback to execute after the batch has
 	// been successfully flushed.
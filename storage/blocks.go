@@ -18,11 +18,13 @@ type Blocks interface {
 	StoreTx(block *flow.Block) func(*transaction.Tx) error
 
 	// ByID returns the block with the given hash. It is available for
-	// finalized and ambiguous blocks.
+	// finalized and ambiguous blocks. Returns storage.ErrPruned if the payload
+	// of the block has been pruned.
 	ByID(blockID flow.Identifier) (*flow.Block, error)
 
 	// ByHeight returns the block at the given height. It is only available
-	// for finalized blocks.
+	// for finalized blocks. Returns storage.ErrPruned if the payload of the
+	// block has been pruned.
 	ByHeight(height uint64) (*flow.Block, error)
 
 	// ByCollectionID returns the block for the given collection ID.
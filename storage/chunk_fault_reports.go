@@ -0,0 +1,32 @@
+package storage
+
+import (
+	"github.com/onflow/flow-go/model/flow"
+)
+
+// ChunkFaultReports is persistent storage for chunk fault reports, which are generated by verification nodes
+// and kept by consensus nodes for investigating the faulty execution results.
+type ChunkFaultReports interface {
+
+	// Store stores a chunk fault report and indexes it by the ID of its execution result, by its reporter within the
+	// given epoch of its executed block, and by the epoch. Storing the same report multiple times is a no-op.
+	Store(report *flow.ChunkFaultReport, epoch uint64) error
+
+	// ByID retrieves a chunk fault report by its ID.
+	ByID(reportID flow.Identifier) (*flow.ChunkFaultReport, error)
+
+	// ByResultID retrieves all chunk fault reports of the given execution result.
+	ByResultID(resultID flow.Identifier) ([]*flow.ChunkFaultReport, error)
+
+	// CountByReporterIDAndEpoch returns the number of chunk fault reports of the given reporter on the blocks of the
+	// given epoch.
+	CountByReporterIDAndEpoch(reporterID flow.Identifier, epoch uint64) (uint, error)
+
+	// CountByReporterIDAndResultID returns the number of chunk fault reports of the given reporter for the given
+	// execution result of a block of the given epoch.
+	CountByReporterIDAndResultID(reporterID flow.Identifier, epoch uint64, resultID flow.Identifier) (uint, error)
+
+	// RemoveBeforeEpoch removes the chunk fault reports on the blocks of the epochs before the given epoch, with
+	// their indexes, and returns the number of removed reports.
+	RemoveBeforeEpoch(epoch uint64) (uint, error)
+}
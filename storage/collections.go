@@ -19,11 +19,13 @@ type Collections interface {
 	Remove(collID flow.Identifier) error
 
 	// LightByID returns collection with the given ID. Only retrieves
-	// transaction hashes.
+	// transaction hashes. Returns storage.ErrPruned if the collection is
+	// indexed as part of a block whose payload has been pruned.
 	LightByID(collID flow.Identifier) (*flow.LightCollection, error)
 
 	// ByID returns the collection with the given ID, including all
-	// transactions within the collection.
+	// transactions within the collection. Returns storage.ErrPruned if the
+	// collection is indexed as part of a block whose payload has been pruned.
 	ByID(collID flow.Identifier) (*flow.Collection, error)
 
 	// StoreLightAndIndexByTransaction inserts the light collection (only
@@ -41,6 +43,7 @@ type Collections interface {
 	StoreLightAndIndexByTransaction(collection *flow.LightCollection) error
 
 	// LightByTransactionID returns the collection for the given transaction ID. Only retrieves
-	// transaction hashes.
+	// transaction hashes. Returns storage.ErrPruned if the collection is part of a block whose
+	// payload has been pruned.
 	LightByTransactionID(txID flow.Identifier) (*flow.LightCollection, error)
 }
@@ -0,0 +1,36 @@
+package storage
+
+// DB is the key-value database backing the storage layer. Code written against DB,
+// instead of a specific database engine, can run on any backend implementing it.
+type DB interface {
+	Reader
+
+	// NewBatch creates a new write batch. Updates added to the batch are applied
+	// atomically when the batch is flushed.
+	NewBatch() BatchStorage
+}
+
+// Reader provides read access to a key-value database.
+type Reader interface {
+	// Get returns a copy of the value stored under the given key.
+	// Expected errors during normal operations:
+	//   - storage.ErrNotFound if no value is stored under the key
+	Get(key []byte) ([]byte, error)
+
+	// IterateByPrefix calls fn for every key-value pair whose key starts with the given
+	// prefix, in ascending key order, until fn returns false or an error. The key and
+	// value passed to fn are only valid until fn returns, and must be copied if retained.
+	// Errors returned by fn are returned unchanged. No other errors are expected during
+	// normal operation.
+	IterateByPrefix(prefix []byte, fn func(key, value []byte) (bool, error)) error
+}
+
+// Writer provides write access to a key-value database.
+type Writer interface {
+	Transaction
+
+	// Delete removes the value stored under the given key. Deleting a key
+	// without a value is a no-op.
+	// No errors are expected during normal operation.
+	Delete(key []byte) error
+}
@@ -0,0 +1,15 @@
+package storage_test
+
+import (
+	"testing"
+
+	"github.com/onflow/flow-go/storage"
+	"github.com/onflow/flow-go/storage/util"
+)
+
+// TestDB tests that every storage backend implements the semantics of storage.DB.
+func TestDB(t *testing.T) {
+	util.RunWithDBs(t, func(t *testing.T, db storage.DB) {
+		util.CheckDB(t, db)
+	})
+}
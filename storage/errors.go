@@ -21,4 +21,9 @@ var (
 	// ErrDataMismatch is returned when a repeatable insert operation attempts
 	// to insert a different value for the same key.
 	ErrDataMismatch = errors.New("data for key is different")
+
+	// ErrPruned is returned when the requested data belongs to a finalized block below
+	// the pruning threshold of a node which does not retain the full history, and has
+	// therefore been removed from the database.
+	ErrPruned = errors.New("data has been pruned")
 )
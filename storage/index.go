@@ -10,5 +10,6 @@ type Index interface {
 	Store(blockID flow.Identifier, index *flow.Index) error
 
 	// ByBlockID retrieves the index for a block payload.
+	// Returns storage.ErrPruned if the payload of the block has been pruned.
 	ByBlockID(blockID flow.Identifier) (*flow.Index, error)
 }
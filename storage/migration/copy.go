@@ -0,0 +1,62 @@
+package migration
+
+import (
+	"fmt"
+
+	"github.com/onflow/flow-go/storage"
+)
+
+// DefaultBatchSize is the default number of key-value pairs written per batch by Copy.
+const DefaultBatchSize = 1000
+
+// Copy copies every key-value pair of the source database into the destination
+// database, in batches of at most batchSize pairs, and returns the number of
+// copied pairs. It can be used to migrate the data of a node between storage
+// backends. Pairs already present in the destination are overwritten, hence an
+// interrupted copy can be restarted from scratch.
+// The source must not be modified while it is copied.
+// No errors are expected during normal operation.
+func Copy(src storage.Reader, dst storage.DB, batchSize uint) (uint64, error) {
+	if batchSize == 0 {
+		return 0, fmt.Errorf("batch size must be positive")
+	}
+
+	var copied uint64
+	var pending uint
+	batch := dst.NewBatch()
+	err := src.IterateByPrefix(nil, func(key, value []byte) (bool, error) {
+		// the key and value are only valid until we return, while some backends
+		// keep references to them until the batch is flushed
+		k := make([]byte, len(key))
+		copy(k, key)
+		v := make([]byte, len(value))
+		copy(v, value)
+
+		err := batch.GetWriter().Set(k, v)
+		if err != nil {
+			return false, fmt.Errorf("could not add key %x to batch: %w", key, err)
+		}
+		pending++
+		copied++
+
+		if pending < batchSize {
+			return true, nil
+		}
+		err = batch.Flush()
+		if err != nil {
+			return false, fmt.Errorf("could not flush batch: %w", err)
+		}
+		batch = dst.NewBatch()
+		pending = 0
+		return true, nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("could not copy data: %w", err)
+	}
+
+	err = batch.Flush()
+	if err != nil {
+		return 0, fmt.Errorf("could not flush last batch: %w", err)
+	}
+	return copied, nil
+}
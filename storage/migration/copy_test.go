@@ -0,0 +1,79 @@
+package migration_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/cockroachdb/pebble"
+	"github.com/dgraph-io/badger/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/storage"
+	badgerstorage "github.com/onflow/flow-go/storage/badger"
+	"github.com/onflow/flow-go/storage/badger/operation"
+	"github.com/onflow/flow-go/storage/migration"
+	pebblestorage "github.com/onflow/flow-go/storage/pebble"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+// TestCopy_BadgerToPebble tests that a database written by the badger storage layer
+// is copied to pebble unchanged, and that copying it back to badger restores data
+// which the badger storage layer reads as before.
+func TestCopy_BadgerToPebble(t *testing.T) {
+	unittest.RunWithBadgerDB(t, func(src *badger.DB) {
+		unittest.RunWithPebbleDB(t, func(dst *pebble.DB) {
+			unittest.RunWithBadgerDB(t, func(back *badger.DB) {
+				headers := make([]*flow.Header, 0, 10)
+				for i := 0; i < 10; i++ {
+					header := unittest.BlockHeaderFixture()
+					require.NoError(t, src.Update(operation.InsertHeader(header.ID(), header)))
+					require.NoError(t, src.Update(operation.IndexBlockHeight(header.Height, header.ID())))
+					headers = append(headers, header)
+				}
+
+				// copying in batches smaller than the data set
+				copied, err := migration.Copy(badgerstorage.NewDB(src), pebblestorage.NewDB(dst), 3)
+				require.NoError(t, err)
+				assert.Equal(t, uint64(20), copied)
+				assert.Equal(t, contents(t, badgerstorage.NewDB(src)), contents(t, pebblestorage.NewDB(dst)))
+
+				copied, err = migration.Copy(pebblestorage.NewDB(dst), badgerstorage.NewDB(back), migration.DefaultBatchSize)
+				require.NoError(t, err)
+				assert.Equal(t, uint64(20), copied)
+
+				for _, expected := range headers {
+					var blockID flow.Identifier
+					require.NoError(t, back.View(operation.LookupBlockHeight(expected.Height, &blockID)))
+					assert.Equal(t, expected.ID(), blockID)
+
+					var header flow.Header
+					require.NoError(t, back.View(operation.RetrieveHeader(blockID, &header)))
+					assert.Equal(t, expected, &header)
+				}
+			})
+		})
+	})
+}
+
+// TestCopy_InvalidBatchSize tests that copying with an empty batch size is rejected.
+func TestCopy_InvalidBatchSize(t *testing.T) {
+	unittest.RunWithPebbleDB(t, func(src *pebble.DB) {
+		unittest.RunWithPebbleDB(t, func(dst *pebble.DB) {
+			_, err := migration.Copy(pebblestorage.NewDB(src), pebblestorage.NewDB(dst), 0)
+			require.Error(t, err)
+		})
+	})
+}
+
+// contents returns all key-value pairs of the given database.
+func contents(t *testing.T, db storage.Reader) map[string]string {
+	pairs := make(map[string]string)
+	err := db.IterateByPrefix(nil, func(key, value []byte) (bool, error) {
+		pairs[fmt.Sprintf("%x", key)] = string(value)
+		return true, nil
+	})
+	require.NoError(t, err)
+	return pairs
+}
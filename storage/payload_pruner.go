@@ -0,0 +1,22 @@
+package storage
+
+// PayloadPruner removes the payloads of finalized blocks on nodes which do not retain the full
+// history of the chain. The guarantees, collections and receipts contained in the pruned payloads
+// are removed together with their payload indexes. Headers, seals, execution results, epoch data,
+// the seal and result indexes of the payloads and the indexes needed to build sealing segments are
+// retained. Retrieving a pruned collection or transaction returns storage.ErrPruned, as long as
+// it is indexed by block, respectively by collection.
+type PayloadPruner interface {
+
+	// PrunedHeight returns the lowest finalized height whose payload has not been pruned.
+	// Expected errors during normal operation:
+	//   - storage.ErrNotFound if no payload has been pruned yet
+	PrunedHeight() (uint64, error)
+
+	// PruneUpToHeight removes the payloads of the finalized blocks below the given height, which
+	// must not exceed the finalized height + 1. Once pruned, retrieving a payload (or a block) of
+	// such a block returns storage.ErrPruned. Callers are responsible for retaining the blocks
+	// they still need, such as the blocks of the latest sealing segment.
+	// No errors are expected during normal operation.
+	PruneUpToHeight(height uint64) error
+}
@@ -13,6 +13,7 @@ type Payloads interface {
 	Store(blockID flow.Identifier, payload *flow.Payload) error
 
 	// ByBlockID returns the payload with the given hash. It is available for
-	// finalized and ambiguous blocks.
+	// finalized and ambiguous blocks. Returns storage.ErrPruned if the payload
+	// has been pruned.
 	ByBlockID(blockID flow.Identifier) (*flow.Payload, error)
 }
@@ -0,0 +1,77 @@
+package pebble
+
+import (
+	"sync"
+
+	"github.com/cockroachdb/pebble"
+
+	"github.com/onflow/flow-go/storage"
+)
+
+var _ storage.BatchStorage = (*Batch)(nil)
+
+type Batch struct {
+	writer *writer
+
+	lock      sync.RWMutex
+	callbacks []func()
+}
+
+func NewBatch(db *pebble.DB) *Batch {
+	return &Batch{
+		writer:    &writer{batch: db.NewBatch()},
+		callbacks: make([]func(), 0),
+	}
+}
+
+func (b *Batch) GetWriter() storage.Writer {
+	return b.writer
+}
+
+// OnSucceed adds a callback to execute after the batch has
+// been successfully flushed.
+// useful for implementing the cache where we will only cache
+// after the batch has been successfully flushed
+func (b *Batch) OnSucceed(callback func()) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	b.callbacks = append(b.callbacks, callback)
+}
+
+// Flush will commit the pebble batch and sync it to disk, in
+// addition, it will call the callbacks added by
+// OnSucceed
+func (b *Batch) Flush() error {
+	err := b.writer.batch.Commit(pebble.Sync)
+	if err != nil {
+		return err
+	}
+
+	b.lock.RLock()
+	defer b.lock.RUnlock()
+	for _, callback := range b.callbacks {
+		callback()
+	}
+	return nil
+}
+
+// writer adapts a pebble batch to storage.Writer. Pebble copies keys and
+// values into the batch, hence the caller may reuse them after each call.
+// Unlike badger write batches, pebble batches are not safe for concurrent
+// use, so writes are serialized by the writer.
+type writer struct {
+	lock  sync.Mutex
+	batch *pebble.Batch
+}
+
+func (w *writer) Set(key, val []byte) error {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	return w.batch.Set(key, val, nil)
+}
+
+func (w *writer) Delete(key []byte) error {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	return w.batch.Delete(key, nil)
+}
@@ -0,0 +1,93 @@
+package pebble
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/cockroachdb/pebble"
+
+	"github.com/onflow/flow-go/storage"
+)
+
+var _ storage.DB = (*DB)(nil)
+
+// DB implements the backend-agnostic storage.DB on top of a pebble database.
+// Every read observes the latest committed state of the database, hence consecutive
+// reads are not guaranteed to observe the same state of the database.
+type DB struct {
+	db *pebble.DB
+}
+
+func NewDB(db *pebble.DB) *DB {
+	return &DB{db: db}
+}
+
+// Get returns a copy of the value stored under the given key.
+// Expected errors during normal operations:
+//   - storage.ErrNotFound if no value is stored under the key
+func (d *DB) Get(key []byte) ([]byte, error) {
+	value, closer, err := d.db.Get(key)
+	if errors.Is(err, pebble.ErrNotFound) {
+		return nil, storage.ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not load data: %w", err)
+	}
+	defer closer.Close()
+
+	// the value returned by pebble is only valid until the closer is closed
+	data := make([]byte, len(value))
+	copy(data, value)
+	return data, nil
+}
+
+// IterateByPrefix calls fn for every key-value pair whose key starts with the given
+// prefix, in ascending key order, until fn returns false or an error.
+// Errors returned by fn are returned unchanged. No other errors are expected during
+// normal operation.
+func (d *DB) IterateByPrefix(prefix []byte, fn func(key, value []byte) (bool, error)) error {
+	opts := &pebble.IterOptions{}
+	if len(prefix) > 0 {
+		opts.LowerBound = prefix
+		opts.UpperBound = prefixUpperBound(prefix)
+	}
+	it := d.db.NewIter(opts)
+
+	for it.First(); it.Valid(); it.Next() {
+		proceed, err := fn(it.Key(), it.Value())
+		if err != nil {
+			_ = it.Close()
+			return err
+		}
+		if !proceed {
+			break
+		}
+	}
+
+	err := it.Close()
+	if err != nil {
+		return fmt.Errorf("could not iterate: %w", err)
+	}
+	return nil
+}
+
+// NewBatch creates a new pebble write batch, which supports callbacks
+// executed after the batch was successfully flushed.
+func (d *DB) NewBatch() storage.BatchStorage {
+	return NewBatch(d.db)
+}
+
+// prefixUpperBound returns the smallest key that is larger than all keys starting
+// with the given prefix, or nil if there is no such key, because the prefix only
+// consists of 0xff bytes.
+func prefixUpperBound(prefix []byte) []byte {
+	end := make([]byte, len(prefix))
+	copy(end, prefix)
+	for i := len(end) - 1; i >= 0; i-- {
+		end[i]++
+		if end[i] != 0 {
+			return end[:i+1]
+		}
+	}
+	return nil
+}
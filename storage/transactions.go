@@ -11,5 +11,7 @@ type Transactions interface {
 	Store(tx *flow.TransactionBody) error
 
 	// ByID returns the transaction for the given fingerprint.
+	// Returns storage.ErrPruned if the transaction is indexed as part of a
+	// collection whose block payload has been pruned.
 	ByID(txID flow.Identifier) (*flow.TransactionBody, error)
 }
@@ -0,0 +1,216 @@
+package util
+
+import (
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/cockroachdb/pebble"
+	"github.com/dgraph-io/badger/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/storage"
+	badgerstorage "github.com/onflow/flow-go/storage/badger"
+	pebblestorage "github.com/onflow/flow-go/storage/pebble"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+// RunWithDBs runs f as a subtest once for every storage backend, each time with
+// a new empty storage.DB, which is closed after f returns.
+func RunWithDBs(t *testing.T, f func(t *testing.T, db storage.DB)) {
+	t.Run("badger", func(t *testing.T) {
+		unittest.RunWithBadgerDB(t, func(db *badger.DB) {
+			f(t, badgerstorage.NewDB(db))
+		})
+	})
+	t.Run("pebble", func(t *testing.T) {
+		unittest.RunWithPebbleDB(t, func(db *pebble.DB) {
+			f(t, pebblestorage.NewDB(db))
+		})
+	})
+}
+
+// RunWithBatchDBs runs f as a subtest once for every storage backend, for testing stores
+// that write through batches but read through badger transactions. The stores read from
+// the given badger database, while the batches created by batchDB are written to the
+// backend. Once such a batch is flushed, every key it wrote is read back from the backend
+// and copied into the badger database, before the callbacks of the batch are executed.
+func RunWithBatchDBs(t *testing.T, f func(t *testing.T, db *badger.DB, batchDB storage.DB)) {
+	t.Run("badger", func(t *testing.T) {
+		unittest.RunWithBadgerDB(t, func(db *badger.DB) {
+			f(t, db, badgerstorage.NewDB(db))
+		})
+	})
+	t.Run("pebble", func(t *testing.T) {
+		unittest.RunWithBadgerDB(t, func(db *badger.DB) {
+			unittest.RunWithPebbleDB(t, func(backend *pebble.DB) {
+				f(t, db, &syncedDB{DB: pebblestorage.NewDB(backend), target: db})
+			})
+		})
+	})
+}
+
+// syncedDB is a storage.DB whose flushed batches are copied into a badger database.
+type syncedDB struct {
+	storage.DB
+	target *badger.DB
+}
+
+func (d *syncedDB) NewBatch() storage.BatchStorage {
+	return &syncedBatch{db: d, batch: d.DB.NewBatch()}
+}
+
+// syncedBatch records the keys written to the backend batch, and copies them
+// into the badger database once the backend batch was flushed.
+type syncedBatch struct {
+	db    *syncedDB
+	batch storage.BatchStorage
+
+	lock      sync.Mutex
+	keys      [][]byte
+	callbacks []func()
+}
+
+func (b *syncedBatch) GetWriter() storage.Writer {
+	return &syncedWriter{batch: b}
+}
+
+func (b *syncedBatch) OnSucceed(callback func()) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	b.callbacks = append(b.callbacks, callback)
+}
+
+func (b *syncedBatch) Flush() error {
+	err := b.batch.Flush()
+	if err != nil {
+		return err
+	}
+
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	err = b.db.target.Update(func(tx *badger.Txn) error {
+		for _, key := range b.keys {
+			value, err := b.db.Get(key)
+			if errors.Is(err, storage.ErrNotFound) {
+				err = tx.Delete(key)
+			} else if err == nil {
+				err = tx.Set(key, value)
+			}
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	for _, callback := range b.callbacks {
+		callback()
+	}
+	return nil
+}
+
+// syncedWriter writes to the backend batch, recording the written keys.
+type syncedWriter struct {
+	batch *syncedBatch
+}
+
+func (w *syncedWriter) Set(key, val []byte) error {
+	w.record(key)
+	return w.batch.batch.GetWriter().Set(key, val)
+}
+
+func (w *syncedWriter) Delete(key []byte) error {
+	w.record(key)
+	return w.batch.batch.GetWriter().Delete(key)
+}
+
+func (w *syncedWriter) record(key []byte) {
+	w.batch.lock.Lock()
+	defer w.batch.lock.Unlock()
+	w.batch.keys = append(w.batch.keys, append([]byte(nil), key...))
+}
+
+// CheckDB tests that the given empty database implements the semantics of storage.DB.
+func CheckDB(t *testing.T, db storage.DB) {
+	// reading a missing key
+	_, err := db.Get([]byte{0x01, 0x01})
+	require.ErrorIs(t, err, storage.ErrNotFound)
+
+	// writes only become visible once the batch is flushed, after which the callbacks are executed
+	batch := db.NewBatch()
+	succeeded := false
+	batch.OnSucceed(func() {
+		succeeded = true
+	})
+	require.NoError(t, batch.GetWriter().Set([]byte{0x01, 0x02}, []byte("b")))
+	require.NoError(t, batch.GetWriter().Set([]byte{0x01, 0x01}, []byte("a")))
+	require.NoError(t, batch.GetWriter().Set([]byte{0x01, 0x03}, []byte("c")))
+	require.NoError(t, batch.GetWriter().Set([]byte{0x02, 0x01}, []byte("d")))
+	require.NoError(t, batch.GetWriter().Set([]byte{0xff, 0xff}, []byte("e")))
+	_, err = db.Get([]byte{0x01, 0x01})
+	require.ErrorIs(t, err, storage.ErrNotFound)
+	assert.False(t, succeeded)
+
+	require.NoError(t, batch.Flush())
+	assert.True(t, succeeded)
+	value, err := db.Get([]byte{0x01, 0x01})
+	require.NoError(t, err)
+	assert.Equal(t, []byte("a"), value)
+
+	// iterating visits the keys with the prefix in ascending order
+	var visited []string
+	err = db.IterateByPrefix([]byte{0x01}, func(key, value []byte) (bool, error) {
+		visited = append(visited, string(value))
+		return true, nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a", "b", "c"}, visited)
+
+	// iterating by a prefix without a successor, and without a prefix at all
+	visited = nil
+	err = db.IterateByPrefix([]byte{0xff}, func(key, value []byte) (bool, error) {
+		visited = append(visited, string(value))
+		return true, nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"e"}, visited)
+
+	visited = nil
+	err = db.IterateByPrefix(nil, func(key, value []byte) (bool, error) {
+		visited = append(visited, string(value))
+		return true, nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a", "b", "c", "d", "e"}, visited)
+
+	// iterating stops as soon as the function returns false
+	visited = nil
+	err = db.IterateByPrefix([]byte{0x01}, func(key, value []byte) (bool, error) {
+		visited = append(visited, string(value))
+		return len(visited) < 2, nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a", "b"}, visited)
+
+	// errors of the function are returned unchanged
+	expected := storage.ErrDataMismatch
+	err = db.IterateByPrefix([]byte{0x01}, func(key, value []byte) (bool, error) {
+		return true, expected
+	})
+	require.ErrorIs(t, err, expected)
+
+	// deleting existing and missing keys
+	batch = db.NewBatch()
+	require.NoError(t, batch.GetWriter().Delete([]byte{0x01, 0x02}))
+	require.NoError(t, batch.GetWriter().Delete([]byte{0x03, 0x01}))
+	require.NoError(t, batch.Flush())
+	_, err = db.Get([]byte{0x01, 0x02})
+	require.ErrorIs(t, err, storage.ErrNotFound)
+	value, err = db.Get([]byte{0x02, 0x01})
+	require.NoError(t, err)
+	assert.Equal(t, []byte("d"), value)
+}
@@ -1919,6 +1919,21 @@ func EpochCommitFixture(opts ...func(*flow.EpochCommit)) *flow.EpochCommit {
 	return commit
 }
 
+// EpochRecoverFixture returns an EpochRecover service event, with setup and commit
+// events for the same epoch counter.
+func EpochRecoverFixture(opts ...func(*flow.EpochSetup)) *flow.EpochRecover {
+	setup := EpochSetupFixture(opts...)
+	commit := EpochCommitFixture(
+		CommitWithCounter(setup.Counter),
+		WithClusterQCsFromAssignments(setup.Assignments),
+		WithDKGFromParticipants(setup.Participants),
+	)
+	return &flow.EpochRecover{
+		EpochSetup:  *setup,
+		EpochCommit: *commit,
+	}
+}
+
 // BootstrapFixture generates all the artifacts necessary to bootstrap the
 // protocol state.
 func BootstrapFixture(participants flow.IdentityList, opts ...func(*flow.Block)) (*flow.Block, *flow.ExecutionResult, *flow.Seal) {
@@ -12,6 +12,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/cockroachdb/pebble"
 	"github.com/dgraph-io/badger/v2"
 	"github.com/rs/zerolog"
 	"github.com/stretchr/testify/assert"
@@ -369,6 +370,24 @@ func RunWithTypedBadgerDB(t testing.TB, create func(badger.Options) (*badger.DB,
 	})
 }
 
+func PebbleDB(t testing.TB, dir string) *pebble.DB {
+	db, err := pebble.Open(dir, &pebble.Options{})
+	require.NoError(t, err)
+	return db
+}
+
+// RunWithPebbleDB creates a Pebble DB that is passed to f and closed
+// after f returns.
+func RunWithPebbleDB(t testing.TB, f func(*pebble.DB)) {
+	RunWithTempDir(t, func(dir string) {
+		db := PebbleDB(t, dir)
+		defer func() {
+			assert.NoError(t, db.Close())
+		}()
+		f(db)
+	})
+}
+
 func TempBadgerDB(t testing.TB) (*badger.DB, string) {
 	dir := TempDir(t)
 	db := BadgerDB(t, dir)